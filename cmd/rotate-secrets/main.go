@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+// rotate-secrets is a utility to create new keys for the cookies and CSRF tokens of a room.
+// The current keys stay valid for a grace period, a running server picks up the new ones by itself.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+
+	"github.com/ssbc/go-ssb-room/v2/internal/repo"
+	"github.com/ssbc/go-ssb-room/v2/web"
+)
+
+func main() {
+	u, err := user.Current()
+	check(err)
+
+	var (
+		repoPath    string
+		gracePeriod time.Duration
+	)
+
+	flag.StringVar(&repoPath, "repo", filepath.Join(u.HomeDir, ".ssb-go-room"), "[optional] where the locally stored files of the room are located")
+	flag.DurationVar(&gracePeriod, "grace", web.DefaultSecretsGracePeriod, "[optional] how long the current keys are still accepted. 0 signs everyone out right away")
+	flag.Parse()
+
+	if _, err := os.Stat(repoPath); err != nil {
+		if os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "error: %s does not exist (-repo)?\n", repoPath)
+			os.Exit(1)
+		}
+	}
+
+	r := repo.New(repoPath)
+	err = web.RotateSecrets(r, gracePeriod)
+	check(err)
+
+	fmt.Fprintf(os.Stderr, "Created new keys, the previous ones are accepted for %s\n", gracePeriod)
+}
+
+func check(err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+}
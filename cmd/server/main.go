@@ -18,6 +18,7 @@ import (
 	"os/user"
 	"path/filepath"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -40,6 +41,8 @@ import (
 	"github.com/ssbc/go-ssb-room/v2/roomdb/sqlite"
 	"github.com/ssbc/go-ssb-room/v2/roomsrv"
 	mksrv "github.com/ssbc/go-ssb-room/v2/roomsrv"
+	"github.com/ssbc/go-ssb-room/v2/roomstate"
+	"github.com/ssbc/go-ssb-room/v2/web"
 	"github.com/ssbc/go-ssb-room/v2/web/handlers"
 )
 
@@ -62,10 +65,22 @@ var (
 
 	aliasesAsSubdomains bool
 
+	heartbeatInterval  time.Duration
+	heartbeatMaxMissed int
+
+	fullSessionIPs bool
+
+	trustedProxies web.TrustedProxies
+
+	sessionIdleTimeout time.Duration
+	sessionLifetime    time.Duration
+
 	listenAddrDebug string
 	logToFile       string
 	repoDir         string
 
+	roomsConfig string
+
 	privacyMode = roomdb.ModeUnknown
 
 	// helper
@@ -99,6 +114,8 @@ func initFlags() {
 
 	flag.BoolVar(&flagDisableUNIXSock, "nounixsock", false, "disable the UNIX socket RPC interface")
 
+	flag.StringVar(&roomsConfig, "rooms", "", "JSON file that lists several rooms to host in this process (replaces -repo, -https-domain, -lismux, -mode and -aliases-as-subdomains)")
+
 	flag.StringVar(&repoDir, "repo", filepath.Join(u.HomeDir, ".ssb-go-room"), "where to put the log and indexes")
 
 	flag.StringVar(&listenAddrDebug, "dbg", "localhost:6078", "listen addr for metrics and pprof HTTP server")
@@ -120,6 +137,22 @@ func initFlags() {
 
 	flag.BoolVar(&aliasesAsSubdomains, "aliases-as-subdomains", true, "needs to be disabled if a wildcard certificate for the room is not available. (stub until we have the admin/settings page)")
 
+	flag.DurationVar(&heartbeatInterval, "heartbeat-interval", roomstate.DefaultHeartbeatInterval, "how often attendants are probed to detect dead connections (0 disables it)")
+	flag.IntVar(&heartbeatMaxMissed, "heartbeat-max-missed", roomstate.DefaultHeartbeatMaxMissed, "how many probes in a row an attendant can miss before it is evicted from the room")
+
+	flag.Func("trusted-proxies", "comma separated addresses or networks (like 127.0.0.1 or 10.0.0.0/8) of the reverse proxies whose X-Forwarded-For headers are trusted", func(val string) error {
+		tp, err := web.ParseTrustedProxies(strings.Split(val, ","))
+		if err != nil {
+			return err
+		}
+		trustedProxies = tp
+		return nil
+	})
+
+	flag.BoolVar(&fullSessionIPs, "session-full-ip", false, "store the full IP address of sign-in sessions instead of truncating it to the network (/24 or /48)")
+	flag.DurationVar(&sessionIdleTimeout, "session-idle-timeout", web.DefaultSessionIdleTimeout, "sign-in sessions end after this long without requests")
+	flag.DurationVar(&sessionLifetime, "session-lifetime", web.DefaultSessionLifetime, "sign-in sessions end this long after they started, even if they are used")
+
 	flag.Parse()
 
 	if logToFile != "" {
@@ -146,29 +179,59 @@ func runroomsrv() error {
 		return nil
 	}
 
-	if httpsDomain == "" {
-		if !development {
-			return fmt.Errorf("https-domain can't be empty. See '%s -h' for a full list of options", os.Args[0])
+	var rooms []*hostedRoom
+	if roomsConfig != "" {
+		var err error
+		rooms, err = loadRoomsConfig(roomsConfig)
+		if err != nil {
+			return err
 		}
-		httpsDomain = "localhost"
+	} else {
+		if httpsDomain == "" {
+			if !development {
+				return fmt.Errorf("https-domain can't be empty. See '%s -h' for a full list of options", os.Args[0])
+			}
+			httpsDomain = "localhost"
+		}
+
+		rooms = []*hostedRoom{{
+			domain:              httpsDomain,
+			repoDir:             repoDir,
+			listenAddrMUXRPC:    listenAddrShsMux,
+			privacyMode:         privacyMode,
+			aliasesAsSubdomains: aliasesAsSubdomains,
+		}}
 	}
 
 	// validate listen addresses to bail out on invalid flag input before doing anything else
-	_, muxrpcPortStr, err := net.SplitHostPort(listenAddrShsMux)
-	if err != nil {
-		return fmt.Errorf("invalid muxrpc listener: %w", err)
-	}
+	for _, room := range rooms {
+		_, muxrpcPortStr, err := net.SplitHostPort(room.listenAddrMUXRPC)
+		if err != nil {
+			return fmt.Errorf("invalid muxrpc listener for %s: %w", room.domain, err)
+		}
 
-	_, err = net.LookupPort("tcp", muxrpcPortStr)
-	if err != nil {
-		return fmt.Errorf("invalid tcp port for muxrpc listener: %w", err)
+		_, err = net.LookupPort("tcp", muxrpcPortStr)
+		if err != nil {
+			return fmt.Errorf("invalid tcp port for muxrpc listener of %s: %w", room.domain, err)
+		}
 	}
 
-	_, portHTTPStr, err := net.SplitHostPort(listenAddrHTTP)
+	hostHTTP, portHTTPStr, err := net.SplitHostPort(listenAddrHTTP)
 	if err != nil {
 		return fmt.Errorf("invalid http listener: %w", err)
 	}
 
+	// a listener on loopback usually sits behind a reverse proxy on the same server.
+	// without trusting it, all clients share its address for the rate limits and lockouts.
+	if len(trustedProxies) == 0 {
+		if ip := net.ParseIP(hostHTTP); hostHTTP == "localhost" || (ip != nil && ip.IsLoopback()) {
+			level.Warn(log).Log(
+				"event", "no trusted proxies",
+				"msg", "the HTTP listener is on loopback but -trusted-proxies isn't set. Behind a reverse proxy every client shares its address, so one of them can lock everyone out of password sign-ins and invites. Use -trusted-proxies 127.0.0.1,::1 for a proxy on the same server.",
+			)
+		}
+	}
+
 	portHTTP, err := net.LookupPort("tcp", portHTTPStr)
 	if err != nil {
 		return fmt.Errorf("invalid tcp port for muxrpc listener: %w", err)
@@ -182,11 +245,147 @@ func runroomsrv() error {
 		return fmt.Errorf("secret-handshake appkey is invalid base64: %w", err)
 	}
 
+	if listenAddrDebug != "" {
+		go func() {
+			// http.Handle("/metrics", promhttp.Handler())
+			level.Debug(log).Log("starting", "metrics", "addr", listenAddrDebug)
+			err := http.ListenAndServe(listenAddrDebug, nil)
+			checkAndLog(err)
+		}()
+	}
+
+	// HTTP rate limiter
+	throttleStore, err := memstore.New(65536) // 64k different combinations of limitByPathAndAddr
+	if err != nil {
+		return fmt.Errorf("failed to init HTTP rate limiter store: %w", err)
+	}
+	quota := throttled.RateQuota{
+		MaxRate:  throttled.PerSec(5), // different requests per second per VaryBy
+		MaxBurst: 25,
+	}
+	limiter, err := throttled.NewGCRARateLimiter(throttleStore, quota)
+	if err != nil {
+		return fmt.Errorf("failed to init HTTP rate limiter: %w", err)
+	}
+
+	httpRateLimiter := throttled.HTTPRateLimiter{
+		RateLimiter: limiter,
+		VaryBy:      limitByPathAndAddr{},
+	}
+
+	// setup the shs+muxrpc server and the web dashboard of each room
+	for _, room := range rooms {
+		roomLog := log
+		if len(rooms) > 1 {
+			roomLog = kitlog.With(log, "room", room.domain)
+		}
+
+		err := setupRoom(ctx, roomLog, room, ak, uint(portHTTP), httpRateLimiter)
+		if err != nil {
+			return fmt.Errorf("room %s: %w", room.domain, err)
+		}
+	}
+
+	// open the HTTP listener
+	httpLis, err := net.Listen("tcp", listenAddrHTTP)
+	if err != nil {
+		return fmt.Errorf("failed to open listener for HTTPdashboard: %w", err)
+	}
+
+	c := make(chan os.Signal)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-c
+		level.Warn(log).Log("event", "killed", "msg", "received signal, shutting down", "signal", sig.String())
+		cancel()
+		for _, room := range rooms {
+			room.srv.Shutdown()
+		}
+
+		httpLis.Close()
+		time.Sleep(2 * time.Second)
+
+		for _, room := range rooms {
+			err := room.srv.Close()
+			checkAndLog(err)
+		}
+
+		time.Sleep(2 * time.Second)
+		os.Exit(0)
+	}()
+
+	// with more than one room, the Host header decides which one a request is for
+	var httpHandler http.Handler
+	if len(rooms) == 1 {
+		httpHandler = rooms[0].handler
+	} else {
+		httpHandler = newHostRouter(rooms, trustedProxies)
+	}
+
+	if len(trustedProxies) == 0 {
+		httpHandler = warnUntrustedForwarding(httpHandler)
+	}
+
+	// all init was successfull
+	for _, room := range rooms {
+		level.Info(log).Log(
+			"event", "serving",
+			"ID", room.srv.Whoami().String(),
+			"domain", room.domain,
+			"shsmuxaddr", room.listenAddrMUXRPC,
+			"httpaddr", listenAddrHTTP,
+			"version", version, "commit", commit,
+		)
+	}
+
+	// start serving http connections
+	go func() {
+		srv := http.Server{
+			Addr: httpLis.Addr().String(),
+
+			// Good practice to set timeouts to avoid Slowloris attacks.
+			// Keep in mind that the SSE stuff for "sign-in with ssb" can take a moment, thou
+			ReadHeaderTimeout: time.Second * 15,
+			WriteTimeout:      time.Minute * 3,
+			IdleTimeout:       time.Minute * 3,
+
+			Handler: httpHandler,
+		}
+
+		err = srv.Serve(httpLis)
+		if err != nil {
+			level.Error(log).Log("event", "http serve failed", "err", err)
+		}
+	}()
+
+	// start serving shs+muxrpc connections, each room has its own listener
+	var wg sync.WaitGroup
+	for _, room := range rooms {
+		wg.Add(1)
+		go func(room *hostedRoom) {
+			defer wg.Done()
+			serveMUXRPC(ctx, room)
+		}(room)
+	}
+	wg.Wait()
+
+	var closeErr error
+	for _, room := range rooms {
+		if err := room.srv.Close(); err != nil {
+			closeErr = err
+		}
+	}
+	return closeErr
+}
+
+// setupRoom opens the database of the room and creates its shs+muxrpc server and HTTP handler
+func setupRoom(ctx context.Context, log kitlog.Logger, room *hostedRoom, ak []byte, portHTTP uint, httpRateLimiter throttled.HTTPRateLimiter) error {
 	opts := []roomsrv.Option{
 		roomsrv.WithLogger(log),
 		roomsrv.WithAppKey(ak),
-		roomsrv.WithRepoPath(repoDir),
+		roomsrv.WithRepoPath(room.repoDir),
 		roomsrv.WithUNIXSocket(!flagDisableUNIXSock),
+		roomsrv.WithHeartbeat(heartbeatInterval, heartbeatMaxMissed),
 	}
 
 	if logToFile != "" {
@@ -198,7 +397,7 @@ func runroomsrv() error {
 			}
 
 			muxrpcDumpDir := filepath.Join(
-				repoDir,
+				room.repoDir,
 				logToFile,
 				parts[1], // key first
 				parts[0],
@@ -208,16 +407,7 @@ func runroomsrv() error {
 		}))
 	}
 
-	if listenAddrDebug != "" {
-		go func() {
-			// http.Handle("/metrics", promhttp.Handler())
-			level.Debug(log).Log("starting", "metrics", "addr", listenAddrDebug)
-			err := http.ListenAndServe(listenAddrDebug, nil)
-			checkAndLog(err)
-		}()
-	}
-
-	r := repo.New(repoDir)
+	r := repo.New(room.repoDir)
 
 	keyPair, err := repo.DefaultKeyPair(r)
 	checkAndLog(err)
@@ -226,14 +416,14 @@ func runroomsrv() error {
 	networkInfo := network.ServerEndpointDetails{
 		Development: development,
 
-		Domain:    httpsDomain,
-		PortHTTPS: uint(portHTTP),
+		Domain:    room.domain,
+		PortHTTPS: portHTTP,
 
 		RoomID: keyPair.Feed,
 
-		ListenAddressMUXRPC: listenAddrShsMux,
+		ListenAddressMUXRPC: room.listenAddrMUXRPC,
 
-		UseSubdomainForAliases: aliasesAsSubdomains,
+		UseSubdomainForAliases: room.aliasesAsSubdomains,
 	}
 
 	// open the sqlite version of the roomdb
@@ -244,8 +434,8 @@ func runroomsrv() error {
 
 	bridge := signinwithssb.NewSignalBridge()
 	// the privacy mode flag was passed => update it in the database
-	if privacyMode != roomdb.ModeUnknown {
-		db.Config.SetPrivacyMode(ctx, privacyMode)
+	if room.privacyMode != roomdb.ModeUnknown {
+		db.Config.SetPrivacyMode(ctx, room.privacyMode)
 	}
 
 	// create the shs+muxrpc server
@@ -253,6 +443,7 @@ func runroomsrv() error {
 		db.Members,
 		db.DeniedKeys,
 		db.Aliases,
+		db.Invites,
 		db.AuthWithSSB,
 		bridge,
 		db.Config,
@@ -261,50 +452,37 @@ func runroomsrv() error {
 	if err != nil {
 		return fmt.Errorf("failed to instantiate ssb server: %w", err)
 	}
-
-	// open the HTTP listener
-	httpLis, err := net.Listen("tcp", listenAddrHTTP)
-	if err != nil {
-		return fmt.Errorf("failed to open listener for HTTPdashboard: %w", err)
-	}
-
-	c := make(chan os.Signal)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		sig := <-c
-		level.Warn(log).Log("event", "killed", "msg", "received signal, shutting down", "signal", sig.String())
-		cancel()
-		roomsrv.Shutdown()
-
-		httpLis.Close()
-		time.Sleep(2 * time.Second)
-
-		err := roomsrv.Close()
-		checkAndLog(err)
-
-		time.Sleep(2 * time.Second)
-		os.Exit(0)
-	}()
+	room.srv = roomsrv
 
 	// setup web dashboard handlers
 	webHandler, err := handlers.New(
 		kitlog.With(log, "package", "web"),
-		repo.New(repoDir),
+		repo.New(room.repoDir),
 		networkInfo,
 		roomsrv.StateManager,
 		roomsrv.Network,
 		bridge,
 		handlers.Databases{
-			Aliases:       db.Aliases,
-			AuthFallback:  db.AuthFallback,
-			AuthWithSSB:   db.AuthWithSSB,
-			Config:        db.Config,
-			DeniedKeys:    db.DeniedKeys,
-			Invites:       db.Invites,
-			Notices:       db.Notices,
-			Members:       db.Members,
-			PinnedNotices: db.PinnedNotices,
+			Aliases:        db.Aliases,
+			APITokens:      db.APITokens,
+			AuthFallback:   db.AuthFallback,
+			AuthWithSSB:    db.AuthWithSSB,
+			Config:         db.Config,
+			DeniedKeys:     db.DeniedKeys,
+			Invites:        db.Invites,
+			InviteRequests: db.InviteRequests,
+			LoginLockouts:  db.LoginLockouts,
+			Notices:        db.Notices,
+			Members:        db.Members,
+			OIDCClients:    db.OIDCClients,
+			PinnedNotices:  db.PinnedNotices,
+			WebAuthn:       db.WebAuthn,
+			WebSessions:    db.WebSessions,
+			TOTP:           db.TOTP,
 		},
+		handlers.WithFullSessionIPs(fullSessionIPs),
+		handlers.WithTrustedProxies(trustedProxies),
+		handlers.WithSessionTimeouts(sessionIdleTimeout, sessionLifetime),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create HTTPdashboard handler: %w", err)
@@ -316,9 +494,9 @@ func runroomsrv() error {
 
 		AllowedHosts: []string{
 			// the normal domain
-			httpsDomain,
+			room.domain,
 			// the domain but as a wildcard match with *. infront
-			`*\.` + strings.Replace(httpsDomain, ".", `\.`, -1),
+			`*\.` + strings.Replace(room.domain, ".", `\.`, -1),
 		},
 
 		// for the wildcard matching
@@ -326,7 +504,7 @@ func runroomsrv() error {
 
 		// TLS stuff
 		SSLRedirect: true,
-		SSLHost:     httpsDomain,
+		SSLHost:     room.domain,
 
 		// Important for reverse-proxy setups (when nginx or similar does the TLS termination)
 		SSLProxyHeaders:   map[string]string{"X-Forwarded-Proto": "https"},
@@ -347,73 +525,29 @@ func runroomsrv() error {
 		//ContentTypeNosniff: true, // TODO: fix Content-Type headers served from assets
 	})
 
-	// HTTP rate limiter
-	throttleStore, err := memstore.New(65536) // 64k different combinations of limitByPathAndAddr
-	if err != nil {
-		return fmt.Errorf("failed to init HTTP rate limiter store: %w", err)
-	}
-	quota := throttled.RateQuota{
-		MaxRate:  throttled.PerSec(5), // different requests per second per VaryBy
-		MaxBurst: 25,
-	}
-	limiter, err := throttled.NewGCRARateLimiter(throttleStore, quota)
-	if err != nil {
-		return fmt.Errorf("failed to init HTTP rate limiter: %w", err)
-	}
-
-	httpRateLimiter := throttled.HTTPRateLimiter{
-		RateLimiter: limiter,
-		VaryBy:      limitByPathAndAddr{},
-	}
-
 	// wrap dashboard/alias/invite handler in ratlimiter and security middleware
 	var httpHandler http.Handler
 	httpHandler = httpRateLimiter.RateLimit(webHandler)
 	httpHandler = secureMiddleware.Handler(httpHandler)
 	httpHandler = roomsrv.Network.WebsockHandler(httpHandler)
+	room.handler = httpHandler
 
-	// all init was successfull
-	level.Info(log).Log(
-		"event", "serving",
-		"ID", roomsrv.Whoami().String(),
-		"shsmuxaddr", listenAddrShsMux,
-		"httpaddr", listenAddrHTTP,
-		"version", version, "commit", commit,
-	)
-
-	// start serving http connections
-	go func() {
-		srv := http.Server{
-			Addr: httpLis.Addr().String(),
-
-			// Good practice to set timeouts to avoid Slowloris attacks.
-			// Keep in mind that the SSE stuff for "sign-in with ssb" can take a moment, thou
-			ReadHeaderTimeout: time.Second * 15,
-			WriteTimeout:      time.Minute * 3,
-			IdleTimeout:       time.Minute * 3,
-
-			Handler: httpHandler,
-		}
-
-		err = srv.Serve(httpLis)
-		if err != nil {
-			level.Error(log).Log("event", "http serve failed", "err", err)
-		}
-	}()
+	return nil
+}
 
-	// start serving shs+muxrpc connections
+// serveMUXRPC accepts shs+muxrpc connections for a room until the context is canceled
+func serveMUXRPC(ctx context.Context, room *hostedRoom) {
 	for {
 		// Note: This is where the serving starts ;)
-		err = roomsrv.Network.Serve(ctx)
+		err := room.srv.Network.Serve(ctx)
 		if err != nil {
-			level.Warn(log).Log("event", "roomsrv node.Serve returned", "err", err)
+			level.Warn(log).Log("event", "roomsrv node.Serve returned", "room", room.domain, "err", err)
 		}
 
 		time.Sleep(1 * time.Second)
 		select {
 		case <-ctx.Done():
-			err := roomsrv.Close()
-			return err
+			return
 		default:
 		}
 	}
@@ -426,6 +560,26 @@ func main() {
 	}
 }
 
+// warnUntrustedForwarding logs an error the first time a request from loopback carries X-Forwarded-For.
+// That is a reverse proxy that isn't trusted, so the address of the client can't be told.
+func warnUntrustedForwarding(next http.Handler) http.Handler {
+	var once sync.Once
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("X-Forwarded-For") != "" {
+			host, _, err := net.SplitHostPort(req.RemoteAddr)
+			if ip := net.ParseIP(host); err == nil && ip != nil && ip.IsLoopback() {
+				once.Do(func() {
+					level.Error(log).Log(
+						"event", "untrusted reverse proxy",
+						"msg", "got a request with X-Forwarded-For from loopback but -trusted-proxies isn't set. Every client is treated as the same address for the rate limits and lockouts. Restart the room with -trusted-proxies 127.0.0.1,::1 (see docs/deployment.md).",
+					)
+				})
+			}
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
 type limitByPathAndAddr struct{}
 
 func (limitByPathAndAddr) Key(r *http.Request) string {
@@ -434,11 +588,8 @@ func (limitByPathAndAddr) Key(r *http.Request) string {
 	k.WriteString(r.URL.Path)
 	k.WriteString("\n")
 
-	remoteIP := r.Header.Get("X-Forwarded-For")
-	if remoteIP == "" {
-		remoteIP = r.RemoteAddr
-	}
-	k.WriteString(remoteIP)
+	// requests without a known address share one bucket
+	k.WriteString(trustedProxies.ClientIP(r).String())
 
 	return k.String()
 }
@@ -0,0 +1,174 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/roomsrv"
+	"github.com/ssbc/go-ssb-room/v2/web"
+)
+
+// roomConfig is one entry of the file passed via -rooms.
+// Each room has its own identity (the secret in its repo), database, domain and shs+muxrpc listener.
+type roomConfig struct {
+	// Domain is used for TLS, AllowedHosts checks and to route HTTP requests to this room
+	Domain string `json:"domain"`
+
+	// Repo is the folder with the secret and the database of this room
+	Repo string `json:"repo"`
+
+	// ListenAddrMUXRPC is the address to listen on for secret-handshake+muxrpc. It can't be shared between rooms.
+	ListenAddrMUXRPC string `json:"lismux"`
+
+	// Mode is the optional privacy mode (open, community, restricted) to set for this room
+	Mode string `json:"mode,omitempty"`
+
+	// AliasesAsSubdomains defaults to true, like the -aliases-as-subdomains flag
+	AliasesAsSubdomains *bool `json:"aliases-as-subdomains,omitempty"`
+}
+
+// hostedRoom is a room that is served by this process
+type hostedRoom struct {
+	domain              string
+	repoDir             string
+	listenAddrMUXRPC    string
+	privacyMode         roomdb.PrivacyMode
+	aliasesAsSubdomains bool
+
+	srv     *roomsrv.Server
+	handler http.Handler
+}
+
+// loadRoomsConfig reads the JSON list of rooms and checks that they don't overlap
+func loadRoomsConfig(path string) ([]*hostedRoom, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open rooms config: %w", err)
+	}
+	defer f.Close()
+
+	var configs []roomConfig
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&configs); err != nil {
+		return nil, fmt.Errorf("failed to decode rooms config: %w", err)
+	}
+
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("rooms config %s doesn't contain any rooms", path)
+	}
+
+	var (
+		domains = make(map[string]struct{})
+		repos   = make(map[string]struct{})
+		addrs   = make(map[string]struct{})
+
+		rooms = make([]*hostedRoom, len(configs))
+	)
+	for i, cfg := range configs {
+		room := &hostedRoom{
+			domain:              normalizeHost(cfg.Domain),
+			repoDir:             cfg.Repo,
+			listenAddrMUXRPC:    cfg.ListenAddrMUXRPC,
+			privacyMode:         roomdb.ModeUnknown,
+			aliasesAsSubdomains: true,
+		}
+
+		if room.domain == "" {
+			return nil, fmt.Errorf("room #%d: domain can't be empty", i)
+		}
+		if _, taken := domains[room.domain]; taken {
+			return nil, fmt.Errorf("room #%d: domain %s is used by more than one room", i, room.domain)
+		}
+		domains[room.domain] = struct{}{}
+
+		if room.repoDir == "" {
+			return nil, fmt.Errorf("room %s: repo can't be empty", room.domain)
+		}
+		if _, taken := repos[room.repoDir]; taken {
+			return nil, fmt.Errorf("room %s: repo %s is used by more than one room", room.domain, room.repoDir)
+		}
+		repos[room.repoDir] = struct{}{}
+
+		if room.listenAddrMUXRPC == "" {
+			return nil, fmt.Errorf("room %s: lismux can't be empty", room.domain)
+		}
+		if _, taken := addrs[room.listenAddrMUXRPC]; taken {
+			return nil, fmt.Errorf("room %s: lismux %s is used by more than one room", room.domain, room.listenAddrMUXRPC)
+		}
+		addrs[room.listenAddrMUXRPC] = struct{}{}
+
+		if cfg.Mode != "" {
+			pm := roomdb.ParsePrivacyMode(cfg.Mode)
+			if err := pm.IsValid(); err != nil {
+				return nil, fmt.Errorf("room %s: %s, valid values are open, community, restricted", room.domain, err)
+			}
+			room.privacyMode = pm
+		}
+
+		if cfg.AliasesAsSubdomains != nil {
+			room.aliasesAsSubdomains = *cfg.AliasesAsSubdomains
+		}
+
+		rooms[i] = room
+	}
+
+	return rooms, nil
+}
+
+// hostRouter passes HTTP requests to the room that matches the requested host.
+// Subdomains of a room are routed to it as well, for the aliases.
+// X-Forwarded-Host is only used if the request came from one of the trusted proxies.
+type hostRouter struct {
+	rooms []*hostedRoom
+
+	trustedProxies web.TrustedProxies
+}
+
+func newHostRouter(rooms []*hostedRoom, tp web.TrustedProxies) hostRouter {
+	sorted := make([]*hostedRoom, len(rooms))
+	copy(sorted, rooms)
+
+	// longest domain first, so that a room on sub.example.net isn't shadowed by one on example.net
+	sort.Slice(sorted, func(i, j int) bool {
+		return len(sorted[i].domain) > len(sorted[j].domain)
+	})
+
+	return hostRouter{rooms: sorted, trustedProxies: tp}
+}
+
+func (hr hostRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	// otherwise anyone could pick the room they talk to
+	host := req.Host
+	if fwd := req.Header.Get("X-Forwarded-Host"); fwd != "" && hr.trustedProxies.FromProxy(req) {
+		host = fwd
+	}
+	host = normalizeHost(host)
+
+	for _, room := range hr.rooms {
+		if host == room.domain || strings.HasSuffix(host, "."+room.domain) {
+			room.handler.ServeHTTP(w, req)
+			return
+		}
+	}
+
+	http.Error(w, "no room is hosted on this domain", http.StatusNotFound)
+}
+
+// normalizeHost strips the port and the trailing dot and lowercases the host
+func normalizeHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
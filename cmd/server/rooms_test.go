@@ -0,0 +1,310 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/throttled/throttled/v2"
+	"github.com/throttled/throttled/v2/store/memstore"
+	kitlog "go.mindeco.de/log"
+
+	refs "github.com/ssbc/go-ssb-refs"
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web"
+)
+
+func TestLoadRoomsConfig(t *testing.T) {
+	type testCase struct {
+		name   string
+		config string
+
+		// if set, loading needs to fail with an error that contains it
+		wantErr string
+
+		wantDomains []string
+	}
+
+	cases := []testCase{
+		{
+			name: "two rooms",
+			config: `[
+				{"domain": "One.example.net.", "repo": "one", "lismux": ":8008", "mode": "open"},
+				{"domain": "two.example.net:443", "repo": "two", "lismux": ":8009", "aliases-as-subdomains": false}
+			]`,
+			wantDomains: []string{"one.example.net", "two.example.net"},
+		},
+		{
+			name:    "not a list",
+			config:  `{"domain": "one.example.net"}`,
+			wantErr: "failed to decode",
+		},
+		{
+			name:    "unknown field",
+			config:  `[{"domain": "one.example.net", "repo": "one", "lismux": ":8008", "port": 8008}]`,
+			wantErr: "unknown field",
+		},
+		{
+			name:    "no rooms",
+			config:  `[]`,
+			wantErr: "doesn't contain any rooms",
+		},
+		{
+			name:    "empty domain",
+			config:  `[{"repo": "one", "lismux": ":8008"}]`,
+			wantErr: "domain can't be empty",
+		},
+		{
+			name: "duplicate domain",
+			config: `[
+				{"domain": "one.example.net", "repo": "one", "lismux": ":8008"},
+				{"domain": "ONE.example.net.", "repo": "two", "lismux": ":8009"}
+			]`,
+			wantErr: "domain one.example.net is used by more than one room",
+		},
+		{
+			name:    "empty repo",
+			config:  `[{"domain": "one.example.net", "lismux": ":8008"}]`,
+			wantErr: "repo can't be empty",
+		},
+		{
+			name: "duplicate repo",
+			config: `[
+				{"domain": "one.example.net", "repo": "same", "lismux": ":8008"},
+				{"domain": "two.example.net", "repo": "same", "lismux": ":8009"}
+			]`,
+			wantErr: "repo same is used by more than one room",
+		},
+		{
+			name:    "empty listener",
+			config:  `[{"domain": "one.example.net", "repo": "one"}]`,
+			wantErr: "lismux can't be empty",
+		},
+		{
+			name: "duplicate port",
+			config: `[
+				{"domain": "one.example.net", "repo": "one", "lismux": ":8008"},
+				{"domain": "two.example.net", "repo": "two", "lismux": ":8008"}
+			]`,
+			wantErr: "lismux :8008 is used by more than one room",
+		},
+		{
+			name:    "invalid mode",
+			config:  `[{"domain": "one.example.net", "repo": "one", "lismux": ":8008", "mode": "secret"}]`,
+			wantErr: "valid values are open, community, restricted",
+		},
+	}
+
+	dir := t.TempDir()
+	for i, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := require.New(t)
+
+			path := filepath.Join(dir, strings.Replace(tc.name, " ", "-", -1)+".json")
+			r.NoError(os.WriteFile(path, []byte(tc.config), 0600), "case %d", i)
+
+			rooms, err := loadRoomsConfig(path)
+			if tc.wantErr != "" {
+				r.Error(err)
+				r.Contains(err.Error(), tc.wantErr)
+				return
+			}
+			r.NoError(err)
+
+			r.Len(rooms, len(tc.wantDomains))
+			for i, room := range rooms {
+				r.Equal(tc.wantDomains[i], room.domain)
+			}
+		})
+	}
+
+	// the optional fields of the first case
+	rooms, err := loadRoomsConfig(filepath.Join(dir, "two-rooms.json"))
+	require.NoError(t, err)
+	assert.Equal(t, roomdb.ModeOpen, rooms[0].privacyMode)
+	assert.True(t, rooms[0].aliasesAsSubdomains)
+	assert.Equal(t, roomdb.ModeUnknown, rooms[1].privacyMode)
+	assert.False(t, rooms[1].aliasesAsSubdomains)
+
+	_, err = loadRoomsConfig(filepath.Join(dir, "does-not-exist.json"))
+	assert.Error(t, err)
+}
+
+// domainHandler replies with the domain of the room it belongs to
+func domainHandler(domain string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		io.WriteString(w, domain)
+	})
+}
+
+func TestHostRouter(t *testing.T) {
+	var rooms []*hostedRoom
+	for _, domain := range []string{"example.net", "sub.example.net", "other.org"} {
+		rooms = append(rooms, &hostedRoom{
+			domain:  domain,
+			handler: domainHandler(domain),
+		})
+	}
+	proxies, err := web.ParseTrustedProxies([]string{"127.0.0.1"})
+	require.NoError(t, err)
+	router := newHostRouter(rooms, proxies)
+
+	type testCase struct {
+		host          string
+		forwardedHost string
+		viaProxy      bool
+
+		// empty means no room should be found
+		wantRoom string
+	}
+
+	cases := []testCase{
+		{host: "example.net", wantRoom: "example.net"},
+		{host: "EXAMPLE.net.", wantRoom: "example.net"},
+		{host: "example.net:8443", wantRoom: "example.net"},
+		{host: "alice.example.net", wantRoom: "example.net"},
+
+		// the longest domain wins, for the room itself and its aliases
+		{host: "sub.example.net", wantRoom: "sub.example.net"},
+		{host: "bob.sub.example.net", wantRoom: "sub.example.net"},
+
+		{host: "other.org", wantRoom: "other.org"},
+
+		// the reverse proxy knows better
+		{host: "localhost:3000", forwardedHost: "other.org", viaProxy: true, wantRoom: "other.org"},
+		{host: "example.net", forwardedHost: "alice.sub.example.net", viaProxy: true, wantRoom: "sub.example.net"},
+
+		// but anybody else can't pick the room with the header
+		{host: "example.net", forwardedHost: "other.org", wantRoom: "example.net"},
+		{host: "localhost:3000", forwardedHost: "other.org"},
+
+		// unknown hosts
+		{host: "localhost:3000"},
+		{host: "badexample.net"},
+		{host: "example.net.evil.org"},
+		{host: "example.net", forwardedHost: "evil.org", viaProxy: true},
+	}
+
+	for i, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Host = tc.host
+		if tc.viaProxy {
+			req.RemoteAddr = "127.0.0.1:4321"
+		}
+		if tc.forwardedHost != "" {
+			req.Header.Set("X-Forwarded-Host", tc.forwardedHost)
+		}
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if tc.wantRoom == "" {
+			assert.Equal(t, http.StatusNotFound, rec.Code, "case %d: %s", i, tc.host)
+			continue
+		}
+
+		assert.Equal(t, http.StatusOK, rec.Code, "case %d: %s", i, tc.host)
+		assert.Equal(t, tc.wantRoom, rec.Body.String(), "case %d: %s via %q", i, tc.host, tc.forwardedHost)
+	}
+}
+
+// two rooms in the same process only know about their own members and aliases
+func TestRoomsIsolation(t *testing.T) {
+	r := require.New(t)
+	a := assert.New(t)
+
+	// setupRoom uses the flag values
+	heartbeatMaxMissed = 1
+	sessionIdleTimeout = time.Hour
+	sessionLifetime = 24 * time.Hour
+	flagDisableUNIXSock = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	throttleStore, err := memstore.New(1024)
+	r.NoError(err)
+	limiter, err := throttled.NewGCRARateLimiter(throttleStore, throttled.RateQuota{
+		MaxRate:  throttled.PerSec(100),
+		MaxBurst: 100,
+	})
+	r.NoError(err)
+	httpRateLimiter := throttled.HTTPRateLimiter{
+		RateLimiter: limiter,
+		VaryBy:      limitByPathAndAddr{},
+	}
+
+	appKey := make([]byte, 32)
+
+	dir := t.TempDir()
+	rooms := []*hostedRoom{
+		{domain: "one.example.net", repoDir: filepath.Join(dir, "one"), listenAddrMUXRPC: "localhost:0", privacyMode: roomdb.ModeCommunity},
+		{domain: "two.example.net", repoDir: filepath.Join(dir, "two"), listenAddrMUXRPC: "localhost:0", privacyMode: roomdb.ModeCommunity},
+	}
+	for _, room := range rooms {
+		err := setupRoom(ctx, kitlog.NewNopLogger(), room, appKey, 443, httpRateLimiter)
+		r.NoError(err, "room %s", room.domain)
+	}
+	defer func() {
+		for _, room := range rooms {
+			room.srv.Shutdown()
+			a.NoError(room.srv.Close())
+		}
+	}()
+	one, two := rooms[0], rooms[1]
+
+	a.False(one.srv.Whoami().Equal(two.srv.Whoami()), "rooms need their own identity")
+
+	alice, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte("a"), 32), refs.RefAlgoFeedSSB1)
+	r.NoError(err)
+
+	_, err = one.srv.Members.Add(ctx, alice, roomdb.RoleMember)
+	r.NoError(err)
+	r.NoError(one.srv.Aliases.Register(ctx, "alice", alice, bytes.Repeat([]byte("s"), 64)))
+
+	// the database of the other room doesn't know her
+	_, err = two.srv.Members.GetByFeed(ctx, alice)
+	a.True(errors.Is(err, roomdb.ErrNotFound), "wrong error: %v", err)
+	_, err = two.srv.Aliases.Resolve(ctx, "alice")
+	a.True(errors.Is(err, roomdb.ErrNotFound), "wrong error: %v", err)
+
+	// and neither does its web interface
+	router := newHostRouter(rooms, nil)
+	resolve := func(host string) map[string]string {
+		req := httptest.NewRequest(http.MethodGet, "/alias/alice?encoding=json", nil)
+		req.Host = host
+		req.Header.Set("X-Forwarded-Proto", "https")
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		r.Equal(http.StatusOK, rec.Code, "resolve on %s", host)
+
+		var resp map[string]string
+		r.NoError(json.NewDecoder(rec.Body).Decode(&resp))
+		return resp
+	}
+
+	resp := resolve(one.domain)
+	a.Equal("successful", resp["status"])
+	a.Equal(alice.String(), resp["userId"])
+	a.Equal(one.srv.Whoami().String(), resp["roomId"])
+
+	resp = resolve(two.domain)
+	a.Equal("error", resp["status"])
+	a.Equal("", resp["userId"])
+}
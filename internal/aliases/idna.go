@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package aliases
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/mtibben/confusables"
+	"golang.org/x/net/idna"
+)
+
+// profile maps aliases like UTS #46 does for domain lookups (lowercasing, width and compatibility mappings)
+// and checks the result against the IDNA2008 rules, so that every alias is also a valid DNS label.
+var profile = idna.New(
+	idna.MapForLookup(),
+	idna.Transitional(false),
+	idna.BidiRule(),
+	idna.CheckHyphens(true),
+	idna.CheckJoiners(true),
+	idna.VerifyDNSLength(true),
+)
+
+// Normalize returns the ASCII form of an alias, which is how it is stored and used in subdomains.
+// Plain aliases like "bob" stay the same, Unicode ones are turned into punycode ("xn--...").
+// It returns an error if the alias can't be a single DNS label.
+func Normalize(alias string) (string, error) {
+	ascii, err := profile.ToASCII(alias)
+	if err != nil {
+		return "", fmt.Errorf("aliases: %q is not a valid name: %w", alias, err)
+	}
+
+	if ascii == "" || strings.Contains(ascii, ".") {
+		return "", fmt.Errorf("aliases: %q needs to be a single label", alias)
+	}
+
+	return ascii, nil
+}
+
+// ToUnicode returns the form of a stored alias that is shown to people and signed by its owner.
+// If the alias can't be decoded, it is returned as is.
+func ToUnicode(alias string) string {
+	name, err := profile.ToUnicode(alias)
+	if err != nil {
+		return alias
+	}
+	return name
+}
+
+// ConfusableWith returns the first of the existing aliases that looks like the new one,
+// following the skeletons of UTS #39. Aliases that are exactly the same aren't reported,
+// since registering those fails anyway.
+func ConfusableWith(alias string, existing []string) (string, bool) {
+	skeleton := confusables.Skeleton(ToUnicode(alias))
+
+	for _, other := range existing {
+		if other == alias {
+			continue
+		}
+
+		if confusables.Skeleton(ToUnicode(other)) == skeleton {
+			return other, true
+		}
+	}
+
+	return "", false
+}
+
+// the script combinations which are allowed in a single label, like the highly restrictive level of UTS #39 does
+var allowedScriptSets = []map[string]bool{
+	{"Latin": true, "Han": true, "Hiragana": true, "Katakana": true},
+	{"Latin": true, "Han": true, "Bopomofo": true},
+	{"Latin": true, "Han": true, "Hangul": true},
+}
+
+// isSingleScript checks that a label doesn't mix scripts, which is how most homographs are built (like a cyrillic а in paypal).
+// Characters that are used by all scripts (like digits and hyphens) are ignored.
+func isSingleScript(label string) bool {
+	scripts := make(map[string]bool)
+
+	for _, r := range label {
+		if unicode.In(r, unicode.Common, unicode.Inherited) {
+			continue
+		}
+
+		for name, table := range unicode.Scripts {
+			if unicode.Is(table, r) {
+				scripts[name] = true
+				break
+			}
+		}
+	}
+
+	if len(scripts) <= 1 {
+		return true
+	}
+
+	for _, allowed := range allowedScriptSets {
+		var fits = true
+		for script := range scripts {
+			if !allowed[script] {
+				fits = false
+				break
+			}
+		}
+		if fits {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package aliases
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	a := assert.New(t)
+
+	cases := []struct {
+		alias string
+		ascii string
+		valid bool
+	}{
+		{"bob", "bob", true},
+		{"Bob", "bob", true},
+		{"bücher", "xn--bcher-kva", true},
+		{"Bücher", "xn--bcher-kva", true},
+		{"xn--bcher-kva", "xn--bcher-kva", true},
+
+		{"", "", false},
+		{"two.labels", "", false},
+		{"no spaces", "", false},
+		{"-leading", "", false},
+	}
+
+	for i, tc := range cases {
+		ascii, err := Normalize(tc.alias)
+		if !tc.valid {
+			a.Error(err, "should be invalid %d: %s", i, tc.alias)
+			continue
+		}
+		a.NoError(err, "should be valid %d: %s", i, tc.alias)
+		a.Equal(tc.ascii, ascii, "wrong ascii form for %d: %s", i, tc.alias)
+	}
+
+	a.Equal("bücher", ToUnicode("xn--bcher-kva"))
+	a.Equal("bob", ToUnicode("bob"))
+}
+
+func TestConfusableWith(t *testing.T) {
+	a := assert.New(t)
+
+	existing := []string{"ace", "bob"}
+
+	// cyrillic а, с and е look like the latin ones
+	lookalike, err := Normalize("асе")
+	a.NoError(err)
+
+	other, confusable := ConfusableWith(lookalike, existing)
+	a.True(confusable)
+	a.Equal("ace", other)
+
+	// the same alias is reported as taken, not as confusable
+	_, confusable = ConfusableWith("bob", existing)
+	a.False(confusable)
+
+	_, confusable = ConfusableWith("alice", existing)
+	a.False(confusable)
+}
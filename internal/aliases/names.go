@@ -4,15 +4,19 @@
 
 package aliases
 
+import "unicode"
+
 // IsValid decides whether an alias is okay for use or not.
 // The room spec defines it as _labels valid under RFC 1035_ ( https://ssbc.github.io/rooms2/#alias-string )
 // but that can be mostly any string since DNS is a 8bit binary protocol,
 // as long as it's shorter then 63 charachters.
 //
-// Right now it's pretty basic set of characters (a-z, 0-9).
-// In theory we could be more liberal but there is a bunch of stuff to figure out,
-// like homograph attacks (https://en.wikipedia.org/wiki/IDN_homograph_attack),
-// if we would decide to allow full utf8 unicode.
+// It expects the Unicode form of an alias (see Normalize and ToUnicode).
+// Besides a-z and 0-9, letters and digits of any script can be used, as long as they are lowercase
+// (or their script has no case) and the alias doesn't mix scripts.
+// Together with ConfusableWith, this keeps homograph attacks (https://en.wikipedia.org/wiki/IDN_homograph_attack) out.
+//
+// The admins of a room can narrow it down further, see CheckPolicy.
 func IsValid(alias string) bool {
 	if len(alias) > 63 {
 		return false
@@ -28,8 +32,16 @@ func IsValid(alias string) bool {
 			continue
 		}
 
+		if char > unicode.MaxASCII && !unicode.IsUpper(char) && !unicode.IsTitle(char) {
+			// letters, digits and the combining marks some scripts need for them
+			if unicode.IsLetter(char) || unicode.IsDigit(char) || unicode.In(char, unicode.Mn, unicode.Mc) {
+				continue
+			}
+		}
+
 		valid = false
 		break
 	}
-	return valid
+
+	return valid && isSingleScript(alias)
 }
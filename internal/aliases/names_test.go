@@ -24,6 +24,19 @@ func TestIsValid(t *testing.T) {
 
 		{"NoUpperCase", false},
 
+		// other scripts
+		{"müller", true},
+		{"пример", true},
+		{"Пример", false},
+		{"日本語", true},
+		{"にほんご", true},
+
+		// but not mixed, like a cyrillic а in paypal
+		{"pаypal", false},
+		{"приmер", false},
+		// japanese is commonly written with several scripts
+		{"日本のgo", true},
+
 		// too long
 		{"AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA", false},
 	}
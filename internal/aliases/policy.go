@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package aliases
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mtibben/confusables"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+)
+
+// CheckPolicy decides whether an alias can be registered under the policy of the room.
+// It expects the ASCII form of the alias (see Normalize) and checks the Unicode form against the rules of IsValid,
+// the length, hyphens, reserved names and blocked words.
+// If the alias isn't allowed, it returns roomdb.ErrAliasNotAllowed with the reason.
+func CheckPolicy(alias string, policy roomdb.AliasPolicy) error {
+	notAllowed := func(reason string) error {
+		return roomdb.ErrAliasNotAllowed{Name: alias, Reason: reason}
+	}
+
+	if alias == "" {
+		return notAllowed("it is empty")
+	}
+
+	if len(alias) > 63 {
+		return notAllowed("it is longer than 63 characters")
+	}
+
+	if normalized, err := Normalize(alias); err != nil || normalized != alias {
+		return notAllowed("it is not a normalized name")
+	}
+
+	name := ToUnicode(alias)
+
+	withoutHyphens := name
+	if policy.AllowHyphens {
+		if strings.HasPrefix(name, "-") || strings.HasSuffix(name, "-") {
+			return notAllowed("it can't start or end with a hyphen")
+		}
+		withoutHyphens = strings.ReplaceAll(name, "-", "")
+	}
+
+	if !IsValid(withoutHyphens) {
+		if policy.AllowHyphens {
+			return notAllowed("only lowercase letters and digits of one script and hyphens can be used")
+		}
+		return notAllowed("only lowercase letters and digits of one script can be used")
+	}
+
+	if uint(utf8.RuneCountInString(name)) < policy.MinLength {
+		return notAllowed(fmt.Sprintf("it needs at least %d characters", policy.MinLength))
+	}
+
+	for _, reserved := range policy.Reserved {
+		if name == strings.ToLower(reserved) || alias == strings.ToLower(reserved) {
+			return notAllowed("it is reserved")
+		}
+	}
+
+	for _, blocked := range policy.Blocked {
+		re, err := blockedPattern(blocked)
+		if err != nil {
+			return err
+		}
+
+		var matched bool
+		if re != nil {
+			matched = re.MatchString(name)
+		} else {
+			// also catch words that are spelled with lookalike characters
+			word := strings.ToLower(blocked)
+			matched = strings.Contains(name, word) ||
+				strings.Contains(confusables.Skeleton(name), confusables.Skeleton(word))
+		}
+
+		if matched {
+			return notAllowed("it contains a blocked word")
+		}
+	}
+
+	return nil
+}
+
+// ValidatePolicy checks that the regular expressions in the blocked list can be used
+func ValidatePolicy(policy roomdb.AliasPolicy) error {
+	for _, blocked := range policy.Blocked {
+		if _, err := blockedPattern(blocked); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// blockedPattern compiles entries like /^x+$/ and returns nil for plain words
+func blockedPattern(entry string) (*regexp.Regexp, error) {
+	if len(entry) < 2 || !strings.HasPrefix(entry, "/") || !strings.HasSuffix(entry, "/") {
+		return nil, nil
+	}
+
+	re, err := regexp.Compile(entry[1 : len(entry)-1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %s: %w", entry, err)
+	}
+
+	return re, nil
+}
@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package aliases
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+)
+
+func TestCheckPolicy(t *testing.T) {
+	a := assert.New(t)
+
+	policy := roomdb.AliasPolicy{
+		Reserved:  []string{"admin", "WWW"},
+		Blocked:   []string{"badword", "/^x+$/"},
+		MinLength: 3,
+	}
+
+	withHyphens := policy
+	withHyphens.AllowHyphens = true
+
+	cases := []struct {
+		alias   string
+		policy  roomdb.AliasPolicy
+		allowed bool
+	}{
+		{"basic", policy, true},
+		{"", policy, false},
+		{"ab", policy, false},
+		{"abc", policy, true},
+
+		{"admin", policy, false},
+		{"admins", policy, true},
+		{"www", policy, false},
+
+		{"mybadwordhere", policy, false},
+		{"xxxx", policy, false},
+		{"xxxy", policy, true},
+
+		{"with-hyphen", policy, false},
+		{"with-hyphen", withHyphens, true},
+		{"-leading", withHyphens, false},
+		{"trailing-", withHyphens, false},
+		{"No-Upper", withHyphens, false},
+		{"a-b", withHyphens, true},
+
+		// the policy doesn't loosen the basic rules
+		{"no spaces", policy, false},
+		{"AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA", policy, false},
+	}
+
+	for i, tc := range cases {
+		err := CheckPolicy(tc.alias, tc.policy)
+		if tc.allowed {
+			a.NoError(err, "should be allowed %d: %s", i, tc.alias)
+			continue
+		}
+
+		var notAllowed roomdb.ErrAliasNotAllowed
+		a.True(errors.As(err, &notAllowed), "wrong error for %d: %s: %v", i, tc.alias, err)
+	}
+
+	a.NoError(ValidatePolicy(policy))
+	a.Error(ValidatePolicy(roomdb.AliasPolicy{Blocked: []string{"/(/"}}))
+}
@@ -6,10 +6,10 @@ package broadcasts
 
 import (
 	"io"
+	"sort"
 	"sync"
 
 	refs "github.com/ssbc/go-ssb-refs"
-	"github.com/ssbc/go-ssb-room/v2/internal/maybemod/multierror"
 )
 
 type AttendantsEmitter interface {
@@ -19,12 +19,32 @@ type AttendantsEmitter interface {
 	io.Closer
 }
 
+// AttendantsUpdater is the writing side of an AttendantsBroadcast.
+// Updated announces that an attendant who is already present changed, like the features they announced.
+// Subscribers receive it as another Joined but, unlike a Joined, a Left that follows it isn't swallowed by the coalescing.
+type AttendantsUpdater interface {
+	AttendantsEmitter
+
+	Updated(member refs.FeedRef) error
+}
+
 // NewAttendantsEmitter returns the Sink, to write to the broadcaster, and the new
-// broadcast instance.
-func NewAttendantsEmitter() (AttendantsEmitter, *AttendantsBroadcast) {
+// broadcast instance. Subscribers can hold up to DefaultQueueSize pending changes.
+func NewAttendantsEmitter() (AttendantsUpdater, *AttendantsBroadcast) {
+	return NewAttendantsEmitterWithQueueSize(DefaultQueueSize)
+}
+
+// NewAttendantsEmitterWithQueueSize is like NewAttendantsEmitter but sets the number of pending changes
+// a subscriber can accumulate before it is disconnected with ErrSubscriberTooSlow.
+func NewAttendantsEmitterWithQueueSize(size int) (AttendantsUpdater, *AttendantsBroadcast) {
+	if size < 1 {
+		size = DefaultQueueSize
+	}
+
 	bcst := AttendantsBroadcast{
-		mu:    &sync.Mutex{},
-		sinks: make(map[*AttendantsEmitter]struct{}),
+		mu:        &sync.Mutex{},
+		queueSize: size,
+		sinks:     make(map[*attendantsSubscriber]struct{}),
 	}
 
 	return (*attendantsSink)(&bcst), &bcst
@@ -33,95 +53,227 @@ func NewAttendantsEmitter() (AttendantsEmitter, *AttendantsBroadcast) {
 // AttendantsBroadcast is an interface for registering one or more Sinks to recieve
 // updates.
 type AttendantsBroadcast struct {
-	mu    *sync.Mutex
-	sinks map[*AttendantsEmitter]struct{}
+	mu        *sync.Mutex
+	queueSize int
+	sinks     map[*attendantsSubscriber]struct{}
 }
 
-// Register a Sink for updates to be sent. also returns
+// Register a Sink for updates to be sent. also returns a function to unregister it again.
+// Updates are delivered asynchronously, in the order they were emitted.
 func (bcst *AttendantsBroadcast) Register(sink AttendantsEmitter) func() {
+	sub := newAttendantsSubscriber(sink, bcst.queueSize)
+
 	bcst.mu.Lock()
-	defer bcst.mu.Unlock()
-	bcst.sinks[&sink] = struct{}{}
+	bcst.sinks[sub] = struct{}{}
+	bcst.mu.Unlock()
+
+	go sub.run(bcst.remove)
 
 	return func() {
-		bcst.mu.Lock()
-		defer bcst.mu.Unlock()
-		delete(bcst.sinks, &sink)
-		sink.Close()
+		bcst.remove(sub)
+		sub.stop(nil)
 	}
 }
 
-type attendantsSink AttendantsBroadcast
+func (bcst *AttendantsBroadcast) remove(sub *attendantsSubscriber) {
+	bcst.mu.Lock()
+	delete(bcst.sinks, sub)
+	bcst.mu.Unlock()
+}
 
-func (bcst *attendantsSink) Joined(member refs.FeedRef) error {
+// flush blocks until all registered subscribers delivered their pending changes.
+func (bcst *AttendantsBroadcast) flush() {
 	bcst.mu.Lock()
-	for s := range bcst.sinks {
-		err := (*s).Joined(member)
-		if err != nil {
-			delete(bcst.sinks, s)
-		}
+	subs := make([]*attendantsSubscriber, 0, len(bcst.sinks))
+	for sub := range bcst.sinks {
+		subs = append(subs, sub)
 	}
 	bcst.mu.Unlock()
 
+	for _, sub := range subs {
+		sub.waitIdle()
+	}
+}
+
+type attendantsSink AttendantsBroadcast
+
+func (bcst *attendantsSink) Joined(member refs.FeedRef) error {
+	bcst.queue(member, attendantJoined)
 	return nil
 }
 
 func (bcst *attendantsSink) Left(member refs.FeedRef) error {
-	bcst.mu.Lock()
-	for s := range bcst.sinks {
-		err := (*s).Left(member)
-		if err != nil {
-			delete(bcst.sinks, s)
-		}
-	}
-	bcst.mu.Unlock()
+	bcst.queue(member, attendantLeft)
+	return nil
+}
 
+func (bcst *attendantsSink) Updated(member refs.FeedRef) error {
+	bcst.queue(member, attendantUpdated)
 	return nil
 }
 
-// Close implements the Sink interface.
-func (bcst *attendantsSink) Close() error {
+// queue hands the change to each subscriber without waiting for them to deliver it.
+// Subscribers with an overflowing queue are dropped.
+func (bcst *attendantsSink) queue(member refs.FeedRef, kind attendantChangeKind) {
 	bcst.mu.Lock()
 	defer bcst.mu.Unlock()
-	sinks := make([]AttendantsEmitter, 0, len(bcst.sinks))
 
-	for sink := range bcst.sinks {
-		sinks = append(sinks, *sink)
+	for sub := range bcst.sinks {
+		if !sub.push(member, kind) {
+			delete(bcst.sinks, sub)
+			go sub.stop(ErrSubscriberTooSlow)
+		}
 	}
+}
 
+// Close implements the Sink interface.
+func (bcst *attendantsSink) Close() error {
 	bcst.mu.Lock()
-	defer bcst.mu.Unlock()
+	subs := make([]stopper, 0, len(bcst.sinks))
+	for sub := range bcst.sinks {
+		subs = append(subs, sub)
+	}
+	bcst.sinks = make(map[*attendantsSubscriber]struct{})
+	bcst.mu.Unlock()
 
-	sinks = make([]AttendantsEmitter, 0, len(bcst.sinks))
+	return stopAll(subs)
+}
 
-	for sink := range bcst.sinks {
-		sinks = append(sinks, *sink)
-	}
+type attendantChangeKind uint
+
+const (
+	attendantJoined attendantChangeKind = iota
+	attendantLeft
+	attendantUpdated
+)
+
+type attendantChange struct {
+	seq    uint64
+	member refs.FeedRef
+	kind   attendantChangeKind
+}
 
-	var (
-		wg sync.WaitGroup
-		me multierror.List
-	)
+// attendantsSubscriber coalesces the changes that weren't delivered yet into a diff of the room state.
+// A join and a leave of the same member cancel each other out, which keeps the queue short during bursts.
+// An update only stands in for a join the subscriber was already told about, so a leave replaces it instead.
+type attendantsSubscriber struct {
+	subscription
 
-	// might be fine without the waitgroup and concurrency
+	emitter   AttendantsEmitter
+	queueSize int
 
-	wg.Add(len(sinks))
-	for _, sink_ := range sinks {
-		go func(sink AttendantsEmitter) {
-			defer wg.Done()
+	seq     uint64
+	pending map[string]attendantChange
+}
+
+func newAttendantsSubscriber(sink AttendantsEmitter, queueSize int) *attendantsSubscriber {
+	sub := &attendantsSubscriber{
+		emitter:   sink,
+		queueSize: queueSize,
+		pending:   make(map[string]attendantChange),
+	}
+	sub.init(sink)
+	return sub
+}
+
+// push adds the change to the pending diff. It returns false if the queue is full.
+func (sub *attendantsSubscriber) push(member refs.FeedRef, kind attendantChangeKind) bool {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
 
-			err := sink.Close()
-			if err != nil {
-				me.Errs = append(me.Errs, err)
-				return
+	key := member.String()
+	if prev, has := sub.pending[key]; has {
+		switch {
+		case prev.kind == attendantJoined && kind == attendantLeft,
+			prev.kind == attendantLeft && kind == attendantJoined:
+			// the subscriber didn't see the previous change yet
+			delete(sub.pending, key)
+
+		case prev.kind == attendantUpdated && kind == attendantLeft:
+			// the subscriber knows about this member, it needs to hear that they left
+			sub.seq++
+			sub.pending[key] = attendantChange{
+				seq:    sub.seq,
+				member: member,
+				kind:   attendantLeft,
 			}
-		}(sink_)
+		}
+		return true
+	}
+
+	if len(sub.pending) >= sub.queueSize {
+		return false
 	}
-	wg.Wait()
 
-	if len(me.Errs) == 0 {
+	sub.seq++
+	sub.pending[key] = attendantChange{
+		seq:    sub.seq,
+		member: member,
+		kind:   kind,
+	}
+	sub.notify()
+	return true
+}
+
+// take returns the pending changes in the order they were queued and marks the worker busy until it asks for more.
+func (sub *attendantsSubscriber) take() []attendantChange {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+
+	if len(sub.pending) == 0 {
+		sub.setBusy(false)
 		return nil
 	}
+	sub.setBusy(true)
 
-	return me
+	changes := make([]attendantChange, 0, len(sub.pending))
+	for _, c := range sub.pending {
+		changes = append(changes, c)
+	}
+	sub.pending = make(map[string]attendantChange)
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].seq < changes[j].seq
+	})
+	return changes
+}
+
+func (sub *attendantsSubscriber) run(unregister func(*attendantsSubscriber)) {
+	for {
+		select {
+		case <-sub.done:
+			return
+		case <-sub.wakeup:
+		}
+
+		for changes := sub.take(); len(changes) > 0; changes = sub.take() {
+			for _, c := range changes {
+				select {
+				case <-sub.done:
+					return
+				default:
+				}
+
+				var err error
+				if c.kind == attendantLeft {
+					err = sub.emitter.Left(c.member)
+				} else {
+					err = sub.emitter.Joined(c.member)
+				}
+				if err != nil {
+					unregister(sub)
+					sub.stop(err)
+					return
+				}
+			}
+		}
+	}
+}
+
+func (sub *attendantsSubscriber) waitIdle() {
+	sub.mu.Lock()
+	for (sub.busy || len(sub.pending) > 0) && !sub.stopped {
+		sub.idle.Wait()
+	}
+	sub.mu.Unlock()
 }
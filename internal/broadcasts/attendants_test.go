@@ -0,0 +1,310 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package broadcasts
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	refs "github.com/ssbc/go-ssb-refs"
+)
+
+func makeAttendants(t testing.TB, n int) []refs.FeedRef {
+	feeds := make([]refs.FeedRef, n)
+	for i := range feeds {
+		key := bytes.Repeat([]byte{0}, 32)
+		binary.BigEndian.PutUint32(key, uint32(i))
+
+		fr, err := refs.NewFeedRefFromBytes(key, refs.RefAlgoFeedSSB1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		feeds[i] = fr
+	}
+	return feeds
+}
+
+// recordingSink applies all the changes it receives to a set of present attendants
+type recordingSink struct {
+	mu      sync.Mutex
+	present map[string]struct{}
+	events  []string
+
+	// if gate is set, every call waits for a value before returning
+	gate chan struct{}
+	// if entered is set, every call sends on it before waiting for the gate
+	entered chan struct{}
+
+	closed    chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+}
+
+func newRecordingSink() *recordingSink {
+	return &recordingSink{
+		present: make(map[string]struct{}),
+		closed:  make(chan struct{}),
+	}
+}
+
+func (rs *recordingSink) Joined(member refs.FeedRef) error {
+	if rs.entered != nil {
+		rs.entered <- struct{}{}
+	}
+	if rs.gate != nil {
+		<-rs.gate
+	}
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.present[member.String()] = struct{}{}
+	rs.events = append(rs.events, "joined "+member.String())
+	return nil
+}
+
+func (rs *recordingSink) Left(member refs.FeedRef) error {
+	if rs.entered != nil {
+		rs.entered <- struct{}{}
+	}
+	if rs.gate != nil {
+		<-rs.gate
+	}
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	delete(rs.present, member.String())
+	rs.events = append(rs.events, "left "+member.String())
+	return nil
+}
+
+func (rs *recordingSink) Close() error {
+	return rs.CloseWithError(nil)
+}
+
+func (rs *recordingSink) CloseWithError(err error) error {
+	rs.closeOnce.Do(func() {
+		rs.closeErr = err
+		close(rs.closed)
+	})
+	return nil
+}
+
+func (rs *recordingSink) count() int {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return len(rs.present)
+}
+
+func TestAttendantsThousands(t *testing.T) {
+	const (
+		numAttendants  = 5000
+		numSubscribers = 25
+	)
+
+	sink, bcast := NewAttendantsEmitterWithQueueSize(numAttendants)
+	defer sink.Close()
+
+	subs := make([]*recordingSink, numSubscribers)
+	for i := range subs {
+		subs[i] = newRecordingSink()
+		unregister := bcast.Register(subs[i])
+		defer unregister()
+	}
+
+	attendants := makeAttendants(t, numAttendants)
+
+	for _, a := range attendants {
+		sink.Joined(a)
+	}
+	bcast.flush()
+
+	for i, s := range subs {
+		if n := s.count(); n != numAttendants {
+			t.Errorf("subscriber %d: expected %d attendants but got %d", i, numAttendants, n)
+		}
+	}
+
+	// leave concurrently
+	var wg sync.WaitGroup
+	wg.Add(numAttendants)
+	for _, a := range attendants {
+		go func(a refs.FeedRef) {
+			sink.Left(a)
+			wg.Done()
+		}(a)
+	}
+	wg.Wait()
+	bcast.flush()
+
+	for i, s := range subs {
+		if n := s.count(); n != 0 {
+			t.Errorf("subscriber %d: expected everybody to have left but %d remained", i, n)
+		}
+	}
+}
+
+func TestAttendantsSlowSubscriberIsDropped(t *testing.T) {
+	const numAttendants = 3000
+
+	sink, bcast := NewAttendantsEmitterWithQueueSize(100)
+	defer sink.Close()
+
+	fast := newRecordingSink()
+	defer bcast.Register(fast)()
+
+	// slow never returns from the first call until the end of the test
+	slow := newRecordingSink()
+	slow.gate = make(chan struct{})
+	defer close(slow.gate)
+	defer bcast.Register(slow)()
+
+	attendants := makeAttendants(t, numAttendants)
+
+	emitted := make(chan struct{})
+	go func() {
+		for i, a := range attendants {
+			sink.Joined(a)
+			if i%50 == 0 {
+				// give the fast subscriber a chance to keep up with its smaller queue
+				bcast.flushSubscriber(fast)
+			}
+		}
+		close(emitted)
+	}()
+
+	select {
+	case <-emitted:
+	case <-time.After(10 * time.Second):
+		t.Fatal("emitting was blocked by the slow subscriber")
+	}
+
+	select {
+	case <-slow.closed:
+		if !errors.Is(slow.closeErr, ErrSubscriberTooSlow) {
+			t.Errorf("expected the slow subscriber to be closed with ErrSubscriberTooSlow but got: %v", slow.closeErr)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("slow subscriber wasn't disconnected")
+	}
+
+	bcast.flush()
+	if n := fast.count(); n != numAttendants {
+		t.Errorf("expected fast subscriber to get all %d attendants but got %d", numAttendants, n)
+	}
+
+	select {
+	case <-fast.closed:
+		t.Error("fast subscriber shouldn't be closed")
+	default:
+	}
+}
+
+func TestAttendantsCoalescing(t *testing.T) {
+	sink, bcast := NewAttendantsEmitter()
+	defer sink.Close()
+
+	rs := newRecordingSink()
+	rs.gate = make(chan struct{})
+	defer bcast.Register(rs)()
+
+	feeds := makeAttendants(t, 3)
+	a, b, c := feeds[0], feeds[1], feeds[2]
+
+	// the subscriber is now stuck delivering this one
+	sink.Joined(a)
+
+	// these happen while the subscriber is busy
+	sink.Joined(b)
+	sink.Left(b)
+	sink.Left(a)
+	sink.Joined(a)
+	sink.Joined(c)
+
+	close(rs.gate)
+	bcast.flush()
+
+	expected := []string{
+		"joined " + a.String(),
+		"joined " + c.String(),
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	if len(rs.events) != len(expected) {
+		t.Fatalf("expected %d events but got %d: %v", len(expected), len(rs.events), rs.events)
+	}
+	for i, evt := range expected {
+		if rs.events[i] != evt {
+			t.Errorf("event %d: expected %q but got %q", i, evt, rs.events[i])
+		}
+	}
+}
+
+// an attendant changes their features (which the room sends as an update) and disconnects
+// while a slow subscriber still has the update queued. The leave needs to get through.
+func TestAttendantsUpdateThenLeave(t *testing.T) {
+	sink, bcast := NewAttendantsEmitter()
+	defer sink.Close()
+
+	rs := newRecordingSink()
+	rs.gate = make(chan struct{})
+	rs.entered = make(chan struct{}, 3)
+	defer bcast.Register(rs)()
+
+	feeds := makeAttendants(t, 2)
+	a, b := feeds[0], feeds[1]
+
+	// the subscriber is now stuck delivering this one
+	sink.Joined(a)
+	<-rs.entered
+
+	// these happen while the subscriber is busy
+	sink.Joined(b)
+	sink.Updated(a)
+	sink.Left(a)
+	sink.Updated(b)
+
+	close(rs.gate)
+	bcast.flush()
+
+	expected := []string{
+		"joined " + a.String(),
+		"joined " + b.String(),
+		"left " + a.String(),
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	if len(rs.events) != len(expected) {
+		t.Fatalf("expected %d events but got %d: %v", len(expected), len(rs.events), rs.events)
+	}
+	for i, evt := range expected {
+		if rs.events[i] != evt {
+			t.Errorf("event %d: expected %q but got %q", i, evt, rs.events[i])
+		}
+	}
+
+	if _, has := rs.present[a.String()]; has {
+		t.Error("a should have left")
+	}
+}
+
+// flushSubscriber waits until the subscriber for sink is done delivering
+func (bcst *AttendantsBroadcast) flushSubscriber(sink AttendantsEmitter) {
+	bcst.mu.Lock()
+	var found *attendantsSubscriber
+	for sub := range bcst.sinks {
+		if sub.emitter == sink {
+			found = sub
+		}
+	}
+	bcst.mu.Unlock()
+
+	if found != nil {
+		found.waitIdle()
+	}
+}
@@ -7,8 +7,6 @@ package broadcasts
 import (
 	"io"
 	"sync"
-
-	"github.com/ssbc/go-ssb-room/v2/internal/maybemod/multierror"
 )
 
 type EndpointsEmitter interface {
@@ -21,7 +19,7 @@ type EndpointsEmitter interface {
 func NewEndpointsEmitter() (EndpointsEmitter, *EndpointsBroadcast) {
 	bcst := EndpointsBroadcast{
 		mu:    &sync.Mutex{},
-		sinks: make(map[*EndpointsEmitter]struct{}),
+		sinks: make(map[*endpointsSubscriber]struct{}),
 	}
 
 	return (*endpointsSink)(&bcst), &bcst
@@ -31,34 +29,53 @@ func NewEndpointsEmitter() (EndpointsEmitter, *EndpointsBroadcast) {
 // updates.
 type EndpointsBroadcast struct {
 	mu    *sync.Mutex
-	sinks map[*EndpointsEmitter]struct{}
+	sinks map[*endpointsSubscriber]struct{}
 }
 
-// Register a Sink for updates to be sent. also returns
+// Register a Sink for updates to be sent. also returns a function to unregister it again.
+// Updates are delivered asynchronously and a slow sink only receives the latest list.
 func (bcst *EndpointsBroadcast) Register(sink EndpointsEmitter) func() {
+	sub := newEndpointsSubscriber(sink)
+
 	bcst.mu.Lock()
-	defer bcst.mu.Unlock()
-	bcst.sinks[&sink] = struct{}{}
+	bcst.sinks[sub] = struct{}{}
+	bcst.mu.Unlock()
+
+	go sub.run(bcst.remove)
 
 	return func() {
-		bcst.mu.Lock()
-		defer bcst.mu.Unlock()
-		delete(bcst.sinks, &sink)
-		sink.Close()
+		bcst.remove(sub)
+		sub.stop(nil)
+	}
+}
+
+func (bcst *EndpointsBroadcast) remove(sub *endpointsSubscriber) {
+	bcst.mu.Lock()
+	delete(bcst.sinks, sub)
+	bcst.mu.Unlock()
+}
+
+// flush blocks until all registered subscribers delivered their pending update.
+func (bcst *EndpointsBroadcast) flush() {
+	bcst.mu.Lock()
+	subs := make([]*endpointsSubscriber, 0, len(bcst.sinks))
+	for sub := range bcst.sinks {
+		subs = append(subs, sub)
+	}
+	bcst.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.waitIdle()
 	}
 }
 
 type endpointsSink EndpointsBroadcast
 
-// Pour implements the Sink interface.
+// Update implements the Sink interface.
 func (bcst *endpointsSink) Update(members []string) error {
-
 	bcst.mu.Lock()
-	for s := range bcst.sinks {
-		err := (*s).Update(members)
-		if err != nil {
-			delete(bcst.sinks, s)
-		}
+	for sub := range bcst.sinks {
+		sub.push(members)
 	}
 	bcst.mu.Unlock()
 
@@ -67,41 +84,79 @@ func (bcst *endpointsSink) Update(members []string) error {
 
 // Close implements the Sink interface.
 func (bcst *endpointsSink) Close() error {
-	var sinks []EndpointsEmitter
-
 	bcst.mu.Lock()
-	defer bcst.mu.Unlock()
+	subs := make([]stopper, 0, len(bcst.sinks))
+	for sub := range bcst.sinks {
+		subs = append(subs, sub)
+	}
+	bcst.sinks = make(map[*endpointsSubscriber]struct{})
+	bcst.mu.Unlock()
 
-	sinks = make([]EndpointsEmitter, 0, len(bcst.sinks))
+	return stopAll(subs)
+}
 
-	for sink := range bcst.sinks {
-		sinks = append(sinks, *sink)
-	}
+// endpointsSubscriber only keeps the latest list of members since each update replaces the previous one.
+type endpointsSubscriber struct {
+	subscription
+
+	emitter EndpointsEmitter
+
+	latest     []string
+	hasPending bool
+}
+
+func newEndpointsSubscriber(sink EndpointsEmitter) *endpointsSubscriber {
+	sub := &endpointsSubscriber{emitter: sink}
+	sub.init(sink)
+	return sub
+}
 
-	var (
-		wg sync.WaitGroup
-		me multierror.List
-	)
+func (sub *endpointsSubscriber) push(members []string) {
+	sub.mu.Lock()
+	sub.latest = members
+	sub.hasPending = true
+	sub.notify()
+	sub.mu.Unlock()
+}
 
-	// might be fine without the waitgroup and concurrency
+func (sub *endpointsSubscriber) take() ([]string, bool) {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
 
-	wg.Add(len(sinks))
-	for _, sink_ := range sinks {
-		go func(sink EndpointsEmitter) {
-			defer wg.Done()
+	if !sub.hasPending {
+		sub.setBusy(false)
+		return nil, false
+	}
+	sub.setBusy(true)
 
-			err := sink.Close()
+	members := sub.latest
+	sub.latest, sub.hasPending = nil, false
+	return members, true
+}
+
+func (sub *endpointsSubscriber) run(unregister func(*endpointsSubscriber)) {
+	for {
+		select {
+		case <-sub.done:
+			return
+		case <-sub.wakeup:
+		}
+
+		for members, has := sub.take(); has; members, has = sub.take() {
+			err := sub.emitter.Update(members)
 			if err != nil {
-				me.Errs = append(me.Errs, err)
+				unregister(sub)
+				sub.stop(err)
 				return
 			}
-		}(sink_)
+		}
 	}
-	wg.Wait()
+}
 
-	if len(me.Errs) == 0 {
-		return nil
+func (sub *endpointsSubscriber) waitIdle() {
+	sub.mu.Lock()
+	for (sub.busy || sub.hasPending) && !sub.stopped {
+		sub.idle.Wait()
 	}
-
-	return me
+	sub.mu.Unlock()
 }
@@ -39,6 +39,7 @@ func ExampleBroadcast() {
 	defer closeSink()
 
 	sink.Update([]string{"whoop", "whoop"})
+	bcast.flush()
 
 	// Output:
 	// test: 2
@@ -60,6 +61,7 @@ func ExampleBroadcastCanceled() {
 	closeSink() // p2 never prints
 
 	sink.Update([]string{"hi"})
+	bcast.flush()
 
 	// Output:
 	// test: 1
@@ -95,8 +97,10 @@ func TestBroadcastOneErrs(t *testing.T) {
 	defer closeSink()
 
 	sink.Update([]string{"run1"})
+	bcast.flush()
 
 	sink.Update([]string{"run", "2"})
+	bcast.flush()
 
 	output := buf.String()
 
@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package broadcasts
+
+import (
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/ssbc/go-ssb-room/v2/internal/maybemod/multierror"
+)
+
+// DefaultQueueSize is the number of pending updates a single subscriber can accumulate before it is considered too slow.
+const DefaultQueueSize = 1024
+
+// ErrSubscriberTooSlow is passed to subscribers that are disconnected because their queue of pending updates overflowed.
+var ErrSubscriberTooSlow = errors.New("broadcasts: subscriber too slow, queue of pending updates overflowed")
+
+// errorCloser is implemented by sinks that can tell the other side why they were closed, like muxrpc.ByteSink.
+type errorCloser interface {
+	CloseWithError(error) error
+}
+
+// subscription holds the bookkeeping that is shared by the typed subscribers.
+// Each subscriber gets its own goroutine which delivers the pending updates to the sink.
+// That way the emitting side never waits on a single slow sink.
+type subscription struct {
+	// mu also guards the pending updates of the typed subscribers
+	mu sync.Mutex
+
+	// idle is signaled once nothing is pending and the worker is done delivering
+	idle    *sync.Cond
+	busy    bool
+	stopped bool
+
+	wakeup chan struct{}
+	done   chan struct{}
+
+	sink     io.Closer
+	stopOnce sync.Once
+	stopErr  error
+}
+
+func (s *subscription) init(sink io.Closer) {
+	s.idle = sync.NewCond(&s.mu)
+	s.wakeup = make(chan struct{}, 1)
+	s.done = make(chan struct{})
+	s.sink = sink
+}
+
+// notify schedules the worker, if it isn't already. s.mu needs to be held.
+func (s *subscription) notify() {
+	select {
+	case s.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// setBusy updates the worker state and wakes up waiters once it is done. s.mu needs to be held.
+func (s *subscription) setBusy(busy bool) {
+	s.busy = busy
+	if !busy {
+		s.idle.Broadcast()
+	}
+}
+
+// stop ends the delivery of updates and closes the sink.
+// If reason is not nil, it is passed to sinks that support closing with an error.
+func (s *subscription) stop(reason error) error {
+	s.stopOnce.Do(func() {
+		s.mu.Lock()
+		s.stopped = true
+		s.idle.Broadcast()
+		s.mu.Unlock()
+
+		close(s.done)
+
+		if ec, ok := s.sink.(errorCloser); ok && reason != nil {
+			s.stopErr = ec.CloseWithError(reason)
+			return
+		}
+		s.stopErr = s.sink.Close()
+	})
+	return s.stopErr
+}
+
+type stopper interface {
+	stop(reason error) error
+}
+
+// stopAll closes all the passed subscribers concurrently and collects the errors of doing so.
+func stopAll(subs []stopper) error {
+	var (
+		wg sync.WaitGroup
+
+		mu sync.Mutex
+		me multierror.List
+	)
+
+	wg.Add(len(subs))
+	for _, sub_ := range subs {
+		go func(sub stopper) {
+			defer wg.Done()
+
+			err := sub.stop(nil)
+			if err != nil {
+				mu.Lock()
+				me.Errs = append(me.Errs, err)
+				mu.Unlock()
+			}
+		}(sub_)
+	}
+	wg.Wait()
+
+	if len(me.Errs) == 0 {
+		return nil
+	}
+
+	return me
+}
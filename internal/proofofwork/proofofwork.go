@@ -0,0 +1,176 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+// Package proofofwork makes clients spend some CPU time before they can do something that is cheap to ask for,
+// like creating an invite in an open room, without depending on an external captcha service.
+//
+// A challenge looks like <bits>.<expires>.<random>.<mac>. It is signed by the issuer, so nothing needs to be stored until it is solved.
+// A solution is a nonce for which the sha256 of <challenge>:<nonce> starts with at least bits zero bits.
+package proofofwork
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrInvalidChallenge is returned for challenges that weren't issued by this issuer or are malformed
+	ErrInvalidChallenge = errors.New("proofofwork: invalid challenge")
+
+	// ErrExpired is returned for challenges that were solved too late
+	ErrExpired = errors.New("proofofwork: challenge expired")
+
+	// ErrInsufficientWork is returned if the nonce doesn't solve the challenge, or the challenge is easier than required
+	ErrInsufficientWork = errors.New("proofofwork: insufficient work")
+
+	// ErrAlreadyUsed is returned if the solution for the challenge was already accepted once
+	ErrAlreadyUsed = errors.New("proofofwork: challenge was already used")
+)
+
+// nonces are counters, they don't need to be long
+const maxNonceLength = 64
+
+// Issuer hands out challenges and verifies their solutions.
+// Its key only lives in memory, so challenges don't survive a restart.
+type Issuer struct {
+	key      []byte
+	validity time.Duration
+
+	mu   sync.Mutex
+	used map[string]time.Time // the accepted challenges, until they expire
+}
+
+// NewIssuer returns an issuer with a random key, whose challenges can be solved for validity.
+func NewIssuer(validity time.Duration) *Issuer {
+	key := make([]byte, 32)
+	rand.Read(key)
+
+	return &Issuer{
+		key:      key,
+		validity: validity,
+		used:     make(map[string]time.Time),
+	}
+}
+
+// Challenge returns a new challenge that needs a solution with n leading zero bits.
+func (iss *Issuer) Challenge(n uint) string {
+	randBytes := make([]byte, 16)
+	rand.Read(randBytes)
+
+	expires := time.Now().Add(iss.validity).Unix()
+	unsigned := fmt.Sprintf("%d.%d.%s", n, expires, base64.RawURLEncoding.EncodeToString(randBytes))
+
+	return unsigned + "." + iss.mac(unsigned)
+}
+
+func (iss *Issuer) mac(unsigned string) string {
+	h := hmac.New(sha256.New, iss.key)
+	h.Write([]byte(unsigned))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Verify checks that nonce solves the challenge, which needs to ask for at least minBits.
+// Each challenge is only accepted once.
+func (iss *Issuer) Verify(challenge, nonce string, minBits uint) error {
+	n, expires, err := parse(challenge)
+	if err != nil {
+		return err
+	}
+
+	lastDot := strings.LastIndex(challenge, ".")
+	if !hmac.Equal([]byte(iss.mac(challenge[:lastDot])), []byte(challenge[lastDot+1:])) {
+		return ErrInvalidChallenge
+	}
+
+	now := time.Now()
+	if now.After(expires) {
+		return ErrExpired
+	}
+
+	if n < minBits || len(nonce) > maxNonceLength || !Solves(challenge, nonce, n) {
+		return ErrInsufficientWork
+	}
+
+	iss.mu.Lock()
+	defer iss.mu.Unlock()
+
+	for c, exp := range iss.used {
+		if now.After(exp) {
+			delete(iss.used, c)
+		}
+	}
+
+	if _, has := iss.used[challenge]; has {
+		return ErrAlreadyUsed
+	}
+	iss.used[challenge] = expires
+
+	return nil
+}
+
+// Bits returns how many leading zero bits the solution of the challenge needs.
+func Bits(challenge string) (uint, error) {
+	n, _, err := parse(challenge)
+	return n, err
+}
+
+func parse(challenge string) (uint, time.Time, error) {
+	parts := strings.Split(challenge, ".")
+	if len(parts) != 4 {
+		return 0, time.Time{}, ErrInvalidChallenge
+	}
+
+	n, err := strconv.ParseUint(parts[0], 10, 8)
+	if err != nil || n > sha256.Size*8 {
+		return 0, time.Time{}, ErrInvalidChallenge
+	}
+
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, time.Time{}, ErrInvalidChallenge
+	}
+
+	return uint(n), time.Unix(expires, 0), nil
+}
+
+// Solves tells if the sha256 of challenge:nonce starts with at least n zero bits.
+func Solves(challenge, nonce string, n uint) bool {
+	sum := sha256.Sum256([]byte(challenge + ":" + nonce))
+
+	var zeros uint
+	for _, b := range sum {
+		if b != 0 {
+			zeros += uint(bits.LeadingZeros8(b))
+			break
+		}
+		zeros += 8
+	}
+
+	return zeros >= n
+}
+
+// Solve finds a nonce for the challenge by counting up. It's what the javascript of the web frontend does, for clients that can't run it.
+func Solve(challenge string) (string, error) {
+	n, err := Bits(challenge)
+	if err != nil {
+		return "", err
+	}
+
+	for i := uint64(0); ; i++ {
+		nonce := strconv.FormatUint(i, 10)
+		if Solves(challenge, nonce, n) {
+			return nonce, nil
+		}
+	}
+}
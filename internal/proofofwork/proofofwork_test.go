@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package proofofwork
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSolveAndVerify(t *testing.T) {
+	r := require.New(t)
+
+	iss := NewIssuer(time.Minute)
+
+	challenge := iss.Challenge(8)
+
+	n, err := Bits(challenge)
+	r.NoError(err)
+	r.EqualValues(8, n)
+
+	nonce, err := Solve(challenge)
+	r.NoError(err)
+	r.True(Solves(challenge, nonce, 8))
+
+	// asking for more than the challenge did
+	err = iss.Verify(challenge, nonce, 9)
+	r.True(errors.Is(err, ErrInsufficientWork), "wrong error: %v", err)
+
+	r.NoError(iss.Verify(challenge, nonce, 8))
+
+	// only once
+	err = iss.Verify(challenge, nonce, 8)
+	r.True(errors.Is(err, ErrAlreadyUsed), "wrong error: %v", err)
+
+	// a wrong nonce
+	challenge = iss.Challenge(16)
+	nonce, err = Solve(challenge)
+	r.NoError(err)
+	for i := 0; Solves(challenge, nonce, 16); i++ {
+		nonce += "x"
+	}
+	err = iss.Verify(challenge, nonce, 16)
+	r.True(errors.Is(err, ErrInsufficientWork), "wrong error: %v", err)
+}
+
+func TestVerifyInvalid(t *testing.T) {
+	r := require.New(t)
+
+	iss := NewIssuer(time.Minute)
+
+	// the difficulty can't be lowered
+	challenge := iss.Challenge(20)
+	easier := "1" + strings.TrimPrefix(challenge, "20")
+	nonce, err := Solve(easier)
+	r.NoError(err)
+	err = iss.Verify(easier, nonce, 0)
+	r.True(errors.Is(err, ErrInvalidChallenge), "wrong error: %v", err)
+
+	// challenges of other issuers aren't valid
+	challenge = NewIssuer(time.Minute).Challenge(1)
+	nonce, err = Solve(challenge)
+	r.NoError(err)
+	err = iss.Verify(challenge, nonce, 1)
+	r.True(errors.Is(err, ErrInvalidChallenge), "wrong error: %v", err)
+
+	for _, c := range []string{"", "nope", "1.2.3", "300.1.a.b"} {
+		err = iss.Verify(c, "0", 0)
+		r.True(errors.Is(err, ErrInvalidChallenge), "wrong error for %q: %v", c, err)
+	}
+
+	expired := NewIssuer(-time.Minute)
+	challenge = expired.Challenge(1)
+	nonce, err = Solve(challenge)
+	r.NoError(err)
+	err = expired.Verify(challenge, nonce, 1)
+	r.True(errors.Is(err, ErrExpired), "wrong error: %v", err)
+}
@@ -28,7 +28,8 @@ type Handler struct {
 	logger kitlog.Logger
 	self   refs.FeedRef
 
-	db roomdb.AliasesService
+	db     roomdb.AliasesService
+	config roomdb.RoomConfig
 
 	netInfo network.ServerEndpointDetails
 
@@ -36,13 +37,14 @@ type Handler struct {
 }
 
 // New returns a fresh alias muxrpc handler
-func New(log kitlog.Logger, self refs.FeedRef, aliasesDB roomdb.AliasesService, netInfo network.ServerEndpointDetails) Handler {
+func New(log kitlog.Logger, self refs.FeedRef, aliasesDB roomdb.AliasesService, config roomdb.RoomConfig, netInfo network.ServerEndpointDetails) Handler {
 
 	var h Handler
 	h.self = self
 	h.netInfo = netInfo
 	h.logger = log
 	h.db = aliasesDB
+	h.config = config
 
 	return h
 }
@@ -55,6 +57,9 @@ var httpRouter = router.CompleteApp()
 // It receives two string arguments over muxrpc (alias and signature),
 // checks the signature confirmation is correct (for this room and signed by the key of theconnection)
 // If it is valid, it registers the alias on the roomdb and returns true. If not it returns an error.
+//
+// The alias needs to be in its normalized Unicode form, which is also what is signed.
+// The roomdb stores the ASCII (punycode) form of it, which is used for the subdomain.
 func (h Handler) Register(ctx context.Context, req *muxrpc.Request) (interface{}, error) {
 	var args []string
 
@@ -67,24 +72,20 @@ func (h Handler) Register(ctx context.Context, req *muxrpc.Request) (interface{}
 		return nil, fmt.Errorf("registerAlias: expected two arguments got %d", n)
 	}
 
-	if !strings.HasSuffix(args[1], sigSuffix) {
-		return nil, fmt.Errorf("registerAlias: signature does not have the expected suffix")
+	name, confirmation, err := h.unpackConfirmation(args[0], args[1])
+	if err != nil {
+		return nil, fmt.Errorf("registerAlias: %w", err)
 	}
 
-	// remove the suffix of the base64 string
-	sig := strings.TrimSuffix(args[1], sigSuffix)
-
-	var confirmation aliases.Confirmation
-	confirmation.RoomID = h.self
-	confirmation.Alias = args[0]
-	confirmation.Signature, err = base64.StdEncoding.DecodeString(sig)
+	// check alias is valid and allowed by the admins of the room
+	policy, err := h.config.GetAliasPolicy(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("registerAlias: bad signature encoding: %w", err)
+		return nil, fmt.Errorf("registerAlias: could not get alias policy: %w", err)
 	}
 
-	// check alias is valid
-	if !aliases.IsValid(confirmation.Alias) {
-		return nil, fmt.Errorf("registerAlias: invalid alias")
+	err = aliases.CheckPolicy(name, policy)
+	if err != nil {
+		return nil, fmt.Errorf("registerAlias: %w", err)
 	}
 
 	// get the user from the muxrpc connection
@@ -100,16 +101,69 @@ func (h Handler) Register(ctx context.Context, req *muxrpc.Request) (interface{}
 		return nil, fmt.Errorf("registerAlias: invalid signature")
 	}
 
-	err = h.db.Register(ctx, confirmation.Alias, confirmation.UserID, confirmation.Signature)
+	// reject aliases that look like existing ones, like a cyrillic аce for ace
+	existing, err := h.db.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("registerAlias: could not list aliases: %w", err)
+	}
+
+	existingNames := make([]string, len(existing))
+	for i, alias := range existing {
+		existingNames[i] = alias.Name
+	}
+
+	if other, confusable := aliases.ConfusableWith(name, existingNames); confusable {
+		return nil, fmt.Errorf("registerAlias: %w", roomdb.ErrAliasNotAllowed{
+			Name:   name,
+			Reason: fmt.Sprintf("it looks like %q", aliases.ToUnicode(other)),
+		})
+	}
+
+	err = h.db.Register(ctx, name, confirmation.UserID, confirmation.Signature)
 	if err != nil {
 		var takenErr roomdb.ErrAliasTaken
 		if errors.As(err, &takenErr) {
 			return nil, takenErr
 		}
+		if errors.Is(err, roomdb.ErrAliasQuota) {
+			return nil, fmt.Errorf("registerAlias: you already have as many aliases as this room allows")
+		}
 		return nil, fmt.Errorf("registerAlias: could not register alias: %w", err)
 	}
 
-	return h.netInfo.URLForAlias(confirmation.Alias), nil
+	return h.netInfo.URLForAlias(name), nil
+}
+
+// unpackConfirmation normalizes the alias and decodes the signature of its confirmation.
+// It returns the ASCII form of the alias, which is how the roomdb stores it.
+// The UserID of the confirmation still needs to be set before it can be verified.
+func (h Handler) unpackConfirmation(alias, signature string) (string, aliases.Confirmation, error) {
+	var confirmation aliases.Confirmation
+
+	if !strings.HasSuffix(signature, sigSuffix) {
+		return "", confirmation, fmt.Errorf("signature does not have the expected suffix")
+	}
+
+	// remove the suffix of the base64 string
+	sig := strings.TrimSuffix(signature, sigSuffix)
+
+	name, err := aliases.Normalize(alias)
+	if err != nil {
+		return "", confirmation, fmt.Errorf("invalid alias: %w", err)
+	}
+
+	if display := aliases.ToUnicode(name); display != alias {
+		return "", confirmation, fmt.Errorf("the alias needs to be signed and registered as %q", display)
+	}
+
+	confirmation.RoomID = h.self
+	confirmation.Alias = alias
+	confirmation.Signature, err = base64.StdEncoding.DecodeString(sig)
+	if err != nil {
+		return "", confirmation, fmt.Errorf("bad signature encoding: %w", err)
+	}
+
+	return name, confirmation, nil
 }
 
 // Revoke checks that the alias is from that user before revoking the alias from the database.
@@ -131,7 +185,12 @@ func (h Handler) Revoke(ctx context.Context, req *muxrpc.Request) (interface{},
 		return nil, err
 	}
 
-	alias, err := h.db.Resolve(ctx, args[0])
+	name, err := aliases.Normalize(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("revokeAlias: invalid alias: %w", err)
+	}
+
+	alias, err := h.db.Resolve(ctx, name)
 	if err != nil {
 		return nil, err
 	}
@@ -148,6 +207,146 @@ func (h Handler) Revoke(ctx context.Context, req *muxrpc.Request) (interface{},
 	return true, nil
 }
 
+// Transfer checks that the alias is from that user before allowing another member to take it over.
+// It receives two string arguments over muxrpc, the alias and the feed of the new owner.
+// The transfer only happens once the new owner accepts it with a confirmation of their own, see Accept.
+func (h Handler) Transfer(ctx context.Context, req *muxrpc.Request) (interface{}, error) {
+	var args []string
+
+	err := json.Unmarshal(req.RawArgs, &args)
+	if err != nil {
+		return nil, fmt.Errorf("transferAlias: bad request: %w", err)
+	}
+
+	if n := len(args); n != 2 {
+		return nil, fmt.Errorf("transferAlias: expected two arguments got %d", n)
+	}
+
+	newOwner, err := refs.ParseFeedRef(args[1])
+	if err != nil {
+		return nil, fmt.Errorf("transferAlias: invalid feed ref: %w", err)
+	}
+
+	alias, err := h.ownAlias(ctx, req, args[0])
+	if err != nil {
+		return nil, fmt.Errorf("transferAlias: %w", err)
+	}
+
+	if alias.Feed.Equal(newOwner) {
+		return nil, fmt.Errorf("transferAlias: the alias already belongs to that feed")
+	}
+
+	err = h.db.AuthorizeTransfer(ctx, alias.Name, newOwner)
+	if err != nil {
+		if errors.Is(err, roomdb.ErrNotFound) {
+			return nil, fmt.Errorf("transferAlias: %s is not a member of the room", newOwner.String())
+		}
+		return nil, fmt.Errorf("transferAlias: could not authorize transfer: %w", err)
+	}
+
+	return true, nil
+}
+
+// CancelTransfer checks that the alias is from that user before cancelling a pending transfer of it.
+func (h Handler) CancelTransfer(ctx context.Context, req *muxrpc.Request) (interface{}, error) {
+	var args []string
+
+	err := json.Unmarshal(req.RawArgs, &args)
+	if err != nil {
+		return nil, fmt.Errorf("cancelAliasTransfer: bad request: %w", err)
+	}
+
+	if n := len(args); n != 1 {
+		return nil, fmt.Errorf("cancelAliasTransfer: expected one argument got %d", n)
+	}
+
+	alias, err := h.ownAlias(ctx, req, args[0])
+	if err != nil {
+		return nil, fmt.Errorf("cancelAliasTransfer: %w", err)
+	}
+
+	err = h.db.CancelTransfer(ctx, alias.Name)
+	if err != nil {
+		return nil, fmt.Errorf("cancelAliasTransfer: could not cancel transfer: %w", err)
+	}
+
+	return true, nil
+}
+
+// ownAlias resolves the alias and checks that it belongs to the feed of the muxrpc connection
+func (h Handler) ownAlias(ctx context.Context, req *muxrpc.Request, name string) (roomdb.Alias, error) {
+	userID, err := network.GetFeedRefFromAddr(req.RemoteAddr())
+	if err != nil {
+		return roomdb.Alias{}, err
+	}
+
+	name, err = aliases.Normalize(name)
+	if err != nil {
+		return roomdb.Alias{}, fmt.Errorf("invalid alias: %w", err)
+	}
+
+	alias, err := h.db.Resolve(ctx, name)
+	if err != nil {
+		return roomdb.Alias{}, err
+	}
+
+	if !alias.Feed.Equal(userID) {
+		return roomdb.Alias{}, fmt.Errorf("not your alias (moderators need to use the web dashboard of the room")
+	}
+
+	return alias, nil
+}
+
+// Accept is an async muxrpc method handler for taking over an alias that was handed to the feed of the connection.
+// Like Register, it receives the alias and a signature of a fresh confirmation for it by the new owner.
+// If the confirmation is valid, the alias moves over to the new owner and the URL of it is returned.
+func (h Handler) Accept(ctx context.Context, req *muxrpc.Request) (interface{}, error) {
+	var args []string
+
+	err := json.Unmarshal(req.RawArgs, &args)
+	if err != nil {
+		return nil, fmt.Errorf("acceptAlias: bad request: %w", err)
+	}
+
+	if n := len(args); n != 2 {
+		return nil, fmt.Errorf("acceptAlias: expected two arguments got %d", n)
+	}
+
+	name, confirmation, err := h.unpackConfirmation(args[0], args[1])
+	if err != nil {
+		return nil, fmt.Errorf("acceptAlias: %w", err)
+	}
+
+	// get the user from the muxrpc connection
+	userID, err := network.GetFeedRefFromAddr(req.RemoteAddr())
+	if err != nil {
+		return nil, err
+	}
+
+	confirmation.UserID = userID
+
+	// check the signature
+	if !confirmation.Verify() {
+		return nil, fmt.Errorf("acceptAlias: invalid signature")
+	}
+
+	err = h.db.Transfer(ctx, name, confirmation.UserID, confirmation.Signature)
+	if err != nil {
+		if errors.Is(err, roomdb.ErrNotFound) {
+			return nil, fmt.Errorf("acceptAlias: no such alias")
+		}
+		if errors.Is(err, roomdb.ErrAliasTransferNotAuthorized) {
+			return nil, fmt.Errorf("acceptAlias: the alias wasn't handed to you")
+		}
+		if errors.Is(err, roomdb.ErrAliasQuota) {
+			return nil, fmt.Errorf("acceptAlias: you already have as many aliases as this room allows")
+		}
+		return nil, fmt.Errorf("acceptAlias: could not transfer alias: %w", err)
+	}
+
+	return h.netInfo.URLForAlias(name), nil
+}
+
 func (h Handler) List(ctx context.Context, req *muxrpc.Request) (interface{}, error) {
 	var args []string
 
@@ -180,10 +379,11 @@ func (h Handler) List(ctx context.Context, req *muxrpc.Request) (interface{}, er
 	return aliasesToListOfAliasStrings(filteredAliases), nil
 }
 
-func aliasesToListOfAliasStrings(aliases []roomdb.Alias) []string {
+// aliasesToListOfAliasStrings returns the names in their Unicode form, the way they were signed
+func aliasesToListOfAliasStrings(list []roomdb.Alias) []string {
 	result := make([]string, 0)
-	for _, alias := range aliases {
-		result = append(result, alias.Name)
+	for _, alias := range list {
+		result = append(result, aliases.ToUnicode(alias.Name))
 	}
 	return result
 }
@@ -0,0 +1,163 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+// Package invites implements the muxrpc handlers for claiming invites and listing the ones a member created.
+package invites
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ssbc/go-muxrpc/v2"
+	kitlog "go.mindeco.de/log"
+	"go.mindeco.de/log/level"
+
+	"github.com/ssbc/go-ssb-room/v2/internal/network"
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+)
+
+// Handler implements the muxrpc methods for invites
+type Handler struct {
+	logger kitlog.Logger
+
+	invites    roomdb.InvitesService
+	members    roomdb.MembersService
+	deniedKeys roomdb.DeniedKeysService
+
+	netInfo network.ServerEndpointDetails
+}
+
+// New returns a fresh invites muxrpc handler
+func New(log kitlog.Logger, invitesDB roomdb.InvitesService, membersDB roomdb.MembersService, deniedKeysDB roomdb.DeniedKeysService, netInfo network.ServerEndpointDetails) Handler {
+	var h Handler
+	h.logger = log
+	h.invites = invitesDB
+	h.members = membersDB
+	h.deniedKeys = deniedKeysDB
+	h.netInfo = netInfo
+
+	return h
+}
+
+// ConsumeReply is what room.consumeInvite returns after the invite was used
+type ConsumeReply struct {
+	// RoomAddress is the multiserver address of the room, to connect to it as a member
+	RoomAddress string `json:"multiserverAddress"`
+
+	// Role is the role the new member has now (member, moderator or admin)
+	Role string `json:"role"`
+}
+
+// Consume is an async muxrpc method handler for claiming an invite.
+// It receives the token of the invite as the only argument.
+// Unlike the HTTP endpoint, the new member is always the feed of the connection and not something from the arguments.
+func (h Handler) Consume(ctx context.Context, req *muxrpc.Request) (interface{}, error) {
+	var args []string
+
+	err := json.Unmarshal(req.RawArgs, &args)
+	if err != nil {
+		return nil, fmt.Errorf("consumeInvite: bad request: %w", err)
+	}
+
+	if n := len(args); n != 1 {
+		return nil, fmt.Errorf("consumeInvite: expected one argument got %d", n)
+	}
+
+	// get the new member from the muxrpc connection
+	newMember, err := network.GetFeedRefFromAddr(req.RemoteAddr())
+	if err != nil {
+		return nil, err
+	}
+
+	// before consuming the invite: check if the invitee is banned
+	if h.deniedKeys.HasFeed(ctx, newMember) {
+		return nil, fmt.Errorf("consumeInvite: this key has been banned")
+	}
+
+	inv, err := h.invites.Consume(ctx, args[0], newMember)
+	if err != nil {
+		if errors.Is(err, roomdb.ErrNotFound) {
+			return nil, fmt.Errorf("consumeInvite: invite not found")
+		}
+		if errors.Is(err, roomdb.ErrInviteForOtherFeed) {
+			return nil, fmt.Errorf("consumeInvite: %w", err)
+		}
+		return nil, fmt.Errorf("consumeInvite: could not use invite: %w", err)
+	}
+	level.Info(h.logger).Log("event", "invite consumed", "id", inv.ID, "ref", newMember.ShortSigil(), "role", inv.Role)
+
+	return ConsumeReply{
+		RoomAddress: h.netInfo.MultiserverAddress(),
+		Role:        roleName(inv.Role),
+	}, nil
+}
+
+// ListedInvite is one entry of what room.listMyInvites returns
+type ListedInvite struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	Role      string    `json:"role"`
+
+	// ForFeed is the only feed that can use the invite, empty if anyone can
+	ForFeed string `json:"forFeed,omitempty"`
+
+	// Status is one of active, consumed, expired or revoked
+	Status string `json:"status"`
+
+	// UsedBy and UsedAt are only set if the invite was consumed
+	UsedBy string     `json:"usedBy,omitempty"`
+	UsedAt *time.Time `json:"usedAt,omitempty"`
+}
+
+// ListMine is an async muxrpc method handler that returns the invites the calling member created.
+// It takes no arguments, the member is the feed of the connection.
+func (h Handler) ListMine(ctx context.Context, req *muxrpc.Request) (interface{}, error) {
+	ref, err := network.GetFeedRefFromAddr(req.RemoteAddr())
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := h.members.GetByFeed(ctx, ref)
+	if err != nil {
+		if errors.Is(err, roomdb.ErrNotFound) {
+			return nil, fmt.Errorf("listMyInvites: not a member")
+		}
+		return nil, fmt.Errorf("listMyInvites: could not get member: %w", err)
+	}
+
+	created, err := h.invites.ListCreatedBy(ctx, member.ID)
+	if err != nil {
+		return nil, fmt.Errorf("listMyInvites: could not list invites: %w", err)
+	}
+
+	list := make([]ListedInvite, len(created))
+	for i, inv := range created {
+		li := ListedInvite{
+			ID:        inv.ID,
+			CreatedAt: inv.CreatedAt,
+			Role:      roleName(inv.Role),
+			Status:    strings.ToLower(strings.TrimPrefix(inv.Status.String(), "InviteStatus")),
+		}
+		if inv.ForFeed != nil {
+			li.ForFeed = inv.ForFeed.String()
+		}
+		if inv.UsedBy != nil {
+			li.UsedBy = inv.UsedBy.PubKey.String()
+			usedAt := inv.UsedAt
+			li.UsedAt = &usedAt
+		}
+		list[i] = li
+	}
+
+	return list, nil
+}
+
+// roleName turns RoleModerator into moderator
+func roleName(r roomdb.Role) string {
+	return strings.ToLower(strings.TrimPrefix(r.String(), "Role"))
+}
@@ -18,15 +18,19 @@ import (
 
 // AttendantsUpdate is emitted if a single member joins or leaves.
 // Type is either 'joined' or 'left'.
+// Joined is emitted again if the attendant announces new features.
 type AttendantsUpdate struct {
-	Type string       `json:"type"`
-	ID   refs.FeedRef `json:"id"`
+	Type     string       `json:"type"`
+	ID       refs.FeedRef `json:"id"`
+	Features []string     `json:"features,omitempty"`
 }
 
-// AttendantsInitialState is emitted the first time the stream is opened
+// AttendantsInitialState is emitted the first time the stream is opened.
+// Features holds the announced features of the attendants that have any, keyed by their feed reference.
 type AttendantsInitialState struct {
-	Type string         `json:"type"`
-	IDs  []refs.FeedRef `json:"ids"`
+	Type     string              `json:"type"`
+	IDs      []refs.FeedRef      `json:"ids"`
+	Features map[string][]string `json:"features,omitempty"`
 }
 
 func (h *Handler) attendants(ctx context.Context, req *muxrpc.Request, snk *muxrpc.ByteSink) error {
@@ -53,35 +57,80 @@ func (h *Handler) attendants(ctx context.Context, req *muxrpc.Request, snk *muxr
 	h.state.AddEndpoint(peer, req.Endpoint())
 
 	// send the current state
+	attendants := h.state.ListAsRefs()
 	snk.SetEncoding(muxrpc.TypeJSON)
 	err = json.NewEncoder(snk).Encode(AttendantsInitialState{
-		Type: "state",
-		IDs:  h.state.ListAsRefs(),
+		Type:     "state",
+		IDs:      attendants,
+		Features: h.featuresOf(attendants),
 	})
 	if err != nil {
 		return err
 	}
 
 	// register for future updates
-	toPeer := newAttendantsEncoder(snk)
+	toPeer := newAttendantsEncoder(snk, h.state.Features)
 	h.state.RegisterAttendantsUpdates(toPeer)
 
 	return nil
 }
 
+// featuresOf returns the announced features of the passed attendants, skipping those without any
+func (h *Handler) featuresOf(attendants []refs.FeedRef) map[string][]string {
+	features := make(map[string][]string)
+	for _, a := range attendants {
+		if f := h.state.Features(a); len(f) > 0 {
+			features[a.String()] = f
+		}
+	}
+	return features
+}
+
+func (h *Handler) announceFeatures(_ context.Context, req *muxrpc.Request) (interface{}, error) {
+	var args [][]string
+	err := json.Unmarshal(req.RawArgs, &args)
+	if err != nil {
+		return nil, fmt.Errorf("announceFeatures: bad request: %w", err)
+	}
+
+	if n := len(args); n != 1 {
+		return nil, fmt.Errorf("announceFeatures: expected one argument (the list of features) got %d", n)
+	}
+
+	peer, err := network.GetFeedRefFromAddr(req.RemoteAddr())
+	if err != nil {
+		return nil, err
+	}
+
+	// add the peer to the room state if they arent already
+	h.state.AlreadyAdded(peer, req.Endpoint())
+
+	err = h.state.SetFeatures(peer, args[0])
+	if err != nil {
+		return nil, fmt.Errorf("announceFeatures: %w", err)
+	}
+
+	return true, nil
+}
+
 // a muxrpc json encoder for endpoints broadcasts
 type attendantsJSONEncoder struct {
 	mu  sync.Mutex // only one caller to forwarder at a time
 	snk *muxrpc.ByteSink
 	enc *json.Encoder
+
+	// features looks up the current features of an attendant when the update is sent
+	features func(refs.FeedRef) []string
 }
 
-func newAttendantsEncoder(snk *muxrpc.ByteSink) *attendantsJSONEncoder {
+func newAttendantsEncoder(snk *muxrpc.ByteSink, features func(refs.FeedRef) []string) *attendantsJSONEncoder {
 	enc := json.NewEncoder(snk)
 	snk.SetEncoding(muxrpc.TypeJSON)
 	return &attendantsJSONEncoder{
 		snk: snk,
 		enc: enc,
+
+		features: features,
 	}
 }
 
@@ -89,8 +138,9 @@ func (uf *attendantsJSONEncoder) Joined(member refs.FeedRef) error {
 	uf.mu.Lock()
 	defer uf.mu.Unlock()
 	return uf.enc.Encode(AttendantsUpdate{
-		Type: "joined",
-		ID:   member,
+		Type:     "joined",
+		ID:       member,
+		Features: uf.features(member),
 	})
 }
 
@@ -108,3 +158,9 @@ func (uf *attendantsJSONEncoder) Close() error {
 	defer uf.mu.Unlock()
 	return uf.snk.Close()
 }
+
+// CloseWithError is used by the broadcaster to disconnect peers that can't keep up with the updates.
+// It doesn't take the lock since a write that is stuck is likely the reason for closing.
+func (uf *attendantsJSONEncoder) CloseWithError(err error) error {
+	return uf.snk.CloseWithError(err)
+}
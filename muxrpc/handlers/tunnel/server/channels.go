@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ssbc/go-muxrpc/v2"
+	refs "github.com/ssbc/go-ssb-refs"
+
+	"github.com/ssbc/go-ssb-room/v2/internal/network"
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/roomstate"
+)
+
+// channelCaller unpacks the channel name argument and checks that the calling peer is allowed to use channels.
+// In community and restricted mode only members can join and list channels, the same as for room.attendants.
+func (h *Handler) channelCaller(ctx context.Context, req *muxrpc.Request) (refs.FeedRef, string, error) {
+	var args []string
+	err := json.Unmarshal(req.RawArgs, &args)
+	if err != nil {
+		return refs.FeedRef{}, "", fmt.Errorf("bad request: %w", err)
+	}
+
+	if n := len(args); n != 1 {
+		return refs.FeedRef{}, "", fmt.Errorf("expected one argument (the channel name) got %d", n)
+	}
+	name := args[0]
+
+	if !roomstate.IsValidChannelName(name) {
+		return refs.FeedRef{}, "", fmt.Errorf("invalid channel name: %q", name)
+	}
+
+	peer, err := network.GetFeedRefFromAddr(req.RemoteAddr())
+	if err != nil {
+		return refs.FeedRef{}, "", err
+	}
+
+	pm, err := h.config.GetPrivacyMode(ctx)
+	if err != nil {
+		return refs.FeedRef{}, "", fmt.Errorf("running with unknown privacy mode")
+	}
+
+	if pm == roomdb.ModeCommunity || pm == roomdb.ModeRestricted {
+		_, err := h.membersdb.GetByFeed(ctx, peer)
+		if err != nil {
+			return refs.FeedRef{}, "", fmt.Errorf("external users are not allowed to use channels")
+		}
+	}
+
+	return peer, name, nil
+}
+
+func (h *Handler) joinChannel(ctx context.Context, req *muxrpc.Request) (interface{}, error) {
+	peer, name, err := h.channelCaller(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("joinChannel: %w", err)
+	}
+
+	// add the peer to the room state if they arent already
+	h.state.AlreadyAdded(peer, req.Endpoint())
+
+	err = h.state.JoinChannel(name, peer)
+	if err != nil {
+		return nil, fmt.Errorf("joinChannel: %w", err)
+	}
+
+	return true, nil
+}
+
+func (h *Handler) leaveChannel(ctx context.Context, req *muxrpc.Request) (interface{}, error) {
+	peer, name, err := h.channelCaller(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("leaveChannel: %w", err)
+	}
+
+	h.state.LeaveChannel(name, peer)
+
+	return true, nil
+}
+
+// channelAttendants works like room.attendants but only for the attendants of a single channel.
+// The caller needs to join the channel first.
+func (h *Handler) channelAttendants(ctx context.Context, req *muxrpc.Request, snk *muxrpc.ByteSink) error {
+	peer, name, err := h.channelCaller(ctx, req)
+	if err != nil {
+		return fmt.Errorf("channelAttendants: %w", err)
+	}
+
+	// register for future updates, the current state is sent before any of them
+	snk.SetEncoding(muxrpc.TypeJSON)
+	toPeer := newAttendantsEncoder(snk, h.state.Features)
+	err = h.state.RegisterChannelUpdates(name, peer, toPeer, func(attendants []refs.FeedRef) error {
+		return json.NewEncoder(snk).Encode(AttendantsInitialState{
+			Type:     "state",
+			IDs:      attendants,
+			Features: h.featuresOf(attendants),
+		})
+	})
+	if errors.Is(err, roomstate.ErrNotInChannel) {
+		return fmt.Errorf("channelAttendants: need to join channel %q first", name)
+	}
+	return err
+}
@@ -59,8 +59,13 @@ func (h *Handler) RegisterRoom(mux typemux.HandlerMux) {
 	mux.RegisterAsync(append(namespace, "ping"), typemux.AsyncFunc(h.ping))
 
 	mux.RegisterSource(append(namespace, "attendants"), typemux.SourceFunc(h.attendants))
+	mux.RegisterAsync(append(namespace, "announceFeatures"), typemux.AsyncFunc(h.announceFeatures))
 	mux.RegisterSource(append(namespace, "members"), typemux.SourceFunc(h.members))
 
+	mux.RegisterAsync(append(namespace, "joinChannel"), typemux.AsyncFunc(h.joinChannel))
+	mux.RegisterAsync(append(namespace, "leaveChannel"), typemux.AsyncFunc(h.leaveChannel))
+	mux.RegisterSource(append(namespace, "channelAttendants"), typemux.SourceFunc(h.channelAttendants))
+
 	mux.RegisterDuplex(append(namespace, "connect"), connectHandler{
 		logger: h.logger,
 		self:   h.netInfo.RoomID,
@@ -169,3 +169,9 @@ func (uf *endpointsJSONEncoder) Close() error {
 	defer uf.mu.Unlock()
 	return uf.snk.Close()
 }
+
+// CloseWithError is used by the broadcaster to disconnect peers that can't keep up with the updates.
+// It doesn't take the lock since a write that is stuck is likely the reason for closing.
+func (uf *endpointsJSONEncoder) CloseWithError(err error) error {
+	return uf.snk.CloseWithError(err)
+}
@@ -116,6 +116,34 @@ func TestAliasRegister(t *testing.T) {
 	r.True(errors.As(err, &callErr), "expected a call error: %T -- %s", err, err)
 	r.Equal(`alias ("bob") is already taken`, callErr.Message)
 
+	// a reserved name can't be registered
+	registerSigned := func(name string) error {
+		var reg aliases.Registration
+		reg.Alias = name
+		reg.RoomID = session.srv.Whoami()
+		reg.UserID = bobSession.srv.Whoami()
+
+		conf := reg.Sign(bobsKey.Pair.Secret)
+		sig := base64.StdEncoding.EncodeToString(conf.Signature) + ".sig.ed25519"
+
+		var response string
+		return clientForServer.Async(ctx, &response, muxrpc.TypeString, muxrpc.Method{"room", "registerAlias"}, name, sig)
+	}
+
+	err = registerSigned("admin")
+	r.Error(err)
+	r.True(errors.As(err, &callErr), "expected a call error: %T -- %s", err, err)
+	a.Contains(callErr.Message, "is not allowed: it is reserved")
+
+	// neither can more aliases than the room allows
+	err = session.srv.Config.SetAliasPolicy(ctx, roomdb.AliasPolicy{PerMember: 1})
+	r.NoError(err)
+
+	err = registerSigned("bobby")
+	r.Error(err)
+	r.True(errors.As(err, &callErr), "expected a call error: %T -- %s", err, err)
+	a.Contains(callErr.Message, "as many aliases as this room allows")
+
 	for _, bot := range theBots {
 		bot.srv.Shutdown()
 		r.NoError(bot.srv.Close())
@@ -195,3 +223,128 @@ func TestListAliases(t *testing.T) {
 	r.NoError(err)
 	a.Equal("[\"bob\"]", response, "new alias should be in the list")
 }
+
+func TestAliasTransfer(t *testing.T) {
+	testInit(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	r := require.New(t)
+	a := assert.New(t)
+
+	// make a random test key
+	appKey := make([]byte, 32)
+	rand.Read(appKey)
+
+	netOpts := []roomsrv.Option{
+		roomsrv.WithAppKey(appKey),
+		roomsrv.WithContext(ctx),
+	}
+
+	theBots := []*testSession{}
+
+	session := makeNamedTestBot(t, "srv", ctx, netOpts)
+	theBots = append(theBots, session)
+
+	// bob moves to a new identity, which we call carol
+	bobsKey, err := keys.NewKeyPair(nil)
+	r.NoError(err)
+	bobSession := makeNamedTestBot(t, "bob", ctx, append(netOpts,
+		roomsrv.WithKeyPair(bobsKey),
+	))
+	theBots = append(theBots, bobSession)
+
+	carolsKey, err := keys.NewKeyPair(nil)
+	r.NoError(err)
+	carolSession := makeNamedTestBot(t, "carol", ctx, append(netOpts,
+		roomsrv.WithKeyPair(carolsKey),
+	))
+	theBots = append(theBots, carolSession)
+
+	for _, bot := range []*testSession{bobSession, carolSession} {
+		_, err = session.srv.Members.Add(ctx, bot.srv.Whoami(), roomdb.RoleMember)
+		r.NoError(err)
+
+		// allow bots to dial the remote
+		// side-effect of re-using a room-server as the client
+		_, err = bot.srv.Members.Add(ctx, session.srv.Whoami(), roomdb.RoleMember)
+		r.NoError(err)
+
+		err = bot.srv.Network.Connect(ctx, session.srv.Network.GetListenAddr())
+		r.NoError(err, "connect to the Server")
+	}
+
+	t.Log("letting handshaking settle..")
+	time.Sleep(1 * time.Second)
+
+	bobsClient, ok := bobSession.srv.Network.GetEndpointFor(session.srv.Whoami())
+	r.True(ok)
+	carolsClient, ok := carolSession.srv.Network.GetEndpointFor(session.srv.Whoami())
+	r.True(ok)
+
+	signFor := func(pair *keys.KeyPair, user *testSession) ([]byte, string) {
+		var reg aliases.Registration
+		reg.Alias = "bob"
+		reg.RoomID = session.srv.Whoami()
+		reg.UserID = user.srv.Whoami()
+
+		conf := reg.Sign(pair.Pair.Secret)
+		return conf.Signature, base64.StdEncoding.EncodeToString(conf.Signature) + ".sig.ed25519"
+	}
+
+	_, bobsSig := signFor(bobsKey, bobSession)
+
+	var response string
+	err = bobsClient.Async(ctx, &response, muxrpc.TypeString, muxrpc.Method{"room", "registerAlias"}, "bob", bobsSig)
+	r.NoError(err)
+
+	carolsRawSig, carolsSig := signFor(carolsKey, carolSession)
+
+	// carol can't just take it
+	var callErr *muxrpc.CallError
+	err = carolsClient.Async(ctx, &response, muxrpc.TypeString, muxrpc.Method{"room", "acceptAlias"}, "bob", carolsSig)
+	r.Error(err)
+	r.True(errors.As(err, &callErr), "expected a call error: %T -- %s", err, err)
+	a.Contains(callErr.Message, "wasn't handed to you")
+
+	// nor can she hand it to herself
+	err = carolsClient.Async(ctx, &response, muxrpc.TypeString, muxrpc.Method{"room", "transferAlias"}, "bob", carolSession.srv.Whoami().String())
+	r.Error(err)
+	r.True(errors.As(err, &callErr), "expected a call error: %T -- %s", err, err)
+	a.Contains(callErr.Message, "not your alias")
+
+	// bob hands it over
+	var done bool
+	err = bobsClient.Async(ctx, &done, muxrpc.TypeJSON, muxrpc.Method{"room", "transferAlias"}, "bob", carolSession.srv.Whoami().String())
+	r.NoError(err)
+	a.True(done)
+
+	// her confirmation needs to be signed by her
+	err = carolsClient.Async(ctx, &response, muxrpc.TypeString, muxrpc.Method{"room", "acceptAlias"}, "bob", bobsSig)
+	r.Error(err)
+	r.True(errors.As(err, &callErr), "expected a call error: %T -- %s", err, err)
+	a.Contains(callErr.Message, "invalid signature")
+
+	err = carolsClient.Async(ctx, &response, muxrpc.TypeString, muxrpc.Method{"room", "acceptAlias"}, "bob", carolsSig)
+	r.NoError(err)
+
+	resolveURL, err := url.Parse(response)
+	r.NoError(err)
+	a.Equal("bob.srv", resolveURL.Host)
+
+	alias, err := session.srv.Aliases.Resolve(ctx, "bob")
+	r.NoError(err)
+	a.True(alias.Feed.Equal(carolsKey.Feed), "carol owns the alias now")
+	a.Equal(carolsRawSig, alias.Signature)
+	a.Nil(alias.TransferTo)
+
+	// bob can't revoke it anymore
+	err = bobsClient.Async(ctx, &done, muxrpc.TypeJSON, muxrpc.Method{"room", "revokeAlias"}, "bob")
+	r.Error(err)
+
+	for _, bot := range theBots {
+		bot.srv.Shutdown()
+		r.NoError(bot.srv.Close())
+		r.NoError(bot.serveGroup.Wait())
+	}
+	cancel()
+}
@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package go_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ssbc/go-muxrpc/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssbc/go-ssb-room/v2/muxrpc/handlers/tunnel/server"
+)
+
+// peers only see the attendants of the channels they joined
+func TestRoomChannels(t *testing.T) {
+	testInit(t)
+	r := require.New(t)
+	a := assert.New(t)
+
+	testPath := filepath.Join("testrun", t.Name())
+	os.RemoveAll(testPath)
+
+	ctx := context.Background()
+	ctx, cancel := context.WithCancel(ctx)
+	t.Cleanup(cancel)
+
+	// create the roomsrv
+	ts := makeNamedTestBot(t, "server", ctx, nil)
+	ctx = ts.ctx
+
+	alf := ts.makeTestClient("alf")
+	bre := ts.makeTestClient("bre")
+	carl := ts.makeTestClient("carl")
+
+	joinChannel := func(c testClient, name string) {
+		var ok bool
+		err := c.Async(ctx, &ok, muxrpc.TypeJSON, muxrpc.Method{"room", "joinChannel"}, name)
+		r.NoError(err)
+		r.True(ok)
+	}
+
+	// invalid names are rejected
+	var ok bool
+	err := alf.Async(ctx, &ok, muxrpc.TypeJSON, muxrpc.Method{"room", "joinChannel"}, "Not A Channel")
+	r.Error(err)
+
+	joinChannel(alf, "dev")
+	joinChannel(bre, "dev")
+	joinChannel(carl, "local-berlin")
+
+	// alf sees alf and bre, but not carl
+	alfsSource, err := alf.Source(ctx, muxrpc.TypeJSON, muxrpc.Method{"room", "channelAttendants"}, "dev")
+	r.NoError(err)
+
+	a.True(alfsSource.Next(ctx))
+	var initState server.AttendantsInitialState
+	decodeJSONsrc(t, alfsSource, &initState)
+	a.Equal("state", initState.Type)
+	a.Len(initState.IDs, 2)
+	assertListContains(t, initState.IDs, alf.feed)
+	assertListContains(t, initState.IDs, bre.feed)
+
+	announcementsForAlf := make(announcements)
+	go logAttendantsStream(ts, alfsSource, "alf", announcementsForAlf)
+
+	// carl can't list a channel he didn't join
+	carlsSource, err := carl.Source(ctx, muxrpc.TypeJSON, muxrpc.Method{"room", "channelAttendants"}, "dev")
+	r.NoError(err)
+	r.False(carlsSource.Next(ctx), "source should be canceled")
+	r.Error(carlsSource.Err(), "source should have an error")
+
+	// now carl joins and alf sees him
+	joinChannel(carl, "dev")
+	time.Sleep(1 * time.Second) // give some time to process new events
+
+	_, seen := announcementsForAlf[carl.feed.String()]
+	a.True(seen, "alf saw carl join dev")
+
+	// bre leaves the channel
+	err = bre.Async(ctx, &ok, muxrpc.TypeJSON, muxrpc.Method{"room", "leaveChannel"}, "dev")
+	r.NoError(err)
+
+	// carl leaves the room completly
+	carl.Terminate()
+	time.Sleep(1 * time.Second) // give some time to process new events
+
+	// bre should still be in the room but not in the channel anymore
+	_, has := ts.srv.StateManager.Has(bre.feed)
+	a.True(has, "bre should still be in the room")
+	a.False(ts.srv.StateManager.InChannel("dev", bre.feed))
+
+	_, seen = announcementsForAlf[carl.feed.String()]
+	a.False(seen, "carl should be gone for alf")
+
+	a.Len(ts.srv.StateManager.ChannelAttendants("dev"), 1)
+	a.Len(ts.srv.StateManager.ChannelAttendants("local-berlin"), 0)
+
+	// terminate server and the clients
+	ts.srv.Shutdown()
+	alf.Terminate()
+	bre.Terminate()
+	ts.srv.Close()
+
+	// wait for all muxrpc serve()s to exit
+	r.NoError(ts.serveGroup.Wait())
+	cancel()
+}
@@ -135,14 +135,11 @@ func TestConnEstablishmentDenyNonMembersRestrictedRoom(t *testing.T) {
 		ID refs.FeedRef
 	}
 
+	// B is not a member and the server is restricted, so B can only claim an invite
 	endpointB, has := botB.Network.GetEndpointFor(serv.Whoami())
-	r.False(has, "botB has an endpoint for the server!")
-	if endpointB != nil {
-		a.Nil(endpointB, "should not have an endpoint on B (B is not a member, and the server is restricted)")
-		err = endpointB.Async(ctx, &srvWho, muxrpc.TypeJSON, muxrpc.Method{"whoami"})
-		r.Error(err)
-		t.Log(srvWho.ID.String())
-	}
+	r.True(has, "botB has no endpoint for the server")
+	err = endpointB.Async(ctx, &srvWho, muxrpc.TypeJSON, muxrpc.Method{"whoami"})
+	r.Error(err, "non-members shouldn't be able to call whoami")
 
 	endpointA, has := botA.Network.GetEndpointFor(serv.Whoami())
 	r.True(has, "botA has no endpoint for the server")
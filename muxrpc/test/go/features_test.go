@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package go_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ssbc/go-muxrpc/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssbc/go-ssb-room/v2/muxrpc/handlers/tunnel/server"
+)
+
+func TestAttendantFeatures(t *testing.T) {
+	testInit(t)
+	r := require.New(t)
+	a := assert.New(t)
+
+	testPath := filepath.Join("testrun", t.Name())
+	os.RemoveAll(testPath)
+
+	ctx := context.Background()
+	ctx, cancel := context.WithCancel(ctx)
+	t.Cleanup(cancel)
+
+	// create the roomsrv
+	ts := makeNamedTestBot(t, "server", ctx, nil)
+	ctx = ts.ctx
+
+	alf := ts.makeTestClient("alf")
+	bre := ts.makeTestClient("bre")
+
+	// alf follows the attendants
+	alfsSource, err := alf.Source(ctx, muxrpc.TypeJSON, muxrpc.Method{"room", "attendants"})
+	r.NoError(err)
+
+	a.True(alfsSource.Next(ctx))
+	var initState server.AttendantsInitialState
+	decodeJSONsrc(t, alfsSource, &initState)
+	a.Equal("state", initState.Type)
+	a.Len(initState.Features, 0)
+
+	announce := func(c testClient, features []string) error {
+		var ok bool
+		return c.Async(ctx, &ok, muxrpc.TypeJSON, muxrpc.Method{"room", "announceFeatures"}, features)
+	}
+
+	// invalid features are rejected
+	r.Error(announce(bre, []string{"Not Valid"}))
+	r.Error(announce(bre, []string{strings.Repeat("a", 33)}))
+	tooMany := make([]string, 17)
+	for i := range tooMany {
+		tooMany[i] = strings.Repeat("x", i+1)
+	}
+	r.Error(announce(bre, tooMany))
+
+	// now a valid one, duplicates are dropped
+	r.NoError(announce(bre, []string{"metafeeds", "ebt", "ebt", "app:patchwork"}))
+
+	// bre joined the room through the first announcement.
+	// depending on how fast alf reads, that one can be merged with the update for the features
+	var update server.AttendantsUpdate
+	for i := 0; i < 2 && len(update.Features) == 0; i++ {
+		a.True(alfsSource.Next(ctx))
+		decodeJSONsrc(t, alfsSource, &update)
+		a.Equal("joined", update.Type)
+		a.True(update.ID.Equal(bre.feed))
+	}
+	a.Equal([]string{"app:patchwork", "ebt", "metafeeds"}, update.Features)
+
+	// the initial state of a new stream contains them, too
+	bresSource, err := bre.Source(ctx, muxrpc.TypeJSON, muxrpc.Method{"room", "attendants"})
+	r.NoError(err)
+
+	a.True(bresSource.Next(ctx))
+	decodeJSONsrc(t, bresSource, &initState)
+	a.Equal("state", initState.Type)
+	a.Len(initState.IDs, 2)
+	a.Equal([]string{"app:patchwork", "ebt", "metafeeds"}, initState.Features[bre.feed.String()])
+	_, has := initState.Features[alf.feed.String()]
+	a.False(has, "alf didn't announce anything")
+
+	// terminate server and the clients
+	ts.srv.Shutdown()
+	alf.Terminate()
+	bre.Terminate()
+	ts.srv.Close()
+
+	// wait for all muxrpc serve()s to exit
+	r.NoError(ts.serveGroup.Wait())
+	cancel()
+}
@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package go_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ssbc/go-muxrpc/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssbc/go-ssb-room/v2/roomsrv"
+)
+
+// attendants that don't reply to the heartbeat probes are evicted from the room
+func TestHeartbeatEvictsUnresponsive(t *testing.T) {
+	testInit(t)
+	r := require.New(t)
+	a := assert.New(t)
+
+	testPath := filepath.Join("testrun", t.Name())
+	os.RemoveAll(testPath)
+
+	ctx := context.Background()
+	ctx, cancel := context.WithCancel(ctx)
+	t.Cleanup(cancel)
+
+	// create the roomsrv with a fast heartbeat
+	opts := []roomsrv.Option{
+		roomsrv.WithHeartbeat(250*time.Millisecond, 2),
+	}
+	ts := makeNamedTestBot(t, "server", ctx, opts)
+	ctx = ts.ctx
+
+	isProbe := func(m muxrpc.Method) bool { return m.String() == "whoami" }
+
+	// alf replies to the probes
+	alf := ts.makeTestClient("alf")
+	alf.mockedHandler.HandledCalls(isProbe)
+	alf.mockedHandler.HandleCallCalls(func(ctx context.Context, req *muxrpc.Request) {
+		if isProbe(req.Method) {
+			req.Return(ctx, map[string]string{"id": alf.feed.String()})
+		}
+	})
+
+	// bre accepts the probes but never replies, like a half-open connection would
+	bre := ts.makeTestClient("bre")
+	bre.mockedHandler.HandledCalls(isProbe)
+
+	for _, c := range []testClient{alf, bre} {
+		var ok bool
+		err := c.Async(ctx, &ok, muxrpc.TypeJSON, muxrpc.Method{"tunnel", "announce"})
+		r.NoError(err)
+		r.True(ok)
+	}
+
+	time.Sleep(2 * time.Second) // a couple of heartbeats
+
+	_, has := ts.srv.StateManager.Has(alf.feed)
+	a.True(has, "alf should still be in the room")
+
+	_, has = ts.srv.StateManager.Has(bre.feed)
+	a.False(has, "bre should have been evicted")
+
+	_, hasLatency := ts.srv.StateManager.Latency(alf.feed)
+	a.True(hasLatency, "should have measured alf's latency")
+
+	_, hasLatency = ts.srv.StateManager.Latency(bre.feed)
+	a.False(hasLatency, "bre never replied")
+
+	// shut everything down
+	ts.srv.Shutdown()
+	alf.Terminate()
+	bre.Terminate()
+	ts.srv.Close()
+
+	// bre's connection was terminated by the server, which might surface as an error here
+	if err := ts.serveGroup.Wait(); err != nil {
+		t.Log("serve group:", err)
+	}
+	cancel()
+}
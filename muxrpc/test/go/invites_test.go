@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package go_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ssbc/go-muxrpc/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssbc/go-ssb-room/v2/internal/maybemod/keys"
+	"github.com/ssbc/go-ssb-room/v2/muxrpc/handlers/invites"
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+)
+
+// bob isn't a member of the restricted room but can still claim an invite over muxrpc
+func TestInviteConsume(t *testing.T) {
+	testInit(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := require.New(t)
+	a := assert.New(t)
+
+	theBots := createServerAndBots(t, ctx, 1)
+	serv := theBots[0].srv
+	bob := theBots[1].srv
+
+	adminKey, err := keys.NewKeyPair(nil)
+	r.NoError(err)
+	adminID, err := serv.Members.Add(ctx, adminKey.Feed, roomdb.RoleAdmin)
+	r.NoError(err)
+
+	token, err := serv.Invites.Create(ctx, adminID, roomdb.InviteOptions{Role: roomdb.RoleModerator})
+	r.NoError(err)
+
+	// allow bob to dial the server
+	_, err = bob.Members.Add(ctx, serv.Whoami(), roomdb.RoleMember)
+	r.NoError(err)
+
+	err = bob.Network.Connect(ctx, serv.Network.GetListenAddr())
+	r.NoError(err)
+
+	t.Log("letting handshaking settle..")
+	time.Sleep(1 * time.Second)
+
+	endpoint, has := bob.Network.GetEndpointFor(serv.Whoami())
+	r.True(has, "bob has no endpoint for the server")
+
+	var reply invites.ConsumeReply
+	err = endpoint.Async(ctx, &reply, muxrpc.TypeJSON, muxrpc.Method{"room", "consumeInvite"}, token)
+	r.NoError(err)
+	a.True(strings.HasPrefix(reply.RoomAddress, "net:srv:"), "wrong address: %s", reply.RoomAddress)
+	a.Equal("moderator", reply.Role)
+
+	member, err := serv.Members.GetByFeed(ctx, bob.Whoami())
+	r.NoError(err)
+	a.Equal(roomdb.RoleModerator, member.Role)
+
+	// the invite can only be used once
+	err = endpoint.Async(ctx, &reply, muxrpc.TypeJSON, muxrpc.Method{"room", "consumeInvite"}, token)
+	r.Error(err)
+}
+
+// members can list the invites they created, including who used them
+func TestInviteListMine(t *testing.T) {
+	testInit(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := require.New(t)
+	a := assert.New(t)
+
+	theBots := createServerAndBots(t, ctx, 2)
+	serv := theBots[0].srv
+	bob := theBots[1].srv
+	carla := theBots[2].srv
+
+	bobID, err := serv.Members.Add(ctx, bob.Whoami(), roomdb.RoleMember)
+	r.NoError(err)
+
+	token, err := serv.Invites.Create(ctx, bobID, roomdb.InviteOptions{})
+	r.NoError(err)
+	_, err = serv.Invites.Create(ctx, bobID, roomdb.InviteOptions{})
+	r.NoError(err)
+
+	_, err = serv.Invites.Consume(ctx, token, carla.Whoami())
+	r.NoError(err)
+
+	// allow bob to dial the server
+	_, err = bob.Members.Add(ctx, serv.Whoami(), roomdb.RoleMember)
+	r.NoError(err)
+
+	err = bob.Network.Connect(ctx, serv.Network.GetListenAddr())
+	r.NoError(err)
+
+	t.Log("letting handshaking settle..")
+	time.Sleep(1 * time.Second)
+
+	endpoint, has := bob.Network.GetEndpointFor(serv.Whoami())
+	r.True(has, "bob has no endpoint for the server")
+
+	var list []invites.ListedInvite
+	err = endpoint.Async(ctx, &list, muxrpc.TypeJSON, muxrpc.Method{"room", "listMyInvites"})
+	r.NoError(err)
+	r.Len(list, 2)
+
+	var consumed, active int
+	for _, inv := range list {
+		a.Equal("member", inv.Role)
+		switch inv.Status {
+		case "consumed":
+			consumed++
+			a.Equal(carla.Whoami().String(), inv.UsedBy)
+			a.NotNil(inv.UsedAt)
+		case "active":
+			active++
+			a.Equal("", inv.UsedBy)
+		default:
+			t.Errorf("unexpected status: %s", inv.Status)
+		}
+	}
+	a.Equal(1, consumed)
+	a.Equal(1, active)
+}
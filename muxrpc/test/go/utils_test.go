@@ -125,7 +125,7 @@ func makeNamedTestBot(t testing.TB, name string, ctx context.Context, opts []roo
 	}
 
 	sb := signinwithssb.NewSignalBridge()
-	theBot, err := roomsrv.New(db.Members, db.DeniedKeys, db.Aliases, db.AuthWithSSB, sb, db.Config, netInfo, botOptions...)
+	theBot, err := roomsrv.New(db.Members, db.DeniedKeys, db.Aliases, db.Invites, db.AuthWithSSB, sb, db.Config, netInfo, botOptions...)
 	r.NoError(err)
 
 	ts := testSession{
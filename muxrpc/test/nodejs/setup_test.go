@@ -132,8 +132,9 @@ func (ts *testSession) startGoServer(
 
 	fakeConfig := new(mockdb.FakeRoomConfig)
 	deniedKeysDB := new(mockdb.FakeDeniedKeysService)
+	invitesDB := new(mockdb.FakeInvitesService)
 
-	srv, err := roomsrv.New(membersDB, deniedKeysDB, aliasDB, authSessionsDB, sb, fakeConfig, netInfo, opts...)
+	srv, err := roomsrv.New(membersDB, deniedKeysDB, aliasDB, invitesDB, authSessionsDB, sb, fakeConfig, netInfo, opts...)
 	r.NoError(err, "failed to init tees a server")
 	ts.t.Logf("go server: %s", srv.Whoami().String())
 	ts.t.Cleanup(func() {
@@ -14,6 +14,7 @@ package roomdb
 
 import (
 	"context"
+	"time"
 
 	refs "github.com/ssbc/go-ssb-refs"
 	"go.mindeco.de/http/auth"
@@ -25,6 +26,23 @@ type RoomConfig interface {
 	SetPrivacyMode(context.Context, PrivacyMode) error
 	GetDefaultLanguage(context.Context) (string, error)
 	SetDefaultLanguage(context.Context, string) error
+
+	// GetTOTPMandatory tells if admins and moderators need a second factor to sign in with their fallback password
+	GetTOTPMandatory(context.Context) (bool, error)
+	SetTOTPMandatory(context.Context, bool) error
+
+	// GetOpenInviteLimits returns what protects the creation of invites while the room is open
+	GetOpenInviteLimits(context.Context) (OpenInviteLimits, error)
+	SetOpenInviteLimits(context.Context, OpenInviteLimits) error
+
+	// GetMemberInviteQuota returns how many unused invites each member can have, 0 means no limit.
+	// It doesn't apply to moderators and admins.
+	GetMemberInviteQuota(context.Context) (uint, error)
+	SetMemberInviteQuota(context.Context, uint) error
+
+	// GetAliasPolicy returns which aliases can be registered and how many each member can have
+	GetAliasPolicy(context.Context) (AliasPolicy, error)
+	SetAliasPolicy(context.Context, AliasPolicy) error
 }
 
 // AuthFallbackService allows password authentication which might be helpful for scenarios
@@ -47,6 +65,86 @@ type AuthFallbackService interface {
 	SetPasswordWithToken(_ context.Context, resetToken string, password string) error
 }
 
+// LoginLockoutService protects the fallback password sign-in against guessing.
+// Failed sign-ins are counted per login (alias or feed) and per IP address.
+// After a number of failures, the login or address is locked out for a time that doubles with every further failure.
+//counterfeiter:generate . LoginLockoutService
+type LoginLockoutService interface {
+	// Check returns ErrLockedOut if the login or the address are locked out right now.
+	// The address can be empty if it isn't known, then it is counted together with the other unknown ones.
+	Check(ctx context.Context, login, address string) error
+
+	// Failed counts a failed sign-in for the login and the address.
+	// It returns the lockouts that started because of it, if any.
+	Failed(ctx context.Context, login, address string) ([]LoginLockout, error)
+
+	// Succeeded forgets the failures of the login.
+	// Those of the address are kept, since it might be trying out different logins.
+	Succeeded(ctx context.Context, login string) error
+
+	// List returns the logins and addresses with recent failures, those that are locked out first.
+	List(ctx context.Context) ([]LoginLockout, error)
+
+	// Clear forgets the failures and ends the lockout with that id.
+	// It returns ErrNotFound if there is none.
+	Clear(ctx context.Context, id int64) error
+}
+
+// APITokensService stores the personal api tokens of admins and moderators.
+// Like invites, only a hash of the tokens is stored.
+//counterfeiter:generate . APITokensService
+type APITokensService interface {
+	// Create stores a new token of the member and returns it, base64 URL encoded.
+	// All the scopes need to be valid and expiresAt needs to be in the future.
+	Create(ctx context.Context, memberID int64, name string, scopes []APITokenScope, expiresAt time.Time) (string, error)
+
+	// CheckToken returns the token if it exists and didn't expire yet. It also updates when it was used last.
+	// It returns ErrNotFound otherwise.
+	CheckToken(ctx context.Context, token string) (APIToken, error)
+
+	// List returns the tokens of a member, the newest first. Expired ones are included.
+	List(ctx context.Context, memberID int64) ([]APIToken, error)
+
+	// Revoke deletes the token with that id, if it belongs to the member.
+	// It returns ErrNotFound otherwise.
+	Revoke(ctx context.Context, memberID, id int64) error
+
+	// RevokeAll deletes all the tokens of the member, for instance when it's suspended.
+	RevokeAll(ctx context.Context, memberID int64) error
+}
+
+// OIDCClientsService stores the apps that can use the room as their OpenID Connect identity provider,
+// together with the authorization codes that were handed out to them.
+// Like invites, only hashes of the client secrets and the codes are stored.
+//counterfeiter:generate . OIDCClientsService
+type OIDCClientsService interface {
+	// Register adds a new client and returns it, together with its secret which can't be retrieved again.
+	// There needs to be at least one redirect URI.
+	Register(ctx context.Context, name string, redirectURIs []string) (OIDCClient, string, error)
+
+	// GetByClientID returns the client with that public client_id or ErrNotFound.
+	GetByClientID(ctx context.Context, clientID string) (OIDCClient, error)
+
+	// CheckSecret returns the client if the secret belongs to it and ErrNotFound otherwise.
+	CheckSecret(ctx context.Context, clientID, secret string) (OIDCClient, error)
+
+	// GetByID returns the client with that ID or ErrNotFound.
+	GetByID(ctx context.Context, id int64) (OIDCClient, error)
+
+	// List returns all the registered clients.
+	List(ctx context.Context) ([]OIDCClient, error)
+
+	// RemoveID removes the client and all its pending authorization codes.
+	RemoveID(ctx context.Context, id int64) error
+
+	// CreateCode stores the authorization and returns the code for it.
+	CreateCode(ctx context.Context, auth OIDCAuthCode) (string, error)
+
+	// ConsumeCode returns the authorization of the code and deletes it, so that each code can only be used once.
+	// It returns ErrNotFound if the code doesn't exist or expired.
+	ConsumeCode(ctx context.Context, code string) (OIDCAuthCode, error)
+}
+
 // AuthWithSSBService defines utility functions for the challenge/response system of sign-in with ssb
 // They are particualarly of service to check valid sessions (after the client provided a solution for a challenge)
 // And to log out valid sessions from the clients device.
@@ -60,11 +158,99 @@ type AuthWithSSBService interface {
 	// CheckToken checks if the passed token is still valid and returns the member id if so
 	CheckToken(ctx context.Context, token string) (int64, error)
 
+	// CheckDeviceToken is like CheckToken but only accepts the sessions that were marked with MarkDevice.
+	// It returns ErrNotFound for all other sessions.
+	CheckDeviceToken(ctx context.Context, token string) (int64, error)
+
+	// MarkDevice records that the session was handed to a device by the device sign-in,
+	// which lets it be used as a bearer token for the JSON API.
+	MarkDevice(ctx context.Context, token string) error
+
 	// RemoveToken removes a single token from the database
 	RemoveToken(ctx context.Context, token string) error
 
 	// WipeTokensForMember deletes all tokens currently held for that member
 	WipeTokensForMember(ctx context.Context, memberID int64) error
+
+	// SetClientDetails records the user agent and address of the browser that holds the token
+	SetClientDetails(ctx context.Context, token, userAgent, remoteAddr string) error
+
+	// ListSessions returns the sessions of a member, the most recently used first
+	ListSessions(ctx context.Context, memberID int64) ([]SIWSSBSession, error)
+
+	// RemoveSession ends a single session of a member.
+	// It returns ErrNotFound if the member doesn't have a session with that id.
+	RemoveSession(ctx context.Context, memberID, sessionID int64) error
+}
+
+// WebSessionsService stores the sign-in sessions of the web dashboard, so that they can be ended from the server.
+// The browser only holds the ID of its session, the room only stores a hash of it.
+//counterfeiter:generate . WebSessionsService
+type WebSessionsService interface {
+	// Create stores a new session and returns its ID.
+	// memberID is zero if nobody signed in with the session yet.
+	Create(ctx context.Context, memberID int64, data []byte, expiresAt, idleExpiresAt time.Time) (string, error)
+
+	// Get returns the session with that ID.
+	// It returns ErrNotFound if it doesn't exist or one of its expiry times passed.
+	Get(ctx context.Context, id string) (WebSession, error)
+
+	// Update replaces the member and the data of the session and marks it as used.
+	Update(ctx context.Context, id string, memberID int64, data []byte, idleExpiresAt time.Time) error
+
+	// Touch marks the session as used, which moves the time it expires without being used.
+	Touch(ctx context.Context, id string, idleExpiresAt time.Time) error
+
+	// Remove ends a single session
+	Remove(ctx context.Context, id string) error
+
+	// RemoveForMember ends all the sessions of a member, to log them out everywhere.
+	RemoveForMember(ctx context.Context, memberID int64) error
+}
+
+// TOTPService stores the time-based one-time password (TOTP) secrets and recovery codes
+// that members use as a second factor for the fallback password sign-in.
+//counterfeiter:generate . TOTPService
+type TOTPService interface {
+	// Enroll stores a new, unconfirmed secret for the member, replacing a previous unconfirmed one.
+	// It fails if the member already confirmed a secret.
+	Enroll(ctx context.Context, memberID int64, secret string) error
+
+	// Confirm checks the code against the unconfirmed secret of the member and enables it.
+	// It returns a fresh set of single-use recovery codes. Only their hashes are stored.
+	Confirm(ctx context.Context, memberID int64, code string) ([]string, error)
+
+	// Verify checks a code from the authenticator app or one of the recovery codes of the member.
+	// Codes can't be used twice. It returns ErrInvalidTOTPCode if the code doesn't match.
+	Verify(ctx context.Context, memberID int64, code string) error
+
+	// Status returns if the member enabled the second factor and how many recovery codes are left
+	Status(ctx context.Context, memberID int64) (TOTPStatus, error)
+
+	// Disable removes the secret and the recovery codes of the member
+	Disable(ctx context.Context, memberID int64) error
+}
+
+// WebAuthnService stores the WebAuthn credentials (passkeys) that members can use to sign into the dashboard,
+// as an alternative to sign-in with ssb and the fallback password.
+//counterfeiter:generate . WebAuthnService
+type WebAuthnService interface {
+	// AddCredential stores a new credential for the member and returns its id.
+	AddCredential(ctx context.Context, memberID int64, cred WebAuthnCredential) (int64, error)
+
+	// GetCredential returns a single credential of a member.
+	// It returns ErrNotFound if the member doesn't have a credential with that id.
+	GetCredential(ctx context.Context, memberID, id int64) (WebAuthnCredential, error)
+
+	// ListCredentials returns all the credentials of a member, in the order they were added
+	ListCredentials(ctx context.Context, memberID int64) ([]WebAuthnCredential, error)
+
+	// MarkUsed stores the new signature counter of the credential after a successful sign-in and updates its last use
+	MarkUsed(ctx context.Context, id int64, signCount uint32) error
+
+	// RemoveCredential deletes a single credential of a member.
+	// It returns ErrNotFound if the member doesn't have a credential with that id.
+	RemoveCredential(ctx context.Context, memberID, id int64) error
 }
 
 // MembersService stores and retreives the list of internal users (members, mods and admins).
@@ -138,24 +324,50 @@ type AliasesService interface {
 	// List returns a list of all registerd aliases
 	List(ctx context.Context) ([]Alias, error)
 
-	// Register receives an alias and signature for it. Validation needs to happen before this.
+	// Register receives an alias and signature for it. Validation needs to happen before this,
+	// except for the PerMember limit of the AliasPolicy, which returns ErrAliasQuota.
 	Register(ctx context.Context, alias string, userFeed refs.FeedRef, signature []byte) error
 
 	// Revoke removes an alias from the system
 	Revoke(ctx context.Context, alias string) error
+
+	// AuthorizeTransfer allows the feed to to take over the alias, replacing an earlier authorization.
+	// Checking that the caller is the owner of the alias or a moderator needs to happen before this.
+	// It returns ErrNotFound if the alias doesn't exist or to isn't a member.
+	AuthorizeTransfer(ctx context.Context, alias string, to refs.FeedRef) error
+
+	// CancelTransfer removes a pending transfer of the alias, if there is one.
+	CancelTransfer(ctx context.Context, alias string) error
+
+	// Transfer hands the alias over to newOwner, with the signature of its new confirmation, in one step.
+	// Validation of the signature needs to happen before this. It returns ErrAliasTransferNotAuthorized
+	// if the transfer wasn't authorized for newOwner and ErrAliasQuota if newOwner already has as many
+	// aliases as the AliasPolicy allows them.
+	Transfer(ctx context.Context, alias string, newOwner refs.FeedRef, signature []byte) error
 }
 
 // InvitesService manages creation and consumption of invite tokens for joining the room.
 //counterfeiter:generate . InvitesService
 type InvitesService interface {
 	// Create creates a new invite for a new member. It returns the token or an error.
-	// createdBy is user ID of the admin or moderator who created it. MemberID -1 is allowed if Privacy Mode is set to Open.
-	// aliasSuggestion is optional (empty string is fine) but can be used to disambiguate open invites. (See https://github.com/ssbc/rooms2/issues/21)
-	Create(ctx context.Context, createdBy int64) (string, error)
+	// createdBy is user ID of the member who created it. MemberID -1 is allowed if Privacy Mode is set to Open.
+	// opts can set the role of the new member and the feed that can use the invite, the zero value is a plain invite for anyone.
+	// It returns ErrInviteQuota if createdBy is a member that already has as many unused invites as the MemberInviteQuota allows.
+	Create(ctx context.Context, createdBy int64, opts InviteOptions) (string, error)
+
+	// CreateOpenMode creates a plain invite for anyone, while Privacy Mode is set to Open.
+	// address is where the request came from, or empty if it isn't known. It returns ErrOpenInviteLimit
+	// if the address already created as many invites in the last day as the OpenInviteLimits allow.
+	// All the requests without an address count against the same limit.
+	CreateOpenMode(ctx context.Context, address string) (string, error)
+
+	// CountOpenMode returns how many invites were created in open mode since then
+	CountOpenMode(ctx context.Context, since time.Time) (uint, error)
 
 	// Consume checks if the passed token is still valid.
-	// If it is it adds newMember to the members of the room and invalidates the token.
-	// If the token isn't valid, it returns an error.
+	// If it is it adds newMember to the members of the room, with the role of the invite, and invalidates the token.
+	// The role is capped at the current role of the creator. Existing members keep their role.
+	// If the token isn't valid, it returns an error. If it is for another feed, it returns ErrInviteForOtherFeed.
 	Consume(ctx context.Context, token string, newMember refs.FeedRef) (Invite, error)
 
 	// GetByToken returns the Invite if one for that token exists, or an error
@@ -167,11 +379,62 @@ type InvitesService interface {
 	// List returns a list of all the valid invites
 	List(ctx context.Context) ([]Invite, error)
 
+	// ListCreatedBy returns the invites the member created, including those that were used, revoked or expired, the newest first.
+	ListCreatedBy(ctx context.Context, memberID int64) ([]CreatedInvite, error)
+
 	// Count returns the total number of invites, optionally excluding inactive invites
 	Count(ctx context.Context, onlyActive bool) (uint, error)
 
 	// Revoke removes a active invite and invalidates it for future use.
 	Revoke(ctx context.Context, id int64) error
+
+	// InvitedBy returns how the member joined the room.
+	// It returns ErrNotFound if they didn't use an invite, or joined before those were recorded.
+	InvitedBy(ctx context.Context, memberID int64) (InviteUse, error)
+
+	// ListInvitedBy returns the uses of the invites that the member created, oldest first.
+	// If recursive is true, it also returns the uses of the invites that those members created and so on,
+	// which is everyone that came into the room through the member.
+	ListInvitedBy(ctx context.Context, memberID int64, recursive bool) ([]InviteUse, error)
+
+	// CreateBatch creates count plain invites, as if createdBy created each of them, that can't be used after expiresAt.
+	// It returns the batch and the tokens of its invites.
+	CreateBatch(ctx context.Context, createdBy int64, name string, count uint, expiresAt time.Time) (InviteBatch, []string, error)
+
+	// ListBatches returns the batches that still have invites which can be used, the newest first.
+	ListBatches(ctx context.Context) ([]InviteBatch, error)
+
+	// GetBatch returns the batch with that id, or ErrNotFound
+	GetBatch(ctx context.Context, id int64) (InviteBatch, error)
+
+	// RevokeBatch invalidates all the unused invites of the batch. It returns ErrNotFound if there is no such batch.
+	RevokeBatch(ctx context.Context, id int64) error
+}
+
+// InviteRequestsService keeps the requests of people that want to join the room, until a moderator approves or rejects them.
+//counterfeiter:generate . InviteRequestsService
+type InviteRequestsService interface {
+	// Create stores a pending request and returns a token, base64 URL encoded, that the requester can check on it with.
+	// address is where the request came from. It returns ErrInviteRequestLimit if it made too many requests recently
+	// or if there are too many pending ones, overall or from the address, and ErrAlreadyAdded if there is a pending request for the feed already.
+	// Requests without a valid address are all counted as coming from the same one.
+	Create(ctx context.Context, feed refs.FeedRef, message, address string) (string, error)
+
+	// GetByToken returns the request for the token, or ErrNotFound
+	GetByToken(ctx context.Context, token string) (InviteRequest, error)
+
+	// GetByID returns the request with that id, or ErrNotFound
+	GetByID(ctx context.Context, id int64) (InviteRequest, error)
+
+	// List returns the pending requests, the oldest first.
+	List(ctx context.Context) ([]InviteRequest, error)
+
+	// Approve creates an invite for the feed of the pending request, as if decidedBy created it, and returns its token.
+	// The token is also kept with the request, so that the requester can pick it up. It returns ErrNotFound if there is no such pending request.
+	Approve(ctx context.Context, id, decidedBy int64) (string, error)
+
+	// Reject marks the pending request as rejected. It returns ErrNotFound if there is no such pending request.
+	Reject(ctx context.Context, id int64) error
 }
 
 // PinnedNoticesService allows an admin to assign Notices to specific placeholder pages.
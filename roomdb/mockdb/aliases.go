@@ -14,6 +14,31 @@ import (
 )
 
 type FakeAliasesService struct {
+	AuthorizeTransferStub        func(context.Context, string, refs.FeedRef) error
+	authorizeTransferMutex       sync.RWMutex
+	authorizeTransferArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 refs.FeedRef
+	}
+	authorizeTransferReturns struct {
+		result1 error
+	}
+	authorizeTransferReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CancelTransferStub        func(context.Context, string) error
+	cancelTransferMutex       sync.RWMutex
+	cancelTransferArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	cancelTransferReturns struct {
+		result1 error
+	}
+	cancelTransferReturnsOnCall map[int]struct {
+		result1 error
+	}
 	GetByIDStub        func(context.Context, int64) (roomdb.Alias, error)
 	getByIDMutex       sync.RWMutex
 	getByIDArgsForCall []struct {
@@ -81,10 +106,149 @@ type FakeAliasesService struct {
 	revokeReturnsOnCall map[int]struct {
 		result1 error
 	}
+	TransferStub        func(context.Context, string, refs.FeedRef, []byte) error
+	transferMutex       sync.RWMutex
+	transferArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 refs.FeedRef
+		arg4 []byte
+	}
+	transferReturns struct {
+		result1 error
+	}
+	transferReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeAliasesService) AuthorizeTransfer(arg1 context.Context, arg2 string, arg3 refs.FeedRef) error {
+	fake.authorizeTransferMutex.Lock()
+	ret, specificReturn := fake.authorizeTransferReturnsOnCall[len(fake.authorizeTransferArgsForCall)]
+	fake.authorizeTransferArgsForCall = append(fake.authorizeTransferArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 refs.FeedRef
+	}{arg1, arg2, arg3})
+	stub := fake.AuthorizeTransferStub
+	fakeReturns := fake.authorizeTransferReturns
+	fake.recordInvocation("AuthorizeTransfer", []interface{}{arg1, arg2, arg3})
+	fake.authorizeTransferMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeAliasesService) AuthorizeTransferCallCount() int {
+	fake.authorizeTransferMutex.RLock()
+	defer fake.authorizeTransferMutex.RUnlock()
+	return len(fake.authorizeTransferArgsForCall)
+}
+
+func (fake *FakeAliasesService) AuthorizeTransferCalls(stub func(context.Context, string, refs.FeedRef) error) {
+	fake.authorizeTransferMutex.Lock()
+	defer fake.authorizeTransferMutex.Unlock()
+	fake.AuthorizeTransferStub = stub
+}
+
+func (fake *FakeAliasesService) AuthorizeTransferArgsForCall(i int) (context.Context, string, refs.FeedRef) {
+	fake.authorizeTransferMutex.RLock()
+	defer fake.authorizeTransferMutex.RUnlock()
+	argsForCall := fake.authorizeTransferArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeAliasesService) AuthorizeTransferReturns(result1 error) {
+	fake.authorizeTransferMutex.Lock()
+	defer fake.authorizeTransferMutex.Unlock()
+	fake.AuthorizeTransferStub = nil
+	fake.authorizeTransferReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAliasesService) AuthorizeTransferReturnsOnCall(i int, result1 error) {
+	fake.authorizeTransferMutex.Lock()
+	defer fake.authorizeTransferMutex.Unlock()
+	fake.AuthorizeTransferStub = nil
+	if fake.authorizeTransferReturnsOnCall == nil {
+		fake.authorizeTransferReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.authorizeTransferReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAliasesService) CancelTransfer(arg1 context.Context, arg2 string) error {
+	fake.cancelTransferMutex.Lock()
+	ret, specificReturn := fake.cancelTransferReturnsOnCall[len(fake.cancelTransferArgsForCall)]
+	fake.cancelTransferArgsForCall = append(fake.cancelTransferArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.CancelTransferStub
+	fakeReturns := fake.cancelTransferReturns
+	fake.recordInvocation("CancelTransfer", []interface{}{arg1, arg2})
+	fake.cancelTransferMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeAliasesService) CancelTransferCallCount() int {
+	fake.cancelTransferMutex.RLock()
+	defer fake.cancelTransferMutex.RUnlock()
+	return len(fake.cancelTransferArgsForCall)
+}
+
+func (fake *FakeAliasesService) CancelTransferCalls(stub func(context.Context, string) error) {
+	fake.cancelTransferMutex.Lock()
+	defer fake.cancelTransferMutex.Unlock()
+	fake.CancelTransferStub = stub
+}
+
+func (fake *FakeAliasesService) CancelTransferArgsForCall(i int) (context.Context, string) {
+	fake.cancelTransferMutex.RLock()
+	defer fake.cancelTransferMutex.RUnlock()
+	argsForCall := fake.cancelTransferArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAliasesService) CancelTransferReturns(result1 error) {
+	fake.cancelTransferMutex.Lock()
+	defer fake.cancelTransferMutex.Unlock()
+	fake.CancelTransferStub = nil
+	fake.cancelTransferReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAliasesService) CancelTransferReturnsOnCall(i int, result1 error) {
+	fake.cancelTransferMutex.Lock()
+	defer fake.cancelTransferMutex.Unlock()
+	fake.CancelTransferStub = nil
+	if fake.cancelTransferReturnsOnCall == nil {
+		fake.cancelTransferReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.cancelTransferReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeAliasesService) GetByID(arg1 context.Context, arg2 int64) (roomdb.Alias, error) {
 	fake.getByIDMutex.Lock()
 	ret, specificReturn := fake.getByIDReturnsOnCall[len(fake.getByIDArgsForCall)]
@@ -410,9 +574,82 @@ func (fake *FakeAliasesService) RevokeReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeAliasesService) Transfer(arg1 context.Context, arg2 string, arg3 refs.FeedRef, arg4 []byte) error {
+	var arg4Copy []byte
+	if arg4 != nil {
+		arg4Copy = make([]byte, len(arg4))
+		copy(arg4Copy, arg4)
+	}
+	fake.transferMutex.Lock()
+	ret, specificReturn := fake.transferReturnsOnCall[len(fake.transferArgsForCall)]
+	fake.transferArgsForCall = append(fake.transferArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 refs.FeedRef
+		arg4 []byte
+	}{arg1, arg2, arg3, arg4Copy})
+	stub := fake.TransferStub
+	fakeReturns := fake.transferReturns
+	fake.recordInvocation("Transfer", []interface{}{arg1, arg2, arg3, arg4Copy})
+	fake.transferMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeAliasesService) TransferCallCount() int {
+	fake.transferMutex.RLock()
+	defer fake.transferMutex.RUnlock()
+	return len(fake.transferArgsForCall)
+}
+
+func (fake *FakeAliasesService) TransferCalls(stub func(context.Context, string, refs.FeedRef, []byte) error) {
+	fake.transferMutex.Lock()
+	defer fake.transferMutex.Unlock()
+	fake.TransferStub = stub
+}
+
+func (fake *FakeAliasesService) TransferArgsForCall(i int) (context.Context, string, refs.FeedRef, []byte) {
+	fake.transferMutex.RLock()
+	defer fake.transferMutex.RUnlock()
+	argsForCall := fake.transferArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeAliasesService) TransferReturns(result1 error) {
+	fake.transferMutex.Lock()
+	defer fake.transferMutex.Unlock()
+	fake.TransferStub = nil
+	fake.transferReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAliasesService) TransferReturnsOnCall(i int, result1 error) {
+	fake.transferMutex.Lock()
+	defer fake.transferMutex.Unlock()
+	fake.TransferStub = nil
+	if fake.transferReturnsOnCall == nil {
+		fake.transferReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.transferReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeAliasesService) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.authorizeTransferMutex.RLock()
+	defer fake.authorizeTransferMutex.RUnlock()
+	fake.cancelTransferMutex.RLock()
+	defer fake.cancelTransferMutex.RUnlock()
 	fake.getByIDMutex.RLock()
 	defer fake.getByIDMutex.RUnlock()
 	fake.listMutex.RLock()
@@ -423,6 +660,8 @@ func (fake *FakeAliasesService) Invocations() map[string][][]interface{} {
 	defer fake.resolveMutex.RUnlock()
 	fake.revokeMutex.RLock()
 	defer fake.revokeMutex.RUnlock()
+	fake.transferMutex.RLock()
+	defer fake.transferMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -13,6 +13,20 @@ import (
 )
 
 type FakeAuthWithSSBService struct {
+	CheckDeviceTokenStub        func(context.Context, string) (int64, error)
+	checkDeviceTokenMutex       sync.RWMutex
+	checkDeviceTokenArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	checkDeviceTokenReturns struct {
+		result1 int64
+		result2 error
+	}
+	checkDeviceTokenReturnsOnCall map[int]struct {
+		result1 int64
+		result2 error
+	}
 	CheckTokenStub        func(context.Context, string) (int64, error)
 	checkTokenMutex       sync.RWMutex
 	checkTokenArgsForCall []struct {
@@ -41,6 +55,45 @@ type FakeAuthWithSSBService struct {
 		result1 string
 		result2 error
 	}
+	ListSessionsStub        func(context.Context, int64) ([]roomdb.SIWSSBSession, error)
+	listSessionsMutex       sync.RWMutex
+	listSessionsArgsForCall []struct {
+		arg1 context.Context
+		arg2 int64
+	}
+	listSessionsReturns struct {
+		result1 []roomdb.SIWSSBSession
+		result2 error
+	}
+	listSessionsReturnsOnCall map[int]struct {
+		result1 []roomdb.SIWSSBSession
+		result2 error
+	}
+	MarkDeviceStub        func(context.Context, string) error
+	markDeviceMutex       sync.RWMutex
+	markDeviceArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	markDeviceReturns struct {
+		result1 error
+	}
+	markDeviceReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RemoveSessionStub        func(context.Context, int64, int64) error
+	removeSessionMutex       sync.RWMutex
+	removeSessionArgsForCall []struct {
+		arg1 context.Context
+		arg2 int64
+		arg3 int64
+	}
+	removeSessionReturns struct {
+		result1 error
+	}
+	removeSessionReturnsOnCall map[int]struct {
+		result1 error
+	}
 	RemoveTokenStub        func(context.Context, string) error
 	removeTokenMutex       sync.RWMutex
 	removeTokenArgsForCall []struct {
@@ -53,6 +106,20 @@ type FakeAuthWithSSBService struct {
 	removeTokenReturnsOnCall map[int]struct {
 		result1 error
 	}
+	SetClientDetailsStub        func(context.Context, string, string, string) error
+	setClientDetailsMutex       sync.RWMutex
+	setClientDetailsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+	}
+	setClientDetailsReturns struct {
+		result1 error
+	}
+	setClientDetailsReturnsOnCall map[int]struct {
+		result1 error
+	}
 	WipeTokensForMemberStub        func(context.Context, int64) error
 	wipeTokensForMemberMutex       sync.RWMutex
 	wipeTokensForMemberArgsForCall []struct {
@@ -69,6 +136,71 @@ type FakeAuthWithSSBService struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeAuthWithSSBService) CheckDeviceToken(arg1 context.Context, arg2 string) (int64, error) {
+	fake.checkDeviceTokenMutex.Lock()
+	ret, specificReturn := fake.checkDeviceTokenReturnsOnCall[len(fake.checkDeviceTokenArgsForCall)]
+	fake.checkDeviceTokenArgsForCall = append(fake.checkDeviceTokenArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.CheckDeviceTokenStub
+	fakeReturns := fake.checkDeviceTokenReturns
+	fake.recordInvocation("CheckDeviceToken", []interface{}{arg1, arg2})
+	fake.checkDeviceTokenMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAuthWithSSBService) CheckDeviceTokenCallCount() int {
+	fake.checkDeviceTokenMutex.RLock()
+	defer fake.checkDeviceTokenMutex.RUnlock()
+	return len(fake.checkDeviceTokenArgsForCall)
+}
+
+func (fake *FakeAuthWithSSBService) CheckDeviceTokenCalls(stub func(context.Context, string) (int64, error)) {
+	fake.checkDeviceTokenMutex.Lock()
+	defer fake.checkDeviceTokenMutex.Unlock()
+	fake.CheckDeviceTokenStub = stub
+}
+
+func (fake *FakeAuthWithSSBService) CheckDeviceTokenArgsForCall(i int) (context.Context, string) {
+	fake.checkDeviceTokenMutex.RLock()
+	defer fake.checkDeviceTokenMutex.RUnlock()
+	argsForCall := fake.checkDeviceTokenArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAuthWithSSBService) CheckDeviceTokenReturns(result1 int64, result2 error) {
+	fake.checkDeviceTokenMutex.Lock()
+	defer fake.checkDeviceTokenMutex.Unlock()
+	fake.CheckDeviceTokenStub = nil
+	fake.checkDeviceTokenReturns = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAuthWithSSBService) CheckDeviceTokenReturnsOnCall(i int, result1 int64, result2 error) {
+	fake.checkDeviceTokenMutex.Lock()
+	defer fake.checkDeviceTokenMutex.Unlock()
+	fake.CheckDeviceTokenStub = nil
+	if fake.checkDeviceTokenReturnsOnCall == nil {
+		fake.checkDeviceTokenReturnsOnCall = make(map[int]struct {
+			result1 int64
+			result2 error
+		})
+	}
+	fake.checkDeviceTokenReturnsOnCall[i] = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeAuthWithSSBService) CheckToken(arg1 context.Context, arg2 string) (int64, error) {
 	fake.checkTokenMutex.Lock()
 	ret, specificReturn := fake.checkTokenReturnsOnCall[len(fake.checkTokenArgsForCall)]
@@ -199,6 +331,196 @@ func (fake *FakeAuthWithSSBService) CreateTokenReturnsOnCall(i int, result1 stri
 	}{result1, result2}
 }
 
+func (fake *FakeAuthWithSSBService) ListSessions(arg1 context.Context, arg2 int64) ([]roomdb.SIWSSBSession, error) {
+	fake.listSessionsMutex.Lock()
+	ret, specificReturn := fake.listSessionsReturnsOnCall[len(fake.listSessionsArgsForCall)]
+	fake.listSessionsArgsForCall = append(fake.listSessionsArgsForCall, struct {
+		arg1 context.Context
+		arg2 int64
+	}{arg1, arg2})
+	stub := fake.ListSessionsStub
+	fakeReturns := fake.listSessionsReturns
+	fake.recordInvocation("ListSessions", []interface{}{arg1, arg2})
+	fake.listSessionsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAuthWithSSBService) ListSessionsCallCount() int {
+	fake.listSessionsMutex.RLock()
+	defer fake.listSessionsMutex.RUnlock()
+	return len(fake.listSessionsArgsForCall)
+}
+
+func (fake *FakeAuthWithSSBService) ListSessionsCalls(stub func(context.Context, int64) ([]roomdb.SIWSSBSession, error)) {
+	fake.listSessionsMutex.Lock()
+	defer fake.listSessionsMutex.Unlock()
+	fake.ListSessionsStub = stub
+}
+
+func (fake *FakeAuthWithSSBService) ListSessionsArgsForCall(i int) (context.Context, int64) {
+	fake.listSessionsMutex.RLock()
+	defer fake.listSessionsMutex.RUnlock()
+	argsForCall := fake.listSessionsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAuthWithSSBService) ListSessionsReturns(result1 []roomdb.SIWSSBSession, result2 error) {
+	fake.listSessionsMutex.Lock()
+	defer fake.listSessionsMutex.Unlock()
+	fake.ListSessionsStub = nil
+	fake.listSessionsReturns = struct {
+		result1 []roomdb.SIWSSBSession
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAuthWithSSBService) ListSessionsReturnsOnCall(i int, result1 []roomdb.SIWSSBSession, result2 error) {
+	fake.listSessionsMutex.Lock()
+	defer fake.listSessionsMutex.Unlock()
+	fake.ListSessionsStub = nil
+	if fake.listSessionsReturnsOnCall == nil {
+		fake.listSessionsReturnsOnCall = make(map[int]struct {
+			result1 []roomdb.SIWSSBSession
+			result2 error
+		})
+	}
+	fake.listSessionsReturnsOnCall[i] = struct {
+		result1 []roomdb.SIWSSBSession
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAuthWithSSBService) MarkDevice(arg1 context.Context, arg2 string) error {
+	fake.markDeviceMutex.Lock()
+	ret, specificReturn := fake.markDeviceReturnsOnCall[len(fake.markDeviceArgsForCall)]
+	fake.markDeviceArgsForCall = append(fake.markDeviceArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.MarkDeviceStub
+	fakeReturns := fake.markDeviceReturns
+	fake.recordInvocation("MarkDevice", []interface{}{arg1, arg2})
+	fake.markDeviceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeAuthWithSSBService) MarkDeviceCallCount() int {
+	fake.markDeviceMutex.RLock()
+	defer fake.markDeviceMutex.RUnlock()
+	return len(fake.markDeviceArgsForCall)
+}
+
+func (fake *FakeAuthWithSSBService) MarkDeviceCalls(stub func(context.Context, string) error) {
+	fake.markDeviceMutex.Lock()
+	defer fake.markDeviceMutex.Unlock()
+	fake.MarkDeviceStub = stub
+}
+
+func (fake *FakeAuthWithSSBService) MarkDeviceArgsForCall(i int) (context.Context, string) {
+	fake.markDeviceMutex.RLock()
+	defer fake.markDeviceMutex.RUnlock()
+	argsForCall := fake.markDeviceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAuthWithSSBService) MarkDeviceReturns(result1 error) {
+	fake.markDeviceMutex.Lock()
+	defer fake.markDeviceMutex.Unlock()
+	fake.MarkDeviceStub = nil
+	fake.markDeviceReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAuthWithSSBService) MarkDeviceReturnsOnCall(i int, result1 error) {
+	fake.markDeviceMutex.Lock()
+	defer fake.markDeviceMutex.Unlock()
+	fake.MarkDeviceStub = nil
+	if fake.markDeviceReturnsOnCall == nil {
+		fake.markDeviceReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.markDeviceReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAuthWithSSBService) RemoveSession(arg1 context.Context, arg2 int64, arg3 int64) error {
+	fake.removeSessionMutex.Lock()
+	ret, specificReturn := fake.removeSessionReturnsOnCall[len(fake.removeSessionArgsForCall)]
+	fake.removeSessionArgsForCall = append(fake.removeSessionArgsForCall, struct {
+		arg1 context.Context
+		arg2 int64
+		arg3 int64
+	}{arg1, arg2, arg3})
+	stub := fake.RemoveSessionStub
+	fakeReturns := fake.removeSessionReturns
+	fake.recordInvocation("RemoveSession", []interface{}{arg1, arg2, arg3})
+	fake.removeSessionMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeAuthWithSSBService) RemoveSessionCallCount() int {
+	fake.removeSessionMutex.RLock()
+	defer fake.removeSessionMutex.RUnlock()
+	return len(fake.removeSessionArgsForCall)
+}
+
+func (fake *FakeAuthWithSSBService) RemoveSessionCalls(stub func(context.Context, int64, int64) error) {
+	fake.removeSessionMutex.Lock()
+	defer fake.removeSessionMutex.Unlock()
+	fake.RemoveSessionStub = stub
+}
+
+func (fake *FakeAuthWithSSBService) RemoveSessionArgsForCall(i int) (context.Context, int64, int64) {
+	fake.removeSessionMutex.RLock()
+	defer fake.removeSessionMutex.RUnlock()
+	argsForCall := fake.removeSessionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeAuthWithSSBService) RemoveSessionReturns(result1 error) {
+	fake.removeSessionMutex.Lock()
+	defer fake.removeSessionMutex.Unlock()
+	fake.RemoveSessionStub = nil
+	fake.removeSessionReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAuthWithSSBService) RemoveSessionReturnsOnCall(i int, result1 error) {
+	fake.removeSessionMutex.Lock()
+	defer fake.removeSessionMutex.Unlock()
+	fake.RemoveSessionStub = nil
+	if fake.removeSessionReturnsOnCall == nil {
+		fake.removeSessionReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.removeSessionReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeAuthWithSSBService) RemoveToken(arg1 context.Context, arg2 string) error {
 	fake.removeTokenMutex.Lock()
 	ret, specificReturn := fake.removeTokenReturnsOnCall[len(fake.removeTokenArgsForCall)]
@@ -261,6 +583,70 @@ func (fake *FakeAuthWithSSBService) RemoveTokenReturnsOnCall(i int, result1 erro
 	}{result1}
 }
 
+func (fake *FakeAuthWithSSBService) SetClientDetails(arg1 context.Context, arg2 string, arg3 string, arg4 string) error {
+	fake.setClientDetailsMutex.Lock()
+	ret, specificReturn := fake.setClientDetailsReturnsOnCall[len(fake.setClientDetailsArgsForCall)]
+	fake.setClientDetailsArgsForCall = append(fake.setClientDetailsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.SetClientDetailsStub
+	fakeReturns := fake.setClientDetailsReturns
+	fake.recordInvocation("SetClientDetails", []interface{}{arg1, arg2, arg3, arg4})
+	fake.setClientDetailsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeAuthWithSSBService) SetClientDetailsCallCount() int {
+	fake.setClientDetailsMutex.RLock()
+	defer fake.setClientDetailsMutex.RUnlock()
+	return len(fake.setClientDetailsArgsForCall)
+}
+
+func (fake *FakeAuthWithSSBService) SetClientDetailsCalls(stub func(context.Context, string, string, string) error) {
+	fake.setClientDetailsMutex.Lock()
+	defer fake.setClientDetailsMutex.Unlock()
+	fake.SetClientDetailsStub = stub
+}
+
+func (fake *FakeAuthWithSSBService) SetClientDetailsArgsForCall(i int) (context.Context, string, string, string) {
+	fake.setClientDetailsMutex.RLock()
+	defer fake.setClientDetailsMutex.RUnlock()
+	argsForCall := fake.setClientDetailsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeAuthWithSSBService) SetClientDetailsReturns(result1 error) {
+	fake.setClientDetailsMutex.Lock()
+	defer fake.setClientDetailsMutex.Unlock()
+	fake.SetClientDetailsStub = nil
+	fake.setClientDetailsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAuthWithSSBService) SetClientDetailsReturnsOnCall(i int, result1 error) {
+	fake.setClientDetailsMutex.Lock()
+	defer fake.setClientDetailsMutex.Unlock()
+	fake.SetClientDetailsStub = nil
+	if fake.setClientDetailsReturnsOnCall == nil {
+		fake.setClientDetailsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setClientDetailsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeAuthWithSSBService) WipeTokensForMember(arg1 context.Context, arg2 int64) error {
 	fake.wipeTokensForMemberMutex.Lock()
 	ret, specificReturn := fake.wipeTokensForMemberReturnsOnCall[len(fake.wipeTokensForMemberArgsForCall)]
@@ -326,12 +712,22 @@ func (fake *FakeAuthWithSSBService) WipeTokensForMemberReturnsOnCall(i int, resu
 func (fake *FakeAuthWithSSBService) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.checkDeviceTokenMutex.RLock()
+	defer fake.checkDeviceTokenMutex.RUnlock()
 	fake.checkTokenMutex.RLock()
 	defer fake.checkTokenMutex.RUnlock()
 	fake.createTokenMutex.RLock()
 	defer fake.createTokenMutex.RUnlock()
+	fake.listSessionsMutex.RLock()
+	defer fake.listSessionsMutex.RUnlock()
+	fake.markDeviceMutex.RLock()
+	defer fake.markDeviceMutex.RUnlock()
+	fake.removeSessionMutex.RLock()
+	defer fake.removeSessionMutex.RUnlock()
 	fake.removeTokenMutex.RLock()
 	defer fake.removeTokenMutex.RUnlock()
+	fake.setClientDetailsMutex.RLock()
+	defer fake.setClientDetailsMutex.RUnlock()
 	fake.wipeTokensForMemberMutex.RLock()
 	defer fake.wipeTokensForMemberMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
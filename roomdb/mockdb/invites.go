@@ -8,6 +8,7 @@ package mockdb
 import (
 	"context"
 	"sync"
+	"time"
 
 	refs "github.com/ssbc/go-ssb-refs"
 	"github.com/ssbc/go-ssb-room/v2/roomdb"
@@ -43,11 +44,26 @@ type FakeInvitesService struct {
 		result1 uint
 		result2 error
 	}
-	CreateStub        func(context.Context, int64) (string, error)
+	CountOpenModeStub        func(context.Context, time.Time) (uint, error)
+	countOpenModeMutex       sync.RWMutex
+	countOpenModeArgsForCall []struct {
+		arg1 context.Context
+		arg2 time.Time
+	}
+	countOpenModeReturns struct {
+		result1 uint
+		result2 error
+	}
+	countOpenModeReturnsOnCall map[int]struct {
+		result1 uint
+		result2 error
+	}
+	CreateStub        func(context.Context, int64, roomdb.InviteOptions) (string, error)
 	createMutex       sync.RWMutex
 	createArgsForCall []struct {
 		arg1 context.Context
 		arg2 int64
+		arg3 roomdb.InviteOptions
 	}
 	createReturns struct {
 		result1 string
@@ -57,6 +73,53 @@ type FakeInvitesService struct {
 		result1 string
 		result2 error
 	}
+	CreateBatchStub        func(context.Context, int64, string, uint, time.Time) (roomdb.InviteBatch, []string, error)
+	createBatchMutex       sync.RWMutex
+	createBatchArgsForCall []struct {
+		arg1 context.Context
+		arg2 int64
+		arg3 string
+		arg4 uint
+		arg5 time.Time
+	}
+	createBatchReturns struct {
+		result1 roomdb.InviteBatch
+		result2 []string
+		result3 error
+	}
+	createBatchReturnsOnCall map[int]struct {
+		result1 roomdb.InviteBatch
+		result2 []string
+		result3 error
+	}
+	CreateOpenModeStub        func(context.Context, string) (string, error)
+	createOpenModeMutex       sync.RWMutex
+	createOpenModeArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	createOpenModeReturns struct {
+		result1 string
+		result2 error
+	}
+	createOpenModeReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	GetBatchStub        func(context.Context, int64) (roomdb.InviteBatch, error)
+	getBatchMutex       sync.RWMutex
+	getBatchArgsForCall []struct {
+		arg1 context.Context
+		arg2 int64
+	}
+	getBatchReturns struct {
+		result1 roomdb.InviteBatch
+		result2 error
+	}
+	getBatchReturnsOnCall map[int]struct {
+		result1 roomdb.InviteBatch
+		result2 error
+	}
 	GetByIDStub        func(context.Context, int64) (roomdb.Invite, error)
 	getByIDMutex       sync.RWMutex
 	getByIDArgsForCall []struct {
@@ -85,6 +148,20 @@ type FakeInvitesService struct {
 		result1 roomdb.Invite
 		result2 error
 	}
+	InvitedByStub        func(context.Context, int64) (roomdb.InviteUse, error)
+	invitedByMutex       sync.RWMutex
+	invitedByArgsForCall []struct {
+		arg1 context.Context
+		arg2 int64
+	}
+	invitedByReturns struct {
+		result1 roomdb.InviteUse
+		result2 error
+	}
+	invitedByReturnsOnCall map[int]struct {
+		result1 roomdb.InviteUse
+		result2 error
+	}
 	ListStub        func(context.Context) ([]roomdb.Invite, error)
 	listMutex       sync.RWMutex
 	listArgsForCall []struct {
@@ -98,6 +175,48 @@ type FakeInvitesService struct {
 		result1 []roomdb.Invite
 		result2 error
 	}
+	ListBatchesStub        func(context.Context) ([]roomdb.InviteBatch, error)
+	listBatchesMutex       sync.RWMutex
+	listBatchesArgsForCall []struct {
+		arg1 context.Context
+	}
+	listBatchesReturns struct {
+		result1 []roomdb.InviteBatch
+		result2 error
+	}
+	listBatchesReturnsOnCall map[int]struct {
+		result1 []roomdb.InviteBatch
+		result2 error
+	}
+	ListCreatedByStub        func(context.Context, int64) ([]roomdb.CreatedInvite, error)
+	listCreatedByMutex       sync.RWMutex
+	listCreatedByArgsForCall []struct {
+		arg1 context.Context
+		arg2 int64
+	}
+	listCreatedByReturns struct {
+		result1 []roomdb.CreatedInvite
+		result2 error
+	}
+	listCreatedByReturnsOnCall map[int]struct {
+		result1 []roomdb.CreatedInvite
+		result2 error
+	}
+	ListInvitedByStub        func(context.Context, int64, bool) ([]roomdb.InviteUse, error)
+	listInvitedByMutex       sync.RWMutex
+	listInvitedByArgsForCall []struct {
+		arg1 context.Context
+		arg2 int64
+		arg3 bool
+	}
+	listInvitedByReturns struct {
+		result1 []roomdb.InviteUse
+		result2 error
+	}
+	listInvitedByReturnsOnCall map[int]struct {
+		result1 []roomdb.InviteUse
+		result2 error
+	}
 	RevokeStub        func(context.Context, int64) error
 	revokeMutex       sync.RWMutex
 	revokeArgsForCall []struct {
@@ -110,6 +229,18 @@ type FakeInvitesService struct {
 	revokeReturnsOnCall map[int]struct {
 		result1 error
 	}
+	RevokeBatchStub        func(context.Context, int64) error
+	revokeBatchMutex       sync.RWMutex
+	revokeBatchArgsForCall []struct {
+		arg1 context.Context
+		arg2 int64
+	}
+	revokeBatchReturns struct {
+		result1 error
+	}
+	revokeBatchReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -245,19 +376,85 @@ func (fake *FakeInvitesService) CountReturnsOnCall(i int, result1 uint, result2
 	}{result1, result2}
 }
 
-func (fake *FakeInvitesService) Create(arg1 context.Context, arg2 int64) (string, error) {
+func (fake *FakeInvitesService) CountOpenMode(arg1 context.Context, arg2 time.Time) (uint, error) {
+	fake.countOpenModeMutex.Lock()
+	ret, specificReturn := fake.countOpenModeReturnsOnCall[len(fake.countOpenModeArgsForCall)]
+	fake.countOpenModeArgsForCall = append(fake.countOpenModeArgsForCall, struct {
+		arg1 context.Context
+		arg2 time.Time
+	}{arg1, arg2})
+	stub := fake.CountOpenModeStub
+	fakeReturns := fake.countOpenModeReturns
+	fake.recordInvocation("CountOpenMode", []interface{}{arg1, arg2})
+	fake.countOpenModeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeInvitesService) CountOpenModeCallCount() int {
+	fake.countOpenModeMutex.RLock()
+	defer fake.countOpenModeMutex.RUnlock()
+	return len(fake.countOpenModeArgsForCall)
+}
+
+func (fake *FakeInvitesService) CountOpenModeCalls(stub func(context.Context, time.Time) (uint, error)) {
+	fake.countOpenModeMutex.Lock()
+	defer fake.countOpenModeMutex.Unlock()
+	fake.CountOpenModeStub = stub
+}
+
+func (fake *FakeInvitesService) CountOpenModeArgsForCall(i int) (context.Context, time.Time) {
+	fake.countOpenModeMutex.RLock()
+	defer fake.countOpenModeMutex.RUnlock()
+	argsForCall := fake.countOpenModeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeInvitesService) CountOpenModeReturns(result1 uint, result2 error) {
+	fake.countOpenModeMutex.Lock()
+	defer fake.countOpenModeMutex.Unlock()
+	fake.CountOpenModeStub = nil
+	fake.countOpenModeReturns = struct {
+		result1 uint
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInvitesService) CountOpenModeReturnsOnCall(i int, result1 uint, result2 error) {
+	fake.countOpenModeMutex.Lock()
+	defer fake.countOpenModeMutex.Unlock()
+	fake.CountOpenModeStub = nil
+	if fake.countOpenModeReturnsOnCall == nil {
+		fake.countOpenModeReturnsOnCall = make(map[int]struct {
+			result1 uint
+			result2 error
+		})
+	}
+	fake.countOpenModeReturnsOnCall[i] = struct {
+		result1 uint
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInvitesService) Create(arg1 context.Context, arg2 int64, arg3 roomdb.InviteOptions) (string, error) {
 	fake.createMutex.Lock()
 	ret, specificReturn := fake.createReturnsOnCall[len(fake.createArgsForCall)]
 	fake.createArgsForCall = append(fake.createArgsForCall, struct {
 		arg1 context.Context
 		arg2 int64
-	}{arg1, arg2})
+		arg3 roomdb.InviteOptions
+	}{arg1, arg2, arg3})
 	stub := fake.CreateStub
 	fakeReturns := fake.createReturns
-	fake.recordInvocation("Create", []interface{}{arg1, arg2})
+	fake.recordInvocation("Create", []interface{}{arg1, arg2, arg3})
 	fake.createMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -271,17 +468,17 @@ func (fake *FakeInvitesService) CreateCallCount() int {
 	return len(fake.createArgsForCall)
 }
 
-func (fake *FakeInvitesService) CreateCalls(stub func(context.Context, int64) (string, error)) {
+func (fake *FakeInvitesService) CreateCalls(stub func(context.Context, int64, roomdb.InviteOptions) (string, error)) {
 	fake.createMutex.Lock()
 	defer fake.createMutex.Unlock()
 	fake.CreateStub = stub
 }
 
-func (fake *FakeInvitesService) CreateArgsForCall(i int) (context.Context, int64) {
+func (fake *FakeInvitesService) CreateArgsForCall(i int) (context.Context, int64, roomdb.InviteOptions) {
 	fake.createMutex.RLock()
 	defer fake.createMutex.RUnlock()
 	argsForCall := fake.createArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeInvitesService) CreateReturns(result1 string, result2 error) {
@@ -310,6 +507,207 @@ func (fake *FakeInvitesService) CreateReturnsOnCall(i int, result1 string, resul
 	}{result1, result2}
 }
 
+func (fake *FakeInvitesService) CreateBatch(arg1 context.Context, arg2 int64, arg3 string, arg4 uint, arg5 time.Time) (roomdb.InviteBatch, []string, error) {
+	fake.createBatchMutex.Lock()
+	ret, specificReturn := fake.createBatchReturnsOnCall[len(fake.createBatchArgsForCall)]
+	fake.createBatchArgsForCall = append(fake.createBatchArgsForCall, struct {
+		arg1 context.Context
+		arg2 int64
+		arg3 string
+		arg4 uint
+		arg5 time.Time
+	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.CreateBatchStub
+	fakeReturns := fake.createBatchReturns
+	fake.recordInvocation("CreateBatch", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.createBatchMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeInvitesService) CreateBatchCallCount() int {
+	fake.createBatchMutex.RLock()
+	defer fake.createBatchMutex.RUnlock()
+	return len(fake.createBatchArgsForCall)
+}
+
+func (fake *FakeInvitesService) CreateBatchCalls(stub func(context.Context, int64, string, uint, time.Time) (roomdb.InviteBatch, []string, error)) {
+	fake.createBatchMutex.Lock()
+	defer fake.createBatchMutex.Unlock()
+	fake.CreateBatchStub = stub
+}
+
+func (fake *FakeInvitesService) CreateBatchArgsForCall(i int) (context.Context, int64, string, uint, time.Time) {
+	fake.createBatchMutex.RLock()
+	defer fake.createBatchMutex.RUnlock()
+	argsForCall := fake.createBatchArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeInvitesService) CreateBatchReturns(result1 roomdb.InviteBatch, result2 []string, result3 error) {
+	fake.createBatchMutex.Lock()
+	defer fake.createBatchMutex.Unlock()
+	fake.CreateBatchStub = nil
+	fake.createBatchReturns = struct {
+		result1 roomdb.InviteBatch
+		result2 []string
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeInvitesService) CreateBatchReturnsOnCall(i int, result1 roomdb.InviteBatch, result2 []string, result3 error) {
+	fake.createBatchMutex.Lock()
+	defer fake.createBatchMutex.Unlock()
+	fake.CreateBatchStub = nil
+	if fake.createBatchReturnsOnCall == nil {
+		fake.createBatchReturnsOnCall = make(map[int]struct {
+			result1 roomdb.InviteBatch
+			result2 []string
+			result3 error
+		})
+	}
+	fake.createBatchReturnsOnCall[i] = struct {
+		result1 roomdb.InviteBatch
+		result2 []string
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeInvitesService) CreateOpenMode(arg1 context.Context, arg2 string) (string, error) {
+	fake.createOpenModeMutex.Lock()
+	ret, specificReturn := fake.createOpenModeReturnsOnCall[len(fake.createOpenModeArgsForCall)]
+	fake.createOpenModeArgsForCall = append(fake.createOpenModeArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.CreateOpenModeStub
+	fakeReturns := fake.createOpenModeReturns
+	fake.recordInvocation("CreateOpenMode", []interface{}{arg1, arg2})
+	fake.createOpenModeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeInvitesService) CreateOpenModeCallCount() int {
+	fake.createOpenModeMutex.RLock()
+	defer fake.createOpenModeMutex.RUnlock()
+	return len(fake.createOpenModeArgsForCall)
+}
+
+func (fake *FakeInvitesService) CreateOpenModeCalls(stub func(context.Context, string) (string, error)) {
+	fake.createOpenModeMutex.Lock()
+	defer fake.createOpenModeMutex.Unlock()
+	fake.CreateOpenModeStub = stub
+}
+
+func (fake *FakeInvitesService) CreateOpenModeArgsForCall(i int) (context.Context, string) {
+	fake.createOpenModeMutex.RLock()
+	defer fake.createOpenModeMutex.RUnlock()
+	argsForCall := fake.createOpenModeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeInvitesService) CreateOpenModeReturns(result1 string, result2 error) {
+	fake.createOpenModeMutex.Lock()
+	defer fake.createOpenModeMutex.Unlock()
+	fake.CreateOpenModeStub = nil
+	fake.createOpenModeReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInvitesService) CreateOpenModeReturnsOnCall(i int, result1 string, result2 error) {
+	fake.createOpenModeMutex.Lock()
+	defer fake.createOpenModeMutex.Unlock()
+	fake.CreateOpenModeStub = nil
+	if fake.createOpenModeReturnsOnCall == nil {
+		fake.createOpenModeReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.createOpenModeReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInvitesService) GetBatch(arg1 context.Context, arg2 int64) (roomdb.InviteBatch, error) {
+	fake.getBatchMutex.Lock()
+	ret, specificReturn := fake.getBatchReturnsOnCall[len(fake.getBatchArgsForCall)]
+	fake.getBatchArgsForCall = append(fake.getBatchArgsForCall, struct {
+		arg1 context.Context
+		arg2 int64
+	}{arg1, arg2})
+	stub := fake.GetBatchStub
+	fakeReturns := fake.getBatchReturns
+	fake.recordInvocation("GetBatch", []interface{}{arg1, arg2})
+	fake.getBatchMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeInvitesService) GetBatchCallCount() int {
+	fake.getBatchMutex.RLock()
+	defer fake.getBatchMutex.RUnlock()
+	return len(fake.getBatchArgsForCall)
+}
+
+func (fake *FakeInvitesService) GetBatchCalls(stub func(context.Context, int64) (roomdb.InviteBatch, error)) {
+	fake.getBatchMutex.Lock()
+	defer fake.getBatchMutex.Unlock()
+	fake.GetBatchStub = stub
+}
+
+func (fake *FakeInvitesService) GetBatchArgsForCall(i int) (context.Context, int64) {
+	fake.getBatchMutex.RLock()
+	defer fake.getBatchMutex.RUnlock()
+	argsForCall := fake.getBatchArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeInvitesService) GetBatchReturns(result1 roomdb.InviteBatch, result2 error) {
+	fake.getBatchMutex.Lock()
+	defer fake.getBatchMutex.Unlock()
+	fake.GetBatchStub = nil
+	fake.getBatchReturns = struct {
+		result1 roomdb.InviteBatch
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInvitesService) GetBatchReturnsOnCall(i int, result1 roomdb.InviteBatch, result2 error) {
+	fake.getBatchMutex.Lock()
+	defer fake.getBatchMutex.Unlock()
+	fake.GetBatchStub = nil
+	if fake.getBatchReturnsOnCall == nil {
+		fake.getBatchReturnsOnCall = make(map[int]struct {
+			result1 roomdb.InviteBatch
+			result2 error
+		})
+	}
+	fake.getBatchReturnsOnCall[i] = struct {
+		result1 roomdb.InviteBatch
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeInvitesService) GetByID(arg1 context.Context, arg2 int64) (roomdb.Invite, error) {
 	fake.getByIDMutex.Lock()
 	ret, specificReturn := fake.getByIDReturnsOnCall[len(fake.getByIDArgsForCall)]
@@ -440,6 +838,71 @@ func (fake *FakeInvitesService) GetByTokenReturnsOnCall(i int, result1 roomdb.In
 	}{result1, result2}
 }
 
+func (fake *FakeInvitesService) InvitedBy(arg1 context.Context, arg2 int64) (roomdb.InviteUse, error) {
+	fake.invitedByMutex.Lock()
+	ret, specificReturn := fake.invitedByReturnsOnCall[len(fake.invitedByArgsForCall)]
+	fake.invitedByArgsForCall = append(fake.invitedByArgsForCall, struct {
+		arg1 context.Context
+		arg2 int64
+	}{arg1, arg2})
+	stub := fake.InvitedByStub
+	fakeReturns := fake.invitedByReturns
+	fake.recordInvocation("InvitedBy", []interface{}{arg1, arg2})
+	fake.invitedByMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeInvitesService) InvitedByCallCount() int {
+	fake.invitedByMutex.RLock()
+	defer fake.invitedByMutex.RUnlock()
+	return len(fake.invitedByArgsForCall)
+}
+
+func (fake *FakeInvitesService) InvitedByCalls(stub func(context.Context, int64) (roomdb.InviteUse, error)) {
+	fake.invitedByMutex.Lock()
+	defer fake.invitedByMutex.Unlock()
+	fake.InvitedByStub = stub
+}
+
+func (fake *FakeInvitesService) InvitedByArgsForCall(i int) (context.Context, int64) {
+	fake.invitedByMutex.RLock()
+	defer fake.invitedByMutex.RUnlock()
+	argsForCall := fake.invitedByArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeInvitesService) InvitedByReturns(result1 roomdb.InviteUse, result2 error) {
+	fake.invitedByMutex.Lock()
+	defer fake.invitedByMutex.Unlock()
+	fake.InvitedByStub = nil
+	fake.invitedByReturns = struct {
+		result1 roomdb.InviteUse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInvitesService) InvitedByReturnsOnCall(i int, result1 roomdb.InviteUse, result2 error) {
+	fake.invitedByMutex.Lock()
+	defer fake.invitedByMutex.Unlock()
+	fake.InvitedByStub = nil
+	if fake.invitedByReturnsOnCall == nil {
+		fake.invitedByReturnsOnCall = make(map[int]struct {
+			result1 roomdb.InviteUse
+			result2 error
+		})
+	}
+	fake.invitedByReturnsOnCall[i] = struct {
+		result1 roomdb.InviteUse
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeInvitesService) List(arg1 context.Context) ([]roomdb.Invite, error) {
 	fake.listMutex.Lock()
 	ret, specificReturn := fake.listReturnsOnCall[len(fake.listArgsForCall)]
@@ -504,6 +967,201 @@ func (fake *FakeInvitesService) ListReturnsOnCall(i int, result1 []roomdb.Invite
 	}{result1, result2}
 }
 
+func (fake *FakeInvitesService) ListBatches(arg1 context.Context) ([]roomdb.InviteBatch, error) {
+	fake.listBatchesMutex.Lock()
+	ret, specificReturn := fake.listBatchesReturnsOnCall[len(fake.listBatchesArgsForCall)]
+	fake.listBatchesArgsForCall = append(fake.listBatchesArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.ListBatchesStub
+	fakeReturns := fake.listBatchesReturns
+	fake.recordInvocation("ListBatches", []interface{}{arg1})
+	fake.listBatchesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeInvitesService) ListBatchesCallCount() int {
+	fake.listBatchesMutex.RLock()
+	defer fake.listBatchesMutex.RUnlock()
+	return len(fake.listBatchesArgsForCall)
+}
+
+func (fake *FakeInvitesService) ListBatchesCalls(stub func(context.Context) ([]roomdb.InviteBatch, error)) {
+	fake.listBatchesMutex.Lock()
+	defer fake.listBatchesMutex.Unlock()
+	fake.ListBatchesStub = stub
+}
+
+func (fake *FakeInvitesService) ListBatchesArgsForCall(i int) context.Context {
+	fake.listBatchesMutex.RLock()
+	defer fake.listBatchesMutex.RUnlock()
+	argsForCall := fake.listBatchesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeInvitesService) ListBatchesReturns(result1 []roomdb.InviteBatch, result2 error) {
+	fake.listBatchesMutex.Lock()
+	defer fake.listBatchesMutex.Unlock()
+	fake.ListBatchesStub = nil
+	fake.listBatchesReturns = struct {
+		result1 []roomdb.InviteBatch
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInvitesService) ListBatchesReturnsOnCall(i int, result1 []roomdb.InviteBatch, result2 error) {
+	fake.listBatchesMutex.Lock()
+	defer fake.listBatchesMutex.Unlock()
+	fake.ListBatchesStub = nil
+	if fake.listBatchesReturnsOnCall == nil {
+		fake.listBatchesReturnsOnCall = make(map[int]struct {
+			result1 []roomdb.InviteBatch
+			result2 error
+		})
+	}
+	fake.listBatchesReturnsOnCall[i] = struct {
+		result1 []roomdb.InviteBatch
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInvitesService) ListCreatedBy(arg1 context.Context, arg2 int64) ([]roomdb.CreatedInvite, error) {
+	fake.listCreatedByMutex.Lock()
+	ret, specificReturn := fake.listCreatedByReturnsOnCall[len(fake.listCreatedByArgsForCall)]
+	fake.listCreatedByArgsForCall = append(fake.listCreatedByArgsForCall, struct {
+		arg1 context.Context
+		arg2 int64
+	}{arg1, arg2})
+	stub := fake.ListCreatedByStub
+	fakeReturns := fake.listCreatedByReturns
+	fake.recordInvocation("ListCreatedBy", []interface{}{arg1, arg2})
+	fake.listCreatedByMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeInvitesService) ListCreatedByCallCount() int {
+	fake.listCreatedByMutex.RLock()
+	defer fake.listCreatedByMutex.RUnlock()
+	return len(fake.listCreatedByArgsForCall)
+}
+
+func (fake *FakeInvitesService) ListCreatedByCalls(stub func(context.Context, int64) ([]roomdb.CreatedInvite, error)) {
+	fake.listCreatedByMutex.Lock()
+	defer fake.listCreatedByMutex.Unlock()
+	fake.ListCreatedByStub = stub
+}
+
+func (fake *FakeInvitesService) ListCreatedByArgsForCall(i int) (context.Context, int64) {
+	fake.listCreatedByMutex.RLock()
+	defer fake.listCreatedByMutex.RUnlock()
+	argsForCall := fake.listCreatedByArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeInvitesService) ListCreatedByReturns(result1 []roomdb.CreatedInvite, result2 error) {
+	fake.listCreatedByMutex.Lock()
+	defer fake.listCreatedByMutex.Unlock()
+	fake.ListCreatedByStub = nil
+	fake.listCreatedByReturns = struct {
+		result1 []roomdb.CreatedInvite
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInvitesService) ListCreatedByReturnsOnCall(i int, result1 []roomdb.CreatedInvite, result2 error) {
+	fake.listCreatedByMutex.Lock()
+	defer fake.listCreatedByMutex.Unlock()
+	fake.ListCreatedByStub = nil
+	if fake.listCreatedByReturnsOnCall == nil {
+		fake.listCreatedByReturnsOnCall = make(map[int]struct {
+			result1 []roomdb.CreatedInvite
+			result2 error
+		})
+	}
+	fake.listCreatedByReturnsOnCall[i] = struct {
+		result1 []roomdb.CreatedInvite
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInvitesService) ListInvitedBy(arg1 context.Context, arg2 int64, arg3 bool) ([]roomdb.InviteUse, error) {
+	fake.listInvitedByMutex.Lock()
+	ret, specificReturn := fake.listInvitedByReturnsOnCall[len(fake.listInvitedByArgsForCall)]
+	fake.listInvitedByArgsForCall = append(fake.listInvitedByArgsForCall, struct {
+		arg1 context.Context
+		arg2 int64
+		arg3 bool
+	}{arg1, arg2, arg3})
+	stub := fake.ListInvitedByStub
+	fakeReturns := fake.listInvitedByReturns
+	fake.recordInvocation("ListInvitedBy", []interface{}{arg1, arg2, arg3})
+	fake.listInvitedByMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeInvitesService) ListInvitedByCallCount() int {
+	fake.listInvitedByMutex.RLock()
+	defer fake.listInvitedByMutex.RUnlock()
+	return len(fake.listInvitedByArgsForCall)
+}
+
+func (fake *FakeInvitesService) ListInvitedByCalls(stub func(context.Context, int64, bool) ([]roomdb.InviteUse, error)) {
+	fake.listInvitedByMutex.Lock()
+	defer fake.listInvitedByMutex.Unlock()
+	fake.ListInvitedByStub = stub
+}
+
+func (fake *FakeInvitesService) ListInvitedByArgsForCall(i int) (context.Context, int64, bool) {
+	fake.listInvitedByMutex.RLock()
+	defer fake.listInvitedByMutex.RUnlock()
+	argsForCall := fake.listInvitedByArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeInvitesService) ListInvitedByReturns(result1 []roomdb.InviteUse, result2 error) {
+	fake.listInvitedByMutex.Lock()
+	defer fake.listInvitedByMutex.Unlock()
+	fake.ListInvitedByStub = nil
+	fake.listInvitedByReturns = struct {
+		result1 []roomdb.InviteUse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInvitesService) ListInvitedByReturnsOnCall(i int, result1 []roomdb.InviteUse, result2 error) {
+	fake.listInvitedByMutex.Lock()
+	defer fake.listInvitedByMutex.Unlock()
+	fake.ListInvitedByStub = nil
+	if fake.listInvitedByReturnsOnCall == nil {
+		fake.listInvitedByReturnsOnCall = make(map[int]struct {
+			result1 []roomdb.InviteUse
+			result2 error
+		})
+	}
+	fake.listInvitedByReturnsOnCall[i] = struct {
+		result1 []roomdb.InviteUse
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeInvitesService) Revoke(arg1 context.Context, arg2 int64) error {
 	fake.revokeMutex.Lock()
 	ret, specificReturn := fake.revokeReturnsOnCall[len(fake.revokeArgsForCall)]
@@ -566,6 +1224,68 @@ func (fake *FakeInvitesService) RevokeReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeInvitesService) RevokeBatch(arg1 context.Context, arg2 int64) error {
+	fake.revokeBatchMutex.Lock()
+	ret, specificReturn := fake.revokeBatchReturnsOnCall[len(fake.revokeBatchArgsForCall)]
+	fake.revokeBatchArgsForCall = append(fake.revokeBatchArgsForCall, struct {
+		arg1 context.Context
+		arg2 int64
+	}{arg1, arg2})
+	stub := fake.RevokeBatchStub
+	fakeReturns := fake.revokeBatchReturns
+	fake.recordInvocation("RevokeBatch", []interface{}{arg1, arg2})
+	fake.revokeBatchMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeInvitesService) RevokeBatchCallCount() int {
+	fake.revokeBatchMutex.RLock()
+	defer fake.revokeBatchMutex.RUnlock()
+	return len(fake.revokeBatchArgsForCall)
+}
+
+func (fake *FakeInvitesService) RevokeBatchCalls(stub func(context.Context, int64) error) {
+	fake.revokeBatchMutex.Lock()
+	defer fake.revokeBatchMutex.Unlock()
+	fake.RevokeBatchStub = stub
+}
+
+func (fake *FakeInvitesService) RevokeBatchArgsForCall(i int) (context.Context, int64) {
+	fake.revokeBatchMutex.RLock()
+	defer fake.revokeBatchMutex.RUnlock()
+	argsForCall := fake.revokeBatchArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeInvitesService) RevokeBatchReturns(result1 error) {
+	fake.revokeBatchMutex.Lock()
+	defer fake.revokeBatchMutex.Unlock()
+	fake.RevokeBatchStub = nil
+	fake.revokeBatchReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeInvitesService) RevokeBatchReturnsOnCall(i int, result1 error) {
+	fake.revokeBatchMutex.Lock()
+	defer fake.revokeBatchMutex.Unlock()
+	fake.RevokeBatchStub = nil
+	if fake.revokeBatchReturnsOnCall == nil {
+		fake.revokeBatchReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.revokeBatchReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeInvitesService) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -573,16 +1293,34 @@ func (fake *FakeInvitesService) Invocations() map[string][][]interface{} {
 	defer fake.consumeMutex.RUnlock()
 	fake.countMutex.RLock()
 	defer fake.countMutex.RUnlock()
+	fake.countOpenModeMutex.RLock()
+	defer fake.countOpenModeMutex.RUnlock()
 	fake.createMutex.RLock()
 	defer fake.createMutex.RUnlock()
+	fake.createBatchMutex.RLock()
+	defer fake.createBatchMutex.RUnlock()
+	fake.createOpenModeMutex.RLock()
+	defer fake.createOpenModeMutex.RUnlock()
+	fake.getBatchMutex.RLock()
+	defer fake.getBatchMutex.RUnlock()
 	fake.getByIDMutex.RLock()
 	defer fake.getByIDMutex.RUnlock()
 	fake.getByTokenMutex.RLock()
 	defer fake.getByTokenMutex.RUnlock()
+	fake.invitedByMutex.RLock()
+	defer fake.invitedByMutex.RUnlock()
 	fake.listMutex.RLock()
 	defer fake.listMutex.RUnlock()
+	fake.listBatchesMutex.RLock()
+	defer fake.listBatchesMutex.RUnlock()
+	fake.listCreatedByMutex.RLock()
+	defer fake.listCreatedByMutex.RUnlock()
+	fake.listInvitedByMutex.RLock()
+	defer fake.listInvitedByMutex.RUnlock()
 	fake.revokeMutex.RLock()
 	defer fake.revokeMutex.RUnlock()
+	fake.revokeBatchMutex.RLock()
+	defer fake.revokeBatchMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
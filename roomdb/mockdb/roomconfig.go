@@ -13,6 +13,19 @@ import (
 )
 
 type FakeRoomConfig struct {
+	GetAliasPolicyStub        func(context.Context) (roomdb.AliasPolicy, error)
+	getAliasPolicyMutex       sync.RWMutex
+	getAliasPolicyArgsForCall []struct {
+		arg1 context.Context
+	}
+	getAliasPolicyReturns struct {
+		result1 roomdb.AliasPolicy
+		result2 error
+	}
+	getAliasPolicyReturnsOnCall map[int]struct {
+		result1 roomdb.AliasPolicy
+		result2 error
+	}
 	GetDefaultLanguageStub        func(context.Context) (string, error)
 	getDefaultLanguageMutex       sync.RWMutex
 	getDefaultLanguageArgsForCall []struct {
@@ -26,6 +39,32 @@ type FakeRoomConfig struct {
 		result1 string
 		result2 error
 	}
+	GetMemberInviteQuotaStub        func(context.Context) (uint, error)
+	getMemberInviteQuotaMutex       sync.RWMutex
+	getMemberInviteQuotaArgsForCall []struct {
+		arg1 context.Context
+	}
+	getMemberInviteQuotaReturns struct {
+		result1 uint
+		result2 error
+	}
+	getMemberInviteQuotaReturnsOnCall map[int]struct {
+		result1 uint
+		result2 error
+	}
+	GetOpenInviteLimitsStub        func(context.Context) (roomdb.OpenInviteLimits, error)
+	getOpenInviteLimitsMutex       sync.RWMutex
+	getOpenInviteLimitsArgsForCall []struct {
+		arg1 context.Context
+	}
+	getOpenInviteLimitsReturns struct {
+		result1 roomdb.OpenInviteLimits
+		result2 error
+	}
+	getOpenInviteLimitsReturnsOnCall map[int]struct {
+		result1 roomdb.OpenInviteLimits
+		result2 error
+	}
 	GetPrivacyModeStub        func(context.Context) (roomdb.PrivacyMode, error)
 	getPrivacyModeMutex       sync.RWMutex
 	getPrivacyModeArgsForCall []struct {
@@ -39,6 +78,31 @@ type FakeRoomConfig struct {
 		result1 roomdb.PrivacyMode
 		result2 error
 	}
+	GetTOTPMandatoryStub        func(context.Context) (bool, error)
+	getTOTPMandatoryMutex       sync.RWMutex
+	getTOTPMandatoryArgsForCall []struct {
+		arg1 context.Context
+	}
+	getTOTPMandatoryReturns struct {
+		result1 bool
+		result2 error
+	}
+	getTOTPMandatoryReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	SetAliasPolicyStub        func(context.Context, roomdb.AliasPolicy) error
+	setAliasPolicyMutex       sync.RWMutex
+	setAliasPolicyArgsForCall []struct {
+		arg1 context.Context
+		arg2 roomdb.AliasPolicy
+	}
+	setAliasPolicyReturns struct {
+		result1 error
+	}
+	setAliasPolicyReturnsOnCall map[int]struct {
+		result1 error
+	}
 	SetDefaultLanguageStub        func(context.Context, string) error
 	setDefaultLanguageMutex       sync.RWMutex
 	setDefaultLanguageArgsForCall []struct {
@@ -51,6 +115,30 @@ type FakeRoomConfig struct {
 	setDefaultLanguageReturnsOnCall map[int]struct {
 		result1 error
 	}
+	SetMemberInviteQuotaStub        func(context.Context, uint) error
+	setMemberInviteQuotaMutex       sync.RWMutex
+	setMemberInviteQuotaArgsForCall []struct {
+		arg1 context.Context
+		arg2 uint
+	}
+	setMemberInviteQuotaReturns struct {
+		result1 error
+	}
+	setMemberInviteQuotaReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetOpenInviteLimitsStub        func(context.Context, roomdb.OpenInviteLimits) error
+	setOpenInviteLimitsMutex       sync.RWMutex
+	setOpenInviteLimitsArgsForCall []struct {
+		arg1 context.Context
+		arg2 roomdb.OpenInviteLimits
+	}
+	setOpenInviteLimitsReturns struct {
+		result1 error
+	}
+	setOpenInviteLimitsReturnsOnCall map[int]struct {
+		result1 error
+	}
 	SetPrivacyModeStub        func(context.Context, roomdb.PrivacyMode) error
 	setPrivacyModeMutex       sync.RWMutex
 	setPrivacyModeArgsForCall []struct {
@@ -63,10 +151,86 @@ type FakeRoomConfig struct {
 	setPrivacyModeReturnsOnCall map[int]struct {
 		result1 error
 	}
+	SetTOTPMandatoryStub        func(context.Context, bool) error
+	setTOTPMandatoryMutex       sync.RWMutex
+	setTOTPMandatoryArgsForCall []struct {
+		arg1 context.Context
+		arg2 bool
+	}
+	setTOTPMandatoryReturns struct {
+		result1 error
+	}
+	setTOTPMandatoryReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeRoomConfig) GetAliasPolicy(arg1 context.Context) (roomdb.AliasPolicy, error) {
+	fake.getAliasPolicyMutex.Lock()
+	ret, specificReturn := fake.getAliasPolicyReturnsOnCall[len(fake.getAliasPolicyArgsForCall)]
+	fake.getAliasPolicyArgsForCall = append(fake.getAliasPolicyArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.GetAliasPolicyStub
+	fakeReturns := fake.getAliasPolicyReturns
+	fake.recordInvocation("GetAliasPolicy", []interface{}{arg1})
+	fake.getAliasPolicyMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRoomConfig) GetAliasPolicyCallCount() int {
+	fake.getAliasPolicyMutex.RLock()
+	defer fake.getAliasPolicyMutex.RUnlock()
+	return len(fake.getAliasPolicyArgsForCall)
+}
+
+func (fake *FakeRoomConfig) GetAliasPolicyCalls(stub func(context.Context) (roomdb.AliasPolicy, error)) {
+	fake.getAliasPolicyMutex.Lock()
+	defer fake.getAliasPolicyMutex.Unlock()
+	fake.GetAliasPolicyStub = stub
+}
+
+func (fake *FakeRoomConfig) GetAliasPolicyArgsForCall(i int) context.Context {
+	fake.getAliasPolicyMutex.RLock()
+	defer fake.getAliasPolicyMutex.RUnlock()
+	argsForCall := fake.getAliasPolicyArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRoomConfig) GetAliasPolicyReturns(result1 roomdb.AliasPolicy, result2 error) {
+	fake.getAliasPolicyMutex.Lock()
+	defer fake.getAliasPolicyMutex.Unlock()
+	fake.GetAliasPolicyStub = nil
+	fake.getAliasPolicyReturns = struct {
+		result1 roomdb.AliasPolicy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRoomConfig) GetAliasPolicyReturnsOnCall(i int, result1 roomdb.AliasPolicy, result2 error) {
+	fake.getAliasPolicyMutex.Lock()
+	defer fake.getAliasPolicyMutex.Unlock()
+	fake.GetAliasPolicyStub = nil
+	if fake.getAliasPolicyReturnsOnCall == nil {
+		fake.getAliasPolicyReturnsOnCall = make(map[int]struct {
+			result1 roomdb.AliasPolicy
+			result2 error
+		})
+	}
+	fake.getAliasPolicyReturnsOnCall[i] = struct {
+		result1 roomdb.AliasPolicy
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeRoomConfig) GetDefaultLanguage(arg1 context.Context) (string, error) {
 	fake.getDefaultLanguageMutex.Lock()
 	ret, specificReturn := fake.getDefaultLanguageReturnsOnCall[len(fake.getDefaultLanguageArgsForCall)]
@@ -131,6 +295,134 @@ func (fake *FakeRoomConfig) GetDefaultLanguageReturnsOnCall(i int, result1 strin
 	}{result1, result2}
 }
 
+func (fake *FakeRoomConfig) GetMemberInviteQuota(arg1 context.Context) (uint, error) {
+	fake.getMemberInviteQuotaMutex.Lock()
+	ret, specificReturn := fake.getMemberInviteQuotaReturnsOnCall[len(fake.getMemberInviteQuotaArgsForCall)]
+	fake.getMemberInviteQuotaArgsForCall = append(fake.getMemberInviteQuotaArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.GetMemberInviteQuotaStub
+	fakeReturns := fake.getMemberInviteQuotaReturns
+	fake.recordInvocation("GetMemberInviteQuota", []interface{}{arg1})
+	fake.getMemberInviteQuotaMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRoomConfig) GetMemberInviteQuotaCallCount() int {
+	fake.getMemberInviteQuotaMutex.RLock()
+	defer fake.getMemberInviteQuotaMutex.RUnlock()
+	return len(fake.getMemberInviteQuotaArgsForCall)
+}
+
+func (fake *FakeRoomConfig) GetMemberInviteQuotaCalls(stub func(context.Context) (uint, error)) {
+	fake.getMemberInviteQuotaMutex.Lock()
+	defer fake.getMemberInviteQuotaMutex.Unlock()
+	fake.GetMemberInviteQuotaStub = stub
+}
+
+func (fake *FakeRoomConfig) GetMemberInviteQuotaArgsForCall(i int) context.Context {
+	fake.getMemberInviteQuotaMutex.RLock()
+	defer fake.getMemberInviteQuotaMutex.RUnlock()
+	argsForCall := fake.getMemberInviteQuotaArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRoomConfig) GetMemberInviteQuotaReturns(result1 uint, result2 error) {
+	fake.getMemberInviteQuotaMutex.Lock()
+	defer fake.getMemberInviteQuotaMutex.Unlock()
+	fake.GetMemberInviteQuotaStub = nil
+	fake.getMemberInviteQuotaReturns = struct {
+		result1 uint
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRoomConfig) GetMemberInviteQuotaReturnsOnCall(i int, result1 uint, result2 error) {
+	fake.getMemberInviteQuotaMutex.Lock()
+	defer fake.getMemberInviteQuotaMutex.Unlock()
+	fake.GetMemberInviteQuotaStub = nil
+	if fake.getMemberInviteQuotaReturnsOnCall == nil {
+		fake.getMemberInviteQuotaReturnsOnCall = make(map[int]struct {
+			result1 uint
+			result2 error
+		})
+	}
+	fake.getMemberInviteQuotaReturnsOnCall[i] = struct {
+		result1 uint
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRoomConfig) GetOpenInviteLimits(arg1 context.Context) (roomdb.OpenInviteLimits, error) {
+	fake.getOpenInviteLimitsMutex.Lock()
+	ret, specificReturn := fake.getOpenInviteLimitsReturnsOnCall[len(fake.getOpenInviteLimitsArgsForCall)]
+	fake.getOpenInviteLimitsArgsForCall = append(fake.getOpenInviteLimitsArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.GetOpenInviteLimitsStub
+	fakeReturns := fake.getOpenInviteLimitsReturns
+	fake.recordInvocation("GetOpenInviteLimits", []interface{}{arg1})
+	fake.getOpenInviteLimitsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRoomConfig) GetOpenInviteLimitsCallCount() int {
+	fake.getOpenInviteLimitsMutex.RLock()
+	defer fake.getOpenInviteLimitsMutex.RUnlock()
+	return len(fake.getOpenInviteLimitsArgsForCall)
+}
+
+func (fake *FakeRoomConfig) GetOpenInviteLimitsCalls(stub func(context.Context) (roomdb.OpenInviteLimits, error)) {
+	fake.getOpenInviteLimitsMutex.Lock()
+	defer fake.getOpenInviteLimitsMutex.Unlock()
+	fake.GetOpenInviteLimitsStub = stub
+}
+
+func (fake *FakeRoomConfig) GetOpenInviteLimitsArgsForCall(i int) context.Context {
+	fake.getOpenInviteLimitsMutex.RLock()
+	defer fake.getOpenInviteLimitsMutex.RUnlock()
+	argsForCall := fake.getOpenInviteLimitsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRoomConfig) GetOpenInviteLimitsReturns(result1 roomdb.OpenInviteLimits, result2 error) {
+	fake.getOpenInviteLimitsMutex.Lock()
+	defer fake.getOpenInviteLimitsMutex.Unlock()
+	fake.GetOpenInviteLimitsStub = nil
+	fake.getOpenInviteLimitsReturns = struct {
+		result1 roomdb.OpenInviteLimits
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRoomConfig) GetOpenInviteLimitsReturnsOnCall(i int, result1 roomdb.OpenInviteLimits, result2 error) {
+	fake.getOpenInviteLimitsMutex.Lock()
+	defer fake.getOpenInviteLimitsMutex.Unlock()
+	fake.GetOpenInviteLimitsStub = nil
+	if fake.getOpenInviteLimitsReturnsOnCall == nil {
+		fake.getOpenInviteLimitsReturnsOnCall = make(map[int]struct {
+			result1 roomdb.OpenInviteLimits
+			result2 error
+		})
+	}
+	fake.getOpenInviteLimitsReturnsOnCall[i] = struct {
+		result1 roomdb.OpenInviteLimits
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeRoomConfig) GetPrivacyMode(arg1 context.Context) (roomdb.PrivacyMode, error) {
 	fake.getPrivacyModeMutex.Lock()
 	ret, specificReturn := fake.getPrivacyModeReturnsOnCall[len(fake.getPrivacyModeArgsForCall)]
@@ -195,6 +487,132 @@ func (fake *FakeRoomConfig) GetPrivacyModeReturnsOnCall(i int, result1 roomdb.Pr
 	}{result1, result2}
 }
 
+func (fake *FakeRoomConfig) GetTOTPMandatory(arg1 context.Context) (bool, error) {
+	fake.getTOTPMandatoryMutex.Lock()
+	ret, specificReturn := fake.getTOTPMandatoryReturnsOnCall[len(fake.getTOTPMandatoryArgsForCall)]
+	fake.getTOTPMandatoryArgsForCall = append(fake.getTOTPMandatoryArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.GetTOTPMandatoryStub
+	fakeReturns := fake.getTOTPMandatoryReturns
+	fake.recordInvocation("GetTOTPMandatory", []interface{}{arg1})
+	fake.getTOTPMandatoryMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRoomConfig) GetTOTPMandatoryCallCount() int {
+	fake.getTOTPMandatoryMutex.RLock()
+	defer fake.getTOTPMandatoryMutex.RUnlock()
+	return len(fake.getTOTPMandatoryArgsForCall)
+}
+
+func (fake *FakeRoomConfig) GetTOTPMandatoryCalls(stub func(context.Context) (bool, error)) {
+	fake.getTOTPMandatoryMutex.Lock()
+	defer fake.getTOTPMandatoryMutex.Unlock()
+	fake.GetTOTPMandatoryStub = stub
+}
+
+func (fake *FakeRoomConfig) GetTOTPMandatoryArgsForCall(i int) context.Context {
+	fake.getTOTPMandatoryMutex.RLock()
+	defer fake.getTOTPMandatoryMutex.RUnlock()
+	argsForCall := fake.getTOTPMandatoryArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRoomConfig) GetTOTPMandatoryReturns(result1 bool, result2 error) {
+	fake.getTOTPMandatoryMutex.Lock()
+	defer fake.getTOTPMandatoryMutex.Unlock()
+	fake.GetTOTPMandatoryStub = nil
+	fake.getTOTPMandatoryReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRoomConfig) GetTOTPMandatoryReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.getTOTPMandatoryMutex.Lock()
+	defer fake.getTOTPMandatoryMutex.Unlock()
+	fake.GetTOTPMandatoryStub = nil
+	if fake.getTOTPMandatoryReturnsOnCall == nil {
+		fake.getTOTPMandatoryReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.getTOTPMandatoryReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRoomConfig) SetAliasPolicy(arg1 context.Context, arg2 roomdb.AliasPolicy) error {
+	fake.setAliasPolicyMutex.Lock()
+	ret, specificReturn := fake.setAliasPolicyReturnsOnCall[len(fake.setAliasPolicyArgsForCall)]
+	fake.setAliasPolicyArgsForCall = append(fake.setAliasPolicyArgsForCall, struct {
+		arg1 context.Context
+		arg2 roomdb.AliasPolicy
+	}{arg1, arg2})
+	stub := fake.SetAliasPolicyStub
+	fakeReturns := fake.setAliasPolicyReturns
+	fake.recordInvocation("SetAliasPolicy", []interface{}{arg1, arg2})
+	fake.setAliasPolicyMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRoomConfig) SetAliasPolicyCallCount() int {
+	fake.setAliasPolicyMutex.RLock()
+	defer fake.setAliasPolicyMutex.RUnlock()
+	return len(fake.setAliasPolicyArgsForCall)
+}
+
+func (fake *FakeRoomConfig) SetAliasPolicyCalls(stub func(context.Context, roomdb.AliasPolicy) error) {
+	fake.setAliasPolicyMutex.Lock()
+	defer fake.setAliasPolicyMutex.Unlock()
+	fake.SetAliasPolicyStub = stub
+}
+
+func (fake *FakeRoomConfig) SetAliasPolicyArgsForCall(i int) (context.Context, roomdb.AliasPolicy) {
+	fake.setAliasPolicyMutex.RLock()
+	defer fake.setAliasPolicyMutex.RUnlock()
+	argsForCall := fake.setAliasPolicyArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRoomConfig) SetAliasPolicyReturns(result1 error) {
+	fake.setAliasPolicyMutex.Lock()
+	defer fake.setAliasPolicyMutex.Unlock()
+	fake.SetAliasPolicyStub = nil
+	fake.setAliasPolicyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRoomConfig) SetAliasPolicyReturnsOnCall(i int, result1 error) {
+	fake.setAliasPolicyMutex.Lock()
+	defer fake.setAliasPolicyMutex.Unlock()
+	fake.SetAliasPolicyStub = nil
+	if fake.setAliasPolicyReturnsOnCall == nil {
+		fake.setAliasPolicyReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setAliasPolicyReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeRoomConfig) SetDefaultLanguage(arg1 context.Context, arg2 string) error {
 	fake.setDefaultLanguageMutex.Lock()
 	ret, specificReturn := fake.setDefaultLanguageReturnsOnCall[len(fake.setDefaultLanguageArgsForCall)]
@@ -257,6 +675,130 @@ func (fake *FakeRoomConfig) SetDefaultLanguageReturnsOnCall(i int, result1 error
 	}{result1}
 }
 
+func (fake *FakeRoomConfig) SetMemberInviteQuota(arg1 context.Context, arg2 uint) error {
+	fake.setMemberInviteQuotaMutex.Lock()
+	ret, specificReturn := fake.setMemberInviteQuotaReturnsOnCall[len(fake.setMemberInviteQuotaArgsForCall)]
+	fake.setMemberInviteQuotaArgsForCall = append(fake.setMemberInviteQuotaArgsForCall, struct {
+		arg1 context.Context
+		arg2 uint
+	}{arg1, arg2})
+	stub := fake.SetMemberInviteQuotaStub
+	fakeReturns := fake.setMemberInviteQuotaReturns
+	fake.recordInvocation("SetMemberInviteQuota", []interface{}{arg1, arg2})
+	fake.setMemberInviteQuotaMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRoomConfig) SetMemberInviteQuotaCallCount() int {
+	fake.setMemberInviteQuotaMutex.RLock()
+	defer fake.setMemberInviteQuotaMutex.RUnlock()
+	return len(fake.setMemberInviteQuotaArgsForCall)
+}
+
+func (fake *FakeRoomConfig) SetMemberInviteQuotaCalls(stub func(context.Context, uint) error) {
+	fake.setMemberInviteQuotaMutex.Lock()
+	defer fake.setMemberInviteQuotaMutex.Unlock()
+	fake.SetMemberInviteQuotaStub = stub
+}
+
+func (fake *FakeRoomConfig) SetMemberInviteQuotaArgsForCall(i int) (context.Context, uint) {
+	fake.setMemberInviteQuotaMutex.RLock()
+	defer fake.setMemberInviteQuotaMutex.RUnlock()
+	argsForCall := fake.setMemberInviteQuotaArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRoomConfig) SetMemberInviteQuotaReturns(result1 error) {
+	fake.setMemberInviteQuotaMutex.Lock()
+	defer fake.setMemberInviteQuotaMutex.Unlock()
+	fake.SetMemberInviteQuotaStub = nil
+	fake.setMemberInviteQuotaReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRoomConfig) SetMemberInviteQuotaReturnsOnCall(i int, result1 error) {
+	fake.setMemberInviteQuotaMutex.Lock()
+	defer fake.setMemberInviteQuotaMutex.Unlock()
+	fake.SetMemberInviteQuotaStub = nil
+	if fake.setMemberInviteQuotaReturnsOnCall == nil {
+		fake.setMemberInviteQuotaReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setMemberInviteQuotaReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRoomConfig) SetOpenInviteLimits(arg1 context.Context, arg2 roomdb.OpenInviteLimits) error {
+	fake.setOpenInviteLimitsMutex.Lock()
+	ret, specificReturn := fake.setOpenInviteLimitsReturnsOnCall[len(fake.setOpenInviteLimitsArgsForCall)]
+	fake.setOpenInviteLimitsArgsForCall = append(fake.setOpenInviteLimitsArgsForCall, struct {
+		arg1 context.Context
+		arg2 roomdb.OpenInviteLimits
+	}{arg1, arg2})
+	stub := fake.SetOpenInviteLimitsStub
+	fakeReturns := fake.setOpenInviteLimitsReturns
+	fake.recordInvocation("SetOpenInviteLimits", []interface{}{arg1, arg2})
+	fake.setOpenInviteLimitsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRoomConfig) SetOpenInviteLimitsCallCount() int {
+	fake.setOpenInviteLimitsMutex.RLock()
+	defer fake.setOpenInviteLimitsMutex.RUnlock()
+	return len(fake.setOpenInviteLimitsArgsForCall)
+}
+
+func (fake *FakeRoomConfig) SetOpenInviteLimitsCalls(stub func(context.Context, roomdb.OpenInviteLimits) error) {
+	fake.setOpenInviteLimitsMutex.Lock()
+	defer fake.setOpenInviteLimitsMutex.Unlock()
+	fake.SetOpenInviteLimitsStub = stub
+}
+
+func (fake *FakeRoomConfig) SetOpenInviteLimitsArgsForCall(i int) (context.Context, roomdb.OpenInviteLimits) {
+	fake.setOpenInviteLimitsMutex.RLock()
+	defer fake.setOpenInviteLimitsMutex.RUnlock()
+	argsForCall := fake.setOpenInviteLimitsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRoomConfig) SetOpenInviteLimitsReturns(result1 error) {
+	fake.setOpenInviteLimitsMutex.Lock()
+	defer fake.setOpenInviteLimitsMutex.Unlock()
+	fake.SetOpenInviteLimitsStub = nil
+	fake.setOpenInviteLimitsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRoomConfig) SetOpenInviteLimitsReturnsOnCall(i int, result1 error) {
+	fake.setOpenInviteLimitsMutex.Lock()
+	defer fake.setOpenInviteLimitsMutex.Unlock()
+	fake.SetOpenInviteLimitsStub = nil
+	if fake.setOpenInviteLimitsReturnsOnCall == nil {
+		fake.setOpenInviteLimitsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setOpenInviteLimitsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeRoomConfig) SetPrivacyMode(arg1 context.Context, arg2 roomdb.PrivacyMode) error {
 	fake.setPrivacyModeMutex.Lock()
 	ret, specificReturn := fake.setPrivacyModeReturnsOnCall[len(fake.setPrivacyModeArgsForCall)]
@@ -319,17 +861,95 @@ func (fake *FakeRoomConfig) SetPrivacyModeReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeRoomConfig) SetTOTPMandatory(arg1 context.Context, arg2 bool) error {
+	fake.setTOTPMandatoryMutex.Lock()
+	ret, specificReturn := fake.setTOTPMandatoryReturnsOnCall[len(fake.setTOTPMandatoryArgsForCall)]
+	fake.setTOTPMandatoryArgsForCall = append(fake.setTOTPMandatoryArgsForCall, struct {
+		arg1 context.Context
+		arg2 bool
+	}{arg1, arg2})
+	stub := fake.SetTOTPMandatoryStub
+	fakeReturns := fake.setTOTPMandatoryReturns
+	fake.recordInvocation("SetTOTPMandatory", []interface{}{arg1, arg2})
+	fake.setTOTPMandatoryMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRoomConfig) SetTOTPMandatoryCallCount() int {
+	fake.setTOTPMandatoryMutex.RLock()
+	defer fake.setTOTPMandatoryMutex.RUnlock()
+	return len(fake.setTOTPMandatoryArgsForCall)
+}
+
+func (fake *FakeRoomConfig) SetTOTPMandatoryCalls(stub func(context.Context, bool) error) {
+	fake.setTOTPMandatoryMutex.Lock()
+	defer fake.setTOTPMandatoryMutex.Unlock()
+	fake.SetTOTPMandatoryStub = stub
+}
+
+func (fake *FakeRoomConfig) SetTOTPMandatoryArgsForCall(i int) (context.Context, bool) {
+	fake.setTOTPMandatoryMutex.RLock()
+	defer fake.setTOTPMandatoryMutex.RUnlock()
+	argsForCall := fake.setTOTPMandatoryArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRoomConfig) SetTOTPMandatoryReturns(result1 error) {
+	fake.setTOTPMandatoryMutex.Lock()
+	defer fake.setTOTPMandatoryMutex.Unlock()
+	fake.SetTOTPMandatoryStub = nil
+	fake.setTOTPMandatoryReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRoomConfig) SetTOTPMandatoryReturnsOnCall(i int, result1 error) {
+	fake.setTOTPMandatoryMutex.Lock()
+	defer fake.setTOTPMandatoryMutex.Unlock()
+	fake.SetTOTPMandatoryStub = nil
+	if fake.setTOTPMandatoryReturnsOnCall == nil {
+		fake.setTOTPMandatoryReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setTOTPMandatoryReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeRoomConfig) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.getAliasPolicyMutex.RLock()
+	defer fake.getAliasPolicyMutex.RUnlock()
 	fake.getDefaultLanguageMutex.RLock()
 	defer fake.getDefaultLanguageMutex.RUnlock()
+	fake.getMemberInviteQuotaMutex.RLock()
+	defer fake.getMemberInviteQuotaMutex.RUnlock()
+	fake.getOpenInviteLimitsMutex.RLock()
+	defer fake.getOpenInviteLimitsMutex.RUnlock()
 	fake.getPrivacyModeMutex.RLock()
 	defer fake.getPrivacyModeMutex.RUnlock()
+	fake.getTOTPMandatoryMutex.RLock()
+	defer fake.getTOTPMandatoryMutex.RUnlock()
+	fake.setAliasPolicyMutex.RLock()
+	defer fake.setAliasPolicyMutex.RUnlock()
 	fake.setDefaultLanguageMutex.RLock()
 	defer fake.setDefaultLanguageMutex.RUnlock()
+	fake.setMemberInviteQuotaMutex.RLock()
+	defer fake.setMemberInviteQuotaMutex.RUnlock()
+	fake.setOpenInviteLimitsMutex.RLock()
+	defer fake.setOpenInviteLimitsMutex.RUnlock()
 	fake.setPrivacyModeMutex.RLock()
 	defer fake.setPrivacyModeMutex.RUnlock()
+	fake.setTOTPMandatoryMutex.RLock()
+	defer fake.setTOTPMandatoryMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -7,6 +7,7 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"fmt"
 
 	"github.com/friendsofgo/errors"
 	"github.com/mattn/go-sqlite3"
@@ -54,10 +55,26 @@ func (a Aliases) findOne(ctx context.Context, by qm.QueryMod) (roomdb.Alias, err
 	found.Name = entry.Name
 	found.Signature = entry.Signature
 	found.Feed = entry.R.Member.PubKey.FeedRef
+	found.TransferTo, err = aliasTransferTo(entry)
+	if err != nil {
+		return found, err
+	}
 
 	return found, nil
 }
 
+func aliasTransferTo(entry *models.Alias) (*refs.FeedRef, error) {
+	if entry.TransferTo == "" {
+		return nil, nil
+	}
+
+	feed, err := refs.ParseFeedRef(entry.TransferTo)
+	if err != nil {
+		return nil, fmt.Errorf("roomdb: invalid transfer feed of alias %d: %w", entry.ID, err)
+	}
+	return &feed, nil
+}
+
 // List returns a list of all registerd aliases
 func (a Aliases) List(ctx context.Context) ([]roomdb.Alias, error) {
 	all, err := models.Aliases(qm.Load("Member")).All(ctx, a.db)
@@ -73,6 +90,11 @@ func (a Aliases) List(ctx context.Context) ([]roomdb.Alias, error) {
 			Feed:      entry.R.Member.PubKey.FeedRef,
 			Signature: entry.Signature,
 		}
+
+		aliases[i].TransferTo, err = aliasTransferTo(entry)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return aliases, nil
@@ -90,6 +112,11 @@ func (a Aliases) Register(ctx context.Context, alias string, userFeed refs.FeedR
 			return err
 		}
 
+		err = checkAliasQuota(ctx, tx, memberEntry)
+		if err != nil {
+			return err
+		}
+
 		var newEntry models.Alias
 		newEntry.Name = alias
 		newEntry.MemberID = memberEntry.ID
@@ -104,6 +131,33 @@ func (a Aliases) Register(ctx context.Context, alias string, userFeed refs.FeedR
 	})
 }
 
+// checkAliasQuota returns ErrAliasQuota if the member can't have another alias
+func checkAliasQuota(ctx context.Context, tx *sql.Tx, memberEntry *models.Member) error {
+	// moderators and admins can have as many as they like
+	if roomdb.Role(memberEntry.Role) != roomdb.RoleMember {
+		return nil
+	}
+
+	config, err := models.FindConfig(ctx, tx, configRowID)
+	if err != nil {
+		return err
+	}
+
+	if config.AliasPerMember == 0 {
+		return nil
+	}
+
+	count, err := models.Aliases(qm.Where("member_id = ?", memberEntry.ID)).Count(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if count >= config.AliasPerMember {
+		return roomdb.ErrAliasQuota
+	}
+
+	return nil
+}
+
 // Revoke removes an alias from the system
 func (a Aliases) Revoke(ctx context.Context, alias string) error {
 	return transact(a.db, func(tx *sql.Tx) error {
@@ -121,3 +175,82 @@ func (a Aliases) Revoke(ctx context.Context, alias string) error {
 		return err
 	})
 }
+
+// AuthorizeTransfer allows the feed to to take over the alias, replacing an earlier authorization.
+func (a Aliases) AuthorizeTransfer(ctx context.Context, alias string, to refs.FeedRef) error {
+	return transact(a.db, func(tx *sql.Tx) error {
+		entry, err := models.Aliases(qm.Where("name = ?", alias)).One(ctx, tx)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return roomdb.ErrNotFound
+			}
+			return err
+		}
+
+		// only members can own aliases
+		_, err = models.Members(qm.Where("pub_key = ?", to.String())).One(ctx, tx)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return roomdb.ErrNotFound
+			}
+			return err
+		}
+
+		entry.TransferTo = to.String()
+		_, err = entry.Update(ctx, tx, boil.Infer())
+		return err
+	})
+}
+
+// CancelTransfer removes a pending transfer of the alias, if there is one.
+func (a Aliases) CancelTransfer(ctx context.Context, alias string) error {
+	return transact(a.db, func(tx *sql.Tx) error {
+		entry, err := models.Aliases(qm.Where("name = ?", alias)).One(ctx, tx)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return roomdb.ErrNotFound
+			}
+			return err
+		}
+
+		entry.TransferTo = ""
+		_, err = entry.Update(ctx, tx, boil.Infer())
+		return err
+	})
+}
+
+// Transfer hands the alias over to newOwner, with the signature of its new confirmation, in one step.
+func (a Aliases) Transfer(ctx context.Context, alias string, newOwner refs.FeedRef, signature []byte) error {
+	return transact(a.db, func(tx *sql.Tx) error {
+		entry, err := models.Aliases(qm.Where("name = ?", alias)).One(ctx, tx)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return roomdb.ErrNotFound
+			}
+			return err
+		}
+
+		if entry.TransferTo != newOwner.String() {
+			return roomdb.ErrAliasTransferNotAuthorized
+		}
+
+		memberEntry, err := models.Members(qm.Where("pub_key = ?", newOwner.String())).One(ctx, tx)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return roomdb.ErrNotFound
+			}
+			return err
+		}
+
+		err = checkAliasQuota(ctx, tx, memberEntry)
+		if err != nil {
+			return err
+		}
+
+		entry.MemberID = memberEntry.ID
+		entry.Signature = signature
+		entry.TransferTo = ""
+		_, err = entry.Update(ctx, tx, boil.Infer())
+		return err
+	})
+}
@@ -144,3 +144,126 @@ func TestAliasesUniqueError(t *testing.T) {
 	r.True(errors.As(err, &takenErr), "expected a special error value")
 	r.Equal(testName, takenErr.Name)
 }
+
+func TestAliasesPerMemberLimit(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	testRepo := filepath.Join("testrun", t.Name())
+	os.RemoveAll(testRepo)
+	tr := repo.New(testRepo)
+
+	db, err := Open(tr)
+	r.NoError(err)
+
+	member, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte("memb"), 8), refs.RefAlgoFeedSSB1)
+	r.NoError(err)
+	_, err = db.Members.Add(ctx, member, roomdb.RoleMember)
+	r.NoError(err)
+
+	moderator, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte("modr"), 8), refs.RefAlgoFeedSSB1)
+	r.NoError(err)
+	_, err = db.Members.Add(ctx, moderator, roomdb.RoleModerator)
+	r.NoError(err)
+
+	err = db.Config.SetAliasPolicy(ctx, roomdb.AliasPolicy{PerMember: 1})
+	r.NoError(err)
+
+	testSig := bytes.Repeat([]byte("s"), 64)
+
+	r.NoError(db.Aliases.Register(ctx, "first", member, testSig))
+
+	err = db.Aliases.Register(ctx, "second", member, testSig)
+	r.ErrorIs(err, roomdb.ErrAliasQuota)
+
+	// moderators aren't limited
+	r.NoError(db.Aliases.Register(ctx, "third", moderator, testSig))
+	r.NoError(db.Aliases.Register(ctx, "fourth", moderator, testSig))
+
+	// after revoking one, there is room for another
+	r.NoError(db.Aliases.Revoke(ctx, "first"))
+	r.NoError(db.Aliases.Register(ctx, "second", member, testSig))
+}
+
+func TestAliasesTransfer(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	testRepo := filepath.Join("testrun", t.Name())
+	os.RemoveAll(testRepo)
+	tr := repo.New(testRepo)
+
+	db, err := Open(tr)
+	r.NoError(err)
+
+	oldFeed, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte("oldf"), 8), refs.RefAlgoFeedSSB1)
+	r.NoError(err)
+	_, err = db.Members.Add(ctx, oldFeed, roomdb.RoleMember)
+	r.NoError(err)
+
+	newFeed, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte("newf"), 8), refs.RefAlgoFeedSSB1)
+	r.NoError(err)
+
+	oldSig := bytes.Repeat([]byte("o"), 64)
+	newSig := bytes.Repeat([]byte("n"), 64)
+
+	r.NoError(db.Aliases.Register(ctx, "moving", oldFeed, oldSig))
+
+	// the new feed needs to be a member first
+	err = db.Aliases.AuthorizeTransfer(ctx, "moving", newFeed)
+	r.ErrorIs(err, roomdb.ErrNotFound)
+
+	_, err = db.Members.Add(ctx, newFeed, roomdb.RoleMember)
+	r.NoError(err)
+
+	err = db.Aliases.AuthorizeTransfer(ctx, "unknown", newFeed)
+	r.ErrorIs(err, roomdb.ErrNotFound)
+
+	// can't take it without the authorization
+	err = db.Aliases.Transfer(ctx, "moving", newFeed, newSig)
+	r.ErrorIs(err, roomdb.ErrAliasTransferNotAuthorized)
+
+	r.NoError(db.Aliases.AuthorizeTransfer(ctx, "moving", newFeed))
+
+	alias, err := db.Aliases.Resolve(ctx, "moving")
+	r.NoError(err)
+	r.NotNil(alias.TransferTo)
+	r.True(alias.TransferTo.Equal(newFeed))
+	r.True(alias.Feed.Equal(oldFeed), "still owned by the old feed")
+
+	// cancelling it stops the transfer
+	r.NoError(db.Aliases.CancelTransfer(ctx, "moving"))
+	err = db.Aliases.Transfer(ctx, "moving", newFeed, newSig)
+	r.ErrorIs(err, roomdb.ErrAliasTransferNotAuthorized)
+
+	r.NoError(db.Aliases.AuthorizeTransfer(ctx, "moving", newFeed))
+
+	// only the authorized feed can take it
+	err = db.Aliases.Transfer(ctx, "moving", oldFeed, newSig)
+	r.ErrorIs(err, roomdb.ErrAliasTransferNotAuthorized)
+
+	// the new owner is subject to the per member limit
+	r.NoError(db.Aliases.Register(ctx, "already", newFeed, newSig))
+	r.NoError(db.Config.SetAliasPolicy(ctx, roomdb.AliasPolicy{PerMember: 1}))
+
+	err = db.Aliases.Transfer(ctx, "moving", newFeed, newSig)
+	r.ErrorIs(err, roomdb.ErrAliasQuota)
+
+	alias, err = db.Aliases.Resolve(ctx, "moving")
+	r.NoError(err)
+	r.True(alias.Feed.Equal(oldFeed), "a failed transfer shouldn't change the owner")
+	r.Equal(oldSig, alias.Signature)
+
+	r.NoError(db.Config.SetAliasPolicy(ctx, roomdb.AliasPolicy{}))
+	r.NoError(db.Aliases.Transfer(ctx, "moving", newFeed, newSig))
+
+	alias, err = db.Aliases.Resolve(ctx, "moving")
+	r.NoError(err)
+	r.True(alias.Feed.Equal(newFeed))
+	r.Equal(newSig, alias.Signature)
+	r.Nil(alias.TransferTo)
+
+	// the authorization is used up
+	err = db.Aliases.Transfer(ctx, "moving", newFeed, newSig)
+	r.ErrorIs(err, roomdb.ErrAliasTransferNotAuthorized)
+}
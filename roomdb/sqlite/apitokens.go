@@ -0,0 +1,218 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package sqlite
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/mattn/go-sqlite3"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/roomdb/sqlite/models"
+)
+
+// compiler assertion to ensure the struct fullfills the interface
+var _ roomdb.APITokensService = (*APITokens)(nil)
+
+const apiTokenLength = 32
+
+// APITokens stores the personal api tokens in the api_tokens table.
+// Like with invites, only the sha256 of the token is stored.
+type APITokens struct {
+	db *sql.DB
+}
+
+// Create stores a new token of the member and returns it, base64 URL encoded.
+func (at APITokens) Create(ctx context.Context, memberID int64, name string, scopes []roomdb.APITokenScope, expiresAt time.Time) (string, error) {
+	if len(scopes) == 0 {
+		return "", fmt.Errorf("roomdb: api token needs at least one scope")
+	}
+
+	scopeStrs := make([]string, len(scopes))
+	for i, s := range scopes {
+		if err := s.IsValid(); err != nil {
+			return "", err
+		}
+		scopeStrs[i] = string(s)
+	}
+
+	if !expiresAt.After(time.Now()) {
+		return "", fmt.Errorf("roomdb: api token would already be expired")
+	}
+
+	var newToken = models.APIToken{
+		MemberID:  memberID,
+		Name:      name,
+		Scopes:    strings.Join(scopeStrs, " "),
+		ExpiresAt: expiresAt,
+	}
+
+	tokenBytes := make([]byte, apiTokenLength)
+
+	err := transact(at.db, func(tx *sql.Tx) error {
+		// check the member is registerd
+		if _, err := models.FindMember(ctx, tx, memberID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return roomdb.ErrNotFound
+			}
+			return err
+		}
+
+		cols := boil.Whitelist(
+			models.APITokenColumns.MemberID,
+			models.APITokenColumns.Name,
+			models.APITokenColumns.HashedToken,
+			models.APITokenColumns.Scopes,
+			models.APITokenColumns.ExpiresAt,
+		)
+
+		for tries := 100; tries > 0; tries-- {
+			rand.Read(tokenBytes)
+
+			// hash the binary of the token for storage
+			h := sha256.New()
+			h.Write(tokenBytes)
+			newToken.HashedToken = fmt.Sprintf("%x", h.Sum(nil))
+
+			err := newToken.Insert(ctx, tx, cols)
+			if err != nil {
+				var sqlErr sqlite3.Error
+				if errors.As(err, &sqlErr) && sqlErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+					// generated an existing token, retry
+					continue
+				}
+				return err
+			}
+			return nil
+		}
+
+		return errors.New("roomdb: failed to generate an api token in a reasonable amount of time")
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return base64.URLEncoding.EncodeToString(tokenBytes), nil
+}
+
+// CheckToken returns the token if it exists and didn't expire yet, and updates when it was used last.
+func (at APITokens) CheckToken(ctx context.Context, token string) (roomdb.APIToken, error) {
+	var t roomdb.APIToken
+
+	hashedToken, err := getHashedAPIToken(token)
+	if err != nil {
+		return t, roomdb.ErrNotFound
+	}
+
+	err = transact(at.db, func(tx *sql.Tx) error {
+		entry, err := models.APITokens(qm.Where("hashed_token = ?", hashedToken)).One(ctx, tx)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return roomdb.ErrNotFound
+			}
+			return err
+		}
+
+		t = apiTokenFromModel(entry)
+		if t.Expired() {
+			return roomdb.ErrNotFound
+		}
+
+		entry.LastUsedAt = time.Now()
+		_, err = entry.Update(ctx, tx, boil.Whitelist(models.APITokenColumns.LastUsedAt))
+		if err != nil {
+			return err
+		}
+		t.LastUsedAt = entry.LastUsedAt
+
+		return nil
+	})
+	if err != nil {
+		return roomdb.APIToken{}, err
+	}
+
+	return t, nil
+}
+
+// List returns the tokens of a member, the newest first.
+func (at APITokens) List(ctx context.Context, memberID int64) ([]roomdb.APIToken, error) {
+	all, err := models.APITokens(
+		qm.Where("member_id = ?", memberID),
+		qm.OrderBy("id DESC"),
+	).All(ctx, at.db)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]roomdb.APIToken, len(all))
+	for i, entry := range all {
+		tokens[i] = apiTokenFromModel(entry)
+	}
+
+	return tokens, nil
+}
+
+// Revoke deletes the token with that id, if it belongs to the member.
+func (at APITokens) Revoke(ctx context.Context, memberID, id int64) error {
+	n, err := models.APITokens(qm.Where("id = ? AND member_id = ?", id, memberID)).DeleteAll(ctx, at.db)
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return roomdb.ErrNotFound
+	}
+
+	return nil
+}
+
+// RevokeAll deletes all the tokens of the member.
+func (at APITokens) RevokeAll(ctx context.Context, memberID int64) error {
+	_, err := models.APITokens(qm.Where("member_id = ?", memberID)).DeleteAll(ctx, at.db)
+	return err
+}
+
+func apiTokenFromModel(entry *models.APIToken) roomdb.APIToken {
+	var scopes []roomdb.APITokenScope
+	for _, s := range strings.Fields(entry.Scopes) {
+		scopes = append(scopes, roomdb.APITokenScope(s))
+	}
+
+	return roomdb.APIToken{
+		ID:         entry.ID,
+		MemberID:   entry.MemberID,
+		Name:       entry.Name,
+		Scopes:     scopes,
+		CreatedAt:  entry.CreatedAt,
+		ExpiresAt:  entry.ExpiresAt,
+		LastUsedAt: entry.LastUsedAt,
+	}
+}
+
+func getHashedAPIToken(b64tok string) (string, error) {
+	tokenBytes, err := base64.URLEncoding.DecodeString(b64tok)
+	if err != nil {
+		return "", err
+	}
+
+	if n := len(tokenBytes); n != apiTokenLength {
+		return "", fmt.Errorf("roomdb: invalid api token length (only got %d bytes)", n)
+	}
+
+	// hash the binary of the passed token
+	h := sha256.New()
+	h.Write(tokenBytes)
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package sqlite
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	refs "github.com/ssbc/go-ssb-refs"
+	"github.com/ssbc/go-ssb-room/v2/internal/repo"
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPITokens(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	testRepo := filepath.Join("testrun", t.Name())
+	os.RemoveAll(testRepo)
+	tr := repo.New(testRepo)
+
+	db, err := Open(tr)
+	r.NoError(err, "failed to open database")
+
+	alf, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte("alf!"), 8), refs.RefAlgoFeedSSB1)
+	r.NoError(err)
+	bre, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte("bre!"), 8), refs.RefAlgoFeedSSB1)
+	r.NoError(err)
+
+	alfID, err := db.Members.Add(ctx, alf, roomdb.RoleAdmin)
+	r.NoError(err)
+	breID, err := db.Members.Add(ctx, bre, roomdb.RoleModerator)
+	r.NoError(err)
+
+	nextWeek := time.Now().Add(7 * 24 * time.Hour)
+
+	// invalid ones
+	_, err = db.APITokens.Create(ctx, alfID, "nothing", nil, nextWeek)
+	r.Error(err, "no scopes")
+	_, err = db.APITokens.Create(ctx, alfID, "bogus", []roomdb.APITokenScope{"everything:please"}, nextWeek)
+	r.Error(err, "unknown scope")
+	_, err = db.APITokens.Create(ctx, alfID, "past", []roomdb.APITokenScope{roomdb.APIScopeMembersRead}, time.Now().Add(-time.Minute))
+	r.Error(err, "already expired")
+	_, err = db.APITokens.Create(ctx, 9999, "nobody", []roomdb.APITokenScope{roomdb.APIScopeMembersRead}, nextWeek)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "unexpected error: %v", err)
+
+	tok, err := db.APITokens.Create(ctx, alfID, "invite bot", []roomdb.APITokenScope{roomdb.APIScopeInvitesCreate, roomdb.APIScopeInvitesRead}, nextWeek)
+	r.NoError(err)
+	r.NotEqual("", tok)
+
+	// the token itself is not stored
+	var count int
+	r.NoError(db.db.QueryRow("SELECT count(*) FROM api_tokens WHERE hashed_token = ?", tok).Scan(&count))
+	r.Equal(0, count)
+
+	checked, err := db.APITokens.CheckToken(ctx, tok)
+	r.NoError(err)
+	r.Equal(alfID, checked.MemberID)
+	r.Equal("invite bot", checked.Name)
+	r.True(checked.HasScope(roomdb.APIScopeInvitesCreate))
+	r.True(checked.HasScope(roomdb.APIScopeInvitesRead))
+	r.False(checked.HasScope(roomdb.APIScopeMembersWrite))
+	r.False(checked.Expired())
+
+	_, err = db.APITokens.CheckToken(ctx, "not-a-token")
+	r.True(errors.Is(err, roomdb.ErrNotFound), "unexpected error: %v", err)
+
+	// expired tokens don't work anymore but are still listed
+	_, err = db.db.Exec("UPDATE api_tokens SET expires_at = ? WHERE id = ?", time.Now().Add(-time.Hour), checked.ID)
+	r.NoError(err)
+	_, err = db.APITokens.CheckToken(ctx, tok)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "unexpected error: %v", err)
+
+	tok2, err := db.APITokens.Create(ctx, alfID, "reports", []roomdb.APITokenScope{roomdb.APIScopeMembersRead}, nextWeek)
+	r.NoError(err)
+	r.NotEqual(tok, tok2)
+
+	lst, err := db.APITokens.List(ctx, alfID)
+	r.NoError(err)
+	r.Len(lst, 2)
+	r.Equal("reports", lst[0].Name, "newest first")
+	r.True(lst[1].Expired())
+	reportsID := lst[0].ID
+
+	lst, err = db.APITokens.List(ctx, breID)
+	r.NoError(err)
+	r.Len(lst, 0)
+
+	// only the owner can revoke
+	err = db.APITokens.Revoke(ctx, breID, reportsID)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "unexpected error: %v", err)
+
+	r.NoError(db.APITokens.Revoke(ctx, alfID, reportsID))
+	_, err = db.APITokens.CheckToken(ctx, tok2)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "unexpected error: %v", err)
+
+	// revoking all of them leaves the ones of other members alone
+	tok3, err := db.APITokens.Create(ctx, alfID, "stats", []roomdb.APITokenScope{roomdb.APIScopeStatsRead}, nextWeek)
+	r.NoError(err)
+	breTok, err := db.APITokens.Create(ctx, breID, "stats", []roomdb.APITokenScope{roomdb.APIScopeStatsRead}, nextWeek)
+	r.NoError(err)
+	r.NoError(db.APITokens.RevokeAll(ctx, alfID))
+	_, err = db.APITokens.CheckToken(ctx, tok3)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "unexpected error: %v", err)
+	_, err = db.APITokens.CheckToken(ctx, breTok)
+	r.NoError(err)
+
+	// removing the member removes the tokens
+	r.NoError(db.Members.RemoveID(ctx, alfID))
+	lst, err = db.APITokens.List(ctx, alfID)
+	r.NoError(err)
+	r.Len(lst, 0)
+
+	r.NoError(db.Close())
+}
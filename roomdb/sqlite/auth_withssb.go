@@ -7,6 +7,7 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"sort"
 	"time"
 
 	"github.com/friendsofgo/errors"
@@ -84,12 +85,32 @@ func (a AuthWithSSB) CreateToken(ctx context.Context, memberID int64) (string, e
 
 const sessionTimeout = time.Hour * 24
 
+// lastUsedResolution is how precise the last use of a session is tracked
+const lastUsedResolution = time.Minute
+
+// maxUserAgentLength limits how much of the user agent of a session is stored
+const maxUserAgentLength = 256
+
 // CheckToken checks if the passed token is still valid and returns the member id if so
 func (a AuthWithSSB) CheckToken(ctx context.Context, token string) (int64, error) {
+	return a.checkToken(ctx, token, false)
+}
+
+// CheckDeviceToken is like CheckToken but only accepts the sessions that were marked with MarkDevice
+func (a AuthWithSSB) CheckDeviceToken(ctx context.Context, token string) (int64, error) {
+	return a.checkToken(ctx, token, true)
+}
+
+func (a AuthWithSSB) checkToken(ctx context.Context, token string, deviceOnly bool) (int64, error) {
 	var memberID int64
 
 	err := transact(a.db, func(tx *sql.Tx) error {
-		session, err := models.SIWSSBSessions(qm.Where("token = ?", token)).One(ctx, a.db)
+		mods := []qm.QueryMod{qm.Where("token = ?", token)}
+		if deviceOnly {
+			mods = append(mods, qm.Where("device = ?", true))
+		}
+
+		session, err := models.SIWSSBSessions(mods...).One(ctx, a.db)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				return roomdb.ErrNotFound
@@ -106,6 +127,15 @@ func (a AuthWithSSB) CheckToken(ctx context.Context, token string) (int64, error
 			return errors.New("sign-in with ssb: session expired")
 		}
 
+		// only update the last use once in a while, this is checked on every request
+		if time.Since(session.LastUsedAt) > lastUsedResolution {
+			session.LastUsedAt = time.Now()
+			_, err = session.Update(ctx, tx, boil.Whitelist(models.SIWSSBSessionColumns.LastUsedAt))
+			if err != nil {
+				return err
+			}
+		}
+
 		memberID = session.MemberID
 		return nil
 	})
@@ -135,3 +165,101 @@ func (a AuthWithSSB) WipeTokensForMember(ctx context.Context, memberID int64) er
 		return nil
 	})
 }
+
+// MarkDevice records that the session was handed to a device by the device sign-in
+func (a AuthWithSSB) MarkDevice(ctx context.Context, token string) error {
+	n, err := models.SIWSSBSessions(qm.Where("token = ?", token)).UpdateAll(ctx, a.db, models.M{
+		models.SIWSSBSessionColumns.Device: true,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return roomdb.ErrNotFound
+	}
+	return nil
+}
+
+// SetClientDetails records the user agent and address of the browser that holds the token
+func (a AuthWithSSB) SetClientDetails(ctx context.Context, token, userAgent, remoteAddr string) error {
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	return transact(a.db, func(tx *sql.Tx) error {
+		session, err := models.SIWSSBSessions(qm.Where("token = ?", token)).One(ctx, tx)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return roomdb.ErrNotFound
+			}
+			return err
+		}
+
+		session.UserAgent = userAgent
+		session.RemoteAddr = remoteAddr
+		session.LastUsedAt = time.Now()
+
+		cols := boil.Whitelist(
+			models.SIWSSBSessionColumns.UserAgent,
+			models.SIWSSBSessionColumns.RemoteAddr,
+			models.SIWSSBSessionColumns.LastUsedAt,
+		)
+		_, err = session.Update(ctx, tx, cols)
+		return err
+	})
+}
+
+// ListSessions returns the sessions of a member, the most recently used first.
+// Expired sessions are not included.
+func (a AuthWithSSB) ListSessions(ctx context.Context, memberID int64) ([]roomdb.SIWSSBSession, error) {
+	entries, err := models.SIWSSBSessions(qm.Where("member_id = ?", memberID)).All(ctx, a.db)
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions = make([]roomdb.SIWSSBSession, 0, len(entries))
+	for _, entry := range entries {
+		if time.Since(entry.CreatedAt) > sessionTimeout {
+			continue
+		}
+
+		sessions = append(sessions, roomdb.SIWSSBSession{
+			ID:       entry.ID,
+			MemberID: entry.MemberID,
+
+			CreatedAt:  entry.CreatedAt,
+			LastUsedAt: entry.LastUsedAt,
+
+			UserAgent:  entry.UserAgent,
+			RemoteAddr: entry.RemoteAddr,
+
+			Device: entry.Device,
+		})
+	}
+
+	// sorted here since the timestamps in the table don't all have the same format
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastUsedAt.After(sessions[j].LastUsedAt)
+	})
+
+	return sessions, nil
+}
+
+// RemoveSession ends a single session of a member.
+// It returns ErrNotFound if the member doesn't have a session with that id.
+func (a AuthWithSSB) RemoveSession(ctx context.Context, memberID, sessionID int64) error {
+	return transact(a.db, func(tx *sql.Tx) error {
+		session, err := models.SIWSSBSessions(
+			qm.Where("id = ? AND member_id = ?", sessionID, memberID),
+		).One(ctx, tx)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return roomdb.ErrNotFound
+			}
+			return err
+		}
+
+		_, err = session.Delete(ctx, tx)
+		return err
+	})
+}
@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package sqlite
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	refs "github.com/ssbc/go-ssb-refs"
+	"github.com/ssbc/go-ssb-room/v2/internal/repo"
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthWithSSBSessions(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	testRepo := filepath.Join("testrun", t.Name())
+	os.RemoveAll(testRepo)
+	tr := repo.New(testRepo)
+
+	db, err := Open(tr)
+	r.NoError(err, "failed to open database")
+
+	// fake feeds for testing, looks ok at least
+	alf, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte("alf!"), 8), refs.RefAlgoFeedSSB1)
+	r.NoError(err)
+	bre, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte("bre!"), 8), refs.RefAlgoFeedSSB1)
+	r.NoError(err)
+
+	alfID, err := db.Members.Add(ctx, alf, roomdb.RoleMember)
+	r.NoError(err)
+	breID, err := db.Members.Add(ctx, bre, roomdb.RoleMember)
+	r.NoError(err)
+
+	// no sessions yet
+	sessions, err := db.AuthWithSSB.ListSessions(ctx, alfID)
+	r.NoError(err)
+	r.Len(sessions, 0)
+
+	// two for alf, one for bre
+	alfsPhone, err := db.AuthWithSSB.CreateToken(ctx, alfID)
+	r.NoError(err)
+	alfsLaptop, err := db.AuthWithSSB.CreateToken(ctx, alfID)
+	r.NoError(err)
+	bresToken, err := db.AuthWithSSB.CreateToken(ctx, breID)
+	r.NoError(err)
+
+	err = db.AuthWithSSB.SetClientDetails(ctx, alfsPhone, "Phone Browser/1.0", "203.0.113.0")
+	r.NoError(err)
+	err = db.AuthWithSSB.SetClientDetails(ctx, alfsLaptop, "Laptop Browser/2.0", "2001:db8:1::")
+	r.NoError(err)
+
+	err = db.AuthWithSSB.SetClientDetails(ctx, "not-a-token", "Some Browser", "")
+	r.ErrorIs(err, roomdb.ErrNotFound)
+
+	sessions, err = db.AuthWithSSB.ListSessions(ctx, alfID)
+	r.NoError(err)
+	r.Len(sessions, 2)
+
+	var laptopSession roomdb.SIWSSBSession
+	for _, s := range sessions {
+		r.Equal(alfID, s.MemberID)
+		if s.UserAgent == "Laptop Browser/2.0" {
+			laptopSession = s
+		}
+	}
+	r.NotZero(laptopSession.ID, "laptop session not found")
+	r.Equal("2001:db8:1::", laptopSession.RemoteAddr)
+
+	// bre can't end alfs session
+	err = db.AuthWithSSB.RemoveSession(ctx, breID, laptopSession.ID)
+	r.ErrorIs(err, roomdb.ErrNotFound)
+
+	// alf can
+	err = db.AuthWithSSB.RemoveSession(ctx, alfID, laptopSession.ID)
+	r.NoError(err)
+
+	_, err = db.AuthWithSSB.CheckToken(ctx, alfsLaptop)
+	r.Error(err, "laptop session should be gone")
+
+	gotID, err := db.AuthWithSSB.CheckToken(ctx, alfsPhone)
+	r.NoError(err, "phone session should still work")
+	r.Equal(alfID, gotID)
+
+	gotID, err = db.AuthWithSSB.CheckToken(ctx, bresToken)
+	r.NoError(err)
+	r.Equal(breID, gotID)
+
+	sessions, err = db.AuthWithSSB.ListSessions(ctx, alfID)
+	r.NoError(err)
+	r.Len(sessions, 1)
+	r.Equal("Phone Browser/1.0", sessions[0].UserAgent)
+	r.False(sessions[0].Device)
+
+	// only device sessions pass the device check
+	_, err = db.AuthWithSSB.CheckDeviceToken(ctx, alfsPhone)
+	r.ErrorIs(err, roomdb.ErrNotFound)
+
+	err = db.AuthWithSSB.MarkDevice(ctx, alfsPhone)
+	r.NoError(err)
+	err = db.AuthWithSSB.MarkDevice(ctx, "not-a-token")
+	r.ErrorIs(err, roomdb.ErrNotFound)
+
+	gotID, err = db.AuthWithSSB.CheckDeviceToken(ctx, alfsPhone)
+	r.NoError(err)
+	r.Equal(alfID, gotID)
+
+	gotID, err = db.AuthWithSSB.CheckToken(ctx, alfsPhone)
+	r.NoError(err, "device sessions still work as normal sessions")
+	r.Equal(alfID, gotID)
+
+	_, err = db.AuthWithSSB.CheckDeviceToken(ctx, bresToken)
+	r.ErrorIs(err, roomdb.ErrNotFound)
+
+	sessions, err = db.AuthWithSSB.ListSessions(ctx, alfID)
+	r.NoError(err)
+	r.Len(sessions, 1)
+	r.True(sessions[0].Device)
+
+	r.NoError(db.Close())
+}
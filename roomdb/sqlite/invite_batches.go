@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/roomdb/sqlite/models"
+)
+
+// CreateBatch creates count plain invites that belong to a new batch, all in one transaction.
+func (i Invites) CreateBatch(ctx context.Context, createdBy int64, name string, count uint, expiresAt time.Time) (roomdb.InviteBatch, []string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return roomdb.InviteBatch{}, nil, fmt.Errorf("roomdb: invite batches need a name")
+	}
+
+	if count == 0 || count > roomdb.InviteBatchMaxSize {
+		return roomdb.InviteBatch{}, nil, fmt.Errorf("roomdb: invalid number of invites for a batch: %d", count)
+	}
+
+	if !expiresAt.After(time.Now()) {
+		return roomdb.InviteBatch{}, nil, fmt.Errorf("roomdb: invite batch would already be expired")
+	}
+
+	var (
+		batch  models.InviteBatch
+		tokens = make([]string, count)
+	)
+
+	err := transact(i.db, func(tx *sql.Tx) error {
+		batch = models.InviteBatch{
+			Name:      name,
+			CreatedBy: createdBy,
+			ExpiresAt: expiresAt.UTC(),
+		}
+
+		err := batch.Insert(ctx, tx, boil.Infer())
+		if err != nil {
+			return err
+		}
+
+		for idx := range tokens {
+			tokens[idx], err = i.create(ctx, tx, createdBy, roomdb.InviteOptions{}, inviteOrigin{batchID: batch.ID})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return roomdb.InviteBatch{}, nil, err
+	}
+
+	b := toInviteBatch(&batch)
+	b.Unused = int(count)
+	return b, tokens, nil
+}
+
+// ListBatches returns the batches that still have invites which can be used, the newest first.
+func (i Invites) ListBatches(ctx context.Context) ([]roomdb.InviteBatch, error) {
+	var lst []roomdb.InviteBatch
+
+	err := transact(i.db, func(tx *sql.Tx) error {
+		entries, err := models.InviteBatches(
+			qm.Where("expires_at > ?", time.Now().UTC()),
+			qm.OrderBy("created_at DESC, id DESC"),
+		).All(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		for _, e := range entries {
+			b, err := i.countUnused(ctx, tx, e)
+			if err != nil {
+				return err
+			}
+
+			if b.Unused == 0 {
+				continue
+			}
+			lst = append(lst, b)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return lst, nil
+}
+
+// GetBatch returns the batch with that id, or ErrNotFound
+func (i Invites) GetBatch(ctx context.Context, id int64) (roomdb.InviteBatch, error) {
+	entry, err := models.FindInviteBatch(ctx, i.db, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return roomdb.InviteBatch{}, roomdb.ErrNotFound
+		}
+		return roomdb.InviteBatch{}, err
+	}
+
+	return i.countUnused(ctx, i.db, entry)
+}
+
+// RevokeBatch invalidates all the unused invites of the batch.
+func (i Invites) RevokeBatch(ctx context.Context, id int64) error {
+	return transact(i.db, func(tx *sql.Tx) error {
+		exists, err := models.InviteBatchExists(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return roomdb.ErrNotFound
+		}
+
+		_, err = models.Invites(
+			qm.Where("active = true AND batch_id = ?", id),
+		).UpdateAll(ctx, tx, models.M{models.InviteColumns.Active: false})
+		return err
+	})
+}
+
+// countUnused returns the batch with the number of its invites that can still be used
+func (i Invites) countUnused(ctx context.Context, exec boil.ContextExecutor, entry *models.InviteBatch) (roomdb.InviteBatch, error) {
+	b := toInviteBatch(entry)
+
+	unused, err := models.Invites(
+		usableInvites(),
+		qm.Where("batch_id = ?", entry.ID),
+	).Count(ctx, exec)
+	if err != nil {
+		return roomdb.InviteBatch{}, err
+	}
+	b.Unused = int(unused)
+
+	return b, nil
+}
+
+func toInviteBatch(entry *models.InviteBatch) roomdb.InviteBatch {
+	return roomdb.InviteBatch{
+		ID:        entry.ID,
+		Name:      entry.Name,
+		CreatedBy: entry.CreatedBy,
+		CreatedAt: entry.CreatedAt,
+		ExpiresAt: entry.ExpiresAt,
+	}
+}
+
+// expireInviteBatches is called by the scrubber of Open. It deactivates the invites of expired batches,
+// so that deleteRevokedInvites can remove them, and deletes the batches that have no invites left.
+func expireInviteBatches(tx boil.ContextExecutor) error {
+	ctx := context.Background()
+
+	_, err := models.Invites(
+		qm.Where("active = true AND batch_id IN (SELECT id FROM invite_batches WHERE expires_at <= ?)", time.Now().UTC()),
+	).UpdateAll(ctx, tx, models.M{models.InviteColumns.Active: false})
+	if err != nil {
+		return fmt.Errorf("roomdb: failed to expire invite batches: %w", err)
+	}
+
+	_, err = models.InviteBatches(
+		qm.Where("id NOT IN (SELECT batch_id FROM invites)"),
+	).DeleteAll(ctx, tx)
+	if err != nil {
+		return fmt.Errorf("roomdb: failed to delete empty invite batches: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package sqlite
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	refs "github.com/ssbc/go-ssb-refs"
+	"github.com/ssbc/go-ssb-room/v2/internal/repo"
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+)
+
+func TestInviteBatches(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	testRepo := filepath.Join("testrun", t.Name())
+	os.RemoveAll(testRepo)
+	tr := repo.New(testRepo)
+
+	db, err := Open(tr)
+	r.NoError(err, "failed to open database")
+
+	newFeed := func(b string) refs.FeedRef {
+		feed, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte(b), 8), refs.RefAlgoFeedSSB1)
+		r.NoError(err)
+		return feed
+	}
+	admin, alf, bob := newFeed("adm!"), newFeed("alf!"), newFeed("bob!")
+
+	adminID, err := db.Members.Add(ctx, admin, roomdb.RoleAdmin)
+	r.NoError(err)
+
+	inAWeek := time.Now().Add(7 * 24 * time.Hour)
+
+	_, _, err = db.Invites.CreateBatch(ctx, adminID, "  ", 3, inAWeek)
+	r.Error(err, "batches need a name")
+	_, _, err = db.Invites.CreateBatch(ctx, adminID, "workshop", 0, inAWeek)
+	r.Error(err, "empty batch")
+	_, _, err = db.Invites.CreateBatch(ctx, adminID, "workshop", roomdb.InviteBatchMaxSize+1, inAWeek)
+	r.Error(err, "too many invites")
+	_, _, err = db.Invites.CreateBatch(ctx, adminID, "workshop", 3, time.Now().Add(-time.Minute))
+	r.Error(err, "already expired")
+
+	workshop, tokens, err := db.Invites.CreateBatch(ctx, adminID, "workshop", 3, inAWeek)
+	r.NoError(err)
+	r.Len(tokens, 3)
+	r.Equal("workshop", workshop.Name)
+	r.Equal(adminID, workshop.CreatedBy)
+	r.EqualValues(3, workshop.Unused)
+	r.False(workshop.CreatedAt.IsZero())
+
+	meetup, meetupTokens, err := db.Invites.CreateBatch(ctx, adminID, "meetup", 2, inAWeek)
+	r.NoError(err)
+
+	// the invites of batches are plain invites
+	lst, err := db.Invites.List(ctx)
+	r.NoError(err)
+	r.Len(lst, 5)
+
+	_, err = db.Invites.Consume(ctx, tokens[0], alf)
+	r.NoError(err)
+
+	batches, err := db.Invites.ListBatches(ctx)
+	r.NoError(err)
+	r.Len(batches, 2)
+	r.Equal(meetup.ID, batches[0].ID, "newest first")
+	r.Equal(workshop.ID, batches[1].ID)
+	r.EqualValues(2, batches[1].Unused)
+
+	// revoking the batch revokes all of its unused invites
+	r.NoError(db.Invites.RevokeBatch(ctx, workshop.ID))
+
+	_, err = db.Invites.Consume(ctx, tokens[1], bob)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "wrong error: %v", err)
+
+	got, err := db.Invites.GetBatch(ctx, workshop.ID)
+	r.NoError(err)
+	r.EqualValues(0, got.Unused)
+
+	batches, err = db.Invites.ListBatches(ctx)
+	r.NoError(err)
+	r.Len(batches, 1)
+	r.Equal(meetup.ID, batches[0].ID)
+
+	err = db.Invites.RevokeBatch(ctx, 666)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "wrong error: %v", err)
+
+	_, err = db.Invites.GetBatch(ctx, 666)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "wrong error: %v", err)
+
+	// the invites of expired batches can't be used anymore
+	_, err = db.db.Exec("UPDATE invite_batches SET expires_at = ? WHERE id = ?", time.Now().UTC().Add(-time.Minute), meetup.ID)
+	r.NoError(err)
+
+	_, err = db.Invites.GetByToken(ctx, meetupTokens[0])
+	r.True(errors.Is(err, roomdb.ErrNotFound), "wrong error: %v", err)
+
+	_, err = db.Invites.Consume(ctx, meetupTokens[0], bob)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "wrong error: %v", err)
+
+	lst, err = db.Invites.List(ctx)
+	r.NoError(err)
+	r.Len(lst, 0)
+
+	batches, err = db.Invites.ListBatches(ctx)
+	r.NoError(err)
+	r.Len(batches, 0)
+
+	// the scrubber removes the batches that have no invites left, the used one of the workshop is kept
+	r.NoError(expireInviteBatches(db.db))
+	r.NoError(deleteRevokedInvites(db.db))
+	r.NoError(expireInviteBatches(db.db))
+
+	_, err = db.Invites.GetBatch(ctx, meetup.ID)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "wrong error: %v", err)
+
+	_, err = db.Invites.GetBatch(ctx, workshop.ID)
+	r.NoError(err)
+
+	r.NoError(db.Close())
+}
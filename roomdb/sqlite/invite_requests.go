@@ -0,0 +1,281 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package sqlite
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/mattn/go-sqlite3"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	refs "github.com/ssbc/go-ssb-refs"
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/roomdb/sqlite/models"
+)
+
+// compiler assertion to ensure the struct fullfills the interface
+var _ roomdb.InviteRequestsService = (*InviteRequests)(nil)
+
+const (
+	inviteRequestTokenLength = 32
+
+	// how many requests can come from the same address in inviteRequestsWindow
+	inviteRequestsPerAddress = 3
+	inviteRequestsWindow     = 24 * time.Hour
+
+	// how many requests can wait for a decision at the same time, so that the queue can't be flooded from many addresses
+	inviteRequestsPendingMax = 200
+
+	// how many of them can come from the same address, even if they are older than inviteRequestsWindow
+	inviteRequestsPendingPerAddress = 5
+
+	// decided requests are kept this long, so that the requesters can still pick up their invite
+	inviteRequestsKeepDecided = 30 * 24 * time.Hour
+)
+
+// InviteRequests keeps the requests to join the room in the invite_requests table.
+// Like with invites, only the sha256 of the tokens the requesters get is stored.
+type InviteRequests struct {
+	db *sql.DB
+
+	invites Invites
+}
+
+// Create stores a pending request and returns the token that the requester can check on it with.
+func (ir InviteRequests) Create(ctx context.Context, feed refs.FeedRef, message, address string) (string, error) {
+	address = addressBucket(address)
+
+	var newRequest = models.InviteRequest{
+		PubKey:  roomdb.DBFeedRef{FeedRef: feed},
+		Message: message,
+		Address: address,
+		State:   int64(roomdb.InviteRequestPending),
+	}
+
+	tokenBytes := make([]byte, inviteRequestTokenLength)
+
+	err := transact(ir.db, func(tx *sql.Tx) error {
+		pending := qm.Where("state = ?", int64(roomdb.InviteRequestPending))
+
+		exists, err := models.InviteRequests(pending, qm.Where("pub_key = ?", feed.String())).Exists(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return roomdb.ErrAlreadyAdded{Ref: feed}
+		}
+
+		count, err := models.InviteRequests(pending).Count(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if count >= inviteRequestsPendingMax {
+			return roomdb.ErrInviteRequestLimit
+		}
+
+		count, err = models.InviteRequests(
+			qm.Where("address = ? AND created_at > ?", address, time.Now().UTC().Add(-inviteRequestsWindow)),
+		).Count(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if count >= inviteRequestsPerAddress {
+			return roomdb.ErrInviteRequestLimit
+		}
+
+		count, err = models.InviteRequests(pending, qm.Where("address = ?", address)).Count(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if count >= inviteRequestsPendingPerAddress {
+			return roomdb.ErrInviteRequestLimit
+		}
+
+		cols := boil.Whitelist(
+			models.InviteRequestColumns.HashedToken,
+			models.InviteRequestColumns.PubKey,
+			models.InviteRequestColumns.Message,
+			models.InviteRequestColumns.Address,
+			models.InviteRequestColumns.State,
+		)
+
+		for tries := 100; tries > 0; tries-- {
+			rand.Read(tokenBytes)
+
+			// hash the binary of the token for storage
+			h := sha256.New()
+			h.Write(tokenBytes)
+			newRequest.HashedToken = fmt.Sprintf("%x", h.Sum(nil))
+
+			err := newRequest.Insert(ctx, tx, cols)
+			if err != nil {
+				var sqlErr sqlite3.Error
+				if errors.As(err, &sqlErr) && sqlErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+					// generated an existing token, retry
+					continue
+				}
+				return err
+			}
+			return nil
+		}
+
+		return errors.New("roomdb: failed to generate an invite request token in a reasonable amount of time")
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return base64.URLEncoding.EncodeToString(tokenBytes), nil
+}
+
+// GetByToken returns the request for the token, or ErrNotFound
+func (ir InviteRequests) GetByToken(ctx context.Context, token string) (roomdb.InviteRequest, error) {
+	tokenBytes, err := base64.URLEncoding.DecodeString(token)
+	if err != nil || len(tokenBytes) != inviteRequestTokenLength {
+		return roomdb.InviteRequest{}, roomdb.ErrNotFound
+	}
+
+	h := sha256.New()
+	h.Write(tokenBytes)
+	hashedToken := fmt.Sprintf("%x", h.Sum(nil))
+
+	entry, err := models.InviteRequests(qm.Where("hashed_token = ?", hashedToken)).One(ctx, ir.db)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return roomdb.InviteRequest{}, roomdb.ErrNotFound
+		}
+		return roomdb.InviteRequest{}, err
+	}
+
+	return toInviteRequest(entry), nil
+}
+
+// GetByID returns the request with that id, or ErrNotFound
+func (ir InviteRequests) GetByID(ctx context.Context, id int64) (roomdb.InviteRequest, error) {
+	entry, err := models.FindInviteRequest(ctx, ir.db, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return roomdb.InviteRequest{}, roomdb.ErrNotFound
+		}
+		return roomdb.InviteRequest{}, err
+	}
+
+	return toInviteRequest(entry), nil
+}
+
+// List returns the pending requests, the oldest first.
+func (ir InviteRequests) List(ctx context.Context) ([]roomdb.InviteRequest, error) {
+	entries, err := models.InviteRequests(
+		qm.Where("state = ?", int64(roomdb.InviteRequestPending)),
+		qm.OrderBy("created_at ASC, id ASC"),
+	).All(ctx, ir.db)
+	if err != nil {
+		return nil, err
+	}
+
+	lst := make([]roomdb.InviteRequest, len(entries))
+	for i, e := range entries {
+		lst[i] = toInviteRequest(e)
+	}
+
+	return lst, nil
+}
+
+// Approve creates an invite for the feed of the pending request and keeps its token with the request.
+func (ir InviteRequests) Approve(ctx context.Context, id, decidedBy int64) (string, error) {
+	var token string
+
+	err := transact(ir.db, func(tx *sql.Tx) error {
+		entry, err := findPendingInviteRequest(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		token, err = ir.invites.create(ctx, tx, decidedBy, roomdb.InviteOptions{ForFeed: &entry.PubKey.FeedRef}, inviteOrigin{})
+		if err != nil {
+			return err
+		}
+
+		// the invite only works for the feed of the request, so it's fine to keep it around in the clear
+		entry.State = int64(roomdb.InviteRequestApproved)
+		entry.InviteToken = token
+		entry.DecidedAt = time.Now().UTC()
+
+		_, err = entry.Update(ctx, tx, boil.Whitelist(
+			models.InviteRequestColumns.State,
+			models.InviteRequestColumns.InviteToken,
+			models.InviteRequestColumns.DecidedAt,
+		))
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// Reject marks the pending request as rejected.
+func (ir InviteRequests) Reject(ctx context.Context, id int64) error {
+	return transact(ir.db, func(tx *sql.Tx) error {
+		entry, err := findPendingInviteRequest(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		entry.State = int64(roomdb.InviteRequestRejected)
+		entry.DecidedAt = time.Now().UTC()
+
+		_, err = entry.Update(ctx, tx, boil.Whitelist(
+			models.InviteRequestColumns.State,
+			models.InviteRequestColumns.DecidedAt,
+		))
+		return err
+	})
+}
+
+func findPendingInviteRequest(ctx context.Context, tx *sql.Tx, id int64) (*models.InviteRequest, error) {
+	entry, err := models.InviteRequests(
+		qm.Where("id = ? AND state = ?", id, int64(roomdb.InviteRequestPending)),
+	).One(ctx, tx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, roomdb.ErrNotFound
+		}
+		return nil, err
+	}
+	return entry, nil
+}
+
+func toInviteRequest(entry *models.InviteRequest) roomdb.InviteRequest {
+	return roomdb.InviteRequest{
+		ID:          entry.ID,
+		PubKey:      entry.PubKey.FeedRef,
+		Message:     entry.Message,
+		State:       roomdb.InviteRequestState(entry.State),
+		InviteToken: entry.InviteToken,
+		CreatedAt:   entry.CreatedAt,
+		DecidedAt:   entry.DecidedAt,
+	}
+}
+
+// deleteDecidedInviteRequests is called by the scrubber of Open, so that old requests don't pile up
+func deleteDecidedInviteRequests(tx boil.ContextExecutor) error {
+	_, err := models.InviteRequests(
+		qm.Where("state != ? AND decided_at < ?", int64(roomdb.InviteRequestPending), time.Now().UTC().Add(-inviteRequestsKeepDecided)),
+	).DeleteAll(context.Background(), tx)
+	if err != nil {
+		return fmt.Errorf("roomdb: failed to delete old invite requests: %w", err)
+	}
+	return nil
+}
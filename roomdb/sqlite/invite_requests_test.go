@@ -0,0 +1,170 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package sqlite
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	refs "github.com/ssbc/go-ssb-refs"
+	"github.com/ssbc/go-ssb-room/v2/internal/repo"
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInviteRequests(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	testRepo := filepath.Join("testrun", t.Name())
+	os.RemoveAll(testRepo)
+	tr := repo.New(testRepo)
+
+	db, err := Open(tr)
+	r.NoError(err, "failed to open database")
+
+	newFeed := func(b string) refs.FeedRef {
+		feed, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte(b), 8), refs.RefAlgoFeedSSB1)
+		r.NoError(err)
+		return feed
+	}
+	mod, alf, bob, carl, dora := newFeed("mod!"), newFeed("alf!"), newFeed("bob!"), newFeed("carl"), newFeed("dora")
+
+	modID, err := db.Members.Add(ctx, mod, roomdb.RoleModerator)
+	r.NoError(err)
+
+	alfTok, err := db.InviteRequests.Create(ctx, alf, "hi, i'm alf", "10.0.0.0")
+	r.NoError(err)
+
+	// only one pending request per feed
+	_, err = db.InviteRequests.Create(ctx, alf, "me again", "10.0.1.0")
+	var alreadyAdded roomdb.ErrAlreadyAdded
+	r.True(errors.As(err, &alreadyAdded), "wrong error: %v", err)
+
+	bobTok, err := db.InviteRequests.Create(ctx, bob, "", "10.0.0.0")
+	r.NoError(err)
+
+	_, err = db.InviteRequests.Create(ctx, carl, "", "10.0.0.0")
+	r.NoError(err)
+
+	// the address used up its requests
+	_, err = db.InviteRequests.Create(ctx, dora, "", "10.0.0.0")
+	r.True(errors.Is(err, roomdb.ErrInviteRequestLimit), "wrong error: %v", err)
+
+	_, err = db.InviteRequests.Create(ctx, dora, "", "")
+	r.NoError(err)
+
+	lst, err := db.InviteRequests.List(ctx)
+	r.NoError(err)
+	r.Len(lst, 4)
+	r.True(lst[0].PubKey.Equal(alf))
+	r.Equal("hi, i'm alf", lst[0].Message)
+	r.Equal(roomdb.InviteRequestPending, lst[0].State)
+
+	// approving creates an invite that only the requester can use
+	inviteTok, err := db.InviteRequests.Approve(ctx, lst[0].ID, modID)
+	r.NoError(err)
+
+	req, err := db.InviteRequests.GetByToken(ctx, alfTok)
+	r.NoError(err)
+	r.Equal(roomdb.InviteRequestApproved, req.State)
+	r.Equal(inviteTok, req.InviteToken)
+
+	inv, err := db.Invites.GetByToken(ctx, inviteTok)
+	r.NoError(err)
+	r.Equal(modID, inv.CreatedBy.ID)
+	r.NotNil(inv.ForFeed)
+	r.True(inv.ForFeed.Equal(alf))
+
+	// decided requests can't be decided again
+	_, err = db.InviteRequests.Approve(ctx, lst[0].ID, modID)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "wrong error: %v", err)
+
+	r.NoError(db.InviteRequests.Reject(ctx, lst[1].ID))
+	err = db.InviteRequests.Reject(ctx, lst[1].ID)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "wrong error: %v", err)
+
+	req, err = db.InviteRequests.GetByToken(ctx, bobTok)
+	r.NoError(err)
+	r.Equal(roomdb.InviteRequestRejected, req.State)
+	r.Equal("", req.InviteToken)
+
+	req, err = db.InviteRequests.GetByID(ctx, lst[1].ID)
+	r.NoError(err)
+	r.True(req.PubKey.Equal(bob))
+
+	lst, err = db.InviteRequests.List(ctx)
+	r.NoError(err)
+	r.Len(lst, 2)
+
+	// once the request of bob was decided, they can ask again
+	_, err = db.InviteRequests.Create(ctx, bob, "please?", "")
+	r.NoError(err)
+
+	_, err = db.InviteRequests.GetByToken(ctx, "not-a-token")
+	r.Equal(roomdb.ErrNotFound, err)
+
+	_, err = db.InviteRequests.GetByID(ctx, 666)
+	r.Equal(roomdb.ErrNotFound, err)
+
+	r.NoError(db.Close())
+}
+
+func TestInviteRequestsAddressLimits(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	testRepo := filepath.Join("testrun", t.Name())
+	os.RemoveAll(testRepo)
+	tr := repo.New(testRepo)
+
+	db, err := Open(tr)
+	r.NoError(err, "failed to open database")
+
+	var feedCount byte
+	newFeed := func() refs.FeedRef {
+		feedCount++
+		feed, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte{feedCount}, 32), refs.RefAlgoFeedSSB1)
+		r.NoError(err)
+		return feed
+	}
+
+	// requests without a valid address share one bucket
+	for _, addr := range []string{"", "not-an-address", "<script>"} {
+		_, err = db.InviteRequests.Create(ctx, newFeed(), "", addr)
+		r.NoError(err, "address %q", addr)
+	}
+	for _, addr := range []string{"", "still-not-one"} {
+		_, err = db.InviteRequests.Create(ctx, newFeed(), "", addr)
+		r.True(errors.Is(err, roomdb.ErrInviteRequestLimit), "address %q: wrong error: %v", addr, err)
+	}
+
+	// other addresses can still ask
+	_, err = db.InviteRequests.Create(ctx, newFeed(), "", "10.0.0.0")
+	r.NoError(err)
+
+	// pending requests count against the address, even after the window
+	for i := 0; i < 3; i++ {
+		_, err = db.InviteRequests.Create(ctx, newFeed(), "", "10.0.1.0")
+		r.NoError(err)
+	}
+	dayBefore := time.Now().Add(-inviteRequestsWindow - time.Hour)
+	_, err = db.db.Exec("UPDATE invite_requests SET created_at = ? WHERE address = ?", dayBefore, "10.0.1.0")
+	r.NoError(err)
+
+	for i := 0; i < inviteRequestsPendingPerAddress-3; i++ {
+		_, err = db.InviteRequests.Create(ctx, newFeed(), "", "10.0.1.0")
+		r.NoError(err)
+	}
+	_, err = db.InviteRequests.Create(ctx, newFeed(), "", "10.0.1.0")
+	r.True(errors.Is(err, roomdb.ErrInviteRequestLimit), "wrong error: %v", err)
+
+	r.NoError(db.Close())
+}
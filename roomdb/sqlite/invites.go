@@ -11,6 +11,7 @@ import (
 	"database/sql"
 	"encoding/base64"
 	"fmt"
+	"time"
 
 	"github.com/friendsofgo/errors"
 	"github.com/mattn/go-sqlite3"
@@ -34,73 +35,181 @@ type Invites struct {
 }
 
 // Create creates a new invite for a new member. It returns the token or an error.
-// createdBy is user ID of the admin or moderator who created it.
-// aliasSuggestion is optional (empty string is fine) but can be used to disambiguate open invites. (See https://github.com/ssbc/rooms2/issues/21)
+// createdBy is user ID of the member who created it, those with RoleMember are limited by the MemberInviteQuota.
+// opts.Role can't be higher than the role of the creator, open mode invites (createdBy -1) are always for members.
 // The returned token is base64 URL encoded and has inviteTokenLength when decoded.
-func (i Invites) Create(ctx context.Context, createdBy int64) (string, error) {
+func (i Invites) Create(ctx context.Context, createdBy int64, opts roomdb.InviteOptions) (string, error) {
+	var token string
+	err := transact(i.db, func(tx *sql.Tx) error {
+		var err error
+		token, err = i.create(ctx, tx, createdBy, opts, inviteOrigin{})
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// how long the invites that were created in open mode count against the limit of an address
+const openInvitesWindow = 24 * time.Hour
+
+// CreateOpenMode creates a plain invite for anyone, if the room is open and the address didn't create too many already.
+func (i Invites) CreateOpenMode(ctx context.Context, address string) (string, error) {
+	var token string
+	err := transact(i.db, func(tx *sql.Tx) error {
+		var err error
+		token, err = i.create(ctx, tx, -1, roomdb.InviteOptions{}, inviteOrigin{address: addressBucket(address)})
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// CountOpenMode returns how many invites were created in open mode since then, used or not.
+func (i Invites) CountOpenMode(ctx context.Context, since time.Time) (uint, error) {
+	count, err := models.Invites(
+		qm.Where("open_mode = true AND created_at > ?", since.UTC()),
+	).Count(ctx, i.db)
+	if err != nil {
+		return 0, err
+	}
+	return uint(count), nil
+}
+
+// inviteOrigin is where an invite came from, besides who created it
+type inviteOrigin struct {
+	// batchID is the batch the invite belongs to, or 0
+	batchID int64
+
+	// address is where an open mode invite was requested from
+	address string
+}
+
+// create inserts a new invite as part of a transaction, so that other changes can depend on it.
+func (i Invites) create(ctx context.Context, tx *sql.Tx, createdBy int64, opts roomdb.InviteOptions, origin inviteOrigin) (string, error) {
 	var newInvite = models.Invite{
 		CreatedBy: createdBy,
+		Role:      int64(roomdb.RoleMember),
+		BatchID:   origin.batchID,
 	}
 
-	tokenBytes := make([]byte, inviteTokenLength)
+	if opts.Role != roomdb.RoleUnknown {
+		if err := opts.Role.IsValid(); err != nil {
+			return "", err
+		}
+		newInvite.Role = int64(opts.Role)
+	}
 
-	err := transact(i.db, func(tx *sql.Tx) error {
+	if opts.ForFeed != nil {
+		newInvite.ForFeed = opts.ForFeed.String()
+	}
 
-		if createdBy == -1 {
-			config, err := models.FindConfig(ctx, tx, configRowID)
-			if err != nil {
-				return err
-			}
+	tokenBytes := make([]byte, inviteTokenLength)
 
-			if config.PrivacyMode != roomdb.ModeOpen {
-				return fmt.Errorf("roomdb: privacy mode not set to open but %s", config.PrivacyMode.String())
+	creatorRole := roomdb.RoleMember
+	if createdBy != -1 {
+		creator, err := models.FindMember(ctx, tx, createdBy)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return "", roomdb.ErrNotFound
 			}
+			return "", err
+		}
+		creatorRole = roomdb.Role(creator.Role)
+	}
 
-			m, err := models.Members(qm.Where("role = ?", roomdb.RoleAdmin)).One(ctx, tx)
+	if roomdb.Role(newInvite.Role) > creatorRole {
+		return "", fmt.Errorf("roomdb: a %s can't create invites for a %s", creatorRole, roomdb.Role(newInvite.Role))
+	}
+
+	// moderators and admins can create as many as they like
+	if createdBy != -1 && creatorRole == roomdb.RoleMember {
+		config, err := models.FindConfig(ctx, tx, configRowID)
+		if err != nil {
+			return "", err
+		}
+
+		if config.MemberInviteQuota > 0 {
+			count, err := models.Invites(
+				usableInvites(),
+				qm.Where("created_by = ?", createdBy),
+			).Count(ctx, tx)
 			if err != nil {
-				// we could insert something like a system user but should probably hit it from the members list then
-				if errors.Is(err, sql.ErrNoRows) {
-					return fmt.Errorf("roomdb: no admin user available to associate invite to")
-				}
-				return err
+				return "", err
+			}
+			if count >= config.MemberInviteQuota {
+				return "", roomdb.ErrInviteQuota
 			}
-			newInvite.CreatedBy = m.ID
 		}
+	}
 
-		inserted := false
-	trying:
-		for tries := 100; tries > 0; tries-- {
-			// generate an invite code
-			rand.Read(tokenBytes)
+	if createdBy == -1 {
+		config, err := models.FindConfig(ctx, tx, configRowID)
+		if err != nil {
+			return "", err
+		}
 
-			// hash the binary of the token for storage
-			h := sha256.New()
-			h.Write(tokenBytes)
-			newInvite.HashedToken = fmt.Sprintf("%x", h.Sum(nil))
+		if config.PrivacyMode != roomdb.ModeOpen {
+			return "", fmt.Errorf("roomdb: privacy mode not set to open but %s", config.PrivacyMode.String())
+		}
 
-			// insert the new invite
-			err := newInvite.Insert(ctx, tx, boil.Infer())
+		if config.OpenInvitesPerAddress > 0 {
+			count, err := models.Invites(
+				qm.Where("open_mode = true AND address = ? AND created_at > ?", origin.address, time.Now().UTC().Add(-openInvitesWindow)),
+			).Count(ctx, tx)
 			if err != nil {
-				var sqlErr sqlite3.Error
-				if errors.As(err, &sqlErr) && sqlErr.ExtendedCode == sqlite3.ErrConstraintUnique {
-					// generated an existing token, retry
-					continue trying
-				}
-				return err
+				return "", err
+			}
+			if count >= config.OpenInvitesPerAddress {
+				return "", roomdb.ErrOpenInviteLimit
 			}
-			inserted = true
-			break // no error means it worked!
 		}
 
-		if !inserted {
-			return errors.New("roomdb: failed to generate an invite token in a reasonable amount of time")
+		m, err := models.Members(qm.Where("role = ?", roomdb.RoleAdmin)).One(ctx, tx)
+		if err != nil {
+			// we could insert something like a system user but should probably hit it from the members list then
+			if errors.Is(err, sql.ErrNoRows) {
+				return "", fmt.Errorf("roomdb: no admin user available to associate invite to")
+			}
+			return "", err
 		}
+		newInvite.CreatedBy = m.ID
+		newInvite.OpenMode = true
+		newInvite.Address = origin.address
+	}
 
-		return nil
-	})
+	inserted := false
+trying:
+	for tries := 100; tries > 0; tries-- {
+		// generate an invite code
+		rand.Read(tokenBytes)
 
-	if err != nil {
-		return "", err
+		// hash the binary of the token for storage
+		h := sha256.New()
+		h.Write(tokenBytes)
+		newInvite.HashedToken = fmt.Sprintf("%x", h.Sum(nil))
+
+		// insert the new invite
+		err := newInvite.Insert(ctx, tx, boil.Infer())
+		if err != nil {
+			var sqlErr sqlite3.Error
+			if errors.As(err, &sqlErr) && sqlErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+				// generated an existing token, retry
+				continue trying
+			}
+			return "", err
+		}
+		inserted = true
+		break // no error means it worked!
+	}
+
+	if !inserted {
+		return "", errors.New("roomdb: failed to generate an invite token in a reasonable amount of time")
 	}
 
 	return base64.URLEncoding.EncodeToString(tokenBytes), nil
@@ -119,7 +228,8 @@ func (i Invites) Consume(ctx context.Context, token string, newMember refs.FeedR
 
 	err = transact(i.db, func(tx *sql.Tx) error {
 		entry, err := models.Invites(
-			qm.Where("active = true AND hashed_token = ?", hashedToken),
+			usableInvites(),
+			qm.Where("hashed_token = ?", hashedToken),
 			qm.Load("CreatedByMember"),
 		).One(ctx, tx)
 		if err != nil {
@@ -129,11 +239,27 @@ func (i Invites) Consume(ctx context.Context, token string, newMember refs.FeedR
 			return err
 		}
 
-		_, err = i.members.add(ctx, tx, newMember, roomdb.RoleMember)
+		if entry.ForFeed != "" && entry.ForFeed != newMember.String() {
+			return roomdb.ErrInviteForOtherFeed
+		}
+
+		// the creator might have been demoted since they created the invite
+		role := roomdb.Role(entry.Role)
+		if creatorRole := roomdb.Role(entry.R.CreatedByMember.Role); role > creatorRole {
+			role = creatorRole
+		}
+
+		memberID, err := i.members.add(ctx, tx, newMember, role)
 		var alreadyAdded roomdb.ErrAlreadyAdded
 		if err != nil {
 			if errors.As(err, &alreadyAdded) && alreadyAdded.Ref.Equal(newMember) {
 				// it is fine to use an invite twice
+				existing, err := models.Members(qm.Where("pub_key = ?", newMember.String())).One(ctx, tx)
+				if err != nil {
+					return err
+				}
+				// but it never changes the role they already have
+				memberID = existing.ID
 			} else {
 				return err
 			}
@@ -146,10 +272,26 @@ func (i Invites) Consume(ctx context.Context, token string, newMember refs.FeedR
 			return err
 		}
 
+		// keep track of who brought them in
+		use := models.InviteUse{
+			InviteID:  entry.ID,
+			InvitedBy: entry.CreatedBy,
+			MemberID:  memberID,
+			PubKey:    roomdb.DBFeedRef{FeedRef: newMember},
+		}
+		err = use.Insert(ctx, tx, boil.Infer())
+		if err != nil {
+			return err
+		}
+
 		inv.ID = entry.ID
 		inv.CreatedAt = entry.CreatedAt
 		inv.CreatedBy.ID = entry.R.CreatedByMember.ID
 		inv.CreatedBy.Role = roomdb.Role(entry.R.CreatedByMember.Role)
+		inv.Role = role
+		if entry.ForFeed != "" {
+			inv.ForFeed = &newMember
+		}
 
 		return nil
 	})
@@ -165,11 +307,11 @@ func (i Invites) Consume(ctx context.Context, token string, newMember refs.FeedR
 }
 
 // since invites are marked as invalid so that the code can't be generated twice,
-// they need to be deleted periodically.
-func deleteConsumedInvites(tx boil.ContextExecutor) error {
-	_, err := models.Invites(qm.Where("active = false")).DeleteAll(context.Background(), tx)
+// revoked ones need to be deleted periodically. The used ones are kept, together with their InviteUse.
+func deleteRevokedInvites(tx boil.ContextExecutor) error {
+	_, err := models.Invites(qm.Where("active = false AND id NOT IN (SELECT invite_id FROM invite_uses)")).DeleteAll(context.Background(), tx)
 	if err != nil {
-		return fmt.Errorf("roomdb: failed to delete used invites: %w", err)
+		return fmt.Errorf("roomdb: failed to delete revoked invites: %w", err)
 	}
 	return nil
 }
@@ -183,7 +325,8 @@ func (i Invites) GetByToken(ctx context.Context, token string) (roomdb.Invite, e
 	}
 
 	entry, err := models.Invites(
-		qm.Where("active = true AND hashed_token = ?", ht),
+		usableInvites(),
+		qm.Where("hashed_token = ?", ht),
 		qm.Load("CreatedByMember"),
 	).One(ctx, i.db)
 	if err != nil {
@@ -197,6 +340,12 @@ func (i Invites) GetByToken(ctx context.Context, token string) (roomdb.Invite, e
 	inv.CreatedAt = entry.CreatedAt
 	inv.CreatedBy.ID = entry.R.CreatedByMember.ID
 	inv.CreatedBy.Role = roomdb.Role(entry.R.CreatedByMember.Role)
+	inv.Role = roomdb.Role(entry.Role)
+	inv.OpenMode = entry.OpenMode
+	inv.ForFeed, err = inviteFeed(entry)
+	if err != nil {
+		return inv, err
+	}
 
 	return inv, nil
 }
@@ -205,7 +354,8 @@ func (i Invites) GetByID(ctx context.Context, id int64) (roomdb.Invite, error) {
 	var inv roomdb.Invite
 
 	entry, err := models.Invites(
-		qm.Where("active = true AND id = ?", id),
+		usableInvites(),
+		qm.Where("id = ?", id),
 		qm.Load("CreatedByMember"),
 	).One(ctx, i.db)
 	if err != nil {
@@ -221,6 +371,12 @@ func (i Invites) GetByID(ctx context.Context, id int64) (roomdb.Invite, error) {
 	inv.CreatedBy.Role = roomdb.Role(entry.R.CreatedByMember.Role)
 	inv.CreatedBy.PubKey = entry.R.CreatedByMember.PubKey.FeedRef
 	inv.CreatedBy.Aliases = i.members.getAliases(entry.R.CreatedByMember)
+	inv.Role = roomdb.Role(entry.Role)
+	inv.OpenMode = entry.OpenMode
+	inv.ForFeed, err = inviteFeed(entry)
+	if err != nil {
+		return inv, err
+	}
 
 	return inv, nil
 }
@@ -231,7 +387,7 @@ func (i Invites) List(ctx context.Context) ([]roomdb.Invite, error) {
 
 	err := transact(i.db, func(tx *sql.Tx) error {
 		entries, err := models.Invites(
-			qm.Where("active = true"),
+			usableInvites(),
 			qm.Load("CreatedByMember"),
 			qm.Load("CreatedByMember.Aliases"),
 		).All(ctx, tx)
@@ -247,6 +403,12 @@ func (i Invites) List(ctx context.Context) ([]roomdb.Invite, error) {
 			inv.CreatedBy.ID = e.R.CreatedByMember.ID
 			inv.CreatedBy.PubKey = e.R.CreatedByMember.PubKey.FeedRef
 			inv.CreatedBy.Aliases = i.members.getAliases(e.R.CreatedByMember)
+			inv.Role = roomdb.Role(e.Role)
+			inv.OpenMode = e.OpenMode
+			inv.ForFeed, err = inviteFeed(e)
+			if err != nil {
+				return err
+			}
 
 			invs[idx] = inv
 		}
@@ -260,10 +422,116 @@ func (i Invites) List(ctx context.Context) ([]roomdb.Invite, error) {
 	return invs, nil
 }
 
+// ListCreatedBy returns the invites the member created, the newest first.
+// Revoked invites are only listed until the scrubber deletes them, see deleteRevokedInvites.
+func (i Invites) ListCreatedBy(ctx context.Context, memberID int64) ([]roomdb.CreatedInvite, error) {
+	entries, err := models.Invites(
+		qm.Where("created_by = ?", memberID),
+		qm.Load("CreatedByMember"),
+		qm.Load("CreatedByMember.Aliases"),
+		qm.OrderBy("created_at DESC, id DESC"),
+	).All(ctx, i.db)
+	if err != nil {
+		return nil, err
+	}
+
+	var inviteIDs []interface{}
+	for _, e := range entries {
+		inviteIDs = append(inviteIDs, e.ID)
+	}
+
+	// who used them
+	usesByInvite := make(map[int64]roomdb.InviteUse)
+	if len(inviteIDs) > 0 {
+		useEntries, err := models.InviteUses(
+			qm.WhereIn("invite_id IN ?", inviteIDs...),
+			qm.OrderBy("used_at ASC, id ASC"),
+		).All(ctx, i.db)
+		if err != nil {
+			return nil, err
+		}
+
+		uses, err := i.resolveUses(ctx, useEntries)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range uses {
+			if _, has := usesByInvite[u.InviteID]; !has {
+				usesByInvite[u.InviteID] = u
+			}
+		}
+	}
+
+	// which of them are part of expired batches
+	expiredBatches := make(map[int64]struct{})
+	batches, err := models.InviteBatches(
+		qm.Where("created_by = ? AND expires_at <= ?", memberID, time.Now().UTC()),
+	).All(ctx, i.db)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range batches {
+		expiredBatches[b.ID] = struct{}{}
+	}
+
+	invs := make([]roomdb.CreatedInvite, len(entries))
+	for idx, e := range entries {
+		var inv roomdb.CreatedInvite
+		inv.ID = e.ID
+		inv.CreatedAt = e.CreatedAt
+		inv.CreatedBy.ID = e.R.CreatedByMember.ID
+		inv.CreatedBy.Role = roomdb.Role(e.R.CreatedByMember.Role)
+		inv.CreatedBy.PubKey = e.R.CreatedByMember.PubKey.FeedRef
+		inv.CreatedBy.Aliases = i.members.getAliases(e.R.CreatedByMember)
+		inv.Role = roomdb.Role(e.Role)
+		inv.OpenMode = e.OpenMode
+		inv.ForFeed, err = inviteFeed(e)
+		if err != nil {
+			return nil, err
+		}
+
+		_, expired := expiredBatches[e.BatchID]
+		if use, has := usesByInvite[e.ID]; has {
+			inv.Status = roomdb.InviteStatusConsumed
+			inv.UsedBy = &use.Member
+			inv.UsedAt = use.UsedAt
+		} else if expired {
+			inv.Status = roomdb.InviteStatusExpired
+		} else if e.Active {
+			inv.Status = roomdb.InviteStatusActive
+		} else {
+			inv.Status = roomdb.InviteStatusRevoked
+		}
+
+		invs[idx] = inv
+	}
+
+	return invs, nil
+}
+
+// usableInvites matches the invites that are active and not part of an expired batch.
+// The invites of expired batches are only deactivated by the scrubber, see expireInviteBatches.
+func usableInvites() qm.QueryMod {
+	return qm.Where("active = true AND (batch_id = 0 OR batch_id IN (SELECT id FROM invite_batches WHERE expires_at > ?))", time.Now().UTC())
+}
+
+// inviteFeed returns the feed that can use the invite, or nil if anyone can.
+func inviteFeed(entry *models.Invite) (*refs.FeedRef, error) {
+	if entry.ForFeed == "" {
+		return nil, nil
+	}
+
+	feed, err := refs.ParseFeedRef(entry.ForFeed)
+	if err != nil {
+		return nil, fmt.Errorf("roomdb: invalid feed of invite %d: %w", entry.ID, err)
+	}
+	return &feed, nil
+}
+
 func (i Invites) Count(ctx context.Context, onlyActive bool) (uint, error) {
 	queryMod := qm.Where("1")
 	if onlyActive {
-		queryMod = qm.Where("active = true")
+		queryMod = usableInvites()
 	}
 	count, err := models.Invites(queryMod).Count(ctx, i.db)
 	if err != nil {
@@ -276,7 +544,8 @@ func (i Invites) Count(ctx context.Context, onlyActive bool) (uint, error) {
 func (i Invites) Revoke(ctx context.Context, id int64) error {
 	return transact(i.db, func(tx *sql.Tx) error {
 		entry, err := models.Invites(
-			qm.Where("active = true AND id = ?", id),
+			usableInvites(),
+			qm.Where("id = ?", id),
 		).One(ctx, tx)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
@@ -295,6 +564,115 @@ func (i Invites) Revoke(ctx context.Context, id int64) error {
 	})
 }
 
+// InvitedBy returns how the member joined the room, or ErrNotFound if that isn't known.
+// If they used several invites, like after they were removed, it's the first one.
+func (i Invites) InvitedBy(ctx context.Context, memberID int64) (roomdb.InviteUse, error) {
+	entry, err := models.InviteUses(
+		qm.Where("member_id = ?", memberID),
+		qm.OrderBy("used_at ASC, id ASC"),
+	).One(ctx, i.db)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return roomdb.InviteUse{}, roomdb.ErrNotFound
+		}
+		return roomdb.InviteUse{}, err
+	}
+
+	uses, err := i.resolveUses(ctx, models.InviteUseSlice{entry})
+	if err != nil {
+		return roomdb.InviteUse{}, err
+	}
+
+	return uses[0], nil
+}
+
+// ListInvitedBy returns the uses of the invites the member created, optionally including everyone that came in through them.
+func (i Invites) ListInvitedBy(ctx context.Context, memberID int64, recursive bool) ([]roomdb.InviteUse, error) {
+	query := qm.Where("invited_by = ?", memberID)
+	if recursive {
+		// UNION only adds members that weren't found yet, so that this ends
+		// even if somebody joined again through an invite of someone they invited.
+		query = qm.Where(`member_id != ? AND invited_by IN (
+			WITH RECURSIVE invited(id) AS (
+				SELECT ?
+				UNION
+				SELECT u.member_id FROM invite_uses u JOIN invited ON u.invited_by = invited.id
+			)
+			SELECT id FROM invited
+		)`, memberID, memberID)
+	}
+
+	entries, err := models.InviteUses(
+		query,
+		qm.OrderBy("used_at ASC, id ASC"),
+	).All(ctx, i.db)
+	if err != nil {
+		return nil, err
+	}
+
+	return i.resolveUses(ctx, entries)
+}
+
+// resolveUses returns the entries with the details of the members that still exist
+func (i Invites) resolveUses(ctx context.Context, entries models.InviteUseSlice) ([]roomdb.InviteUse, error) {
+	var (
+		ids  []interface{}
+		seen = make(map[int64]struct{})
+	)
+	for _, e := range entries {
+		for _, id := range []int64{e.InvitedBy, e.MemberID} {
+			if _, has := seen[id]; !has {
+				seen[id] = struct{}{}
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	found := make(map[int64]roomdb.Member, len(ids))
+	if len(ids) > 0 {
+		mEntries, err := models.Members(
+			qm.WhereIn("id IN ?", ids...),
+			qm.Load("Aliases"),
+		).All(ctx, i.db)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range mEntries {
+			found[m.ID] = roomdb.Member{
+				ID:      m.ID,
+				Role:    roomdb.Role(m.Role),
+				PubKey:  m.PubKey.FeedRef,
+				Aliases: i.members.getAliases(m),
+			}
+		}
+	}
+
+	uses := make([]roomdb.InviteUse, len(entries))
+	for idx, e := range entries {
+		inviter, has := found[e.InvitedBy]
+		if !has {
+			inviter.ID = e.InvitedBy
+		}
+
+		member, has := found[e.MemberID]
+		if !has {
+			member.ID = e.MemberID
+			member.PubKey = e.PubKey.FeedRef
+		}
+
+		uses[idx] = roomdb.InviteUse{
+			ID:        e.ID,
+			InviteID:  e.InviteID,
+			InvitedBy: inviter,
+			Member:    member,
+			UsedAt:    e.UsedAt,
+		}
+	}
+
+	return uses, nil
+}
+
 const inviteTokenLength = 50
 
 func getHashedToken(b64tok string) (string, error) {
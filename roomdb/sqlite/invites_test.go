@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"errors"
 	"math/rand"
 	"os"
 	"path/filepath"
@@ -54,7 +55,7 @@ func TestInvites(t *testing.T) {
 	t.Run("user needs to exist", func(t *testing.T) {
 		r := require.New(t)
 
-		_, err := db.Invites.Create(ctx, 666)
+		_, err := db.Invites.Create(ctx, 666, roomdb.InviteOptions{})
 		r.Error(err, "can't create invite for invalid user")
 	})
 
@@ -76,7 +77,7 @@ func TestInvites(t *testing.T) {
 		// i really don't want to do a mocked time functions and rather solve the comment in migration 6 instead
 		before := time.Now()
 
-		tok, err := db.Invites.Create(ctx, mid)
+		tok, err := db.Invites.Create(ctx, mid, roomdb.InviteOptions{})
 		r.NoError(err, "failed to create invite token")
 
 		_, err = base64.URLEncoding.DecodeString(tok)
@@ -119,7 +120,7 @@ func TestInvites(t *testing.T) {
 	t.Run("simple create but revoke before use", func(t *testing.T) {
 		r := require.New(t)
 
-		tok, err := db.Invites.Create(ctx, mid)
+		tok, err := db.Invites.Create(ctx, mid, roomdb.InviteOptions{})
 		r.NoError(err, "failed to create invite token")
 
 		lst, err := db.Invites.List(ctx)
@@ -141,7 +142,7 @@ func TestInvites(t *testing.T) {
 	t.Run("invite member again", func(t *testing.T) {
 		r := require.New(t)
 
-		tok, err := db.Invites.Create(ctx, mid)
+		tok, err := db.Invites.Create(ctx, mid, roomdb.InviteOptions{})
 		r.NoError(err, "failed to create invite token")
 
 		lst, err := db.Invites.List(ctx)
@@ -156,3 +157,391 @@ func TestInvites(t *testing.T) {
 		r.Len(lst, 0, "expected no active invites")
 	})
 }
+
+func TestInviteUses(t *testing.T) {
+	ctx := context.Background()
+	r := require.New(t)
+
+	testRepo := filepath.Join("testrun", t.Name())
+	os.RemoveAll(testRepo)
+	tr := repo.New(testRepo)
+
+	db, err := Open(tr)
+	r.NoError(err)
+
+	newFeed := func(b string) refs.FeedRef {
+		feed, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte(b), 8), refs.RefAlgoFeedSSB1)
+		r.NoError(err)
+		return feed
+	}
+
+	// mod invites alf, alf invites bob and carl, bob invites dora
+	mod := newFeed("mod!")
+	modID, err := db.Members.Add(ctx, mod, roomdb.RoleModerator)
+	r.NoError(err)
+
+	invite := func(by int64, who refs.FeedRef) int64 {
+		tok, err := db.Invites.Create(ctx, by, roomdb.InviteOptions{})
+		r.NoError(err)
+		_, err = db.Invites.Consume(ctx, tok, who)
+		r.NoError(err)
+		m, err := db.Members.GetByFeed(ctx, who)
+		r.NoError(err)
+		return m.ID
+	}
+
+	alf := newFeed("alf!")
+	alfID := invite(modID, alf)
+	bobID := invite(alfID, newFeed("bob!"))
+	carlID := invite(alfID, newFeed("carl"))
+	dora := newFeed("dora")
+	doraID := invite(bobID, dora)
+
+	use, err := db.Invites.InvitedBy(ctx, doraID)
+	r.NoError(err)
+	r.Equal(bobID, use.InvitedBy.ID)
+	r.Equal(doraID, use.Member.ID)
+	r.True(use.Member.PubKey.Equal(dora))
+
+	_, err = db.Invites.InvitedBy(ctx, modID)
+	r.Equal(roomdb.ErrNotFound, err)
+
+	direct, err := db.Invites.ListInvitedBy(ctx, alfID, false)
+	r.NoError(err)
+	r.Len(direct, 2)
+	r.Equal(bobID, direct[0].Member.ID)
+	r.Equal(carlID, direct[1].Member.ID)
+
+	everyone, err := db.Invites.ListInvitedBy(ctx, modID, true)
+	r.NoError(err)
+	r.Len(everyone, 4)
+
+	// joining again through your own subtree doesn't loop
+	r.NoError(db.Members.RemoveID(ctx, alfID))
+	alfAgainID := invite(doraID, alf)
+
+	through, err := db.Invites.ListInvitedBy(ctx, alfAgainID, true)
+	r.NoError(err)
+	r.Len(through, 0, "a new member id starts a new tree")
+
+	through, err = db.Invites.ListInvitedBy(ctx, bobID, true)
+	r.NoError(err)
+	r.Len(through, 2, "dora and alf, who came back through her")
+
+	// removed members are still listed, with their key
+	everyone, err = db.Invites.ListInvitedBy(ctx, modID, false)
+	r.NoError(err)
+	r.Len(everyone, 1)
+	r.Equal(alfID, everyone[0].Member.ID)
+	r.True(everyone[0].Member.PubKey.Equal(alf))
+	r.Equal(roomdb.Role(0), everyone[0].Member.Role)
+
+	// the used invites are kept by the scrubber, the revoked ones not
+	tok, err := db.Invites.Create(ctx, modID, roomdb.InviteOptions{})
+	r.NoError(err)
+	inv, err := db.Invites.GetByToken(ctx, tok)
+	r.NoError(err)
+	r.NoError(db.Invites.Revoke(ctx, inv.ID))
+
+	r.NoError(deleteRevokedInvites(db.db))
+	count, err := db.Invites.Count(ctx, false)
+	r.NoError(err)
+	// the invites alf created were removed with them, only their uses are kept
+	r.EqualValues(3, count, "the invites for alf, dora and alf's second one")
+
+	r.NoError(db.Close())
+}
+
+func TestInviteRoleAndFeed(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	testRepo := filepath.Join("testrun", t.Name())
+	os.RemoveAll(testRepo)
+	tr := repo.New(testRepo)
+
+	db, err := Open(tr)
+	r.NoError(err)
+
+	newFeed := func(b string) refs.FeedRef {
+		feed, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte(b), 8), refs.RefAlgoFeedSSB1)
+		r.NoError(err)
+		return feed
+	}
+	mod, alf, bob := newFeed("modd"), newFeed("alfa"), newFeed("bobb")
+
+	modID, err := db.Members.Add(ctx, mod, roomdb.RoleModerator)
+	r.NoError(err)
+
+	// moderators can't invite admins
+	_, err = db.Invites.Create(ctx, modID, roomdb.InviteOptions{Role: roomdb.RoleAdmin})
+	r.Error(err)
+
+	tok, err := db.Invites.Create(ctx, modID, roomdb.InviteOptions{Role: roomdb.RoleModerator, ForFeed: &alf})
+	r.NoError(err)
+
+	inv, err := db.Invites.GetByToken(ctx, tok)
+	r.NoError(err)
+	r.Equal(roomdb.RoleModerator, inv.Role)
+	r.NotNil(inv.ForFeed)
+	r.True(inv.ForFeed.Equal(alf))
+
+	lst, err := db.Invites.List(ctx)
+	r.NoError(err)
+	r.Len(lst, 1)
+	r.Equal(roomdb.RoleModerator, lst[0].Role)
+	r.True(lst[0].ForFeed.Equal(alf))
+
+	// somebody else can't use it and it stays active
+	_, err = db.Invites.Consume(ctx, tok, bob)
+	r.True(errors.Is(err, roomdb.ErrInviteForOtherFeed), "wrong error: %v", err)
+
+	_, err = db.Members.GetByFeed(ctx, bob)
+	r.Equal(roomdb.ErrNotFound, err)
+
+	inv, err = db.Invites.Consume(ctx, tok, alf)
+	r.NoError(err)
+	r.Equal(roomdb.RoleModerator, inv.Role)
+
+	alfMember, err := db.Members.GetByFeed(ctx, alf)
+	r.NoError(err)
+	r.Equal(roomdb.RoleModerator, alfMember.Role)
+
+	// plain invites are for members and don't lower the role of existing ones
+	tok, err = db.Invites.Create(ctx, modID, roomdb.InviteOptions{})
+	r.NoError(err)
+
+	inv, err = db.Invites.Consume(ctx, tok, alf)
+	r.NoError(err)
+	r.Equal(roomdb.RoleMember, inv.Role)
+	r.Nil(inv.ForFeed)
+
+	alfMember, err = db.Members.GetByFeed(ctx, alf)
+	r.NoError(err)
+	r.Equal(roomdb.RoleModerator, alfMember.Role)
+
+	// invites don't raise the role of existing members either
+	bobID, err := db.Members.Add(ctx, bob, roomdb.RoleMember)
+	r.NoError(err)
+
+	tok, err = db.Invites.Create(ctx, modID, roomdb.InviteOptions{Role: roomdb.RoleModerator})
+	r.NoError(err)
+
+	_, err = db.Invites.Consume(ctx, tok, bob)
+	r.NoError(err)
+
+	bobMember, err := db.Members.GetByID(ctx, bobID)
+	r.NoError(err)
+	r.Equal(roomdb.RoleMember, bobMember.Role)
+
+	r.NoError(db.Close())
+}
+
+func TestInviteRoleOfDemotedCreator(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	testRepo := filepath.Join("testrun", t.Name())
+	os.RemoveAll(testRepo)
+	tr := repo.New(testRepo)
+
+	db, err := Open(tr)
+	r.NoError(err)
+
+	newFeed := func(b string) refs.FeedRef {
+		feed, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte(b), 8), refs.RefAlgoFeedSSB1)
+		r.NoError(err)
+		return feed
+	}
+	admin, mod, alf, bob := newFeed("admi"), newFeed("modd"), newFeed("alfa"), newFeed("bobb")
+
+	// changing roles needs another admin
+	_, err = db.Members.Add(ctx, admin, roomdb.RoleAdmin)
+	r.NoError(err)
+
+	modID, err := db.Members.Add(ctx, mod, roomdb.RoleModerator)
+	r.NoError(err)
+
+	tok, err := db.Invites.Create(ctx, modID, roomdb.InviteOptions{Role: roomdb.RoleModerator})
+	r.NoError(err)
+
+	// the moderator is demoted before the invite is used
+	r.NoError(db.Members.SetRole(ctx, modID, roomdb.RoleMember))
+
+	inv, err := db.Invites.Consume(ctx, tok, alf)
+	r.NoError(err)
+	r.Equal(roomdb.RoleMember, inv.Role)
+
+	alfMember, err := db.Members.GetByFeed(ctx, alf)
+	r.NoError(err)
+	r.Equal(roomdb.RoleMember, alfMember.Role)
+
+	// invites of removed members can't be used anymore
+	tok, err = db.Invites.Create(ctx, modID, roomdb.InviteOptions{})
+	r.NoError(err)
+
+	r.NoError(db.Members.RemoveFeed(ctx, mod))
+
+	_, err = db.Invites.Consume(ctx, tok, bob)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "wrong error: %v", err)
+
+	r.NoError(db.Close())
+}
+
+func TestInvitesOpenMode(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	testRepo := filepath.Join("testrun", t.Name())
+	os.RemoveAll(testRepo)
+	tr := repo.New(testRepo)
+
+	db, err := Open(tr)
+	r.NoError(err)
+
+	admin, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte("adm!"), 8), refs.RefAlgoFeedSSB1)
+	r.NoError(err)
+	adminID, err := db.Members.Add(ctx, admin, roomdb.RoleAdmin)
+	r.NoError(err)
+
+	// only while the room is open
+	_, err = db.Invites.CreateOpenMode(ctx, "192.0.2.1")
+	r.Error(err)
+
+	r.NoError(db.Config.SetPrivacyMode(ctx, roomdb.ModeOpen))
+	r.NoError(db.Config.SetOpenInviteLimits(ctx, roomdb.OpenInviteLimits{PerAddress: 2}))
+
+	before := time.Now().Add(-time.Minute)
+
+	for i := 0; i < 2; i++ {
+		_, err = db.Invites.CreateOpenMode(ctx, "192.0.2.1")
+		r.NoError(err)
+	}
+
+	_, err = db.Invites.CreateOpenMode(ctx, "192.0.2.1")
+	r.True(errors.Is(err, roomdb.ErrOpenInviteLimit), "wrong error: %v", err)
+
+	// other addresses have their own limit
+	_, err = db.Invites.CreateOpenMode(ctx, "192.0.2.2")
+	r.NoError(err)
+
+	// invites of members don't count
+	_, err = db.Invites.Create(ctx, adminID, roomdb.InviteOptions{})
+	r.NoError(err)
+
+	count, err := db.Invites.CountOpenMode(ctx, before)
+	r.NoError(err)
+	r.EqualValues(3, count)
+
+	count, err = db.Invites.CountOpenMode(ctx, time.Now().Add(time.Minute))
+	r.NoError(err)
+	r.EqualValues(0, count)
+
+	lst, err := db.Invites.List(ctx)
+	r.NoError(err)
+	r.Len(lst, 4)
+
+	var openMode int
+	for _, inv := range lst {
+		if inv.OpenMode {
+			openMode++
+			r.Equal(adminID, inv.CreatedBy.ID)
+		}
+	}
+	r.Equal(3, openMode)
+
+	// requests without an address share one limit
+	for i := 0; i < 2; i++ {
+		_, err = db.Invites.CreateOpenMode(ctx, "")
+		r.NoError(err)
+	}
+	_, err = db.Invites.CreateOpenMode(ctx, "")
+	r.True(errors.Is(err, roomdb.ErrOpenInviteLimit), "wrong error: %v", err)
+
+	// 0 turns the limit off
+	r.NoError(db.Config.SetOpenInviteLimits(ctx, roomdb.OpenInviteLimits{}))
+	_, err = db.Invites.CreateOpenMode(ctx, "192.0.2.1")
+	r.NoError(err)
+
+	r.NoError(db.Close())
+}
+
+func TestInvitesCreatedByMember(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	testRepo := filepath.Join("testrun", t.Name())
+	os.RemoveAll(testRepo)
+	tr := repo.New(testRepo)
+
+	db, err := Open(tr)
+	r.NoError(err)
+
+	newFeed := func(b string) refs.FeedRef {
+		feed, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte(b), 8), refs.RefAlgoFeedSSB1)
+		r.NoError(err)
+		return feed
+	}
+	mod, alf, bob := newFeed("modd"), newFeed("alfa"), newFeed("bobb")
+
+	modID, err := db.Members.Add(ctx, mod, roomdb.RoleModerator)
+	r.NoError(err)
+	alfID, err := db.Members.Add(ctx, alf, roomdb.RoleMember)
+	r.NoError(err)
+
+	r.NoError(db.Config.SetMemberInviteQuota(ctx, 2))
+
+	usedTok, err := db.Invites.Create(ctx, alfID, roomdb.InviteOptions{})
+	r.NoError(err)
+	revokedTok, err := db.Invites.Create(ctx, alfID, roomdb.InviteOptions{})
+	r.NoError(err)
+
+	_, err = db.Invites.Create(ctx, alfID, roomdb.InviteOptions{})
+	r.True(errors.Is(err, roomdb.ErrInviteQuota), "wrong error: %v", err)
+
+	// moderators don't have a quota
+	for i := 0; i < 3; i++ {
+		_, err = db.Invites.Create(ctx, modID, roomdb.InviteOptions{})
+		r.NoError(err)
+	}
+
+	// used and revoked invites don't count
+	_, err = db.Invites.Consume(ctx, usedTok, bob)
+	r.NoError(err)
+
+	revoked, err := db.Invites.GetByToken(ctx, revokedTok)
+	r.NoError(err)
+	r.NoError(db.Invites.Revoke(ctx, revoked.ID))
+
+	_, err = db.Invites.Create(ctx, alfID, roomdb.InviteOptions{})
+	r.NoError(err)
+
+	lst, err := db.Invites.ListCreatedBy(ctx, alfID)
+	r.NoError(err)
+	r.Len(lst, 3)
+
+	// newest first
+	r.Equal(roomdb.InviteStatusActive, lst[0].Status)
+	r.Nil(lst[0].UsedBy)
+
+	r.Equal(roomdb.InviteStatusRevoked, lst[1].Status)
+	r.Equal(revoked.ID, lst[1].ID)
+
+	r.Equal(roomdb.InviteStatusConsumed, lst[2].Status)
+	r.NotNil(lst[2].UsedBy)
+	r.True(lst[2].UsedBy.PubKey.Equal(bob))
+	r.Equal(alfID, lst[2].CreatedBy.ID)
+
+	lst, err = db.Invites.ListCreatedBy(ctx, modID)
+	r.NoError(err)
+	r.Len(lst, 3)
+
+	// 0 turns the quota off
+	r.NoError(db.Config.SetMemberInviteQuota(ctx, 0))
+	_, err = db.Invites.Create(ctx, alfID, roomdb.InviteOptions{})
+	r.NoError(err)
+	_, err = db.Invites.Create(ctx, alfID, roomdb.InviteOptions{})
+	r.NoError(err)
+
+	r.NoError(db.Close())
+}
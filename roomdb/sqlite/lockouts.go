@@ -0,0 +1,255 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/roomdb/sqlite/models"
+)
+
+// compiler assertion to ensure the struct fullfills the interface
+var _ roomdb.LoginLockoutService = (*LoginLockouts)(nil)
+
+const (
+	// how many failures are tolerated before a login is locked out
+	lockoutLoginFailures = 5
+
+	// addresses get more tries, since several members might sign in from behind the same one
+	lockoutAddressFailures = 20
+
+	// the first lockout, it doubles with every further failure
+	lockoutBase = time.Minute
+
+	// the longest lockout
+	lockoutMax = 24 * time.Hour
+
+	// failures are forgotten if there were no new ones for this long
+	lockoutForgetAfter = 24 * time.Hour
+)
+
+// LoginLockouts counts the failed fallback password sign-ins in the login_lockouts table
+type LoginLockouts struct {
+	db *sql.DB
+
+	// now is used instead of time.Now, if set. It's only changed by the tests.
+	now func() time.Time
+}
+
+func (l LoginLockouts) currentTime() time.Time {
+	if l.now != nil {
+		return l.now()
+	}
+	return time.Now()
+}
+
+// Check returns ErrLockedOut if the login or the address are locked out right now.
+func (l LoginLockouts) Check(ctx context.Context, login, address string) error {
+	now := l.currentTime().Unix()
+
+	entries, err := models.LoginLockouts(
+		qm.Where("locked_until > ?", now),
+		qm.Where("(kind = ? AND subject = ?) OR (kind = ? AND subject = ?)",
+			int64(roomdb.LockoutKindLogin), normalizeLockoutLogin(login),
+			int64(roomdb.LockoutKindAddress), addressBucket(address),
+		),
+	).All(ctx, l.db)
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	// report the one that lasts longer
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].LockedUntil > entries[j].LockedUntil
+	})
+
+	return roomdb.ErrLockedOut{
+		Kind:    roomdb.LockoutKind(entries[0].Kind),
+		Subject: entries[0].Subject,
+		Until:   time.Unix(entries[0].LockedUntil, 0),
+	}
+}
+
+// Failed counts a failed sign-in for the login and the address and returns the lockouts that started because of it.
+func (l LoginLockouts) Failed(ctx context.Context, login, address string) ([]roomdb.LoginLockout, error) {
+	now := l.currentTime()
+
+	var started []roomdb.LoginLockout
+
+	err := transact(l.db, func(tx *sql.Tx) error {
+		// drop the ones nobody tried in a while, so that the table doesn't fill up with made up logins
+		_, err := models.LoginLockouts(
+			qm.Where("last_failure < ? AND locked_until < ?", now.Add(-lockoutForgetAfter).Unix(), now.Unix()),
+		).DeleteAll(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		lockout, err := countFailure(ctx, tx, now, roomdb.LockoutKindLogin, normalizeLockoutLogin(login), lockoutLoginFailures)
+		if err != nil {
+			return err
+		}
+		if lockout != nil {
+			started = append(started, *lockout)
+		}
+
+		lockout, err = countFailure(ctx, tx, now, roomdb.LockoutKindAddress, addressBucket(address), lockoutAddressFailures)
+		if err != nil {
+			return err
+		}
+		if lockout != nil {
+			started = append(started, *lockout)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return started, nil
+}
+
+// countFailure increments the failures of the subject and locks it out if it has more than allowed.
+// It returns the new lockout, if one started.
+func countFailure(ctx context.Context, tx *sql.Tx, now time.Time, kind roomdb.LockoutKind, subject string, allowed int64) (*roomdb.LoginLockout, error) {
+	entry, err := models.LoginLockouts(
+		qm.Where("kind = ? AND subject = ?", int64(kind), subject),
+	).One(ctx, tx)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+
+		entry = &models.LoginLockout{
+			Kind:    int64(kind),
+			Subject: subject,
+		}
+		if err := entry.Insert(ctx, tx, boil.Whitelist(
+			models.LoginLockoutColumns.Kind,
+			models.LoginLockoutColumns.Subject,
+		)); err != nil {
+			return nil, err
+		}
+	}
+
+	entry.Failures++
+	entry.LastFailure = now.Unix()
+
+	var lockedOut bool
+	if entry.Failures >= allowed {
+		entry.LockedUntil = now.Add(lockoutDuration(entry.Failures - allowed)).Unix()
+		lockedOut = true
+	}
+
+	_, err = entry.Update(ctx, tx, boil.Whitelist(
+		models.LoginLockoutColumns.Failures,
+		models.LoginLockoutColumns.LastFailure,
+		models.LoginLockoutColumns.LockedUntil,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	if !lockedOut {
+		return nil, nil
+	}
+
+	lockout := lockoutFromModel(entry)
+	return &lockout, nil
+}
+
+// lockoutDuration doubles the base for every failure over the allowed ones
+func lockoutDuration(over int64) time.Duration {
+	d := lockoutBase
+	for i := int64(0); i < over; i++ {
+		d *= 2
+		if d >= lockoutMax {
+			return lockoutMax
+		}
+	}
+	return d
+}
+
+// Succeeded forgets the failures of the login.
+func (l LoginLockouts) Succeeded(ctx context.Context, login string) error {
+	_, err := models.LoginLockouts(
+		qm.Where("kind = ? AND subject = ?", int64(roomdb.LockoutKindLogin), normalizeLockoutLogin(login)),
+	).DeleteAll(ctx, l.db)
+	return err
+}
+
+// List returns the logins and addresses with recent failures, those that are locked out first.
+func (l LoginLockouts) List(ctx context.Context) ([]roomdb.LoginLockout, error) {
+	now := l.currentTime()
+
+	entries, err := models.LoginLockouts(
+		qm.Where("last_failure >= ? OR locked_until >= ?", now.Add(-lockoutForgetAfter).Unix(), now.Unix()),
+		qm.OrderBy("locked_until DESC, last_failure DESC"),
+	).All(ctx, l.db)
+	if err != nil {
+		return nil, err
+	}
+
+	var lst = make([]roomdb.LoginLockout, len(entries))
+	for i, entry := range entries {
+		lst[i] = lockoutFromModel(entry)
+	}
+
+	return lst, nil
+}
+
+// Clear forgets the failures and ends the lockout with that id.
+func (l LoginLockouts) Clear(ctx context.Context, id int64) error {
+	n, err := models.LoginLockouts(qm.Where("id = ?", id)).DeleteAll(ctx, l.db)
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return roomdb.ErrNotFound
+	}
+
+	return nil
+}
+
+func lockoutFromModel(entry *models.LoginLockout) roomdb.LoginLockout {
+	lockout := roomdb.LoginLockout{
+		ID:          entry.ID,
+		Kind:        roomdb.LockoutKind(entry.Kind),
+		Subject:     entry.Subject,
+		Failures:    int(entry.Failures),
+		LastFailure: time.Unix(entry.LastFailure, 0),
+	}
+
+	if entry.LockedUntil > 0 {
+		lockout.LockedUntil = time.Unix(entry.LockedUntil, 0)
+	}
+
+	return lockout
+}
+
+// normalizeLockoutLogin makes sure that the same login is counted together, even with surrounding whitespace.
+// Aliases are lowercase, so different spellings of them are counted together, too.
+func normalizeLockoutLogin(login string) string {
+	login = strings.TrimSpace(login)
+	if !strings.HasPrefix(login, "@") {
+		login = strings.ToLower(login)
+	}
+	return login
+}
@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ssbc/go-ssb-room/v2/internal/repo"
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginLockouts(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	testRepo := filepath.Join("testrun", t.Name())
+	os.RemoveAll(testRepo)
+	tr := repo.New(testRepo)
+
+	db, err := Open(tr)
+	r.NoError(err, "failed to open database")
+
+	// move the clock of the lockouts by hand
+	now := time.Now()
+	lockouts := LoginLockouts{db: db.db, now: func() time.Time { return now }}
+
+	r.NoError(lockouts.Check(ctx, "alice", "10.0.0.1"))
+
+	// the first failures are tolerated
+	for i := 1; i < lockoutLoginFailures; i++ {
+		started, err := lockouts.Failed(ctx, "alice", "10.0.0.1")
+		r.NoError(err)
+		r.Len(started, 0)
+	}
+	r.NoError(lockouts.Check(ctx, "alice", "10.0.0.1"))
+
+	lst, err := lockouts.List(ctx)
+	r.NoError(err)
+	r.Len(lst, 2)
+
+	// the next one locks out the login, but not yet the address
+	started, err := lockouts.Failed(ctx, "  Alice ", "10.0.0.1")
+	r.NoError(err)
+	r.Len(started, 1)
+	r.Equal(roomdb.LockoutKindLogin, started[0].Kind)
+	r.Equal("alice", started[0].Subject)
+	r.EqualValues(lockoutLoginFailures, started[0].Failures)
+	r.Equal(now.Add(lockoutBase).Unix(), started[0].LockedUntil.Unix())
+
+	err = lockouts.Check(ctx, "alice", "10.0.0.2")
+	var locked roomdb.ErrLockedOut
+	r.True(errors.As(err, &locked), "expected lockout: %v", err)
+	r.Equal(roomdb.LockoutKindLogin, locked.Kind)
+	r.Equal("alice", locked.Subject)
+
+	// other logins from the same address are fine
+	r.NoError(lockouts.Check(ctx, "bob", "10.0.0.1"))
+
+	// the lockout ends, the next failure doubles it
+	now = now.Add(lockoutBase + time.Second)
+	r.NoError(lockouts.Check(ctx, "alice", "10.0.0.1"))
+
+	started, err = lockouts.Failed(ctx, "alice", "10.0.0.1")
+	r.NoError(err)
+	r.Len(started, 1)
+	r.Equal(now.Add(2*lockoutBase).Unix(), started[0].LockedUntil.Unix())
+
+	// a success resets the login
+	now = now.Add(2*lockoutBase + time.Second)
+	r.NoError(lockouts.Succeeded(ctx, "alice"))
+	r.NoError(lockouts.Check(ctx, "alice", "10.0.0.1"))
+
+	lst, err = lockouts.List(ctx)
+	r.NoError(err)
+	r.Len(lst, 1)
+	r.Equal(roomdb.LockoutKindAddress, lst[0].Kind)
+	r.Equal("10.0.0.1", lst[0].Subject)
+	r.EqualValues(lockoutLoginFailures+1, lst[0].Failures)
+	r.False(lst[0].Active())
+
+	// guessing many logins from one address locks it out
+	for i := lockoutLoginFailures + 2; i < lockoutAddressFailures; i++ {
+		started, err = lockouts.Failed(ctx, "guess", "10.0.0.1")
+		r.NoError(err)
+
+		for _, l := range started {
+			r.NotEqual(roomdb.LockoutKindAddress, l.Kind, "locked the address too early")
+		}
+
+		// a new login every time
+		r.NoError(lockouts.Succeeded(ctx, "guess"))
+	}
+
+	started, err = lockouts.Failed(ctx, "guess", "10.0.0.1")
+	r.NoError(err)
+	r.Len(started, 1)
+	r.Equal(roomdb.LockoutKindAddress, started[0].Kind)
+
+	err = lockouts.Check(ctx, "carla", "10.0.0.1")
+	r.True(errors.As(err, &locked), "expected lockout: %v", err)
+	r.Equal(roomdb.LockoutKindAddress, locked.Kind)
+
+	// an admin clears it
+	lst, err = lockouts.List(ctx)
+	r.NoError(err)
+	r.Len(lst, 2)
+	r.Equal(roomdb.LockoutKindAddress, lst[0].Kind, "locked ones come first")
+	r.True(lst[0].Active())
+
+	r.NoError(lockouts.Clear(ctx, lst[0].ID))
+	r.NoError(lockouts.Check(ctx, "carla", "10.0.0.1"))
+
+	err = lockouts.Clear(ctx, lst[0].ID)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "unexpected error: %v", err)
+
+	// old failures are forgotten
+	now = now.Add(lockoutForgetAfter + time.Minute)
+	_, err = lockouts.Failed(ctx, "dave", "")
+	r.NoError(err)
+
+	lst, err = lockouts.List(ctx)
+	r.NoError(err)
+	r.Len(lst, 2, "dave and the unknown address")
+	for _, l := range lst {
+		if l.Kind == roomdb.LockoutKindLogin {
+			r.Equal("dave", l.Subject)
+		} else {
+			r.Equal(unknownAddress, l.Subject)
+		}
+	}
+
+	// failures without an address count together
+	for i := 2; i < lockoutAddressFailures; i++ {
+		_, err = lockouts.Failed(ctx, fmt.Sprintf("nobody-%d", i), "")
+		r.NoError(err)
+	}
+	r.NoError(lockouts.Check(ctx, "erin", ""))
+
+	started, err = lockouts.Failed(ctx, "nobody", "")
+	r.NoError(err)
+	r.Len(started, 1)
+	r.Equal(roomdb.LockoutKindAddress, started[0].Kind)
+	r.Equal(unknownAddress, started[0].Subject)
+
+	err = lockouts.Check(ctx, "erin", "")
+	r.True(errors.As(err, &locked), "expected lockout: %v", err)
+	r.Equal(roomdb.LockoutKindAddress, locked.Kind)
+
+	// the backoff is capped
+	r.Equal(lockoutMax, lockoutDuration(100))
+
+	r.NoError(db.Close())
+}
@@ -36,6 +36,8 @@ func (Members) getAliases(mEntry *models.Member) []roomdb.Alias {
 		aliases[j].Feed = mEntry.PubKey.FeedRef
 		aliases[j].Name = aEntry.Name
 		aliases[j].Signature = aEntry.Signature
+		// only ever set from a valid feed ref, see Aliases.AuthorizeTransfer
+		aliases[j].TransferTo, _ = aliasTransferTo(aEntry)
 	}
 	return aliases
 }
@@ -182,12 +184,16 @@ func (m Members) RemoveID(ctx context.Context, id int64) error {
 		return err
 	}
 
-	_, err = entry.Delete(ctx, m.db)
-	if err != nil {
-		return err
-	}
+	return transact(m.db, func(tx *sql.Tx) error {
+		_, err = entry.Delete(ctx, tx)
+		if err != nil {
+			return err
+		}
 
-	return nil
+		// the web sessions don't reference the members table, since they can exist before somebody signed in
+		_, err = models.WebSessions(qm.Where("member_id = ?", id)).DeleteAll(ctx, tx)
+		return err
+	})
 }
 
 // SetRole updates the role r of the passed memberID.
@@ -0,0 +1,57 @@
+-- SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+--
+-- SPDX-License-Identifier: CC0-1.0
+
+-- +migrate Up
+
+-- add last use, user agent and remote address to the sign-in with ssb sessions
+-- ============================================================================
+
+-- sqlite can't add a column with a non-constant default (like CURRENT_TIMESTAMP)
+-- so the table is re-created, the same way as in 04-overhaul-fallback-auth.sql
+CREATE TABLE updated_siwssb_sessions (
+  id            INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+  token         TEXT UNIQUE NOT NULL,
+  member_id     INTEGER NOT NULL,
+  created_at    DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  last_used_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  user_agent    TEXT NOT NULL DEFAULT '',
+  remote_addr   TEXT NOT NULL DEFAULT '',
+
+  FOREIGN KEY ( member_id ) REFERENCES members( "id" ) ON DELETE CASCADE
+);
+
+-- copy existing sessions, they were last used when they were created as far as we know
+INSERT INTO updated_siwssb_sessions(id, token, member_id, created_at, last_used_at)
+SELECT id, token, member_id, created_at, created_at
+FROM SIWSSB_sessions;
+
+DROP INDEX SIWSSB_by_token;
+DROP INDEX SIWSSB_by_member;
+DROP TABLE SIWSSB_sessions;
+ALTER TABLE updated_siwssb_sessions RENAME TO SIWSSB_sessions;
+
+CREATE UNIQUE INDEX SIWSSB_by_token ON SIWSSB_sessions(token);
+CREATE INDEX SIWSSB_by_member ON SIWSSB_sessions(member_id);
+
+-- +migrate Down
+CREATE TABLE previous_siwssb_sessions (
+  id            INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+  token         TEXT UNIQUE NOT NULL,
+  member_id     INTEGER NOT NULL,
+  created_at    DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  FOREIGN KEY ( member_id ) REFERENCES members( "id" ) ON DELETE CASCADE
+);
+
+INSERT INTO previous_siwssb_sessions(id, token, member_id, created_at)
+SELECT id, token, member_id, created_at
+FROM SIWSSB_sessions;
+
+DROP INDEX SIWSSB_by_token;
+DROP INDEX SIWSSB_by_member;
+DROP TABLE SIWSSB_sessions;
+ALTER TABLE previous_siwssb_sessions RENAME TO SIWSSB_sessions;
+
+CREATE UNIQUE INDEX SIWSSB_by_token ON SIWSSB_sessions(token);
+CREATE INDEX SIWSSB_by_member ON SIWSSB_sessions(member_id);
@@ -0,0 +1,29 @@
+-- SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+--
+-- SPDX-License-Identifier: CC0-1.0
+
+-- +migrate Up
+
+-- webauthn credentials (passkeys) that members can use to sign into the dashboard
+-- ===============================================================================
+CREATE TABLE webauthn_credentials (
+  id                INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+  member_id         INTEGER NOT NULL,
+  name              TEXT NOT NULL DEFAULT '',
+  credential_id     BLOB UNIQUE NOT NULL,
+  public_key        BLOB NOT NULL,
+  attestation_type  TEXT NOT NULL DEFAULT '',
+  sign_count        INTEGER NOT NULL DEFAULT 0,
+  created_at        DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  last_used_at      DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  FOREIGN KEY ( member_id ) REFERENCES members( "id" ) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX webauthn_credentials_by_credential_id ON webauthn_credentials(credential_id);
+CREATE INDEX webauthn_credentials_by_member ON webauthn_credentials(member_id);
+
+-- +migrate Down
+DROP INDEX webauthn_credentials_by_credential_id;
+DROP INDEX webauthn_credentials_by_member;
+DROP TABLE webauthn_credentials;
@@ -0,0 +1,43 @@
+-- SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+--
+-- SPDX-License-Identifier: CC0-1.0
+
+-- +migrate Up
+
+-- time-based one-time password (TOTP) secrets, the second factor for the fallback password sign-in
+-- ===============================================================================================
+CREATE TABLE totp_secrets (
+  id            INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+  member_id     INTEGER UNIQUE NOT NULL,
+  secret        TEXT NOT NULL,
+  confirmed     BOOLEAN NOT NULL DEFAULT false, -- only confirmed secrets are asked for during sign-in
+  last_step     INTEGER NOT NULL DEFAULT 0,     -- the last time step that was used, so that codes can't be replayed
+  created_at    DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  FOREIGN KEY ( member_id ) REFERENCES members( "id" ) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX totp_secrets_by_member ON totp_secrets(member_id);
+
+-- single-use recovery codes, for when the authenticator is lost
+CREATE TABLE totp_recovery_codes (
+  id          INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+  member_id   INTEGER NOT NULL,
+  code_hash   BLOB NOT NULL,
+
+  FOREIGN KEY ( member_id ) REFERENCES members( "id" ) ON DELETE CASCADE
+);
+
+CREATE INDEX totp_recovery_codes_by_member ON totp_recovery_codes(member_id);
+
+-- admins can require the second factor for the fallback sign-in of admins and moderators
+ALTER TABLE config ADD COLUMN totp_mandatory boolean NOT NULL DEFAULT false;
+
+-- +migrate Down
+ALTER TABLE config DROP COLUMN totp_mandatory;
+
+DROP INDEX totp_recovery_codes_by_member;
+DROP TABLE totp_recovery_codes;
+
+DROP INDEX totp_secrets_by_member;
+DROP TABLE totp_secrets;
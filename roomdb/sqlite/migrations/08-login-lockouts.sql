@@ -0,0 +1,24 @@
+-- SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+--
+-- SPDX-License-Identifier: CC0-1.0
+
+-- +migrate Up
+
+-- failed fallback password sign-ins, counted per login (alias or feed) and per IP address
+-- ======================================================================================
+CREATE TABLE login_lockouts (
+  id            INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+  kind          INTEGER NOT NULL,           -- see roomdb.LockoutKind
+  subject       TEXT NOT NULL,              -- the login or the address
+  failures      INTEGER NOT NULL DEFAULT 0,
+  last_failure  INTEGER NOT NULL DEFAULT 0, -- unix timestamps, so that they can be compared in queries
+  locked_until  INTEGER NOT NULL DEFAULT 0,
+
+  UNIQUE(kind, subject)
+);
+
+CREATE UNIQUE INDEX login_lockouts_by_subject ON login_lockouts(kind, subject);
+
+-- +migrate Down
+DROP INDEX login_lockouts_by_subject;
+DROP TABLE login_lockouts;
@@ -0,0 +1,28 @@
+-- SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+--
+-- SPDX-License-Identifier: CC0-1.0
+
+-- +migrate Up
+
+-- personal api tokens of admins and moderators, for scripts that use the dashboard
+-- ================================================================================
+CREATE TABLE api_tokens (
+  id            INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+  member_id     INTEGER NOT NULL,
+  name          TEXT NOT NULL DEFAULT '',
+  hashed_token  TEXT UNIQUE NOT NULL,
+  scopes        TEXT NOT NULL DEFAULT '', -- space separated, see roomdb.APITokenScope
+  created_at    DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  expires_at    DATETIME NOT NULL,
+  last_used_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  FOREIGN KEY ( member_id ) REFERENCES members( "id" ) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX api_tokens_by_hashed_token ON api_tokens(hashed_token);
+CREATE INDEX api_tokens_by_member ON api_tokens(member_id);
+
+-- +migrate Down
+DROP INDEX api_tokens_by_hashed_token;
+DROP INDEX api_tokens_by_member;
+DROP TABLE api_tokens;
@@ -0,0 +1,43 @@
+-- SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+--
+-- SPDX-License-Identifier: CC0-1.0
+
+-- +migrate Up
+
+-- apps that members can sign into with their room identity, registered by admins
+-- ===============================================================================
+CREATE TABLE oidc_clients (
+  id             INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+  client_id      TEXT UNIQUE NOT NULL,
+  hashed_secret  TEXT NOT NULL,
+  name           TEXT NOT NULL,
+  redirect_uris  TEXT NOT NULL, -- newline separated
+  created_at     DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX oidc_clients_by_client_id ON oidc_clients(client_id);
+
+-- the authorization codes of the code flow, they are exchanged for the id token by the client
+-- ============================================================================================
+CREATE TABLE oidc_auth_codes (
+  id              INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+  hashed_code     TEXT UNIQUE NOT NULL,
+  client_id       INTEGER NOT NULL,
+  member_id       INTEGER NOT NULL,
+  redirect_uri    TEXT NOT NULL,
+  code_challenge  TEXT NOT NULL, -- PKCE, S256 only
+  nonce           TEXT NOT NULL DEFAULT '',
+  scope           TEXT NOT NULL,
+  expires_at      DATETIME NOT NULL,
+
+  FOREIGN KEY ( client_id ) REFERENCES oidc_clients( "id" ) ON DELETE CASCADE,
+  FOREIGN KEY ( member_id ) REFERENCES members( "id" ) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX oidc_auth_codes_by_hashed_code ON oidc_auth_codes(hashed_code);
+
+-- +migrate Down
+DROP INDEX oidc_auth_codes_by_hashed_code;
+DROP TABLE oidc_auth_codes;
+DROP INDEX oidc_clients_by_client_id;
+DROP TABLE oidc_clients;
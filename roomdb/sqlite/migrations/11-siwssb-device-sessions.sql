@@ -0,0 +1,11 @@
+-- SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+--
+-- SPDX-License-Identifier: CC0-1.0
+
+-- +migrate Up
+
+-- sessions that were handed to a device by the device sign-in, only these can be used as bearer tokens
+ALTER TABLE SIWSSB_sessions ADD COLUMN device BOOLEAN NOT NULL DEFAULT false;
+
+-- +migrate Down
+ALTER TABLE SIWSSB_sessions DROP COLUMN device;
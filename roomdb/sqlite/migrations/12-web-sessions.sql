@@ -0,0 +1,26 @@
+-- SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+--
+-- SPDX-License-Identifier: CC0-1.0
+
+-- +migrate Up
+
+-- the sign-in sessions of the web dashboard, the cookie of the browser only holds the id
+-- =======================================================================================
+CREATE TABLE web_sessions (
+  id               INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+  hashed_id        TEXT UNIQUE NOT NULL,
+  member_id        INTEGER NOT NULL DEFAULT 0, -- 0 until somebody signed in with it
+  data             BLOB NOT NULL,
+  created_at       DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  last_used_at     DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  expires_at       DATETIME NOT NULL,
+  idle_expires_at  DATETIME NOT NULL
+);
+
+CREATE UNIQUE INDEX web_sessions_by_hashed_id ON web_sessions(hashed_id);
+CREATE INDEX web_sessions_by_member ON web_sessions(member_id);
+
+-- +migrate Down
+DROP INDEX web_sessions_by_hashed_id;
+DROP INDEX web_sessions_by_member;
+DROP TABLE web_sessions;
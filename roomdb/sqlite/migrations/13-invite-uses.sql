@@ -0,0 +1,25 @@
+-- SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+--
+-- SPDX-License-Identifier: CC0-1.0
+
+-- +migrate Up
+
+-- who used an invite and who created it, so that moderators can see who brought whom into the room
+-- it doesn't reference the members or invites tables, so that it's kept when they are removed
+-- ==============================================================================================
+CREATE TABLE invite_uses (
+  id           INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+  invite_id    INTEGER NOT NULL,
+  invited_by   INTEGER NOT NULL, -- the member that created the invite
+  member_id    INTEGER NOT NULL, -- the member that used it
+  pub_key      TEXT NOT NULL,    -- the feed that used it
+  used_at      DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX invite_uses_by_inviter ON invite_uses(invited_by);
+CREATE INDEX invite_uses_by_member ON invite_uses(member_id);
+
+-- +migrate Down
+DROP INDEX invite_uses_by_inviter;
+DROP INDEX invite_uses_by_member;
+DROP TABLE invite_uses;
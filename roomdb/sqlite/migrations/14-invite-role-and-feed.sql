@@ -0,0 +1,13 @@
+-- SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+--
+-- SPDX-License-Identifier: CC0-1.0
+
+-- +migrate Up
+
+-- the role that the new member gets, and optionally the only feed that can use the invite
+ALTER TABLE invites ADD COLUMN role INTEGER NOT NULL DEFAULT 1; -- roomdb.RoleMember
+ALTER TABLE invites ADD COLUMN for_feed TEXT NOT NULL DEFAULT ''; -- empty if anyone can use it
+
+-- +migrate Down
+ALTER TABLE invites DROP COLUMN role;
+ALTER TABLE invites DROP COLUMN for_feed;
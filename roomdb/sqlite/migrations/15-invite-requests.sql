@@ -0,0 +1,29 @@
+-- SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+--
+-- SPDX-License-Identifier: CC0-1.0
+
+-- +migrate Up
+
+-- requests to join the room, made by people that don't have an invite
+-- ====================================================================
+CREATE TABLE invite_requests (
+  id            INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+  hashed_token  TEXT UNIQUE NOT NULL,         -- lets the requester check on it, hashed like the invite tokens
+  pub_key       TEXT NOT NULL,
+  message       TEXT NOT NULL DEFAULT '',
+  address       TEXT NOT NULL DEFAULT '',     -- the network it came from, to limit how many can be made
+  state         INTEGER NOT NULL DEFAULT 0,   -- see roomdb.InviteRequestState
+  invite_token  TEXT NOT NULL DEFAULT '',     -- the invite for the feed, once it was approved
+  created_at    DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  decided_at    DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX invite_requests_by_hashed_token ON invite_requests(hashed_token);
+CREATE INDEX invite_requests_by_pub_key ON invite_requests(pub_key);
+CREATE INDEX invite_requests_by_address ON invite_requests(address, created_at);
+
+-- +migrate Down
+DROP INDEX invite_requests_by_hashed_token;
+DROP INDEX invite_requests_by_pub_key;
+DROP INDEX invite_requests_by_address;
+DROP TABLE invite_requests;
@@ -0,0 +1,23 @@
+-- SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+--
+-- SPDX-License-Identifier: CC0-1.0
+
+-- +migrate Up
+
+-- invites that were created together, for a workshop or a meetup
+-- ================================================================
+CREATE TABLE invite_batches (
+  id            INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+  name          TEXT NOT NULL,
+  created_by    INTEGER NOT NULL,
+  created_at    DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  expires_at    DATETIME NOT NULL                -- the invites of the batch can't be used after this
+);
+
+ALTER TABLE invites ADD COLUMN batch_id INTEGER NOT NULL DEFAULT 0; -- 0 if it isn't part of a batch
+CREATE INDEX invites_by_batch ON invites(batch_id);
+
+-- +migrate Down
+DROP INDEX invites_by_batch;
+ALTER TABLE invites DROP COLUMN batch_id;
+DROP TABLE invite_batches;
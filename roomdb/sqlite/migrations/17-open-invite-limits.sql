@@ -0,0 +1,21 @@
+-- SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+--
+-- SPDX-License-Identifier: CC0-1.0
+
+-- +migrate Up
+
+-- how hard it is to create invites in open mode, 0 turns the check off.
+-- It starts off, so that apps which don't solve the puzzle yet keep working until the admins turn it on.
+ALTER TABLE config ADD COLUMN open_invite_pow_bits INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE config ADD COLUMN open_invites_per_address INTEGER NOT NULL DEFAULT 5;
+
+ALTER TABLE invites ADD COLUMN open_mode boolean NOT NULL DEFAULT false; -- created by anyone, while the room was open
+ALTER TABLE invites ADD COLUMN address TEXT NOT NULL DEFAULT ''; -- the IP address that created an open-mode invite
+CREATE INDEX invites_by_address ON invites(open_mode, address);
+
+-- +migrate Down
+DROP INDEX invites_by_address;
+ALTER TABLE invites DROP COLUMN address;
+ALTER TABLE invites DROP COLUMN open_mode;
+ALTER TABLE config DROP COLUMN open_invites_per_address;
+ALTER TABLE config DROP COLUMN open_invite_pow_bits;
@@ -0,0 +1,14 @@
+-- SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+--
+-- SPDX-License-Identifier: CC0-1.0
+
+-- +migrate Up
+
+-- how many unused invites each member can have in community mode, 0 means no limit
+ALTER TABLE config ADD COLUMN member_invite_quota INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX invites_by_creator ON invites(created_by);
+
+-- +migrate Down
+DROP INDEX invites_by_creator;
+ALTER TABLE config DROP COLUMN member_invite_quota;
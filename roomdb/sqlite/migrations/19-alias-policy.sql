@@ -0,0 +1,19 @@
+-- SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+--
+-- SPDX-License-Identifier: CC0-1.0
+
+-- +migrate Up
+
+-- which aliases can be registered, the lists are stored as JSON arrays of strings
+ALTER TABLE config ADD COLUMN alias_reserved TEXT NOT NULL DEFAULT '["admin","administrator","api","help","mail","moderator","root","support","www"]';
+ALTER TABLE config ADD COLUMN alias_blocked TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE config ADD COLUMN alias_min_length INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE config ADD COLUMN alias_allow_hyphens boolean NOT NULL DEFAULT false;
+ALTER TABLE config ADD COLUMN alias_per_member INTEGER NOT NULL DEFAULT 0; -- 0 means no limit
+
+-- +migrate Down
+ALTER TABLE config DROP COLUMN alias_per_member;
+ALTER TABLE config DROP COLUMN alias_allow_hyphens;
+ALTER TABLE config DROP COLUMN alias_min_length;
+ALTER TABLE config DROP COLUMN alias_blocked;
+ALTER TABLE config DROP COLUMN alias_reserved;
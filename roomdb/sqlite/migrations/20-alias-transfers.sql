@@ -0,0 +1,11 @@
+-- SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+--
+-- SPDX-License-Identifier: CC0-1.0
+
+-- +migrate Up
+
+-- the feed that the owner (or a moderator) allowed to take over the alias
+ALTER TABLE aliases ADD COLUMN transfer_to TEXT NOT NULL DEFAULT ''; -- empty if there is no pending transfer
+
+-- +migrate Down
+ALTER TABLE aliases DROP COLUMN transfer_to;
@@ -26,25 +26,37 @@ import (
 
 // SIWSSBSession is an object representing the database table.
 type SIWSSBSession struct {
-	ID        int64     `boil:"id" json:"id" toml:"id" yaml:"id"`
-	Token     string    `boil:"token" json:"token" toml:"token" yaml:"token"`
-	MemberID  int64     `boil:"member_id" json:"member_id" toml:"member_id" yaml:"member_id"`
-	CreatedAt time.Time `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	ID         int64     `boil:"id" json:"id" toml:"id" yaml:"id"`
+	Token      string    `boil:"token" json:"token" toml:"token" yaml:"token"`
+	MemberID   int64     `boil:"member_id" json:"member_id" toml:"member_id" yaml:"member_id"`
+	CreatedAt  time.Time `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	LastUsedAt time.Time `boil:"last_used_at" json:"last_used_at" toml:"last_used_at" yaml:"last_used_at"`
+	UserAgent  string    `boil:"user_agent" json:"user_agent" toml:"user_agent" yaml:"user_agent"`
+	RemoteAddr string    `boil:"remote_addr" json:"remote_addr" toml:"remote_addr" yaml:"remote_addr"`
+	Device     bool      `boil:"device" json:"device" toml:"device" yaml:"device"`
 
 	R *sIWSSBSessionR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L sIWSSBSessionL  `boil:"-" json:"-" toml:"-" yaml:"-"`
 }
 
 var SIWSSBSessionColumns = struct {
-	ID        string
-	Token     string
-	MemberID  string
-	CreatedAt string
+	ID         string
+	Token      string
+	MemberID   string
+	CreatedAt  string
+	LastUsedAt string
+	UserAgent  string
+	RemoteAddr string
+	Device     string
 }{
-	ID:        "id",
-	Token:     "token",
-	MemberID:  "member_id",
-	CreatedAt: "created_at",
+	ID:         "id",
+	Token:      "token",
+	MemberID:   "member_id",
+	CreatedAt:  "created_at",
+	LastUsedAt: "last_used_at",
+	UserAgent:  "user_agent",
+	RemoteAddr: "remote_addr",
+	Device:     "device",
 }
 
 // Generated where
@@ -117,15 +129,23 @@ func (w whereHelpertime_Time) GTE(x time.Time) qm.QueryMod {
 }
 
 var SIWSSBSessionWhere = struct {
-	ID        whereHelperint64
-	Token     whereHelperstring
-	MemberID  whereHelperint64
-	CreatedAt whereHelpertime_Time
+	ID         whereHelperint64
+	Token      whereHelperstring
+	MemberID   whereHelperint64
+	CreatedAt  whereHelpertime_Time
+	LastUsedAt whereHelpertime_Time
+	UserAgent  whereHelperstring
+	RemoteAddr whereHelperstring
+	Device     whereHelperbool
 }{
-	ID:        whereHelperint64{field: "\"SIWSSB_sessions\".\"id\""},
-	Token:     whereHelperstring{field: "\"SIWSSB_sessions\".\"token\""},
-	MemberID:  whereHelperint64{field: "\"SIWSSB_sessions\".\"member_id\""},
-	CreatedAt: whereHelpertime_Time{field: "\"SIWSSB_sessions\".\"created_at\""},
+	ID:         whereHelperint64{field: "\"SIWSSB_sessions\".\"id\""},
+	Token:      whereHelperstring{field: "\"SIWSSB_sessions\".\"token\""},
+	MemberID:   whereHelperint64{field: "\"SIWSSB_sessions\".\"member_id\""},
+	CreatedAt:  whereHelpertime_Time{field: "\"SIWSSB_sessions\".\"created_at\""},
+	LastUsedAt: whereHelpertime_Time{field: "\"SIWSSB_sessions\".\"last_used_at\""},
+	UserAgent:  whereHelperstring{field: "\"SIWSSB_sessions\".\"user_agent\""},
+	RemoteAddr: whereHelperstring{field: "\"SIWSSB_sessions\".\"remote_addr\""},
+	Device:     whereHelperbool{field: "\"SIWSSB_sessions\".\"device\""},
 }
 
 // SIWSSBSessionRels is where relationship names are stored.
@@ -149,9 +169,9 @@ func (*sIWSSBSessionR) NewStruct() *sIWSSBSessionR {
 type sIWSSBSessionL struct{}
 
 var (
-	sIWSSBSessionAllColumns            = []string{"id", "token", "member_id", "created_at"}
+	sIWSSBSessionAllColumns            = []string{"id", "token", "member_id", "created_at", "last_used_at", "user_agent", "remote_addr", "device"}
 	sIWSSBSessionColumnsWithoutDefault = []string{}
-	sIWSSBSessionColumnsWithDefault    = []string{"id", "token", "member_id", "created_at"}
+	sIWSSBSessionColumnsWithDefault    = []string{"id", "token", "member_id", "created_at", "last_used_at", "user_agent", "remote_addr", "device"}
 	sIWSSBSessionPrimaryKeyColumns     = []string{"id"}
 )
 
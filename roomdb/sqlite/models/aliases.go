@@ -26,25 +26,28 @@ import (
 
 // Alias is an object representing the database table.
 type Alias struct {
-	ID        int64  `boil:"id" json:"id" toml:"id" yaml:"id"`
-	Name      string `boil:"name" json:"name" toml:"name" yaml:"name"`
-	MemberID  int64  `boil:"member_id" json:"member_id" toml:"member_id" yaml:"member_id"`
-	Signature []byte `boil:"signature" json:"signature" toml:"signature" yaml:"signature"`
+	ID         int64  `boil:"id" json:"id" toml:"id" yaml:"id"`
+	Name       string `boil:"name" json:"name" toml:"name" yaml:"name"`
+	MemberID   int64  `boil:"member_id" json:"member_id" toml:"member_id" yaml:"member_id"`
+	Signature  []byte `boil:"signature" json:"signature" toml:"signature" yaml:"signature"`
+	TransferTo string `boil:"transfer_to" json:"transfer_to" toml:"transfer_to" yaml:"transfer_to"`
 
 	R *aliasR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L aliasL  `boil:"-" json:"-" toml:"-" yaml:"-"`
 }
 
 var AliasColumns = struct {
-	ID        string
-	Name      string
-	MemberID  string
-	Signature string
+	ID         string
+	Name       string
+	MemberID   string
+	Signature  string
+	TransferTo string
 }{
-	ID:        "id",
-	Name:      "name",
-	MemberID:  "member_id",
-	Signature: "signature",
+	ID:         "id",
+	Name:       "name",
+	MemberID:   "member_id",
+	Signature:  "signature",
+	TransferTo: "transfer_to",
 }
 
 // Generated where
@@ -59,15 +62,17 @@ func (w whereHelper__byte) GT(x []byte) qm.QueryMod  { return qmhelper.Where(w.f
 func (w whereHelper__byte) GTE(x []byte) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.GTE, x) }
 
 var AliasWhere = struct {
-	ID        whereHelperint64
-	Name      whereHelperstring
-	MemberID  whereHelperint64
-	Signature whereHelper__byte
+	ID         whereHelperint64
+	Name       whereHelperstring
+	MemberID   whereHelperint64
+	Signature  whereHelper__byte
+	TransferTo whereHelperstring
 }{
-	ID:        whereHelperint64{field: "\"aliases\".\"id\""},
-	Name:      whereHelperstring{field: "\"aliases\".\"name\""},
-	MemberID:  whereHelperint64{field: "\"aliases\".\"member_id\""},
-	Signature: whereHelper__byte{field: "\"aliases\".\"signature\""},
+	ID:         whereHelperint64{field: "\"aliases\".\"id\""},
+	Name:       whereHelperstring{field: "\"aliases\".\"name\""},
+	MemberID:   whereHelperint64{field: "\"aliases\".\"member_id\""},
+	Signature:  whereHelper__byte{field: "\"aliases\".\"signature\""},
+	TransferTo: whereHelperstring{field: "\"aliases\".\"transfer_to\""},
 }
 
 // AliasRels is where relationship names are stored.
@@ -91,9 +96,9 @@ func (*aliasR) NewStruct() *aliasR {
 type aliasL struct{}
 
 var (
-	aliasAllColumns            = []string{"id", "name", "member_id", "signature"}
+	aliasAllColumns            = []string{"id", "name", "member_id", "signature", "transfer_to"}
 	aliasColumnsWithoutDefault = []string{}
-	aliasColumnsWithDefault    = []string{"id", "name", "member_id", "signature"}
+	aliasColumnsWithDefault    = []string{"id", "name", "member_id", "signature", "transfer_to"}
 	aliasPrimaryKeyColumns     = []string{"id"}
 )
 
@@ -10,25 +10,47 @@ package models
 var TableNames = struct {
 	SIWSSBSessions      string
 	Aliases             string
+	APITokens           string
 	Config              string
 	DeniedKeys          string
 	FallbackPasswords   string
 	FallbackResetTokens string
+	InviteBatches       string
+	InviteRequests      string
+	InviteUses          string
 	Invites             string
+	LoginLockouts       string
 	Members             string
 	Notices             string
+	OidcAuthCodes       string
+	OidcClients         string
 	PinNotices          string
 	Pins                string
+	TotpRecoveryCodes   string
+	TotpSecrets         string
+	WebSessions         string
+	WebauthnCredentials string
 }{
 	SIWSSBSessions:      "SIWSSB_sessions",
 	Aliases:             "aliases",
+	APITokens:           "api_tokens",
 	Config:              "config",
 	DeniedKeys:          "denied_keys",
 	FallbackPasswords:   "fallback_passwords",
 	FallbackResetTokens: "fallback_reset_tokens",
+	InviteBatches:       "invite_batches",
+	InviteRequests:      "invite_requests",
+	InviteUses:          "invite_uses",
 	Invites:             "invites",
+	LoginLockouts:       "login_lockouts",
 	Members:             "members",
 	Notices:             "notices",
+	OidcAuthCodes:       "oidc_auth_codes",
+	OidcClients:         "oidc_clients",
 	PinNotices:          "pin_notices",
 	Pins:                "pins",
+	TotpRecoveryCodes:   "totp_recovery_codes",
+	TotpSecrets:         "totp_secrets",
+	WebSessions:         "web_sessions",
+	WebauthnCredentials: "webauthn_credentials",
 }
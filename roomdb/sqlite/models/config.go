@@ -31,6 +31,15 @@ type Config struct {
 	PrivacyMode            roomdb.PrivacyMode `boil:"privacyMode" json:"privacyMode" toml:"privacyMode" yaml:"privacyMode"`
 	DefaultLanguage        string             `boil:"defaultLanguage" json:"defaultLanguage" toml:"defaultLanguage" yaml:"defaultLanguage"`
 	UseSubdomainForAliases bool               `boil:"use_subdomain_for_aliases" json:"use_subdomain_for_aliases" toml:"use_subdomain_for_aliases" yaml:"use_subdomain_for_aliases"`
+	TotpMandatory          bool               `boil:"totp_mandatory" json:"totp_mandatory" toml:"totp_mandatory" yaml:"totp_mandatory"`
+	OpenInvitePowBits      int64              `boil:"open_invite_pow_bits" json:"open_invite_pow_bits" toml:"open_invite_pow_bits" yaml:"open_invite_pow_bits"`
+	OpenInvitesPerAddress  int64              `boil:"open_invites_per_address" json:"open_invites_per_address" toml:"open_invites_per_address" yaml:"open_invites_per_address"`
+	MemberInviteQuota      int64              `boil:"member_invite_quota" json:"member_invite_quota" toml:"member_invite_quota" yaml:"member_invite_quota"`
+	AliasReserved          string             `boil:"alias_reserved" json:"alias_reserved" toml:"alias_reserved" yaml:"alias_reserved"`
+	AliasBlocked           string             `boil:"alias_blocked" json:"alias_blocked" toml:"alias_blocked" yaml:"alias_blocked"`
+	AliasMinLength         int64              `boil:"alias_min_length" json:"alias_min_length" toml:"alias_min_length" yaml:"alias_min_length"`
+	AliasAllowHyphens      bool               `boil:"alias_allow_hyphens" json:"alias_allow_hyphens" toml:"alias_allow_hyphens" yaml:"alias_allow_hyphens"`
+	AliasPerMember         int64              `boil:"alias_per_member" json:"alias_per_member" toml:"alias_per_member" yaml:"alias_per_member"`
 
 	R *configR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L configL  `boil:"-" json:"-" toml:"-" yaml:"-"`
@@ -41,11 +50,29 @@ var ConfigColumns = struct {
 	PrivacyMode            string
 	DefaultLanguage        string
 	UseSubdomainForAliases string
+	TotpMandatory          string
+	OpenInvitePowBits      string
+	OpenInvitesPerAddress  string
+	MemberInviteQuota      string
+	AliasReserved          string
+	AliasBlocked           string
+	AliasMinLength         string
+	AliasAllowHyphens      string
+	AliasPerMember         string
 }{
 	ID:                     "id",
 	PrivacyMode:            "privacyMode",
 	DefaultLanguage:        "defaultLanguage",
 	UseSubdomainForAliases: "use_subdomain_for_aliases",
+	TotpMandatory:          "totp_mandatory",
+	OpenInvitePowBits:      "open_invite_pow_bits",
+	OpenInvitesPerAddress:  "open_invites_per_address",
+	MemberInviteQuota:      "member_invite_quota",
+	AliasReserved:          "alias_reserved",
+	AliasBlocked:           "alias_blocked",
+	AliasMinLength:         "alias_min_length",
+	AliasAllowHyphens:      "alias_allow_hyphens",
+	AliasPerMember:         "alias_per_member",
 }
 
 // Generated where
@@ -85,11 +112,29 @@ var ConfigWhere = struct {
 	PrivacyMode            whereHelperroomdb_PrivacyMode
 	DefaultLanguage        whereHelperstring
 	UseSubdomainForAliases whereHelperbool
+	TotpMandatory          whereHelperbool
+	OpenInvitePowBits      whereHelperint64
+	OpenInvitesPerAddress  whereHelperint64
+	MemberInviteQuota      whereHelperint64
+	AliasReserved          whereHelperstring
+	AliasBlocked           whereHelperstring
+	AliasMinLength         whereHelperint64
+	AliasAllowHyphens      whereHelperbool
+	AliasPerMember         whereHelperint64
 }{
 	ID:                     whereHelperint64{field: "\"config\".\"id\""},
 	PrivacyMode:            whereHelperroomdb_PrivacyMode{field: "\"config\".\"privacyMode\""},
 	DefaultLanguage:        whereHelperstring{field: "\"config\".\"defaultLanguage\""},
 	UseSubdomainForAliases: whereHelperbool{field: "\"config\".\"use_subdomain_for_aliases\""},
+	TotpMandatory:          whereHelperbool{field: "\"config\".\"totp_mandatory\""},
+	OpenInvitePowBits:      whereHelperint64{field: "\"config\".\"open_invite_pow_bits\""},
+	OpenInvitesPerAddress:  whereHelperint64{field: "\"config\".\"open_invites_per_address\""},
+	MemberInviteQuota:      whereHelperint64{field: "\"config\".\"member_invite_quota\""},
+	AliasReserved:          whereHelperstring{field: "\"config\".\"alias_reserved\""},
+	AliasBlocked:           whereHelperstring{field: "\"config\".\"alias_blocked\""},
+	AliasMinLength:         whereHelperint64{field: "\"config\".\"alias_min_length\""},
+	AliasAllowHyphens:      whereHelperbool{field: "\"config\".\"alias_allow_hyphens\""},
+	AliasPerMember:         whereHelperint64{field: "\"config\".\"alias_per_member\""},
 }
 
 // ConfigRels is where relationship names are stored.
@@ -109,9 +154,9 @@ func (*configR) NewStruct() *configR {
 type configL struct{}
 
 var (
-	configAllColumns            = []string{"id", "privacyMode", "defaultLanguage", "use_subdomain_for_aliases"}
+	configAllColumns            = []string{"id", "privacyMode", "defaultLanguage", "use_subdomain_for_aliases", "totp_mandatory", "open_invite_pow_bits", "open_invites_per_address", "member_invite_quota", "alias_reserved", "alias_blocked", "alias_min_length", "alias_allow_hyphens", "alias_per_member"}
 	configColumnsWithoutDefault = []string{"privacyMode", "defaultLanguage", "use_subdomain_for_aliases"}
-	configColumnsWithDefault    = []string{"id"}
+	configColumnsWithDefault    = []string{"id", "totp_mandatory", "open_invite_pow_bits", "open_invites_per_address", "member_invite_quota", "alias_reserved", "alias_blocked", "alias_min_length", "alias_allow_hyphens", "alias_per_member"}
 	configPrimaryKeyColumns     = []string{"id"}
 )
 
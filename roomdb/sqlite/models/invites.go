@@ -31,6 +31,11 @@ type Invite struct {
 	CreatedBy   int64     `boil:"created_by" json:"created_by" toml:"created_by" yaml:"created_by"`
 	CreatedAt   time.Time `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
 	Active      bool      `boil:"active" json:"active" toml:"active" yaml:"active"`
+	Role        int64     `boil:"role" json:"role" toml:"role" yaml:"role"`
+	ForFeed     string    `boil:"for_feed" json:"for_feed" toml:"for_feed" yaml:"for_feed"`
+	BatchID     int64     `boil:"batch_id" json:"batch_id" toml:"batch_id" yaml:"batch_id"`
+	OpenMode    bool      `boil:"open_mode" json:"open_mode" toml:"open_mode" yaml:"open_mode"`
+	Address     string    `boil:"address" json:"address" toml:"address" yaml:"address"`
 
 	R *inviteR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L inviteL  `boil:"-" json:"-" toml:"-" yaml:"-"`
@@ -42,12 +47,22 @@ var InviteColumns = struct {
 	CreatedBy   string
 	CreatedAt   string
 	Active      string
+	Role        string
+	ForFeed     string
+	BatchID     string
+	OpenMode    string
+	Address     string
 }{
 	ID:          "id",
 	HashedToken: "hashed_token",
 	CreatedBy:   "created_by",
 	CreatedAt:   "created_at",
 	Active:      "active",
+	Role:        "role",
+	ForFeed:     "for_feed",
+	BatchID:     "batch_id",
+	OpenMode:    "open_mode",
+	Address:     "address",
 }
 
 // Generated where
@@ -58,12 +73,22 @@ var InviteWhere = struct {
 	CreatedBy   whereHelperint64
 	CreatedAt   whereHelpertime_Time
 	Active      whereHelperbool
+	Role        whereHelperint64
+	ForFeed     whereHelperstring
+	BatchID     whereHelperint64
+	OpenMode    whereHelperbool
+	Address     whereHelperstring
 }{
 	ID:          whereHelperint64{field: "\"invites\".\"id\""},
 	HashedToken: whereHelperstring{field: "\"invites\".\"hashed_token\""},
 	CreatedBy:   whereHelperint64{field: "\"invites\".\"created_by\""},
 	CreatedAt:   whereHelpertime_Time{field: "\"invites\".\"created_at\""},
 	Active:      whereHelperbool{field: "\"invites\".\"active\""},
+	Role:        whereHelperint64{field: "\"invites\".\"role\""},
+	ForFeed:     whereHelperstring{field: "\"invites\".\"for_feed\""},
+	BatchID:     whereHelperint64{field: "\"invites\".\"batch_id\""},
+	OpenMode:    whereHelperbool{field: "\"invites\".\"open_mode\""},
+	Address:     whereHelperstring{field: "\"invites\".\"address\""},
 }
 
 // InviteRels is where relationship names are stored.
@@ -87,9 +112,9 @@ func (*inviteR) NewStruct() *inviteR {
 type inviteL struct{}
 
 var (
-	inviteAllColumns            = []string{"id", "hashed_token", "created_by", "created_at", "active"}
+	inviteAllColumns            = []string{"id", "hashed_token", "created_by", "created_at", "active", "role", "for_feed", "batch_id", "open_mode", "address"}
 	inviteColumnsWithoutDefault = []string{}
-	inviteColumnsWithDefault    = []string{"id", "hashed_token", "created_by", "created_at", "active"}
+	inviteColumnsWithDefault    = []string{"id", "hashed_token", "created_by", "created_at", "active", "role", "for_feed", "batch_id", "open_mode", "address"}
 	invitePrimaryKeyColumns     = []string{"id"}
 )
 
@@ -22,6 +22,7 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"path/filepath"
 	"time"
@@ -42,10 +43,19 @@ type Database struct {
 	Invites Invites
 	Config  Config
 
+	InviteRequests InviteRequests
+
 	DeniedKeys DeniedKeys
 
 	PinnedNotices PinnedNotices
 	Notices       Notices
+
+	APITokens     APITokens
+	LoginLockouts LoginLockouts
+	OIDCClients   OIDCClients
+	TOTP          TOTP
+	WebAuthn      WebAuthn
+	WebSessions   WebSessions
 }
 
 // Open looks for a database file 'fname'
@@ -80,7 +90,11 @@ func Open(r repo.Interface) (*Database, error) {
 		log.Printf("roomdb: applied %d migrations", n)
 	}
 
-	if err := deleteConsumedInvites(db); err != nil {
+	if err := expireInviteBatches(db); err != nil {
+		return nil, err
+	}
+
+	if err := deleteRevokedInvites(db); err != nil {
 		return nil, err
 	}
 
@@ -88,7 +102,15 @@ func Open(r repo.Interface) (*Database, error) {
 		return nil, err
 	}
 
-	// scrub old invites and reset tokens
+	if err := deleteExpiredWebSessions(db); err != nil {
+		return nil, err
+	}
+
+	if err := deleteDecidedInviteRequests(db); err != nil {
+		return nil, err
+	}
+
+	// scrub old invites and batches, reset tokens, sessions and invite requests
 	go func() { // server might not restart as often
 		fiveDays := 5 * 24 * time.Hour
 		ticker := time.NewTicker(fiveDays)
@@ -97,29 +119,46 @@ func Open(r repo.Interface) (*Database, error) {
 				if err := deleteConsumedResetTokens(tx); err != nil {
 					return err
 				}
-				return deleteConsumedInvites(tx)
+				if err := deleteExpiredWebSessions(tx); err != nil {
+					return err
+				}
+				if err := deleteDecidedInviteRequests(tx); err != nil {
+					return err
+				}
+				if err := expireInviteBatches(tx); err != nil {
+					return err
+				}
+				return deleteRevokedInvites(tx)
 			})
 			if err != nil {
 				// TODO: hook up logging
-				log.Printf("roomdb: failed to clean up old invites: %s", err.Error())
+				log.Printf("roomdb: failed to clean up old invites and sessions: %s", err.Error())
 			}
 		}
 	}()
 
 	ml := Members{db}
+	il := Invites{db: db, members: ml}
 
 	roomdb := &Database{
 		db: db,
 
-		Aliases:       Aliases{db},
-		AuthFallback:  AuthFallback{db},
-		AuthWithSSB:   AuthWithSSB{db},
-		Config:        Config{db},
-		DeniedKeys:    DeniedKeys{db},
-		Invites:       Invites{db: db, members: ml},
-		Notices:       Notices{db},
-		Members:       ml,
-		PinnedNotices: PinnedNotices{db},
+		Aliases:        Aliases{db},
+		APITokens:      APITokens{db},
+		AuthFallback:   AuthFallback{db},
+		AuthWithSSB:    AuthWithSSB{db},
+		Config:         Config{db},
+		DeniedKeys:     DeniedKeys{db},
+		Invites:        il,
+		InviteRequests: InviteRequests{db: db, invites: il},
+		LoginLockouts:  LoginLockouts{db: db},
+		Notices:        Notices{db},
+		Members:        ml,
+		OIDCClients:    OIDCClients{db},
+		PinnedNotices:  PinnedNotices{db},
+		TOTP:           TOTP{db},
+		WebAuthn:       WebAuthn{db},
+		WebSessions:    WebSessions{db},
 	}
 
 	return roomdb, nil
@@ -130,6 +169,17 @@ func (t Database) Close() error {
 	return t.db.Close()
 }
 
+// unknownAddress is where requests without a known address are counted, all together.
+// That way the limits per address can't be dodged by hiding it, or by passing something that isn't an address.
+const unknownAddress = "unknown"
+
+func addressBucket(address string) string {
+	if net.ParseIP(address) == nil {
+		return unknownAddress
+	}
+	return address
+}
+
 func transact(db *sql.DB, fn func(tx *sql.Tx) error) error {
 	var err error
 	var tx *sql.Tx
@@ -0,0 +1,286 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package sqlite
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/mattn/go-sqlite3"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/roomdb/sqlite/models"
+)
+
+// compiler assertion to ensure the struct fullfills the interface
+var _ roomdb.OIDCClientsService = (*OIDCClients)(nil)
+
+const (
+	oidcClientIDLength     = 16
+	oidcClientSecretLength = 32
+	oidcCodeLength         = 32
+)
+
+// OIDCClients stores the OpenID Connect clients in the oidc_clients table
+// and the authorization codes that were handed out to them in oidc_auth_codes.
+type OIDCClients struct {
+	db *sql.DB
+}
+
+// Register adds a new client and returns it, together with its secret which can't be retrieved again.
+func (oc OIDCClients) Register(ctx context.Context, name string, redirectURIs []string) (roomdb.OIDCClient, string, error) {
+	if len(redirectURIs) == 0 {
+		return roomdb.OIDCClient{}, "", fmt.Errorf("roomdb: oidc client needs at least one redirect uri")
+	}
+	for _, uri := range redirectURIs {
+		if uri == "" || strings.ContainsAny(uri, "\r\n") {
+			return roomdb.OIDCClient{}, "", fmt.Errorf("roomdb: invalid oidc redirect uri: %q", uri)
+		}
+	}
+
+	secretBytes := make([]byte, oidcClientSecretLength)
+	rand.Read(secretBytes)
+
+	var newClient = models.OidcClient{
+		HashedSecret: hashOIDCSecret(secretBytes),
+		Name:         name,
+		RedirectUris: strings.Join(redirectURIs, "\n"),
+	}
+
+	cols := boil.Whitelist(
+		models.OidcClientColumns.ClientID,
+		models.OidcClientColumns.HashedSecret,
+		models.OidcClientColumns.Name,
+		models.OidcClientColumns.RedirectUris,
+	)
+
+	idBytes := make([]byte, oidcClientIDLength)
+
+	err := transact(oc.db, func(tx *sql.Tx) error {
+		for tries := 100; tries > 0; tries-- {
+			rand.Read(idBytes)
+			newClient.ClientID = hex.EncodeToString(idBytes)
+
+			err := newClient.Insert(ctx, tx, cols)
+			if err != nil {
+				var sqlErr sqlite3.Error
+				if errors.As(err, &sqlErr) && sqlErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+					// generated an existing client id, retry
+					continue
+				}
+				return err
+			}
+
+			// reload to get the created_at timestamp
+			return newClient.Reload(ctx, tx)
+		}
+
+		return errors.New("roomdb: failed to generate an oidc client id in a reasonable amount of time")
+	})
+	if err != nil {
+		return roomdb.OIDCClient{}, "", err
+	}
+
+	return oidcClientFromModel(&newClient), base64.URLEncoding.EncodeToString(secretBytes), nil
+}
+
+// GetByClientID returns the client with that public client_id or ErrNotFound.
+func (oc OIDCClients) GetByClientID(ctx context.Context, clientID string) (roomdb.OIDCClient, error) {
+	entry, err := models.OidcClients(qm.Where("client_id = ?", clientID)).One(ctx, oc.db)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return roomdb.OIDCClient{}, roomdb.ErrNotFound
+		}
+		return roomdb.OIDCClient{}, err
+	}
+
+	return oidcClientFromModel(entry), nil
+}
+
+// CheckSecret returns the client if the secret belongs to it and ErrNotFound otherwise.
+func (oc OIDCClients) CheckSecret(ctx context.Context, clientID, secret string) (roomdb.OIDCClient, error) {
+	secretBytes, err := base64.URLEncoding.DecodeString(secret)
+	if err != nil || len(secretBytes) != oidcClientSecretLength {
+		return roomdb.OIDCClient{}, roomdb.ErrNotFound
+	}
+
+	entry, err := models.OidcClients(qm.Where("client_id = ?", clientID)).One(ctx, oc.db)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return roomdb.OIDCClient{}, roomdb.ErrNotFound
+		}
+		return roomdb.OIDCClient{}, err
+	}
+
+	hashed := hashOIDCSecret(secretBytes)
+	if subtle.ConstantTimeCompare([]byte(hashed), []byte(entry.HashedSecret)) != 1 {
+		return roomdb.OIDCClient{}, roomdb.ErrNotFound
+	}
+
+	return oidcClientFromModel(entry), nil
+}
+
+// GetByID returns the client with that ID or ErrNotFound.
+func (oc OIDCClients) GetByID(ctx context.Context, id int64) (roomdb.OIDCClient, error) {
+	entry, err := models.FindOidcClient(ctx, oc.db, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return roomdb.OIDCClient{}, roomdb.ErrNotFound
+		}
+		return roomdb.OIDCClient{}, err
+	}
+
+	return oidcClientFromModel(entry), nil
+}
+
+// List returns all the registered clients.
+func (oc OIDCClients) List(ctx context.Context) ([]roomdb.OIDCClient, error) {
+	all, err := models.OidcClients(qm.OrderBy("id ASC")).All(ctx, oc.db)
+	if err != nil {
+		return nil, err
+	}
+
+	clients := make([]roomdb.OIDCClient, len(all))
+	for i, entry := range all {
+		clients[i] = oidcClientFromModel(entry)
+	}
+
+	return clients, nil
+}
+
+// RemoveID removes the client and, through the foreign key, all its pending authorization codes.
+func (oc OIDCClients) RemoveID(ctx context.Context, id int64) error {
+	n, err := models.OidcClients(qm.Where("id = ?", id)).DeleteAll(ctx, oc.db)
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return roomdb.ErrNotFound
+	}
+
+	return nil
+}
+
+// CreateCode stores the authorization and returns the code for it, base64 URL encoded.
+func (oc OIDCClients) CreateCode(ctx context.Context, auth roomdb.OIDCAuthCode) (string, error) {
+	if !auth.ExpiresAt.After(time.Now()) {
+		return "", fmt.Errorf("roomdb: oidc code would already be expired")
+	}
+
+	var newCode = models.OidcAuthCode{
+		ClientID:      auth.ClientID,
+		MemberID:      auth.MemberID,
+		RedirectURI:   auth.RedirectURI,
+		CodeChallenge: auth.CodeChallenge,
+		Nonce:         auth.Nonce,
+		Scope:         auth.Scope,
+		ExpiresAt:     auth.ExpiresAt,
+	}
+
+	codeBytes := make([]byte, oidcCodeLength)
+
+	err := transact(oc.db, func(tx *sql.Tx) error {
+		// remove the codes that were never exchanged
+		_, err := models.OidcAuthCodes(qm.Where("expires_at < ?", time.Now())).DeleteAll(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		for tries := 100; tries > 0; tries-- {
+			rand.Read(codeBytes)
+			newCode.HashedCode = hashOIDCSecret(codeBytes)
+
+			err := newCode.Insert(ctx, tx, boil.Infer())
+			if err != nil {
+				var sqlErr sqlite3.Error
+				if errors.As(err, &sqlErr) && sqlErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+					// generated an existing code, retry
+					continue
+				}
+				return err
+			}
+			return nil
+		}
+
+		return errors.New("roomdb: failed to generate an oidc code in a reasonable amount of time")
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return base64.URLEncoding.EncodeToString(codeBytes), nil
+}
+
+// ConsumeCode returns the authorization of the code and deletes it.
+func (oc OIDCClients) ConsumeCode(ctx context.Context, code string) (roomdb.OIDCAuthCode, error) {
+	codeBytes, err := base64.URLEncoding.DecodeString(code)
+	if err != nil || len(codeBytes) != oidcCodeLength {
+		return roomdb.OIDCAuthCode{}, roomdb.ErrNotFound
+	}
+
+	var auth roomdb.OIDCAuthCode
+	err = transact(oc.db, func(tx *sql.Tx) error {
+		entry, err := models.OidcAuthCodes(qm.Where("hashed_code = ?", hashOIDCSecret(codeBytes))).One(ctx, tx)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return roomdb.ErrNotFound
+			}
+			return err
+		}
+
+		// expired codes are cleaned up by CreateCode
+		if !time.Now().Before(entry.ExpiresAt) {
+			return roomdb.ErrNotFound
+		}
+
+		if _, err := entry.Delete(ctx, tx); err != nil {
+			return err
+		}
+
+		auth = roomdb.OIDCAuthCode{
+			ClientID:      entry.ClientID,
+			MemberID:      entry.MemberID,
+			RedirectURI:   entry.RedirectURI,
+			CodeChallenge: entry.CodeChallenge,
+			Nonce:         entry.Nonce,
+			Scope:         entry.Scope,
+			ExpiresAt:     entry.ExpiresAt,
+		}
+		return nil
+	})
+	if err != nil {
+		return roomdb.OIDCAuthCode{}, err
+	}
+
+	return auth, nil
+}
+
+func oidcClientFromModel(entry *models.OidcClient) roomdb.OIDCClient {
+	return roomdb.OIDCClient{
+		ID:           entry.ID,
+		ClientID:     entry.ClientID,
+		Name:         entry.Name,
+		RedirectURIs: strings.Split(entry.RedirectUris, "\n"),
+		CreatedAt:    entry.CreatedAt,
+	}
+}
+
+func hashOIDCSecret(secret []byte) string {
+	h := sha256.New()
+	h.Write(secret)
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package sqlite
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	refs "github.com/ssbc/go-ssb-refs"
+	"github.com/ssbc/go-ssb-room/v2/internal/repo"
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOIDCClients(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	testRepo := filepath.Join("testrun", t.Name())
+	os.RemoveAll(testRepo)
+	tr := repo.New(testRepo)
+
+	db, err := Open(tr)
+	r.NoError(err, "failed to open database")
+
+	alf, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte("alf!"), 8), refs.RefAlgoFeedSSB1)
+	r.NoError(err)
+	alfID, err := db.Members.Add(ctx, alf, roomdb.RoleMember)
+	r.NoError(err)
+
+	// invalid ones
+	_, _, err = db.OIDCClients.Register(ctx, "no redirects", nil)
+	r.Error(err)
+	_, _, err = db.OIDCClients.Register(ctx, "sneaky", []string{"https://wiki.example/cb\nhttps://evil.example"})
+	r.Error(err)
+
+	wiki, secret, err := db.OIDCClients.Register(ctx, "wiki", []string{"https://wiki.example/cb", "https://wiki.example/cb2"})
+	r.NoError(err)
+	r.NotEqual("", wiki.ClientID)
+	r.NotEqual("", secret)
+	r.False(wiki.CreatedAt.IsZero())
+	r.True(wiki.HasRedirectURI("https://wiki.example/cb2"))
+	r.False(wiki.HasRedirectURI("https://wiki.example/"))
+
+	forum, _, err := db.OIDCClients.Register(ctx, "forum", []string{"https://forum.example/oidc"})
+	r.NoError(err)
+	r.NotEqual(wiki.ClientID, forum.ClientID)
+
+	got, err := db.OIDCClients.GetByClientID(ctx, wiki.ClientID)
+	r.NoError(err)
+	r.Equal(wiki.ID, got.ID)
+	r.Equal([]string{"https://wiki.example/cb", "https://wiki.example/cb2"}, got.RedirectURIs)
+
+	_, err = db.OIDCClients.GetByClientID(ctx, "nope")
+	r.True(errors.Is(err, roomdb.ErrNotFound), "unexpected error: %v", err)
+
+	// the secret only works for its client
+	got, err = db.OIDCClients.CheckSecret(ctx, wiki.ClientID, secret)
+	r.NoError(err)
+	r.Equal(wiki.ID, got.ID)
+	_, err = db.OIDCClients.CheckSecret(ctx, forum.ClientID, secret)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "unexpected error: %v", err)
+	_, err = db.OIDCClients.CheckSecret(ctx, wiki.ClientID, "wrong")
+	r.True(errors.Is(err, roomdb.ErrNotFound), "unexpected error: %v", err)
+
+	lst, err := db.OIDCClients.List(ctx)
+	r.NoError(err)
+	r.Len(lst, 2)
+	r.Equal("wiki", lst[0].Name)
+
+	// codes can only be used once
+	auth := roomdb.OIDCAuthCode{
+		ClientID:      wiki.ID,
+		MemberID:      alfID,
+		RedirectURI:   "https://wiki.example/cb",
+		CodeChallenge: "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+		Nonce:         "n-0S6_WzA2Mj",
+		Scope:         "openid",
+		ExpiresAt:     time.Now().Add(time.Minute),
+	}
+	_, err = db.OIDCClients.CreateCode(ctx, roomdb.OIDCAuthCode{ClientID: wiki.ID, MemberID: alfID, ExpiresAt: time.Now().Add(-time.Minute)})
+	r.Error(err, "already expired")
+
+	code, err := db.OIDCClients.CreateCode(ctx, auth)
+	r.NoError(err)
+
+	consumed, err := db.OIDCClients.ConsumeCode(ctx, code)
+	r.NoError(err)
+	r.Equal(auth.MemberID, consumed.MemberID)
+	r.Equal(auth.CodeChallenge, consumed.CodeChallenge)
+	r.Equal(auth.Nonce, consumed.Nonce)
+	r.Equal(auth.RedirectURI, consumed.RedirectURI)
+
+	_, err = db.OIDCClients.ConsumeCode(ctx, code)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "unexpected error: %v", err)
+
+	// expired codes don't work
+	code, err = db.OIDCClients.CreateCode(ctx, auth)
+	r.NoError(err)
+	_, err = db.db.Exec("UPDATE oidc_auth_codes SET expires_at = ?", time.Now().Add(-time.Minute))
+	r.NoError(err)
+	_, err = db.OIDCClients.ConsumeCode(ctx, code)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "unexpected error: %v", err)
+
+	// removing the client removes its codes
+	code, err = db.OIDCClients.CreateCode(ctx, auth)
+	r.NoError(err)
+	r.NoError(db.OIDCClients.RemoveID(ctx, wiki.ID))
+	_, err = db.OIDCClients.ConsumeCode(ctx, code)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "unexpected error: %v", err)
+	_, err = db.OIDCClients.GetByID(ctx, wiki.ID)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "unexpected error: %v", err)
+
+	err = db.OIDCClients.RemoveID(ctx, wiki.ID)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "unexpected error: %v", err)
+
+	r.NoError(db.Close())
+}
@@ -7,6 +7,7 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 
 	"github.com/ssbc/go-ssb-room/v2/roomdb"
@@ -111,3 +112,202 @@ func (c Config) SetDefaultLanguage(ctx context.Context, langTag string) error {
 
 	return nil // alles gut!!
 }
+
+func (c Config) GetTOTPMandatory(ctx context.Context) (bool, error) {
+	config, err := models.FindConfig(ctx, c.db, configRowID)
+	if err != nil {
+		return false, err
+	}
+
+	return config.TotpMandatory, nil
+}
+
+func (c Config) SetTOTPMandatory(ctx context.Context, mandatory bool) error {
+	err := transact(c.db, func(tx *sql.Tx) error {
+		// get the settings row
+		config, err := models.FindConfig(ctx, tx, configRowID)
+		if err != nil {
+			return err
+		}
+
+		config.TotpMandatory = mandatory
+		// issue update stmt
+		rowsAffected, err := config.Update(ctx, tx, boil.Infer())
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return fmt.Errorf("setting mandatory two-factor should have update the settings row, instead 0 rows were updated")
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return nil // alles gut!!
+}
+
+func (c Config) GetOpenInviteLimits(ctx context.Context) (roomdb.OpenInviteLimits, error) {
+	config, err := models.FindConfig(ctx, c.db, configRowID)
+	if err != nil {
+		return roomdb.OpenInviteLimits{}, err
+	}
+
+	return roomdb.OpenInviteLimits{
+		ProofOfWorkBits: uint(config.OpenInvitePowBits),
+		PerAddress:      uint(config.OpenInvitesPerAddress),
+	}, nil
+}
+
+func (c Config) SetOpenInviteLimits(ctx context.Context, limits roomdb.OpenInviteLimits) error {
+	if limits.ProofOfWorkBits > roomdb.OpenInvitesMaxProofOfWorkBits {
+		return fmt.Errorf("proof of work can't need more than %d bits", roomdb.OpenInvitesMaxProofOfWorkBits)
+	}
+
+	err := transact(c.db, func(tx *sql.Tx) error {
+		// get the settings row
+		config, err := models.FindConfig(ctx, tx, configRowID)
+		if err != nil {
+			return err
+		}
+
+		config.OpenInvitePowBits = int64(limits.ProofOfWorkBits)
+		config.OpenInvitesPerAddress = int64(limits.PerAddress)
+		// issue update stmt
+		rowsAffected, err := config.Update(ctx, tx, boil.Infer())
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return fmt.Errorf("setting the open invite limits should have update the settings row, instead 0 rows were updated")
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return nil // alles gut!!
+}
+
+func (c Config) GetMemberInviteQuota(ctx context.Context) (uint, error) {
+	config, err := models.FindConfig(ctx, c.db, configRowID)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint(config.MemberInviteQuota), nil
+}
+
+func (c Config) SetMemberInviteQuota(ctx context.Context, quota uint) error {
+	err := transact(c.db, func(tx *sql.Tx) error {
+		// get the settings row
+		config, err := models.FindConfig(ctx, tx, configRowID)
+		if err != nil {
+			return err
+		}
+
+		config.MemberInviteQuota = int64(quota)
+		// issue update stmt
+		rowsAffected, err := config.Update(ctx, tx, boil.Infer())
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return fmt.Errorf("setting the member invite quota should have update the settings row, instead 0 rows were updated")
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return nil // alles gut!!
+}
+
+func (c Config) GetAliasPolicy(ctx context.Context) (roomdb.AliasPolicy, error) {
+	config, err := models.FindConfig(ctx, c.db, configRowID)
+	if err != nil {
+		return roomdb.AliasPolicy{}, err
+	}
+
+	return aliasPolicyFromConfig(config)
+}
+
+// aliasPolicyFromConfig unpacks the JSON lists of the settings row
+func aliasPolicyFromConfig(config *models.Config) (roomdb.AliasPolicy, error) {
+	policy := roomdb.AliasPolicy{
+		MinLength:    uint(config.AliasMinLength),
+		AllowHyphens: config.AliasAllowHyphens,
+		PerMember:    uint(config.AliasPerMember),
+	}
+
+	err := json.Unmarshal([]byte(config.AliasReserved), &policy.Reserved)
+	if err != nil {
+		return roomdb.AliasPolicy{}, fmt.Errorf("invalid list of reserved aliases: %w", err)
+	}
+
+	err = json.Unmarshal([]byte(config.AliasBlocked), &policy.Blocked)
+	if err != nil {
+		return roomdb.AliasPolicy{}, fmt.Errorf("invalid list of blocked aliases: %w", err)
+	}
+
+	return policy, nil
+}
+
+func (c Config) SetAliasPolicy(ctx context.Context, policy roomdb.AliasPolicy) error {
+	// store empty lists as [] instead of null
+	if policy.Reserved == nil {
+		policy.Reserved = []string{}
+	}
+	if policy.Blocked == nil {
+		policy.Blocked = []string{}
+	}
+
+	reserved, err := json.Marshal(policy.Reserved)
+	if err != nil {
+		return err
+	}
+
+	blocked, err := json.Marshal(policy.Blocked)
+	if err != nil {
+		return err
+	}
+
+	err = transact(c.db, func(tx *sql.Tx) error {
+		// get the settings row
+		config, err := models.FindConfig(ctx, tx, configRowID)
+		if err != nil {
+			return err
+		}
+
+		config.AliasReserved = string(reserved)
+		config.AliasBlocked = string(blocked)
+		config.AliasMinLength = int64(policy.MinLength)
+		config.AliasAllowHyphens = policy.AllowHyphens
+		config.AliasPerMember = int64(policy.PerMember)
+		// issue update stmt
+		rowsAffected, err := config.Update(ctx, tx, boil.Infer())
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return fmt.Errorf("setting the alias policy should have update the settings row, instead 0 rows were updated")
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return nil // alles gut!!
+}
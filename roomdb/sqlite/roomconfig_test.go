@@ -46,3 +46,130 @@ func TestRoomConfig(t *testing.T) {
 	err = db.Config.SetPrivacyMode(ctx, 1337)
 	r.Error(err)
 }
+
+func TestRoomConfigTOTPMandatory(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	testRepo := filepath.Join("testrun", t.Name())
+	os.RemoveAll(testRepo)
+
+	tr := repo.New(testRepo)
+
+	db, err := Open(tr)
+	r.NoError(err)
+
+	// not required by default
+	mandatory, err := db.Config.GetTOTPMandatory(ctx)
+	r.NoError(err)
+	r.False(mandatory)
+
+	err = db.Config.SetTOTPMandatory(ctx, true)
+	r.NoError(err)
+
+	mandatory, err = db.Config.GetTOTPMandatory(ctx)
+	r.NoError(err)
+	r.True(mandatory)
+
+	// the other settings are left alone
+	pm, err := db.Config.GetPrivacyMode(ctx)
+	r.NoError(err)
+	r.Equal(roomdb.ModeCommunity, pm)
+}
+
+func TestRoomConfigOpenInviteLimits(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	testRepo := filepath.Join("testrun", t.Name())
+	os.RemoveAll(testRepo)
+
+	tr := repo.New(testRepo)
+
+	db, err := Open(tr)
+	r.NoError(err)
+
+	limits, err := db.Config.GetOpenInviteLimits(ctx)
+	r.NoError(err)
+	r.Equal(roomdb.OpenInviteLimits{ProofOfWorkBits: 0, PerAddress: 5}, limits)
+
+	err = db.Config.SetOpenInviteLimits(ctx, roomdb.OpenInviteLimits{ProofOfWorkBits: 16, PerAddress: 2})
+	r.NoError(err)
+
+	limits, err = db.Config.GetOpenInviteLimits(ctx)
+	r.NoError(err)
+	r.Equal(roomdb.OpenInviteLimits{ProofOfWorkBits: 16, PerAddress: 2}, limits)
+
+	err = db.Config.SetOpenInviteLimits(ctx, roomdb.OpenInviteLimits{ProofOfWorkBits: roomdb.OpenInvitesMaxProofOfWorkBits + 1})
+	r.Error(err)
+}
+
+func TestRoomConfigMemberInviteQuota(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	testRepo := filepath.Join("testrun", t.Name())
+	os.RemoveAll(testRepo)
+
+	tr := repo.New(testRepo)
+
+	db, err := Open(tr)
+	r.NoError(err)
+
+	quota, err := db.Config.GetMemberInviteQuota(ctx)
+	r.NoError(err)
+	r.EqualValues(0, quota)
+
+	err = db.Config.SetMemberInviteQuota(ctx, 3)
+	r.NoError(err)
+
+	quota, err = db.Config.GetMemberInviteQuota(ctx)
+	r.NoError(err)
+	r.EqualValues(3, quota)
+}
+
+func TestRoomConfigAliasPolicy(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	testRepo := filepath.Join("testrun", t.Name())
+	os.RemoveAll(testRepo)
+
+	tr := repo.New(testRepo)
+
+	db, err := Open(tr)
+	r.NoError(err)
+
+	// some names are reserved from the start
+	policy, err := db.Config.GetAliasPolicy(ctx)
+	r.NoError(err)
+	r.Contains(policy.Reserved, "admin")
+	r.Contains(policy.Reserved, "www")
+	r.Len(policy.Blocked, 0)
+	r.EqualValues(0, policy.MinLength)
+	r.False(policy.AllowHyphens)
+	r.EqualValues(0, policy.PerMember)
+
+	want := roomdb.AliasPolicy{
+		Reserved:     []string{"support"},
+		Blocked:      []string{"badword", "/^x+$/"},
+		MinLength:    3,
+		AllowHyphens: true,
+		PerMember:    2,
+	}
+	err = db.Config.SetAliasPolicy(ctx, want)
+	r.NoError(err)
+
+	policy, err = db.Config.GetAliasPolicy(ctx)
+	r.NoError(err)
+	r.Equal(want, policy)
+
+	// empty lists stay empty
+	err = db.Config.SetAliasPolicy(ctx, roomdb.AliasPolicy{})
+	r.NoError(err)
+
+	policy, err = db.Config.GetAliasPolicy(ctx)
+	r.NoError(err)
+	r.Len(policy.Reserved, 0)
+	r.Len(policy.Blocked, 0)
+}
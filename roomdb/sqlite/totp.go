@@ -0,0 +1,284 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package sqlite
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base32"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/roomdb/sqlite/models"
+)
+
+// compiler assertion to ensure the struct fullfills the interface
+var _ roomdb.TOTPService = (*TOTP)(nil)
+
+// how many recovery codes are handed out when the second factor is confirmed
+const totpRecoveryCodeCount = 10
+
+// the settings most authenticator apps expect
+var totpOpts = totp.ValidateOpts{
+	Period:    30,
+	Digits:    otp.DigitsSix,
+	Algorithm: otp.AlgorithmSHA1,
+}
+
+// TOTP stores the second factor of the members in the totp_secrets and totp_recovery_codes tables
+type TOTP struct {
+	db *sql.DB
+}
+
+// Enroll stores a new, unconfirmed secret for the member, replacing a previous unconfirmed one.
+func (t TOTP) Enroll(ctx context.Context, memberID int64, secret string) error {
+	return transact(t.db, func(tx *sql.Tx) error {
+		// check the member is registerd
+		if _, err := models.FindMember(ctx, tx, memberID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return roomdb.ErrNotFound
+			}
+			return err
+		}
+
+		entry, err := models.TotpSecrets(qm.Where("member_id = ?", memberID)).One(ctx, tx)
+		if err != nil {
+			if !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+
+			entry = &models.TotpSecret{
+				MemberID: memberID,
+				Secret:   secret,
+			}
+			return entry.Insert(ctx, tx, boil.Whitelist(
+				models.TotpSecretColumns.MemberID,
+				models.TotpSecretColumns.Secret,
+			))
+		}
+
+		if entry.Confirmed {
+			return fmt.Errorf("totp: the member already enabled two-factor authentication")
+		}
+
+		entry.Secret = secret
+		_, err = entry.Update(ctx, tx, boil.Whitelist(models.TotpSecretColumns.Secret))
+		return err
+	})
+}
+
+// Confirm checks the code against the unconfirmed secret of the member, enables it and returns new recovery codes.
+func (t TOTP) Confirm(ctx context.Context, memberID int64, code string) ([]string, error) {
+	var recoveryCodes []string
+
+	err := transact(t.db, func(tx *sql.Tx) error {
+		entry, err := models.TotpSecrets(qm.Where("member_id = ? AND confirmed = false", memberID)).One(ctx, tx)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return roomdb.ErrNotFound
+			}
+			return err
+		}
+
+		step, err := matchTOTPStep(entry.Secret, normalizeTOTPCode(code), time.Now())
+		if err != nil {
+			return err
+		}
+
+		entry.Confirmed = true
+		entry.LastStep = step
+		_, err = entry.Update(ctx, tx, boil.Whitelist(
+			models.TotpSecretColumns.Confirmed,
+			models.TotpSecretColumns.LastStep,
+		))
+		if err != nil {
+			return err
+		}
+
+		// drop left-overs from an earlier enrollment
+		_, err = models.TotpRecoveryCodes(qm.Where("member_id = ?", memberID)).DeleteAll(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		recoveryCodes = make([]string, totpRecoveryCodeCount)
+		for i := range recoveryCodes {
+			recoveryCodes[i], err = newRecoveryCode()
+			if err != nil {
+				return err
+			}
+
+			codeHash := hashRecoveryCode(recoveryCodes[i])
+			newCode := models.TotpRecoveryCode{
+				MemberID: memberID,
+				CodeHash: codeHash[:],
+			}
+			err = newCode.Insert(ctx, tx, boil.Whitelist(
+				models.TotpRecoveryCodeColumns.MemberID,
+				models.TotpRecoveryCodeColumns.CodeHash,
+			))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+// Verify checks a code from the authenticator app or one of the recovery codes of the member.
+func (t TOTP) Verify(ctx context.Context, memberID int64, code string) error {
+	code = normalizeTOTPCode(code)
+
+	return transact(t.db, func(tx *sql.Tx) error {
+		entry, err := models.TotpSecrets(qm.Where("member_id = ? AND confirmed = true", memberID)).One(ctx, tx)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return roomdb.ErrNotFound
+			}
+			return err
+		}
+
+		if !isTOTPCode(code) {
+			// not six digits, try it as a recovery code and use it up
+			codeHash := hashRecoveryCode(code)
+			n, err := models.TotpRecoveryCodes(
+				qm.Where("member_id = ? AND code_hash = ?", memberID, codeHash[:]),
+			).DeleteAll(ctx, tx)
+			if err != nil {
+				return err
+			}
+			if n == 0 {
+				return roomdb.ErrInvalidTOTPCode
+			}
+			return nil
+		}
+
+		step, err := matchTOTPStep(entry.Secret, code, time.Now())
+		if err != nil {
+			return err
+		}
+
+		// a code can only be used once, so someone who watched it being typed in can't use it again
+		if step <= entry.LastStep {
+			return roomdb.ErrInvalidTOTPCode
+		}
+
+		entry.LastStep = step
+		_, err = entry.Update(ctx, tx, boil.Whitelist(models.TotpSecretColumns.LastStep))
+		return err
+	})
+}
+
+// Status returns if the member enabled the second factor and how many recovery codes are left
+func (t TOTP) Status(ctx context.Context, memberID int64) (roomdb.TOTPStatus, error) {
+	var status roomdb.TOTPStatus
+
+	enabled, err := models.TotpSecrets(qm.Where("member_id = ? AND confirmed = true", memberID)).Exists(ctx, t.db)
+	if err != nil {
+		return status, err
+	}
+	status.Enabled = enabled
+
+	if !enabled {
+		return status, nil
+	}
+
+	left, err := models.TotpRecoveryCodes(qm.Where("member_id = ?", memberID)).Count(ctx, t.db)
+	if err != nil {
+		return status, err
+	}
+	status.RecoveryCodesLeft = int(left)
+
+	return status, nil
+}
+
+// Disable removes the secret and the recovery codes of the member
+func (t TOTP) Disable(ctx context.Context, memberID int64) error {
+	return transact(t.db, func(tx *sql.Tx) error {
+		_, err := models.TotpSecrets(qm.Where("member_id = ?", memberID)).DeleteAll(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		_, err = models.TotpRecoveryCodes(qm.Where("member_id = ?", memberID)).DeleteAll(ctx, tx)
+		return err
+	})
+}
+
+// matchTOTPStep returns the time step the code belongs to.
+// The steps before and after the current one are accepted as well, to account for clock drift.
+func matchTOTPStep(secret, code string, now time.Time) (int64, error) {
+	current := now.Unix() / int64(totpOpts.Period)
+
+	for _, step := range []int64{current, current - 1, current + 1} {
+		expected, err := totp.GenerateCodeCustom(secret, time.Unix(step*int64(totpOpts.Period), 0), totpOpts)
+		if err != nil {
+			return 0, err
+		}
+
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, nil
+		}
+	}
+
+	return 0, roomdb.ErrInvalidTOTPCode
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != totpOpts.Digits.Length() {
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeTOTPCode removes the whitespace and dashes people might type along with a code
+func normalizeTOTPCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' || r == '\t' {
+			return -1
+		}
+		return r
+	}, code)
+}
+
+var recoveryCodeEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// newRecoveryCode returns a random code like "abcde-fgh23"
+func newRecoveryCode() (string, error) {
+	buf := make([]byte, 7)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	code := recoveryCodeEncoding.EncodeToString(buf)[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+func hashRecoveryCode(code string) [sha256.Size]byte {
+	return sha256.Sum256([]byte(normalizeTOTPCode(code)))
+}
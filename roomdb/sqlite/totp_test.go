@@ -0,0 +1,143 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package sqlite
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+	refs "github.com/ssbc/go-ssb-refs"
+	"github.com/ssbc/go-ssb-room/v2/internal/repo"
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTOTP(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	testRepo := filepath.Join("testrun", t.Name())
+	os.RemoveAll(testRepo)
+	tr := repo.New(testRepo)
+
+	db, err := Open(tr)
+	r.NoError(err, "failed to open database")
+
+	alf, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte("alf!"), 8), refs.RefAlgoFeedSSB1)
+	r.NoError(err)
+
+	alfID, err := db.Members.Add(ctx, alf, roomdb.RoleAdmin)
+	r.NoError(err)
+
+	// unknown members can't enroll
+	err = db.TOTP.Enroll(ctx, 666, "JBSWY3DPEHPK3PXP")
+	r.True(errors.Is(err, roomdb.ErrNotFound), "unexpected error: %v", err)
+
+	status, err := db.TOTP.Status(ctx, alfID)
+	r.NoError(err)
+	r.False(status.Enabled)
+
+	// enroll twice, only the second secret counts
+	err = db.TOTP.Enroll(ctx, alfID, "JBSWY3DPEHPK3PXP")
+	r.NoError(err)
+
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: "test.room", AccountName: alf.String()})
+	r.NoError(err)
+	secret := key.Secret()
+
+	err = db.TOTP.Enroll(ctx, alfID, secret)
+	r.NoError(err)
+
+	// not enabled until it is confirmed
+	status, err = db.TOTP.Status(ctx, alfID)
+	r.NoError(err)
+	r.False(status.Enabled)
+
+	now := time.Now()
+	code, err := totp.GenerateCode(secret, now)
+	r.NoError(err)
+
+	err = db.TOTP.Verify(ctx, alfID, code)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "unconfirmed secret should not be usable: %v", err)
+
+	_, err = db.TOTP.Confirm(ctx, alfID, "000000x")
+	r.Error(err)
+
+	recoveryCodes, err := db.TOTP.Confirm(ctx, alfID, code)
+	r.NoError(err)
+	r.Len(recoveryCodes, 10)
+
+	status, err = db.TOTP.Status(ctx, alfID)
+	r.NoError(err)
+	r.True(status.Enabled)
+	r.Equal(10, status.RecoveryCodesLeft)
+
+	// can't enroll again while enabled
+	err = db.TOTP.Enroll(ctx, alfID, "JBSWY3DPEHPK3PXP")
+	r.Error(err)
+
+	// the code used for confirmation can't be replayed
+	err = db.TOTP.Verify(ctx, alfID, code)
+	r.True(errors.Is(err, roomdb.ErrInvalidTOTPCode), "replayed code: %v", err)
+
+	// the next one works, but only once
+	nextCode, err := totp.GenerateCode(secret, now.Add(30*time.Second))
+	r.NoError(err)
+	err = db.TOTP.Verify(ctx, alfID, nextCode)
+	r.NoError(err)
+	err = db.TOTP.Verify(ctx, alfID, nextCode)
+	r.True(errors.Is(err, roomdb.ErrInvalidTOTPCode), "replayed code: %v", err)
+
+	// codes from the past are not accepted
+	oldCode, err := totp.GenerateCode(secret, now.Add(-5*time.Minute))
+	r.NoError(err)
+	if oldCode != nextCode {
+		err = db.TOTP.Verify(ctx, alfID, oldCode)
+		r.True(errors.Is(err, roomdb.ErrInvalidTOTPCode), "old code: %v", err)
+	}
+
+	// recovery codes can be used once, typos in the format don't matter
+	err = db.TOTP.Verify(ctx, alfID, " "+recoveryCodes[3]+" ")
+	r.NoError(err)
+	err = db.TOTP.Verify(ctx, alfID, recoveryCodes[3])
+	r.True(errors.Is(err, roomdb.ErrInvalidTOTPCode), "used recovery code: %v", err)
+
+	err = db.TOTP.Verify(ctx, alfID, "aaaaa-bbbbb")
+	r.True(errors.Is(err, roomdb.ErrInvalidTOTPCode), "wrong recovery code: %v", err)
+
+	status, err = db.TOTP.Status(ctx, alfID)
+	r.NoError(err)
+	r.Equal(9, status.RecoveryCodesLeft)
+
+	// disable removes everything
+	err = db.TOTP.Disable(ctx, alfID)
+	r.NoError(err)
+
+	status, err = db.TOTP.Status(ctx, alfID)
+	r.NoError(err)
+	r.False(status.Enabled)
+	r.Equal(0, status.RecoveryCodesLeft)
+
+	err = db.TOTP.Verify(ctx, alfID, recoveryCodes[4])
+	r.True(errors.Is(err, roomdb.ErrNotFound), "disabled: %v", err)
+
+	// and the second factor is gone with the member
+	err = db.TOTP.Enroll(ctx, alfID, secret)
+	r.NoError(err)
+	err = db.Members.RemoveID(ctx, alfID)
+	r.NoError(err)
+
+	status, err = db.TOTP.Status(ctx, alfID)
+	r.NoError(err)
+	r.False(status.Enabled)
+
+	r.NoError(db.Close())
+}
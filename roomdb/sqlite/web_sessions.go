@@ -0,0 +1,213 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package sqlite
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/mattn/go-sqlite3"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/roomdb/sqlite/models"
+)
+
+// compiler assertion to ensure the struct fullfills the interface
+var _ roomdb.WebSessionsService = (*WebSessions)(nil)
+
+const webSessionIDLength = 32
+
+// WebSessions stores the sessions of the web dashboard in the web_sessions table.
+// Like with api tokens, only the sha256 of the session id is stored.
+type WebSessions struct {
+	db *sql.DB
+}
+
+// Create stores a new session and returns its id, base64 URL encoded.
+func (ws WebSessions) Create(ctx context.Context, memberID int64, data []byte, expiresAt, idleExpiresAt time.Time) (string, error) {
+	var newSession = models.WebSession{
+		MemberID:      memberID,
+		Data:          data,
+		ExpiresAt:     expiresAt,
+		IdleExpiresAt: idleExpiresAt,
+	}
+
+	idBytes := make([]byte, webSessionIDLength)
+
+	err := transact(ws.db, func(tx *sql.Tx) error {
+		cols := boil.Whitelist(
+			models.WebSessionColumns.HashedID,
+			models.WebSessionColumns.MemberID,
+			models.WebSessionColumns.Data,
+			models.WebSessionColumns.ExpiresAt,
+			models.WebSessionColumns.IdleExpiresAt,
+		)
+
+		for tries := 100; tries > 0; tries-- {
+			rand.Read(idBytes)
+
+			// hash the binary of the id for storage
+			h := sha256.New()
+			h.Write(idBytes)
+			newSession.HashedID = fmt.Sprintf("%x", h.Sum(nil))
+
+			err := newSession.Insert(ctx, tx, cols)
+			if err != nil {
+				var sqlErr sqlite3.Error
+				if errors.As(err, &sqlErr) && sqlErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+					// generated an existing id, retry
+					continue
+				}
+				return err
+			}
+			return nil
+		}
+
+		return errors.New("roomdb: failed to generate a session id in a reasonable amount of time")
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return base64.URLEncoding.EncodeToString(idBytes), nil
+}
+
+// Get returns the session with that id, if neither of its expiry times passed.
+func (ws WebSessions) Get(ctx context.Context, id string) (roomdb.WebSession, error) {
+	entry, err := ws.find(ctx, ws.db, id)
+	if err != nil {
+		return roomdb.WebSession{}, err
+	}
+
+	return roomdb.WebSession{
+		ID:       entry.ID,
+		MemberID: entry.MemberID,
+		Data:     entry.Data,
+
+		CreatedAt:  entry.CreatedAt,
+		LastUsedAt: entry.LastUsedAt,
+
+		ExpiresAt:     entry.ExpiresAt,
+		IdleExpiresAt: entry.IdleExpiresAt,
+	}, nil
+}
+
+// Update replaces the member and the data of the session and marks it as used.
+func (ws WebSessions) Update(ctx context.Context, id string, memberID int64, data []byte, idleExpiresAt time.Time) error {
+	return transact(ws.db, func(tx *sql.Tx) error {
+		entry, err := ws.find(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		entry.MemberID = memberID
+		entry.Data = data
+		entry.LastUsedAt = time.Now()
+		entry.IdleExpiresAt = idleExpiresAt
+
+		cols := boil.Whitelist(
+			models.WebSessionColumns.MemberID,
+			models.WebSessionColumns.Data,
+			models.WebSessionColumns.LastUsedAt,
+			models.WebSessionColumns.IdleExpiresAt,
+		)
+		_, err = entry.Update(ctx, tx, cols)
+		return err
+	})
+}
+
+// Touch marks the session as used and moves the time it expires without being used.
+func (ws WebSessions) Touch(ctx context.Context, id string, idleExpiresAt time.Time) error {
+	return transact(ws.db, func(tx *sql.Tx) error {
+		entry, err := ws.find(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		entry.LastUsedAt = time.Now()
+		entry.IdleExpiresAt = idleExpiresAt
+
+		cols := boil.Whitelist(
+			models.WebSessionColumns.LastUsedAt,
+			models.WebSessionColumns.IdleExpiresAt,
+		)
+		_, err = entry.Update(ctx, tx, cols)
+		return err
+	})
+}
+
+// Remove deletes a single session. It's not an error if the session doesn't exist (anymore).
+func (ws WebSessions) Remove(ctx context.Context, id string) error {
+	hashedID, err := getHashedWebSessionID(id)
+	if err != nil {
+		return nil
+	}
+
+	_, err = models.WebSessions(qm.Where("hashed_id = ?", hashedID)).DeleteAll(ctx, ws.db)
+	return err
+}
+
+// RemoveForMember deletes all the sessions of a member
+func (ws WebSessions) RemoveForMember(ctx context.Context, memberID int64) error {
+	_, err := models.WebSessions(qm.Where("member_id = ?", memberID)).DeleteAll(ctx, ws.db)
+	return err
+}
+
+// find returns the session of the id, or ErrNotFound if it doesn't exist or expired
+func (ws WebSessions) find(ctx context.Context, exec boil.ContextExecutor, id string) (*models.WebSession, error) {
+	hashedID, err := getHashedWebSessionID(id)
+	if err != nil {
+		return nil, roomdb.ErrNotFound
+	}
+
+	entry, err := models.WebSessions(qm.Where("hashed_id = ?", hashedID)).One(ctx, exec)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, roomdb.ErrNotFound
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if now.After(entry.ExpiresAt) || now.After(entry.IdleExpiresAt) {
+		return nil, roomdb.ErrNotFound
+	}
+
+	return entry, nil
+}
+
+// deleteExpiredWebSessions is called by the scrubber of Open, so that abandoned sessions don't pile up
+func deleteExpiredWebSessions(tx boil.ContextExecutor) error {
+	now := time.Now()
+	_, err := models.WebSessions(qm.Where("expires_at < ? OR idle_expires_at < ?", now, now)).DeleteAll(context.Background(), tx)
+	if err != nil {
+		return fmt.Errorf("roomdb: failed to delete expired web sessions: %w", err)
+	}
+	return nil
+}
+
+func getHashedWebSessionID(b64id string) (string, error) {
+	idBytes, err := base64.URLEncoding.DecodeString(b64id)
+	if err != nil {
+		return "", err
+	}
+
+	if n := len(idBytes); n != webSessionIDLength {
+		return "", fmt.Errorf("roomdb: invalid session id length (only got %d bytes)", n)
+	}
+
+	// hash the binary of the passed id
+	h := sha256.New()
+	h.Write(idBytes)
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package sqlite
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	refs "github.com/ssbc/go-ssb-refs"
+	"github.com/ssbc/go-ssb-room/v2/internal/repo"
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebSessions(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	testRepo := filepath.Join("testrun", t.Name())
+	os.RemoveAll(testRepo)
+	tr := repo.New(testRepo)
+
+	db, err := Open(tr)
+	r.NoError(err, "failed to open database")
+
+	alf, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte("alf!"), 8), refs.RefAlgoFeedSSB1)
+	r.NoError(err)
+	alfID, err := db.Members.Add(ctx, alf, roomdb.RoleMember)
+	r.NoError(err)
+
+	tomorrow := time.Now().Add(24 * time.Hour)
+	inAnHour := time.Now().Add(time.Hour)
+
+	// sessions can start before somebody signed in
+	anon, err := db.WebSessions.Create(ctx, 0, []byte("return to /foo"), tomorrow, inAnHour)
+	r.NoError(err)
+
+	// the id itself is not stored
+	var count int
+	r.NoError(db.db.QueryRow("SELECT count(*) FROM web_sessions WHERE hashed_id = ?", anon).Scan(&count))
+	r.Equal(0, count)
+
+	s, err := db.WebSessions.Get(ctx, anon)
+	r.NoError(err)
+	r.EqualValues(0, s.MemberID)
+	r.Equal([]byte("return to /foo"), s.Data)
+
+	r.NoError(db.WebSessions.Update(ctx, anon, alfID, []byte("signed in"), inAnHour))
+	s, err = db.WebSessions.Get(ctx, anon)
+	r.NoError(err)
+	r.Equal(alfID, s.MemberID)
+	r.Equal([]byte("signed in"), s.Data)
+
+	_, err = db.WebSessions.Get(ctx, "not-a-session")
+	r.True(errors.Is(err, roomdb.ErrNotFound), "unexpected error: %v", err)
+
+	// sessions that weren't used in time end
+	other, err := db.WebSessions.Create(ctx, alfID, []byte("other browser"), tomorrow, time.Now().Add(-time.Minute))
+	r.NoError(err)
+	_, err = db.WebSessions.Get(ctx, other)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "unexpected error: %v", err)
+	err = db.WebSessions.Touch(ctx, other, inAnHour)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "expired sessions can't be revived: %v", err)
+
+	// and so do those that are too old, even if they are used
+	old, err := db.WebSessions.Create(ctx, alfID, []byte("old browser"), time.Now().Add(-time.Minute), inAnHour)
+	r.NoError(err)
+	_, err = db.WebSessions.Get(ctx, old)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "unexpected error: %v", err)
+
+	// the scrubber removes both
+	r.NoError(deleteExpiredWebSessions(db.db))
+	r.NoError(db.db.QueryRow("SELECT count(*) FROM web_sessions").Scan(&count))
+	r.Equal(1, count)
+
+	// log out everywhere
+	third, err := db.WebSessions.Create(ctx, alfID, []byte("third browser"), tomorrow, inAnHour)
+	r.NoError(err)
+	r.NoError(db.WebSessions.Touch(ctx, third, tomorrow))
+
+	r.NoError(db.WebSessions.RemoveForMember(ctx, alfID))
+	_, err = db.WebSessions.Get(ctx, anon)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "unexpected error: %v", err)
+	_, err = db.WebSessions.Get(ctx, third)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "unexpected error: %v", err)
+
+	// removing a single one, and the member
+	fourth, err := db.WebSessions.Create(ctx, alfID, []byte("fourth browser"), tomorrow, inAnHour)
+	r.NoError(err)
+	fifth, err := db.WebSessions.Create(ctx, alfID, []byte("fifth browser"), tomorrow, inAnHour)
+	r.NoError(err)
+
+	r.NoError(db.WebSessions.Remove(ctx, fourth))
+	_, err = db.WebSessions.Get(ctx, fourth)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "unexpected error: %v", err)
+	_, err = db.WebSessions.Get(ctx, fifth)
+	r.NoError(err)
+
+	r.NoError(db.Members.RemoveID(ctx, alfID))
+	_, err = db.WebSessions.Get(ctx, fifth)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "unexpected error: %v", err)
+
+	r.NoError(db.Close())
+}
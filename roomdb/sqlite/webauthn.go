@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/friendsofgo/errors"
+	"github.com/mattn/go-sqlite3"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/roomdb/sqlite/models"
+)
+
+// compiler assertion to ensure the struct fullfills the interface
+var _ roomdb.WebAuthnService = (*WebAuthn)(nil)
+
+// WebAuthn stores the passkeys of the members in the webauthn_credentials table
+type WebAuthn struct {
+	db *sql.DB
+}
+
+// AddCredential stores a new credential for the member and returns its id.
+func (wa WebAuthn) AddCredential(ctx context.Context, memberID int64, cred roomdb.WebAuthnCredential) (int64, error) {
+	var entry = models.WebauthnCredential{
+		MemberID:        memberID,
+		Name:            cred.Name,
+		CredentialID:    cred.CredentialID,
+		PublicKey:       cred.PublicKey,
+		AttestationType: cred.AttestationType,
+		SignCount:       int64(cred.SignCount),
+	}
+
+	err := transact(wa.db, func(tx *sql.Tx) error {
+		// check the member is registerd
+		if _, err := models.FindMember(ctx, tx, memberID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return roomdb.ErrNotFound
+			}
+			return err
+		}
+
+		cols := boil.Whitelist(
+			models.WebauthnCredentialColumns.MemberID,
+			models.WebauthnCredentialColumns.Name,
+			models.WebauthnCredentialColumns.CredentialID,
+			models.WebauthnCredentialColumns.PublicKey,
+			models.WebauthnCredentialColumns.AttestationType,
+			models.WebauthnCredentialColumns.SignCount,
+		)
+		return entry.Insert(ctx, tx, cols)
+	})
+	if err != nil {
+		var sqlErr sqlite3.Error
+		if errors.As(err, &sqlErr) && sqlErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return -1, fmt.Errorf("webauthn: credential is already registered")
+		}
+		return -1, err
+	}
+
+	return entry.ID, nil
+}
+
+// GetCredential returns a single credential of a member.
+func (wa WebAuthn) GetCredential(ctx context.Context, memberID, id int64) (roomdb.WebAuthnCredential, error) {
+	entry, err := models.WebauthnCredentials(
+		qm.Where("id = ? AND member_id = ?", id, memberID),
+	).One(ctx, wa.db)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return roomdb.WebAuthnCredential{}, roomdb.ErrNotFound
+		}
+		return roomdb.WebAuthnCredential{}, err
+	}
+
+	return credentialFromModel(entry), nil
+}
+
+// ListCredentials returns all the credentials of a member, in the order they were added
+func (wa WebAuthn) ListCredentials(ctx context.Context, memberID int64) ([]roomdb.WebAuthnCredential, error) {
+	all, err := models.WebauthnCredentials(
+		qm.Where("member_id = ?", memberID),
+		qm.OrderBy("id ASC"),
+	).All(ctx, wa.db)
+	if err != nil {
+		return nil, err
+	}
+
+	creds := make([]roomdb.WebAuthnCredential, len(all))
+	for i, entry := range all {
+		creds[i] = credentialFromModel(entry)
+	}
+
+	return creds, nil
+}
+
+// MarkUsed stores the new signature counter of the credential after a successful sign-in and updates its last use
+func (wa WebAuthn) MarkUsed(ctx context.Context, id int64, signCount uint32) error {
+	return transact(wa.db, func(tx *sql.Tx) error {
+		entry, err := models.FindWebauthnCredential(ctx, tx, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return roomdb.ErrNotFound
+			}
+			return err
+		}
+
+		entry.SignCount = int64(signCount)
+		entry.LastUsedAt = time.Now()
+
+		_, err = entry.Update(ctx, tx, boil.Whitelist(
+			models.WebauthnCredentialColumns.SignCount,
+			models.WebauthnCredentialColumns.LastUsedAt,
+		))
+		return err
+	})
+}
+
+// RemoveCredential deletes a single credential of a member.
+func (wa WebAuthn) RemoveCredential(ctx context.Context, memberID, id int64) error {
+	return transact(wa.db, func(tx *sql.Tx) error {
+		entry, err := models.WebauthnCredentials(
+			qm.Where("id = ? AND member_id = ?", id, memberID),
+		).One(ctx, tx)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return roomdb.ErrNotFound
+			}
+			return err
+		}
+
+		_, err = entry.Delete(ctx, tx)
+		return err
+	})
+}
+
+func credentialFromModel(entry *models.WebauthnCredential) roomdb.WebAuthnCredential {
+	return roomdb.WebAuthnCredential{
+		ID:              entry.ID,
+		MemberID:        entry.MemberID,
+		Name:            entry.Name,
+		CredentialID:    entry.CredentialID,
+		PublicKey:       entry.PublicKey,
+		AttestationType: entry.AttestationType,
+		SignCount:       uint32(entry.SignCount),
+		CreatedAt:       entry.CreatedAt,
+		LastUsedAt:      entry.LastUsedAt,
+	}
+}
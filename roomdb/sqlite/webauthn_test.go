@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package sqlite
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	refs "github.com/ssbc/go-ssb-refs"
+	"github.com/ssbc/go-ssb-room/v2/internal/repo"
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebAuthnCredentials(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	testRepo := filepath.Join("testrun", t.Name())
+	os.RemoveAll(testRepo)
+	tr := repo.New(testRepo)
+
+	db, err := Open(tr)
+	r.NoError(err, "failed to open database")
+
+	alf, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte("alf!"), 8), refs.RefAlgoFeedSSB1)
+	r.NoError(err)
+	bre, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte("bre!"), 8), refs.RefAlgoFeedSSB1)
+	r.NoError(err)
+
+	alfID, err := db.Members.Add(ctx, alf, roomdb.RoleMember)
+	r.NoError(err)
+	breID, err := db.Members.Add(ctx, bre, roomdb.RoleMember)
+	r.NoError(err)
+
+	creds, err := db.WebAuthn.ListCredentials(ctx, alfID)
+	r.NoError(err)
+	r.Len(creds, 0)
+
+	yubikey := roomdb.WebAuthnCredential{
+		Name:            "yubikey",
+		CredentialID:    []byte("credential-1"),
+		PublicKey:       []byte("public-key-1"),
+		AttestationType: "none",
+		SignCount:       1,
+	}
+	yubikeyID, err := db.WebAuthn.AddCredential(ctx, alfID, yubikey)
+	r.NoError(err)
+
+	phone := roomdb.WebAuthnCredential{
+		Name:         "phone",
+		CredentialID: []byte("credential-2"),
+		PublicKey:    []byte("public-key-2"),
+	}
+	phoneID, err := db.WebAuthn.AddCredential(ctx, alfID, phone)
+	r.NoError(err)
+
+	// can't register the same credential twice
+	_, err = db.WebAuthn.AddCredential(ctx, breID, yubikey)
+	r.Error(err)
+
+	// unknown member
+	_, err = db.WebAuthn.AddCredential(ctx, 666, roomdb.WebAuthnCredential{CredentialID: []byte("credential-3"), PublicKey: []byte("k")})
+	r.True(errors.Is(err, roomdb.ErrNotFound), "wrong error: %v", err)
+
+	creds, err = db.WebAuthn.ListCredentials(ctx, alfID)
+	r.NoError(err)
+	r.Len(creds, 2)
+	r.Equal(yubikeyID, creds[0].ID)
+	r.Equal("yubikey", creds[0].Name)
+	r.Equal([]byte("credential-1"), creds[0].CredentialID)
+	r.Equal([]byte("public-key-1"), creds[0].PublicKey)
+	r.Equal("none", creds[0].AttestationType)
+	r.EqualValues(1, creds[0].SignCount)
+	r.Equal(phoneID, creds[1].ID)
+
+	creds, err = db.WebAuthn.ListCredentials(ctx, breID)
+	r.NoError(err)
+	r.Len(creds, 0)
+
+	// update the counter
+	err = db.WebAuthn.MarkUsed(ctx, yubikeyID, 42)
+	r.NoError(err)
+
+	cred, err := db.WebAuthn.GetCredential(ctx, alfID, yubikeyID)
+	r.NoError(err)
+	r.EqualValues(42, cred.SignCount)
+
+	// bre can't see or remove alf's credentials
+	_, err = db.WebAuthn.GetCredential(ctx, breID, yubikeyID)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "wrong error: %v", err)
+
+	err = db.WebAuthn.RemoveCredential(ctx, breID, yubikeyID)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "wrong error: %v", err)
+
+	err = db.WebAuthn.RemoveCredential(ctx, alfID, yubikeyID)
+	r.NoError(err)
+
+	creds, err = db.WebAuthn.ListCredentials(ctx, alfID)
+	r.NoError(err)
+	r.Len(creds, 1)
+	r.Equal(phoneID, creds[0].ID)
+
+	// credentials are removed with their member
+	err = db.Members.RemoveID(ctx, alfID)
+	r.NoError(err)
+
+	_, err = db.WebAuthn.GetCredential(ctx, alfID, phoneID)
+	r.True(errors.Is(err, roomdb.ErrNotFound), "wrong error: %v", err)
+
+	r.NoError(db.Close())
+}
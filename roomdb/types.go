@@ -17,6 +17,28 @@ import (
 // ErrNotFound is returned by the admin db if an object couldn't be found.
 var ErrNotFound = errors.New("roomdb: object not found")
 
+// ErrInvalidTOTPCode is returned by the TOTPService if a two-factor code or recovery code doesn't match.
+var ErrInvalidTOTPCode = errors.New("roomdb: invalid two-factor code")
+
+// ErrInviteForOtherFeed is returned when somebody tries to use an invite that was created for another feed.
+var ErrInviteForOtherFeed = errors.New("roomdb: the invite is for another feed")
+
+// ErrInviteRequestLimit is returned if too many invite requests were made recently, from the same address or in total.
+var ErrInviteRequestLimit = errors.New("roomdb: too many invite requests")
+
+// ErrOpenInviteLimit is returned if an address created too many invites in open mode recently.
+var ErrOpenInviteLimit = errors.New("roomdb: too many open-mode invites")
+
+// ErrInviteQuota is returned if a member already has as many unused invites as the room allows them.
+var ErrInviteQuota = errors.New("roomdb: too many unused invites")
+
+// ErrAliasQuota is returned if a member already has as many aliases as the AliasPolicy allows them.
+var ErrAliasQuota = errors.New("roomdb: too many aliases")
+
+// ErrAliasTransferNotAuthorized is returned if somebody tries to take over an alias
+// that wasn't handed to them by its owner or a moderator.
+var ErrAliasTransferNotAuthorized = errors.New("roomdb: the alias wasn't handed to that feed")
+
 // Alias is how the roomdb stores an alias.
 type Alias struct {
 	ID int64
@@ -26,6 +48,9 @@ type Alias struct {
 	Feed refs.FeedRef // the ssb identity that belongs to the user
 
 	Signature []byte
+
+	// TransferTo is the feed that is allowed to take over the alias, or nil if there is no pending transfer.
+	TransferTo *refs.FeedRef
 }
 
 type ErrAliasTaken struct {
@@ -36,6 +61,36 @@ func (e ErrAliasTaken) Error() string {
 	return fmt.Sprintf("alias (%q) is already taken", e.Name)
 }
 
+// ErrAliasNotAllowed is returned if an alias goes against the AliasPolicy of the room.
+type ErrAliasNotAllowed struct {
+	Name   string
+	Reason string
+}
+
+func (e ErrAliasNotAllowed) Error() string {
+	return fmt.Sprintf("alias (%q) is not allowed: %s", e.Name, e.Reason)
+}
+
+// AliasPolicy is what the admins of a room decided about the aliases that can be registered,
+// on top of the characters that are always required.
+type AliasPolicy struct {
+	// Reserved names can't be registered by anyone, like admin or www.
+	Reserved []string
+
+	// Blocked can't be part of an alias. An entry between slashes, like /^x+$/, is a regular expression.
+	Blocked []string
+
+	// MinLength is the shortest alias that can be registered, 0 means any length.
+	MinLength uint
+
+	// AllowHyphens lets aliases have hyphens, as long as they don't start or end with one.
+	AllowHyphens bool
+
+	// PerMember is how many aliases a member can have, 0 means no limit.
+	// It doesn't apply to moderators and admins.
+	PerMember uint
+}
+
 // Member holds all the information an internal user of the room has.
 type Member struct {
 	ID      int64
@@ -179,6 +234,348 @@ type Invite struct {
 
 	CreatedBy Member
 	CreatedAt time.Time
+
+	// Role is given to the member that uses the invite.
+	Role Role
+
+	// ForFeed is the only feed that can use the invite, or nil if anyone can.
+	ForFeed *refs.FeedRef
+
+	// OpenMode is true if anyone created it, while the room was open.
+	OpenMode bool
+}
+
+// InviteOptions are the optional settings of a new invite.
+type InviteOptions struct {
+	// Role is given to the member that uses the invite. It can't be higher than the role of the creator.
+	// RoleUnknown, the zero value, means RoleMember.
+	Role Role
+
+	// ForFeed restricts the invite to this feed, if set.
+	ForFeed *refs.FeedRef
+}
+
+// InviteUse records that somebody joined the room with an invite, and who created that invite.
+// They are kept when the members are removed, so that moderators can still tell who brought whom into the room.
+type InviteUse struct {
+	ID       int64
+	InviteID int64
+
+	// InvitedBy is the member that created the invite. Only the ID is set if they were removed since.
+	InvitedBy Member
+
+	// Member is the one that used the invite. Only the ID and PubKey are set if they were removed since.
+	Member Member
+
+	UsedAt time.Time
+}
+
+//go:generate go run golang.org/x/tools/cmd/stringer -type=InviteStatus
+
+// InviteStatus tells what happened to an invite since it was created
+type InviteStatus uint
+
+const (
+	InviteStatusActive InviteStatus = iota
+	InviteStatusConsumed
+	InviteStatusExpired
+	InviteStatusRevoked
+)
+
+// CreatedInvite is an invite as its creator sees it, including the ones that can't be used anymore.
+type CreatedInvite struct {
+	Invite
+
+	Status InviteStatus
+
+	// UsedBy is the member that consumed the invite, if the status is InviteStatusConsumed.
+	// Only the ID and PubKey are set if they were removed since.
+	UsedBy *Member
+	UsedAt time.Time
+}
+
+// InviteBatchMaxSize is the most invites that can be created in one batch.
+const InviteBatchMaxSize = 100
+
+// InviteBatch is a set of invites that were created together, for a workshop or a meetup.
+// Its invites can't be used after ExpiresAt and they can be revoked all at once.
+type InviteBatch struct {
+	ID   int64
+	Name string
+
+	CreatedBy int64
+	CreatedAt time.Time
+	ExpiresAt time.Time
+
+	// Unused is the number of invites of the batch that can still be used
+	Unused int
+}
+
+// OpenInviteLimits protect the creation of invites in open mode, where anyone can create them.
+type OpenInviteLimits struct {
+	// ProofOfWorkBits is how many leading zero bits the proof of work needs, 0 turns it off.
+	ProofOfWorkBits uint
+
+	// PerAddress is how many invites can be created from the same address in a day, 0 means no limit.
+	PerAddress uint
+}
+
+// OpenInvitesMaxProofOfWorkBits keeps the proof of work solvable in a browser.
+const OpenInvitesMaxProofOfWorkBits = 24
+
+//go:generate go run golang.org/x/tools/cmd/stringer -type=InviteRequestState
+
+// InviteRequestState tells if a moderator already decided on an invite request
+type InviteRequestState uint
+
+const (
+	InviteRequestPending InviteRequestState = iota
+	InviteRequestApproved
+	InviteRequestRejected
+)
+
+// InviteRequest is made by somebody outside of the room who wants to join it.
+// If a moderator approves it, an invite for the feed of the request is created and handed to the requester.
+type InviteRequest struct {
+	ID int64
+
+	PubKey  refs.FeedRef
+	Message string
+
+	State InviteRequestState
+
+	// InviteToken is the invite that was created for the request, once it was approved
+	InviteToken string
+
+	CreatedAt time.Time
+	DecidedAt time.Time
+}
+
+// SIWSSBSession is a sign-in with ssb session of a member, as listed by the AuthWithSSBService.
+// The token itself is only stored in the cookie of the browser and isn't part of it.
+type SIWSSBSession struct {
+	ID       int64
+	MemberID int64
+
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+
+	// UserAgent and RemoteAddr are from the last time the session was stored in a browser.
+	// RemoteAddr might be truncated, depending on how the room is configured.
+	UserAgent  string
+	RemoteAddr string
+
+	// Device is true for sessions from the device sign-in
+	Device bool
+}
+
+// WebSession is the server-side part of a sign-in session of the web dashboard, see WebSessionsService.
+type WebSession struct {
+	ID       int64
+	MemberID int64 // zero if nobody signed in with it yet
+
+	// Data holds the encoded values of the session
+	Data []byte
+
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+
+	// ExpiresAt is when the session ends at the latest, IdleExpiresAt when it ends if it isn't used until then
+	ExpiresAt     time.Time
+	IdleExpiresAt time.Time
+}
+
+//go:generate go run golang.org/x/tools/cmd/stringer -type=LockoutKind
+
+// LockoutKind tells what the failed sign-ins of a LoginLockout are counted for
+type LockoutKind uint
+
+// Lockouts are kept for the login that was used (an alias or a feed) and for the address the sign-in came from
+const (
+	LockoutKindUnknown LockoutKind = iota
+	LockoutKindLogin
+	LockoutKindAddress
+)
+
+// LoginLockout counts the failed fallback password sign-ins of a login or an IP address
+type LoginLockout struct {
+	ID int64
+
+	Kind    LockoutKind
+	Subject string // the login or the address
+
+	Failures    int
+	LastFailure time.Time
+
+	// LockedUntil is the zero time if the subject was never locked out
+	LockedUntil time.Time
+}
+
+// Active returns true while the subject is locked out
+func (l LoginLockout) Active() bool {
+	return time.Now().Before(l.LockedUntil)
+}
+
+// ErrLockedOut is returned by the LoginLockoutService while a login or an address is locked out
+type ErrLockedOut struct {
+	Kind    LockoutKind
+	Subject string
+	Until   time.Time
+}
+
+func (e ErrLockedOut) Error() string {
+	return fmt.Sprintf("roomdb: %s %q is locked out until %s", e.Kind, e.Subject, e.Until.Format(time.RFC3339))
+}
+
+// APITokenScope limits what an APIToken can be used for
+type APITokenScope string
+
+// The scopes of the admin endpoints, split into reading and changing things
+const (
+	APIScopeInvitesRead     APITokenScope = "invites:read"
+	APIScopeInvitesCreate   APITokenScope = "invites:create"
+	APIScopeInvitesRevoke   APITokenScope = "invites:revoke"
+	APIScopeMembersRead     APITokenScope = "members:read"
+	APIScopeMembersWrite    APITokenScope = "members:write"
+	APIScopeDeniedKeysRead  APITokenScope = "denied-keys:read"
+	APIScopeDeniedKeysWrite APITokenScope = "denied-keys:write"
+	APIScopeAliasesRead     APITokenScope = "aliases:read"
+	APIScopeAliasesWrite    APITokenScope = "aliases:write"
+	APIScopeNoticesRead     APITokenScope = "notices:read"
+	APIScopeNoticesWrite    APITokenScope = "notices:write"
+	APIScopeSettingsRead    APITokenScope = "settings:read"
+	APIScopeSettingsWrite   APITokenScope = "settings:write"
+	APIScopeStatsRead       APITokenScope = "stats:read"
+)
+
+// APITokenScopes lists all the scopes a token can be created with
+var APITokenScopes = []APITokenScope{
+	APIScopeInvitesRead,
+	APIScopeInvitesCreate,
+	APIScopeInvitesRevoke,
+	APIScopeMembersRead,
+	APIScopeMembersWrite,
+	APIScopeDeniedKeysRead,
+	APIScopeDeniedKeysWrite,
+	APIScopeAliasesRead,
+	APIScopeAliasesWrite,
+	APIScopeNoticesRead,
+	APIScopeNoticesWrite,
+	APIScopeSettingsRead,
+	APIScopeSettingsWrite,
+	APIScopeStatsRead,
+}
+
+// IsValid returns an error if the scope is not one of APITokenScopes
+func (s APITokenScope) IsValid() error {
+	for _, known := range APITokenScopes {
+		if s == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("roomdb: unknown api token scope %q", string(s))
+}
+
+// APIToken is a personal token of an admin or moderator, that scripts can use instead of signing in.
+// The token itself is only returned once, when it's created.
+type APIToken struct {
+	ID       int64
+	MemberID int64
+
+	// Name is picked by the member, to tell their tokens apart
+	Name string
+
+	Scopes []APITokenScope
+
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	LastUsedAt time.Time
+}
+
+// HasScope returns true if the token was created with that scope
+func (t APIToken) HasScope(scope APITokenScope) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Expired returns true if the token can't be used anymore
+func (t APIToken) Expired() bool {
+	return !time.Now().Before(t.ExpiresAt)
+}
+
+// OIDCClient is an app that members can sign into with their room identity, as registered by an admin.
+type OIDCClient struct {
+	ID int64
+
+	// ClientID is the public identifier of the app, used in the OpenID Connect requests
+	ClientID string
+
+	Name string
+
+	// RedirectURIs are the only places the members are sent back to after they agreed to sign in
+	RedirectURIs []string
+
+	CreatedAt time.Time
+}
+
+// HasRedirectURI returns true if the uri was registered for the client. Only exact matches count.
+func (c OIDCClient) HasRedirectURI(uri string) bool {
+	for _, r := range c.RedirectURIs {
+		if r == uri {
+			return true
+		}
+	}
+	return false
+}
+
+// OIDCAuthCode is what a member agreed to during an OpenID Connect authorization request.
+// It is stored until the client exchanges the code for the tokens.
+type OIDCAuthCode struct {
+	// ClientID is the ID of the OIDCClient, not the public client_id
+	ClientID int64
+	MemberID int64
+
+	RedirectURI string
+
+	// CodeChallenge is the base64url encoded sha256 of the PKCE code verifier
+	CodeChallenge string
+
+	Nonce string
+	Scope string
+
+	ExpiresAt time.Time
+}
+
+// TOTPStatus tells if a member enabled the second factor for the fallback password sign-in
+type TOTPStatus struct {
+	Enabled bool
+
+	// RecoveryCodesLeft is the number of unused recovery codes
+	RecoveryCodesLeft int
+}
+
+// WebAuthnCredential is a passkey that a member registered to sign into the dashboard, as stored by the WebAuthnService.
+type WebAuthnCredential struct {
+	ID       int64
+	MemberID int64
+
+	// Name is picked by the member, to tell their credentials apart
+	Name string
+
+	// CredentialID, PublicKey and AttestationType are returned by the authenticator when the credential is created
+	CredentialID    []byte
+	PublicKey       []byte
+	AttestationType string
+
+	// SignCount is the signature counter of the authenticator, used to detect cloned credentials
+	SignCount uint32
+
+	CreatedAt  time.Time
+	LastUsedAt time.Time
 }
 
 // ListEntry values are returned by the DenyListServices
@@ -11,6 +11,7 @@ import (
 
 	"github.com/ssbc/go-ssb-room/v2/muxrpc/handlers/alias"
 	"github.com/ssbc/go-ssb-room/v2/muxrpc/handlers/gossip"
+	"github.com/ssbc/go-ssb-room/v2/muxrpc/handlers/invites"
 	"github.com/ssbc/go-ssb-room/v2/muxrpc/handlers/signinwithssb"
 	"github.com/ssbc/go-ssb-room/v2/muxrpc/handlers/tunnel/server"
 	"github.com/ssbc/go-ssb-room/v2/muxrpc/handlers/whoami"
@@ -33,6 +34,15 @@ func (s *Server) initHandlers() {
 		kitlog.With(s.logger, "unit", "aliases"),
 		s.Whoami(),
 		s.Aliases,
+		s.Config,
+		s.netInfo,
+	)
+
+	invitesHandler := invites.New(
+		kitlog.With(s.logger, "unit", "invites"),
+		s.Invites,
+		s.Members,
+		s.DeniedKeys,
 		s.netInfo,
 	)
 
@@ -62,6 +72,11 @@ func (s *Server) initHandlers() {
 		mux.RegisterAsync(append(method, "registerAlias"), typemux.AsyncFunc(aliasHandler.Register))
 		mux.RegisterAsync(append(method, "revokeAlias"), typemux.AsyncFunc(aliasHandler.Revoke))
 		mux.RegisterAsync(append(method, "listAliases"), typemux.AsyncFunc(aliasHandler.List))
+		mux.RegisterAsync(append(method, "transferAlias"), typemux.AsyncFunc(aliasHandler.Transfer))
+		mux.RegisterAsync(append(method, "cancelAliasTransfer"), typemux.AsyncFunc(aliasHandler.CancelTransfer))
+		mux.RegisterAsync(append(method, "acceptAlias"), typemux.AsyncFunc(aliasHandler.Accept))
+		mux.RegisterAsync(append(method, "consumeInvite"), typemux.AsyncFunc(invitesHandler.Consume))
+		mux.RegisterAsync(append(method, "listMyInvites"), typemux.AsyncFunc(invitesHandler.ListMine))
 
 		method = muxrpc.Method{"httpAuth"}
 		mux.RegisterAsync(append(method, "invalidateAllSolutions"), typemux.AsyncFunc(siwssbHandler.InvalidateAllSolutions))
@@ -70,4 +85,8 @@ func (s *Server) initHandlers() {
 		method = muxrpc.Method{"gossip"}
 		mux.RegisterDuplex(append(method, "ping"), typemux.DuplexFunc(gossip.Ping))
 	}
+
+	// non-members of restricted rooms can only claim an invite
+	s.guest.RegisterAsync(muxrpc.Method{"manifest"}, manifest)
+	s.guest.RegisterAsync(muxrpc.Method{"room", "consumeInvite"}, typemux.AsyncFunc(invitesHandler.Consume))
 }
@@ -35,18 +35,18 @@ func (s *Server) initNetwork() error {
 			return nil, fmt.Errorf("running with unknown privacy mode")
 		}
 
-		// if privacy mode is restricted, deny connections from non-members
-		if pm == roomdb.ModeRestricted {
-			if _, err := s.Members.GetByFeed(s.rootCtx, remote); err != nil {
-				return nil, fmt.Errorf("access restricted to members")
-			}
-		}
-
 		// if feed is in the deny list, deny their connection
 		if s.DeniedKeys.HasFeed(s.rootCtx, remote) {
 			return nil, fmt.Errorf("this key has been banned")
 		}
 
+		// if privacy mode is restricted, non-members can only claim an invite
+		if pm == roomdb.ModeRestricted {
+			if _, err := s.Members.GetByFeed(s.rootCtx, remote); err != nil {
+				return &s.guest, nil
+			}
+		}
+
 		// for community + open modes, allow all connections
 		return &s.public, nil
 	}
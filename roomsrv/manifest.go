@@ -42,10 +42,19 @@ const manifest manifestHandler = `
 		"registerAlias": "async",
 		"revokeAlias": "async",
 		"listAliases": "async",
+		"transferAlias": "async",
+		"cancelAliasTransfer": "async",
+		"acceptAlias": "async",
+		"consumeInvite": "async",
+		"listMyInvites": "async",
 
 		"connect": "duplex",
 		"attendants": "source",
+		"announceFeatures": "async",
 		"members": "source",
+		"joinChannel": "async",
+		"leaveChannel": "async",
+		"channelAttendants": "source",
 		"metadata": "async",
 		"ping": "sync"
 	},
@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/ssbc/go-netwrap"
 	"github.com/ssbc/go-ssb-room/v2/internal/maybemod/keys"
@@ -90,6 +91,22 @@ func WithContext(ctx context.Context) Option {
 	}
 }
 
+// WithHeartbeat changes how often attendants are probed and after how many missed probes in a row they are evicted from the room.
+// An interval of zero disables the probes.
+func WithHeartbeat(interval time.Duration, maxMissed int) Option {
+	return func(s *Server) error {
+		if interval < 0 {
+			return fmt.Errorf("heartbeat: interval can't be negative")
+		}
+		if maxMissed < 1 {
+			return fmt.Errorf("heartbeat: need to allow at least one missed probe, got %d", maxMissed)
+		}
+		s.heartbeatInterval = interval
+		s.heartbeatMaxMissed = maxMissed
+		return nil
+	}
+}
+
 // TODO: remove all this network stuff and make them options on network
 
 // WithDialer changes the function that is used to dial remote peers.
@@ -15,6 +15,7 @@ import (
 	"os/user"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/ssbc/go-muxrpc/v2/typemux"
 	"github.com/ssbc/go-netwrap"
@@ -62,12 +63,17 @@ type Server struct {
 
 	public typemux.HandlerMux
 	master typemux.HandlerMux
+	guest  typemux.HandlerMux
 
 	StateManager *roomstate.Manager
 
+	heartbeatInterval  time.Duration
+	heartbeatMaxMissed int
+
 	Members    roomdb.MembersService
 	DeniedKeys roomdb.DeniedKeysService
 	Aliases    roomdb.AliasesService
+	Invites    roomdb.InvitesService
 
 	authWithSSB       roomdb.AuthWithSSBService
 	authWithSSBBridge *signinwithssb.SignalBridge
@@ -82,6 +88,7 @@ func New(
 	membersdb roomdb.MembersService,
 	deniedkeysdb roomdb.DeniedKeysService,
 	aliasdb roomdb.AliasesService,
+	invitesdb roomdb.InvitesService,
 	awsdb roomdb.AuthWithSSBService,
 	bridge *signinwithssb.SignalBridge,
 	config roomdb.RoomConfig,
@@ -94,6 +101,7 @@ func New(
 	s.Members = membersdb
 	s.DeniedKeys = deniedkeysdb
 	s.Aliases = aliasdb
+	s.Invites = invitesdb
 	s.Config = config
 
 	s.authWithSSB = awsdb
@@ -101,6 +109,9 @@ func New(
 
 	s.netInfo = netInfo
 
+	s.heartbeatInterval = roomstate.DefaultHeartbeatInterval
+	s.heartbeatMaxMissed = roomstate.DefaultHeartbeatMaxMissed
+
 	for i, opt := range opts {
 		err := opt(&s)
 		if err != nil {
@@ -143,6 +154,7 @@ func New(
 
 	s.public = typemux.New(kitlog.With(s.logger, "mux", "public"))
 	s.master = typemux.New(kitlog.With(s.logger, "mux", "master"))
+	s.guest = typemux.New(kitlog.With(s.logger, "mux", "guest"))
 
 	if s.rootCtx == nil {
 		s.rootCtx, s.Shutdown = context.WithCancel(context.Background())
@@ -162,6 +174,10 @@ func New(
 
 	s.StateManager = roomstate.NewManager(s.logger)
 
+	if s.heartbeatInterval > 0 {
+		go s.StateManager.RunHeartbeat(s.rootCtx, s.heartbeatInterval, s.heartbeatMaxMissed)
+	}
+
 	s.initHandlers()
 
 	if err := s.initNetwork(); err != nil {
@@ -0,0 +1,360 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package roomstate
+
+import (
+	"errors"
+	"sort"
+	"sync/atomic"
+
+	refs "github.com/ssbc/go-ssb-refs"
+
+	"github.com/ssbc/go-ssb-room/v2/internal/broadcasts"
+)
+
+// MaxChannelsPerAttendant limits how many channels a single attendant can be part of at the same time.
+const MaxChannelsPerAttendant = 32
+
+var (
+	ErrInvalidChannelName = errors.New("roomstate: invalid channel name")
+	ErrNotAttendant       = errors.New("roomstate: peer is not an attendant of the room")
+	ErrTooManyChannels    = errors.New("roomstate: peer joined too many channels")
+	ErrNotInChannel       = errors.New("roomstate: peer didn't join the channel")
+)
+
+// IsValidChannelName returns true if the name is between 1 and 63 characters of lowercase letters, digits and hyphens.
+// It can't start or end with a hyphen, like "dev" or "local-berlin".
+func IsValidChannelName(name string) bool {
+	if n := len(name); n < 1 || n > 63 {
+		return false
+	}
+
+	if name[0] == '-' || name[len(name)-1] == '-' {
+		return false
+	}
+
+	for _, char := range name {
+		isLower := char >= 'a' && char <= 'z'
+		isDigit := char >= '0' && char <= '9'
+		if !isLower && !isDigit && char != '-' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// channel is a named subset of the attendants of the room.
+// It only exists as long as there are attendants in it or someone is watching it.
+type channel struct {
+	attendants map[string]refs.FeedRef
+	watchers   int
+
+	// the registered sinks of each peer, which are closed when they leave the channel
+	watchersOf map[string][]*channelWatcher
+
+	updater     broadcasts.AttendantsUpdater
+	broadcaster *broadcasts.AttendantsBroadcast
+}
+
+// ChannelCount holds the number of attendants in a channel
+type ChannelCount struct {
+	Name       string
+	Attendants int
+}
+
+// getOrCreateChannel needs to be called with roomMu held
+func (m *Manager) getOrCreateChannel(name string) *channel {
+	ch, has := m.channels[name]
+	if !has {
+		ch = &channel{
+			attendants: make(map[string]refs.FeedRef),
+			watchersOf: make(map[string][]*channelWatcher),
+		}
+		ch.updater, ch.broadcaster = broadcasts.NewAttendantsEmitter()
+		m.channels[name] = ch
+	}
+	return ch
+}
+
+// dropChannelIfUnused needs to be called with roomMu held
+func (m *Manager) dropChannelIfUnused(name string, ch *channel) {
+	if len(ch.attendants) > 0 || ch.watchers > 0 {
+		return
+	}
+	if m.channels[name] == ch {
+		delete(m.channels, name)
+	}
+	ch.updater.Close()
+}
+
+// JoinChannel adds an attendant of the room to the named channel.
+func (m *Manager) JoinChannel(name string, who refs.FeedRef) error {
+	if !IsValidChannelName(name) {
+		return ErrInvalidChannelName
+	}
+
+	m.roomMu.Lock()
+	defer m.roomMu.Unlock()
+
+	key := who.String()
+	if _, has := m.room[key]; !has {
+		return ErrNotAttendant
+	}
+
+	joined := m.joinedChannels[key]
+	if _, has := joined[name]; has {
+		return nil
+	}
+
+	if len(joined) >= MaxChannelsPerAttendant {
+		return ErrTooManyChannels
+	}
+
+	if joined == nil {
+		joined = make(map[string]struct{})
+		m.joinedChannels[key] = joined
+	}
+	joined[name] = struct{}{}
+
+	ch := m.getOrCreateChannel(name)
+	ch.attendants[key] = who
+	ch.updater.Joined(who)
+
+	return nil
+}
+
+// LeaveChannel removes the peer from the named channel.
+func (m *Manager) LeaveChannel(name string, who refs.FeedRef) {
+	m.roomMu.Lock()
+	m.leaveChannel(name, who)
+	m.roomMu.Unlock()
+}
+
+// leaveChannel needs to be called with roomMu held
+func (m *Manager) leaveChannel(name string, who refs.FeedRef) {
+	key := who.String()
+
+	joined := m.joinedChannels[key]
+	if _, has := joined[name]; !has {
+		return
+	}
+	delete(joined, name)
+	if len(joined) == 0 {
+		delete(m.joinedChannels, key)
+	}
+
+	ch, has := m.channels[name]
+	if !has {
+		return
+	}
+	delete(ch.attendants, key)
+
+	// the peer isn't allowed to follow the channel anymore
+	for _, watcher := range ch.watchersOf[key] {
+		if watcher.detach() {
+			ch.watchers--
+		}
+	}
+	delete(ch.watchersOf, key)
+
+	ch.updater.Left(who)
+
+	m.dropChannelIfUnused(name, ch)
+}
+
+// leaveAllChannels needs to be called with roomMu held
+func (m *Manager) leaveAllChannels(who refs.FeedRef) {
+	for name := range m.joinedChannels[who.String()] {
+		m.leaveChannel(name, who)
+	}
+}
+
+// InChannel returns true if the peer joined the named channel.
+func (m *Manager) InChannel(name string, who refs.FeedRef) bool {
+	m.roomMu.Lock()
+	defer m.roomMu.Unlock()
+	_, has := m.joinedChannels[who.String()][name]
+	return has
+}
+
+// ChannelAttendants returns the attendants of the named channel, sorted by their feed reference.
+func (m *Manager) ChannelAttendants(name string) []refs.FeedRef {
+	m.roomMu.Lock()
+	defer m.roomMu.Unlock()
+
+	ch, has := m.channels[name]
+	if !has {
+		return []refs.FeedRef{}
+	}
+	return ch.sortedAttendants()
+}
+
+// sortedAttendants needs to be called with roomMu held
+func (ch *channel) sortedAttendants() []refs.FeedRef {
+	keys := make([]string, 0, len(ch.attendants))
+	for k := range ch.attendants {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	lst := make([]refs.FeedRef, len(keys))
+	for i, k := range keys {
+		lst[i] = ch.attendants[k]
+	}
+	return lst
+}
+
+// ChannelCounts returns the number of attendants for each channel that has any, sorted by name.
+func (m *Manager) ChannelCounts() []ChannelCount {
+	m.roomMu.Lock()
+	defer m.roomMu.Unlock()
+
+	counts := make([]ChannelCount, 0, len(m.channels))
+	for name, ch := range m.channels {
+		if len(ch.attendants) == 0 {
+			continue
+		}
+		counts = append(counts, ChannelCount{
+			Name:       name,
+			Attendants: len(ch.attendants),
+		})
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		return counts[i].Name < counts[j].Name
+	})
+	return counts
+}
+
+// RegisterChannelUpdates registers the sink of the peer for joined and left updates of the named channel.
+// sendState gets the attendants of the channel at the time of the registration. The sink only gets the updates after it returned,
+// so that none of the joins and leaves in between are lost or sent before the state.
+// The peer needs to be in the channel. Once it leaves the channel, the sink is closed.
+func (m *Manager) RegisterChannelUpdates(name string, who refs.FeedRef, sink broadcasts.AttendantsEmitter, sendState func([]refs.FeedRef) error) error {
+	if !IsValidChannelName(name) {
+		return ErrInvalidChannelName
+	}
+
+	watcher, attendants, err := m.registerChannelWatcher(name, who, sink)
+	if err != nil {
+		return err
+	}
+
+	// sent without holding roomMu, the updates in the meantime wait in the queue of the watcher
+	err = sendState(attendants)
+	close(watcher.ready)
+	if err != nil {
+		watcher.unregister()
+		return err
+	}
+	return nil
+}
+
+// registerChannelWatcher takes the snapshot of the attendants under the same lock as the registration
+func (m *Manager) registerChannelWatcher(name string, who refs.FeedRef, sink broadcasts.AttendantsEmitter) (*channelWatcher, []refs.FeedRef, error) {
+	m.roomMu.Lock()
+	defer m.roomMu.Unlock()
+
+	key := who.String()
+	if _, has := m.joinedChannels[key][name]; !has {
+		return nil, nil, ErrNotInChannel
+	}
+
+	ch := m.getOrCreateChannel(name)
+	ch.watchers++
+
+	watcher := &channelWatcher{
+		AttendantsEmitter: sink,
+		ready:             make(chan struct{}),
+	}
+	watcher.release = func() {
+		m.roomMu.Lock()
+		defer m.roomMu.Unlock()
+		ch.watchers--
+		ch.forgetWatcher(key, watcher)
+		m.dropChannelIfUnused(name, ch)
+	}
+	watcher.unregister = ch.broadcaster.Register(watcher)
+	ch.watchersOf[key] = append(ch.watchersOf[key], watcher)
+
+	return watcher, ch.sortedAttendants(), nil
+}
+
+// forgetWatcher needs to be called with roomMu held
+func (ch *channel) forgetWatcher(key string, watcher *channelWatcher) {
+	list := ch.watchersOf[key]
+	for i, w := range list {
+		if w == watcher {
+			list = append(list[:i], list[i+1:]...)
+			break
+		}
+	}
+	if len(list) == 0 {
+		delete(ch.watchersOf, key)
+		return
+	}
+	ch.watchersOf[key] = list
+}
+
+// channelWatcher notices when a registered sink is closed, so that unused channels can be dropped.
+type channelWatcher struct {
+	broadcasts.AttendantsEmitter
+
+	// ready is closed once the initial state was sent, the updates wait for it
+	ready chan struct{}
+
+	// released is set once, either by closing the sink or by detaching it
+	released int32 // accessed atomically
+	release  func()
+
+	detached   int32 // accessed atomically
+	unregister func()
+}
+
+// detach drops all further updates and closes the sink in the background.
+// It needs to be called with roomMu held. It returns true if the caller needs to update the watcher count,
+// which isn't the case if the sink is already being closed.
+func (cw *channelWatcher) detach() bool {
+	atomic.StoreInt32(&cw.detached, 1)
+	go cw.unregister()
+	return atomic.CompareAndSwapInt32(&cw.released, 0, 1)
+}
+
+// releaseOnce needs to be called without roomMu held
+func (cw *channelWatcher) releaseOnce() {
+	if atomic.CompareAndSwapInt32(&cw.released, 0, 1) {
+		cw.release()
+	}
+}
+
+func (cw *channelWatcher) Joined(member refs.FeedRef) error {
+	<-cw.ready
+	if atomic.LoadInt32(&cw.detached) == 1 {
+		return nil
+	}
+	return cw.AttendantsEmitter.Joined(member)
+}
+
+func (cw *channelWatcher) Left(member refs.FeedRef) error {
+	<-cw.ready
+	if atomic.LoadInt32(&cw.detached) == 1 {
+		return nil
+	}
+	return cw.AttendantsEmitter.Left(member)
+}
+
+func (cw *channelWatcher) Close() error {
+	cw.releaseOnce()
+	return cw.AttendantsEmitter.Close()
+}
+
+func (cw *channelWatcher) CloseWithError(err error) error {
+	cw.releaseOnce()
+	if ec, ok := cw.AttendantsEmitter.(interface{ CloseWithError(error) error }); ok {
+		return ec.CloseWithError(err)
+	}
+	return cw.AttendantsEmitter.Close()
+}
@@ -0,0 +1,170 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package roomstate
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ssbc/go-muxrpc/v2"
+	"github.com/stretchr/testify/require"
+	kitlog "go.mindeco.de/log"
+
+	refs "github.com/ssbc/go-ssb-refs"
+)
+
+// recordingEmitter remembers the updates it got and if it was closed
+type recordingEmitter struct {
+	mu      sync.Mutex
+	updates []string
+	closed  bool
+}
+
+func (re *recordingEmitter) Joined(member refs.FeedRef) error {
+	re.mu.Lock()
+	defer re.mu.Unlock()
+	re.updates = append(re.updates, "joined:"+member.String())
+	return nil
+}
+
+func (re *recordingEmitter) Left(member refs.FeedRef) error {
+	re.mu.Lock()
+	defer re.mu.Unlock()
+	re.updates = append(re.updates, "left:"+member.String())
+	return nil
+}
+
+func (re *recordingEmitter) Close() error {
+	re.mu.Lock()
+	defer re.mu.Unlock()
+	re.closed = true
+	return nil
+}
+
+func (re *recordingEmitter) state() ([]string, bool) {
+	re.mu.Lock()
+	defer re.mu.Unlock()
+	return append([]string{}, re.updates...), re.closed
+}
+
+func ignoreState([]refs.FeedRef) error { return nil }
+
+// a peer that leaves a channel doesn't hear about it anymore
+func TestChannelUpdatesStopAfterLeaving(t *testing.T) {
+	r := require.New(t)
+
+	m := NewManager(kitlog.NewNopLogger())
+
+	var peers []refs.FeedRef
+	for _, seed := range []string{"alfa", "brav", "char"} {
+		feed, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte(seed), 8), refs.RefAlgoFeedSSB1)
+		r.NoError(err)
+		m.AddEndpoint(feed, new(muxrpc.FakeEndpoint))
+		peers = append(peers, feed)
+	}
+	alf, bre, cha := peers[0], peers[1], peers[2]
+
+	// only members of the channel can follow it
+	err := m.RegisterChannelUpdates("dev", alf, new(recordingEmitter), ignoreState)
+	r.ErrorIs(err, ErrNotInChannel)
+
+	r.NoError(m.JoinChannel("dev", alf))
+
+	watcher := new(recordingEmitter)
+	r.NoError(m.RegisterChannelUpdates("dev", alf, watcher, ignoreState))
+
+	r.NoError(m.JoinChannel("dev", bre))
+	r.Eventually(func() bool {
+		updates, _ := watcher.state()
+		return len(updates) == 1
+	}, time.Second, 10*time.Millisecond, "should hear that bre joined")
+
+	m.LeaveChannel("dev", alf)
+	r.Eventually(func() bool {
+		_, closed := watcher.state()
+		return closed
+	}, time.Second, 10*time.Millisecond, "the sink should be closed after leaving")
+
+	updatesAfterLeaving, _ := watcher.state()
+
+	// the channel goes on without alf
+	r.NoError(m.JoinChannel("dev", cha))
+	m.LeaveChannel("dev", bre)
+	r.Equal([]refs.FeedRef{cha}, m.ChannelAttendants("dev"))
+
+	time.Sleep(50 * time.Millisecond)
+	updates, _ := watcher.state()
+	r.Equal(updatesAfterLeaving, updates, "no updates after leaving")
+	r.Equal([]string{"joined:" + bre.String()}, updates)
+}
+
+// joins while the state is sent are not lost and arrive after the state
+func TestChannelUpdatesDuringSubscription(t *testing.T) {
+	r := require.New(t)
+
+	m := NewManager(kitlog.NewNopLogger())
+
+	newPeer := func(i int) refs.FeedRef {
+		feed, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte{byte(i)}, 32), refs.RefAlgoFeedSSB1)
+		r.NoError(err)
+		m.AddEndpoint(feed, new(muxrpc.FakeEndpoint))
+		return feed
+	}
+
+	alf := newPeer(0)
+	r.NoError(m.JoinChannel("dev", alf))
+
+	const joining = 50
+	peers := make([]refs.FeedRef, joining)
+	for i := range peers {
+		peers[i] = newPeer(i + 1)
+	}
+
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-start
+		for _, p := range peers {
+			m.JoinChannel("dev", p)
+		}
+	}()
+
+	var (
+		stateSent bool
+		seen      = make(map[string]bool)
+	)
+	watcher := new(recordingEmitter)
+	err := m.RegisterChannelUpdates("dev", alf, watcher, func(attendants []refs.FeedRef) error {
+		// let the others join while the state is on its way
+		close(start)
+		time.Sleep(10 * time.Millisecond)
+
+		updates, _ := watcher.state()
+		r.Len(updates, 0, "updates before the state")
+
+		for _, a := range attendants {
+			seen[a.String()] = true
+		}
+		stateSent = true
+		return nil
+	})
+	r.NoError(err)
+	r.True(stateSent)
+	wg.Wait()
+
+	// everybody is either in the state or in the updates
+	r.Eventually(func() bool {
+		updates, _ := watcher.state()
+		for _, u := range updates {
+			seen[strings.TrimPrefix(u, "joined:")] = true
+		}
+		return len(seen) == joining+1
+	}, time.Second, 10*time.Millisecond, "missed joins")
+}
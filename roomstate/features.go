@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package roomstate
+
+import (
+	"fmt"
+	"sort"
+
+	refs "github.com/ssbc/go-ssb-refs"
+)
+
+// These limits keep the announced features to short capability names, so that the room doesn't turn into a general data store.
+const (
+	MaxFeaturesPerAttendant = 16
+	MaxFeatureLength        = 32
+)
+
+// ErrInvalidFeature is returned if an announced feature doesn't pass ValidateFeatures.
+type ErrInvalidFeature struct {
+	Feature string
+	Reason  string
+}
+
+func (e ErrInvalidFeature) Error() string {
+	return fmt.Sprintf("roomstate: invalid feature %q: %s", e.Feature, e.Reason)
+}
+
+// ValidateFeatures checks the list of capabilities an attendant wants to announce, like "ebt" or "metafeeds".
+// Each feature needs to start with a lowercase letter or digit, followed by lowercase letters, digits or one of - _ . :
+// It returns the sorted list without duplicates.
+func ValidateFeatures(features []string) ([]string, error) {
+	set := make(map[string]struct{}, len(features))
+	for _, f := range features {
+		if n := len(f); n < 1 || n > MaxFeatureLength {
+			return nil, ErrInvalidFeature{Feature: f, Reason: fmt.Sprintf("needs to be between 1 and %d characters long", MaxFeatureLength)}
+		}
+
+		for i, char := range f {
+			isLower := char >= 'a' && char <= 'z'
+			isDigit := char >= '0' && char <= '9'
+			if isLower || isDigit {
+				continue
+			}
+			if i > 0 && (char == '-' || char == '_' || char == '.' || char == ':') {
+				continue
+			}
+			return nil, ErrInvalidFeature{Feature: f, Reason: "contains invalid characters"}
+		}
+
+		set[f] = struct{}{}
+	}
+
+	if n := len(set); n > MaxFeaturesPerAttendant {
+		return nil, fmt.Errorf("roomstate: too many features (%d), only %d are allowed", n, MaxFeaturesPerAttendant)
+	}
+
+	valid := make([]string, 0, len(set))
+	for f := range set {
+		valid = append(valid, f)
+	}
+	sort.Strings(valid)
+	return valid, nil
+}
+
+// SetFeatures replaces the announced features of an attendant of the room.
+// Everyone who follows the attendants of the room, or a channel they are in, receives a fresh joined update with the new features.
+// It is sent as an update, so that it can't swallow a following leave of the attendant for slow subscribers.
+func (m *Manager) SetFeatures(who refs.FeedRef, features []string) error {
+	valid, err := ValidateFeatures(features)
+	if err != nil {
+		return err
+	}
+
+	m.roomMu.Lock()
+	defer m.roomMu.Unlock()
+
+	key := who.String()
+	if _, has := m.room[key]; !has {
+		return ErrNotAttendant
+	}
+
+	if len(valid) == 0 {
+		delete(m.features, key)
+	} else {
+		m.features[key] = valid
+	}
+
+	m.attendantsUpdater.Updated(who)
+	for name := range m.joinedChannels[key] {
+		if ch, has := m.channels[name]; has {
+			ch.updater.Updated(who)
+		}
+	}
+
+	return nil
+}
+
+// Features returns the features an attendant announced, or nil if they didn't.
+func (m *Manager) Features(who refs.FeedRef) []string {
+	m.roomMu.Lock()
+	defer m.roomMu.Unlock()
+	return m.features[who.String()]
+}
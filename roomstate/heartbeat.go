@@ -0,0 +1,159 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package roomstate
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ssbc/go-muxrpc/v2"
+	"go.mindeco.de/log/level"
+
+	refs "github.com/ssbc/go-ssb-refs"
+)
+
+// Default values for the heartbeat, used by roomsrv unless configured otherwise.
+const (
+	DefaultHeartbeatInterval  = 30 * time.Second
+	DefaultHeartbeatMaxMissed = 3
+)
+
+// probeMethod is called on attendants to see if they are still there.
+// Every muxrpc peer replies to it, even if only with an error, like that it doesn't know the method.
+// Any reply means the connection is alive, timeouts and transport errors count as a missed probe.
+var probeMethod = muxrpc.Method{"whoami"}
+
+// isReply returns true if the error was sent by the peer, instead of coming from the connection
+func isReply(err error) bool {
+	var callErr *muxrpc.CallError
+	return errors.As(err, &callErr)
+}
+
+// liveness holds the results of the heartbeat probes for a single attendant
+type liveness struct {
+	rtt      time.Duration
+	lastSeen time.Time
+	missed   int
+}
+
+// RunHeartbeat probes all attendants every interval until the context is canceled.
+// Probes that don't get a reply within the interval are counted as missed.
+// Attendants that miss maxMissed probes in a row are removed from the room and their connection is terminated.
+// This catches half-open connections, where the muxrpc session never ends by itself.
+func (m *Manager) RunHeartbeat(ctx context.Context, interval time.Duration, maxMissed int) {
+	if maxMissed < 1 {
+		maxMissed = 1
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		m.probeAll(ctx, interval, maxMissed)
+	}
+}
+
+func (m *Manager) probeAll(ctx context.Context, timeout time.Duration, maxMissed int) {
+	m.roomMu.Lock()
+	attendants := make(roomStateMap, len(m.room))
+	for who, edp := range m.room {
+		if edp == nil {
+			continue
+		}
+		attendants[who] = edp
+	}
+	m.roomMu.Unlock()
+
+	var wg sync.WaitGroup
+	wg.Add(len(attendants))
+	for who, edp := range attendants {
+		go func(who string, edp muxrpc.Endpoint) {
+			defer wg.Done()
+			m.probe(ctx, who, edp, timeout, maxMissed)
+		}(who, edp)
+	}
+	wg.Wait()
+}
+
+func (m *Manager) probe(ctx context.Context, who string, edp muxrpc.Endpoint, timeout time.Duration, maxMissed int) {
+	probeCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	var reply interface{}
+	err := edp.Async(probeCtx, &reply, muxrpc.TypeJSON, probeMethod)
+	rtt := time.Since(start)
+
+	if ctx.Err() != nil { // shutting down
+		return
+	}
+	// a closed connection fails right away, that doesn't mean the peer is there
+	missed := err != nil && !isReply(err)
+
+	m.roomMu.Lock()
+	// the peer might have left or reconnected in the meantime
+	if current, has := m.room[who]; !has || current != edp {
+		m.roomMu.Unlock()
+		return
+	}
+
+	l, has := m.liveness[who]
+	if !has {
+		l = &liveness{}
+		m.liveness[who] = l
+	}
+
+	if missed {
+		l.missed++
+	} else {
+		l.missed = 0
+		l.rtt = rtt
+		l.lastSeen = time.Now()
+	}
+	evict := l.missed >= maxMissed
+	m.roomMu.Unlock()
+
+	if !evict {
+		return
+	}
+
+	ref, err := refs.ParseFeedRef(who)
+	if err != nil {
+		level.Warn(m.logger).Log("event", "invalid feed ref in room state", "err", err)
+		return
+	}
+
+	// the peer might have reconnected since the check above, that connection needs to stay
+	if !m.removeIfEndpoint(ref, edp) {
+		return
+	}
+
+	level.Info(m.logger).Log("event", "evicted unresponsive attendant", "peer", who, "missed", maxMissed)
+
+	if err := edp.Terminate(); err != nil {
+		level.Debug(m.logger).Log("event", "failed to terminate unresponsive attendant", "peer", who, "err", err)
+	}
+}
+
+// Latency returns the round-trip time of the last successful heartbeat probe of an attendant.
+// The second return value is false if there wasn't one yet.
+func (m *Manager) Latency(who refs.FeedRef) (time.Duration, bool) {
+	m.roomMu.Lock()
+	defer m.roomMu.Unlock()
+
+	l, has := m.liveness[who.String()]
+	if !has || l.lastSeen.IsZero() {
+		return 0, false
+	}
+	return l.rtt, true
+}
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package roomstate
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/ssbc/go-muxrpc/v2"
+	"github.com/stretchr/testify/require"
+	kitlog "go.mindeco.de/log"
+
+	refs "github.com/ssbc/go-ssb-refs"
+)
+
+// a peer that reconnects while its old connection is evicted keeps the new one
+func TestHeartbeatKeepsReconnectedPeer(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	feed, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte("feed"), 8), refs.RefAlgoFeedSSB1)
+	r.NoError(err)
+
+	m := NewManager(kitlog.NewNopLogger())
+
+	// never replies to the probes
+	stale := new(muxrpc.FakeEndpoint)
+	stale.AsyncCalls(func(ctx context.Context, _ interface{}, _ muxrpc.RequestEncoding, _ muxrpc.Method, _ ...interface{}) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	m.AddEndpoint(feed, stale)
+
+	fresh := new(muxrpc.FakeEndpoint)
+	m.AddEndpoint(feed, fresh)
+
+	r.False(m.removeIfEndpoint(feed, stale), "the old endpoint is already replaced")
+	edp, has := m.Has(feed)
+	r.True(has)
+	r.True(edp == fresh, "the new endpoint should still be there")
+
+	// evicting the only endpoint of a peer works
+	m.AddEndpoint(feed, stale)
+	m.probe(ctx, feed.String(), stale, 10*time.Millisecond, 1)
+	_, has = m.Has(feed)
+	r.False(has, "should have been evicted")
+	r.Equal(1, stale.TerminateCallCount())
+
+	// the peer reconnects while the old connection is probed
+	m.AddEndpoint(feed, stale)
+	stale.AsyncCalls(func(ctx context.Context, _ interface{}, _ muxrpc.RequestEncoding, _ muxrpc.Method, _ ...interface{}) error {
+		<-ctx.Done()
+		m.AddEndpoint(feed, fresh)
+		return ctx.Err()
+	})
+	m.probe(ctx, feed.String(), stale, 10*time.Millisecond, 1)
+
+	edp, has = m.Has(feed)
+	r.True(has, "the reconnected peer should still be in the room")
+	r.True(edp == fresh)
+	r.Equal(0, fresh.TerminateCallCount())
+}
+
+// only replies count, a connection that fails right away is missing the beat
+func TestHeartbeatProbeErrors(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	feed, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte("feed"), 8), refs.RefAlgoFeedSSB1)
+	r.NoError(err)
+
+	m := NewManager(kitlog.NewNopLogger())
+
+	// peers that don't know the method still reply
+	unknown := new(muxrpc.FakeEndpoint)
+	unknown.AsyncReturns(&muxrpc.CallError{Name: "Error", Message: "no such command: whoami"})
+	m.AddEndpoint(feed, unknown)
+
+	m.probe(ctx, feed.String(), unknown, 10*time.Millisecond, 1)
+	_, has := m.Has(feed)
+	r.True(has, "an unknown method is a reply")
+	r.Equal(0, unknown.TerminateCallCount())
+
+	// and so do peers that answer with any other error
+	failing := new(muxrpc.FakeEndpoint)
+	failing.AsyncReturns(&muxrpc.CallError{Name: "TypeError", Message: "cannot read property 'id' of undefined"})
+	m.AddEndpoint(feed, failing)
+
+	m.probe(ctx, feed.String(), failing, 10*time.Millisecond, 1)
+	_, has = m.Has(feed)
+	r.True(has, "any error of the peer is a reply")
+	r.Equal(0, failing.TerminateCallCount())
+
+	// closed connections fail without waiting for the timeout
+	closed := new(muxrpc.FakeEndpoint)
+	closed.AsyncReturns(io.EOF)
+	m.AddEndpoint(feed, closed)
+
+	m.probe(ctx, feed.String(), closed, 10*time.Millisecond, 1)
+	_, has = m.Has(feed)
+	r.False(has, "should have been evicted")
+	r.Equal(1, closed.TerminateCallCount())
+}
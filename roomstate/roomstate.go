@@ -22,11 +22,17 @@ type Manager struct {
 	endpointsUpdater     broadcasts.EndpointsEmitter
 	endpointsbroadcaster *broadcasts.EndpointsBroadcast
 
-	attendantsUpdater     broadcasts.AttendantsEmitter
+	attendantsUpdater     broadcasts.AttendantsUpdater
 	attendantsbroadcaster *broadcasts.AttendantsBroadcast
 
 	roomMu *sync.Mutex
 	room   roomStateMap
+
+	// channels, joinedChannels, features and liveness are also guarded by roomMu
+	channels       map[string]*channel
+	joinedChannels map[string]map[string]struct{} // feed ref -> set of channel names
+	features       map[string][]string            // feed ref -> announced features
+	liveness       map[string]*liveness           // feed ref -> heartbeat results
 }
 
 func NewManager(log kitlog.Logger) *Manager {
@@ -36,6 +42,10 @@ func NewManager(log kitlog.Logger) *Manager {
 	m.attendantsUpdater, m.attendantsbroadcaster = broadcasts.NewAttendantsEmitter()
 	m.roomMu = new(sync.Mutex)
 	m.room = make(roomStateMap)
+	m.channels = make(map[string]*channel)
+	m.joinedChannels = make(map[string]map[string]struct{})
+	m.features = make(map[string][]string)
+	m.liveness = make(map[string]*liveness)
 
 	return &m
 }
@@ -89,6 +99,8 @@ func (m *Manager) AddEndpoint(who refs.FeedRef, edp muxrpc.Endpoint) {
 	m.roomMu.Lock()
 	// add ref to to the room map
 	m.room[who.String()] = edp
+	// a new endpoint starts with a clean slate for the heartbeat
+	delete(m.liveness, who.String())
 	currentMembers := m.room.AsList()
 	m.roomMu.Unlock()
 	// update all the connected tunnel.endpoints calls
@@ -97,13 +109,39 @@ func (m *Manager) AddEndpoint(who refs.FeedRef, edp muxrpc.Endpoint) {
 	m.attendantsUpdater.Joined(who)
 }
 
-// Remove removes the peer from the room
+// Remove removes the peer from the room and all the channels they joined
 func (m *Manager) Remove(who refs.FeedRef) {
 	m.roomMu.Lock()
+	currentMembers := m.removeLocked(who)
+	m.roomMu.Unlock()
+	m.sendLeft(who, currentMembers)
+}
+
+// removeIfEndpoint removes the peer like Remove, but only if it is still in the room with that endpoint.
+// It returns false if the peer left or reconnected in the meantime, so that a new connection isn't removed for the old one.
+func (m *Manager) removeIfEndpoint(who refs.FeedRef, edp muxrpc.Endpoint) bool {
+	m.roomMu.Lock()
+	if current, has := m.room[who.String()]; !has || current != edp {
+		m.roomMu.Unlock()
+		return false
+	}
+	currentMembers := m.removeLocked(who)
+	m.roomMu.Unlock()
+	m.sendLeft(who, currentMembers)
+	return true
+}
+
+// removeLocked needs to be called with roomMu held
+func (m *Manager) removeLocked(who refs.FeedRef) []string {
 	// remove ref from lobby
 	delete(m.room, who.String())
-	currentMembers := m.room.AsList()
-	m.roomMu.Unlock()
+	delete(m.features, who.String())
+	delete(m.liveness, who.String())
+	m.leaveAllChannels(who)
+	return m.room.AsList()
+}
+
+func (m *Manager) sendLeft(who refs.FeedRef, currentMembers []string) {
 	// update all the connected tunnel.endpoints calls
 	m.endpointsUpdater.Update(currentMembers)
 	// update all the connected room.attendants calls
@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+// finds a nonce for which sha256(challenge + ':' + nonce) starts with enough zero bits,
+// see internal/proofofwork for the server side of it
+const form = document.querySelector('#create-invite');
+const waitingElem = document.querySelector('#waiting');
+const challengeElem = form.querySelector('input[name=challenge]');
+const nonceElem = form.querySelector('input[name=nonce]');
+
+const encoder = new TextEncoder();
+
+function leadingZeroBits(bytes) {
+  let zeros = 0;
+  for (const b of bytes) {
+    if (b !== 0) {
+      return zeros + Math.clz32(b) - 24;
+    }
+    zeros += 8;
+  }
+  return zeros;
+}
+
+async function solve(challenge) {
+  const bits = parseInt(challenge.split('.')[0], 10);
+  for (let nonce = 0; ; nonce++) {
+    const digest = await crypto.subtle.digest('SHA-256', encoder.encode(challenge + ':' + nonce));
+    if (leadingZeroBits(new Uint8Array(digest)) >= bits) {
+      return String(nonce);
+    }
+  }
+}
+
+form.onsubmit = async function handleSubmit(ev) {
+  if (nonceElem.value !== '') return;
+  ev.preventDefault();
+  waitingElem.classList.remove('hidden');
+  form.querySelector('button[type=submit]').disabled = true;
+  nonceElem.value = await solve(challengeElem.value);
+  form.submit();
+};
@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+const signInForm = document.querySelector('#webauthn-sign-in');
+const registerForm = document.querySelector('#webauthn-register');
+const waitingElem = document.querySelector('#waiting');
+const errorElem = document.querySelector('#failed');
+const unsupportedElem = document.querySelector('#unsupported');
+
+// the server sends binary values as base64 (the ids) or base64url (the challenge)
+function toBuffer(value) {
+  const b64 = value.replace(/-/g, '+').replace(/_/g, '/');
+  const padded = b64 + '='.repeat((4 - (b64.length % 4)) % 4);
+  return Uint8Array.from(atob(padded), (c) => c.charCodeAt(0)).buffer;
+}
+
+// and expects base64url in the responses
+function fromBuffer(buffer) {
+  const bytes = String.fromCharCode(...new Uint8Array(buffer));
+  return btoa(bytes).replace(/\+/g, '-').replace(/\//g, '_').replace(/=+$/, '');
+}
+
+async function post(form, url, body, contentType) {
+  const resp = await fetch(url, {
+    method: 'POST',
+    credentials: 'same-origin',
+    headers: {
+      'X-CSRF-Token': form.dataset.csrf,
+      'Content-Type': contentType,
+    },
+    body,
+  });
+  const data = await resp.json();
+  if (!resp.ok) {
+    throw new Error(data.error);
+  }
+  return data;
+}
+
+function run(form, ceremony) {
+  if (!window.PublicKeyCredential) {
+    unsupportedElem.classList.remove('hidden');
+    form.classList.add('hidden');
+    return;
+  }
+
+  form.onsubmit = async function handleSubmit(ev) {
+    ev.preventDefault();
+    errorElem.classList.add('hidden');
+    waitingElem.classList.remove('hidden');
+    try {
+      await ceremony(form);
+    } catch (err) {
+      console.error(err);
+      errorElem.classList.remove('hidden');
+    } finally {
+      waitingElem.classList.add('hidden');
+    }
+  };
+}
+
+async function signIn(form) {
+  const params = new URLSearchParams(new FormData(form));
+  const options = await post(form, form.action, params, 'application/x-www-form-urlencoded');
+
+  const publicKey = options.publicKey;
+  publicKey.challenge = toBuffer(publicKey.challenge);
+  (publicKey.allowCredentials || []).forEach((c) => {
+    c.id = toBuffer(c.id);
+  });
+
+  const assertion = await navigator.credentials.get({ publicKey });
+
+  const result = await post(form, form.dataset.finalize, JSON.stringify({
+    id: assertion.id,
+    rawId: fromBuffer(assertion.rawId),
+    type: assertion.type,
+    response: {
+      authenticatorData: fromBuffer(assertion.response.authenticatorData),
+      clientDataJSON: fromBuffer(assertion.response.clientDataJSON),
+      signature: fromBuffer(assertion.response.signature),
+      userHandle: assertion.response.userHandle ? fromBuffer(assertion.response.userHandle) : '',
+    },
+  }), 'application/json');
+
+  window.location.replace(result.redirect);
+}
+
+async function register(form) {
+  const options = await post(form, form.action, '', 'application/x-www-form-urlencoded');
+
+  const publicKey = options.publicKey;
+  publicKey.challenge = toBuffer(publicKey.challenge);
+  publicKey.user.id = toBuffer(publicKey.user.id);
+  (publicKey.excludeCredentials || []).forEach((c) => {
+    c.id = toBuffer(c.id);
+  });
+
+  const credential = await navigator.credentials.create({ publicKey });
+
+  const name = encodeURIComponent(form.querySelector('input[name=name]').value);
+  await post(form, `${form.dataset.finalize}?name=${name}`, JSON.stringify({
+    id: credential.id,
+    rawId: fromBuffer(credential.rawId),
+    type: credential.type,
+    response: {
+      attestationObject: fromBuffer(credential.response.attestationObject),
+      clientDataJSON: fromBuffer(credential.response.clientDataJSON),
+    },
+  }), 'application/json');
+
+  window.location.reload();
+}
+
+if (signInForm) run(signInForm, signIn);
+if (registerForm) run(registerForm, register);
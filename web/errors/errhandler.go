@@ -119,6 +119,18 @@ func localizeError(ih *i18n.Localizer, err error) (int, template.HTML) {
 		code = http.StatusNotFound
 		msg = ih.LocalizeSimple("ErrorNotFound")
 
+	case errors.Is(err, roomdb.ErrInviteRequestLimit):
+		code = http.StatusTooManyRequests
+		msg = ih.LocalizeSimple("ErrorInviteRequestLimit")
+
+	case errors.Is(err, roomdb.ErrOpenInviteLimit):
+		code = http.StatusTooManyRequests
+		msg = ih.LocalizeSimple("ErrorOpenInviteLimit")
+
+	case errors.Is(err, roomdb.ErrInviteQuota):
+		code = http.StatusTooManyRequests
+		msg = ih.LocalizeSimple("ErrorInviteQuota")
+
 	case errors.As(err, &aa):
 		msg = ih.LocalizeWithData("ErrorAlreadyAdded", "Feed", aa.Ref.String())
 
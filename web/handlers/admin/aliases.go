@@ -8,16 +8,18 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gorilla/csrf"
 	"go.mindeco.de/http/render"
 
+	refs "github.com/ssbc/go-ssb-refs"
 	"github.com/ssbc/go-ssb-room/v2/roomdb"
 	weberrors "github.com/ssbc/go-ssb-room/v2/web/errors"
 	"github.com/ssbc/go-ssb-room/v2/web/members"
 )
 
-// aliasesHandler implements the managment endpoints for aliases (list and revoke),
+// aliasesHandler implements the managment endpoints for aliases (list, revoke and transfer),
 // does light validation of the web arguments and passes them through to the roomdb.
 type aliasesHandler struct {
 	r *render.Renderer
@@ -104,3 +106,106 @@ func (h aliasesHandler) revoke(rw http.ResponseWriter, req *http.Request) {
 
 	h.flashes.AddMessage(rw, req, "AdminMemberDetailsAliasRevoked")
 }
+
+// transfer allows another member to take over the alias, once they sign a confirmation for it with their feed.
+// Members can hand over their own aliases, moderators and admins can do it for any alias.
+func (h aliasesHandler) transfer(rw http.ResponseWriter, req *http.Request) {
+	if !h.parsePost(rw, req) {
+		return
+	}
+
+	defer http.Redirect(rw, req, redirectToMembers, http.StatusSeeOther)
+
+	aliasEntry, err := h.ownOrElevated(req)
+	if err != nil {
+		h.flashes.AddError(rw, req, err)
+		return
+	}
+
+	newOwner, err := refs.ParseFeedRef(strings.TrimSpace(req.FormValue("new_owner")))
+	if err != nil {
+		h.flashes.AddError(rw, req, weberrors.ErrBadRequest{Where: "Public Key", Details: err})
+		return
+	}
+
+	if aliasEntry.Feed.Equal(newOwner) {
+		h.flashes.AddError(rw, req, weberrors.ErrBadRequest{Where: "Public Key", Details: fmt.Errorf("the alias already belongs to that feed")})
+		return
+	}
+
+	err = h.db.AuthorizeTransfer(req.Context(), aliasEntry.Name, newOwner)
+	if err != nil {
+		h.flashes.AddError(rw, req, err)
+		return
+	}
+
+	h.flashes.AddMessage(rw, req, "AdminMemberDetailsAliasTransferAuthorized")
+}
+
+// cancelTransfer removes a pending transfer, with the same permissions as transfer
+func (h aliasesHandler) cancelTransfer(rw http.ResponseWriter, req *http.Request) {
+	if !h.parsePost(rw, req) {
+		return
+	}
+
+	defer http.Redirect(rw, req, redirectToMembers, http.StatusSeeOther)
+
+	aliasEntry, err := h.ownOrElevated(req)
+	if err != nil {
+		h.flashes.AddError(rw, req, err)
+		return
+	}
+
+	err = h.db.CancelTransfer(req.Context(), aliasEntry.Name)
+	if err != nil {
+		h.flashes.AddError(rw, req, err)
+		return
+	}
+
+	h.flashes.AddMessage(rw, req, "AdminMemberDetailsAliasTransferCanceled")
+}
+
+// parsePost renders an error and returns false if the request isn't a POST with valid form data
+func (h aliasesHandler) parsePost(rw http.ResponseWriter, req *http.Request) bool {
+	if req.Method != "POST" {
+		err := weberrors.ErrBadRequest{Where: "HTTP Method", Details: fmt.Errorf("expected POST request")}
+		h.r.Error(rw, req, http.StatusMethodNotAllowed, err)
+		return false
+	}
+
+	err := req.ParseForm()
+	if err != nil {
+		err = weberrors.ErrRedirect{
+			Path:   redirectToMembers,
+			Reason: weberrors.ErrBadRequest{Where: "Form data", Details: err},
+		}
+		h.r.Error(rw, req, http.StatusBadRequest, err)
+		return false
+	}
+
+	return true
+}
+
+// ownOrElevated resolves the alias from the name field of the form.
+// It returns an error unless the alias belongs to the current member or they are a moderator or admin.
+func (h aliasesHandler) ownOrElevated(req *http.Request) (roomdb.Alias, error) {
+	ctx := req.Context()
+
+	aliasEntry, err := h.db.Resolve(ctx, req.FormValue("name"))
+	if err != nil {
+		return roomdb.Alias{}, err
+	}
+
+	// who is doing this request
+	currentMember := members.FromContext(ctx)
+	if currentMember == nil {
+		return roomdb.Alias{}, weberrors.ErrForbidden{Details: fmt.Errorf("not an member")}
+	}
+
+	// ensure own alias or moderator
+	if !aliasEntry.Feed.Equal(currentMember.PubKey) && currentMember.Role == roomdb.RoleMember {
+		return roomdb.Alias{}, weberrors.ErrForbidden{Details: fmt.Errorf("not your alias or not a moderator")}
+	}
+
+	return aliasEntry, nil
+}
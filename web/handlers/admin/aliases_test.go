@@ -87,3 +87,77 @@ func TestAliasesRevoke(t *testing.T) {
 
 	webassert.HasFlashMessages(t, ts.Client, overviewURL, "ErrorNotFound")
 }
+
+func TestAliasesTransfer(t *testing.T) {
+	ts := newSession(t)
+	a := assert.New(t)
+
+	urlTransfer := ts.URLTo(router.AdminAliasesTransfer)
+	urlCancel := ts.URLTo(router.AdminAliasesTransferCancel)
+	overviewURL := ts.URLTo(router.AdminMembersOverview)
+
+	otherKey, err := refs.ParseFeedRef("@x7iOLUcq3o+sjGeAnipvWeGzfuYgrXl8L4LYlxIhwDc=.ed25519")
+	a.NoError(err)
+	newOwner, err := refs.ParseFeedRef("@Rt2aJrtOqWXhBZ5/vlfzeWQ9Bj/z6iT8CMhlr2WWlG4=.ed25519")
+	a.NoError(err)
+
+	// somebody elses alias
+	aliasEntry := roomdb.Alias{
+		ID:   23,
+		Feed: otherKey,
+		Name: "moving",
+	}
+	ts.AliasesDB.ResolveReturns(aliasEntry, nil)
+
+	// moderators can hand over any alias
+	vals := url.Values{
+		"name":      []string{"moving"},
+		"new_owner": []string{newOwner.String()},
+	}
+	rec := ts.Client.PostForm(urlTransfer, vals)
+	a.Equal(http.StatusSeeOther, rec.Code)
+	a.Equal(overviewURL.Path, rec.Header().Get("Location"))
+
+	webassert.HasFlashMessages(t, ts.Client, overviewURL, "AdminMemberDetailsAliasTransferAuthorized")
+
+	a.Equal(1, ts.AliasesDB.AuthorizeTransferCallCount())
+	_, name, to := ts.AliasesDB.AuthorizeTransferArgsForCall(0)
+	a.Equal("moving", name)
+	a.True(to.Equal(newOwner))
+
+	// the new owner needs to be a valid feed
+	vals.Set("new_owner", "nope")
+	rec = ts.Client.PostForm(urlTransfer, vals)
+	a.Equal(http.StatusSeeOther, rec.Code)
+	webassert.HasFlashMessages(t, ts.Client, overviewURL, "ErrorBadRequest")
+	a.Equal(1, ts.AliasesDB.AuthorizeTransferCallCount())
+
+	// members can't hand over the aliases of others
+	ts.User = roomdb.Member{
+		ID:     7331,
+		Role:   roomdb.RoleMember,
+		PubKey: newOwner,
+	}
+
+	vals.Set("new_owner", newOwner.String())
+	rec = ts.Client.PostForm(urlTransfer, vals)
+	a.Equal(http.StatusSeeOther, rec.Code)
+	webassert.HasFlashMessages(t, ts.Client, overviewURL, "ErrorForbidden")
+	a.Equal(1, ts.AliasesDB.AuthorizeTransferCallCount())
+
+	rec = ts.Client.PostForm(urlCancel, url.Values{"name": []string{"moving"}})
+	a.Equal(http.StatusSeeOther, rec.Code)
+	webassert.HasFlashMessages(t, ts.Client, overviewURL, "ErrorForbidden")
+	a.Equal(0, ts.AliasesDB.CancelTransferCallCount())
+
+	// but their own
+	ts.User.PubKey = otherKey
+
+	rec = ts.Client.PostForm(urlCancel, url.Values{"name": []string{"moving"}})
+	a.Equal(http.StatusSeeOther, rec.Code)
+	webassert.HasFlashMessages(t, ts.Client, overviewURL, "AdminMemberDetailsAliasTransferCanceled")
+
+	a.Equal(1, ts.AliasesDB.CancelTransferCallCount())
+	_, name = ts.AliasesDB.CancelTransferArgsForCall(0)
+	a.Equal("moving", name)
+}
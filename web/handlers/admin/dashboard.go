@@ -72,6 +72,8 @@ func (h dashboardHandler) overview(w http.ResponseWriter, req *http.Request) (in
 			onlineUsers[i].PubKey = ref
 			onlineUsers[i].Role = roomdb.RoleUnknown
 		}
+
+		onlineUsers[i].Latency, onlineUsers[i].HasLatency = h.roomState.Latency(ref)
 	}
 
 	memberCount, err := h.dbs.Members.Count(ctx)
@@ -96,6 +98,7 @@ func (h dashboardHandler) overview(w http.ResponseWriter, req *http.Request) (in
 		"MemberCount": memberCount,
 		"InviteCount": inviteCount,
 		"DeniedCount": deniedCount,
+		"Channels":    h.roomState.ChannelCounts(),
 	}
 
 	pageData["Flashes"], err = h.flashes.GetAll(w, req)
@@ -109,6 +112,15 @@ func (h dashboardHandler) overview(w http.ResponseWriter, req *http.Request) (in
 // connectedUser defines how we want to present a connected user
 type connectedUser struct {
 	roomdb.Member
+
+	// Latency is the round-trip time of the last heartbeat probe, if there was one yet
+	Latency    time.Duration
+	HasLatency bool
+}
+
+// LatencyMillis returns the latency in whole milliseconds, for display
+func (dm connectedUser) LatencyMillis() int64 {
+	return dm.Latency.Milliseconds()
 }
 
 // if the member has an alias, use the first one. Otherwise use the public key
@@ -8,13 +8,18 @@ import (
 	"bytes"
 	"context"
 	"net/http"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/ssbc/go-muxrpc/v2"
 	refs "github.com/ssbc/go-ssb-refs"
 	"github.com/ssbc/go-ssb-room/v2/roomdb"
 	"github.com/ssbc/go-ssb-room/v2/web/router"
 	"github.com/ssbc/go-ssb-room/v2/web/webassert"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDashboardSimple(t *testing.T) {
@@ -93,3 +98,87 @@ func TestDashboardWithVisitors(t *testing.T) {
 	wantLink := ts.URLTo(router.AdminMemberDetails, "id", 23)
 	a.Equal(wantLink.String(), gotLink)
 }
+
+func TestDashboardChannels(t *testing.T) {
+	ts := newSession(t)
+	a := assert.New(t)
+
+	var peers []refs.FeedRef
+	for i := byte(0); i < 3; i++ {
+		ref, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte{i}, 32), refs.RefAlgoFeedSSB1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ts.RoomState.AddEndpoint(ref, nil)
+		peers = append(peers, ref)
+	}
+
+	a.NoError(ts.RoomState.JoinChannel("dev", peers[0]))
+	a.NoError(ts.RoomState.JoinChannel("dev", peers[1]))
+	a.NoError(ts.RoomState.JoinChannel("local-berlin", peers[2]))
+
+	dashURL := ts.URLTo(router.AdminDashboard)
+
+	html, resp := ts.Client.GetHTML(dashURL)
+	a.Equal(http.StatusOK, resp.Code, "wrong HTTP status code")
+
+	channels := html.Find("#channel-list li")
+	a.Equal(2, channels.Length())
+
+	a.Equal("dev", channels.Eq(0).Find(".channel-name").Text())
+	a.Equal("2", channels.Eq(0).Find(".channel-count").Text())
+	a.Equal("local-berlin", channels.Eq(1).Find(".channel-name").Text())
+	a.Equal("1", channels.Eq(1).Find(".channel-count").Text())
+
+	// leaving the room also leaves the channel
+	ts.RoomState.Remove(peers[2])
+
+	html, resp = ts.Client.GetHTML(dashURL)
+	a.Equal(http.StatusOK, resp.Code, "wrong HTTP status code")
+	a.Equal(1, html.Find("#channel-list li").Length())
+}
+
+func TestDashboardLatency(t *testing.T) {
+	ts := newSession(t)
+	a := assert.New(t)
+	r := require.New(t)
+
+	probedRef, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte{0}, 32), refs.RefAlgoFeedSSB1)
+	r.NoError(err)
+	newRef, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte{1}, 32), refs.RefAlgoFeedSSB1)
+	r.NoError(err)
+
+	// replies to the heartbeat probes after a bit
+	edp := new(muxrpc.FakeEndpoint)
+	edp.AsyncCalls(func(context.Context, interface{}, muxrpc.RequestEncoding, muxrpc.Method, ...interface{}) error {
+		time.Sleep(25 * time.Millisecond)
+		return nil
+	})
+	ts.RoomState.AddEndpoint(probedRef, edp)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go ts.RoomState.RunHeartbeat(ctx, 100*time.Millisecond, 3)
+
+	r.Eventually(func() bool {
+		_, has := ts.RoomState.Latency(probedRef)
+		return has
+	}, 2*time.Second, 10*time.Millisecond, "should have probed the attendant")
+	cancel()
+
+	// not probed yet
+	ts.RoomState.AddEndpoint(newRef, nil)
+
+	ts.MembersDB.GetByFeedReturns(roomdb.Member{}, roomdb.ErrNotFound)
+
+	html, resp := ts.Client.GetHTML(ts.URLTo(router.AdminDashboard))
+	a.Equal(http.StatusOK, resp.Code, "wrong HTTP status code")
+
+	latencies := html.Find("#connected-list .latency")
+	r.Equal(1, latencies.Length(), "only the probed attendant has a latency")
+
+	millis, err := strconv.Atoi(strings.TrimSuffix(latencies.Text(), " ms"))
+	r.NoError(err, "not a number of milliseconds: %q", latencies.Text())
+	a.GreaterOrEqual(millis, 25)
+	a.Less(millis, 2000)
+}
@@ -39,12 +39,24 @@ var HTMLTemplates = []string{
 	"admin/denied-keys.tmpl",
 	"admin/denied-keys-remove-confirm.tmpl",
 
+	"admin/lockouts.tmpl",
+
 	"admin/invite-list.tmpl",
 	"admin/invite-revoke-confirm.tmpl",
 	"admin/invite-created.tmpl",
+	"admin/invite-tree.tmpl",
+	"admin/invite-tree-suspend-confirm.tmpl",
+	"admin/invite-requests.tmpl",
+	"admin/invite-batches.tmpl",
+	"admin/invite-batch-created.tmpl",
+	"admin/invite-batch-revoke-confirm.tmpl",
 
 	"admin/notice-edit.tmpl",
 
+	"admin/oidc-clients.tmpl",
+	"admin/oidc-client-created.tmpl",
+	"admin/oidc-clients-remove-confirm.tmpl",
+
 	"admin/member.tmpl",
 	"admin/member-list.tmpl",
 	"admin/members-remove-confirm.tmpl",
@@ -53,14 +65,20 @@ var HTMLTemplates = []string{
 
 // Databases is an option struct that encapsulates the required database services
 type Databases struct {
-	Aliases       roomdb.AliasesService
-	AuthFallback  roomdb.AuthFallbackService
-	Config        roomdb.RoomConfig
-	DeniedKeys    roomdb.DeniedKeysService
-	Invites       roomdb.InvitesService
-	Notices       roomdb.NoticesService
-	Members       roomdb.MembersService
-	PinnedNotices roomdb.PinnedNoticesService
+	Aliases        roomdb.AliasesService
+	APITokens      roomdb.APITokensService
+	AuthFallback   roomdb.AuthFallbackService
+	AuthWithSSB    roomdb.AuthWithSSBService
+	Config         roomdb.RoomConfig
+	DeniedKeys     roomdb.DeniedKeysService
+	Invites        roomdb.InvitesService
+	InviteRequests roomdb.InviteRequestsService
+	LoginLockouts  roomdb.LoginLockoutService
+	Notices        roomdb.NoticesService
+	Members        roomdb.MembersService
+	OIDCClients    roomdb.OIDCClientsService
+	PinnedNotices  roomdb.PinnedNoticesService
+	WebSessions    roomdb.WebSessionsService
 }
 
 // Handler supplies the elevated access pages to known users.
@@ -71,6 +89,7 @@ func Handler(
 	roomState *roomstate.Manager,
 	fh *weberrors.FlashHelper,
 	locHelper *i18n.Helper,
+	secrets *web.Secrets,
 	dbs Databases,
 ) http.Handler {
 	mux := &http.ServeMux{}
@@ -88,14 +107,22 @@ func Handler(
 	mux.HandleFunc("/dashboard", r.HTML("admin/dashboard.tmpl", dashboardHandler.overview))
 
 	var sh = settingsHandler{
-		r:     r,
-		urlTo: urlTo,
-		db:    dbs.Config,
-		loc:   locHelper,
+		r:       r,
+		urlTo:   urlTo,
+		flashes: fh,
+		db:      dbs.Config,
+		aliases: dbs.Aliases,
+		loc:     locHelper,
+		secrets: secrets,
 	}
 	mux.HandleFunc("/settings", r.HTML("admin/settings.tmpl", sh.overview))
 	mux.HandleFunc("/settings/set-privacy", sh.setPrivacy)
 	mux.HandleFunc("/settings/set-language", sh.setLanguage)
+	mux.HandleFunc("/settings/set-totp-mandatory", sh.setTOTPMandatory)
+	mux.HandleFunc("/settings/rotate-secrets", sh.rotateSecrets)
+	mux.HandleFunc("/settings/set-open-invites", sh.setOpenInviteLimits)
+	mux.HandleFunc("/settings/set-member-invites", sh.setMemberInviteQuota)
+	mux.HandleFunc("/settings/set-alias-policy", sh.setAliasPolicy)
 
 	mux.HandleFunc("/menu", r.HTML("admin/menu.tmpl", func(w http.ResponseWriter, req *http.Request) (interface{}, error) {
 		return map[string]interface{}{}, nil
@@ -109,6 +136,8 @@ func Handler(
 	}
 	mux.HandleFunc("/aliases/revoke/confirm", r.HTML("admin/aliases-revoke-confirm.tmpl", ah.revokeConfirm))
 	mux.HandleFunc("/aliases/revoke", ah.revoke)
+	mux.HandleFunc("/aliases/transfer", ah.transfer)
+	mux.HandleFunc("/aliases/transfer/cancel", ah.cancelTransfer)
 
 	var dh = deniedKeysHandler{
 		r:       r,
@@ -123,6 +152,16 @@ func Handler(
 	mux.HandleFunc("/denied/remove/confirm", r.HTML("admin/denied-keys-remove-confirm.tmpl", dh.removeConfirm))
 	mux.HandleFunc("/denied/remove", dh.remove)
 
+	var lh = lockoutsHandler{
+		r:       r,
+		flashes: fh,
+
+		db:      dbs.LoginLockouts,
+		roomCfg: dbs.Config,
+	}
+	mux.HandleFunc("/lockouts", r.HTML("admin/lockouts.tmpl", lh.overview))
+	mux.HandleFunc("/lockouts/clear", lh.clear)
+
 	var mh = membersHandler{
 		r:       r,
 		flashes: fh,
@@ -131,13 +170,16 @@ func Handler(
 
 		db: dbs.Members,
 
+		invitesDB:      dbs.Invites,
 		fallbackAuthDB: dbs.AuthFallback,
+		sessionsDB:     dbs.AuthWithSSB,
 		roomCfgDB:      dbs.Config,
 	}
 	mux.HandleFunc("/member", r.HTML("admin/member.tmpl", mh.details))
 	mux.HandleFunc("/members", r.HTML("admin/member-list.tmpl", mh.overview))
 	mux.HandleFunc("/members/add", mh.add)
 	mux.HandleFunc("/members/change-role", mh.changeRole)
+	mux.HandleFunc("/members/remove-session", mh.removeSession)
 	mux.HandleFunc("/members/remove/confirm", r.HTML("admin/members-remove-confirm.tmpl", mh.removeConfirm))
 	mux.HandleFunc("/members/remove", mh.remove)
 	mux.HandleFunc("/members/create-fallback-reset-link", r.HTML("admin/members-show-password-reset-token.tmpl", mh.createPasswordResetToken))
@@ -147,8 +189,9 @@ func Handler(
 		flashes: fh,
 		urlTo:   urlTo,
 
-		db:     dbs.Invites,
-		config: dbs.Config,
+		db:         dbs.Invites,
+		requestsDB: dbs.InviteRequests,
+		config:     dbs.Config,
 	}
 
 	mux.HandleFunc("/invites", r.HTML("admin/invite-list.tmpl", ih.overview))
@@ -156,6 +199,48 @@ func Handler(
 	mux.HandleFunc("/invites/revoke/confirm", r.HTML("admin/invite-revoke-confirm.tmpl", ih.revokeConfirm))
 	mux.HandleFunc("/invites/revoke", ih.revoke)
 
+	var ith = inviteTreeHandler{
+		r:       r,
+		flashes: fh,
+		urlTo:   urlTo,
+
+		invitesDB:     dbs.Invites,
+		membersDB:     dbs.Members,
+		deniedKeysDB:  dbs.DeniedKeys,
+		authWithSSBDB: dbs.AuthWithSSB,
+		webSessionsDB: dbs.WebSessions,
+		apiTokensDB:   dbs.APITokens,
+		roomCfg:       dbs.Config,
+	}
+	mux.HandleFunc("/invites/tree", r.HTML("admin/invite-tree.tmpl", ith.overview))
+	mux.HandleFunc("/invites/tree/suspend/confirm", r.HTML("admin/invite-tree-suspend-confirm.tmpl", ith.suspendConfirm))
+	mux.HandleFunc("/invites/tree/suspend", ith.suspend)
+
+	var irh = inviteRequestsHandler{
+		r:       r,
+		flashes: fh,
+		urlTo:   urlTo,
+
+		requestsDB: dbs.InviteRequests,
+		roomCfg:    dbs.Config,
+	}
+	mux.HandleFunc("/invites/requests", r.HTML("admin/invite-requests.tmpl", irh.overview))
+	mux.HandleFunc("/invites/requests/approve", irh.approve)
+	mux.HandleFunc("/invites/requests/reject", irh.reject)
+
+	var ibh = inviteBatchesHandler{
+		r:       r,
+		flashes: fh,
+		urlTo:   urlTo,
+
+		db:      dbs.Invites,
+		roomCfg: dbs.Config,
+	}
+	mux.HandleFunc("/invites/batches", r.HTML("admin/invite-batches.tmpl", ibh.overview))
+	mux.HandleFunc("/invites/batches/create", ibh.create)
+	mux.HandleFunc("/invites/batches/revoke/confirm", r.HTML("admin/invite-batch-revoke-confirm.tmpl", ibh.revokeConfirm))
+	mux.HandleFunc("/invites/batches/revoke", ibh.revoke)
+
 	var nh = noticeHandler{
 		r:       r,
 		urlTo:   urlTo,
@@ -170,6 +255,19 @@ func Handler(
 	mux.Handle("/notice/translation/add", http.HandlerFunc(nh.addTranslation))
 	mux.Handle("/notice/save", http.HandlerFunc(nh.save))
 
+	var oh = oidcClientsHandler{
+		r:       r,
+		flashes: fh,
+		urlTo:   urlTo,
+
+		db:      dbs.OIDCClients,
+		roomCfg: dbs.Config,
+	}
+	mux.HandleFunc("/oidc-clients", r.HTML("admin/oidc-clients.tmpl", oh.overview))
+	mux.HandleFunc("/oidc-clients/add", r.HTML("admin/oidc-client-created.tmpl", oh.add))
+	mux.HandleFunc("/oidc-clients/remove/confirm", r.HTML("admin/oidc-clients-remove-confirm.tmpl", oh.removeConfirm))
+	mux.HandleFunc("/oidc-clients/remove", oh.remove)
+
 	// path:/ matches everything that isn't registerd (ie. its the "Not Found handler")
 	mux.HandleFunc("/", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		r.Error(rw, req, 404, weberrors.PageNotFound{Path: req.URL.Path})
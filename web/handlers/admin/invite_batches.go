@@ -0,0 +1,248 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/csrf"
+	"go.mindeco.de/http/render"
+	"go.mindeco.de/log/level"
+	"go.mindeco.de/logging"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web"
+	weberrors "github.com/ssbc/go-ssb-room/v2/web/errors"
+	"github.com/ssbc/go-ssb-room/v2/web/members"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+)
+
+const (
+	// how long the invites of a batch can be used, in days
+	inviteBatchDefaultDays = 30
+	inviteBatchMaxDays     = 365
+
+	inviteBatchNameMaxLength = 100
+)
+
+// inviteBatchesHandler lets admins create many invites at once, for workshops and meetups.
+// Like with single invites the tokens are only known when the batch is created,
+// so the printable sheet or the CSV file is the answer to the create request.
+type inviteBatchesHandler struct {
+	r       *render.Renderer
+	flashes *weberrors.FlashHelper
+	urlTo   web.URLMaker
+
+	db      roomdb.InvitesService
+	roomCfg roomdb.RoomConfig
+}
+
+// batchInvite is one invite on the printable sheet
+type batchInvite struct {
+	FacadeURL string
+	QRCodeURI template.URL
+}
+
+func (h inviteBatchesHandler) overview(rw http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if _, err := members.CheckAllowed(req.Context(), h.roomCfg, members.ActionInviteBatches); err != nil {
+		return nil, err
+	}
+
+	lst, err := h.db.ListBatches(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	pageData, err := paginate(lst, len(lst), req.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	pageData[csrf.TemplateTag] = csrf.TemplateField(req)
+	pageData["MaxSize"] = roomdb.InviteBatchMaxSize
+	pageData["DefaultDays"] = inviteBatchDefaultDays
+	pageData["MaxDays"] = inviteBatchMaxDays
+	pageData["NameMaxLength"] = inviteBatchNameMaxLength
+
+	pageData["Flashes"], err = h.flashes.GetAll(rw, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return pageData, nil
+}
+
+// create makes the batch and answers with its invites, as a printable sheet or as a CSV file if format is csv.
+// Errors are shown on the overview of the batches.
+func (h inviteBatchesHandler) create(rw http.ResponseWriter, req *http.Request) {
+	overviewURL := h.urlTo(router.AdminInviteBatches).Path
+
+	if req.Method != "POST" {
+		err := weberrors.ErrBadRequest{Where: "HTTP Method", Details: fmt.Errorf("expected POST not %s", req.Method)}
+		h.r.Error(rw, req, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := req.ParseForm(); err != nil {
+		err = weberrors.ErrBadRequest{Where: "Form data", Details: err}
+		h.r.Error(rw, req, http.StatusBadRequest, weberrors.ErrRedirect{Path: overviewURL, Reason: err})
+		return
+	}
+
+	ctx := req.Context()
+
+	admin, err := members.CheckAllowed(ctx, h.roomCfg, members.ActionInviteBatches)
+	if err != nil {
+		h.r.Error(rw, req, http.StatusForbidden, weberrors.ErrRedirect{Path: overviewURL, Reason: err})
+		return
+	}
+
+	name := strings.TrimSpace(req.FormValue("name"))
+	if name == "" || len(name) > inviteBatchNameMaxLength {
+		err = weberrors.ErrBadRequest{Where: "name", Details: fmt.Errorf("needs to be between 1 and %d bytes long", inviteBatchNameMaxLength)}
+		h.r.Error(rw, req, http.StatusBadRequest, weberrors.ErrRedirect{Path: overviewURL, Reason: err})
+		return
+	}
+
+	count, err := strconv.ParseUint(req.FormValue("count"), 10, 32)
+	if err != nil || count == 0 || count > roomdb.InviteBatchMaxSize {
+		err = weberrors.ErrBadRequest{Where: "count", Details: fmt.Errorf("needs to be between 1 and %d", roomdb.InviteBatchMaxSize)}
+		h.r.Error(rw, req, http.StatusBadRequest, weberrors.ErrRedirect{Path: overviewURL, Reason: err})
+		return
+	}
+
+	days, err := strconv.ParseUint(req.FormValue("valid_days"), 10, 32)
+	if err != nil || days == 0 || days > inviteBatchMaxDays {
+		err = weberrors.ErrBadRequest{Where: "valid_days", Details: fmt.Errorf("needs to be between 1 and %d", inviteBatchMaxDays)}
+		h.r.Error(rw, req, http.StatusBadRequest, weberrors.ErrRedirect{Path: overviewURL, Reason: err})
+		return
+	}
+
+	expiresAt := time.Now().Add(time.Duration(days) * 24 * time.Hour)
+
+	batch, tokens, err := h.db.CreateBatch(ctx, admin.ID, name, uint(count), expiresAt)
+	if err != nil {
+		h.r.Error(rw, req, http.StatusInternalServerError, weberrors.ErrRedirect{Path: overviewURL, Reason: err})
+		return
+	}
+
+	logger := logging.FromContext(ctx)
+	level.Info(logger).Log("event", "invite batch created", "batch", batch.ID, "count", len(tokens))
+
+	facadeURLs := make([]string, len(tokens))
+	for i, tok := range tokens {
+		facadeURLs[i] = h.urlTo(router.CompleteInviteFacade, "token", tok).String()
+	}
+
+	if req.FormValue("format") == "csv" {
+		rw.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		rw.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, batchFileName(batch)))
+
+		expires := batch.ExpiresAt.UTC().Format(time.RFC3339)
+
+		w := csv.NewWriter(rw)
+		w.Write([]string{"url", "token", "expires"})
+		for i, tok := range tokens {
+			w.Write([]string{facadeURLs[i], tok, expires})
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			level.Warn(logger).Log("event", "sending invite batch csv failed", "err", err)
+		}
+		return
+	}
+
+	invites := make([]batchInvite, len(tokens))
+	for i, u := range facadeURLs {
+		qrURI, err := web.QRCodeURI(u)
+		if err != nil {
+			h.r.Error(rw, req, http.StatusInternalServerError, err)
+			return
+		}
+		invites[i] = batchInvite{FacadeURL: u, QRCodeURI: qrURI}
+	}
+
+	err = h.r.Render(rw, req, "admin/invite-batch-created.tmpl", http.StatusOK, map[string]interface{}{
+		"Batch":   batch,
+		"Invites": invites,
+	})
+	if err != nil {
+		level.Warn(logger).Log("event", "rendering invite batch failed", "err", err)
+	}
+}
+
+var batchFileNameUnsafe = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// batchFileName turns the name of the batch into something that is safe to use as a file name
+func batchFileName(batch roomdb.InviteBatch) string {
+	name := strings.Trim(batchFileNameUnsafe.ReplaceAllString(batch.Name, "-"), "-")
+	if name == "" {
+		return fmt.Sprintf("invites-%d", batch.ID)
+	}
+	return "invites-" + name
+}
+
+func (h inviteBatchesHandler) revokeConfirm(rw http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if _, err := members.CheckAllowed(req.Context(), h.roomCfg, members.ActionInviteBatches); err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.ParseInt(req.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		return nil, weberrors.ErrBadRequest{Where: "ID", Details: err}
+	}
+
+	batch, err := h.db.GetBatch(req.Context(), id)
+	if err != nil {
+		if errors.Is(err, roomdb.ErrNotFound) {
+			return nil, weberrors.ErrNotFound{What: "invite batch"}
+		}
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"Batch":          batch,
+		csrf.TemplateTag: csrf.TemplateField(req),
+	}, nil
+}
+
+func (h inviteBatchesHandler) revoke(rw http.ResponseWriter, req *http.Request) {
+	// always redirect
+	defer http.Redirect(rw, req, h.urlTo(router.AdminInviteBatches).Path, http.StatusSeeOther)
+
+	ctx := req.Context()
+
+	if _, err := members.CheckAllowed(ctx, h.roomCfg, members.ActionInviteBatches); err != nil {
+		h.flashes.AddError(rw, req, err)
+		return
+	}
+
+	if err := req.ParseForm(); err != nil {
+		h.flashes.AddError(rw, req, weberrors.ErrBadRequest{Where: "Form data", Details: err})
+		return
+	}
+
+	id, err := strconv.ParseInt(req.FormValue("id"), 10, 64)
+	if err != nil {
+		h.flashes.AddError(rw, req, weberrors.ErrBadRequest{Where: "ID", Details: err})
+		return
+	}
+
+	err = h.db.RevokeBatch(ctx, id)
+	if err != nil {
+		h.flashes.AddError(rw, req, err)
+		return
+	}
+
+	h.flashes.AddMessage(rw, req, "AdminInviteBatchRevoked")
+}
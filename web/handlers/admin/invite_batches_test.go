@@ -0,0 +1,221 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"encoding/csv"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+	"github.com/ssbc/go-ssb-room/v2/web/webassert"
+)
+
+func TestInviteBatchesOverview(t *testing.T) {
+	ts := newSession(t)
+	a := assert.New(t)
+
+	overviewURL := ts.URLTo(router.AdminInviteBatches)
+
+	// only admins can create batches
+	_, resp := ts.Client.GetHTML(overviewURL)
+	a.Equal(http.StatusForbidden, resp.Code)
+
+	html, _ := ts.Client.GetHTML(ts.URLTo(router.AdminInvitesOverview))
+	a.Equal(0, html.Find("#invite-batches-link").Length())
+
+	ts.User.Role = roomdb.RoleAdmin
+
+	html, _ = ts.Client.GetHTML(ts.URLTo(router.AdminInvitesOverview))
+	href, ok := html.Find("#invite-batches-link").Attr("href")
+	a.True(ok)
+	a.Equal(overviewURL.String(), href)
+
+	html, resp = ts.Client.GetHTML(overviewURL)
+	a.Equal(http.StatusOK, resp.Code, "wrong HTTP status code")
+
+	webassert.Localized(t, html, []webassert.LocalizedElement{
+		{"title", "AdminInviteBatchesTitle"},
+		{"#welcome", "AdminInviteBatchesWelcome"},
+		{"#no-entries", "AdminInviteBatchesEmpty"},
+	})
+
+	ts.InvitesDB.ListBatchesReturns([]roomdb.InviteBatch{
+		{ID: 2, Name: "meetup", ExpiresAt: time.Now().Add(time.Hour), Unused: 1},
+		{ID: 1, Name: "workshop", ExpiresAt: time.Now().Add(time.Hour), Unused: 12},
+	}, nil)
+
+	html, resp = ts.Client.GetHTML(overviewURL)
+	a.Equal(http.StatusOK, resp.Code, "wrong HTTP status code")
+
+	entries := html.Find("#the-list li")
+	a.Equal(2, entries.Length())
+	a.Equal("workshop", entries.Eq(1).Find(".batch-name").Text())
+	a.Contains(entries.Eq(1).Text(), "AdminInviteBatchesUnusedPlural")
+
+	revokeLink, ok := entries.Eq(1).Find(".revoke-batch").Attr("href")
+	a.True(ok)
+	wantLink := ts.URLTo(router.AdminInviteBatchesRevokeConfirm, "id", 1)
+	a.Equal(wantLink.String(), revokeLink)
+}
+
+func TestInviteBatchesCreate(t *testing.T) {
+	ts := newSession(t)
+	a, r := assert.New(t), require.New(t)
+
+	ts.User.Role = roomdb.RoleAdmin
+
+	createURL := ts.URLTo(router.AdminInviteBatchesCreate)
+	overviewURL := ts.URLTo(router.AdminInviteBatches)
+
+	expiresAt := time.Date(2021, 7, 1, 12, 0, 0, 0, time.UTC)
+	ts.InvitesDB.CreateBatchReturns(
+		roomdb.InviteBatch{ID: 3, Name: "ssb workshop", ExpiresAt: expiresAt, Unused: 2},
+		[]string{"token-one", "token-two"},
+		nil,
+	)
+
+	vals := url.Values{
+		"name":       []string{"ssb workshop"},
+		"count":      []string{"2"},
+		"valid_days": []string{"7"},
+		"format":     []string{"html"},
+	}
+
+	before := time.Now()
+	rec := ts.Client.PostForm(createURL, vals)
+	a.Equal(http.StatusOK, rec.Code)
+
+	r.Equal(1, ts.InvitesDB.CreateBatchCallCount())
+	_, createdBy, name, count, gotExpiry := ts.InvitesDB.CreateBatchArgsForCall(0)
+	a.Equal(ts.User.ID, createdBy)
+	a.Equal("ssb workshop", name)
+	a.EqualValues(2, count)
+	a.WithinDuration(before.Add(7*24*time.Hour), gotExpiry, time.Minute)
+
+	doc, err := goquery.NewDocumentFromReader(rec.Body)
+	r.NoError(err)
+
+	webassert.Localized(t, doc, []webassert.LocalizedElement{
+		{"title", "AdminInviteBatchCreatedTitle"},
+		{"#welcome", "AdminInviteBatchCreatedWelcome"},
+	})
+	a.Equal("ssb workshop", doc.Find("#batch-name").Text())
+
+	invites := doc.Find("#the-sheet .batch-invite")
+	r.Equal(2, invites.Length())
+	for i, tok := range []string{"token-one", "token-two"} {
+		inv := invites.Eq(i)
+		a.Equal(ts.URLTo(router.CompleteInviteFacade, "token", tok).String(), inv.Find(".facade-url").Text())
+
+		src, ok := inv.Find("img").Attr("src")
+		a.True(ok)
+		a.True(strings.HasPrefix(src, "data:image/png;base64,"), "not a qr code: %s", src)
+
+		a.Contains(inv.Text(), "2021-07-01")
+	}
+
+	// the same as CSV
+	vals.Set("format", "csv")
+	rec = ts.Client.PostForm(createURL, vals)
+	a.Equal(http.StatusOK, rec.Code)
+	a.Equal("text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+	a.Equal(`attachment; filename="invites-ssb-workshop.csv"`, rec.Header().Get("Content-Disposition"))
+
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	r.NoError(err)
+	r.Len(rows, 3)
+	a.Equal([]string{"url", "token", "expires"}, rows[0])
+	a.Equal([]string{
+		ts.URLTo(router.CompleteInviteFacade, "token", "token-two").String(),
+		"token-two",
+		"2021-07-01T12:00:00Z",
+	}, rows[2])
+
+	// invalid forms go back to the overview
+	for _, tc := range []struct {
+		field, value string
+	}{
+		{"name", " "},
+		{"count", "0"},
+		{"count", "101"},
+		{"valid_days", "nope"},
+		{"valid_days", "366"},
+	} {
+		bad := url.Values{}
+		for k, v := range vals {
+			bad[k] = v
+		}
+		bad.Set(tc.field, tc.value)
+
+		rec = ts.Client.PostForm(createURL, bad)
+		a.Equal(http.StatusSeeOther, rec.Code, "%s=%q", tc.field, tc.value)
+		a.Equal(overviewURL.Path, rec.Header().Get("Location"))
+		webassert.HasFlashMessages(t, ts.Client, overviewURL, "ErrorBadRequest")
+	}
+	a.Equal(2, ts.InvitesDB.CreateBatchCallCount())
+
+	// moderators can't
+	ts.User.Role = roomdb.RoleModerator
+	rec = ts.Client.PostForm(createURL, vals)
+	a.Equal(http.StatusSeeOther, rec.Code)
+	a.Equal(2, ts.InvitesDB.CreateBatchCallCount())
+}
+
+func TestInviteBatchesRevoke(t *testing.T) {
+	ts := newSession(t)
+	a, r := assert.New(t), require.New(t)
+
+	ts.User.Role = roomdb.RoleAdmin
+
+	ts.InvitesDB.GetBatchReturns(roomdb.InviteBatch{ID: 5, Name: "workshop", Unused: 4}, nil)
+
+	html, resp := ts.Client.GetHTML(ts.URLTo(router.AdminInviteBatchesRevokeConfirm, "id", 5))
+	a.Equal(http.StatusOK, resp.Code)
+
+	webassert.Localized(t, html, []webassert.LocalizedElement{
+		{"title", "AdminInviteBatchRevokeConfirmTitle"},
+		{"#welcome", "AdminInviteBatchRevokeConfirmWelcomePlural"},
+	})
+
+	r.Equal(1, ts.InvitesDB.GetBatchCallCount())
+	_, id := ts.InvitesDB.GetBatchArgsForCall(0)
+	a.EqualValues(5, id)
+
+	id2, ok := html.Find("#confirm input[name=id]").Attr("value")
+	a.True(ok)
+	a.Equal("5", id2)
+
+	overviewURL := ts.URLTo(router.AdminInviteBatches)
+	revokeURL := ts.URLTo(router.AdminInviteBatchesRevoke)
+
+	rec := ts.Client.PostForm(revokeURL, url.Values{"id": []string{"5"}})
+	a.Equal(http.StatusSeeOther, rec.Code)
+	a.Equal(overviewURL.Path, rec.Header().Get("Location"))
+	webassert.HasFlashMessages(t, ts.Client, overviewURL, "AdminInviteBatchRevoked")
+
+	r.Equal(1, ts.InvitesDB.RevokeBatchCallCount())
+	_, id = ts.InvitesDB.RevokeBatchArgsForCall(0)
+	a.EqualValues(5, id)
+
+	ts.InvitesDB.RevokeBatchReturns(roomdb.ErrNotFound)
+	rec = ts.Client.PostForm(revokeURL, url.Values{"id": []string{"5"}})
+	a.Equal(http.StatusSeeOther, rec.Code)
+	webassert.HasFlashMessages(t, ts.Client, overviewURL, "ErrorNotFound")
+
+	// moderators can't
+	ts.User.Role = roomdb.RoleModerator
+	rec = ts.Client.PostForm(revokeURL, url.Values{"id": []string{"5"}})
+	a.Equal(http.StatusSeeOther, rec.Code)
+	a.Equal(2, ts.InvitesDB.RevokeBatchCallCount())
+}
@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/csrf"
+	"go.mindeco.de/http/render"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web"
+	weberrors "github.com/ssbc/go-ssb-room/v2/web/errors"
+	"github.com/ssbc/go-ssb-room/v2/web/members"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+)
+
+// inviteRequestsHandler lets moderators approve or reject the requests of people that want to join the room
+type inviteRequestsHandler struct {
+	r       *render.Renderer
+	flashes *weberrors.FlashHelper
+	urlTo   web.URLMaker
+
+	requestsDB roomdb.InviteRequestsService
+	roomCfg    roomdb.RoomConfig
+}
+
+func (h inviteRequestsHandler) overview(rw http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if _, err := members.CheckAllowed(req.Context(), h.roomCfg, members.ActionReviewInvites); err != nil {
+		return nil, err
+	}
+
+	lst, err := h.requestsDB.List(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	pageData, err := paginate(lst, len(lst), req.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	pageData[csrf.TemplateTag] = csrf.TemplateField(req)
+
+	pageData["Flashes"], err = h.flashes.GetAll(rw, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return pageData, nil
+}
+
+// approve creates an invite for the feed of the request, which the requester picks up from the status page of the request
+func (h inviteRequestsHandler) approve(rw http.ResponseWriter, req *http.Request) {
+	h.decide(rw, req, func(moderator *roomdb.Member, id int64) error {
+		_, err := h.requestsDB.Approve(req.Context(), id, moderator.ID)
+		return err
+	}, "AdminInviteRequestsApproved")
+}
+
+func (h inviteRequestsHandler) reject(rw http.ResponseWriter, req *http.Request) {
+	h.decide(rw, req, func(_ *roomdb.Member, id int64) error {
+		return h.requestsDB.Reject(req.Context(), id)
+	}, "AdminInviteRequestsRejected")
+}
+
+// decide checks the form and the role of the moderator before it calls fn, and goes back to the list afterwards
+func (h inviteRequestsHandler) decide(rw http.ResponseWriter, req *http.Request, fn func(*roomdb.Member, int64) error, successLabel string) {
+	if req.Method != "POST" {
+		err := weberrors.ErrBadRequest{Where: "HTTP Method", Details: fmt.Errorf("expected POST not %s", req.Method)}
+		h.r.Error(rw, req, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := req.ParseForm(); err != nil {
+		err = weberrors.ErrBadRequest{Where: "Form data", Details: err}
+		h.r.Error(rw, req, http.StatusBadRequest, err)
+		return
+	}
+
+	listURL := h.urlTo(router.AdminInviteRequests).String()
+	defer http.Redirect(rw, req, listURL, http.StatusSeeOther)
+
+	moderator, err := members.CheckAllowed(req.Context(), h.roomCfg, members.ActionReviewInvites)
+	if err != nil {
+		h.flashes.AddError(rw, req, err)
+		return
+	}
+
+	id, err := strconv.ParseInt(req.FormValue("id"), 10, 64)
+	if err != nil {
+		h.flashes.AddError(rw, req, weberrors.ErrBadRequest{Where: "ID", Details: err})
+		return
+	}
+
+	if err := fn(moderator, id); err != nil {
+		h.flashes.AddError(rw, req, err)
+		return
+	}
+
+	h.flashes.AddMessage(rw, req, successLabel)
+}
@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+	"github.com/ssbc/go-ssb-room/v2/web/webassert"
+)
+
+func TestInviteRequestsOverview(t *testing.T) {
+	ts := newSession(t)
+	a := assert.New(t)
+
+	listURL := ts.URLTo(router.AdminInviteRequests)
+
+	html, resp := ts.Client.GetHTML(listURL)
+	a.Equal(http.StatusOK, resp.Code, "wrong HTTP status code")
+
+	webassert.Localized(t, html, []webassert.LocalizedElement{
+		{"title", "AdminInviteRequestsTitle"},
+		{"#welcome", "AdminInviteRequestsWelcome"},
+		{"#no-entries", "AdminInviteRequestsEmpty"},
+	})
+
+	alf, err := generatePubKey()
+	a.NoError(err)
+	bob, err := generatePubKey()
+	a.NoError(err)
+
+	ts.RequestsDB.ListReturns([]roomdb.InviteRequest{
+		{ID: 1, PubKey: alf, Message: "hello, i'm alf", CreatedAt: time.Now().Add(-time.Hour)},
+		{ID: 2, PubKey: bob, CreatedAt: time.Now()},
+	}, nil)
+
+	html, resp = ts.Client.GetHTML(listURL)
+	a.Equal(http.StatusOK, resp.Code, "wrong HTTP status code")
+
+	entries := html.Find("#the-list li")
+	a.Equal(2, entries.Length())
+	a.Contains(entries.Eq(0).Text(), alf.String())
+	a.Equal("hello, i'm alf", entries.Eq(0).Find(".request-message").Text())
+	a.Equal(0, entries.Eq(1).Find(".request-message").Length())
+
+	approveAction, ok := entries.Eq(1).Find("form.approve-request").Attr("action")
+	a.True(ok)
+	a.Equal(ts.URLTo(router.AdminInviteRequestsApprove).String(), approveAction)
+	id, ok := entries.Eq(1).Find("form.approve-request input[name=id]").Attr("value")
+	a.True(ok)
+	a.Equal("2", id)
+
+	// the invites page links to the queue
+	html, resp = ts.Client.GetHTML(ts.URLTo(router.AdminInvitesOverview))
+	a.Equal(http.StatusOK, resp.Code, "wrong HTTP status code")
+	link := html.Find("#invite-requests-link")
+	href, ok := link.Attr("href")
+	a.True(ok)
+	a.Equal(listURL.String(), href)
+	a.Equal("AdminInviteRequestsPendingPlural", link.Text())
+
+	// members can't see the queue
+	ts.User.Role = roomdb.RoleMember
+	_, resp = ts.Client.GetHTML(listURL)
+	a.Equal(http.StatusForbidden, resp.Code)
+
+	html, resp = ts.Client.GetHTML(ts.URLTo(router.AdminInvitesOverview))
+	a.Equal(http.StatusOK, resp.Code, "wrong HTTP status code")
+	a.Equal(0, html.Find("#invite-requests-link").Length())
+}
+
+func TestInviteRequestsDecide(t *testing.T) {
+	ts := newSession(t)
+	a := assert.New(t)
+
+	listURL := ts.URLTo(router.AdminInviteRequests)
+
+	rec := ts.Client.PostForm(ts.URLTo(router.AdminInviteRequestsApprove), url.Values{"id": []string{"23"}})
+	a.Equal(http.StatusSeeOther, rec.Code)
+	a.Equal(listURL.String(), rec.Header().Get("Location"))
+	webassert.HasFlashMessages(t, ts.Client, listURL, "AdminInviteRequestsApproved")
+
+	a.Equal(1, ts.RequestsDB.ApproveCallCount())
+	_, id, decidedBy := ts.RequestsDB.ApproveArgsForCall(0)
+	a.EqualValues(23, id)
+	a.Equal(ts.User.ID, decidedBy)
+
+	rec = ts.Client.PostForm(ts.URLTo(router.AdminInviteRequestsReject), url.Values{"id": []string{"42"}})
+	a.Equal(http.StatusSeeOther, rec.Code)
+	webassert.HasFlashMessages(t, ts.Client, listURL, "AdminInviteRequestsRejected")
+
+	a.Equal(1, ts.RequestsDB.RejectCallCount())
+	_, id = ts.RequestsDB.RejectArgsForCall(0)
+	a.EqualValues(42, id)
+
+	// requests that were already decided
+	ts.RequestsDB.RejectReturns(roomdb.ErrNotFound)
+	rec = ts.Client.PostForm(ts.URLTo(router.AdminInviteRequestsReject), url.Values{"id": []string{"42"}})
+	a.Equal(http.StatusSeeOther, rec.Code)
+	webassert.HasFlashMessages(t, ts.Client, listURL, "ErrorNotFound")
+
+	rec = ts.Client.PostForm(ts.URLTo(router.AdminInviteRequestsReject), url.Values{"id": []string{"nope"}})
+	a.Equal(http.StatusSeeOther, rec.Code)
+	webassert.HasFlashMessages(t, ts.Client, listURL, "ErrorBadRequest")
+	a.Equal(2, ts.RequestsDB.RejectCallCount())
+
+	// members are not allowed to
+	ts.User.Role = roomdb.RoleMember
+	rec = ts.Client.PostForm(ts.URLTo(router.AdminInviteRequestsApprove), url.Values{"id": []string{"23"}})
+	a.Equal(http.StatusSeeOther, rec.Code)
+	a.Equal(1, ts.RequestsDB.ApproveCallCount())
+}
@@ -0,0 +1,229 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/csrf"
+	"go.mindeco.de/http/render"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web"
+	weberrors "github.com/ssbc/go-ssb-room/v2/web/errors"
+	"github.com/ssbc/go-ssb-room/v2/web/members"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+)
+
+// inviteTreeHandler shows who came into the room through a member,
+// and lets moderators suspend all of them if that member was compromised.
+type inviteTreeHandler struct {
+	r       *render.Renderer
+	flashes *weberrors.FlashHelper
+	urlTo   web.URLMaker
+
+	invitesDB     roomdb.InvitesService
+	membersDB     roomdb.MembersService
+	deniedKeysDB  roomdb.DeniedKeysService
+	authWithSSBDB roomdb.AuthWithSSBService
+	webSessionsDB roomdb.WebSessionsService
+	apiTokensDB   roomdb.APITokensService
+	roomCfg       roomdb.RoomConfig
+}
+
+// inviteTreeEntry is one member of the tree, Depth is 0 for those that the root member invited directly.
+type inviteTreeEntry struct {
+	roomdb.InviteUse
+
+	Depth  int
+	Denied bool
+}
+
+// load returns the root member and everyone that came in through them, in the order of the tree.
+func (h inviteTreeHandler) load(req *http.Request, id int64) (roomdb.Member, []inviteTreeEntry, error) {
+	ctx := req.Context()
+
+	root, err := h.membersDB.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, roomdb.ErrNotFound) {
+			return root, nil, weberrors.ErrNotFound{What: "member"}
+		}
+		return root, nil, err
+	}
+
+	uses, err := h.invitesDB.ListInvitedBy(ctx, root.ID, true)
+	if err != nil {
+		return root, nil, err
+	}
+
+	children := make(map[int64][]roomdb.InviteUse)
+	for _, u := range uses {
+		children[u.InvitedBy.ID] = append(children[u.InvitedBy.ID], u)
+	}
+
+	var (
+		entries []inviteTreeEntry
+		visited = map[int64]bool{root.ID: true}
+		walk    func(int64, int)
+	)
+	walk = func(memberID int64, depth int) {
+		for _, u := range children[memberID] {
+			// members that were removed and invited again show up twice
+			if visited[u.Member.ID] {
+				continue
+			}
+			visited[u.Member.ID] = true
+
+			entries = append(entries, inviteTreeEntry{
+				InviteUse: u,
+				Depth:     depth,
+				Denied:    h.deniedKeysDB.HasFeed(ctx, u.Member.PubKey),
+			})
+			walk(u.Member.ID, depth+1)
+		}
+	}
+	walk(root.ID, 0)
+
+	return root, entries, nil
+}
+
+func (h inviteTreeHandler) overview(rw http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if _, err := members.CheckAllowed(req.Context(), h.roomCfg, members.ActionReviewInvites); err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.ParseInt(req.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		return nil, weberrors.ErrBadRequest{Where: "ID", Details: err}
+	}
+
+	root, entries, err := h.load(req, id)
+	if err != nil {
+		return nil, err
+	}
+
+	pageData := map[string]interface{}{
+		"Root":           root,
+		"Entries":        entries,
+		csrf.TemplateTag: csrf.TemplateField(req),
+	}
+
+	pageData["Flashes"], err = h.flashes.GetAll(rw, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return pageData, nil
+}
+
+func (h inviteTreeHandler) suspendConfirm(rw http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if _, err := members.CheckAllowed(req.Context(), h.roomCfg, members.ActionReviewInvites); err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.ParseInt(req.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		return nil, weberrors.ErrBadRequest{Where: "ID", Details: err}
+	}
+
+	root, entries, err := h.load(req, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"Root":           root,
+		"Count":          len(entries),
+		csrf.TemplateTag: csrf.TemplateField(req),
+	}, nil
+}
+
+// suspend adds everyone that came in through a member to the denied keys, ends their sign-ins and revokes their api tokens.
+// Sign-ins of denied members are refused afterwards, see members.ContextInjecter.
+// The member itself is included if include_root is set. Members with a higher role than the moderator are left alone.
+func (h inviteTreeHandler) suspend(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != "POST" {
+		err := weberrors.ErrBadRequest{Where: "HTTP Method", Details: fmt.Errorf("expected POST not %s", req.Method)}
+		h.r.Error(rw, req, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := req.ParseForm(); err != nil {
+		err = weberrors.ErrBadRequest{Where: "Form data", Details: err}
+		h.r.Error(rw, req, http.StatusBadRequest, err)
+		return
+	}
+
+	id, err := strconv.ParseInt(req.FormValue("id"), 10, 64)
+	if err != nil {
+		err = weberrors.ErrBadRequest{Where: "ID", Details: err}
+		h.r.Error(rw, req, http.StatusBadRequest, err)
+		return
+	}
+
+	treeURL := h.urlTo(router.AdminInvitesTree, "id", id).String()
+	defer http.Redirect(rw, req, treeURL, http.StatusSeeOther)
+
+	ctx := req.Context()
+
+	moderator, err := members.CheckAllowed(ctx, h.roomCfg, members.ActionReviewInvites)
+	if err != nil {
+		h.flashes.AddError(rw, req, err)
+		return
+	}
+
+	root, entries, err := h.load(req, id)
+	if err != nil {
+		h.flashes.AddError(rw, req, err)
+		return
+	}
+
+	suspend := make([]roomdb.Member, 0, len(entries)+1)
+	if req.FormValue("include_root") == "on" {
+		suspend = append(suspend, root)
+	}
+	for _, e := range entries {
+		suspend = append(suspend, e.Member)
+	}
+
+	comment := fmt.Sprintf("suspended by %s: invited through %s", moderator.PubKey.String(), root.PubKey.String())
+	for _, m := range suspend {
+		if m.ID == moderator.ID || m.Role > moderator.Role {
+			continue
+		}
+
+		err = h.deniedKeysDB.Add(ctx, m.PubKey, comment)
+		var alreadyAdded roomdb.ErrAlreadyAdded
+		if err != nil && !errors.As(err, &alreadyAdded) {
+			h.flashes.AddError(rw, req, err)
+			return
+		}
+
+		// removed members don't have a role and nothing to sign out of
+		if m.Role == roomdb.RoleUnknown {
+			continue
+		}
+
+		if err := h.authWithSSBDB.WipeTokensForMember(ctx, m.ID); err != nil {
+			h.flashes.AddError(rw, req, err)
+			return
+		}
+
+		if err := h.webSessionsDB.RemoveForMember(ctx, m.ID); err != nil {
+			h.flashes.AddError(rw, req, err)
+			return
+		}
+
+		if err := h.apiTokensDB.RevokeAll(ctx, m.ID); err != nil {
+			h.flashes.AddError(rw, req, err)
+			return
+		}
+	}
+
+	h.flashes.AddMessage(rw, req, "AdminInviteTreeSuspended")
+}
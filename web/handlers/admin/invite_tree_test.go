@@ -0,0 +1,163 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	refs "github.com/ssbc/go-ssb-refs"
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+	"github.com/ssbc/go-ssb-room/v2/web/webassert"
+)
+
+// setupInviteTree fakes a tree where root invited alf and dora, and alf invited bob and carl.
+// carl was removed since and dora is an admin.
+func setupInviteTree(t *testing.T, ts *testSession) (roomdb.Member, []roomdb.Member) {
+	var feeds []refs.FeedRef
+	for i := 0; i < 5; i++ {
+		feed, err := generatePubKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		feeds = append(feeds, feed)
+	}
+
+	root := roomdb.Member{ID: 10, Role: roomdb.RoleMember, PubKey: feeds[0]}
+	alf := roomdb.Member{ID: 11, Role: roomdb.RoleMember, PubKey: feeds[1]}
+	bob := roomdb.Member{ID: 12, Role: roomdb.RoleMember, PubKey: feeds[2]}
+	carl := roomdb.Member{ID: 13, PubKey: feeds[3]}
+	dora := roomdb.Member{ID: 14, Role: roomdb.RoleAdmin, PubKey: feeds[4]}
+
+	ts.MembersDB.GetByIDReturns(root, nil)
+
+	now := time.Now()
+	ts.InvitesDB.ListInvitedByReturns([]roomdb.InviteUse{
+		{ID: 1, InviteID: 1, InvitedBy: root, Member: alf, UsedAt: now.Add(-4 * time.Hour)},
+		{ID: 2, InviteID: 2, InvitedBy: alf, Member: bob, UsedAt: now.Add(-3 * time.Hour)},
+		{ID: 3, InviteID: 3, InvitedBy: alf, Member: carl, UsedAt: now.Add(-2 * time.Hour)},
+		{ID: 4, InviteID: 4, InvitedBy: root, Member: dora, UsedAt: now.Add(-1 * time.Hour)},
+	}, nil)
+
+	return root, []roomdb.Member{alf, bob, carl, dora}
+}
+
+func TestInviteTree(t *testing.T) {
+	ts := newSession(t)
+	a := assert.New(t)
+
+	root, invited := setupInviteTree(t, ts)
+	bob := invited[1]
+
+	ts.DeniedKeysDB.HasFeedStub = func(_ context.Context, feed refs.FeedRef) bool {
+		return feed.Equal(bob.PubKey)
+	}
+
+	treeURL := ts.URLTo(router.AdminInvitesTree, "id", root.ID)
+
+	html, resp := ts.Client.GetHTML(treeURL)
+	a.Equal(http.StatusOK, resp.Code, "wrong HTTP status code")
+
+	webassert.Localized(t, html, []webassert.LocalizedElement{
+		{"title", "AdminInviteTreeTitle"},
+		{"#welcome", "AdminInviteTreeWelcome"},
+	})
+
+	a.Equal(1, ts.InvitesDB.ListInvitedByCallCount())
+	_, memberID, recursive := ts.InvitesDB.ListInvitedByArgsForCall(0)
+	a.Equal(root.ID, memberID)
+	a.True(recursive)
+
+	// the members are ordered by who invited them
+	entries := html.Find("#the-tree li")
+	a.Equal(4, entries.Length())
+	wantDepths := []string{"0", "1", "1", "0"}
+	for i, m := range invited {
+		li := entries.Eq(i)
+		a.Contains(li.Text(), m.PubKey.String(), "wrong member at %d", i)
+
+		style, _ := li.Attr("style")
+		a.Equal("padding-left: "+wantDepths[i]+"rem", style, "wrong depth at %d", i)
+	}
+
+	// removed members are not linked
+	a.Equal(0, entries.Eq(2).Find("a").Length())
+	a.Equal(1, entries.Eq(1).Find("a").Length())
+
+	a.Contains(entries.Eq(1).Text(), "AdminInviteTreeDenied")
+	a.NotContains(entries.Eq(0).Text(), "AdminInviteTreeDenied")
+
+	suspendLink, ok := html.Find("#suspend-tree").Attr("href")
+	a.True(ok)
+	a.Equal(ts.URLTo(router.AdminInvitesTreeSuspendConfirm, "id", root.ID).String(), suspendLink)
+
+	// members can't see it
+	ts.User.Role = roomdb.RoleMember
+	_, resp = ts.Client.GetHTML(treeURL)
+	a.Equal(http.StatusForbidden, resp.Code)
+}
+
+func TestInviteTreeSuspend(t *testing.T) {
+	ts := newSession(t)
+	a := assert.New(t)
+
+	root, invited := setupInviteTree(t, ts)
+	alf, bob, carl := invited[0], invited[1], invited[2]
+
+	// bob was denied before
+	ts.DeniedKeysDB.AddStub = func(_ context.Context, feed refs.FeedRef, _ string) error {
+		if feed.Equal(bob.PubKey) {
+			return roomdb.ErrAlreadyAdded{Ref: feed}
+		}
+		return nil
+	}
+
+	treeURL := ts.URLTo(router.AdminInvitesTree, "id", root.ID)
+	suspendURL := ts.URLTo(router.AdminInvitesTreeSuspend)
+
+	rec := ts.Client.PostForm(suspendURL, url.Values{
+		"id":           []string{"10"},
+		"include_root": []string{"on"},
+	})
+	a.Equal(http.StatusSeeOther, rec.Code)
+	a.Equal(treeURL.String(), rec.Header().Get("Location"))
+
+	webassert.HasFlashMessages(t, ts.Client, treeURL, "AdminInviteTreeSuspended")
+
+	// dora is an admin and was left alone
+	wantDenied := []roomdb.Member{root, alf, bob, carl}
+	a.Equal(len(wantDenied), ts.DeniedKeysDB.AddCallCount())
+	for i, m := range wantDenied {
+		_, feed, comment := ts.DeniedKeysDB.AddArgsForCall(i)
+		a.True(feed.Equal(m.PubKey), "wrong feed denied at %d", i)
+		a.Contains(comment, root.PubKey.String())
+	}
+
+	// carl was removed and has nothing to sign out of
+	wantSignedOut := []int64{root.ID, alf.ID, bob.ID}
+	a.Equal(len(wantSignedOut), ts.AuthWithSSB.WipeTokensForMemberCallCount())
+	a.Equal(len(wantSignedOut), ts.WebSessions.RemoveForMemberCallCount())
+	a.Equal(len(wantSignedOut), ts.APITokensDB.RevokeAllCallCount())
+	for i, id := range wantSignedOut {
+		_, wiped := ts.AuthWithSSB.WipeTokensForMemberArgsForCall(i)
+		a.Equal(id, wiped)
+		_, removed := ts.WebSessions.RemoveForMemberArgsForCall(i)
+		a.Equal(id, removed)
+		_, revoked := ts.APITokensDB.RevokeAllArgsForCall(i)
+		a.Equal(id, revoked)
+	}
+
+	// members are not allowed to
+	ts.User.Role = roomdb.RoleMember
+	rec = ts.Client.PostForm(suspendURL, url.Values{"id": []string{"10"}})
+	a.Equal(http.StatusSeeOther, rec.Code)
+	a.Equal(len(wantDenied), ts.DeniedKeysDB.AddCallCount())
+}
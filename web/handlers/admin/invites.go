@@ -9,10 +9,13 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/csrf"
 	"go.mindeco.de/http/render"
 
+	refs "github.com/ssbc/go-ssb-refs"
 	"github.com/ssbc/go-ssb-room/v2/roomdb"
 	"github.com/ssbc/go-ssb-room/v2/web"
 	weberrors "github.com/ssbc/go-ssb-room/v2/web/errors"
@@ -20,13 +23,17 @@ import (
 	"github.com/ssbc/go-ssb-room/v2/web/router"
 )
 
+// how many of the unused open mode invites are listed, the newest ones
+const openModeInvitesShown = 20
+
 type invitesHandler struct {
 	r       *render.Renderer
 	flashes *weberrors.FlashHelper
 	urlTo   web.URLMaker
 
-	db     roomdb.InvitesService
-	config roomdb.RoomConfig
+	db         roomdb.InvitesService
+	requestsDB roomdb.InviteRequestsService
+	config     roomdb.RoomConfig
 }
 
 func (h invitesHandler) overview(rw http.ResponseWriter, req *http.Request) (interface{}, error) {
@@ -40,10 +47,57 @@ func (h invitesHandler) overview(rw http.ResponseWriter, req *http.Request) (int
 		lst[i], lst[j] = lst[j], lst[i]
 	}
 
-	pageData, err := paginate(lst, len(lst), req.URL.Query())
+	// the invites that anyone created in open mode get their own section, so that they don't drown out the others
+	var invites, openMode []roomdb.Invite
+	for _, inv := range lst {
+		if inv.OpenMode {
+			openMode = append(openMode, inv)
+		} else {
+			invites = append(invites, inv)
+		}
+	}
+
+	pageData, err := paginate(invites, len(invites), req.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	mode, err := h.config.GetPrivacyMode(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	pageData["ShowOpenMode"] = mode == roomdb.ModeOpen || len(openMode) > 0
+	pageData["OpenModeUnused"] = len(openMode)
+	if len(openMode) > openModeInvitesShown {
+		openMode = openMode[:openModeInvitesShown]
+	}
+	pageData["OpenModeInvites"] = openMode
+
+	openModeCreated, err := h.db.CountOpenMode(req.Context(), time.Now().Add(-24*time.Hour))
 	if err != nil {
 		return nil, err
 	}
+	pageData["OpenModeCreated"] = int(openModeCreated)
+
+	// members can only invite others with the same role or a lower one
+	var roles []roomdb.Role
+	if member := members.FromContext(req.Context()); member != nil {
+		for _, r := range []roomdb.Role{roomdb.RoleMember, roomdb.RoleModerator, roomdb.RoleAdmin} {
+			if r <= member.Role {
+				roles = append(roles, r)
+			}
+		}
+	}
+	pageData["InviteRoles"] = roles
+
+	// moderators see how many people wait for an invite
+	if _, err := members.CheckAllowed(req.Context(), h.config, members.ActionReviewInvites); err == nil {
+		requests, err := h.requestsDB.List(req.Context())
+		if err != nil {
+			return nil, err
+		}
+		pageData["PendingRequests"] = len(requests)
+	}
 
 	pageData[csrf.TemplateTag] = csrf.TemplateField(req)
 	pageData["Flashes"], err = h.flashes.GetAll(rw, req)
@@ -69,7 +123,28 @@ func (h invitesHandler) create(w http.ResponseWriter, req *http.Request) (interf
 		return nil, err
 	}
 
-	token, err := h.db.Create(ctx, member.ID)
+	// both options can be left out, for a plain invite that anyone can use
+	var opts roomdb.InviteOptions
+
+	opts.Role = roomdb.RoleMember
+	if r := req.Form.Get("role"); r != "" {
+		if err := opts.Role.UnmarshalText([]byte(r)); err != nil {
+			return nil, weberrors.ErrBadRequest{Where: "role", Details: err}
+		}
+	}
+	if opts.Role > member.Role {
+		return nil, weberrors.ErrForbidden{Details: fmt.Errorf("can't invite somebody as %s", opts.Role)}
+	}
+
+	if f := strings.TrimSpace(req.Form.Get("for_feed")); f != "" {
+		feed, err := refs.ParseFeedRef(f)
+		if err != nil {
+			return nil, weberrors.ErrBadRequest{Where: "Public Key", Details: err}
+		}
+		opts.ForFeed = &feed
+	}
+
+	token, err := h.db.Create(ctx, member.ID, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -78,6 +153,8 @@ func (h invitesHandler) create(w http.ResponseWriter, req *http.Request) (interf
 
 	return map[string]interface{}{
 		"FacadeURL": facadeURL.String(),
+		"Role":      opts.Role,
+		"ForFeed":   opts.ForFeed,
 	}, nil
 }
 
@@ -125,6 +125,45 @@ func TestInvitesOverview(t *testing.T) {
 	testInviteButtonDisabled(true)
 }
 
+func TestInvitesOverviewOpenMode(t *testing.T) {
+	ts := newSession(t)
+	a := assert.New(t)
+
+	invitesOverviewURL := ts.URLTo(router.AdminInvitesOverview)
+
+	// nothing to show while the room isn't open
+	html, resp := ts.Client.GetHTML(invitesOverviewURL)
+	a.Equal(http.StatusOK, resp.Code, "wrong HTTP status code")
+	a.Equal(0, html.Find("#open-mode-invites").Length())
+
+	testUser := roomdb.Member{ID: 23}
+	ts.InvitesDB.ListReturns([]roomdb.Invite{
+		{ID: 1, CreatedBy: testUser},
+		{ID: 2, CreatedBy: testUser, OpenMode: true},
+		{ID: 3, CreatedBy: testUser, OpenMode: true},
+	}, nil)
+	ts.InvitesDB.CountOpenModeReturns(5, nil)
+
+	html, resp = ts.Client.GetHTML(invitesOverviewURL)
+	a.Equal(http.StatusOK, resp.Code, "wrong HTTP status code")
+
+	webassert.Localized(t, html, []webassert.LocalizedElement{
+		{"#invite-list-count", "AdminInvitesCountSingular"},
+		{"#open-mode-created", "AdminInvitesOpenModeCreatedPlural"},
+		{"#open-mode-unused", "AdminInvitesOpenModeUnusedPlural"},
+	})
+
+	// the open mode invites are not in the table of the others
+	a.EqualValues(1, html.Find("#the-table-rows tr").Length()/2)
+
+	revokeLinks := html.Find("#open-mode-invites .revoke-open-invite")
+	a.Equal(2, revokeLinks.Length())
+	link, ok := revokeLinks.Eq(0).Attr("href")
+	a.True(ok)
+	a.Equal(ts.URLTo(router.AdminInvitesRevokeConfirm, "id", 3).String(), link, "newest first")
+	a.Equal(2, ts.InvitesDB.CountOpenModeCallCount())
+}
+
 func TestInvitesCreateForm(t *testing.T) {
 	ts := newSession(t)
 	a := assert.New(t)
@@ -173,7 +212,7 @@ func TestInvitesCreateAndRevoke(t *testing.T) {
 			totalCreateCallCount += 1
 			a.Equal(http.StatusOK, rec.Code)
 			r.Equal(totalCreateCallCount, ts.InvitesDB.CreateCallCount())
-			_, userID := ts.InvitesDB.CreateArgsForCall(totalCreateCallCount - 1)
+			_, userID, _ := ts.InvitesDB.CreateArgsForCall(totalCreateCallCount - 1)
 			a.EqualValues(ts.User.ID, userID)
 		} else {
 			a.Equal(http.StatusForbidden, rec.Code)
@@ -276,3 +315,42 @@ func TestInvitesCreateAndRevoke(t *testing.T) {
 		})
 	}
 }
+
+func TestInvitesCreateWithOptions(t *testing.T) {
+	ts := newSession(t)
+	a := assert.New(t)
+	r := require.New(t)
+
+	urlCreate := ts.URLTo(router.AdminInvitesCreate)
+	ts.InvitesDB.CreateReturns("your-fake-test-invite", nil)
+
+	feed, err := generatePubKey()
+	r.NoError(err)
+
+	rec := ts.Client.PostForm(urlCreate, url.Values{
+		"role":     []string{"RoleModerator"},
+		"for_feed": []string{" " + feed.String() + " "},
+	})
+	a.Equal(http.StatusOK, rec.Code)
+
+	r.Equal(1, ts.InvitesDB.CreateCallCount())
+	_, _, opts := ts.InvitesDB.CreateArgsForCall(0)
+	a.Equal(roomdb.RoleModerator, opts.Role)
+	r.NotNil(opts.ForFeed)
+	a.True(opts.ForFeed.Equal(feed))
+
+	doc, err := goquery.NewDocumentFromReader(rec.Body)
+	r.NoError(err)
+	a.Contains(doc.Find("#invite-role").Text(), "RoleModerator")
+	a.Contains(doc.Find("#invite-for-feed").Text(), feed.String())
+
+	// a moderator can't invite an admin
+	rec = ts.Client.PostForm(urlCreate, url.Values{"role": []string{"RoleAdmin"}})
+	a.Equal(http.StatusForbidden, rec.Code)
+
+	// and the key has to be valid
+	rec = ts.Client.PostForm(urlCreate, url.Values{"for_feed": []string{"@not-a-key"}})
+	a.Equal(http.StatusBadRequest, rec.Code)
+
+	a.Equal(1, ts.InvitesDB.CreateCallCount())
+}
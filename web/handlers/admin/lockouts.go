@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/csrf"
+	"go.mindeco.de/http/render"
+	"go.mindeco.de/log/level"
+	"go.mindeco.de/logging"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	weberrors "github.com/ssbc/go-ssb-room/v2/web/errors"
+	"github.com/ssbc/go-ssb-room/v2/web/members"
+)
+
+// lockoutsHandler shows the logins and addresses with failed password sign-ins and lets admins end their lockouts
+type lockoutsHandler struct {
+	r *render.Renderer
+
+	flashes *weberrors.FlashHelper
+
+	db      roomdb.LoginLockoutService
+	roomCfg roomdb.RoomConfig
+}
+
+const redirectToLockouts = "/admin/lockouts"
+
+func (h lockoutsHandler) overview(rw http.ResponseWriter, req *http.Request) (interface{}, error) {
+	_, err := members.CheckAllowed(req.Context(), h.roomCfg, members.ActionClearLockouts)
+	if err != nil {
+		return nil, weberrors.ErrForbidden{Details: err}
+	}
+
+	lst, err := h.db.List(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	pageData, err := paginate(lst, len(lst), req.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	pageData[csrf.TemplateTag] = csrf.TemplateField(req)
+	pageData["Flashes"], err = h.flashes.GetAll(rw, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return pageData, nil
+}
+
+func (h lockoutsHandler) clear(rw http.ResponseWriter, req *http.Request) {
+	// always redirect
+	defer http.Redirect(rw, req, redirectToLockouts, http.StatusSeeOther)
+
+	ctx := req.Context()
+
+	member, err := members.CheckAllowed(ctx, h.roomCfg, members.ActionClearLockouts)
+	if err != nil {
+		err := weberrors.ErrNotAuthorized
+		h.flashes.AddError(rw, req, err)
+		return
+	}
+
+	if req.Method != "POST" {
+		err := weberrors.ErrBadRequest{Where: "HTTP Method", Details: fmt.Errorf("expected POST not %s", req.Method)}
+		h.flashes.AddError(rw, req, err)
+		return
+	}
+
+	err = req.ParseForm()
+	if err != nil {
+		err = weberrors.ErrBadRequest{Where: "Form data", Details: err}
+		h.flashes.AddError(rw, req, err)
+		return
+	}
+
+	id, err := strconv.ParseInt(req.FormValue("id"), 10, 64)
+	if err != nil {
+		err = weberrors.ErrBadRequest{Where: "ID", Details: err}
+		h.flashes.AddError(rw, req, err)
+		return
+	}
+
+	err = h.db.Clear(ctx, id)
+	if err != nil {
+		h.flashes.AddError(rw, req, err)
+		return
+	}
+
+	level.Info(logging.FromContext(ctx)).Log("event", "fallback sign-in lockout cleared", "id", id, "by", member.PubKey.ShortSigil())
+	h.flashes.AddMessage(rw, req, "AdminLockoutsCleared")
+}
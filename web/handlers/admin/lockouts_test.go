@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+	"github.com/ssbc/go-ssb-room/v2/web/webassert"
+)
+
+func TestLockoutsOverview(t *testing.T) {
+	ts := newSession(t)
+	a := assert.New(t)
+
+	ts.User.Role = roomdb.RoleAdmin
+
+	ts.LockoutsDB.ListReturns([]roomdb.LoginLockout{
+		{ID: 1, Kind: roomdb.LockoutKindLogin, Subject: "alice", Failures: 7, LastFailure: time.Now(), LockedUntil: time.Now().Add(time.Hour)},
+		{ID: 2, Kind: roomdb.LockoutKindAddress, Subject: "203.0.113.7", Failures: 3, LastFailure: time.Now().Add(-time.Minute)},
+	}, nil)
+
+	listURL := ts.URLTo(router.AdminLockoutsOverview)
+	html, resp := ts.Client.GetHTML(listURL)
+	a.Equal(http.StatusOK, resp.Code, "wrong HTTP status code")
+
+	webassert.Localized(t, html, []webassert.LocalizedElement{
+		{"#welcome", "AdminLockoutsWelcome"},
+		{"title", "AdminLockoutsTitle"},
+		{"#LockoutsCount", "AdminLockoutsCountPlural"},
+	})
+
+	entries := html.Find("#theList li")
+	a.Equal(2, entries.Length())
+	a.Contains(entries.Eq(0).Text(), "alice")
+	a.Contains(entries.Eq(0).Text(), "AdminLockoutsLocked")
+	a.Contains(entries.Eq(1).Text(), "203.0.113.7")
+	a.NotContains(entries.Eq(1).Text(), "AdminLockoutsLocked")
+
+	clearForm := entries.Eq(0).Find("form")
+	action, _ := clearForm.Attr("action")
+	a.Equal(ts.URLTo(router.AdminLockoutsClear).String(), action)
+	webassert.ElementsInForm(t, clearForm, []webassert.FormElement{
+		{Name: "id", Type: "hidden", Value: "1"},
+	})
+
+	// moderators don't get to see the addresses
+	ts.User.Role = roomdb.RoleModerator
+	_, resp = ts.Client.GetHTML(listURL)
+	a.Equal(http.StatusForbidden, resp.Code, "wrong HTTP status code")
+	a.Equal(1, ts.LockoutsDB.ListCallCount())
+}
+
+func TestLockoutsClear(t *testing.T) {
+	ts := newSession(t)
+	a := assert.New(t)
+
+	ts.User.Role = roomdb.RoleAdmin
+
+	clearURL := ts.URLTo(router.AdminLockoutsClear)
+	overview := ts.URLTo(router.AdminLockoutsOverview)
+
+	rec := ts.Client.PostForm(clearURL, url.Values{"id": []string{"666"}})
+	a.Equal(http.StatusSeeOther, rec.Code)
+	a.Equal(overview.Path, rec.Header().Get("Location"))
+	webassert.HasFlashMessages(t, ts.Client, overview, "AdminLockoutsCleared")
+
+	a.Equal(1, ts.LockoutsDB.ClearCallCount())
+	_, theID := ts.LockoutsDB.ClearArgsForCall(0)
+	a.EqualValues(666, theID)
+
+	// unknown ones
+	ts.LockoutsDB.ClearReturns(roomdb.ErrNotFound)
+	rec = ts.Client.PostForm(clearURL, url.Values{"id": []string{"667"}})
+	a.Equal(http.StatusSeeOther, rec.Code)
+	webassert.HasFlashMessages(t, ts.Client, overview, "ErrorNotFound")
+
+	// only admins can clear them
+	ts.User.Role = roomdb.RoleModerator
+	rec = ts.Client.PostForm(clearURL, url.Values{"id": []string{"668"}})
+	a.Equal(http.StatusSeeOther, rec.Code)
+	a.Equal(2, ts.LockoutsDB.ClearCallCount())
+}
@@ -30,7 +30,9 @@ type membersHandler struct {
 	netInfo network.ServerEndpointDetails
 
 	db             roomdb.MembersService
+	invitesDB      roomdb.InvitesService
 	fallbackAuthDB roomdb.AuthFallbackService
+	sessionsDB     roomdb.AuthWithSSBService
 	roomCfgDB      roomdb.RoomConfig
 }
 
@@ -161,12 +163,83 @@ func (h membersHandler) details(rw http.ResponseWriter, req *http.Request) (inte
 		aliasURLs[a.Name] = template.URL(h.netInfo.URLForAlias(a.Name))
 	}
 
-	return map[string]interface{}{
+	pageData := map[string]interface{}{
 		"Member":         member,
 		"AllRoles":       roles,
 		"AliasURLs":      aliasURLs,
 		csrf.TemplateTag: csrf.TemplateField(req),
-	}, nil
+	}
+
+	pageData["Flashes"], err = h.flashes.GetAll(rw, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// moderators get to see who brought the member into the room
+	if viewer := members.FromContext(req.Context()); viewer != nil && viewer.Role >= roomdb.RoleModerator {
+		invitedBy, err := h.invitesDB.InvitedBy(req.Context(), member.ID)
+		if err == nil {
+			pageData["InvitedBy"] = invitedBy
+		} else if !errors.Is(err, roomdb.ErrNotFound) {
+			return nil, err
+		}
+	}
+
+	// only admins get to see where members are signed in
+	if viewer := members.FromContext(req.Context()); viewer != nil && viewer.Role == roomdb.RoleAdmin {
+		pageData["Sessions"], err = h.sessionsDB.ListSessions(req.Context(), member.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return pageData, nil
+}
+
+// removeSession lets admins end a sign-in with ssb session of any member
+func (h membersHandler) removeSession(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "POST" {
+		err := weberrors.ErrBadRequest{Where: "HTTP Method", Details: fmt.Errorf("expected POST not %s", req.Method)}
+		h.r.Error(w, req, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := req.ParseForm(); err != nil {
+		err = weberrors.ErrBadRequest{Where: "Form data", Details: err}
+		h.r.Error(w, req, http.StatusBadRequest, err)
+		return
+	}
+
+	currentMember := members.FromContext(req.Context())
+	if currentMember == nil || currentMember.Role != roomdb.RoleAdmin {
+		err := weberrors.ErrForbidden{Details: fmt.Errorf("not an admin")}
+		h.r.Error(w, req, http.StatusForbidden, err)
+		return
+	}
+
+	memberID, err := strconv.ParseInt(req.FormValue("member_id"), 10, 64)
+	if err != nil {
+		err = weberrors.ErrBadRequest{Where: "Member ID", Details: err}
+		h.r.Error(w, req, http.StatusBadRequest, err)
+		return
+	}
+
+	memberDetailsURL := h.urlTo(router.AdminMemberDetails, "id", memberID).String()
+	defer http.Redirect(w, req, memberDetailsURL, http.StatusSeeOther)
+
+	sessionID, err := strconv.ParseInt(req.FormValue("session_id"), 10, 64)
+	if err != nil {
+		h.flashes.AddError(w, req, weberrors.ErrBadRequest{Where: "Session ID", Details: err})
+		return
+	}
+
+	err = h.sessionsDB.RemoveSession(req.Context(), memberID, sessionID)
+	if err != nil {
+		h.flashes.AddError(w, req, err)
+		return
+	}
+
+	h.flashes.AddMessage(w, req, "AdminMemberSessionRemoved")
 }
 
 func (h membersHandler) removeConfirm(rw http.ResponseWriter, req *http.Request) (interface{}, error) {
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/stretchr/testify/assert"
@@ -233,9 +234,14 @@ func TestMemberDetails(t *testing.T) {
 		t.Error(err)
 	}
 
+	newOwner, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte{1}, 32), refs.RefAlgoFeedSSB1)
+	if err != nil {
+		t.Error(err)
+	}
+
 	aliases := []roomdb.Alias{
 		{ID: 11, Name: "robert", Feed: feedRef, Signature: bytes.Repeat([]byte{0}, 4)},
-		{ID: 21, Name: "bob", Feed: feedRef, Signature: bytes.Repeat([]byte{0}, 4)},
+		{ID: 21, Name: "bob", Feed: feedRef, Signature: bytes.Repeat([]byte{0}, 4), TransferTo: &newOwner},
 	}
 
 	member := roomdb.Member{
@@ -284,6 +290,18 @@ func TestMemberDetails(t *testing.T) {
 	wantLink = ts.URLTo(router.AdminAliasesRevokeConfirm, "id", 21)
 	a.Equal(wantLink.String(), revokeBobLink)
 
+	// bob is being handed over
+	pending := html.Find("#alias-transfers li")
+	a.Equal(1, pending.Length())
+	a.Contains(pending.Text(), newOwner.ShortSigil())
+	cancelName, _ := pending.Find("input[name=name]").Attr("value")
+	a.Equal("bob", cancelName)
+
+	transferForm := html.Find("form#transfer-alias")
+	transferAction, _ := transferForm.Attr("action")
+	a.Equal(ts.URLTo(router.AdminAliasesTransfer).String(), transferAction)
+	a.Equal(2, transferForm.Find("select[name=name] option").Length())
+
 	// check for link to Remove member link
 	removeLink, yes := html.Find("#remove-member").Attr("href")
 	a.True(yes, "a-tag has href attribute")
@@ -535,3 +553,154 @@ func TestMembersCreateResetToken(t *testing.T) {
 	wantResetURL := ts.URLTo(router.MembersChangePassword, "token", testToken)
 	a.Equal(wantResetURL.String(), gotResetURL)
 }
+
+func TestMemberDetailsSessions(t *testing.T) {
+	ts := newSession(t)
+	a := assert.New(t)
+
+	testRef, err := generatePubKey()
+	if err != nil {
+		t.Error(err)
+	}
+
+	ts.MembersDB.GetByIDReturns(roomdb.Member{
+		ID:     2342,
+		Role:   roomdb.RoleMember,
+		PubKey: testRef,
+	}, nil)
+
+	ts.AuthWithSSB.ListSessionsReturns([]roomdb.SIWSSBSession{
+		{ID: 7, MemberID: 2342, LastUsedAt: time.Now(), UserAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:109.0) Gecko/20100101 Firefox/115.0", RemoteAddr: "203.0.113.0"},
+		{ID: 9, MemberID: 2342, LastUsedAt: time.Now()},
+	}, nil)
+
+	urlViewDetails := ts.URLTo(router.AdminMemberDetails, "id", "2342")
+
+	// moderators don't see the sessions
+	ts.User.Role = roomdb.RoleModerator
+
+	doc, resp := ts.Client.GetHTML(urlViewDetails)
+	a.Equal(http.StatusOK, resp.Code)
+	a.Equal(0, doc.Find("#session-list").Length())
+	a.Equal(0, ts.AuthWithSSB.ListSessionsCallCount())
+
+	// admins do
+	ts.User.Role = roomdb.RoleAdmin
+
+	doc, resp = ts.Client.GetHTML(urlViewDetails)
+	a.Equal(http.StatusOK, resp.Code)
+	a.Equal(1, ts.AuthWithSSB.ListSessionsCallCount())
+	_, listedFor := ts.AuthWithSSB.ListSessionsArgsForCall(0)
+	a.EqualValues(2342, listedFor)
+
+	items := doc.Find("#session-list li")
+	a.Equal(2, items.Length())
+	a.Equal("Firefox (Linux)", items.Eq(0).Find(".session-user-agent").Text())
+	a.Equal("203.0.113.0", items.Eq(0).Find(".session-remote-addr").Text())
+	a.Equal(0, items.Eq(1).Find(".session-remote-addr").Length())
+
+	removeURL := ts.URLTo(router.AdminMembersRemoveSession)
+	form := items.Eq(0).Find("form")
+	formAction, hasAction := form.Attr("action")
+	a.True(hasAction, "missing action")
+	a.Equal(removeURL.String(), formAction, "wrong action")
+
+	webassert.ElementsInForm(t, form, []webassert.FormElement{
+		{Name: "member_id", Value: "2342", Type: "hidden"},
+		{Name: "session_id", Value: "7", Type: "hidden"},
+	})
+
+	// now end it
+	resp = ts.Client.PostForm(removeURL, url.Values{
+		"member_id":  []string{"2342"},
+		"session_id": []string{"7"},
+	})
+	a.Equal(http.StatusSeeOther, resp.Code)
+	a.Equal(urlViewDetails.String(), resp.Result().Header.Get("Location"))
+
+	a.Equal(1, ts.AuthWithSSB.RemoveSessionCallCount())
+	_, memberID, sessionID := ts.AuthWithSSB.RemoveSessionArgsForCall(0)
+	a.EqualValues(2342, memberID)
+	a.EqualValues(7, sessionID)
+
+	webassert.HasFlashMessages(t, ts.Client, urlViewDetails, "AdminMemberSessionRemoved")
+
+	// moderators can't end sessions
+	ts.User.Role = roomdb.RoleModerator
+	resp = ts.Client.PostForm(removeURL, url.Values{
+		"member_id":  []string{"2342"},
+		"session_id": []string{"9"},
+	})
+	a.Equal(http.StatusForbidden, resp.Code)
+	a.Equal(1, ts.AuthWithSSB.RemoveSessionCallCount())
+}
+
+func TestMemberDetailsInvitedBy(t *testing.T) {
+	ts := newSession(t)
+	a := assert.New(t)
+
+	testRef, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte{1}, 32), refs.RefAlgoFeedSSB1)
+	if err != nil {
+		t.Error(err)
+	}
+	inviterRef, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte{2}, 32), refs.RefAlgoFeedSSB1)
+	if err != nil {
+		t.Error(err)
+	}
+
+	ts.MembersDB.GetByIDReturns(roomdb.Member{
+		ID:     2342,
+		Role:   roomdb.RoleMember,
+		PubKey: testRef,
+	}, nil)
+
+	ts.InvitesDB.InvitedByReturns(roomdb.InviteUse{
+		ID:        1,
+		InviteID:  3,
+		InvitedBy: roomdb.Member{ID: 23, Role: roomdb.RoleMember, PubKey: inviterRef},
+		Member:    roomdb.Member{ID: 2342, Role: roomdb.RoleMember, PubKey: testRef},
+		UsedAt:    time.Now(),
+	}, nil)
+
+	urlViewDetails := ts.URLTo(router.AdminMemberDetails, "id", "2342")
+
+	doc, resp := ts.Client.GetHTML(urlViewDetails)
+	a.Equal(http.StatusOK, resp.Code)
+
+	inviterLink, ok := doc.Find("#invited-by a").Attr("href")
+	a.True(ok, "no link to the inviter")
+	a.Equal(ts.URLTo(router.AdminMemberDetails, "id", 23).String(), inviterLink)
+	a.Equal(inviterRef.String(), doc.Find("#invited-by a").Text())
+
+	treeLink, ok := doc.Find("#invite-tree").Attr("href")
+	a.True(ok, "no link to the invite tree")
+	a.Equal(ts.URLTo(router.AdminInvitesTree, "id", 2342).String(), treeLink)
+
+	// the inviter was removed since
+	ts.InvitesDB.InvitedByReturns(roomdb.InviteUse{
+		ID:        1,
+		InviteID:  3,
+		InvitedBy: roomdb.Member{ID: 23},
+		Member:    roomdb.Member{ID: 2342, Role: roomdb.RoleMember, PubKey: testRef},
+		UsedAt:    time.Now(),
+	}, nil)
+
+	doc, resp = ts.Client.GetHTML(urlViewDetails)
+	a.Equal(http.StatusOK, resp.Code)
+	a.Equal(0, doc.Find("#invited-by a").Length())
+	a.Contains(doc.Find("#invited-by").Text(), "AdminMemberDetailsInvitedByRemoved")
+
+	// joined without an invite
+	ts.InvitesDB.InvitedByReturns(roomdb.InviteUse{}, roomdb.ErrNotFound)
+
+	doc, resp = ts.Client.GetHTML(urlViewDetails)
+	a.Equal(http.StatusOK, resp.Code)
+	a.Contains(doc.Find("#invited-by").Text(), "AdminMemberDetailsInvitedByUnknown")
+
+	// members don't see it
+	ts.User.Role = roomdb.RoleMember
+	doc, resp = ts.Client.GetHTML(urlViewDetails)
+	a.Equal(http.StatusOK, resp.Code)
+	a.Equal(0, doc.Find("#invited-by").Length())
+	a.Equal(3, ts.InvitesDB.InvitedByCallCount(), "only looked up for moderators")
+}
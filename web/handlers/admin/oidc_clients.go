@@ -0,0 +1,223 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/csrf"
+	"go.mindeco.de/http/render"
+	"go.mindeco.de/log/level"
+	"go.mindeco.de/logging"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web"
+	weberrors "github.com/ssbc/go-ssb-room/v2/web/errors"
+	"github.com/ssbc/go-ssb-room/v2/web/members"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+)
+
+// oidcClientsHandler lets admins register the apps that members can sign into with their room identity
+type oidcClientsHandler struct {
+	r *render.Renderer
+
+	flashes *weberrors.FlashHelper
+	urlTo   web.URLMaker
+
+	db      roomdb.OIDCClientsService
+	roomCfg roomdb.RoomConfig
+}
+
+const redirectToOIDCClients = "/admin/oidc-clients"
+
+func (h oidcClientsHandler) overview(rw http.ResponseWriter, req *http.Request) (interface{}, error) {
+	_, err := members.CheckAllowed(req.Context(), h.roomCfg, members.ActionManageOIDCApps)
+	if err != nil {
+		return nil, weberrors.ErrForbidden{Details: err}
+	}
+
+	lst, err := h.db.List(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	pageData := map[string]interface{}{
+		"Entries":        lst,
+		"Count":          len(lst),
+		"DiscoveryURL":   h.urlTo(router.OIDCDiscovery).String(),
+		csrf.TemplateTag: csrf.TemplateField(req),
+	}
+
+	pageData["Flashes"], err = h.flashes.GetAll(rw, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return pageData, nil
+}
+
+// add registers the client and shows its secret, which isn't stored and can't be shown again
+func (h oidcClientsHandler) add(rw http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "POST" {
+		return nil, weberrors.ErrBadRequest{Where: "HTTP Method", Details: fmt.Errorf("expected POST not %s", req.Method)}
+	}
+
+	if err := req.ParseForm(); err != nil {
+		return nil, weberrors.ErrBadRequest{Where: "Form data", Details: err}
+	}
+
+	ctx := req.Context()
+
+	member, err := members.CheckAllowed(ctx, h.roomCfg, members.ActionManageOIDCApps)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.FormValue("name"))
+	if name == "" {
+		return nil, weberrors.ErrRedirect{
+			Path:   redirectToOIDCClients,
+			Reason: weberrors.ErrBadRequest{Where: "Name", Details: fmt.Errorf("the app needs a name")},
+		}
+	}
+
+	redirectURIs, err := parseRedirectURIs(req.FormValue("redirect_uris"))
+	if err != nil {
+		return nil, weberrors.ErrRedirect{
+			Path:   redirectToOIDCClients,
+			Reason: weberrors.ErrBadRequest{Where: "Redirect URIs", Details: err},
+		}
+	}
+
+	client, secret, err := h.db.Register(ctx, name, redirectURIs)
+	if err != nil {
+		return nil, err
+	}
+
+	level.Info(logging.FromContext(ctx)).Log("event", "oidc client registered", "client", client.ClientID, "by", member.PubKey.ShortSigil())
+
+	return map[string]interface{}{
+		"Client":       client,
+		"Secret":       secret,
+		"DiscoveryURL": h.urlTo(router.OIDCDiscovery).String(),
+	}, nil
+}
+
+func (h oidcClientsHandler) removeConfirm(rw http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if _, err := members.CheckAllowed(req.Context(), h.roomCfg, members.ActionManageOIDCApps); err != nil {
+		return nil, weberrors.ErrForbidden{Details: err}
+	}
+
+	id, err := strconv.ParseInt(req.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		err = weberrors.ErrBadRequest{Where: "ID", Details: err}
+		return nil, err
+	}
+
+	entry, err := h.db.GetByID(req.Context(), id)
+	if err != nil {
+		return nil, weberrors.ErrRedirect{
+			Path:   redirectToOIDCClients,
+			Reason: err,
+		}
+	}
+
+	return map[string]interface{}{
+		"Entry":          entry,
+		csrf.TemplateTag: csrf.TemplateField(req),
+	}, nil
+}
+
+func (h oidcClientsHandler) remove(rw http.ResponseWriter, req *http.Request) {
+	// always redirect
+	defer http.Redirect(rw, req, redirectToOIDCClients, http.StatusSeeOther)
+
+	ctx := req.Context()
+
+	member, err := members.CheckAllowed(ctx, h.roomCfg, members.ActionManageOIDCApps)
+	if err != nil {
+		err := weberrors.ErrNotAuthorized
+		h.flashes.AddError(rw, req, err)
+		return
+	}
+
+	if req.Method != "POST" {
+		err := weberrors.ErrBadRequest{Where: "HTTP Method", Details: fmt.Errorf("expected POST not %s", req.Method)}
+		h.flashes.AddError(rw, req, err)
+		return
+	}
+
+	err = req.ParseForm()
+	if err != nil {
+		err = weberrors.ErrBadRequest{Where: "Form data", Details: err}
+		h.flashes.AddError(rw, req, err)
+		return
+	}
+
+	id, err := strconv.ParseInt(req.FormValue("id"), 10, 64)
+	if err != nil {
+		err = weberrors.ErrBadRequest{Where: "ID", Details: err}
+		h.flashes.AddError(rw, req, err)
+		return
+	}
+
+	err = h.db.RemoveID(ctx, id)
+	if err != nil {
+		h.flashes.AddError(rw, req, err)
+		return
+	}
+
+	level.Info(logging.FromContext(ctx)).Log("event", "oidc client removed", "id", id, "by", member.PubKey.ShortSigil())
+	h.flashes.AddMessage(rw, req, "AdminOIDCClientRemoved")
+}
+
+// parseRedirectURIs takes one uri per line. They need to be absolute and use https, except for local development.
+// Fragments are not allowed, as the code is added to the query of the uri.
+func parseRedirectURIs(text string) ([]string, error) {
+	var uris []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		u, err := url.Parse(line)
+		if err != nil {
+			return nil, err
+		}
+
+		if !u.IsAbs() || u.Host == "" {
+			return nil, fmt.Errorf("%s is not an absolute url", line)
+		}
+
+		if u.Fragment != "" {
+			return nil, fmt.Errorf("%s can't have a fragment", line)
+		}
+
+		switch u.Scheme {
+		case "https":
+		case "http":
+			if host := u.Hostname(); host != "localhost" && !net.ParseIP(host).IsLoopback() {
+				return nil, fmt.Errorf("%s needs to use https", line)
+			}
+		default:
+			return nil, fmt.Errorf("%s needs to use https", line)
+		}
+
+		// kept as they are, the authorization requests need to match them exactly
+		uris = append(uris, line)
+	}
+
+	if len(uris) == 0 {
+		return nil, fmt.Errorf("the app needs at least one redirect uri")
+	}
+
+	return uris, nil
+}
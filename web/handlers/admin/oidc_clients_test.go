@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+	"github.com/ssbc/go-ssb-room/v2/web/webassert"
+)
+
+func TestOIDCClientsOverview(t *testing.T) {
+	ts := newSession(t)
+	a := assert.New(t)
+
+	ts.User.Role = roomdb.RoleAdmin
+
+	ts.OIDCDB.ListReturns([]roomdb.OIDCClient{
+		{ID: 1, ClientID: "0123abcd", Name: "wiki", RedirectURIs: []string{"https://wiki.example/cb"}, CreatedAt: time.Now()},
+		{ID: 2, ClientID: "4567ef01", Name: "forum", RedirectURIs: []string{"https://forum.example/oidc"}, CreatedAt: time.Now()},
+	}, nil)
+
+	listURL := ts.URLTo(router.AdminOIDCClientsOverview)
+	html, resp := ts.Client.GetHTML(listURL)
+	a.Equal(http.StatusOK, resp.Code, "wrong HTTP status code")
+
+	webassert.Localized(t, html, []webassert.LocalizedElement{
+		{"#welcome", "AdminOIDCClientsWelcome"},
+		{"title", "AdminOIDCClientsTitle"},
+		{"#OIDCClientsCount", "AdminOIDCClientsCountPlural"},
+	})
+
+	a.Equal(ts.URLTo(router.OIDCDiscovery).String(), html.Find("#discovery-url").Text())
+
+	entries := html.Find("#theList li")
+	a.Equal(2, entries.Length())
+	a.Contains(entries.Eq(0).Text(), "wiki")
+	a.Contains(entries.Eq(0).Text(), "https://wiki.example/cb")
+	a.Contains(entries.Eq(1).Text(), "forum")
+
+	addForm := html.Find("#add-entry")
+	action, _ := addForm.Attr("action")
+	a.Equal(ts.URLTo(router.AdminOIDCClientsAdd).String(), action)
+
+	// only admins manage the apps
+	ts.User.Role = roomdb.RoleModerator
+	_, resp = ts.Client.GetHTML(listURL)
+	a.Equal(http.StatusForbidden, resp.Code, "wrong HTTP status code")
+	a.Equal(1, ts.OIDCDB.ListCallCount())
+}
+
+func TestOIDCClientsAdd(t *testing.T) {
+	ts := newSession(t)
+	a := assert.New(t)
+
+	ts.User.Role = roomdb.RoleAdmin
+
+	ts.OIDCDB.RegisterReturns(roomdb.OIDCClient{ID: 3, ClientID: "89abcdef", Name: "wiki"}, "the-secret", nil)
+
+	addURL := ts.URLTo(router.AdminOIDCClientsAdd)
+	overview := ts.URLTo(router.AdminOIDCClientsOverview)
+
+	// broken ones go back to the overview
+	for _, vals := range []url.Values{
+		{"name": {""}, "redirect_uris": {"https://wiki.example/cb"}},
+		{"name": {"wiki"}, "redirect_uris": {""}},
+		{"name": {"wiki"}, "redirect_uris": {"http://wiki.example/cb"}},
+		{"name": {"wiki"}, "redirect_uris": {"/cb"}},
+	} {
+		rec := ts.Client.PostForm(addURL, vals)
+		a.Equal(http.StatusSeeOther, rec.Code, "%v", vals)
+		a.Equal(overview.Path, rec.Header().Get("Location"))
+	}
+	a.Equal(0, ts.OIDCDB.RegisterCallCount())
+
+	rec := ts.Client.PostForm(addURL, url.Values{
+		"name":          {" wiki "},
+		"redirect_uris": {"https://wiki.example/cb\r\n\r\nhttp://localhost:8080/cb"},
+	})
+	a.Equal(http.StatusOK, rec.Code)
+
+	a.Equal(1, ts.OIDCDB.RegisterCallCount())
+	_, name, uris := ts.OIDCDB.RegisterArgsForCall(0)
+	a.Equal("wiki", name)
+	a.Equal([]string{"https://wiki.example/cb", "http://localhost:8080/cb"}, uris)
+
+	// the secret is shown once
+	body := rec.Body.String()
+	a.Contains(body, "89abcdef")
+	a.Contains(body, "the-secret")
+}
+
+func TestOIDCClientsRemove(t *testing.T) {
+	ts := newSession(t)
+	a := assert.New(t)
+
+	ts.User.Role = roomdb.RoleAdmin
+
+	removeURL := ts.URLTo(router.AdminOIDCClientsRemove)
+	overview := ts.URLTo(router.AdminOIDCClientsOverview)
+
+	rec := ts.Client.PostForm(removeURL, url.Values{"id": []string{"666"}})
+	a.Equal(http.StatusSeeOther, rec.Code)
+	a.Equal(overview.Path, rec.Header().Get("Location"))
+	webassert.HasFlashMessages(t, ts.Client, overview, "AdminOIDCClientRemoved")
+
+	a.Equal(1, ts.OIDCDB.RemoveIDCallCount())
+	_, theID := ts.OIDCDB.RemoveIDArgsForCall(0)
+	a.EqualValues(666, theID)
+
+	// moderators can't
+	ts.User.Role = roomdb.RoleModerator
+	rec = ts.Client.PostForm(removeURL, url.Values{"id": []string{"666"}})
+	a.Equal(http.StatusSeeOther, rec.Code)
+	a.Equal(1, ts.OIDCDB.RemoveIDCallCount())
+}
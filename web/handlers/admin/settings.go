@@ -8,10 +8,15 @@ import (
 	// "errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"go.mindeco.de/http/render"
+	"go.mindeco.de/log/level"
+	"go.mindeco.de/logging"
 
 	"github.com/gorilla/csrf"
+	"github.com/ssbc/go-ssb-room/v2/internal/aliases"
 	"github.com/ssbc/go-ssb-room/v2/roomdb"
 	"github.com/ssbc/go-ssb-room/v2/web"
 	weberrors "github.com/ssbc/go-ssb-room/v2/web/errors"
@@ -21,10 +26,14 @@ import (
 )
 
 type settingsHandler struct {
-	r     *render.Renderer
-	urlTo web.URLMaker
-	db    roomdb.RoomConfig
-	loc   *i18n.Helper
+	r       *render.Renderer
+	urlTo   web.URLMaker
+	flashes *weberrors.FlashHelper
+	db      roomdb.RoomConfig
+	aliases roomdb.AliasesService
+	loc     *i18n.Helper
+
+	secrets *web.Secrets
 }
 
 func (h settingsHandler) overview(w http.ResponseWriter, req *http.Request) (interface{}, error) {
@@ -40,11 +49,62 @@ func (h settingsHandler) overview(w http.ResponseWriter, req *http.Request) (int
 		return nil, fmt.Errorf("failed to retrieve current privacy mode: %w", err)
 	}
 
+	totpMandatory, err := h.db.GetTOTPMandatory(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve two-factor setting: %w", err)
+	}
+
+	openInviteLimits, err := h.db.GetOpenInviteLimits(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve open invite limits: %w", err)
+	}
+
+	memberInviteQuota, err := h.db.GetMemberInviteQuota(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve member invite quota: %w", err)
+	}
+
+	aliasPolicy, err := h.db.GetAliasPolicy(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve alias policy: %w", err)
+	}
+
+	// existing aliases aren't revoked when the policy changes, the admins can decide about them
+	allAliases, err := h.aliases.List(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list aliases: %w", err)
+	}
+
+	var aliasesAgainstPolicy []roomdb.Alias
+	for _, alias := range allAliases {
+		if aliases.CheckPolicy(alias.Name, aliasPolicy) != nil {
+			aliasesAgainstPolicy = append(aliasesAgainstPolicy, alias)
+		}
+	}
+
+	previousKeys, previousUntil := h.secrets.Previous()
+
+	flashes, err := h.flashes.GetAll(w, req)
+	if err != nil {
+		return nil, err
+	}
+
 	return map[string]interface{}{
-		"CurrentMode":     currentMode,
-		"CurrentLanguage": h.loc.ChooseTranslation(currentLanguage),
-		"PrivacyModes":    privacyModes,
-		csrf.TemplateTag:  csrf.TemplateField(req),
+		"CurrentMode":       currentMode,
+		"CurrentLanguage":   h.loc.ChooseTranslation(currentLanguage),
+		"PrivacyModes":      privacyModes,
+		"TOTPMandatory":     totpMandatory,
+		"OpenInvites":       openInviteLimits,
+		"MaxPoWBits":        roomdb.OpenInvitesMaxProofOfWorkBits,
+		"MemberInviteQuota": memberInviteQuota,
+		"AliasPolicy":       aliasPolicy,
+		"AliasesReserved":   strings.Join(aliasPolicy.Reserved, "\n"),
+		"AliasesBlocked":    strings.Join(aliasPolicy.Blocked, "\n"),
+		"AliasesAgainst":    aliasesAgainstPolicy,
+		"PreviousKeys":      previousKeys,
+		"PreviousUntil":     previousUntil,
+		"Flashes":           flashes,
+		csrf.TemplateTag:    csrf.TemplateField(req),
 	}, nil
 }
 
@@ -95,6 +155,200 @@ func (h settingsHandler) setPrivacy(w http.ResponseWriter, req *http.Request) {
 	h.redirect(router.AdminSettings, w, req)
 }
 
+func (h settingsHandler) setTOTPMandatory(w http.ResponseWriter, req *http.Request) {
+	if !h.verifyPostRequirements(w, req) {
+		return
+	}
+	// handles error cases & make sures the member is an admin
+	currentMember := h.getMember(w, req)
+	if currentMember == nil {
+		return
+	}
+
+	mandatory := req.Form.Get("totp_mandatory") == "true"
+
+	err := h.db.SetTOTPMandatory(req.Context(), mandatory)
+	if err != nil {
+		h.r.Error(w, req, http.StatusBadRequest, fmt.Errorf("something went wrong when setting the two-factor requirement: %w", err))
+		return
+	}
+
+	h.redirect(router.AdminSettings, w, req)
+}
+
+// setOpenInviteLimits changes how hard it is to create invites while the room is open
+func (h settingsHandler) setOpenInviteLimits(w http.ResponseWriter, req *http.Request) {
+	if !h.verifyPostRequirements(w, req) {
+		return
+	}
+	// handles error cases & make sures the member is an admin
+	currentMember := h.getMember(w, req)
+	if currentMember == nil {
+		return
+	}
+
+	settingsURL := h.urlTo(router.AdminSettings).Path
+
+	bits, err := strconv.ParseUint(req.Form.Get("pow_bits"), 10, 32)
+	if err != nil || bits > roomdb.OpenInvitesMaxProofOfWorkBits {
+		err = weberrors.ErrBadRequest{Where: "pow_bits", Details: fmt.Errorf("needs to be between 0 and %d", roomdb.OpenInvitesMaxProofOfWorkBits)}
+		h.r.Error(w, req, http.StatusBadRequest, weberrors.ErrRedirect{Path: settingsURL, Reason: err})
+		return
+	}
+
+	perAddress, err := strconv.ParseUint(req.Form.Get("per_address"), 10, 32)
+	if err != nil {
+		err = weberrors.ErrBadRequest{Where: "per_address", Details: err}
+		h.r.Error(w, req, http.StatusBadRequest, weberrors.ErrRedirect{Path: settingsURL, Reason: err})
+		return
+	}
+
+	err = h.db.SetOpenInviteLimits(req.Context(), roomdb.OpenInviteLimits{
+		ProofOfWorkBits: uint(bits),
+		PerAddress:      uint(perAddress),
+	})
+	if err != nil {
+		h.r.Error(w, req, http.StatusInternalServerError, weberrors.ErrRedirect{Path: settingsURL, Reason: err})
+		return
+	}
+
+	h.flashes.AddMessage(w, req, "OpenInvitesSaved")
+	h.redirect(router.AdminSettings, w, req)
+}
+
+// setMemberInviteQuota changes how many unused invites each member can have at a time
+func (h settingsHandler) setMemberInviteQuota(w http.ResponseWriter, req *http.Request) {
+	if !h.verifyPostRequirements(w, req) {
+		return
+	}
+	// handles error cases & make sures the member is an admin
+	currentMember := h.getMember(w, req)
+	if currentMember == nil {
+		return
+	}
+
+	settingsURL := h.urlTo(router.AdminSettings).Path
+
+	quota, err := strconv.ParseUint(req.Form.Get("quota"), 10, 32)
+	if err != nil {
+		err = weberrors.ErrBadRequest{Where: "quota", Details: err}
+		h.r.Error(w, req, http.StatusBadRequest, weberrors.ErrRedirect{Path: settingsURL, Reason: err})
+		return
+	}
+
+	err = h.db.SetMemberInviteQuota(req.Context(), uint(quota))
+	if err != nil {
+		h.r.Error(w, req, http.StatusInternalServerError, weberrors.ErrRedirect{Path: settingsURL, Reason: err})
+		return
+	}
+
+	h.flashes.AddMessage(w, req, "MemberInviteQuotaSaved")
+	h.redirect(router.AdminSettings, w, req)
+}
+
+// setAliasPolicy changes which aliases can be registered from now on
+func (h settingsHandler) setAliasPolicy(w http.ResponseWriter, req *http.Request) {
+	if !h.verifyPostRequirements(w, req) {
+		return
+	}
+	// handles error cases & make sures the member is an admin
+	currentMember := h.getMember(w, req)
+	if currentMember == nil {
+		return
+	}
+
+	settingsURL := h.urlTo(router.AdminSettings).Path
+
+	minLength, err := strconv.ParseUint(req.Form.Get("min_length"), 10, 32)
+	if err != nil || minLength > 63 {
+		err = weberrors.ErrBadRequest{Where: "min_length", Details: fmt.Errorf("needs to be between 0 and 63")}
+		h.r.Error(w, req, http.StatusBadRequest, weberrors.ErrRedirect{Path: settingsURL, Reason: err})
+		return
+	}
+
+	perMember, err := strconv.ParseUint(req.Form.Get("per_member"), 10, 32)
+	if err != nil {
+		err = weberrors.ErrBadRequest{Where: "per_member", Details: err}
+		h.r.Error(w, req, http.StatusBadRequest, weberrors.ErrRedirect{Path: settingsURL, Reason: err})
+		return
+	}
+
+	policy := roomdb.AliasPolicy{
+		Reserved:     splitLines(req.Form.Get("reserved")),
+		Blocked:      splitLines(req.Form.Get("blocked")),
+		MinLength:    uint(minLength),
+		AllowHyphens: req.Form.Get("allow_hyphens") == "true",
+		PerMember:    uint(perMember),
+	}
+
+	err = aliases.ValidatePolicy(policy)
+	if err != nil {
+		err = weberrors.ErrBadRequest{Where: "blocked", Details: err}
+		h.r.Error(w, req, http.StatusBadRequest, weberrors.ErrRedirect{Path: settingsURL, Reason: err})
+		return
+	}
+
+	err = h.db.SetAliasPolicy(req.Context(), policy)
+	if err != nil {
+		h.r.Error(w, req, http.StatusInternalServerError, weberrors.ErrRedirect{Path: settingsURL, Reason: err})
+		return
+	}
+
+	// point the admins to the aliases that were registered before and don't fit anymore
+	allAliases, err := h.aliases.List(req.Context())
+	if err != nil {
+		h.r.Error(w, req, http.StatusInternalServerError, weberrors.ErrRedirect{Path: settingsURL, Reason: err})
+		return
+	}
+
+	msg := "AliasPolicySaved"
+	for _, alias := range allAliases {
+		if aliases.CheckPolicy(alias.Name, policy) != nil {
+			msg = "AliasPolicySavedExistingAgainst"
+			break
+		}
+	}
+
+	h.flashes.AddMessage(w, req, msg)
+	h.redirect(router.AdminSettings, w, req)
+}
+
+// splitLines returns the trimmed, lowercased and non-empty lines of a textarea
+func splitLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.ToLower(strings.TrimSpace(line))
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// rotateSecrets creates new keys for the cookies and csrf tokens.
+// The current ones are still accepted for a while, so that nobody is signed out by it.
+func (h settingsHandler) rotateSecrets(w http.ResponseWriter, req *http.Request) {
+	if !h.verifyPostRequirements(w, req) {
+		return
+	}
+	// handles error cases & make sures the member is an admin
+	currentMember := h.getMember(w, req)
+	if currentMember == nil {
+		return
+	}
+
+	defer h.redirect(router.AdminSettings, w, req)
+
+	err := h.secrets.Rotate(web.DefaultSecretsGracePeriod)
+	if err != nil {
+		h.flashes.AddError(w, req, err)
+		return
+	}
+
+	level.Info(logging.FromContext(req.Context())).Log("event", "secrets rotated", "member", currentMember.PubKey.ShortSigil())
+	h.flashes.AddMessage(w, req, "SecretsRotated")
+}
+
 /* common-use functions */
 
 func (h settingsHandler) getMember(w http.ResponseWriter, req *http.Request) *roomdb.Member {
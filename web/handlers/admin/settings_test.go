@@ -6,6 +6,7 @@ package admin
 
 import (
 	"net/http"
+	"net/url"
 	"strings"
 	"testing"
 
@@ -14,6 +15,7 @@ import (
 
 	"github.com/ssbc/go-ssb-room/v2/roomdb"
 	"github.com/ssbc/go-ssb-room/v2/web/router"
+	"github.com/ssbc/go-ssb-room/v2/web/webassert"
 )
 
 func TestSettingsOverview(t *testing.T) {
@@ -59,6 +61,12 @@ func TestSettingsOverview(t *testing.T) {
 	// (english translation will only be the name of the label, due to testing suite is set up atm)
 	a.Equal("LanguageName", strings.TrimSpace(languageFormContainer.Find("summary").Text()))
 
+	// two-factor is optional by default, the form offers to require it
+	totpForm := html.Find("#change-totp-mandatory")
+	a.Equal(1, totpForm.Length())
+	totpValue, _ := totpForm.Find("input[name=totp_mandatory]").Attr("value")
+	a.Equal("true", totpValue)
+
 	testDisabledBehaviour := func() {
 		settingsURL := ts.URLTo(router.AdminSettings)
 		html, resp := ts.Client.GetHTML(settingsURL)
@@ -86,6 +94,13 @@ func TestSettingsOverview(t *testing.T) {
 		// the input should be disabled
 		_, disabled = inputs.Attr("disabled")
 		a.True(disabled)
+
+		// and the two-factor requirement can't be changed either
+		a.Equal(0, html.Find("#change-totp-mandatory").Length())
+		inputs = html.Find("#two-factor-container input")
+		a.Equal(1, inputs.Length())
+		_, disabled = inputs.Attr("disabled")
+		a.True(disabled)
 	}
 
 	/* Now: verify that moderators cannot make room settings changes */
@@ -102,3 +117,232 @@ func TestSettingsOverview(t *testing.T) {
 	}
 	testDisabledBehaviour()
 }
+
+func TestSettingsSetTOTPMandatory(t *testing.T) {
+	ts := newSession(t)
+	a := assert.New(t)
+
+	setURL := ts.URLTo(router.AdminSettingsSetTOTPMandatory)
+	settingsURL := ts.URLTo(router.AdminSettings)
+
+	// moderators can't change it
+	ts.User = roomdb.Member{
+		ID:   7331,
+		Role: roomdb.RoleModerator,
+	}
+	resp := ts.Client.PostForm(setURL, url.Values{"totp_mandatory": []string{"true"}})
+	a.NotEqual(http.StatusSeeOther, resp.Code)
+	a.Equal(0, ts.ConfigDB.SetTOTPMandatoryCallCount())
+
+	ts.User = roomdb.Member{
+		ID:   1234,
+		Role: roomdb.RoleAdmin,
+	}
+	resp = ts.Client.PostForm(setURL, url.Values{"totp_mandatory": []string{"true"}})
+	a.Equal(http.StatusSeeOther, resp.Code)
+	location, err := url.Parse(resp.Header().Get("Location"))
+	a.NoError(err)
+	a.Equal(settingsURL.Path, location.Path)
+
+	a.Equal(1, ts.ConfigDB.SetTOTPMandatoryCallCount())
+	_, mandatory := ts.ConfigDB.SetTOTPMandatoryArgsForCall(0)
+	a.True(mandatory)
+
+	// the overview shows the current state
+	ts.ConfigDB.GetTOTPMandatoryReturns(true, nil)
+	html, _ := ts.Client.GetHTML(settingsURL)
+	totpValue, _ := html.Find("#change-totp-mandatory input[name=totp_mandatory]").Attr("value")
+	a.Equal("false", totpValue)
+}
+
+func TestSettingsSetOpenInviteLimits(t *testing.T) {
+	ts := newSession(t)
+	a := assert.New(t)
+
+	setURL := ts.URLTo(router.AdminSettingsSetOpenInvites)
+	settingsURL := ts.URLTo(router.AdminSettings)
+
+	vals := url.Values{
+		"pow_bits":    []string{"18"},
+		"per_address": []string{"3"},
+	}
+
+	// moderators can't change it
+	resp := ts.Client.PostForm(setURL, vals)
+	a.NotEqual(http.StatusSeeOther, resp.Code)
+	a.Equal(0, ts.ConfigDB.SetOpenInviteLimitsCallCount())
+
+	ts.User.Role = roomdb.RoleAdmin
+
+	resp = ts.Client.PostForm(setURL, vals)
+	a.Equal(http.StatusSeeOther, resp.Code)
+	a.Equal(settingsURL.String(), resp.Header().Get("Location"))
+	webassert.HasFlashMessages(t, ts.Client, settingsURL, "OpenInvitesSaved")
+
+	a.Equal(1, ts.ConfigDB.SetOpenInviteLimitsCallCount())
+	_, limits := ts.ConfigDB.SetOpenInviteLimitsArgsForCall(0)
+	a.Equal(roomdb.OpenInviteLimits{ProofOfWorkBits: 18, PerAddress: 3}, limits)
+
+	for _, bad := range []url.Values{
+		{"pow_bits": []string{"25"}, "per_address": []string{"3"}},
+		{"pow_bits": []string{"-1"}, "per_address": []string{"3"}},
+		{"pow_bits": []string{"16"}, "per_address": []string{"many"}},
+	} {
+		resp = ts.Client.PostForm(setURL, bad)
+		a.Equal(http.StatusSeeOther, resp.Code)
+		webassert.HasFlashMessages(t, ts.Client, settingsURL, "ErrorBadRequest")
+	}
+	a.Equal(1, ts.ConfigDB.SetOpenInviteLimitsCallCount())
+
+	// the overview shows the current values
+	ts.ConfigDB.GetOpenInviteLimitsReturns(roomdb.OpenInviteLimits{ProofOfWorkBits: 12, PerAddress: 7}, nil)
+	html, _ := ts.Client.GetHTML(settingsURL)
+	a.Equal("12", html.Find("#open-invites-pow-bits").AttrOr("value", ""))
+	a.Equal("7", html.Find("#open-invites-per-address").AttrOr("value", ""))
+}
+
+func TestSettingsSetMemberInviteQuota(t *testing.T) {
+	ts := newSession(t)
+	a := assert.New(t)
+
+	setURL := ts.URLTo(router.AdminSettingsSetMemberInvites)
+	settingsURL := ts.URLTo(router.AdminSettings)
+
+	vals := url.Values{"quota": []string{"5"}}
+
+	// moderators can't change it
+	resp := ts.Client.PostForm(setURL, vals)
+	a.NotEqual(http.StatusSeeOther, resp.Code)
+	a.Equal(0, ts.ConfigDB.SetMemberInviteQuotaCallCount())
+
+	ts.User.Role = roomdb.RoleAdmin
+
+	resp = ts.Client.PostForm(setURL, vals)
+	a.Equal(http.StatusSeeOther, resp.Code)
+	a.Equal(settingsURL.String(), resp.Header().Get("Location"))
+	webassert.HasFlashMessages(t, ts.Client, settingsURL, "MemberInviteQuotaSaved")
+
+	a.Equal(1, ts.ConfigDB.SetMemberInviteQuotaCallCount())
+	_, quota := ts.ConfigDB.SetMemberInviteQuotaArgsForCall(0)
+	a.EqualValues(5, quota)
+
+	for _, bad := range []string{"-1", "lots"} {
+		resp = ts.Client.PostForm(setURL, url.Values{"quota": []string{bad}})
+		a.Equal(http.StatusSeeOther, resp.Code)
+		webassert.HasFlashMessages(t, ts.Client, settingsURL, "ErrorBadRequest")
+	}
+	a.Equal(1, ts.ConfigDB.SetMemberInviteQuotaCallCount())
+
+	// the overview shows the current value
+	ts.ConfigDB.GetMemberInviteQuotaReturns(9, nil)
+	html, _ := ts.Client.GetHTML(settingsURL)
+	a.Equal("9", html.Find("#member-invites-quota").AttrOr("value", ""))
+}
+
+func TestSettingsSetAliasPolicy(t *testing.T) {
+	ts := newSession(t)
+	a := assert.New(t)
+
+	setURL := ts.URLTo(router.AdminSettingsSetAliasPolicy)
+	settingsURL := ts.URLTo(router.AdminSettings)
+
+	vals := url.Values{
+		"reserved":      []string{"Admin\r\n  www \n\n"},
+		"blocked":       []string{"badword\n/^x+$/"},
+		"min_length":    []string{"3"},
+		"per_member":    []string{"2"},
+		"allow_hyphens": []string{"true"},
+	}
+
+	// moderators can't change it
+	resp := ts.Client.PostForm(setURL, vals)
+	a.NotEqual(http.StatusSeeOther, resp.Code)
+	a.Equal(0, ts.ConfigDB.SetAliasPolicyCallCount())
+
+	ts.User.Role = roomdb.RoleAdmin
+
+	resp = ts.Client.PostForm(setURL, vals)
+	a.Equal(http.StatusSeeOther, resp.Code)
+	a.Equal(settingsURL.String(), resp.Header().Get("Location"))
+	webassert.HasFlashMessages(t, ts.Client, settingsURL, "AliasPolicySaved")
+
+	a.Equal(1, ts.ConfigDB.SetAliasPolicyCallCount())
+	_, policy := ts.ConfigDB.SetAliasPolicyArgsForCall(0)
+	a.Equal(roomdb.AliasPolicy{
+		Reserved:     []string{"admin", "www"},
+		Blocked:      []string{"badword", "/^x+$/"},
+		MinLength:    3,
+		AllowHyphens: true,
+		PerMember:    2,
+	}, policy)
+
+	for _, bad := range []url.Values{
+		{"min_length": []string{"64"}, "per_member": []string{"0"}},
+		{"min_length": []string{"0"}, "per_member": []string{"-1"}},
+		{"min_length": []string{"0"}, "per_member": []string{"0"}, "blocked": []string{"/(/"}},
+	} {
+		resp = ts.Client.PostForm(setURL, bad)
+		a.Equal(http.StatusSeeOther, resp.Code)
+		webassert.HasFlashMessages(t, ts.Client, settingsURL, "ErrorBadRequest")
+	}
+	a.Equal(1, ts.ConfigDB.SetAliasPolicyCallCount())
+
+	// existing aliases that don't fit are listed
+	ts.AliasesDB.ListReturns([]roomdb.Alias{
+		{ID: 1, Name: "fine"},
+		{ID: 2, Name: "xxx"},
+	}, nil)
+
+	resp = ts.Client.PostForm(setURL, vals)
+	a.Equal(http.StatusSeeOther, resp.Code)
+	webassert.HasFlashMessages(t, ts.Client, settingsURL, "AliasPolicySavedExistingAgainst")
+
+	ts.ConfigDB.GetAliasPolicyReturns(policy, nil)
+	html, _ := ts.Client.GetHTML(settingsURL)
+	a.Equal("3", html.Find("#alias-policy-min-length").AttrOr("value", ""))
+	a.Equal("admin\nwww", html.Find("#alias-policy-reserved").Text())
+	against := html.Find("#aliases-against-policy li")
+	a.Equal(1, against.Length())
+	a.Contains(against.Text(), "xxx")
+}
+
+func TestSettingsRotateSecrets(t *testing.T) {
+	ts := newSession(t)
+	a := assert.New(t)
+
+	rotateURL := ts.URLTo(router.AdminSettingsRotateSecrets)
+	settingsURL := ts.URLTo(router.AdminSettings)
+
+	html, resp := ts.Client.GetHTML(settingsURL)
+	a.Equal(http.StatusOK, resp.Code)
+	a.Equal(0, html.Find("#rotate-secrets").Length(), "only admins can rotate")
+	a.Equal("SecretsNoPrevious", strings.TrimSpace(html.Find("#secrets-state").Text()))
+
+	// moderators can't rotate the keys
+	resp = ts.Client.PostForm(rotateURL, url.Values{})
+	a.NotEqual(http.StatusSeeOther, resp.Code)
+	previous, _ := ts.Secrets.Previous()
+	a.Equal(0, previous)
+
+	ts.User = roomdb.Member{
+		ID:   1234,
+		Role: roomdb.RoleAdmin,
+	}
+	resp = ts.Client.PostForm(rotateURL, url.Values{})
+	a.Equal(http.StatusSeeOther, resp.Code)
+	location, err := url.Parse(resp.Header().Get("Location"))
+	a.NoError(err)
+	a.Equal(settingsURL.Path, location.Path)
+
+	previous, until := ts.Secrets.Previous()
+	a.Equal(1, previous)
+	a.False(until.IsZero())
+
+	// the flash cookie is made with the new keys and still works
+	webassert.HasFlashMessages(t, ts.Client, settingsURL, "SecretsRotated")
+
+	html, resp = ts.Client.GetHTML(settingsURL)
+	a.Equal(http.StatusOK, resp.Code)
+	a.Equal(1, html.Find("#rotate-secrets").Length())
+	a.Contains(html.Find("#secrets-state").Text(), "SecretsPreviousUntil")
+}
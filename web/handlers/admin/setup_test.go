@@ -47,13 +47,21 @@ type testSession struct {
 	URLTo web.URLMaker
 
 	AliasesDB    *mockdb.FakeAliasesService
+	APITokensDB  *mockdb.FakeAPITokensService
+	AuthWithSSB  *mockdb.FakeAuthWithSSBService
 	ConfigDB     *mockdb.FakeRoomConfig
 	DeniedKeysDB *mockdb.FakeDeniedKeysService
 	FallbackDB   *mockdb.FakeAuthFallbackService
 	InvitesDB    *mockdb.FakeInvitesService
+	LockoutsDB   *mockdb.FakeLoginLockoutService
 	NoticeDB     *mockdb.FakeNoticesService
 	MembersDB    *mockdb.FakeMembersService
+	OIDCDB       *mockdb.FakeOIDCClientsService
 	PinnedDB     *mockdb.FakePinnedNoticesService
+	RequestsDB   *mockdb.FakeInviteRequestsService
+	WebSessions  *mockdb.FakeWebSessionsService
+
+	Secrets *web.Secrets
 
 	User roomdb.Member
 
@@ -76,6 +84,8 @@ func newSession(t *testing.T) *testSession {
 
 	// fake dbs
 	ts.AliasesDB = new(mockdb.FakeAliasesService)
+	ts.APITokensDB = new(mockdb.FakeAPITokensService)
+	ts.AuthWithSSB = new(mockdb.FakeAuthWithSSBService)
 	ts.ConfigDB = new(mockdb.FakeRoomConfig)
 	// default mode for all tests
 	ts.ConfigDB.GetPrivacyModeReturns(roomdb.ModeCommunity, nil)
@@ -86,6 +96,10 @@ func newSession(t *testing.T) *testSession {
 	ts.PinnedDB = new(mockdb.FakePinnedNoticesService)
 	ts.NoticeDB = new(mockdb.FakeNoticesService)
 	ts.InvitesDB = new(mockdb.FakeInvitesService)
+	ts.RequestsDB = new(mockdb.FakeInviteRequestsService)
+	ts.LockoutsDB = new(mockdb.FakeLoginLockoutService)
+	ts.OIDCDB = new(mockdb.FakeOIDCClientsService)
+	ts.WebSessions = new(mockdb.FakeWebSessionsService)
 
 	log, _ := logtest.KitLogger("admin", t)
 	ts.RoomState = roomstate.NewManager(log)
@@ -138,6 +152,11 @@ func newSession(t *testing.T) *testSession {
 		t.Fatal(err)
 	}
 
+	ts.Secrets, err = web.LoadOrCreateSecrets(testRepo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	authKey := make([]byte, 64)
 	rand.Read(authKey)
 	encKey := make([]byte, 32)
@@ -208,15 +227,22 @@ func newSession(t *testing.T) *testSession {
 		ts.RoomState,
 		flashHelper,
 		locHelper,
+		ts.Secrets,
 		Databases{
-			Aliases:       ts.AliasesDB,
-			AuthFallback:  ts.FallbackDB,
-			Config:        ts.ConfigDB,
-			DeniedKeys:    ts.DeniedKeysDB,
-			Members:       ts.MembersDB,
-			Invites:       ts.InvitesDB,
-			Notices:       ts.NoticeDB,
-			PinnedNotices: ts.PinnedDB,
+			Aliases:        ts.AliasesDB,
+			APITokens:      ts.APITokensDB,
+			AuthFallback:   ts.FallbackDB,
+			AuthWithSSB:    ts.AuthWithSSB,
+			Config:         ts.ConfigDB,
+			DeniedKeys:     ts.DeniedKeysDB,
+			Members:        ts.MembersDB,
+			Invites:        ts.InvitesDB,
+			InviteRequests: ts.RequestsDB,
+			LoginLockouts:  ts.LockoutsDB,
+			Notices:        ts.NoticeDB,
+			OIDCClients:    ts.OIDCDB,
+			PinnedNotices:  ts.PinnedDB,
+			WebSessions:    ts.WebSessions,
 		},
 	)
 
@@ -55,8 +55,9 @@ func (h aliasHandler) resolve(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	name := mux.Vars(req)["alias"]
-	if name == "" && !aliases.IsValid(name) {
+	// the alias can be asked for in its Unicode or ASCII form, the roomdb has the ASCII one
+	name, err := aliases.Normalize(mux.Vars(req)["alias"])
+	if err != nil {
 		ar.SendError(fmt.Errorf("invalid alias"))
 		return
 	}
@@ -67,6 +68,8 @@ func (h aliasHandler) resolve(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	// the owner signed the Unicode form
+	alias.Name = aliases.ToUnicode(alias.Name)
 	ar.SendConfirmation(alias)
 }
 
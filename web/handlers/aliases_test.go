@@ -105,6 +105,48 @@ func TestAliasResolve(t *testing.T) {
 	a.Equal(http.StatusInternalServerError, resp.Code)
 }
 
+func TestAliasResolveUnicode(t *testing.T) {
+	ts := setup(t)
+
+	a := assert.New(t)
+	r := require.New(t)
+
+	feed, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte{'F'}, 32), refs.RefAlgoFeedSSB1)
+	r.NoError(err)
+
+	// stored as punycode
+	ts.AliasesDB.ResolveReturns(roomdb.Alias{
+		ID:        1,
+		Name:      "xn--bcher-kva",
+		Feed:      feed,
+		Signature: bytes.Repeat([]byte{'S'}, 32),
+	}, nil)
+
+	routes := router.CompleteApp()
+
+	// both forms can be resolved
+	for i, name := range []string{"bücher", "xn--bcher-kva"} {
+		jsonURL, err := routes.Get(router.CompleteAliasResolve).URL("alias", name)
+		r.NoError(err)
+
+		q := jsonURL.Query()
+		q.Set("encoding", "json")
+		jsonURL.RawQuery = q.Encode()
+
+		resp := ts.Client.GetBody(jsonURL)
+		a.Equal(http.StatusOK, resp.Code)
+
+		_, resolved := ts.AliasesDB.ResolveArgsForCall(i)
+		a.Equal("xn--bcher-kva", resolved)
+
+		// the confirmation has the form that was signed
+		var ar aliasJSONResponse
+		err = json.NewDecoder(resp.Body).Decode(&ar)
+		r.NoError(err)
+		a.Equal("bücher", ar.Alias)
+	}
+}
+
 func TestAliasResolveOnAndroidChrome(t *testing.T) {
 	ts := setup(t)
 
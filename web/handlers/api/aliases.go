@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ssbc/go-ssb-room/v2/internal/aliases"
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web/members"
+)
+
+func (h handler) listAliases(req *http.Request) (interface{}, error) {
+	lst, err := h.dbs.Aliases.List(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]aliasJSON, len(lst))
+	for i, a := range lst {
+		items[i] = aliasJSON{
+			ID:          a.ID,
+			Name:        a.Name,
+			DisplayName: aliases.ToUnicode(a.Name),
+			Feed:        a.Feed.String(),
+			URL:         h.netInfo.URLForAlias(a.Name),
+		}
+	}
+
+	return paginate(req, items)
+}
+
+func (h handler) revokeAlias(req *http.Request) (interface{}, error) {
+	ctx := req.Context()
+	name, err := aliases.Normalize(mux.Vars(req)["name"])
+	if err != nil {
+		return nil, apiError{http.StatusBadRequest, err.Error()}
+	}
+
+	alias, err := h.dbs.Aliases.Resolve(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// like in the dashboard, members can revoke their own aliases and admins all of them
+	current := members.FromContext(ctx)
+	if !alias.Feed.Equal(current.PubKey) && current.Role != roomdb.RoleAdmin {
+		return nil, apiError{http.StatusForbidden, "not your alias and not an admin"}
+	}
+
+	return nil, h.dbs.Aliases.Revoke(ctx, name)
+}
@@ -0,0 +1,320 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+// Package api implements the versioned JSON API of the room, under /api/v1.
+// It covers what the admin dashboard can do, for scripts and other frontends.
+//
+// Requests are authenticated like the dashboard, with a session cookie or with an api token of an admin or moderator.
+// The OpenAPI document at /api/v1/openapi.json is generated from the list of endpoints in this package.
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"go.mindeco.de/log/level"
+	"go.mindeco.de/logging"
+
+	"github.com/ssbc/go-ssb-room/v2/internal/network"
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/roomstate"
+	"github.com/ssbc/go-ssb-room/v2/web"
+	weberrors "github.com/ssbc/go-ssb-room/v2/web/errors"
+	"github.com/ssbc/go-ssb-room/v2/web/members"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+)
+
+// Databases is an option struct that encapsulates the required database services
+type Databases struct {
+	Aliases       roomdb.AliasesService
+	AuthFallback  roomdb.AuthFallbackService
+	Config        roomdb.RoomConfig
+	DeniedKeys    roomdb.DeniedKeysService
+	Invites       roomdb.InvitesService
+	Members       roomdb.MembersService
+	Notices       roomdb.NoticesService
+	PinnedNotices roomdb.PinnedNoticesService
+}
+
+// endpoint describes one route of the api.
+// The same description is used to serve the requests and to generate the OpenAPI document.
+type endpoint struct {
+	// route is the name in the router package
+	route   string
+	summary string
+
+	// paginated endpoints accept the page and limit query parameters and return a page of response values
+	paginated bool
+
+	// request and response are values of the types that are sent and returned, only used for the OpenAPI document.
+	// A nil request means the endpoint doesn't take a body, a nil response that it returns 204 No Content.
+	request  interface{}
+	response interface{}
+
+	// status is returned on success, http.StatusOK if not set
+	status int
+
+	handle func(req *http.Request) (interface{}, error)
+}
+
+type handler struct {
+	netInfo   network.ServerEndpointDetails
+	roomState *roomstate.Manager
+	urlTo     web.URLMaker
+
+	dbs Databases
+}
+
+// Handler returns the http.Handler for all the routes under /api/v1
+func Handler(
+	netInfo network.ServerEndpointDetails,
+	roomState *roomstate.Manager,
+	dbs Databases,
+) http.Handler {
+	m := router.CompleteApp()
+
+	h := handler{
+		netInfo:   netInfo,
+		roomState: roomState,
+		urlTo:     web.NewURLTo(m, netInfo),
+
+		dbs: dbs,
+	}
+
+	endpoints := h.endpoints()
+	for _, ep := range endpoints {
+		m.Get(ep.route).Handler(h.serve(ep))
+	}
+
+	doc := openAPIDocument(m, h.urlTo, endpoints)
+	m.Get(router.APIOpenAPI).HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		sendJSON(w, req, http.StatusOK, doc)
+	})
+
+	m.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		sendError(w, req, apiError{http.StatusNotFound, "no such endpoint"})
+	})
+	m.MethodNotAllowedHandler = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		sendError(w, req, apiError{http.StatusMethodNotAllowed, "method not allowed"})
+	})
+
+	return m
+}
+
+// endpoints lists all the routes of the api, except for the OpenAPI document itself.
+// They all need a signed-in member, what they can do is checked by the handlers.
+func (h handler) endpoints() []endpoint {
+	return []endpoint{
+		{route: router.APIStats, summary: "Numbers about the room and who is connected right now", response: statsJSON{}, handle: h.stats},
+
+		{route: router.APIMembersList, summary: "List the members, the newest first", paginated: true, response: memberJSON{}, handle: h.listMembers},
+		{route: router.APIMembersAdd, summary: "Add a member", request: addMemberJSON{}, response: memberJSON{}, status: http.StatusCreated, handle: h.addMember},
+		{route: router.APIMembersGet, summary: "Get a member", response: memberJSON{}, handle: h.getMember},
+		{route: router.APIMembersSetRole, summary: "Change the role of a member (admins only)", request: setRoleJSON{}, response: memberJSON{}, handle: h.setMemberRole},
+		{route: router.APIMembersRemove, summary: "Remove a member", handle: h.removeMember},
+		{route: router.APIMembersPasswordReset, summary: "Create a link for the member to set a new password (admins only)", response: passwordResetJSON{}, status: http.StatusCreated, handle: h.createPasswordReset},
+
+		{route: router.APIInvitesList, summary: "List the active invites", paginated: true, response: inviteJSON{}, handle: h.listInvites},
+		{route: router.APIInvitesCreate, summary: "Create an invite", response: createdInviteJSON{}, status: http.StatusCreated, handle: h.createInvite},
+		{route: router.APIInvitesGet, summary: "Get an invite", response: inviteJSON{}, handle: h.getInvite},
+		{route: router.APIInvitesRevoke, summary: "Revoke an invite", handle: h.revokeInvite},
+
+		{route: router.APIDeniedKeysList, summary: "List the keys that are not allowed into the room", paginated: true, response: deniedKeyJSON{}, handle: h.listDeniedKeys},
+		{route: router.APIDeniedKeysAdd, summary: "Deny a key", request: addDeniedKeyJSON{}, response: deniedKeyJSON{}, status: http.StatusCreated, handle: h.addDeniedKey},
+		{route: router.APIDeniedKeysGet, summary: "Get a denied key", response: deniedKeyJSON{}, handle: h.getDeniedKey},
+		{route: router.APIDeniedKeysRemove, summary: "Allow a denied key again", handle: h.removeDeniedKey},
+
+		{route: router.APIAliasesList, summary: "List the registered aliases", paginated: true, response: aliasJSON{}, handle: h.listAliases},
+		{route: router.APIAliasesRevoke, summary: "Revoke an alias (your own, or any as an admin)", handle: h.revokeAlias},
+
+		{route: router.APINoticesList, summary: "List the pinned notices with all their translations", response: []pinnedNoticeJSON{}, handle: h.listNotices},
+		{route: router.APINoticesGet, summary: "Get a notice", response: noticeJSON{}, handle: h.getNotice},
+		{route: router.APINoticesSave, summary: "Change a notice", request: noticeInputJSON{}, response: noticeJSON{}, handle: h.saveNotice},
+		{route: router.APINoticesAddTranslation, summary: "Add a translation to a pinned notice", request: noticeInputJSON{}, response: noticeJSON{}, status: http.StatusCreated, handle: h.addNoticeTranslation},
+
+		{route: router.APISettingsGet, summary: "Get the settings of the room", response: settingsJSON{}, handle: h.getSettings},
+		{route: router.APISettingsUpdate, summary: "Change the settings of the room (admins only), fields that are left out are not changed", request: updateSettingsJSON{}, response: settingsJSON{}, handle: h.updateSettings},
+	}
+}
+
+// serve wraps the handle function of the endpoint with authentication and the encoding of the response
+func (h handler) serve(ep endpoint) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if members.FromContext(req.Context()) == nil {
+			sendError(w, req, apiError{http.StatusUnauthorized, "not signed in"})
+			return
+		}
+
+		resp, err := ep.handle(req)
+		if err != nil {
+			sendError(w, req, err)
+			return
+		}
+
+		if resp == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		status := ep.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		sendJSON(w, req, status, resp)
+	})
+}
+
+// apiError is returned by the handlers if the request can't be done, with the status code that should be used for it
+type apiError struct {
+	code    int
+	message string
+}
+
+func (e apiError) Error() string { return e.message }
+
+func errBadRequest(format string, args ...interface{}) error {
+	return apiError{http.StatusBadRequest, fmt.Sprintf(format, args...)}
+}
+
+var errNotAdmin = apiError{http.StatusForbidden, "only admins can do this"}
+
+type errorJSON struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+}
+
+// sendError picks the status code for the error and sends it as errorJSON.
+// Unexpected errors are logged and not shown to the client.
+func sendError(w http.ResponseWriter, req *http.Request, err error) {
+	var (
+		code = http.StatusInternalServerError
+		msg  = "internal error"
+
+		ae apiError
+		br weberrors.ErrBadRequest
+		fb weberrors.ErrForbidden
+		aa roomdb.ErrAlreadyAdded
+		at roomdb.ErrAliasTaken
+	)
+
+	switch {
+	case errors.As(err, &ae):
+		code, msg = ae.code, ae.message
+
+	case errors.Is(err, roomdb.ErrNotFound):
+		code, msg = http.StatusNotFound, "not found"
+
+	case errors.Is(err, weberrors.ErrNotAuthorized):
+		code, msg = http.StatusForbidden, "not allowed"
+
+	case errors.As(err, &fb):
+		code, msg = http.StatusForbidden, fb.Error()
+
+	case errors.As(err, &br):
+		code, msg = http.StatusBadRequest, br.Error()
+
+	case errors.As(err, &aa):
+		code, msg = http.StatusConflict, aa.Error()
+
+	case errors.As(err, &at):
+		code, msg = http.StatusConflict, at.Error()
+
+	default:
+		level.Error(logging.FromContext(req.Context())).Log("event", "api request failed", "path", req.URL.Path, "err", err)
+	}
+
+	sendJSON(w, req, code, errorJSON{Status: "error", Error: msg})
+}
+
+func sendJSON(w http.ResponseWriter, req *http.Request, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	err := json.NewEncoder(w).Encode(v)
+	if err != nil {
+		level.Warn(logging.FromContext(req.Context())).Log("event", "sending json response failed", "err", err)
+	}
+}
+
+// decodeBody reads the json body of the request into v. Unknown fields are rejected, to catch typos.
+func decodeBody(req *http.Request, v interface{}) error {
+	dec := json.NewDecoder(req.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return errBadRequest("invalid json body: %s", err)
+	}
+	return nil
+}
+
+// idFromPath returns the {id} part of the path as a number
+func idFromPath(req *http.Request) (int64, error) {
+	id, err := strconv.ParseInt(mux.Vars(req)["id"], 10, 64)
+	if err != nil {
+		return -1, errBadRequest("invalid id: %s", err)
+	}
+	return id, nil
+}
+
+// how many elements a page has, by default and at most
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+type pageJSON struct {
+	Items interface{} `json:"items"`
+	Page  int         `json:"page"`
+	Limit int         `json:"limit"`
+	Total int         `json:"total"`
+}
+
+// paginate returns the part of the items slice that was requested by the page and limit query parameters
+func paginate(req *http.Request, items interface{}) (pageJSON, error) {
+	qry := req.URL.Query()
+
+	p := pageJSON{Page: 1, Limit: defaultPageSize}
+
+	if v := qry.Get("page"); v != "" {
+		page, err := strconv.Atoi(v)
+		if err != nil || page < 1 {
+			return p, errBadRequest("page needs to be a number, starting at 1")
+		}
+		p.Page = page
+	}
+
+	if v := qry.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxPageSize {
+			return p, errBadRequest("limit needs to be a number between 1 and %d", maxPageSize)
+		}
+		p.Limit = limit
+	}
+
+	all := reflect.ValueOf(items)
+	p.Total = all.Len()
+
+	// pages past the end are empty, checked before multiplying so that huge page numbers can't overflow
+	start := p.Total
+	if p.Page-1 <= p.Total/p.Limit {
+		start = (p.Page - 1) * p.Limit
+	}
+	if start < 0 {
+		start = 0
+	}
+	if start > p.Total {
+		start = p.Total
+	}
+	end := start + p.Limit
+	if end > p.Total {
+		end = p.Total
+	}
+	p.Items = all.Slice(start, end).Interface()
+
+	return p, nil
+}
@@ -0,0 +1,200 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+)
+
+func TestMembersPagination(t *testing.T) {
+	ts := newSession(t)
+	a, r := assert.New(t), require.New(t)
+
+	var lst []roomdb.Member
+	for i := 1; i <= 25; i++ {
+		pk, err := generatePubKey()
+		r.NoError(err)
+		lst = append(lst, roomdb.Member{ID: int64(i), Role: roomdb.RoleMember, PubKey: pk})
+	}
+	ts.MembersDB.ListReturns(lst, nil)
+
+	resp := ts.do(t, http.MethodGet, "/api/v1/members?page=2&limit=10", nil)
+	r.Equal(http.StatusOK, resp.Code, resp.Body.String())
+	a.Equal("application/json", resp.Header().Get("Content-Type"))
+
+	var page struct {
+		Items []memberJSON `json:"items"`
+		Page  int          `json:"page"`
+		Limit int          `json:"limit"`
+		Total int          `json:"total"`
+	}
+	r.NoError(json.NewDecoder(resp.Body).Decode(&page))
+	a.Equal(2, page.Page)
+	a.Equal(10, page.Limit)
+	a.Equal(25, page.Total)
+	r.Len(page.Items, 10)
+
+	// newest first
+	a.EqualValues(15, page.Items[0].ID)
+	a.EqualValues(6, page.Items[9].ID)
+	a.Equal("member", page.Items[0].Role)
+	a.Equal(lst[14].PubKey.String(), page.Items[0].Feed)
+
+	// the last page is shorter
+	resp = ts.do(t, http.MethodGet, "/api/v1/members?page=3&limit=10", nil)
+	r.Equal(http.StatusOK, resp.Code)
+	r.NoError(json.NewDecoder(resp.Body).Decode(&page))
+	a.Len(page.Items, 5)
+
+	// pages past the end are empty, even huge ones
+	for _, past := range []string{"page=4&limit=10", "page=184467440737095517&limit=100", fmt.Sprintf("page=%d", math.MaxInt64)} {
+		resp = ts.do(t, http.MethodGet, "/api/v1/members?"+past, nil)
+		r.Equal(http.StatusOK, resp.Code, past)
+		page.Items = nil
+		r.NoError(json.NewDecoder(resp.Body).Decode(&page))
+		a.Len(page.Items, 0, past)
+		a.Equal(25, page.Total)
+	}
+
+	for _, bad := range []string{"page=0", "page=nope", "limit=0", fmt.Sprintf("limit=%d", maxPageSize+1)} {
+		resp = ts.do(t, http.MethodGet, "/api/v1/members?"+bad, nil)
+		a.Equal(http.StatusBadRequest, resp.Code, bad)
+	}
+}
+
+func TestErrorStatusCodes(t *testing.T) {
+	ts := newSession(t)
+	a := assert.New(t)
+
+	checkError := func(method, path string, body interface{}, code int) {
+		resp := ts.do(t, method, path, body)
+		a.Equal(code, resp.Code, "%s %s: %s", method, path, resp.Body.String())
+
+		var errResp errorJSON
+		a.NoError(json.NewDecoder(resp.Body).Decode(&errResp), "%s %s", method, path)
+		a.Equal("error", errResp.Status)
+		a.NotEmpty(errResp.Error)
+	}
+
+	ts.MembersDB.GetByIDReturns(roomdb.Member{}, roomdb.ErrNotFound)
+	checkError(http.MethodGet, "/api/v1/members/23", nil, http.StatusNotFound)
+
+	checkError(http.MethodGet, "/api/v1/nope", nil, http.StatusNotFound)
+	checkError(http.MethodPatch, "/api/v1/members", nil, http.StatusMethodNotAllowed)
+
+	// invalid bodies
+	checkError(http.MethodPost, "/api/v1/members", map[string]string{"feed": "nope"}, http.StatusBadRequest)
+	checkError(http.MethodPost, "/api/v1/members", map[string]string{"typo": "x"}, http.StatusBadRequest)
+
+	// only admins can change roles or settings
+	checkError(http.MethodPut, "/api/v1/members/23/role", setRoleJSON{Role: "admin"}, http.StatusForbidden)
+	checkError(http.MethodPatch, "/api/v1/settings", map[string]bool{"totp_mandatory": true}, http.StatusForbidden)
+	a.Equal(0, ts.MembersDB.SetRoleCallCount())
+	a.Equal(0, ts.ConfigDB.SetTOTPMandatoryCallCount())
+
+	// members can't change denied keys in restricted mode
+	ts.User.Role = roomdb.RoleMember
+	ts.ConfigDB.GetPrivacyModeReturns(roomdb.ModeRestricted, nil)
+	checkError(http.MethodDelete, "/api/v1/denied-keys/3", nil, http.StatusForbidden)
+	a.Equal(0, ts.DeniedKeysDB.RemoveIDCallCount())
+
+	// no one signed in
+	resp := ts.doWith(t, ts.Anonymous, http.MethodGet, "/api/v1/members", nil)
+	a.Equal(http.StatusUnauthorized, resp.Code)
+	a.Equal(0, ts.MembersDB.ListCallCount())
+}
+
+func TestCreateInvite(t *testing.T) {
+	ts := newSession(t)
+	a, r := assert.New(t), require.New(t)
+
+	ts.InvitesDB.CreateReturns("sometoken", nil)
+
+	resp := ts.do(t, http.MethodPost, "/api/v1/invites", nil)
+	r.Equal(http.StatusCreated, resp.Code, resp.Body.String())
+
+	var created createdInviteJSON
+	r.NoError(json.NewDecoder(resp.Body).Decode(&created))
+	a.True(strings.HasPrefix(created.URL, "https://"+ts.netInfo.Domain), created.URL)
+	a.Contains(created.URL, "token=sometoken")
+
+	r.Equal(1, ts.InvitesDB.CreateCallCount())
+	_, createdBy, _ := ts.InvitesDB.CreateArgsForCall(0)
+	a.Equal(ts.User.ID, createdBy)
+}
+
+func TestSetRoleAsAdmin(t *testing.T) {
+	ts := newSession(t)
+	a, r := assert.New(t), require.New(t)
+
+	ts.User.Role = roomdb.RoleAdmin
+
+	pk, err := generatePubKey()
+	r.NoError(err)
+	ts.MembersDB.GetByIDReturns(roomdb.Member{ID: 23, Role: roomdb.RoleModerator, PubKey: pk}, nil)
+
+	resp := ts.do(t, http.MethodPut, "/api/v1/members/23/role", setRoleJSON{Role: "moderator"})
+	r.Equal(http.StatusOK, resp.Code, resp.Body.String())
+
+	r.Equal(1, ts.MembersDB.SetRoleCallCount())
+	_, id, role := ts.MembersDB.SetRoleArgsForCall(0)
+	a.EqualValues(23, id)
+	a.Equal(roomdb.RoleModerator, role)
+
+	resp = ts.do(t, http.MethodPut, "/api/v1/members/23/role", setRoleJSON{Role: "owner"})
+	a.Equal(http.StatusBadRequest, resp.Code)
+	a.Equal(1, ts.MembersDB.SetRoleCallCount())
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	ts := newSession(t)
+	a, r := assert.New(t), require.New(t)
+
+	// the document itself doesn't need anyone to be signed in
+	resp := ts.doWith(t, ts.Anonymous, http.MethodGet, "/api/v1/openapi.json", nil)
+	r.Equal(http.StatusOK, resp.Code)
+
+	var doc struct {
+		OpenAPI string `json:"openapi"`
+		Servers []struct {
+			URL string `json:"url"`
+		} `json:"servers"`
+		Paths      map[string]map[string]json.RawMessage `json:"paths"`
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	r.NoError(json.NewDecoder(resp.Body).Decode(&doc))
+
+	a.Equal("3.0.3", doc.OpenAPI)
+	r.Len(doc.Servers, 1)
+	a.Equal("https://"+ts.netInfo.Domain, doc.Servers[0].URL)
+
+	// every endpoint is described, with the variables in the openapi format
+	h := handler{}
+	a.Len(h.endpoints(), 23)
+
+	memberPaths, has := doc.Paths["/api/v1/members/{id}"]
+	r.True(has, "member path missing")
+	a.Contains(memberPaths, "get")
+	a.Contains(memberPaths, "delete")
+	a.Contains(doc.Paths["/api/v1/members/{id}/role"], "put")
+	a.Contains(doc.Paths["/api/v1/settings"], "patch")
+	a.Contains(doc.Paths["/api/v1/aliases/{name}"], "delete")
+
+	for _, name := range []string{"Member", "Invite", "DeniedKey", "Alias", "Notice", "Settings", "Page", "Error"} {
+		a.Contains(doc.Components.Schemas, name)
+	}
+}
@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package api
+
+import (
+	"net/http"
+
+	refs "github.com/ssbc/go-ssb-refs"
+
+	"github.com/ssbc/go-ssb-room/v2/web/members"
+)
+
+func (h handler) listDeniedKeys(req *http.Request) (interface{}, error) {
+	lst, err := h.dbs.DeniedKeys.List(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]deniedKeyJSON, len(lst))
+	for i, e := range lst {
+		items[i] = toDeniedKeyJSON(e)
+	}
+
+	return paginate(req, items)
+}
+
+func (h handler) getDeniedKey(req *http.Request) (interface{}, error) {
+	id, err := idFromPath(req)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := h.dbs.DeniedKeys.GetByID(req.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDeniedKeyJSON(e), nil
+}
+
+func (h handler) addDeniedKey(req *http.Request) (interface{}, error) {
+	ctx := req.Context()
+
+	if _, err := members.CheckAllowed(ctx, h.dbs.Config, members.ActionChangeDeniedKeys); err != nil {
+		return nil, err
+	}
+
+	var body addDeniedKeyJSON
+	if err := decodeBody(req, &body); err != nil {
+		return nil, err
+	}
+
+	feed, err := refs.ParseFeedRef(body.Feed)
+	if err != nil {
+		return nil, errBadRequest("invalid feed: %s", err)
+	}
+
+	if err := h.dbs.DeniedKeys.Add(ctx, feed, body.Comment); err != nil {
+		return nil, err
+	}
+
+	// Add doesn't return the new id, so look it up
+	lst, err := h.dbs.DeniedKeys.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range lst {
+		if e.PubKey.Equal(feed) {
+			return toDeniedKeyJSON(e), nil
+		}
+	}
+
+	return deniedKeyJSON{Feed: feed.String(), Comment: body.Comment}, nil
+}
+
+func (h handler) removeDeniedKey(req *http.Request) (interface{}, error) {
+	if _, err := members.CheckAllowed(req.Context(), h.dbs.Config, members.ActionChangeDeniedKeys); err != nil {
+		return nil, err
+	}
+
+	id, err := idFromPath(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, h.dbs.DeniedKeys.RemoveID(req.Context(), id)
+}
@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web/members"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+)
+
+func toInviteJSON(inv roomdb.Invite) inviteJSON {
+	return inviteJSON{
+		ID:        inv.ID,
+		CreatedBy: toMemberJSON(inv.CreatedBy),
+		CreatedAt: inv.CreatedAt,
+	}
+}
+
+func (h handler) listInvites(req *http.Request) (interface{}, error) {
+	lst, err := h.dbs.Invites.List(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]inviteJSON, len(lst))
+	for i, inv := range lst {
+		items[i] = toInviteJSON(inv)
+	}
+
+	return paginate(req, items)
+}
+
+func (h handler) getInvite(req *http.Request) (interface{}, error) {
+	id, err := idFromPath(req)
+	if err != nil {
+		return nil, err
+	}
+
+	inv, err := h.dbs.Invites.GetByID(req.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toInviteJSON(inv), nil
+}
+
+func (h handler) createInvite(req *http.Request) (interface{}, error) {
+	member, err := members.CheckAllowed(req.Context(), h.dbs.Config, members.ActionInviteMember)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := h.dbs.Invites.Create(req.Context(), member.ID, roomdb.InviteOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return createdInviteJSON{
+		URL: h.urlTo(router.CompleteInviteFacade, "token", token).String(),
+	}, nil
+}
+
+func (h handler) revokeInvite(req *http.Request) (interface{}, error) {
+	if _, err := members.CheckAllowed(req.Context(), h.dbs.Config, members.ActionInviteMember); err != nil {
+		return nil, err
+	}
+
+	id, err := idFromPath(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, h.dbs.Invites.Revoke(req.Context(), id)
+}
@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package api
+
+import (
+	"net/http"
+
+	refs "github.com/ssbc/go-ssb-refs"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web/members"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+)
+
+func (h handler) listMembers(req *http.Request) (interface{}, error) {
+	lst, err := h.dbs.Members.List(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	// recent-to-oldest, like the dashboard
+	items := make([]memberJSON, len(lst))
+	for i, m := range lst {
+		items[len(lst)-1-i] = toMemberJSON(m)
+	}
+
+	return paginate(req, items)
+}
+
+func (h handler) getMember(req *http.Request) (interface{}, error) {
+	id, err := idFromPath(req)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := h.dbs.Members.GetByID(req.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toMemberJSON(m), nil
+}
+
+func (h handler) addMember(req *http.Request) (interface{}, error) {
+	var body addMemberJSON
+	if err := decodeBody(req, &body); err != nil {
+		return nil, err
+	}
+
+	feed, err := refs.ParseFeedRef(body.Feed)
+	if err != nil {
+		return nil, errBadRequest("invalid feed: %s", err)
+	}
+
+	role := roomdb.RoleMember
+	if body.Role != "" {
+		role, err = parseRole(body.Role)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// only admins can change roles in the dashboard, so only they can add members with other roles, too
+	if role != roomdb.RoleMember {
+		if current := members.FromContext(req.Context()); current.Role != roomdb.RoleAdmin {
+			return nil, errNotAdmin
+		}
+	}
+
+	id, err := h.dbs.Members.Add(req.Context(), feed, role)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := h.dbs.Members.GetByID(req.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toMemberJSON(m), nil
+}
+
+func (h handler) setMemberRole(req *http.Request) (interface{}, error) {
+	if current := members.FromContext(req.Context()); current.Role != roomdb.RoleAdmin {
+		return nil, errNotAdmin
+	}
+
+	id, err := idFromPath(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var body setRoleJSON
+	if err := decodeBody(req, &body); err != nil {
+		return nil, err
+	}
+
+	role, err := parseRole(body.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := h.dbs.Members.GetByID(req.Context(), id); err != nil {
+		return nil, err
+	}
+
+	if err := h.dbs.Members.SetRole(req.Context(), id, role); err != nil {
+		// most likely the last admin
+		return nil, apiError{http.StatusConflict, err.Error()}
+	}
+
+	m, err := h.dbs.Members.GetByID(req.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toMemberJSON(m), nil
+}
+
+func (h handler) removeMember(req *http.Request) (interface{}, error) {
+	if _, err := members.CheckAllowed(req.Context(), h.dbs.Config, members.ActionRemoveMember); err != nil {
+		return nil, err
+	}
+
+	id, err := idFromPath(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := h.dbs.Members.GetByID(req.Context(), id); err != nil {
+		return nil, err
+	}
+
+	return nil, h.dbs.Members.RemoveID(req.Context(), id)
+}
+
+func (h handler) createPasswordReset(req *http.Request) (interface{}, error) {
+	current := members.FromContext(req.Context())
+	if current.Role != roomdb.RoleAdmin {
+		return nil, errNotAdmin
+	}
+
+	id, err := idFromPath(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := h.dbs.Members.GetByID(req.Context(), id); err != nil {
+		return nil, err
+	}
+
+	token, err := h.dbs.AuthFallback.CreateResetToken(req.Context(), current.ID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return passwordResetJSON{
+		URL: h.urlTo(router.MembersChangePasswordForm, "token", token).String(),
+	}, nil
+}
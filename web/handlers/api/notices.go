@@ -0,0 +1,136 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web/members"
+)
+
+func (h handler) listNotices(req *http.Request) (interface{}, error) {
+	pinned, err := h.dbs.PinnedNotices.List(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	lst := []pinnedNoticeJSON{}
+	for _, p := range pinned.Sorted() {
+		entry := pinnedNoticeJSON{
+			Name:    p.Name.String(),
+			Notices: make([]noticeJSON, len(p.Notices)),
+		}
+		for i, n := range p.Notices {
+			entry.Notices[i] = toNoticeJSON(n)
+		}
+		lst = append(lst, entry)
+	}
+
+	return lst, nil
+}
+
+func (h handler) getNotice(req *http.Request) (interface{}, error) {
+	id, err := idFromPath(req)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := h.dbs.Notices.GetByID(req.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toNoticeJSON(n), nil
+}
+
+// decodeNotice reads and checks the notice in the body of the request
+func decodeNotice(req *http.Request) (roomdb.Notice, error) {
+	var body noticeInputJSON
+	if err := decodeBody(req, &body); err != nil {
+		return roomdb.Notice{}, err
+	}
+
+	if body.Title == "" {
+		return roomdb.Notice{}, errBadRequest("title can't be empty")
+	}
+
+	// TODO: validate languages properly
+	if body.Language == "" {
+		return roomdb.Notice{}, errBadRequest("language can't be empty")
+	}
+
+	if body.Content == "" {
+		return roomdb.Notice{}, errBadRequest("content can't be empty")
+	}
+
+	return roomdb.Notice{
+		Title:    body.Title,
+		Language: body.Language,
+		// https://github.com/russross/blackfriday/issues/575
+		Content: strings.Replace(body.Content, "\r\n", "\n", -1),
+	}, nil
+}
+
+func (h handler) saveNotice(req *http.Request) (interface{}, error) {
+	ctx := req.Context()
+
+	if _, err := members.CheckAllowed(ctx, h.dbs.Config, members.ActionChangeNotice); err != nil {
+		return nil, err
+	}
+
+	id, err := idFromPath(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Save would create a new one if it doesn't exist
+	if _, err := h.dbs.Notices.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	n, err := decodeNotice(req)
+	if err != nil {
+		return nil, err
+	}
+	n.ID = id
+
+	if err := h.dbs.Notices.Save(ctx, &n); err != nil {
+		return nil, err
+	}
+
+	return toNoticeJSON(n), nil
+}
+
+func (h handler) addNoticeTranslation(req *http.Request) (interface{}, error) {
+	ctx := req.Context()
+
+	if _, err := members.CheckAllowed(ctx, h.dbs.Config, members.ActionChangeNotice); err != nil {
+		return nil, err
+	}
+
+	pinnedName := roomdb.PinnedNoticeName(mux.Vars(req)["name"])
+	if !pinnedName.Valid() {
+		return nil, apiError{http.StatusNotFound, "no such pinned notice"}
+	}
+
+	n, err := decodeNotice(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.dbs.Notices.Save(ctx, &n); err != nil {
+		return nil, err
+	}
+
+	if err := h.dbs.PinnedNotices.Set(ctx, pinnedName, n.ID); err != nil {
+		return nil, err
+	}
+
+	return toNoticeJSON(n), nil
+}
@@ -0,0 +1,298 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package api
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ssbc/go-ssb-room/v2/web"
+	"github.com/ssbc/go-ssb-room/v2/web/members"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+)
+
+// these are only used to build the document, so plain maps are good enough
+type (
+	object map[string]interface{}
+	array  []interface{}
+)
+
+// openAPIDocument describes the endpoints in the OpenAPI 3 format.
+// The paths and methods come from the routes, the schemas from the request and response types of the endpoints.
+func openAPIDocument(m *mux.Router, urlTo web.URLMaker, endpoints []endpoint) object {
+	// the paths include the /api/v1 prefix, so the server is just the root of the room
+	serverURL := urlTo(router.CompleteIndex)
+	serverURL.Path = ""
+
+	sg := schemaGenerator{components: make(object)}
+
+	errorResponse := object{
+		"description": "the request failed",
+		"content":     jsonContent(sg.schemaFor(reflect.TypeOf(errorJSON{}))),
+	}
+
+	paths := make(object)
+	for _, ep := range endpoints {
+		route := m.Get(ep.route)
+		if route == nil {
+			panic("api: no route named " + ep.route)
+		}
+
+		tpl, err := route.GetPathTemplate()
+		if err != nil {
+			panic(err)
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			panic(err)
+		}
+
+		path, params := openAPIPath(tpl)
+
+		if ep.paginated {
+			params = append(params,
+				object{"name": "page", "in": "query", "schema": object{"type": "integer", "minimum": 1, "default": 1}},
+				object{"name": "limit", "in": "query", "schema": object{"type": "integer", "minimum": 1, "maximum": maxPageSize, "default": defaultPageSize}},
+			)
+		}
+
+		status := ep.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		var success object
+		switch {
+		case ep.response == nil:
+			status = http.StatusNoContent
+			success = object{"description": http.StatusText(status)}
+
+		case ep.paginated:
+			success = object{
+				"description": http.StatusText(status),
+				"content":     jsonContent(sg.pageSchema(reflect.TypeOf(ep.response))),
+			}
+
+		default:
+			success = object{
+				"description": http.StatusText(status),
+				"content":     jsonContent(sg.schemaFor(reflect.TypeOf(ep.response))),
+			}
+		}
+
+		op := object{
+			"operationId": ep.route,
+			"summary":     ep.summary,
+			"tags":        array{strings.Split(ep.route, ":")[2]},
+			"parameters":  params,
+			"responses": object{
+				strconv.Itoa(status): success,
+				"default":            errorResponse,
+			},
+		}
+
+		if ep.request != nil {
+			op["requestBody"] = object{
+				"required": true,
+				"content":  jsonContent(sg.schemaFor(reflect.TypeOf(ep.request))),
+			}
+		}
+
+		// the scope is also listed for the cookie, even though it only applies to tokens
+		security := array{object{"cookie": array{}}}
+		if scope, has := members.RequiredAPITokenScope(ep.route); has {
+			security = append(security, object{"token": array{string(scope)}})
+			op["x-token-scope"] = string(scope)
+		}
+		op["security"] = security
+
+		pathItem, ok := paths[path].(object)
+		if !ok {
+			pathItem = make(object)
+			paths[path] = pathItem
+		}
+		for _, method := range methods {
+			pathItem[strings.ToLower(method)] = op
+		}
+	}
+
+	sg.components["Page"] = sg.schemaFor(reflect.TypeOf(pageJSON{}))
+
+	return object{
+		"openapi": "3.0.3",
+		"info": object{
+			"title":   "go-ssb-room",
+			"version": "v1",
+		},
+		"servers": array{
+			object{"url": serverURL.String()},
+		},
+		"paths": paths,
+		"components": object{
+			"schemas": sg.components,
+			"securitySchemes": object{
+				"cookie": object{
+					"type":        "apiKey",
+					"in":          "cookie",
+					"name":        "session",
+					"description": "the session of a member that signed into the dashboard, changes also need the CSRF token in the X-CSRF-Token header",
+				},
+				"token": object{
+					"type":        "http",
+					"scheme":      "bearer",
+					"description": "a personal api token of an admin or moderator, which needs the scope of the endpoint",
+				},
+			},
+		},
+	}
+}
+
+func jsonContent(schema object) object {
+	return object{"application/json": object{"schema": schema}}
+}
+
+var pathVariable = regexp.MustCompile(`\{([^}:]+)(:[^}]+)?\}`)
+
+// openAPIPath turns /members/{id:[0-9]+} into /members/{id} and returns the parameters
+func openAPIPath(tpl string) (string, array) {
+	params := array{}
+
+	path := pathVariable.ReplaceAllStringFunc(tpl, func(v string) string {
+		name := pathVariable.FindStringSubmatch(v)[1]
+
+		schema := object{"type": "string"}
+		if name == "id" {
+			schema = object{"type": "integer", "format": "int64"}
+		}
+		params = append(params, object{"name": name, "in": "path", "required": true, "schema": schema})
+
+		return "{" + name + "}"
+	})
+
+	return path, params
+}
+
+// schemaGenerator turns go types into json schemas.
+// Structs are added to the components and referenced, so that each one is only described once.
+type schemaGenerator struct {
+	components object
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (sg schemaGenerator) schemaFor(t reflect.Type) object {
+	if t == timeType {
+		return object{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return sg.schemaFor(t.Elem())
+
+	case reflect.Slice, reflect.Array:
+		return object{"type": "array", "items": sg.schemaFor(t.Elem())}
+
+	case reflect.String:
+		return object{"type": "string"}
+
+	case reflect.Bool:
+		return object{"type": "boolean"}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return object{"type": "integer"}
+
+	case reflect.Int64, reflect.Uint64:
+		return object{"type": "integer", "format": "int64"}
+
+	case reflect.Float32, reflect.Float64:
+		return object{"type": "number"}
+
+	case reflect.Struct:
+		name := schemaName(t)
+		if _, has := sg.components[name]; !has {
+			// reserve the name first, in case the type references itself
+			sg.components[name] = object{}
+			sg.components[name] = sg.structSchema(t)
+		}
+		return object{"$ref": "#/components/schemas/" + name}
+
+	default:
+		// interface{} and the like, anything goes
+		return object{}
+	}
+}
+
+func (sg schemaGenerator) structSchema(t reflect.Type) object {
+	var (
+		properties = make(object)
+		required   = array{}
+	)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		name := parts[0]
+		if name == "" {
+			name = f.Name
+		}
+
+		properties[name] = sg.schemaFor(f.Type)
+
+		omitEmpty := false
+		for _, opt := range parts[1:] {
+			if opt == "omitempty" {
+				omitEmpty = true
+			}
+		}
+		if !omitEmpty {
+			required = append(required, name)
+		}
+	}
+
+	schema := object{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// pageSchema describes a pageJSON with items of the passed type
+func (sg schemaGenerator) pageSchema(item reflect.Type) object {
+	return object{
+		"allOf": array{
+			object{"$ref": "#/components/schemas/Page"},
+			object{
+				"type": "object",
+				"properties": object{
+					"items": object{"type": "array", "items": sg.schemaFor(item)},
+				},
+			},
+		},
+	}
+}
+
+// schemaName turns memberJSON into Member
+func schemaName(t reflect.Type) string {
+	name := strings.TrimSuffix(t.Name(), "JSON")
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
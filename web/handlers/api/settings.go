@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web/members"
+)
+
+func (h handler) getSettings(req *http.Request) (interface{}, error) {
+	ctx := req.Context()
+
+	pm, err := h.dbs.Config.GetPrivacyMode(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve current privacy mode: %w", err)
+	}
+
+	lang, err := h.dbs.Config.GetDefaultLanguage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve default language: %w", err)
+	}
+
+	totpMandatory, err := h.dbs.Config.GetTOTPMandatory(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve two-factor setting: %w", err)
+	}
+
+	return settingsJSON{
+		PrivacyMode:     privacyModeName(pm),
+		DefaultLanguage: lang,
+		TOTPMandatory:   totpMandatory,
+	}, nil
+}
+
+func (h handler) updateSettings(req *http.Request) (interface{}, error) {
+	ctx := req.Context()
+
+	if current := members.FromContext(ctx); current.Role != roomdb.RoleAdmin {
+		return nil, errNotAdmin
+	}
+
+	var body updateSettingsJSON
+	if err := decodeBody(req, &body); err != nil {
+		return nil, err
+	}
+
+	// check everything before changing anything
+	var pm roomdb.PrivacyMode
+	if body.PrivacyMode != nil {
+		pm = roomdb.ParsePrivacyMode(*body.PrivacyMode)
+		if pm == roomdb.ModeUnknown {
+			return nil, errBadRequest("unknown privacy mode %q, expected open, community or restricted", *body.PrivacyMode)
+		}
+	}
+
+	if body.DefaultLanguage != nil && *body.DefaultLanguage == "" {
+		return nil, errBadRequest("default language can't be empty")
+	}
+
+	if body.PrivacyMode != nil {
+		if err := h.dbs.Config.SetPrivacyMode(ctx, pm); err != nil {
+			return nil, err
+		}
+	}
+
+	if body.DefaultLanguage != nil {
+		if err := h.dbs.Config.SetDefaultLanguage(ctx, *body.DefaultLanguage); err != nil {
+			return nil, err
+		}
+	}
+
+	if body.TOTPMandatory != nil {
+		if err := h.dbs.Config.SetTOTPMandatory(ctx, *body.TOTPMandatory); err != nil {
+			return nil, err
+		}
+	}
+
+	return h.getSettings(req)
+}
@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	refs "github.com/ssbc/go-ssb-refs"
+	"go.mindeco.de/logging/logtest"
+
+	"github.com/ssbc/go-ssb-room/v2/internal/network"
+	"github.com/ssbc/go-ssb-room/v2/internal/randutil"
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/roomdb/mockdb"
+	"github.com/ssbc/go-ssb-room/v2/roomstate"
+	"github.com/ssbc/go-ssb-room/v2/web/members"
+)
+
+type testSession struct {
+	netInfo network.ServerEndpointDetails
+
+	// Handler has ts.User signed in, Anonymous doesn't have anyone
+	Handler   http.Handler
+	Anonymous http.Handler
+
+	AliasesDB    *mockdb.FakeAliasesService
+	ConfigDB     *mockdb.FakeRoomConfig
+	DeniedKeysDB *mockdb.FakeDeniedKeysService
+	FallbackDB   *mockdb.FakeAuthFallbackService
+	InvitesDB    *mockdb.FakeInvitesService
+	MembersDB    *mockdb.FakeMembersService
+	NoticeDB     *mockdb.FakeNoticesService
+	PinnedDB     *mockdb.FakePinnedNoticesService
+
+	User roomdb.Member
+
+	RoomState *roomstate.Manager
+}
+
+var pubKeyCount byte
+
+func generatePubKey() (refs.FeedRef, error) {
+	pk, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte{pubKeyCount}, 32), refs.RefAlgoFeedSSB1)
+	if err != nil {
+		return refs.FeedRef{}, err
+	}
+	pubKeyCount++
+	return pk, nil
+}
+
+func newSession(t *testing.T) *testSession {
+	var ts testSession
+
+	// fake dbs
+	ts.AliasesDB = new(mockdb.FakeAliasesService)
+	ts.ConfigDB = new(mockdb.FakeRoomConfig)
+	// default mode for all tests
+	ts.ConfigDB.GetPrivacyModeReturns(roomdb.ModeCommunity, nil)
+	ts.ConfigDB.GetDefaultLanguageReturns("en", nil)
+	ts.DeniedKeysDB = new(mockdb.FakeDeniedKeysService)
+	ts.FallbackDB = new(mockdb.FakeAuthFallbackService)
+	ts.InvitesDB = new(mockdb.FakeInvitesService)
+	ts.MembersDB = new(mockdb.FakeMembersService)
+	ts.NoticeDB = new(mockdb.FakeNoticesService)
+	ts.PinnedDB = new(mockdb.FakePinnedNoticesService)
+
+	log, _ := logtest.KitLogger("api", t)
+	ts.RoomState = roomstate.NewManager(log)
+
+	pubKey, err := generatePubKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts.netInfo = network.ServerEndpointDetails{
+		Domain:                 randutil.String(10),
+		RoomID:                 pubKey,
+		UseSubdomainForAliases: true,
+	}
+
+	pubKey, err = generatePubKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// fake user
+	ts.User = roomdb.Member{
+		ID:     1234,
+		Role:   roomdb.RoleModerator,
+		PubKey: pubKey,
+	}
+
+	ts.Anonymous = Handler(ts.netInfo, ts.RoomState, Databases{
+		Aliases:       ts.AliasesDB,
+		AuthFallback:  ts.FallbackDB,
+		Config:        ts.ConfigDB,
+		DeniedKeys:    ts.DeniedKeysDB,
+		Invites:       ts.InvitesDB,
+		Members:       ts.MembersDB,
+		Notices:       ts.NoticeDB,
+		PinnedNotices: ts.PinnedDB,
+	})
+	ts.Handler = members.MiddlewareForTests(&ts.User)(ts.Anonymous)
+
+	return &ts
+}
+
+// do sends the request to the signed-in handler. body is encoded as json if it's not nil.
+func (ts *testSession) do(t *testing.T, method, path string, body interface{}) *httptest.ResponseRecorder {
+	return ts.doWith(t, ts.Handler, method, path, body)
+}
+
+func (ts *testSession) doWith(t *testing.T, h http.Handler, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var rd io.Reader
+	if body != nil {
+		enc, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rd = bytes.NewReader(enc)
+	}
+
+	req := httptest.NewRequest(method, path, rd)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
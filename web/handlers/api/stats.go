@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	refs "github.com/ssbc/go-ssb-refs"
+	"go.mindeco.de/log/level"
+	"go.mindeco.de/logging"
+)
+
+func (h handler) stats(req *http.Request) (interface{}, error) {
+	ctx := req.Context()
+
+	stats := statsJSON{
+		OnlineCount: -1,
+		Online:      []string{},
+		Channels:    []channelJSON{},
+	}
+
+	// like the dashboard, don't wait forever for the room state (issue #210)
+	refsUpdateCh := make(chan []refs.FeedRef, 1)
+	go func() {
+		refsUpdateCh <- h.roomState.ListAsRefs()
+	}()
+
+	select {
+	case <-time.After(10 * time.Second):
+		level.Warn(logging.FromContext(ctx)).Log("event", "didnt retreive room state in time")
+
+	case onlineRefs := <-refsUpdateCh:
+		stats.OnlineCount = len(onlineRefs)
+		for _, ref := range onlineRefs {
+			stats.Online = append(stats.Online, ref.String())
+		}
+	}
+
+	memberCount, err := h.dbs.Members.Count(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count members: %w", err)
+	}
+	stats.Members = int(memberCount)
+
+	inviteCount, err := h.dbs.Invites.Count(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count active invites: %w", err)
+	}
+	stats.Invites = int(inviteCount)
+
+	deniedCount, err := h.dbs.DeniedKeys.Count(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count denied keys: %w", err)
+	}
+	stats.DeniedKeys = int(deniedCount)
+
+	for _, ch := range h.roomState.ChannelCounts() {
+		stats.Channels = append(stats.Channels, channelJSON{Name: ch.Name, Attendants: ch.Attendants})
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package api
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+)
+
+// These are the json schemas of the api. They are kept apart from the roomdb types on purpose,
+// so that changes to the database don't change what clients get to see.
+// The field names and types need to stay compatible within one version of the api.
+
+type statsJSON struct {
+	// OnlineCount is -1 if the connected peers couldn't be listed in time
+	OnlineCount int           `json:"online_count"`
+	Online      []string      `json:"online"`
+	Members     int           `json:"members"`
+	Invites     int           `json:"active_invites"`
+	DeniedKeys  int           `json:"denied_keys"`
+	Channels    []channelJSON `json:"channels"`
+}
+
+type channelJSON struct {
+	Name       string `json:"name"`
+	Attendants int    `json:"attendants"`
+}
+
+type memberJSON struct {
+	ID      int64    `json:"id"`
+	Role    string   `json:"role"`
+	Feed    string   `json:"feed"`
+	Aliases []string `json:"aliases"`
+}
+
+type addMemberJSON struct {
+	Feed string `json:"feed"`
+	// Role is "member" if it's left out. Only admins can add moderators and admins.
+	Role string `json:"role,omitempty"`
+}
+
+type setRoleJSON struct {
+	Role string `json:"role"`
+}
+
+type passwordResetJSON struct {
+	URL string `json:"url"`
+}
+
+type inviteJSON struct {
+	ID        int64      `json:"id"`
+	CreatedBy memberJSON `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type createdInviteJSON struct {
+	URL string `json:"url"`
+}
+
+type deniedKeyJSON struct {
+	ID        int64     `json:"id"`
+	Feed      string    `json:"feed"`
+	Comment   string    `json:"comment"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type addDeniedKeyJSON struct {
+	Feed    string `json:"feed"`
+	Comment string `json:"comment,omitempty"`
+}
+
+type aliasJSON struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Feed        string `json:"feed"`
+	URL         string `json:"url"`
+}
+
+type noticeJSON struct {
+	ID       int64  `json:"id"`
+	Title    string `json:"title"`
+	Language string `json:"language"`
+	Content  string `json:"content"`
+}
+
+type noticeInputJSON struct {
+	Title    string `json:"title"`
+	Language string `json:"language"`
+	Content  string `json:"content"`
+}
+
+type pinnedNoticeJSON struct {
+	Name    string       `json:"name"`
+	Notices []noticeJSON `json:"notices"`
+}
+
+type settingsJSON struct {
+	PrivacyMode     string `json:"privacy_mode"`
+	DefaultLanguage string `json:"default_language"`
+	TOTPMandatory   bool   `json:"totp_mandatory"`
+}
+
+type updateSettingsJSON struct {
+	PrivacyMode     *string `json:"privacy_mode,omitempty"`
+	DefaultLanguage *string `json:"default_language,omitempty"`
+	TOTPMandatory   *bool   `json:"totp_mandatory,omitempty"`
+}
+
+// the names of the roles in the api
+var roleNames = map[roomdb.Role]string{
+	roomdb.RoleMember:    "member",
+	roomdb.RoleModerator: "moderator",
+	roomdb.RoleAdmin:     "admin",
+}
+
+func parseRole(name string) (roomdb.Role, error) {
+	for r, n := range roleNames {
+		if n == name {
+			return r, nil
+		}
+	}
+	return roomdb.RoleUnknown, errBadRequest("unknown role %q, expected member, moderator or admin", name)
+}
+
+// privacyModeName turns ModeOpen into open, which ParsePrivacyMode understands as well
+func privacyModeName(pm roomdb.PrivacyMode) string {
+	return strings.ToLower(strings.TrimPrefix(pm.String(), "Mode"))
+}
+
+func toMemberJSON(m roomdb.Member) memberJSON {
+	aliases := make([]string, len(m.Aliases))
+	for i, a := range m.Aliases {
+		aliases[i] = a.Name
+	}
+
+	return memberJSON{
+		ID:      m.ID,
+		Role:    roleNames[m.Role],
+		Feed:    m.PubKey.String(),
+		Aliases: aliases,
+	}
+}
+
+func toNoticeJSON(n roomdb.Notice) noticeJSON {
+	return noticeJSON{
+		ID:       n.ID,
+		Title:    n.Title,
+		Language: n.Language,
+		Content:  n.Content,
+	}
+}
+
+func toDeniedKeyJSON(e roomdb.ListEntry) deniedKeyJSON {
+	return deniedKeyJSON{
+		ID:        e.ID,
+		Feed:      e.PubKey.String(),
+		Comment:   e.Comment,
+		CreatedAt: e.CreatedAt,
+	}
+}
@@ -0,0 +1,533 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.mindeco.de/log/level"
+	"go.mindeco.de/logging"
+
+	"github.com/ssbc/go-ssb-room/v2/internal/network"
+	"github.com/ssbc/go-ssb-room/v2/internal/signinwithssb"
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+)
+
+const (
+	// how long the member has to confirm the code
+	deviceCodeLifetime = 10 * time.Minute
+
+	// how often the device may ask for the token
+	devicePollInterval = 5 * time.Second
+
+	// the device code grant type of RFC 8628
+	deviceGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+	// user codes only use consonants, so that they don't spell words and are easy to type on any keyboard
+	userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+	userCodeLength   = 8
+
+	maxDeviceNameLength = 64
+
+	// how many device sign-ins can wait at the same time, in total and from one address (or /24 network)
+	maxPendingDeviceLogins           = 500
+	maxPendingDeviceLoginsPerAddress = 5
+)
+
+// WithDeviceHandler implements a sign-in for devices without a proper browser, like command line tools,
+// following the device authorization grant of RFC 8628.
+//
+// The device asks for a code and shows the short user code to the member, who confirms it in a signed-in browser session (see PendingDevice and Approve).
+// Alternatively the device can show the ssb uri of the code, so that the member confirms it with their ssb app (httpAuth.sendSolution).
+// Meanwhile the device polls for the result, which is a sign-in with ssb session token that it can use as a bearer token for the JSON API.
+type WithDeviceHandler struct {
+	netInfo network.ServerEndpointDetails
+	urlTo   web.URLMaker
+
+	sessiondb roomdb.AuthWithSSBService
+
+	bridge *signinwithssb.SignalBridge
+
+	fullRemoteIPs bool
+
+	pending *pendingDeviceLogins
+}
+
+func NewWithDeviceHandler(
+	m *mux.Router,
+	netInfo network.ServerEndpointDetails,
+	sessiondb roomdb.AuthWithSSBService,
+	bridge *signinwithssb.SignalBridge,
+	fullRemoteIPs bool,
+) *WithDeviceHandler {
+	var h WithDeviceHandler
+	h.netInfo = netInfo
+	h.urlTo = web.NewURLTo(m, netInfo)
+	h.sessiondb = sessiondb
+	h.bridge = bridge
+	h.fullRemoteIPs = fullRemoteIPs
+	h.pending = &pendingDeviceLogins{
+		byDevice: make(map[string]*pendingDeviceLogin),
+		byUser:   make(map[string]*pendingDeviceLogin),
+	}
+
+	m.Get(router.AuthDeviceCode).HandlerFunc(h.code)
+	m.Get(router.AuthDeviceToken).HandlerFunc(h.token)
+
+	return &h
+}
+
+type deviceCodeJSON struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	SSBURI                  string `json:"ssb_uri"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// code starts a new device sign-in. The device can pass a name, which is shown to the member.
+func (h WithDeviceHandler) code(w http.ResponseWriter, req *http.Request) {
+	if err := req.ParseForm(); err != nil {
+		sendDeviceError(w, req, http.StatusBadRequest, "invalid_request", "invalid form data")
+		return
+	}
+
+	name := strings.TrimSpace(req.PostForm.Get("client_name"))
+	if len(name) > maxDeviceNameLength {
+		name = name[:maxDeviceNameLength]
+	}
+
+	// the caps always use the truncated address, so that one network can't fill them up
+	address := web.RemoteIP(req, true)
+
+	login, err := h.pending.add(name, req.UserAgent(), web.RemoteIP(req, !h.fullRemoteIPs), address)
+	if errors.Is(err, errTooManyDeviceLogins) {
+		level.Warn(logging.FromContext(req.Context())).Log("event", "too many pending device sign-ins", "address", address)
+		sendDeviceError(w, req, http.StatusTooManyRequests, "slow_down", "too many pending sign-ins, try again later")
+		return
+	}
+	if err != nil {
+		level.Error(logging.FromContext(req.Context())).Log("event", "failed to create device code", "err", err)
+		sendDeviceError(w, req, http.StatusInternalServerError, "server_error", "internal error")
+		return
+	}
+
+	// the ssb app of the member can confirm the code like a sign-in that was started on another device
+	sc := h.bridge.RegisterSession()
+
+	go h.waitForSolution(login.deviceCode, sc)
+
+	var queryParams = make(url.Values)
+	queryParams.Set("action", "start-http-auth")
+	queryParams.Set("sid", h.netInfo.RoomID.String())
+	queryParams.Set("sc", sc)
+	queryParams.Set("multiserverAddress", h.netInfo.MultiserverAddress())
+	ssbURI := url.URL{
+		Scheme:   "ssb",
+		Opaque:   "experimental",
+		RawQuery: queryParams.Encode(),
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	sendDeviceJSON(w, req, http.StatusOK, deviceCodeJSON{
+		DeviceCode:              login.deviceCode,
+		UserCode:                formatUserCode(login.userCode),
+		VerificationURI:         h.urlTo(router.AuthDeviceVerify).String(),
+		VerificationURIComplete: h.urlTo(router.AuthDeviceVerify, "user_code", formatUserCode(login.userCode)).String(),
+		SSBURI:                  ssbURI.String(),
+		ExpiresIn:               int(deviceCodeLifetime.Seconds()),
+		Interval:                int(devicePollInterval.Seconds()),
+	})
+}
+
+// waitForSolution waits for the ssb app of a member to confirm the sign-in, see muxrpc/handlers/signinwithssb
+func (h WithDeviceHandler) waitForSolution(deviceCode, sc string) {
+	events, has := h.bridge.GetEventChannel(sc)
+	if !has {
+		return
+	}
+
+	timeout := time.NewTimer(deviceCodeLifetime)
+	defer timeout.Stop()
+
+	select {
+	case evt, ok := <-events:
+		if !ok || !evt.Worked {
+			// the code can still be confirmed in the browser
+			return
+		}
+		h.approve(context.Background(), deviceCode, evt.Token)
+
+	case <-timeout.C:
+	}
+}
+
+// approve hands the session token to the pending sign-in, or throws it away if the sign-in is gone, was already decided or the session can't be marked as a device session
+func (h WithDeviceHandler) approve(ctx context.Context, deviceCode, token string) bool {
+	// only device sessions can be used as bearer tokens, see members.APITokenAuthenticator
+	var (
+		login pendingDeviceLogin
+		ok    bool
+	)
+	if err := h.sessiondb.MarkDevice(ctx, token); err != nil {
+		level.Error(logging.FromContext(ctx)).Log("event", "failed to mark device session", "err", err)
+	} else {
+		login, ok = h.pending.decide(deviceCode, token)
+	}
+	if !ok {
+		if err := h.sessiondb.RemoveToken(ctx, token); err != nil {
+			level.Warn(logging.FromContext(ctx)).Log("event", "failed to remove unused device token", "err", err)
+		}
+		return false
+	}
+
+	// so that the member can tell the session apart from their browsers
+	userAgent := login.userAgent
+	if login.name != "" {
+		userAgent = login.name
+	}
+	if err := h.sessiondb.SetClientDetails(ctx, token, userAgent, login.remoteIP); err != nil {
+		level.Warn(logging.FromContext(ctx)).Log("event", "failed to store device session details", "err", err)
+	}
+
+	return true
+}
+
+type deviceTokenJSON struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// token is polled by the device until the member confirmed or denied the code, or the code expired
+func (h WithDeviceHandler) token(w http.ResponseWriter, req *http.Request) {
+	if err := req.ParseForm(); err != nil {
+		sendDeviceError(w, req, http.StatusBadRequest, "invalid_request", "invalid form data")
+		return
+	}
+
+	if req.PostForm.Get("grant_type") != deviceGrantType {
+		sendDeviceError(w, req, http.StatusBadRequest, "unsupported_grant_type", "expected the device_code grant type")
+		return
+	}
+
+	result, token := h.pending.poll(req.PostForm.Get("device_code"))
+	switch result {
+	case pollApproved:
+		w.Header().Set("Cache-Control", "no-store")
+		sendDeviceJSON(w, req, http.StatusOK, deviceTokenJSON{
+			AccessToken: token,
+			TokenType:   "Bearer",
+			ExpiresIn:   int(sessionLifetime.Seconds()),
+		})
+
+	case pollPending:
+		sendDeviceError(w, req, http.StatusBadRequest, "authorization_pending", "the code wasn't confirmed yet")
+	case pollTooFast:
+		sendDeviceError(w, req, http.StatusBadRequest, "slow_down", fmt.Sprintf("only ask every %s", devicePollInterval))
+	case pollDenied:
+		sendDeviceError(w, req, http.StatusBadRequest, "access_denied", "the member denied the sign-in")
+	case pollExpired:
+		sendDeviceError(w, req, http.StatusBadRequest, "expired_token", "the code expired, start again")
+	default:
+		sendDeviceError(w, req, http.StatusBadRequest, "invalid_grant", "unknown device code")
+	}
+}
+
+// DeviceSignIn is what the member gets to see about a device that waits for the confirmation of its code
+type DeviceSignIn struct {
+	UserCode  string
+	Name      string
+	UserAgent string
+}
+
+// PendingDevice returns the device sign-in of the code the member entered, if it's still waiting
+func (h WithDeviceHandler) PendingDevice(userCode string) (DeviceSignIn, bool) {
+	userCode = normalizeUserCode(userCode)
+	login, has := h.pending.getByUserCode(userCode)
+	if !has {
+		return DeviceSignIn{}, false
+	}
+
+	return DeviceSignIn{
+		UserCode:  formatUserCode(login.userCode),
+		Name:      login.name,
+		UserAgent: login.userAgent,
+	}, true
+}
+
+// ErrUnknownUserCode is returned if the code doesn't exist, expired or was already used
+var ErrUnknownUserCode = errors.New("unknown or expired code")
+
+// Approve signs the device of the code in as the member
+func (h WithDeviceHandler) Approve(ctx context.Context, userCode string, memberID int64) error {
+	login, has := h.pending.getByUserCode(normalizeUserCode(userCode))
+	if !has {
+		return ErrUnknownUserCode
+	}
+
+	token, err := h.sessiondb.CreateToken(ctx, memberID)
+	if err != nil {
+		return err
+	}
+
+	if !h.approve(ctx, login.deviceCode, token) {
+		return ErrUnknownUserCode
+	}
+
+	return nil
+}
+
+// Deny lets the device of the code know that the member didn't want it to sign in
+func (h WithDeviceHandler) Deny(userCode string) error {
+	login, has := h.pending.getByUserCode(normalizeUserCode(userCode))
+	if !has {
+		return ErrUnknownUserCode
+	}
+
+	if _, ok := h.pending.decide(login.deviceCode, ""); !ok {
+		return ErrUnknownUserCode
+	}
+
+	return nil
+}
+
+func formatUserCode(code string) string {
+	return code[:4] + "-" + code[4:]
+}
+
+// normalizeUserCode ignores the case, dashes and spaces of what the member typed
+func normalizeUserCode(entered string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToUpper(entered) {
+		if strings.ContainsRune(userCodeAlphabet, r) {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+type deviceErrorJSON struct {
+	Error       string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func sendDeviceError(w http.ResponseWriter, req *http.Request, status int, code, description string) {
+	sendDeviceJSON(w, req, status, deviceErrorJSON{Error: code, Description: description})
+}
+
+func sendDeviceJSON(w http.ResponseWriter, req *http.Request, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		level.Warn(logging.FromContext(req.Context())).Log("event", "failed to send json", "err", err)
+	}
+}
+
+// pendingDeviceLogins keeps the device sign-ins until the device picked up the result or they expired.
+// Like the challenges of the signal bridge they are only kept in memory.
+type pendingDeviceLogins struct {
+	mu       sync.Mutex
+	byDevice map[string]*pendingDeviceLogin
+	byUser   map[string]*pendingDeviceLogin
+}
+
+type pendingDeviceLogin struct {
+	deviceCode string
+	userCode   string
+
+	// what the member sees about the device
+	name      string
+	userAgent string
+	remoteIP  string
+
+	// the truncated address, used for the cap per address
+	address string
+
+	expires  time.Time
+	lastPoll time.Time
+
+	decided bool
+	token   string // empty if the member denied it
+}
+
+type pollResult uint
+
+const (
+	pollUnknown pollResult = iota
+	pollPending
+	pollTooFast
+	pollApproved
+	pollDenied
+	pollExpired
+)
+
+// errTooManyDeviceLogins is returned by add if too many sign-ins are waiting, overall or from the address
+var errTooManyDeviceLogins = errors.New("too many pending device sign-ins")
+
+func (p *pendingDeviceLogins) add(name, userAgent, remoteIP, address string) (pendingDeviceLogin, error) {
+	codeBytes := make([]byte, 32)
+	if _, err := rand.Read(codeBytes); err != nil {
+		return pendingDeviceLogin{}, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// drop the ones that were abandoned
+	now := time.Now()
+	for code, l := range p.byDevice {
+		if now.After(l.expires) {
+			delete(p.byDevice, code)
+			delete(p.byUser, l.userCode)
+		}
+	}
+
+	// requests without a known address share one bucket
+	if address == "" {
+		address = "unknown"
+	}
+	if len(p.byDevice) >= maxPendingDeviceLogins {
+		return pendingDeviceLogin{}, errTooManyDeviceLogins
+	}
+	fromAddress := 0
+	for _, l := range p.byDevice {
+		if l.address == address {
+			fromAddress++
+		}
+	}
+	if fromAddress >= maxPendingDeviceLoginsPerAddress {
+		return pendingDeviceLogin{}, errTooManyDeviceLogins
+	}
+
+	var userCode string
+	for tries := 100; ; tries-- {
+		if tries == 0 {
+			return pendingDeviceLogin{}, errors.New("failed to generate an unused user code")
+		}
+
+		var err error
+		userCode, err = randomUserCode()
+		if err != nil {
+			return pendingDeviceLogin{}, err
+		}
+
+		if _, used := p.byUser[userCode]; !used {
+			break
+		}
+	}
+
+	l := &pendingDeviceLogin{
+		deviceCode: base64.URLEncoding.EncodeToString(codeBytes),
+		userCode:   userCode,
+
+		name:      name,
+		userAgent: userAgent,
+		remoteIP:  remoteIP,
+		address:   address,
+
+		expires: now.Add(deviceCodeLifetime),
+	}
+	p.byDevice[l.deviceCode] = l
+	p.byUser[l.userCode] = l
+
+	return *l, nil
+}
+
+func randomUserCode() (string, error) {
+	max := big.NewInt(int64(len(userCodeAlphabet)))
+
+	code := make([]byte, userCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = userCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// getByUserCode returns the sign-in if it's still waiting for the member
+func (p *pendingDeviceLogins) getByUserCode(userCode string) (pendingDeviceLogin, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	l, has := p.byUser[userCode]
+	if !has || l.decided || time.Now().After(l.expires) {
+		return pendingDeviceLogin{}, false
+	}
+	return *l, true
+}
+
+// decide stores the answer of the member. An empty token means the sign-in was denied.
+// It returns false if the sign-in doesn't exist anymore or was already decided.
+func (p *pendingDeviceLogins) decide(deviceCode, token string) (pendingDeviceLogin, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	l, has := p.byDevice[deviceCode]
+	if !has || l.decided || time.Now().After(l.expires) {
+		return pendingDeviceLogin{}, false
+	}
+
+	l.decided = true
+	l.token = token
+
+	// the code can't be entered again
+	delete(p.byUser, l.userCode)
+
+	return *l, true
+}
+
+// poll returns the state of the sign-in and the token once it was approved.
+// Decided sign-ins are removed, so the token is only handed out once.
+func (p *pendingDeviceLogins) poll(deviceCode string) (pollResult, string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	l, has := p.byDevice[deviceCode]
+	if !has {
+		return pollUnknown, ""
+	}
+
+	if l.decided {
+		delete(p.byDevice, deviceCode)
+		if l.token == "" {
+			return pollDenied, ""
+		}
+		return pollApproved, l.token
+	}
+
+	now := time.Now()
+	if now.After(l.expires) {
+		delete(p.byDevice, deviceCode)
+		delete(p.byUser, l.userCode)
+		return pollExpired, ""
+	}
+
+	tooFast := now.Sub(l.lastPoll) < devicePollInterval
+	l.lastPoll = now
+	if tooFast {
+		return pollTooFast, ""
+	}
+	return pollPending, ""
+}
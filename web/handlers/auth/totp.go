@@ -0,0 +1,482 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/csrf"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/sessions"
+	"go.mindeco.de/http/render"
+	"go.mindeco.de/log/level"
+	"go.mindeco.de/logging"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web"
+	weberrors "github.com/ssbc/go-ssb-room/v2/web/errors"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+)
+
+const (
+	passwordSessionName = "AuthWithPasswordSession"
+	totpPendingName     = "TOTPPending"
+
+	// how long members have to enter the code after the password
+	totpPendingTimeout = 5 * time.Minute
+
+	// wrong codes before the password has to be entered again
+	totpMaxAttempts = 5
+)
+
+// WithTOTPHandler handles the fallback password sign-in.
+// Members who enabled two-factor authentication have to enter a code from their authenticator app
+// (or one of their recovery codes) after the password.
+// Failed sign-ins, with a wrong password or a wrong code, are counted per login and address, to lock out those who try to guess them.
+// The count is only reset once the whole sign-in succeeded.
+type WithTOTPHandler struct {
+	render  *render.Renderer
+	flashes *weberrors.FlashHelper
+
+	membersdb  roomdb.MembersService
+	fallbackdb roomdb.AuthFallbackService
+	totpdb     roomdb.TOTPService
+	configdb   roomdb.RoomConfig
+	lockoutdb  roomdb.LoginLockoutService
+	deniedkeys roomdb.DeniedKeysService
+
+	cookieStore sessions.Store
+
+	pending *pendingTOTPLogins
+}
+
+func NewWithTOTPHandler(
+	m *mux.Router,
+	r *render.Renderer,
+	flashes *weberrors.FlashHelper,
+	membersDB roomdb.MembersService,
+	fallbackDB roomdb.AuthFallbackService,
+	totpDB roomdb.TOTPService,
+	configDB roomdb.RoomConfig,
+	lockoutDB roomdb.LoginLockoutService,
+	deniedKeysDB roomdb.DeniedKeysService,
+	cookies sessions.Store,
+) *WithTOTPHandler {
+	var h WithTOTPHandler
+	h.render = r
+	h.flashes = flashes
+	h.membersdb = membersDB
+	h.fallbackdb = fallbackDB
+	h.totpdb = totpDB
+	h.configdb = configDB
+	h.lockoutdb = lockoutDB
+	h.deniedkeys = deniedKeysDB
+	h.cookieStore = cookies
+	h.pending = &pendingTOTPLogins{logins: make(map[string]pendingTOTPLogin)}
+
+	m.Get(router.AuthFallbackFinalize).HandlerFunc(h.finalizePassword)
+	m.Get(router.AuthFallbackTOTP).HandlerFunc(r.HTML("auth/fallback_totp.tmpl", h.codeForm))
+	m.Get(router.AuthFallbackTOTPFinalize).HandlerFunc(h.finalizeCode)
+
+	return &h
+}
+
+// AuthenticateRequest uses the passed request to load and return the session data that was stored previously.
+// If it is invalid or there is no session, it will return ErrNotAuthorized.
+func (h WithTOTPHandler) AuthenticateRequest(r *http.Request) (*roomdb.Member, error) {
+	session, err := h.cookieStore.Get(r, passwordSessionName)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.IsNew {
+		return nil, weberrors.ErrNotAuthorized
+	}
+
+	// the session store takes care of the timeouts
+	memberID, ok := session.Values[web.SessionMemberKey].(int64)
+	if !ok {
+		return nil, weberrors.ErrNotAuthorized
+	}
+
+	member, err := h.membersdb.GetByID(r.Context(), memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+// Logout destroys the session data and updates the cookie with an invalidated one.
+func (h WithTOTPHandler) Logout(w http.ResponseWriter, r *http.Request) error {
+	session, err := h.cookieStore.Get(r, passwordSessionName)
+	if err != nil {
+		return err
+	}
+
+	if session.IsNew {
+		// not a password session
+		return nil
+	}
+
+	session.Options.MaxAge = -1
+	return session.Save(r, w)
+}
+
+// finalizePassword checks the password of the sign-in form.
+// If the member needs a second factor, the code is asked for next. Otherwise the session starts right away.
+func (h WithTOTPHandler) finalizePassword(w http.ResponseWriter, req *http.Request) {
+	if err := req.ParseForm(); err != nil {
+		h.render.Error(w, req, http.StatusBadRequest, weberrors.ErrBadRequest{Where: "Form data", Details: err})
+		return
+	}
+
+	ctx := req.Context()
+	logger := logging.FromContext(ctx)
+
+	login := req.FormValue("user")
+	remoteIP := web.RemoteIP(req, false)
+
+	if !h.checkLockout(w, req, login, remoteIP) {
+		return
+	}
+
+	checked, err := h.fallbackdb.Check(login, req.FormValue("pass"))
+	if err != nil {
+		var badLogin weberrors.ErrRedirect
+		if !errors.As(err, &badLogin) {
+			h.render.Error(w, req, http.StatusInternalServerError, err)
+			return
+		}
+
+		level.Warn(logger).Log("event", "fallback sign-in failed", "login", login, "addr", remoteIP)
+		h.countFailure(req, login, remoteIP)
+
+		h.render.Error(w, req, http.StatusForbidden, err)
+		return
+	}
+
+	memberID, ok := checked.(int64)
+	if !ok {
+		h.render.Error(w, req, http.StatusInternalServerError, fmt.Errorf("unexpected member id: %T", checked))
+		return
+	}
+
+	member, err := h.membersdb.GetByID(ctx, memberID)
+	if err != nil {
+		h.render.Error(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	// the member might have been suspended, for instance through the invite tree
+	if h.deniedkeys.HasFeed(ctx, member.PubKey) {
+		level.Warn(logger).Log("event", "fallback sign-in of denied member", "login", login, "addr", remoteIP)
+		h.render.Error(w, req, http.StatusForbidden, weberrors.ErrRedirect{
+			Path:   routePath(router.AuthFallbackLogin),
+			Reason: weberrors.ErrGenericLocalized{Label: "ErrorAuthDenied"},
+		})
+		return
+	}
+
+	status, err := h.totpdb.Status(ctx, memberID)
+	if err != nil {
+		h.render.Error(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	if !status.Enabled {
+		if member.Role == roomdb.RoleAdmin || member.Role == roomdb.RoleModerator {
+			mandatory, err := h.configdb.GetTOTPMandatory(ctx)
+			if err != nil {
+				h.render.Error(w, req, http.StatusInternalServerError, err)
+				return
+			}
+
+			if mandatory {
+				h.render.Error(w, req, http.StatusForbidden, weberrors.ErrRedirect{
+					Path:   routePath(router.AuthFallbackLogin),
+					Reason: weberrors.ErrGenericLocalized{Label: "ErrorAuthTOTPRequired"},
+				})
+				return
+			}
+		}
+
+		h.startSession(w, req, memberID, login)
+		return
+	}
+
+	pendingID, err := h.pending.add(memberID, login)
+	if err != nil {
+		h.render.Error(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	session, err := h.cookieStore.Get(req, totpPendingName)
+	if err != nil {
+		h.render.Error(w, req, http.StatusInternalServerError, err)
+		return
+	}
+	session.Values[totpPending] = pendingID
+	session.Options.MaxAge = int(totpPendingTimeout.Seconds())
+	if err := session.Save(req, w); err != nil {
+		h.render.Error(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	http.Redirect(w, req, routePath(router.AuthFallbackTOTP), http.StatusSeeOther)
+}
+
+// codeForm asks for the code, if the password was entered recently
+func (h WithTOTPHandler) codeForm(w http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if _, _, ok := h.loadPending(req); !ok {
+		return nil, weberrors.ErrRedirect{
+			Path:   routePath(router.AuthFallbackLogin),
+			Reason: weberrors.ErrGenericLocalized{Label: "ErrorAuthTOTPExpired"},
+		}
+	}
+
+	flashes, err := h.flashes.GetAll(w, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		csrf.TemplateTag: csrf.TemplateField(req),
+		"Flashes":        flashes,
+	}, nil
+}
+
+// finalizeCode checks the code and starts the session
+func (h WithTOTPHandler) finalizeCode(w http.ResponseWriter, req *http.Request) {
+	if err := req.ParseForm(); err != nil {
+		h.render.Error(w, req, http.StatusBadRequest, weberrors.ErrBadRequest{Where: "Form data", Details: err})
+		return
+	}
+
+	pendingID, pending, ok := h.loadPending(req)
+	if !ok {
+		h.render.Error(w, req, http.StatusForbidden, weberrors.ErrRedirect{
+			Path:   routePath(router.AuthFallbackLogin),
+			Reason: weberrors.ErrGenericLocalized{Label: "ErrorAuthTOTPExpired"},
+		})
+		return
+	}
+
+	// the codes count against the same limits as the passwords
+	remoteIP := web.RemoteIP(req, false)
+	if !h.checkLockout(w, req, pending.login, remoteIP) {
+		h.pending.remove(pendingID)
+		return
+	}
+
+	err := h.totpdb.Verify(req.Context(), pending.memberID, req.FormValue("code"))
+	if err != nil {
+		if !errors.Is(err, roomdb.ErrInvalidTOTPCode) {
+			h.render.Error(w, req, http.StatusInternalServerError, err)
+			return
+		}
+
+		level.Warn(logging.FromContext(req.Context())).Log("event", "fallback sign-in failed", "reason", "wrong code", "login", pending.login, "addr", remoteIP)
+		h.countFailure(req, pending.login, remoteIP)
+
+		// after too many tries, start over with the password
+		retry := routePath(router.AuthFallbackTOTP)
+		if !h.pending.fail(pendingID) {
+			retry = routePath(router.AuthFallbackLogin)
+		}
+
+		h.render.Error(w, req, http.StatusForbidden, weberrors.ErrRedirect{
+			Path:   retry,
+			Reason: weberrors.ErrGenericLocalized{Label: "ErrorAuthTOTPBadCode"},
+		})
+		return
+	}
+
+	h.pending.remove(pendingID)
+
+	pendingSession, err := h.cookieStore.Get(req, totpPendingName)
+	if err != nil {
+		h.render.Error(w, req, http.StatusInternalServerError, err)
+		return
+	}
+	delete(pendingSession.Values, totpPending)
+	pendingSession.Options.MaxAge = -1
+	if err := pendingSession.Save(req, w); err != nil {
+		h.render.Error(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	h.startSession(w, req, pending.memberID, pending.login)
+}
+
+// checkLockout renders an error and returns false if the login or the address are locked out.
+// The log lines of the sign-in steps are meant to be read by tools like fail2ban, keep their form stable.
+func (h WithTOTPHandler) checkLockout(w http.ResponseWriter, req *http.Request, login, remoteIP string) bool {
+	err := h.lockoutdb.Check(req.Context(), login, remoteIP)
+	if err == nil {
+		return true
+	}
+
+	var locked roomdb.ErrLockedOut
+	if !errors.As(err, &locked) {
+		h.render.Error(w, req, http.StatusInternalServerError, err)
+		return false
+	}
+
+	level.Warn(logging.FromContext(req.Context())).Log("event", "fallback sign-in rejected", "reason", "locked out", "login", login, "addr", remoteIP)
+	h.render.Error(w, req, http.StatusTooManyRequests, weberrors.ErrRedirect{
+		Path:   routePath(router.AuthFallbackLogin),
+		Reason: weberrors.ErrGenericLocalized{Label: "ErrorAuthLockedOut"},
+	})
+	return false
+}
+
+// countFailure records a wrong password or code and logs the lockouts it started
+func (h WithTOTPHandler) countFailure(req *http.Request, login, remoteIP string) {
+	logger := logging.FromContext(req.Context())
+
+	started, err := h.lockoutdb.Failed(req.Context(), login, remoteIP)
+	if err != nil {
+		level.Error(logger).Log("event", "failed to count failed sign-in", "err", err)
+	}
+	for _, l := range started {
+		level.Warn(logger).Log("event", "fallback sign-in locked out",
+			"kind", l.Kind,
+			"subject", l.Subject,
+			"failures", l.Failures,
+			"until", l.LockedUntil.Format(time.RFC3339),
+			"addr", remoteIP,
+		)
+	}
+}
+
+// startSession resets the failed sign-ins of the login, stores the member in the session and sends them to the dashboard
+func (h WithTOTPHandler) startSession(w http.ResponseWriter, req *http.Request, memberID int64, login string) {
+	if err := h.lockoutdb.Succeeded(req.Context(), login); err != nil {
+		level.Error(logging.FromContext(req.Context())).Log("event", "failed to reset failed sign-ins", "err", err)
+	}
+
+	session, err := h.cookieStore.Get(req, passwordSessionName)
+	if err != nil {
+		h.render.Error(w, req, http.StatusInternalServerError, err)
+		return
+	}
+	session.Values[web.SessionMemberKey] = memberID
+	if err := session.Save(req, w); err != nil {
+		h.render.Error(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	http.Redirect(w, req, routePath(router.AdminDashboard), http.StatusSeeOther)
+}
+
+// loadPending returns the sign-in that is waiting for its code
+func (h WithTOTPHandler) loadPending(req *http.Request) (string, pendingTOTPLogin, bool) {
+	session, err := h.cookieStore.Get(req, totpPendingName)
+	if err != nil || session.IsNew {
+		return "", pendingTOTPLogin{}, false
+	}
+
+	pendingID, ok := session.Values[totpPending].(string)
+	if !ok {
+		return "", pendingTOTPLogin{}, false
+	}
+
+	pending, ok := h.pending.get(pendingID)
+	return pendingID, pending, ok
+}
+
+// routePath returns the path of a route without parameters
+func routePath(name string) string {
+	u, err := router.CompleteApp().Get(name).URL()
+	if err != nil {
+		panic(fmt.Sprintf("auth: no route %q: %s", name, err))
+	}
+	return u.Path
+}
+
+// pendingTOTPLogins keeps the sign-ins that passed the password check but still need a code.
+// They are kept on the server (and not just in the cookie) so that the number of attempts can be limited.
+type pendingTOTPLogins struct {
+	mu     sync.Mutex
+	logins map[string]pendingTOTPLogin
+}
+
+type pendingTOTPLogin struct {
+	memberID int64
+	// what was entered in the sign-in form, for the lockouts
+	login    string
+	expires  time.Time
+	attempts int
+}
+
+func (p *pendingTOTPLogins) add(memberID int64, login string) (string, error) {
+	idBytes := make([]byte, 32)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", err
+	}
+	id := base64.URLEncoding.EncodeToString(idBytes)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// drop the ones that were abandoned
+	now := time.Now()
+	for pid, l := range p.logins {
+		if now.After(l.expires) {
+			delete(p.logins, pid)
+		}
+	}
+
+	p.logins[id] = pendingTOTPLogin{
+		memberID: memberID,
+		login:    login,
+		expires:  now.Add(totpPendingTimeout),
+	}
+	return id, nil
+}
+
+func (p *pendingTOTPLogins) get(id string) (pendingTOTPLogin, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	l, has := p.logins[id]
+	if !has || time.Now().After(l.expires) {
+		return pendingTOTPLogin{}, false
+	}
+	return l, true
+}
+
+// fail counts a wrong code and returns false if there are no attempts left
+func (p *pendingTOTPLogins) fail(id string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	l, has := p.logins[id]
+	if !has {
+		return false
+	}
+
+	l.attempts++
+	if l.attempts >= totpMaxAttempts {
+		delete(p.logins, id)
+		return false
+	}
+
+	p.logins[id] = l
+	return true
+}
+
+func (p *pendingTOTPLogins) remove(id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.logins, id)
+}
@@ -0,0 +1,508 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/gorilla/csrf"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/sessions"
+	"go.mindeco.de/http/render"
+	"go.mindeco.de/log/level"
+	"go.mindeco.de/logging"
+
+	refs "github.com/ssbc/go-ssb-refs"
+	"github.com/ssbc/go-ssb-room/v2/internal/network"
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web"
+	weberrors "github.com/ssbc/go-ssb-room/v2/web/errors"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+)
+
+const (
+	webauthnSessionName  = "AuthWithWebAuthnSession"
+	webauthnCeremonyName = "WebAuthnCeremony"
+
+	// how long a started registration or sign-in is valid
+	webauthnCeremonyTimeout = 5 * time.Minute
+)
+
+// WithWebAuthnHandler lets members sign in with the passkeys (WebAuthn credentials) they registered.
+// It also implements the ceremony to register new ones, which the members pages use.
+type WithWebAuthnHandler struct {
+	render *render.Renderer
+
+	webauthn *webauthn.WebAuthn
+
+	membersdb     roomdb.MembersService
+	aliasesdb     roomdb.AliasesService
+	credentialsdb roomdb.WebAuthnService
+	deniedkeysdb  roomdb.DeniedKeysService
+
+	cookieStore sessions.Store
+}
+
+func NewWithWebAuthnHandler(
+	m *mux.Router,
+	r *render.Renderer,
+	netInfo network.ServerEndpointDetails,
+	aliasDB roomdb.AliasesService,
+	membersDB roomdb.MembersService,
+	credentialsDB roomdb.WebAuthnService,
+	deniedKeysDB roomdb.DeniedKeysService,
+	cookies sessions.Store,
+) (*WithWebAuthnHandler, error) {
+
+	// the origin has to match what the browser sees, otherwise the authenticator responses are rejected
+	origin := url.URL{Scheme: "https", Host: netInfo.Domain}
+	if netInfo.Development {
+		origin.Scheme = "http"
+		origin.Host += fmt.Sprintf(":%d", netInfo.PortHTTPS)
+	}
+
+	wa, err := webauthn.New(&webauthn.Config{
+		RPDisplayName: netInfo.Domain,
+		RPID:          netInfo.Domain,
+		RPOrigin:      origin.String(),
+
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			UserVerification: protocol.VerificationPreferred,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("webauthn: failed to initialize: %w", err)
+	}
+
+	var h WithWebAuthnHandler
+	h.render = r
+	h.webauthn = wa
+	h.aliasesdb = aliasDB
+	h.membersdb = membersDB
+	h.credentialsdb = credentialsDB
+	h.deniedkeysdb = deniedKeysDB
+	h.cookieStore = cookies
+
+	m.Get(router.AuthWebAuthnLogin).HandlerFunc(r.HTML("auth/webauthn_sign_in.tmpl", h.loginForm))
+	m.Get(router.AuthWebAuthnBegin).HandlerFunc(h.beginLogin)
+	m.Get(router.AuthWebAuthnFinalize).HandlerFunc(h.finalizeLogin)
+
+	return &h, nil
+}
+
+// AuthenticateRequest uses the passed request to load and return the session data that was stored previously.
+// If it is invalid or there is no session, it will return ErrNotAuthorized.
+// Sessions end when the passkey that was used to start them is removed.
+func (h WithWebAuthnHandler) AuthenticateRequest(r *http.Request) (*roomdb.Member, error) {
+	session, err := h.cookieStore.Get(r, webauthnSessionName)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.IsNew {
+		return nil, weberrors.ErrNotAuthorized
+	}
+
+	// the session store takes care of the timeouts
+	memberID, ok := session.Values[web.SessionMemberKey].(int64)
+	if !ok {
+		return nil, weberrors.ErrNotAuthorized
+	}
+
+	credentialID, ok := session.Values[webauthnCredential].(int64)
+	if !ok {
+		return nil, weberrors.ErrNotAuthorized
+	}
+
+	if _, err := h.credentialsdb.GetCredential(r.Context(), memberID, credentialID); err != nil {
+		if errors.Is(err, roomdb.ErrNotFound) {
+			return nil, weberrors.ErrNotAuthorized
+		}
+		return nil, err
+	}
+
+	member, err := h.membersdb.GetByID(r.Context(), memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+// Logout destroys the session data and updates the cookie with an invalidated one.
+func (h WithWebAuthnHandler) Logout(w http.ResponseWriter, r *http.Request) error {
+	session, err := h.cookieStore.Get(r, webauthnSessionName)
+	if err != nil {
+		return err
+	}
+
+	if session.IsNew {
+		// not a webauthn session
+		return nil
+	}
+
+	session.Options.MaxAge = -1
+	return session.Save(r, w)
+}
+
+func (h WithWebAuthnHandler) loginForm(w http.ResponseWriter, req *http.Request) (interface{}, error) {
+	return map[string]interface{}{
+		"csrfToken": csrf.Token(req),
+	}, nil
+}
+
+// beginLogin looks up the passkeys of the member and sends the challenge for the browser to sign
+func (h WithWebAuthnHandler) beginLogin(w http.ResponseWriter, req *http.Request) {
+	member, err := memberFromLogin(req.Context(), h.aliasesdb, h.membersdb, req.FormValue("user"))
+	if err != nil {
+		sendWebAuthnError(w, req, http.StatusForbidden, err)
+		return
+	}
+
+	user, err := h.loadUser(req, member)
+	if err != nil {
+		sendWebAuthnError(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	if len(user.credentials) == 0 {
+		// don't tell apart unknown members and members without passkeys
+		sendWebAuthnError(w, req, http.StatusForbidden, weberrors.ErrNotAuthorized)
+		return
+	}
+
+	assertion, sessionData, err := h.webauthn.BeginLogin(user)
+	if err != nil {
+		sendWebAuthnError(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := h.saveCeremony(w, req, member.ID, sessionData); err != nil {
+		sendWebAuthnError(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	sendWebAuthnJSON(w, req, assertion)
+}
+
+// finalizeLogin checks the signed challenge and starts the session
+func (h WithWebAuthnHandler) finalizeLogin(w http.ResponseWriter, req *http.Request) {
+	memberID, sessionData, err := h.loadCeremony(w, req)
+	if err != nil {
+		sendWebAuthnError(w, req, http.StatusBadRequest, err)
+		return
+	}
+
+	member, err := h.membersdb.GetByID(req.Context(), memberID)
+	if err != nil {
+		sendWebAuthnError(w, req, http.StatusForbidden, err)
+		return
+	}
+
+	user, err := h.loadUser(req, member)
+	if err != nil {
+		sendWebAuthnError(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	signedWith, err := h.webauthn.FinishLogin(user, *sessionData, req)
+	if err != nil {
+		sendWebAuthnError(w, req, http.StatusForbidden, fmt.Errorf("webauthn: sign-in failed: %w", err))
+		return
+	}
+
+	if signedWith.Authenticator.CloneWarning {
+		sendWebAuthnError(w, req, http.StatusForbidden, fmt.Errorf("webauthn: signature counter went backwards, the passkey might be cloned"))
+		return
+	}
+
+	cred, has := user.byCredentialID(signedWith.ID)
+	if !has {
+		sendWebAuthnError(w, req, http.StatusForbidden, fmt.Errorf("webauthn: unknown credential"))
+		return
+	}
+
+	if err := h.credentialsdb.MarkUsed(req.Context(), cred.ID, signedWith.Authenticator.SignCount); err != nil {
+		sendWebAuthnError(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	// the member might have been suspended, for instance through the invite tree
+	if h.deniedkeysdb.HasFeed(req.Context(), member.PubKey) {
+		sendWebAuthnError(w, req, http.StatusForbidden, weberrors.ErrDenied)
+		return
+	}
+
+	session, err := h.cookieStore.Get(req, webauthnSessionName)
+	if err != nil {
+		sendWebAuthnError(w, req, http.StatusInternalServerError, err)
+		return
+	}
+	session.Values[web.SessionMemberKey] = member.ID
+	session.Values[webauthnCredential] = cred.ID
+	if err := session.Save(req, w); err != nil {
+		sendWebAuthnError(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	dashboardURL, err := router.CompleteApp().Get(router.AdminDashboard).URL()
+	if err != nil {
+		sendWebAuthnError(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	sendWebAuthnJSON(w, req, struct {
+		Status   string `json:"status"`
+		Redirect string `json:"redirect"`
+	}{"successful", dashboardURL.Path})
+}
+
+// BeginRegistration sends the options for a new passkey of the member to the browser.
+// The member needs to be signed in already, which the caller has to check.
+func (h WithWebAuthnHandler) BeginRegistration(w http.ResponseWriter, req *http.Request, member roomdb.Member) {
+	user, err := h.loadUser(req, member)
+	if err != nil {
+		sendWebAuthnError(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	// don't register the same authenticator twice
+	exclude := make([]protocol.CredentialDescriptor, len(user.credentials))
+	for i, c := range user.credentials {
+		exclude[i] = protocol.CredentialDescriptor{
+			Type:         protocol.PublicKeyCredentialType,
+			CredentialID: c.CredentialID,
+		}
+	}
+
+	creation, sessionData, err := h.webauthn.BeginRegistration(user, webauthn.WithExclusions(exclude))
+	if err != nil {
+		sendWebAuthnError(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := h.saveCeremony(w, req, member.ID, sessionData); err != nil {
+		sendWebAuthnError(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	sendWebAuthnJSON(w, req, creation)
+}
+
+// FinishRegistration checks the response of the authenticator and stores the new passkey under the passed name.
+func (h WithWebAuthnHandler) FinishRegistration(w http.ResponseWriter, req *http.Request, member roomdb.Member, name string) {
+	memberID, sessionData, err := h.loadCeremony(w, req)
+	if err != nil {
+		sendWebAuthnError(w, req, http.StatusBadRequest, err)
+		return
+	}
+
+	if memberID != member.ID {
+		sendWebAuthnError(w, req, http.StatusForbidden, fmt.Errorf("webauthn: registration was started by another member"))
+		return
+	}
+
+	user, err := h.loadUser(req, member)
+	if err != nil {
+		sendWebAuthnError(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	created, err := h.webauthn.FinishRegistration(user, *sessionData, req)
+	if err != nil {
+		sendWebAuthnError(w, req, http.StatusBadRequest, fmt.Errorf("webauthn: registration failed: %w", err))
+		return
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "passkey"
+	}
+
+	_, err = h.credentialsdb.AddCredential(req.Context(), member.ID, roomdb.WebAuthnCredential{
+		Name:            name,
+		CredentialID:    created.ID,
+		PublicKey:       created.PublicKey,
+		AttestationType: created.AttestationType,
+		SignCount:       created.Authenticator.SignCount,
+	})
+	if err != nil {
+		sendWebAuthnError(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	sendWebAuthnJSON(w, req, struct {
+		Status string `json:"status"`
+	}{"successful"})
+}
+
+// memberFromLogin resolves the passed alias or feed reference to a member
+func memberFromLogin(ctx context.Context, aliasesdb roomdb.AliasesService, membersdb roomdb.MembersService, login string) (roomdb.Member, error) {
+	login = strings.TrimSpace(login)
+
+	feed, err := refs.ParseFeedRef(login)
+	if err != nil {
+		alias, err := aliasesdb.Resolve(ctx, login)
+		if err != nil {
+			return roomdb.Member{}, weberrors.ErrNotAuthorized
+		}
+		feed = alias.Feed
+	}
+
+	member, err := membersdb.GetByFeed(ctx, feed)
+	if err != nil {
+		return roomdb.Member{}, weberrors.ErrNotAuthorized
+	}
+
+	return member, nil
+}
+
+func (h WithWebAuthnHandler) loadUser(req *http.Request, m roomdb.Member) (webauthnUser, error) {
+	creds, err := h.credentialsdb.ListCredentials(req.Context(), m.ID)
+	if err != nil {
+		return webauthnUser{}, err
+	}
+	return webauthnUser{member: m, credentials: creds}, nil
+}
+
+// saveCeremony stores the challenge of a started registration or sign-in, until the browser replies
+func (h WithWebAuthnHandler) saveCeremony(w http.ResponseWriter, req *http.Request, memberID int64, data *webauthn.SessionData) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	session, err := h.cookieStore.Get(req, webauthnCeremonyName)
+	if err != nil {
+		return err
+	}
+
+	session.Values[webauthnMember] = memberID
+	session.Values[webauthnCeremony] = encoded
+	session.Values[userTimeout] = time.Now().Add(webauthnCeremonyTimeout)
+	session.Options.MaxAge = int(webauthnCeremonyTimeout.Seconds())
+	return session.Save(req, w)
+}
+
+// loadCeremony returns the data stored by saveCeremony and removes it, so that every challenge can only be used once
+func (h WithWebAuthnHandler) loadCeremony(w http.ResponseWriter, req *http.Request) (int64, *webauthn.SessionData, error) {
+	session, err := h.cookieStore.Get(req, webauthnCeremonyName)
+	if err != nil {
+		return -1, nil, err
+	}
+
+	errNoCeremony := fmt.Errorf("webauthn: no registration or sign-in was started")
+	if session.IsNew {
+		return -1, nil, errNoCeremony
+	}
+
+	memberID, ok := session.Values[webauthnMember].(int64)
+	if !ok {
+		return -1, nil, errNoCeremony
+	}
+
+	encoded, ok := session.Values[webauthnCeremony].([]byte)
+	if !ok {
+		return -1, nil, errNoCeremony
+	}
+
+	tout, ok := session.Values[userTimeout].(time.Time)
+	if !ok || time.Now().After(tout) {
+		return -1, nil, fmt.Errorf("webauthn: the registration or sign-in took too long")
+	}
+
+	session.Options.MaxAge = -1
+	if err := session.Save(req, w); err != nil {
+		return -1, nil, err
+	}
+
+	var data webauthn.SessionData
+	if err := json.Unmarshal(encoded, &data); err != nil {
+		return -1, nil, err
+	}
+
+	return memberID, &data, nil
+}
+
+func sendWebAuthnJSON(w http.ResponseWriter, req *http.Request, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		level.Warn(logging.FromContext(req.Context())).Log("event", "sending json response failed", "err", err)
+	}
+}
+
+func sendWebAuthnError(w http.ResponseWriter, req *http.Request, code int, err error) {
+	level.Debug(logging.FromContext(req.Context())).Log("event", "webauthn failed", "err", err)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+	}{"error", err.Error()})
+}
+
+// webauthnUser implements webauthn.User for a member and their credentials
+type webauthnUser struct {
+	member      roomdb.Member
+	credentials []roomdb.WebAuthnCredential
+}
+
+var _ webauthn.User = webauthnUser{}
+
+// WebAuthnID returns the member id, which doesn't change, unlike the aliases
+func (u webauthnUser) WebAuthnID() []byte {
+	id := make([]byte, 8)
+	binary.BigEndian.PutUint64(id, uint64(u.member.ID))
+	return id
+}
+
+func (u webauthnUser) WebAuthnName() string {
+	return u.member.PubKey.String()
+}
+
+func (u webauthnUser) WebAuthnDisplayName() string {
+	if len(u.member.Aliases) > 0 {
+		return u.member.Aliases[0].Name
+	}
+	return u.member.PubKey.ShortSigil()
+}
+
+func (u webauthnUser) WebAuthnIcon() string { return "" }
+
+func (u webauthnUser) WebAuthnCredentials() []webauthn.Credential {
+	creds := make([]webauthn.Credential, len(u.credentials))
+	for i, c := range u.credentials {
+		creds[i] = webauthn.Credential{
+			ID:              c.CredentialID,
+			PublicKey:       c.PublicKey,
+			AttestationType: c.AttestationType,
+			Authenticator: webauthn.Authenticator{
+				SignCount: c.SignCount,
+			},
+		}
+	}
+	return creds
+}
+
+func (u webauthnUser) byCredentialID(id []byte) (roomdb.WebAuthnCredential, bool) {
+	for _, c := range u.credentials {
+		if bytes.Equal(c.CredentialID, id) {
+			return c, true
+		}
+	}
+	return roomdb.WebAuthnCredential{}, false
+}
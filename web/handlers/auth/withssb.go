@@ -39,7 +39,9 @@ import (
 var HTMLTemplates = []string{
 	"auth/decide_method.tmpl",
 	"auth/fallback_sign_in.tmpl",
+	"auth/fallback_totp.tmpl",
 	"auth/withssb_server_start.tmpl",
+	"auth/webauthn_sign_in.tmpl",
 }
 
 // custom sessionKey type to prevent collision
@@ -56,8 +58,20 @@ const (
 
 	memberToken sessionKey = iota
 	userTimeout
+
+	// used by the webauthn handler
+	webauthnMember
+	webauthnCredential
+	webauthnCeremony
+
+	// used by the totp handler
+	totpPending
+
+	// where to go after signing in with ssb, see RedirectAfterSignIn
+	returnTo
 )
 
+// sessionLifetime is how long the tokens of sign-in with ssb are valid (see roomdb/sqlite), independent of the session they are stored in
 const sessionLifetime = time.Hour * 24
 
 // WithSSBHandler implements the oauth-like challenge/response dance described in
@@ -80,6 +94,9 @@ type WithSSBHandler struct {
 	endpoints network.Endpoints
 
 	bridge *signinwithssb.SignalBridge
+
+	// fullRemoteIPs disables the truncation of the addresses that are stored with the sessions
+	fullRemoteIPs bool
 }
 
 func NewWithSSBHandler(
@@ -92,6 +109,7 @@ func NewWithSSBHandler(
 	sessiondb roomdb.AuthWithSSBService,
 	cookies sessions.Store,
 	bridge *signinwithssb.SignalBridge,
+	fullRemoteIPs bool,
 ) *WithSSBHandler {
 
 	var ssb WithSSBHandler
@@ -104,6 +122,7 @@ func NewWithSSBHandler(
 	ssb.sessiondb = sessiondb
 	ssb.cookieStore = cookies
 	ssb.bridge = bridge
+	ssb.fullRemoteIPs = fullRemoteIPs
 
 	m.Get(router.AuthWithSSBLogin).HandlerFunc(ssb.DecideMethod)
 	m.Get(router.AuthWithSSBServerEvents).HandlerFunc(ssb.eventSource)
@@ -125,25 +144,12 @@ func (h WithSSBHandler) AuthenticateRequest(r *http.Request) (*roomdb.Member, er
 		return nil, weberrors.ErrNotAuthorized
 	}
 
+	// the session store takes care of the timeouts
 	tokenVal, ok := session.Values[memberToken]
 	if !ok {
 		return nil, weberrors.ErrNotAuthorized
 	}
 
-	t, ok := session.Values[userTimeout]
-	if !ok {
-		return nil, weberrors.ErrNotAuthorized
-	}
-
-	tout, ok := t.(time.Time)
-	if !ok {
-		return nil, weberrors.ErrNotAuthorized
-	}
-
-	if time.Now().After(tout) {
-		return nil, weberrors.ErrNotAuthorized
-	}
-
 	token, ok := tokenVal.(string)
 	if !ok {
 		return nil, weberrors.ErrNotAuthorized
@@ -185,7 +191,6 @@ func (h WithSSBHandler) Logout(w http.ResponseWriter, r *http.Request) error {
 		return err
 	}
 
-	session.Values[userTimeout] = time.Now().Add(-sessionLifetime)
 	session.Options.MaxAge = -1
 	if err := session.Save(r, w); err != nil {
 		return err
@@ -194,22 +199,58 @@ func (h WithSSBHandler) Logout(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
-// saveCookie is a utility function that stores the passed token inside the cookie
-func (h WithSSBHandler) saveCookie(w http.ResponseWriter, req *http.Request, token string) error {
+// RedirectAfterSignIn remembers a page of this room to go to once the member signed in with ssb,
+// instead of the landing page or the dashboard. It's used by pages that need a member, like the consent page of other apps.
+func (h WithSSBHandler) RedirectAfterSignIn(w http.ResponseWriter, req *http.Request, path string) error {
+	if !isLocalPath(path) {
+		return fmt.Errorf("ssb http auth: not a local path: %q", path)
+	}
+
+	session, err := h.cookieStore.Get(req, siwssbSessionName)
+	if err != nil {
+		return fmt.Errorf("ssb http auth: failed to load cookie session: %w", err)
+	}
+
+	session.Values[returnTo] = path
+	if err := session.Save(req, w); err != nil {
+		return fmt.Errorf("ssb http auth: failed to update cookie session: %w", err)
+	}
+
+	return nil
+}
+
+// isLocalPath makes sure the redirect stays on this site
+func isLocalPath(path string) bool {
+	return strings.HasPrefix(path, "/") && !strings.HasPrefix(path, "//") && !strings.HasPrefix(path, "/\\")
+}
+
+// saveCookie is a utility function that stores the passed token of the member inside the session.
+// It returns the page that was remembered with RedirectAfterSignIn or an empty string.
+func (h WithSSBHandler) saveCookie(w http.ResponseWriter, req *http.Request, memberID int64, token string) (string, error) {
 	session, err := h.cookieStore.Get(req, siwssbSessionName)
 	if err != nil {
 		err = fmt.Errorf("ssb http auth: failed to load cookie session: %w", err)
-		return err
+		return "", err
 	}
 
+	next, _ := session.Values[returnTo].(string)
+	delete(session.Values, returnTo)
+
 	session.Values[memberToken] = token
-	session.Values[userTimeout] = time.Now().Add(sessionLifetime)
+	session.Values[web.SessionMemberKey] = memberID
 	if err := session.Save(req, w); err != nil {
 		err = fmt.Errorf("ssb http auth: failed to update cookie session: %w", err)
-		return err
+		return "", err
 	}
 
-	return nil
+	// remember where the session is used, so that members can tell their sessions apart
+	remoteIP := web.RemoteIP(req, !h.fullRemoteIPs)
+	err = h.sessiondb.SetClientDetails(req.Context(), token, req.UserAgent(), remoteIP)
+	if err != nil {
+		level.Warn(logging.FromContext(req.Context())).Log("event", "failed to store session details", "err", err)
+	}
+
+	return next, nil
 }
 
 // this is the /login landing page which branches out to the different methods
@@ -325,17 +366,21 @@ func (h WithSSBHandler) clientInitiated(w http.ResponseWriter, req *http.Request
 		return err
 	}
 
-	if err := h.saveCookie(w, req, tok); err != nil {
+	next, err := h.saveCookie(w, req, member.ID, tok)
+	if err != nil {
 		return err
 	}
 
-	// go to the dashboard
-	dashboardURL, err := router.CompleteApp().Get(router.AdminDashboard).URL()
-	if err != nil {
-		return err
+	// go to the dashboard, unless a page asked for the sign-in
+	if next == "" {
+		dashboardURL, err := router.CompleteApp().Get(router.AdminDashboard).URL()
+		if err != nil {
+			return err
+		}
+		next = dashboardURL.Path
 	}
 
-	http.Redirect(w, req, dashboardURL.Path, http.StatusTemporaryRedirect)
+	http.Redirect(w, req, next, http.StatusTemporaryRedirect)
 	return nil
 }
 
@@ -414,17 +459,23 @@ func (h WithSSBHandler) finalizeCookie(w http.ResponseWriter, r *http.Request) {
 	tok := r.URL.Query().Get("token")
 
 	// check the token is correct
-	if _, err := h.sessiondb.CheckToken(r.Context(), tok); err != nil {
+	memberID, err := h.sessiondb.CheckToken(r.Context(), tok)
+	if err != nil {
 		http.Error(w, "invalid session token", http.StatusForbidden)
 		return
 	}
 
-	if err := h.saveCookie(w, r, tok); err != nil {
+	next, err := h.saveCookie(w, r, memberID, tok)
+	if err != nil {
 		http.Error(w, "failed to save cookie", http.StatusInternalServerError)
 		return
 	}
 
-	http.Redirect(w, r, "/", http.StatusTemporaryRedirect)
+	if next == "" {
+		next = "/"
+	}
+
+	http.Redirect(w, r, next, http.StatusTemporaryRedirect)
 }
 
 // the time after which the SSE dance is considered failed
@@ -10,6 +10,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
@@ -65,6 +66,66 @@ func TestLoginForm(t *testing.T) {
 	})
 }
 
+func TestWebAuthnLoginForm(t *testing.T) {
+	ts := setup(t)
+	a := assert.New(t)
+
+	html, resp := ts.Client.GetHTML(ts.URLTo(router.AuthWebAuthnLogin))
+	a.Equal(http.StatusOK, resp.Code, "wrong HTTP status code")
+
+	webassert.Localized(t, html, []webassert.LocalizedElement{
+		{"title", "AuthTitle"},
+		{"#welcome", "AuthWebAuthnWelcome"},
+	})
+
+	form := html.Find("form#webauthn-sign-in")
+	action, _ := form.Attr("action")
+	a.Equal(ts.URLTo(router.AuthWebAuthnBegin).String(), action)
+	finalize, _ := form.Attr("data-finalize")
+	a.Equal(ts.URLTo(router.AuthWebAuthnFinalize).String(), finalize)
+	csrfToken, _ := form.Attr("data-csrf")
+	a.NotEqual("", csrfToken, "should have a csrf token for the javascript")
+}
+
+// the begin step can't be used to find out which members have passkeys
+func TestWebAuthnBeginUnknownAndWithoutPasskeys(t *testing.T) {
+	ts := setup(t)
+	a, r := assert.New(t), require.New(t)
+
+	client, err := keys.NewKeyPair(nil)
+	r.NoError(err)
+
+	html, resp := ts.Client.GetHTML(ts.URLTo(router.AuthWebAuthnLogin))
+	a.Equal(http.StatusOK, resp.Code)
+	csrfToken, _ := html.Find("form#webauthn-sign-in").Attr("data-csrf")
+
+	var refererHeader = make(http.Header)
+	refererHeader.Set("Referer", "https://localhost")
+	ts.Client.SetHeaders(refererHeader)
+
+	begin := func(user string) (int, string) {
+		resp := ts.Client.PostForm(ts.URLTo(router.AuthWebAuthnBegin), url.Values{
+			"user":               []string{user},
+			"gorilla.csrf.Token": []string{csrfToken},
+		})
+		return resp.Code, resp.Body.String()
+	}
+
+	// not a member
+	ts.MembersDB.GetByFeedReturns(roomdb.Member{}, roomdb.ErrNotFound)
+	unknownCode, unknownBody := begin(client.Feed.String())
+
+	// a member without passkeys
+	ts.MembersDB.GetByFeedReturns(roomdb.Member{ID: 23, Role: roomdb.RoleMember}, nil)
+	ts.WebAuthnDB.ListCredentialsReturns(nil, nil)
+	noPasskeysCode, noPasskeysBody := begin(client.Feed.String())
+	a.Equal(1, ts.WebAuthnDB.ListCredentialsCallCount())
+
+	a.Equal(http.StatusForbidden, unknownCode)
+	a.Equal(unknownCode, noPasskeysCode)
+	a.Equal(unknownBody, noPasskeysBody)
+}
+
 func TestFallbackAuthWrongPassword(t *testing.T) {
 	ts := setup(t)
 	a := assert.New(t)
@@ -111,6 +172,12 @@ func TestFallbackAuthWrongPassword(t *testing.T) {
 	a.Equal(http.StatusSeeOther, resp.Code, "wrong HTTP status code for sign in")
 	a.Equal(1, ts.AuthFallbackDB.CheckCallCount())
 
+	// the failure was counted
+	a.Equal(1, ts.LockoutsDB.FailedCallCount())
+	_, failedLogin, _ := ts.LockoutsDB.FailedArgsForCall(0)
+	a.Equal("test", failedLogin)
+	a.Equal(0, ts.LockoutsDB.SucceededCallCount())
+
 	// check flash error for bad login
 	res := resp.Result()
 	a.Equal(signInFormURL.Path, res.Header.Get("Location"), "redirecting to overview")
@@ -124,6 +191,53 @@ func TestFallbackAuthWrongPassword(t *testing.T) {
 	a.Equal("ErrorAuthBadLogin", flashes.Text())
 }
 
+func TestFallbackAuthLockedOut(t *testing.T) {
+	ts := setup(t)
+	a := assert.New(t)
+
+	signInFormURL := ts.URLTo(router.AuthFallbackLogin)
+
+	doc, resp := ts.Client.GetHTML(signInFormURL)
+	a.Equal(http.StatusOK, resp.Code)
+
+	csrfTokenElem := doc.Find("#password-fallback input[type=hidden]")
+	a.Equal(1, csrfTokenElem.Length())
+	csrfName, has := csrfTokenElem.Attr("name")
+	a.True(has, "should have a name attribute")
+	csrfValue, has := csrfTokenElem.Attr("value")
+	a.True(has, "should have value attribute")
+
+	ts.LockoutsDB.CheckReturns(roomdb.ErrLockedOut{
+		Kind:    roomdb.LockoutKindLogin,
+		Subject: "test",
+		Until:   time.Now().Add(time.Minute),
+	})
+
+	// important for CSRF
+	var refererHeader = make(http.Header)
+	refererHeader.Set("Referer", "https://localhost")
+	ts.Client.SetHeaders(refererHeader)
+
+	loginVals := url.Values{
+		"user": []string{"test"},
+		"pass": []string{"test"},
+
+		csrfName: []string{csrfValue},
+	}
+	resp = ts.Client.PostForm(ts.URLTo(router.AuthFallbackFinalize), loginVals)
+	a.Equal(http.StatusSeeOther, resp.Code, "wrong HTTP status code for sign in")
+	a.Equal(signInFormURL.Path, resp.Header().Get("Location"))
+
+	// the password isn't even looked at
+	a.Equal(1, ts.LockoutsDB.CheckCallCount())
+	_, checkedLogin, _ := ts.LockoutsDB.CheckArgsForCall(0)
+	a.Equal("test", checkedLogin)
+	a.Equal(0, ts.AuthFallbackDB.CheckCallCount())
+	a.Equal(0, ts.LockoutsDB.FailedCallCount())
+
+	webassert.HasFlashMessages(t, ts.Client, signInFormURL, "ErrorAuthLockedOut")
+}
+
 func TestFallbackAuthWorks(t *testing.T) {
 	ts := setup(t)
 	a := assert.New(t)
@@ -167,6 +281,8 @@ func TestFallbackAuthWorks(t *testing.T) {
 	a.Equal(http.StatusSeeOther, resp.Code, "wrong HTTP status code for sign in")
 
 	a.Equal(1, ts.AuthFallbackDB.CheckCallCount())
+	a.Equal(1, ts.LockoutsDB.SucceededCallCount())
+	a.Equal(0, ts.LockoutsDB.FailedCallCount())
 
 	// now request the protected dashboard page
 	dashboardURL := ts.URLTo(router.AdminDashboard)
@@ -208,6 +324,268 @@ func TestFallbackAuthWorks(t *testing.T) {
 	})
 }
 
+// members that were suspended, for instance through the invite tree, are signed out and can't sign in again
+func TestFallbackAuthSuspended(t *testing.T) {
+	ts := setup(t)
+	a, r := assert.New(t), require.New(t)
+
+	client, err := keys.NewKeyPair(nil)
+	r.NoError(err)
+	suspended := roomdb.Member{ID: 23, Role: roomdb.RoleModerator, PubKey: client.Feed}
+	ts.MembersDB.GetByIDReturns(suspended, nil)
+	ts.AuthFallbackDB.CheckReturns(suspended.ID, nil)
+
+	signInFormURL := ts.URLTo(router.AuthFallbackLogin)
+	signIn := func() *httptest.ResponseRecorder {
+		doc, resp := ts.Client.GetHTML(signInFormURL)
+		r.Equal(http.StatusOK, resp.Code)
+
+		loginVals := webassert.CSRFTokenPresent(t, doc.Find("#password-fallback"))
+		loginVals.Set("user", "test")
+		loginVals.Set("pass", "test")
+		return ts.Client.PostForm(ts.URLTo(router.AuthFallbackFinalize), loginVals)
+	}
+
+	// important for CSRF
+	var refererHeader = make(http.Header)
+	refererHeader.Set("Referer", "https://localhost")
+	ts.Client.SetHeaders(refererHeader)
+
+	resp := signIn()
+	r.Equal(http.StatusSeeOther, resp.Code, "wrong HTTP status code for sign in")
+
+	dashboardURL := ts.URLTo(router.AdminDashboard)
+	_, resp = ts.Client.GetHTML(dashboardURL)
+	a.Equal(http.StatusOK, resp.Code, "should be signed in")
+
+	// the member is suspended
+	ts.DeniedKeysDB.HasFeedStub = func(_ context.Context, feed refs.FeedRef) bool {
+		return feed.Equal(suspended.PubKey)
+	}
+
+	_, resp = ts.Client.GetHTML(dashboardURL)
+	a.Equal(http.StatusForbidden, resp.Code, "the session should not work anymore")
+
+	// and can't sign in again
+	resp = signIn()
+	r.Equal(http.StatusSeeOther, resp.Code)
+	a.Equal(signInFormURL.Path, resp.Header().Get("Location"))
+	a.Equal(1, ts.LockoutsDB.SucceededCallCount(), "only the first sign-in should count")
+	webassert.HasFlashMessages(t, ts.Client, signInFormURL, "ErrorAuthDenied")
+
+	_, resp = ts.Client.GetHTML(dashboardURL)
+	a.Equal(http.StatusForbidden, resp.Code)
+
+	// nor use an api token
+	ts.APITokensDB.CheckTokenReturns(roomdb.APIToken{ID: 1, MemberID: suspended.ID, Scopes: []roomdb.APITokenScope{roomdb.APIScopeStatsRead}}, nil)
+
+	bearer := make(http.Header)
+	bearer.Set("Authorization", "Bearer some-token")
+	ts.Client.ClearHeaders()
+	ts.Client.SetHeaders(bearer)
+
+	resp = ts.Client.GetBody(ts.URLTo(router.APIStats))
+	a.Equal(http.StatusForbidden, resp.Code, resp.Body.String())
+}
+
+func TestFallbackAuthWithTOTP(t *testing.T) {
+	ts := setup(t)
+	a := assert.New(t)
+
+	signInFormURL := ts.URLTo(router.AuthFallbackLogin)
+
+	doc, resp := ts.Client.GetHTML(signInFormURL)
+	a.Equal(http.StatusOK, resp.Code)
+
+	csrfTokenElem := doc.Find("#password-fallback input[type=hidden]")
+	a.Equal(1, csrfTokenElem.Length())
+	csrfName, has := csrfTokenElem.Attr("name")
+	a.True(has, "should have a name attribute")
+	csrfValue, has := csrfTokenElem.Attr("value")
+	a.True(has, "should have value attribute")
+
+	testRef, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte{1}, 32), refs.RefAlgoFeedSSB1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testMember := roomdb.Member{
+		ID:     23,
+		Role:   roomdb.RoleAdmin,
+		PubKey: testRef,
+	}
+	ts.MembersDB.GetByFeedReturns(testMember, nil)
+	ts.MembersDB.GetByIDReturns(testMember, nil)
+	ts.TOTPDB.StatusReturns(roomdb.TOTPStatus{Enabled: true, RecoveryCodesLeft: 10}, nil)
+	ts.AuthFallbackDB.CheckReturns(int64(23), nil)
+
+	// important for CSRF
+	var refererHeader = make(http.Header)
+	refererHeader.Set("Referer", "https://localhost")
+	ts.Client.SetHeaders(refererHeader)
+
+	loginVals := url.Values{
+		"user": []string{testMember.PubKey.String()},
+		"pass": []string{"test"},
+
+		csrfName: []string{csrfValue},
+	}
+	resp = ts.Client.PostForm(ts.URLTo(router.AuthFallbackFinalize), loginVals)
+	a.Equal(http.StatusSeeOther, resp.Code, "wrong HTTP status code for sign in")
+	a.Equal(1, ts.AuthFallbackDB.CheckCallCount())
+
+	codeFormURL := ts.URLTo(router.AuthFallbackTOTP)
+	a.Equal(codeFormURL.Path, resp.Header().Get("Location"), "should ask for the code")
+
+	// the password alone is not enough
+	dashboardURL := ts.URLTo(router.AdminDashboard)
+	_, resp = ts.Client.GetHTML(dashboardURL)
+	a.Equal(http.StatusForbidden, resp.Code, "should not be signed in yet")
+	a.Equal(0, ts.LockoutsDB.SucceededCallCount(), "the failures are only reset after the code")
+
+	doc, resp = ts.Client.GetHTML(codeFormURL)
+	a.Equal(http.StatusOK, resp.Code)
+
+	codeForm := doc.Find("#totp-code")
+	webassert.CSRFTokenPresent(t, codeForm)
+	csrfTokenElem = codeForm.Find("input[type=hidden]")
+	csrfName, _ = csrfTokenElem.Attr("name")
+	csrfValue, _ = csrfTokenElem.Attr("value")
+
+	// a wrong code goes back to the form
+	ts.TOTPDB.VerifyReturns(roomdb.ErrInvalidTOTPCode)
+	codeVals := url.Values{
+		"code":   []string{"000000"},
+		csrfName: []string{csrfValue},
+	}
+	resp = ts.Client.PostForm(ts.URLTo(router.AuthFallbackTOTPFinalize), codeVals)
+	a.Equal(http.StatusSeeOther, resp.Code)
+	a.Equal(codeFormURL.Path, resp.Header().Get("Location"), "should ask again")
+	a.Equal(1, ts.LockoutsDB.FailedCallCount())
+	_, failedLogin, _ := ts.LockoutsDB.FailedArgsForCall(0)
+	a.Equal(testMember.PubKey.String(), failedLogin)
+
+	doc, resp = ts.Client.GetHTML(codeFormURL)
+	a.Equal(http.StatusOK, resp.Code)
+	flashes := doc.Find("#flashes-list").Children()
+	a.Equal(1, flashes.Length())
+	a.Equal("ErrorAuthTOTPBadCode", flashes.Text())
+
+	// the right one signs in
+	ts.TOTPDB.VerifyReturns(nil)
+	codeVals.Set("code", "123456")
+	resp = ts.Client.PostForm(ts.URLTo(router.AuthFallbackTOTPFinalize), codeVals)
+	a.Equal(http.StatusSeeOther, resp.Code)
+	a.Equal(dashboardURL.Path, resp.Header().Get("Location"))
+
+	a.Equal(2, ts.TOTPDB.VerifyCallCount())
+	_, memberID, code := ts.TOTPDB.VerifyArgsForCall(1)
+	a.EqualValues(23, memberID)
+	a.Equal("123456", code)
+
+	a.Equal(1, ts.LockoutsDB.SucceededCallCount())
+	_, succeededLogin := ts.LockoutsDB.SucceededArgsForCall(0)
+	a.Equal(testMember.PubKey.String(), succeededLogin)
+
+	html, resp := ts.Client.GetHTML(dashboardURL)
+	if !a.Equal(http.StatusOK, resp.Code, "wrong HTTP status code for dashboard") {
+		t.Log(html.Find("body").Text())
+	}
+}
+
+// wrong codes count towards the lockout, like wrong passwords
+func TestFallbackAuthTOTPLockout(t *testing.T) {
+	ts := setup(t)
+	a := assert.New(t)
+
+	signInFormURL := ts.URLTo(router.AuthFallbackLogin)
+
+	doc, resp := ts.Client.GetHTML(signInFormURL)
+	a.Equal(http.StatusOK, resp.Code)
+
+	csrfTokenElem := doc.Find("#password-fallback input[type=hidden]")
+	csrfName, _ := csrfTokenElem.Attr("name")
+	csrfValue, _ := csrfTokenElem.Attr("value")
+
+	testRef, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte{1}, 32), refs.RefAlgoFeedSSB1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testMember := roomdb.Member{
+		ID:     23,
+		Role:   roomdb.RoleMember,
+		PubKey: testRef,
+	}
+	ts.MembersDB.GetByIDReturns(testMember, nil)
+	ts.TOTPDB.StatusReturns(roomdb.TOTPStatus{Enabled: true, RecoveryCodesLeft: 10}, nil)
+	ts.AuthFallbackDB.CheckReturns(int64(23), nil)
+	ts.TOTPDB.VerifyReturns(roomdb.ErrInvalidTOTPCode)
+
+	// the database locks the login out after three failures
+	const maxFailures = 3
+	failures := 0
+	ts.LockoutsDB.FailedStub = func(context.Context, string, string) ([]roomdb.LoginLockout, error) {
+		failures++
+		return nil, nil
+	}
+	ts.LockoutsDB.CheckStub = func(_ context.Context, login, _ string) error {
+		if failures >= maxFailures {
+			return roomdb.ErrLockedOut{Kind: roomdb.LockoutKindLogin, Subject: login, Until: time.Now().Add(time.Minute)}
+		}
+		return nil
+	}
+
+	// important for CSRF
+	var refererHeader = make(http.Header)
+	refererHeader.Set("Referer", "https://localhost")
+	ts.Client.SetHeaders(refererHeader)
+
+	resp = ts.Client.PostForm(ts.URLTo(router.AuthFallbackFinalize), url.Values{
+		"user":   []string{"test"},
+		"pass":   []string{"test"},
+		csrfName: []string{csrfValue},
+	})
+	codeFormURL := ts.URLTo(router.AuthFallbackTOTP)
+	a.Equal(codeFormURL.Path, resp.Header().Get("Location"), "should ask for the code")
+
+	doc, resp = ts.Client.GetHTML(codeFormURL)
+	a.Equal(http.StatusOK, resp.Code)
+	csrfTokenElem = doc.Find("#totp-code input[type=hidden]")
+	csrfName, _ = csrfTokenElem.Attr("name")
+	csrfValue, _ = csrfTokenElem.Attr("value")
+	codeVals := url.Values{
+		"code":   []string{"000000"},
+		csrfName: []string{csrfValue},
+	}
+
+	// fewer tries than the attempts of a single sign-in
+	for i := 0; i < maxFailures; i++ {
+		resp = ts.Client.PostForm(ts.URLTo(router.AuthFallbackTOTPFinalize), codeVals)
+		a.Equal(http.StatusSeeOther, resp.Code, "try %d", i)
+		a.Equal(codeFormURL.Path, resp.Header().Get("Location"), "try %d", i)
+		webassert.HasFlashMessages(t, ts.Client, codeFormURL, "ErrorAuthTOTPBadCode")
+	}
+	a.Equal(maxFailures, ts.TOTPDB.VerifyCallCount())
+	a.Equal(maxFailures, ts.LockoutsDB.FailedCallCount())
+
+	// now even the right code is rejected, without being looked at
+	ts.TOTPDB.VerifyReturns(nil)
+	resp = ts.Client.PostForm(ts.URLTo(router.AuthFallbackTOTPFinalize), codeVals)
+	a.Equal(http.StatusSeeOther, resp.Code)
+	a.Equal(signInFormURL.Path, resp.Header().Get("Location"))
+	a.Equal(maxFailures, ts.TOTPDB.VerifyCallCount())
+	webassert.HasFlashMessages(t, ts.Client, signInFormURL, "ErrorAuthLockedOut")
+
+	// the pending sign-in is gone, too
+	resp = ts.Client.PostForm(ts.URLTo(router.AuthFallbackTOTPFinalize), codeVals)
+	a.Equal(signInFormURL.Path, resp.Header().Get("Location"))
+	a.Equal(maxFailures, ts.TOTPDB.VerifyCallCount())
+
+	a.Equal(0, ts.LockoutsDB.SucceededCallCount())
+
+	_, resp = ts.Client.GetHTML(ts.URLTo(router.AdminDashboard))
+	a.Equal(http.StatusForbidden, resp.Code, "should not be signed in")
+}
+
 func TestAuthWithSSBClientInitNotConnected(t *testing.T) {
 	ts := setup(t)
 	a, r := assert.New(t), require.New(t)
@@ -390,6 +768,11 @@ func TestAuthWithSSBClientInitHasClient(t *testing.T) {
 	sessionCookie := resp.Result().Cookies()
 	r.True(len(sessionCookie) > 0, "expecting one cookie!")
 
+	// the session should know where it was created
+	r.Equal(1, ts.AuthWithSSB.SetClientDetailsCallCount())
+	_, tok, _, _ := ts.AuthWithSSB.SetClientDetailsArgsForCall(0)
+	a.Equal("abcdefgh", tok)
+
 	html, resp := ts.Client.GetHTML(dashboardURL)
 	if !a.Equal(http.StatusOK, resp.Code, "wrong HTTP status code for dashboard") {
 		t.Log(html.Find("body").Text())
@@ -0,0 +1,331 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssbc/go-ssb-room/v2/internal/maybemod/keys"
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+	"github.com/ssbc/go-ssb-room/v2/web/webassert"
+)
+
+type deviceCode struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	SSBURI                  string `json:"ssb_uri"`
+	Interval                int    `json:"interval"`
+}
+
+func (ts *testSession) startDeviceSignIn(t *testing.T, name string) deviceCode {
+	resp := ts.Client.PostForm(ts.URLTo(router.AuthDeviceCode), url.Values{"client_name": {name}})
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	var code deviceCode
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&code))
+	return code
+}
+
+func (ts *testSession) pollDeviceToken(deviceCode string) *httptest.ResponseRecorder {
+	return ts.Client.PostForm(ts.URLTo(router.AuthDeviceToken), url.Values{
+		"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+		"device_code": {deviceCode},
+	})
+}
+
+func deviceError(t *testing.T, resp *httptest.ResponseRecorder) string {
+	require.Equal(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+	var body struct {
+		Error string `json:"error"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	return body.Error
+}
+
+func TestDeviceSignIn(t *testing.T) {
+	ts := setup(t)
+	a, r := assert.New(t), require.New(t)
+
+	client, err := keys.NewKeyPair(nil)
+	r.NoError(err)
+	testMember := roomdb.Member{ID: 23, Role: roomdb.RoleModerator, PubKey: client.Feed}
+	ts.MembersDB.GetByIDReturns(testMember, nil)
+
+	// the device doesn't need a csrf token
+	code := ts.startDeviceSignIn(t, "roomctl")
+	a.Regexp("^[A-Z]{4}-[A-Z]{4}$", code.UserCode)
+	a.Equal(ts.URLTo(router.AuthDeviceVerify).String(), code.VerificationURI)
+	a.True(strings.HasPrefix(code.SSBURI, "ssb:experimental?"), code.SSBURI)
+	a.Equal(5, code.Interval)
+
+	a.Equal("authorization_pending", deviceError(t, ts.pollDeviceToken(code.DeviceCode)))
+	a.Equal("slow_down", deviceError(t, ts.pollDeviceToken(code.DeviceCode)))
+	a.Equal("invalid_grant", deviceError(t, ts.pollDeviceToken("not-a-code")))
+
+	// the member has to sign in first
+	verifyURL := ts.URLTo(router.AuthDeviceVerify, "user_code", strings.ToLower(strings.Replace(code.UserCode, "-", " ", 1)))
+	resp := ts.Client.GetBody(verifyURL)
+	r.Equal(http.StatusSeeOther, resp.Code)
+	a.Equal(ts.URLTo(router.AuthWithSSBLogin).Path, resp.Header().Get("Location"))
+
+	ts.AuthWithSSB.CheckTokenReturns(testMember.ID, nil)
+	resp = ts.Client.GetBody(ts.URLTo(router.AuthWithSSBFinalize, "token", "the-token"))
+	r.Equal(http.StatusTemporaryRedirect, resp.Code)
+	a.Equal(verifyURL.RequestURI(), resp.Header().Get("Location"))
+
+	html, resp := ts.Client.GetHTML(verifyURL)
+	r.Equal(http.StatusOK, resp.Code, "wrong HTTP status code for the verify page")
+	webassert.Localized(t, html, []webassert.LocalizedElement{
+		{"title", "AuthDeviceTitle"},
+		{"#welcome", "AuthDeviceConfirmWelcome"},
+		{"#device-access", "AuthDeviceConfirmAccess"},
+	})
+	a.Equal("roomctl", html.Find("#device-name").Text())
+	a.Equal(code.UserCode, html.Find("#user-code").Text())
+
+	confirm := webassert.CSRFTokenPresent(t, html.Find("#confirm"))
+	confirm.Set("user_code", code.UserCode)
+	confirm.Set("allow", "yes")
+
+	ts.AuthWithSSB.CreateTokenReturns("device-session", nil)
+
+	var refererHeader = make(http.Header)
+	refererHeader.Set("Referer", "https://localhost")
+	ts.Client.SetHeaders(refererHeader)
+
+	resp = ts.Client.PostForm(ts.URLTo(router.AuthDeviceConfirm), confirm)
+	r.Equal(http.StatusSeeOther, resp.Code, resp.Body.String())
+	a.Equal(ts.URLTo(router.MembersSessions).Path, resp.Header().Get("Location"))
+
+	r.Equal(1, ts.AuthWithSSB.CreateTokenCallCount())
+	_, memberID := ts.AuthWithSSB.CreateTokenArgsForCall(0)
+	a.Equal(testMember.ID, memberID)
+
+	// the browser sign-in stored its details, too
+	detailsCalls := ts.AuthWithSSB.SetClientDetailsCallCount()
+	r.True(detailsCalls > 0)
+	_, token, userAgent, _ := ts.AuthWithSSB.SetClientDetailsArgsForCall(detailsCalls - 1)
+	a.Equal("device-session", token)
+	a.Equal("roomctl", userAgent)
+
+	r.Equal(1, ts.AuthWithSSB.MarkDeviceCallCount())
+	_, token = ts.AuthWithSSB.MarkDeviceArgsForCall(0)
+	a.Equal("device-session", token)
+
+	// the code can't be used twice
+	resp = ts.Client.PostForm(ts.URLTo(router.AuthDeviceConfirm), confirm)
+	a.Equal(http.StatusSeeOther, resp.Code)
+	a.Equal(ts.URLTo(router.AuthDeviceVerify).Path, resp.Header().Get("Location"))
+	a.Equal(1, ts.AuthWithSSB.CreateTokenCallCount())
+	a.Equal(detailsCalls, ts.AuthWithSSB.SetClientDetailsCallCount())
+
+	// the device gets the session once
+	resp = ts.pollDeviceToken(code.DeviceCode)
+	r.Equal(http.StatusOK, resp.Code, resp.Body.String())
+	a.Equal("no-store", resp.Header().Get("Cache-Control"))
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+	}
+	r.NoError(json.NewDecoder(resp.Body).Decode(&tok))
+	a.Equal("device-session", tok.AccessToken)
+	a.Equal("Bearer", tok.TokenType)
+
+	a.Equal("invalid_grant", deviceError(t, ts.pollDeviceToken(code.DeviceCode)))
+
+	// and can use it for the json api
+	ts.APITokensDB.CheckTokenReturns(roomdb.APIToken{}, roomdb.ErrNotFound)
+	ts.AuthWithSSB.CheckDeviceTokenReturns(testMember.ID, nil)
+
+	bearer := make(http.Header)
+	bearer.Set("Authorization", "Bearer "+tok.AccessToken)
+	ts.Client.SetHeaders(bearer)
+
+	resp = ts.Client.GetBody(ts.URLTo(router.APIStats))
+	a.Equal(http.StatusOK, resp.Code, resp.Body.String())
+	r.Equal(1, ts.AuthWithSSB.CheckDeviceTokenCallCount())
+	_, checked := ts.AuthWithSSB.CheckDeviceTokenArgsForCall(0)
+	a.Equal("device-session", checked)
+
+	// only to read
+	resp = ts.Client.PostForm(ts.URLTo(router.APIMembersAdd), url.Values{"pub_key": {"@Rt2aJrtOqWXhBZ5/vlfzeWQ9Bj/z6iT8CMhlr2WWZeA=.ed25519"}})
+	a.Equal(http.StatusForbidden, resp.Code, resp.Body.String())
+	a.Contains(resp.Body.String(), "members:write")
+	a.Equal(0, ts.MembersDB.AddCallCount())
+
+	// but not for the admin pages
+	resp = ts.Client.GetBody(ts.URLTo(router.AdminInvitesOverview))
+	a.Equal(http.StatusUnauthorized, resp.Code)
+}
+
+func TestDeviceSignInDenied(t *testing.T) {
+	ts := setup(t)
+	a, r := assert.New(t), require.New(t)
+
+	client, err := keys.NewKeyPair(nil)
+	r.NoError(err)
+	testMember := roomdb.Member{ID: 23, Role: roomdb.RoleMember, PubKey: client.Feed}
+	ts.MembersDB.GetByIDReturns(testMember, nil)
+
+	code := ts.startDeviceSignIn(t, "")
+
+	ts.AuthWithSSB.CheckTokenReturns(testMember.ID, nil)
+	resp := ts.Client.GetBody(ts.URLTo(router.AuthWithSSBFinalize, "token", "the-token"))
+	r.Equal(http.StatusTemporaryRedirect, resp.Code)
+
+	// unknown codes
+	html, resp := ts.Client.GetHTML(ts.URLTo(router.AuthDeviceVerify, "user_code", "BBBB-BBBB"))
+	r.Equal(http.StatusOK, resp.Code)
+	a.Equal(1, html.Find("#unknown-code").Length())
+	a.Equal(0, html.Find("#confirm").Length())
+
+	var refererHeader = make(http.Header)
+	refererHeader.Set("Referer", "https://localhost")
+	ts.Client.SetHeaders(refererHeader)
+
+	html, resp = ts.Client.GetHTML(ts.URLTo(router.AuthDeviceVerify, "user_code", code.UserCode))
+	r.Equal(http.StatusOK, resp.Code)
+	confirm := webassert.CSRFTokenPresent(t, html.Find("#confirm"))
+	confirm.Set("user_code", code.UserCode)
+	confirm.Set("deny", "yes")
+
+	verifyURL := ts.URLTo(router.AuthDeviceVerify)
+	resp = ts.Client.PostForm(ts.URLTo(router.AuthDeviceConfirm), confirm)
+	r.Equal(http.StatusSeeOther, resp.Code, resp.Body.String())
+	a.Equal(verifyURL.Path, resp.Header().Get("Location"))
+	webassert.HasFlashMessages(t, ts.Client, verifyURL, "AuthDeviceDenied")
+
+	a.Equal(0, ts.AuthWithSSB.CreateTokenCallCount())
+	a.Equal("access_denied", deviceError(t, ts.pollDeviceToken(code.DeviceCode)))
+}
+
+func TestDeviceSignInMarkFails(t *testing.T) {
+	ts := setup(t)
+	a, r := assert.New(t), require.New(t)
+
+	client, err := keys.NewKeyPair(nil)
+	r.NoError(err)
+	testMember := roomdb.Member{ID: 23, Role: roomdb.RoleModerator, PubKey: client.Feed}
+	ts.MembersDB.GetByIDReturns(testMember, nil)
+
+	code := ts.startDeviceSignIn(t, "roomctl")
+
+	ts.AuthWithSSB.CheckTokenReturns(testMember.ID, nil)
+	resp := ts.Client.GetBody(ts.URLTo(router.AuthWithSSBFinalize, "token", "the-token"))
+	r.Equal(http.StatusTemporaryRedirect, resp.Code)
+
+	var refererHeader = make(http.Header)
+	refererHeader.Set("Referer", "https://localhost")
+	ts.Client.SetHeaders(refererHeader)
+
+	html, resp := ts.Client.GetHTML(ts.URLTo(router.AuthDeviceVerify, "user_code", code.UserCode))
+	r.Equal(http.StatusOK, resp.Code)
+	confirm := webassert.CSRFTokenPresent(t, html.Find("#confirm"))
+	confirm.Set("user_code", code.UserCode)
+	confirm.Set("allow", "yes")
+
+	ts.AuthWithSSB.CreateTokenReturns("device-session", nil)
+	ts.AuthWithSSB.MarkDeviceReturns(roomdb.ErrNotFound)
+
+	resp = ts.Client.PostForm(ts.URLTo(router.AuthDeviceConfirm), confirm)
+	r.Equal(http.StatusSeeOther, resp.Code, resp.Body.String())
+
+	// the session that can't be used by the device is thrown away and the code stays open
+	r.Equal(1, ts.AuthWithSSB.RemoveTokenCallCount())
+	_, removed := ts.AuthWithSSB.RemoveTokenArgsForCall(0)
+	a.Equal("device-session", removed)
+	a.Equal("authorization_pending", deviceError(t, ts.pollDeviceToken(code.DeviceCode)))
+}
+
+func TestDeviceSessionOfMember(t *testing.T) {
+	ts := setup(t)
+	a, r := assert.New(t), require.New(t)
+
+	client, err := keys.NewKeyPair(nil)
+	r.NoError(err)
+	testMember := roomdb.Member{ID: 23, Role: roomdb.RoleMember, PubKey: client.Feed}
+	ts.MembersDB.GetByIDReturns(testMember, nil)
+
+	// plain members can approve devices too
+	ts.APITokensDB.CheckTokenReturns(roomdb.APIToken{}, roomdb.ErrNotFound)
+	ts.AuthWithSSB.CheckDeviceTokenReturns(testMember.ID, nil)
+
+	bearer := make(http.Header)
+	bearer.Set("Authorization", "Bearer device-session")
+	ts.Client.SetHeaders(bearer)
+
+	resp := ts.Client.GetBody(ts.URLTo(router.APIStats))
+	a.Equal(http.StatusOK, resp.Code, resp.Body.String())
+	r.Equal(1, ts.AuthWithSSB.CheckDeviceTokenCallCount())
+
+	// but the api still checks what they can do
+	resp = ts.Client.PostForm(ts.URLTo(router.APIMembersAdd), url.Values{"pub_key": {"@Rt2aJrtOqWXhBZ5/vlfzeWQ9Bj/z6iT8CMhlr2WWZeA=.ed25519"}})
+	a.Equal(http.StatusForbidden, resp.Code, resp.Body.String())
+	a.Equal(0, ts.MembersDB.AddCallCount())
+
+	// api tokens of plain members are still refused
+	ts.APITokensDB.CheckTokenReturns(roomdb.APIToken{ID: 1, MemberID: testMember.ID, Scopes: []roomdb.APITokenScope{roomdb.APIScopeStatsRead}}, nil)
+	resp = ts.Client.GetBody(ts.URLTo(router.APIStats))
+	a.Equal(http.StatusForbidden, resp.Code, resp.Body.String())
+}
+
+func TestDeviceSignInBrowserSessionNoBearer(t *testing.T) {
+	ts := setup(t)
+	a, r := assert.New(t), require.New(t)
+
+	testMember := roomdb.Member{ID: 23, Role: roomdb.RoleAdmin}
+	ts.MembersDB.GetByIDReturns(testMember, nil)
+
+	// a valid session of a browser sign-in, which isn't a device session
+	ts.AuthWithSSB.CheckTokenReturns(testMember.ID, nil)
+	ts.AuthWithSSB.CheckDeviceTokenReturns(-1, roomdb.ErrNotFound)
+	ts.APITokensDB.CheckTokenReturns(roomdb.APIToken{}, roomdb.ErrNotFound)
+
+	bearer := make(http.Header)
+	bearer.Set("Authorization", "Bearer browser-session")
+	ts.Client.SetHeaders(bearer)
+
+	resp := ts.Client.GetBody(ts.URLTo(router.APIStats))
+	a.Equal(http.StatusUnauthorized, resp.Code, resp.Body.String())
+
+	r.Equal(1, ts.AuthWithSSB.CheckDeviceTokenCallCount())
+	_, checked := ts.AuthWithSSB.CheckDeviceTokenArgsForCall(0)
+	a.Equal("browser-session", checked)
+}
+
+func TestDeviceSignInPendingCap(t *testing.T) {
+	ts := setup(t)
+	a, r := assert.New(t), require.New(t)
+
+	for i := 0; i < 5; i++ {
+		ts.startDeviceSignIn(t, "roomctl")
+	}
+
+	// too many from the same address
+	resp := ts.Client.PostForm(ts.URLTo(router.AuthDeviceCode), url.Values{"client_name": {"roomctl"}})
+	r.Equal(http.StatusTooManyRequests, resp.Code, resp.Body.String())
+	var body struct {
+		Error string `json:"error"`
+	}
+	r.NoError(json.NewDecoder(resp.Body).Decode(&body))
+	a.Equal("slow_down", body.Error)
+
+	// other networks can still start one
+	otherAddress := make(http.Header)
+	otherAddress.Set("X-Forwarded-For", "198.51.100.7")
+	ts.Client.SetHeaders(otherAddress)
+	ts.startDeviceSignIn(t, "roomctl")
+}
@@ -13,14 +13,15 @@ import (
 	"time"
 
 	"github.com/gorilla/csrf"
+	"github.com/gorilla/securecookie"
 	"github.com/gorilla/sessions"
 	"github.com/russross/blackfriday/v2"
-	"go.mindeco.de/http/auth"
 	"go.mindeco.de/http/render"
 	"go.mindeco.de/log/level"
 	"go.mindeco.de/logging"
 
 	"github.com/ssbc/go-ssb-room/v2/internal/network"
+	"github.com/ssbc/go-ssb-room/v2/internal/proofofwork"
 	"github.com/ssbc/go-ssb-room/v2/internal/repo"
 	"github.com/ssbc/go-ssb-room/v2/internal/signinwithssb"
 	"github.com/ssbc/go-ssb-room/v2/roomdb"
@@ -28,7 +29,9 @@ import (
 	"github.com/ssbc/go-ssb-room/v2/web"
 	weberrs "github.com/ssbc/go-ssb-room/v2/web/errors"
 	"github.com/ssbc/go-ssb-room/v2/web/handlers/admin"
+	"github.com/ssbc/go-ssb-room/v2/web/handlers/api"
 	roomsAuth "github.com/ssbc/go-ssb-room/v2/web/handlers/auth"
+	"github.com/ssbc/go-ssb-room/v2/web/handlers/oidc"
 	"github.com/ssbc/go-ssb-room/v2/web/i18n"
 	"github.com/ssbc/go-ssb-room/v2/web/members"
 	"github.com/ssbc/go-ssb-room/v2/web/router"
@@ -39,11 +42,24 @@ var HTMLTemplates = []string{
 	"alias.tmpl",
 
 	"change-member-password.tmpl",
+	"member-sessions.tmpl",
+	"member-passkeys.tmpl",
+	"member-totp.tmpl",
+	"member-totp-enroll.tmpl",
+	"member-totp-recovery.tmpl",
+	"member-api-tokens.tmpl",
+	"member-api-token-created.tmpl",
+	"member-invites.tmpl",
+	"member-invite-created.tmpl",
+	"device-verify.tmpl",
 
 	"invite/consumed.tmpl",
 	"invite/facade.tmpl",
 	"invite/facade-fallback.tmpl",
 	"invite/insert-id.tmpl",
+	"invite/open-create.tmpl",
+	"invite/request.tmpl",
+	"invite/request-status.tmpl",
 
 	"notice/list.tmpl",
 	"notice/show.tmpl",
@@ -53,15 +69,66 @@ var HTMLTemplates = []string{
 
 // Databases is an options stuct for the required databases of the web handlers
 type Databases struct {
-	Aliases       roomdb.AliasesService
-	AuthFallback  roomdb.AuthFallbackService
-	AuthWithSSB   roomdb.AuthWithSSBService
-	Config        roomdb.RoomConfig
-	DeniedKeys    roomdb.DeniedKeysService
-	Invites       roomdb.InvitesService
-	Notices       roomdb.NoticesService
-	Members       roomdb.MembersService
-	PinnedNotices roomdb.PinnedNoticesService
+	Aliases        roomdb.AliasesService
+	APITokens      roomdb.APITokensService
+	AuthFallback   roomdb.AuthFallbackService
+	AuthWithSSB    roomdb.AuthWithSSBService
+	Config         roomdb.RoomConfig
+	DeniedKeys     roomdb.DeniedKeysService
+	Invites        roomdb.InvitesService
+	InviteRequests roomdb.InviteRequestsService
+	LoginLockouts  roomdb.LoginLockoutService
+	Notices        roomdb.NoticesService
+	Members        roomdb.MembersService
+	OIDCClients    roomdb.OIDCClientsService
+	PinnedNotices  roomdb.PinnedNoticesService
+	TOTP           roomdb.TOTPService
+	WebAuthn       roomdb.WebAuthnService
+	WebSessions    roomdb.WebSessionsService
+}
+
+// Option changes the default behaviour of the web handlers
+type Option func(*options) error
+
+type options struct {
+	fullSessionIPs bool
+
+	trustedProxies web.TrustedProxies
+
+	sessionIdleTimeout time.Duration
+	sessionLifetime    time.Duration
+}
+
+// WithFullSessionIPs stores the complete IP address with the sign-in sessions of members.
+// By default only the network part of it is stored.
+func WithFullSessionIPs(yes bool) Option {
+	return func(o *options) error {
+		o.fullSessionIPs = yes
+		return nil
+	}
+}
+
+// WithTrustedProxies sets the reverse proxies in front of the room.
+// Only requests from them can set the address of the client with X-Forwarded-For.
+func WithTrustedProxies(tp web.TrustedProxies) Option {
+	return func(o *options) error {
+		o.trustedProxies = tp
+		return nil
+	}
+}
+
+// WithSessionTimeouts sets when the sign-in sessions end: after idle without requests,
+// and lifetime after they started at the latest.
+// The defaults are web.DefaultSessionIdleTimeout and web.DefaultSessionLifetime.
+func WithSessionTimeouts(idle, lifetime time.Duration) Option {
+	return func(o *options) error {
+		if idle <= 0 || lifetime <= 0 {
+			return fmt.Errorf("session timeouts need to be positive (idle: %s, lifetime: %s)", idle, lifetime)
+		}
+		o.sessionIdleTimeout = idle
+		o.sessionLifetime = lifetime
+		return nil
+	}
 }
 
 // New initializes the whole web stack for rooms, with all the sub-modules and routing.
@@ -73,7 +140,18 @@ func New(
 	roomEndpoints network.Endpoints,
 	bridge *signinwithssb.SignalBridge,
 	dbs Databases,
+	opts ...Option,
 ) (http.Handler, error) {
+	var o = options{
+		sessionIdleTimeout: web.DefaultSessionIdleTimeout,
+		sessionLifetime:    web.DefaultSessionLifetime,
+	}
+	for i, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, fmt.Errorf("web Handler: error applying option #%d: %w", i, err)
+		}
+	}
+
 	m := router.CompleteApp()
 	urlTo := web.NewURLTo(m, netInfo)
 
@@ -82,19 +160,25 @@ func New(
 		return nil, err
 	}
 
-	cookieCodec, err := web.LoadOrCreateCookieSecrets(repo)
+	// the keys for the cookies and csrf tokens, they can be rotated while the server runs
+	secrets, err := web.LoadOrCreateSecrets(repo)
 	if err != nil {
 		return nil, err
 	}
+	cookieCodec := []securecookie.Codec{secrets}
 
+	// only used for the flash messages and the language, the sign-ins use the sessionStore
 	cookieStore := &sessions.CookieStore{
 		Codecs: cookieCodec,
 		Options: &sessions.Options{
 			Path:   "/",
-			MaxAge: 2 * 60 * 60, // two hours in seconds  // TODO: configure
+			MaxAge: 2 * 60 * 60, // two hours in seconds
 		},
 	}
 
+	// the sign-in sessions are kept in the database, so that they can be ended from the server
+	sessionStore := web.NewSessionStore(dbs.WebSessions, cookieCodec, o.sessionIdleTimeout, o.sessionLifetime)
+
 	flashHelper := weberrs.NewFlashHelper(cookieStore, locHelper)
 
 	eh := weberrs.NewErrorHandler(locHelper, flashHelper)
@@ -103,6 +187,7 @@ func New(
 		HTMLTemplates,
 		roomsAuth.HTMLTemplates,
 		admin.HTMLTemplates,
+		oidc.HTMLTemplates,
 	)
 
 	renderOpts := []render.Option{
@@ -190,27 +275,8 @@ func New(
 	}
 	eh.SetRenderer(r)
 
-	authWithPassword, err := auth.NewHandler(dbs.AuthFallback,
-		auth.SetStore(cookieStore),
-		auth.SetErrorHandler(func(rw http.ResponseWriter, req *http.Request, err error, code int) {
-			eh.Handle(rw, req, code, err)
-		}),
-		auth.SetNotAuthorizedHandler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
-			eh.Handle(rw, req, http.StatusForbidden, weberrs.ErrNotAuthorized)
-		})),
-		auth.SetLifetime(2*time.Hour), // TODO: configure
-	)
-	if err != nil {
-		return nil, fmt.Errorf("web Handler: failed to init fallback auth system: %w", err)
-	}
-
 	// Cross Site Request Forgery prevention middleware
-	csrfKey, err := web.LoadOrCreateCSRFSecret(repo)
-	if err != nil {
-		return nil, err
-	}
-
-	CSRF := csrf.Protect(csrfKey,
+	CSRF := secrets.CSRF(
 		csrf.Path("/"),
 		csrf.ErrorHandler(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 			err := csrf.FailureReason(req)
@@ -232,8 +298,46 @@ func New(
 		dbs.Aliases,
 		dbs.Members,
 		dbs.AuthWithSSB,
-		cookieStore,
+		sessionStore,
+		bridge,
+		o.fullSessionIPs,
+	)
+
+	authWithWebAuthn, err := roomsAuth.NewWithWebAuthnHandler(
+		m,
+		r,
+		netInfo,
+		dbs.Aliases,
+		dbs.Members,
+		dbs.WebAuthn,
+		dbs.DeniedKeys,
+		sessionStore,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("web Handler: failed to init webauthn: %w", err)
+	}
+
+	// command line tools and other devices without a browser sign in with a code that the member confirms
+	authWithDevice := roomsAuth.NewWithDeviceHandler(
+		m,
+		netInfo,
+		dbs.AuthWithSSB,
 		bridge,
+		o.fullSessionIPs,
+	)
+
+	// takes over the password sign-in form, to count failed attempts and ask for the second factor of those who enabled it
+	authWithTOTP := roomsAuth.NewWithTOTPHandler(
+		m,
+		r,
+		flashHelper,
+		dbs.Members,
+		dbs.AuthFallback,
+		dbs.TOTP,
+		dbs.Config,
+		dbs.LoginLockouts,
+		dbs.DeniedKeys,
+		sessionStore,
 	)
 
 	// auth routes
@@ -245,7 +349,6 @@ func New(
 		}
 	})
 
-	m.Get(router.AuthFallbackFinalize).HandlerFunc(authWithPassword.Authorize)
 	m.Get(router.AuthFallbackLogin).Handler(r.HTML("auth/fallback_sign_in.tmpl", func(w http.ResponseWriter, req *http.Request) (interface{}, error) {
 		pageData := map[string]interface{}{
 			csrf.TemplateTag: csrf.TemplateField(req),
@@ -261,7 +364,14 @@ func New(
 		if err != nil {
 			level.Warn(logging.FromContext(req.Context())).Log("err", err)
 		}
-		authWithPassword.Logout(w, req)
+		err = authWithWebAuthn.Logout(w, req)
+		if err != nil {
+			level.Warn(logging.FromContext(req.Context())).Log("err", err)
+		}
+		err = authWithTOTP.Logout(w, req)
+		if err != nil {
+			level.Warn(logging.FromContext(req.Context())).Log("err", err)
+		}
 	})
 
 	// all the admin routes
@@ -271,22 +381,120 @@ func New(
 		roomState,
 		flashHelper,
 		locHelper,
+		secrets,
 		admin.Databases{
+			Aliases:        dbs.Aliases,
+			APITokens:      dbs.APITokens,
+			AuthFallback:   dbs.AuthFallback,
+			AuthWithSSB:    dbs.AuthWithSSB,
+			Config:         dbs.Config,
+			DeniedKeys:     dbs.DeniedKeys,
+			Invites:        dbs.Invites,
+			InviteRequests: dbs.InviteRequests,
+			LoginLockouts:  dbs.LoginLockouts,
+			Notices:        dbs.Notices,
+			Members:        dbs.Members,
+			OIDCClients:    dbs.OIDCClients,
+			PinnedNotices:  dbs.PinnedNotices,
+			WebSessions:    dbs.WebSessions,
+		},
+	)
+	mainMux.Handle("/admin/", members.AuthenticateFromContext(r)(adminHandler))
+
+	// other apps can let members sign in with their room identity
+	oidcKey, err := web.LoadOrCreateOIDCSigningKey(repo)
+	if err != nil {
+		return nil, fmt.Errorf("web Handler: failed to load the openid connect signing key: %w", err)
+	}
+	oidc.NewProvider(m, r, netInfo, oidcKey, authWithSSB, oidc.Databases{
+		Clients:    dbs.OIDCClients,
+		Members:    dbs.Members,
+		DeniedKeys: dbs.DeniedKeys,
+	})
+
+	// the json api, it checks the signed-in member itself so that it can answer with json
+	apiHandler := api.Handler(
+		netInfo,
+		roomState,
+		api.Databases{
 			Aliases:       dbs.Aliases,
 			AuthFallback:  dbs.AuthFallback,
 			Config:        dbs.Config,
 			DeniedKeys:    dbs.DeniedKeys,
 			Invites:       dbs.Invites,
-			Notices:       dbs.Notices,
 			Members:       dbs.Members,
+			Notices:       dbs.Notices,
 			PinnedNotices: dbs.PinnedNotices,
 		},
 	)
-	mainMux.Handle("/admin/", members.AuthenticateFromContext(r)(adminHandler))
+	mainMux.Handle("/api/", apiHandler)
 
-	var mh = newMembersHandler(netInfo.Development, r, urlTo, flashHelper, dbs.AuthFallback)
+	var mh = newMembersHandler(netInfo.Development, r, urlTo, flashHelper, dbs.AuthFallback, dbs.AuthWithSSB, dbs.WebSessions)
 	m.Get(router.MembersChangePasswordForm).HandlerFunc(r.HTML("change-member-password.tmpl", mh.changePasswordForm))
 	m.Get(router.MembersChangePassword).HandlerFunc(mh.changePassword)
+	m.Get(router.MembersSessions).HandlerFunc(r.HTML("member-sessions.tmpl", mh.sessions))
+	m.Get(router.MembersSessionsRevoke).HandlerFunc(mh.revokeSession)
+	m.Get(router.MembersSessionsRevokeAll).HandlerFunc(mh.revokeAllSessions)
+
+	var ph = passkeysHandler{
+		r:     r,
+		urlTo: urlTo,
+		fh:    flashHelper,
+
+		webauthn:    authWithWebAuthn,
+		credentials: dbs.WebAuthn,
+	}
+	m.Get(router.MembersPasskeys).HandlerFunc(r.HTML("member-passkeys.tmpl", ph.list))
+	m.Get(router.MembersPasskeysBegin).HandlerFunc(ph.begin)
+	m.Get(router.MembersPasskeysFinish).HandlerFunc(ph.finish)
+	m.Get(router.MembersPasskeysRemove).HandlerFunc(ph.remove)
+
+	var dh = deviceHandler{
+		r:     r,
+		urlTo: urlTo,
+		fh:    flashHelper,
+
+		device:  authWithDevice,
+		withSSB: authWithSSB,
+	}
+	m.Get(router.AuthDeviceVerify).HandlerFunc(r.HTML("device-verify.tmpl", dh.verify))
+	m.Get(router.AuthDeviceConfirm).HandlerFunc(dh.confirm)
+
+	var th = totpHandler{
+		r:       r,
+		urlTo:   urlTo,
+		fh:      flashHelper,
+		netInfo: netInfo,
+
+		totp: dbs.TOTP,
+	}
+	m.Get(router.MembersTOTP).HandlerFunc(r.HTML("member-totp.tmpl", th.overview))
+	m.Get(router.MembersTOTPEnroll).HandlerFunc(r.HTML("member-totp-enroll.tmpl", th.enroll))
+	m.Get(router.MembersTOTPConfirm).HandlerFunc(r.HTML("member-totp-recovery.tmpl", th.confirm))
+	m.Get(router.MembersTOTPDisable).HandlerFunc(th.disable)
+
+	var ath = apiTokensHandler{
+		r:     r,
+		urlTo: urlTo,
+		fh:    flashHelper,
+
+		tokens: dbs.APITokens,
+	}
+	m.Get(router.MembersAPITokens).HandlerFunc(r.HTML("member-api-tokens.tmpl", ath.list))
+	m.Get(router.MembersAPITokensCreate).HandlerFunc(r.HTML("member-api-token-created.tmpl", ath.create))
+	m.Get(router.MembersAPITokensRevoke).HandlerFunc(ath.revoke)
+
+	var mih = memberInvitesHandler{
+		r:     r,
+		urlTo: urlTo,
+		fh:    flashHelper,
+
+		config:  dbs.Config,
+		invites: dbs.Invites,
+	}
+	m.Get(router.MembersInvites).HandlerFunc(r.HTML("member-invites.tmpl", mih.list))
+	m.Get(router.MembersInvitesCreate).HandlerFunc(r.HTML("member-invite-created.tmpl", mih.create))
+	m.Get(router.MembersInvitesRevoke).HandlerFunc(mih.revoke)
 
 	// handle setting language
 	m.Get(router.CompleteSetLanguage).HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
@@ -352,9 +560,12 @@ func New(
 	//public invites
 	var ih = inviteHandler{
 		render:      r,
+		flashes:     flashHelper,
 		urlTo:       urlTo,
 		networkInfo: netInfo,
 
+		proofOfWork: proofofwork.NewIssuer(openModeChallengeValidity),
+
 		config:        dbs.Config,
 		pinnedNotices: dbs.PinnedNotices,
 		invites:       dbs.Invites,
@@ -366,6 +577,20 @@ func New(
 	m.Get(router.CompleteInviteConsume).HandlerFunc(ih.consume)
 	m.Get(router.OpenModeCreateInvite).HandlerFunc(ih.createOpenMode)
 
+	var irh = inviteRequestHandler{
+		r:     r,
+		urlTo: urlTo,
+		fh:    flashHelper,
+
+		config:         dbs.Config,
+		inviteRequests: dbs.InviteRequests,
+		deniedKeys:     dbs.DeniedKeys,
+		members:        dbs.Members,
+	}
+	m.Get(router.CompleteInviteRequestForm).HandlerFunc(r.HTML("invite/request.tmpl", irh.form))
+	m.Get(router.CompleteInviteRequestCreate).HandlerFunc(irh.create)
+	m.Get(router.CompleteInviteRequestStatus).HandlerFunc(irh.status)
+
 	// static assets
 	m.PathPrefix("/assets/").Handler(http.StripPrefix("/assets/", http.FileServer(web.Assets)))
 
@@ -380,10 +605,15 @@ func New(
 
 	consumeURL := urlTo(router.CompleteInviteConsume)
 	openModeCreateInviteURL := urlTo(router.OpenModeCreateInvite)
+	inviteRequestURL := urlTo(router.CompleteInviteRequestCreate)
+	oidcTokenURL := urlTo(router.OIDCToken)
+	oidcUserInfoURL := urlTo(router.OIDCUserInfo)
+	deviceCodeURL := urlTo(router.AuthDeviceCode)
+	deviceTokenURL := urlTo(router.AuthDeviceToken)
 
 	// apply HTTP middleware
 	middlewares := []func(http.Handler) http.Handler{
-		members.ContextInjecter(dbs.Members, authWithPassword, authWithSSB),
+		members.ContextInjecter(dbs.DeniedKeys, authWithSSB, authWithWebAuthn, authWithTOTP),
 		CSRF,
 
 		// We disable CSRF for certain requests that are done by apps
@@ -399,10 +629,28 @@ func New(
 					next.ServeHTTP(w, csrf.UnsafeSkipCheck(req))
 					return
 				}
+				// browsers can't post json to other sites without asking them first
+				if req.URL.Path == inviteRequestURL.Path && ct == "application/json" {
+					next.ServeHTTP(w, csrf.UnsafeSkipCheck(req))
+					return
+				}
+				// the openid connect apps authenticate with their secret or an access token
+				if req.URL.Path == oidcTokenURL.Path || req.URL.Path == oidcUserInfoURL.Path {
+					next.ServeHTTP(w, csrf.UnsafeSkipCheck(req))
+					return
+				}
+				// devices only hold the device code they got, not a browser session
+				if req.URL.Path == deviceCodeURL.Path || req.URL.Path == deviceTokenURL.Path {
+					next.ServeHTTP(w, csrf.UnsafeSkipCheck(req))
+					return
+				}
 				next.ServeHTTP(w, req)
 			})
 		},
 
+		// scripts can use the admin endpoints with an api token, and devices the json api with their session, instead of a session cookie
+		members.APITokenAuthenticator(dbs.APITokens, dbs.AuthWithSSB, dbs.Members, dbs.DeniedKeys),
+
 		logging.InjectHandler(logger),
 		logging.RecoveryHandler(),
 	}
@@ -411,6 +659,9 @@ func New(
 		middlewares = append(middlewares, r.GetReloader())
 	}
 
+	// the outermost one, so that everything after it sees the address of the client
+	middlewares = append(middlewares, o.trustedProxies.Middleware)
+
 	var finalHandler http.Handler = mainMux
 	for _, applyMiddleware := range middlewares {
 		finalHandler = applyMiddleware(finalHandler)
@@ -0,0 +1,262 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/csrf"
+	"go.mindeco.de/http/render"
+	"go.mindeco.de/log/level"
+	"go.mindeco.de/logging"
+
+	refs "github.com/ssbc/go-ssb-refs"
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web"
+	weberrs "github.com/ssbc/go-ssb-room/v2/web/errors"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+)
+
+// how long the message to the moderators can be, in bytes
+const inviteRequestMessageMaxLength = 1000
+
+// inviteRequestHandler lets people without an invite ask the moderators of the room for one
+type inviteRequestHandler struct {
+	r     *render.Renderer
+	urlTo web.URLMaker
+	fh    *weberrs.FlashHelper
+
+	config         roomdb.RoomConfig
+	inviteRequests roomdb.InviteRequestsService
+	deniedKeys     roomdb.DeniedKeysService
+	members        roomdb.MembersService
+}
+
+type inviteRequestPayload struct {
+	ID      refs.FeedRef `json:"id"`
+	Message string       `json:"message"`
+}
+
+type inviteRequestJSONResponse struct {
+	Status    string `json:"status"`
+	StatusURL string `json:"statusURL"`
+}
+
+type inviteRequestStatusJSONResponse struct {
+	State string `json:"state"`
+
+	// Invite is the url of the invite, once the request was approved
+	Invite string `json:"invite,omitempty"`
+}
+
+// form shows the form to request an invite. Open rooms don't need it, everyone can create an invite there.
+func (h inviteRequestHandler) form(w http.ResponseWriter, req *http.Request) (interface{}, error) {
+	mode, err := h.config.GetPrivacyMode(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	if mode == roomdb.ModeOpen {
+		return nil, weberrs.ErrRedirect{Path: h.urlTo(router.OpenModeCreateInvite).Path}
+	}
+
+	pageData := map[string]interface{}{
+		csrf.TemplateTag:   csrf.TemplateField(req),
+		"MessageMaxLength": inviteRequestMessageMaxLength,
+	}
+
+	pageData["Flashes"], err = h.fh.GetAll(w, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return pageData, nil
+}
+
+// create stores the request and sends the requester to the page where they can check on it.
+// Apps can post the request as JSON and get the url of that page back instead.
+func (h inviteRequestHandler) create(w http.ResponseWriter, req *http.Request) {
+	if req.Header.Get("Content-Type") == "application/json" {
+		h.createJSON(w, req)
+		return
+	}
+
+	formURL := h.urlTo(router.CompleteInviteRequestForm).Path
+
+	if err := req.ParseForm(); err != nil {
+		err = weberrs.ErrBadRequest{Where: "Form data", Details: err}
+		h.r.Error(w, req, http.StatusBadRequest, weberrs.ErrRedirect{Path: formURL, Reason: err})
+		return
+	}
+
+	feed, err := refs.ParseFeedRef(strings.TrimSpace(req.FormValue("id")))
+	if err != nil {
+		err = weberrs.ErrBadRequest{Where: "Public Key", Details: err}
+		h.r.Error(w, req, http.StatusBadRequest, weberrs.ErrRedirect{Path: formURL, Reason: err})
+		return
+	}
+
+	token, code, err := h.submit(req, feed, req.FormValue("message"))
+	if err != nil {
+		var alreadyAdded roomdb.ErrAlreadyAdded
+		if errors.As(err, &alreadyAdded) {
+			err = weberrs.ErrGenericLocalized{Label: "InviteRequestAlreadyPending"}
+		}
+		h.r.Error(w, req, code, weberrs.ErrRedirect{Path: formURL, Reason: err})
+		return
+	}
+
+	statusURL := h.urlTo(router.CompleteInviteRequestStatus, "token", token)
+	http.Redirect(w, req, statusURL.String(), http.StatusSeeOther)
+}
+
+func (h inviteRequestHandler) createJSON(w http.ResponseWriter, req *http.Request) {
+	logger := logging.FromContext(req.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+
+	sendError := func(code int, err error) {
+		w.WriteHeader(code)
+		data := struct {
+			Status string `json:"status"`
+			Error  string `json:"error"`
+		}{"error", err.Error()}
+		if err := enc.Encode(data); err != nil {
+			level.Warn(logger).Log("event", "sending json error failed", "err", err)
+		}
+	}
+
+	var payload inviteRequestPayload
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		sendError(http.StatusBadRequest, weberrs.ErrBadRequest{Where: "JSON body", Details: err})
+		return
+	}
+
+	token, code, err := h.submit(req, payload.ID, payload.Message)
+	if err != nil {
+		sendError(code, err)
+		return
+	}
+
+	err = enc.Encode(inviteRequestJSONResponse{
+		Status:    "successful",
+		StatusURL: h.urlTo(router.CompleteInviteRequestStatus, "token", token).String(),
+	})
+	if err != nil {
+		level.Warn(logger).Log("event", "sending json response failed", "err", err)
+	}
+}
+
+// submit checks the request and stores it. It returns the token of the request, or an error and the http status code for it.
+func (h inviteRequestHandler) submit(req *http.Request, feed refs.FeedRef, message string) (string, int, error) {
+	ctx := req.Context()
+
+	mode, err := h.config.GetPrivacyMode(ctx)
+	if err != nil {
+		return "", http.StatusInternalServerError, err
+	}
+	if mode == roomdb.ModeOpen {
+		return "", http.StatusBadRequest, weberrs.ErrBadRequest{Where: "Privacy mode", Details: fmt.Errorf("everyone can create invites in open rooms")}
+	}
+
+	message = strings.TrimSpace(message)
+	if len(message) > inviteRequestMessageMaxLength {
+		return "", http.StatusBadRequest, weberrs.ErrBadRequest{Where: "Message", Details: fmt.Errorf("longer than %d bytes", inviteRequestMessageMaxLength)}
+	}
+
+	if h.deniedKeys.HasFeed(ctx, feed) {
+		return "", http.StatusForbidden, weberrs.ErrForbidden{Details: fmt.Errorf("this key can't join the room")}
+	}
+
+	if _, err := h.members.GetByFeed(ctx, feed); err == nil {
+		return "", http.StatusBadRequest, weberrs.ErrBadRequest{Where: "Public Key", Details: fmt.Errorf("already a member of the room")}
+	} else if !errors.Is(err, roomdb.ErrNotFound) {
+		return "", http.StatusInternalServerError, err
+	}
+
+	token, err := h.inviteRequests.Create(ctx, feed, message, web.RemoteIP(req, true))
+	if err != nil {
+		var alreadyAdded roomdb.ErrAlreadyAdded
+		switch {
+		case errors.Is(err, roomdb.ErrInviteRequestLimit):
+			return "", http.StatusTooManyRequests, roomdb.ErrInviteRequestLimit
+		case errors.As(err, &alreadyAdded):
+			return "", http.StatusConflict, alreadyAdded
+		}
+		return "", http.StatusInternalServerError, err
+	}
+
+	level.Info(logging.FromContext(ctx)).Log("event", "invite requested", "ref", feed.ShortSigil())
+	return token, http.StatusOK, nil
+}
+
+// status shows the requester if their request was decided on yet, and the invite once it was approved
+func (h inviteRequestHandler) status(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Query().Get("encoding") == "json" {
+		h.statusJSON(w, req)
+		return
+	}
+
+	h.r.HTML("invite/request-status.tmpl", func(w http.ResponseWriter, req *http.Request) (interface{}, error) {
+		request, err := h.inviteRequests.GetByToken(req.Context(), req.URL.Query().Get("token"))
+		if err != nil {
+			if errors.Is(err, roomdb.ErrNotFound) {
+				return nil, weberrs.ErrNotFound{What: "invite request"}
+			}
+			return nil, err
+		}
+
+		pageData := map[string]interface{}{
+			"Request": request,
+		}
+		if request.State == roomdb.InviteRequestApproved {
+			pageData["FacadeURL"] = h.urlTo(router.CompleteInviteFacade, "token", request.InviteToken).String()
+		}
+		return pageData, nil
+	})(w, req)
+}
+
+func (h inviteRequestHandler) statusJSON(w http.ResponseWriter, req *http.Request) {
+	logger := logging.FromContext(req.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+
+	request, err := h.inviteRequests.GetByToken(req.Context(), req.URL.Query().Get("token"))
+	if err != nil {
+		code := http.StatusInternalServerError
+		if errors.Is(err, roomdb.ErrNotFound) {
+			code = http.StatusNotFound
+		}
+		w.WriteHeader(code)
+		data := struct {
+			Status string `json:"status"`
+			Error  string `json:"error"`
+		}{"error", err.Error()}
+		if err := enc.Encode(data); err != nil {
+			level.Warn(logger).Log("event", "sending json error failed", "err", err)
+		}
+		return
+	}
+
+	var resp inviteRequestStatusJSONResponse
+	switch request.State {
+	case roomdb.InviteRequestApproved:
+		resp.State = "approved"
+		resp.Invite = h.urlTo(router.CompleteInviteFacade, "token", request.InviteToken).String()
+	case roomdb.InviteRequestRejected:
+		resp.State = "rejected"
+	default:
+		resp.State = "pending"
+	}
+
+	if err := enc.Encode(resp); err != nil {
+		level.Warn(logger).Log("event", "sending json response failed", "err", err)
+	}
+}
@@ -0,0 +1,192 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	refs "github.com/ssbc/go-ssb-refs"
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+	"github.com/ssbc/go-ssb-room/v2/web/webassert"
+)
+
+func TestInviteRequestForm(t *testing.T) {
+	ts := setup(t)
+	a, r := assert.New(t), require.New(t)
+
+	testFeed, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte{7}, 32), refs.RefAlgoFeedSSB1)
+	r.NoError(err)
+
+	formURL := ts.URLTo(router.CompleteInviteRequestForm)
+
+	doc, resp := ts.Client.GetHTML(formURL)
+	a.Equal(http.StatusOK, resp.Code)
+
+	webassert.Localized(t, doc, []webassert.LocalizedElement{
+		{"title", "InviteRequestTitle"},
+		{"#welcome", "InviteRequestWelcome"},
+	})
+
+	form := doc.Find("#request-invite")
+	webassert.CSRFTokenPresent(t, form)
+
+	csrfTokenElem := form.Find("input[type=hidden]")
+	csrfName, has := csrfTokenElem.Attr("name")
+	a.True(has, "should have a name attribute")
+	csrfValue, has := csrfTokenElem.Attr("value")
+	a.True(has, "should have value attribute")
+
+	ts.MembersDB.GetByFeedReturns(roomdb.Member{}, roomdb.ErrNotFound)
+	ts.RequestsDB.CreateReturns("the-request-token", nil)
+
+	// important for CSRF
+	var refererHeader = make(http.Header)
+	refererHeader.Set("Referer", "https://localhost")
+	ts.Client.SetHeaders(refererHeader)
+
+	resp = ts.Client.PostForm(ts.URLTo(router.CompleteInviteRequestCreate), url.Values{
+		"id":      []string{testFeed.String()},
+		"message": []string{"  hi, please let me in  "},
+
+		csrfName: []string{csrfValue},
+	})
+	a.Equal(http.StatusSeeOther, resp.Code)
+
+	statusURL := ts.URLTo(router.CompleteInviteRequestStatus, "token", "the-request-token")
+	a.Equal(statusURL.String(), resp.Header().Get("Location"))
+
+	r.Equal(1, ts.RequestsDB.CreateCallCount())
+	_, feed, message, _ := ts.RequestsDB.CreateArgsForCall(0)
+	a.True(feed.Equal(testFeed))
+	a.Equal("hi, please let me in", message)
+
+	// a second request for the same feed goes back to the form
+	ts.RequestsDB.CreateReturns("", roomdb.ErrAlreadyAdded{Ref: testFeed})
+	resp = ts.Client.PostForm(ts.URLTo(router.CompleteInviteRequestCreate), url.Values{
+		"id": []string{testFeed.String()},
+
+		csrfName: []string{csrfValue},
+	})
+	a.Equal(http.StatusSeeOther, resp.Code)
+	a.Equal(formURL.Path, resp.Header().Get("Location"))
+	webassert.HasFlashMessages(t, ts.Client, formURL, "InviteRequestAlreadyPending")
+
+	// open rooms don't need it
+	ts.ConfigDB.GetPrivacyModeReturns(roomdb.ModeOpen, nil)
+	_, resp = ts.Client.GetHTML(formURL)
+	a.Equal(http.StatusSeeOther, resp.Code)
+	a.Equal(ts.URLTo(router.OpenModeCreateInvite).Path, resp.Header().Get("Location"))
+}
+
+func TestInviteRequestJSON(t *testing.T) {
+	ts := setup(t)
+	a, r := assert.New(t), require.New(t)
+
+	testFeed, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte{8}, 32), refs.RefAlgoFeedSSB1)
+	r.NoError(err)
+
+	createURL := ts.URLTo(router.CompleteInviteRequestCreate)
+
+	ts.MembersDB.GetByFeedReturns(roomdb.Member{}, roomdb.ErrNotFound)
+	ts.RequestsDB.CreateReturns("the-request-token", nil)
+
+	payload := inviteRequestPayload{ID: testFeed, Message: "hello"}
+
+	resp := ts.Client.SendJSON(createURL, payload)
+	a.Equal(http.StatusOK, resp.Code)
+
+	var created inviteRequestJSONResponse
+	r.NoError(json.NewDecoder(resp.Body).Decode(&created))
+	a.Equal("successful", created.Status)
+	a.Equal(ts.URLTo(router.CompleteInviteRequestStatus, "token", "the-request-token").String(), created.StatusURL)
+
+	// denied keys can't ask
+	ts.DeniedKeysDB.HasFeedStub = func(_ context.Context, feed refs.FeedRef) bool {
+		return feed.Equal(testFeed)
+	}
+	resp = ts.Client.SendJSON(createURL, payload)
+	a.Equal(http.StatusForbidden, resp.Code)
+	a.Equal(1, ts.RequestsDB.CreateCallCount())
+	ts.DeniedKeysDB.HasFeedStub = nil
+
+	// neither can members
+	ts.MembersDB.GetByFeedReturns(roomdb.Member{ID: 1, PubKey: testFeed}, nil)
+	resp = ts.Client.SendJSON(createURL, payload)
+	a.Equal(http.StatusBadRequest, resp.Code)
+	a.Equal(1, ts.RequestsDB.CreateCallCount())
+	ts.MembersDB.GetByFeedReturns(roomdb.Member{}, roomdb.ErrNotFound)
+
+	ts.RequestsDB.CreateReturns("", roomdb.ErrInviteRequestLimit)
+	resp = ts.Client.SendJSON(createURL, payload)
+	a.Equal(http.StatusTooManyRequests, resp.Code)
+
+	ts.RequestsDB.CreateReturns("", roomdb.ErrAlreadyAdded{Ref: testFeed})
+	resp = ts.Client.SendJSON(createURL, payload)
+	a.Equal(http.StatusConflict, resp.Code)
+}
+
+func TestInviteRequestStatus(t *testing.T) {
+	ts := setup(t)
+	a, r := assert.New(t), require.New(t)
+
+	testFeed, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte{9}, 32), refs.RefAlgoFeedSSB1)
+	r.NoError(err)
+
+	statusURL := ts.URLTo(router.CompleteInviteRequestStatus, "token", "the-request-token")
+
+	ts.RequestsDB.GetByTokenReturns(roomdb.InviteRequest{ID: 1, PubKey: testFeed, State: roomdb.InviteRequestPending}, nil)
+
+	doc, resp := ts.Client.GetHTML(statusURL)
+	a.Equal(http.StatusOK, resp.Code)
+	webassert.Localized(t, doc, []webassert.LocalizedElement{
+		{"#request-state", "InviteRequestPending"},
+	})
+	a.Equal(0, doc.Find("#invite-facade-link").Length())
+
+	r.Equal(1, ts.RequestsDB.GetByTokenCallCount())
+	_, tok := ts.RequestsDB.GetByTokenArgsForCall(0)
+	a.Equal("the-request-token", tok)
+
+	ts.RequestsDB.GetByTokenReturns(roomdb.InviteRequest{
+		ID:          1,
+		PubKey:      testFeed,
+		State:       roomdb.InviteRequestApproved,
+		InviteToken: "the-invite-token",
+	}, nil)
+
+	facadeURL := ts.URLTo(router.CompleteInviteFacade, "token", "the-invite-token")
+
+	doc, resp = ts.Client.GetHTML(statusURL)
+	a.Equal(http.StatusOK, resp.Code)
+	webassert.Localized(t, doc, []webassert.LocalizedElement{
+		{"#request-state", "InviteRequestApproved"},
+	})
+	href, ok := doc.Find("#invite-facade-link").Attr("href")
+	a.True(ok)
+	a.Equal(facadeURL.String(), href)
+
+	statusJSONURL := ts.URLTo(router.CompleteInviteRequestStatus, "token", "the-request-token", "encoding", "json")
+
+	resp = ts.Client.GetBody(statusJSONURL)
+	a.Equal(http.StatusOK, resp.Code)
+
+	var status inviteRequestStatusJSONResponse
+	r.NoError(json.NewDecoder(resp.Body).Decode(&status))
+	a.Equal("approved", status.State)
+	a.Equal(facadeURL.String(), status.Invite)
+
+	ts.RequestsDB.GetByTokenReturns(roomdb.InviteRequest{}, roomdb.ErrNotFound)
+	resp = ts.Client.GetBody(statusJSONURL)
+	a.Equal(http.StatusNotFound, resp.Code)
+}
@@ -5,23 +5,23 @@
 package handlers
 
 import (
-	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"html/template"
-	"image/color"
+	"io"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/gorilla/csrf"
-	"github.com/skip2/go-qrcode"
 	"go.mindeco.de/http/render"
 	"go.mindeco.de/log/level"
 	"go.mindeco.de/logging"
 
 	refs "github.com/ssbc/go-ssb-refs"
 	"github.com/ssbc/go-ssb-room/v2/internal/network"
+	"github.com/ssbc/go-ssb-room/v2/internal/proofofwork"
 	"github.com/ssbc/go-ssb-room/v2/roomdb"
 	"github.com/ssbc/go-ssb-room/v2/web"
 	weberrors "github.com/ssbc/go-ssb-room/v2/web/errors"
@@ -30,9 +30,13 @@ import (
 
 type inviteHandler struct {
 	render      *render.Renderer
+	flashes     *weberrors.FlashHelper
 	urlTo       web.URLMaker
 	networkInfo network.ServerEndpointDetails
 
+	// proofOfWork issues the challenges for invites in open mode
+	proofOfWork *proofofwork.Issuer
+
 	invites       roomdb.InvitesService
 	pinnedNotices roomdb.PinnedNoticesService
 	config        roomdb.RoomConfig
@@ -130,26 +134,17 @@ func (h inviteHandler) presentFacadeAsHTML(rw http.ResponseWriter, req *http.Req
 		thisURL.Scheme = "http"
 		thisURL.Host += fmt.Sprintf(":%d", h.networkInfo.PortHTTPS)
 	}
-	qrCode, err := qrcode.New(thisURL.String(), qrcode.Medium)
-	if err != nil {
-		return nil, err
-	}
-
-	qrCode.BackgroundColor = color.Transparent // transparent to fit into the page
-	qrCode.ForegroundColor = color.Black
-
-	qrCodeData, err := qrCode.PNG(-5)
+	qrURI, err := web.QRCodeURI(thisURL.String())
 	if err != nil {
 		return nil, err
 	}
-	qrURI := "data:image/png;base64," + base64.StdEncoding.EncodeToString(qrCodeData)
 
 	return map[string]interface{}{
 		csrf.TemplateTag: csrf.TemplateField(req),
 		"RoomTitle":      notice.Title,
 		"JoinRoomURI":    joinRoomURI,
 		"FallbackURL":    fallbackURL,
-		"QRCodeURI":      template.URL(qrURI),
+		"QRCodeURI":      qrURI,
 	}, nil
 }
 
@@ -257,11 +252,15 @@ func (h inviteHandler) consume(rw http.ResponseWriter, req *http.Request) {
 			resp.SendError(weberrors.ErrNotFound{What: "invite"})
 			return
 		}
+		if errors.Is(err, roomdb.ErrInviteForOtherFeed) {
+			resp.SendError(weberrors.ErrForbidden{Details: err})
+			return
+		}
 		resp.SendError(err)
 		return
 	}
 	log := logging.FromContext(req.Context())
-	level.Info(log).Log("event", "invite consumed", "id", inv.ID, "ref", newMember.ShortSigil())
+	level.Info(log).Log("event", "invite consumed", "id", inv.ID, "ref", newMember.ShortSigil(), "role", inv.Role)
 
 	resp.SendSuccess()
 }
@@ -350,51 +349,139 @@ func (html inviteConsumeHTMLResponder) SendError(err error) {
 	html.renderer.Error(html.rw, html.req, http.StatusInternalServerError, err)
 }
 
+// how long the proof of work for an open mode invite can take
+const openModeChallengeValidity = 10 * time.Minute
+
+// openModeCreatePayload is what apps send to create an invite in open mode.
+// Challenge and Nonce are the solved proof of work, if the room asks for one.
+type openModeCreatePayload struct {
+	Challenge string `json:"challenge"`
+	Nonce     string `json:"nonce"`
+}
+
+// createOpenMode lets anyone create an invite while the room is open.
+// Browsers get a form that solves the proof of work, apps can post JSON and get the challenge back with status 428 if it's missing.
 func (h inviteHandler) createOpenMode(rw http.ResponseWriter, r *http.Request) {
-	switch r.Header.Get("Accept") {
-	case "application/json":
+	if r.Header.Get("Accept") == "application/json" {
 		if r.Method != http.MethodPost {
 			h.render.Error(rw, r, http.StatusBadRequest, errors.New("invalid method"))
+			return
 		}
 		h.createOpenModeJSON(rw, r)
-	default:
-		if r.Method != http.MethodGet {
-			h.render.Error(rw, r, http.StatusBadRequest, errors.New("invalid method"))
-		}
-		h.render.HTML("admin/invite-created.tmpl", h.createOpenModeHTML)(rw, r)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		h.createOpenModeHTML(rw, r)
+		return
 	}
+
+	h.render.HTML("invite/open-create.tmpl", h.openModeForm)(rw, r)
 }
 
-func (h inviteHandler) createOpenModeHTML(rw http.ResponseWriter, req *http.Request) (interface{}, error) {
+// openModeForm shows the form to create an invite, with a new challenge if the room asks for a proof of work
+func (h inviteHandler) openModeForm(rw http.ResponseWriter, req *http.Request) (interface{}, error) {
 	ctx := req.Context()
 
-	token, err := h.invites.Create(ctx, -1)
+	mode, err := h.config.GetPrivacyMode(ctx)
 	if err != nil {
 		return nil, err
 	}
+	if mode != roomdb.ModeOpen {
+		return nil, weberrors.ErrForbidden{Details: fmt.Errorf("only open rooms let everyone create invites")}
+	}
 
-	facadeURL := h.urlTo(router.CompleteInviteFacade, "token", token)
+	limits, err := h.config.GetOpenInviteLimits(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	return map[string]interface{}{
-		"FacadeURL": facadeURL.String(),
-	}, nil
+	pageData := map[string]interface{}{
+		csrf.TemplateTag: csrf.TemplateField(req),
+	}
+	if limits.ProofOfWorkBits > 0 {
+		pageData["Challenge"] = h.proofOfWork.Challenge(limits.ProofOfWorkBits)
+	}
+
+	pageData["Flashes"], err = h.flashes.GetAll(rw, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return pageData, nil
+}
+
+func (h inviteHandler) createOpenModeHTML(rw http.ResponseWriter, req *http.Request) {
+	formURL := h.urlTo(router.OpenModeCreateInvite).Path
+
+	if err := req.ParseForm(); err != nil {
+		err = weberrors.ErrBadRequest{Where: "Form data", Details: err}
+		h.render.Error(rw, req, http.StatusBadRequest, weberrors.ErrRedirect{Path: formURL, Reason: err})
+		return
+	}
+
+	token, code, err := h.submitOpenMode(req, req.FormValue("challenge"), req.FormValue("nonce"))
+	if err != nil {
+		h.render.Error(rw, req, code, weberrors.ErrRedirect{Path: formURL, Reason: err})
+		return
+	}
+
+	err = h.render.Render(rw, req, "admin/invite-created.tmpl", http.StatusOK, map[string]interface{}{
+		"FacadeURL": h.urlTo(router.CompleteInviteFacade, "token", token).String(),
+	})
+	if err != nil {
+		level.Warn(logging.FromContext(req.Context())).Log("event", "rendering created invite failed", "err", err)
+	}
 }
 
 func (h inviteHandler) createOpenModeJSON(rw http.ResponseWriter, req *http.Request) {
 	logger := logging.FromContext(req.Context())
-	ctx := req.Context()
+
+	rw.Header().Set("Content-Type", "application/json")
 	enc := json.NewEncoder(rw)
 
-	token, err := h.invites.Create(ctx, -1)
-	if err != nil {
+	sendError := func(code int, err error) {
+		rw.WriteHeader(code)
 		data := struct {
 			Status string `json:"status"`
 			Error  string `json:"error"`
 		}{"failed", err.Error()}
-		rw.WriteHeader(http.StatusInternalServerError)
 		if err := enc.Encode(data); err != nil {
 			level.Warn(logger).Log("event", "sending json error failed", "err", err)
 		}
+	}
+
+	// apps that don't know about the proof of work don't send a body
+	var payload openModeCreatePayload
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil && err != io.EOF {
+		sendError(http.StatusBadRequest, weberrors.ErrBadRequest{Where: "JSON body", Details: err})
+		return
+	}
+
+	if payload.Challenge == "" {
+		limits, err := h.config.GetOpenInviteLimits(req.Context())
+		if err != nil {
+			sendError(http.StatusInternalServerError, err)
+			return
+		}
+
+		if limits.ProofOfWorkBits > 0 {
+			rw.WriteHeader(http.StatusPreconditionRequired)
+			data := struct {
+				Status    string `json:"status"`
+				Challenge string `json:"challenge"`
+				Bits      uint   `json:"bits"`
+			}{"challenge", h.proofOfWork.Challenge(limits.ProofOfWorkBits), limits.ProofOfWorkBits}
+			if err := enc.Encode(data); err != nil {
+				level.Warn(logger).Log("event", "sending json challenge failed", "err", err)
+			}
+			return
+		}
+	}
+
+	token, code, err := h.submitOpenMode(req, payload.Challenge, payload.Nonce)
+	if err != nil {
+		sendError(code, err)
 		return
 	}
 
@@ -406,3 +493,33 @@ func (h inviteHandler) createOpenModeJSON(rw http.ResponseWriter, req *http.Requ
 		level.Warn(logger).Log("event", "sending json response failed", "err", err)
 	}
 }
+
+// submitOpenMode checks the proof of work, if the room asks for one, and creates the invite.
+// It returns the token of the invite, or an error and the http status code for it.
+func (h inviteHandler) submitOpenMode(req *http.Request, challenge, nonce string) (string, int, error) {
+	ctx := req.Context()
+
+	limits, err := h.config.GetOpenInviteLimits(ctx)
+	if err != nil {
+		return "", http.StatusInternalServerError, err
+	}
+
+	if limits.ProofOfWorkBits > 0 {
+		if err := h.proofOfWork.Verify(challenge, nonce, limits.ProofOfWorkBits); err != nil {
+			return "", http.StatusBadRequest, weberrors.ErrBadRequest{Where: "Proof of work", Details: err}
+		}
+	}
+
+	address := web.RemoteIP(req, true)
+
+	token, err := h.invites.CreateOpenMode(ctx, address)
+	if err != nil {
+		if errors.Is(err, roomdb.ErrOpenInviteLimit) {
+			return "", http.StatusTooManyRequests, roomdb.ErrOpenInviteLimit
+		}
+		return "", http.StatusInternalServerError, err
+	}
+
+	level.Info(logging.FromContext(ctx)).Log("event", "open mode invite created", "address", address)
+	return token, http.StatusOK, nil
+}
@@ -11,13 +11,16 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strconv"
 	"strings"
 	"testing"
 
+	"github.com/PuerkitoBio/goquery"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	refs "github.com/ssbc/go-ssb-refs"
+	"github.com/ssbc/go-ssb-room/v2/internal/proofofwork"
 	"github.com/ssbc/go-ssb-room/v2/roomdb"
 	weberrors "github.com/ssbc/go-ssb-room/v2/web/errors"
 	"github.com/ssbc/go-ssb-room/v2/web/router"
@@ -346,39 +349,200 @@ func TestInviteConsumptionDenied(t *testing.T) {
 
 func TestOpenModeCreateInviteHTML(t *testing.T) {
 	ts := setup(t)
-	r := require.New(t)
+	a, r := assert.New(t), require.New(t)
+
+	formURL := ts.URLTo(router.OpenModeCreateInvite)
+
+	// only open rooms
+	_, resp := ts.Client.GetHTML(formURL)
+	a.Equal(http.StatusForbidden, resp.Code)
+
+	ts.ConfigDB.GetPrivacyModeReturns(roomdb.ModeOpen, nil)
+	ts.ConfigDB.GetOpenInviteLimitsReturns(roomdb.OpenInviteLimits{ProofOfWorkBits: 4, PerAddress: 5}, nil)
 
 	someToken := "fake-token"
-	ts.InvitesDB.CreateReturns(someToken, nil)
+	ts.InvitesDB.CreateOpenModeReturns(someToken, nil)
 
-	doc, resp := ts.Client.GetHTML(ts.URLTo(router.OpenModeCreateInvite))
+	// showing the form doesn't create anything
+	doc, resp := ts.Client.GetHTML(formURL)
 	r.Equal(http.StatusOK, resp.Code)
+	a.Equal(0, ts.InvitesDB.CreateOpenModeCallCount())
+
+	webassert.Localized(t, doc, []webassert.LocalizedElement{
+		{"title", "OpenModeCreateInviteTitle"},
+		{"#welcome", "OpenModeCreateInviteWelcome"},
+	})
+
+	form := doc.Find("#create-invite")
+	webassert.CSRFTokenPresent(t, form)
 
+	csrfTokenElem := form.Find("input[type=hidden]").First()
+	csrfName, has := csrfTokenElem.Attr("name")
+	a.True(has, "should have a name attribute")
+	csrfValue, has := csrfTokenElem.Attr("value")
+	a.True(has, "should have value attribute")
+
+	challenge, has := form.Find("input[name=challenge]").Attr("value")
+	r.True(has, "should have a challenge")
+	bits, err := proofofwork.Bits(challenge)
+	r.NoError(err)
+	a.EqualValues(4, bits)
+
+	nonce, err := proofofwork.Solve(challenge)
+	r.NoError(err)
+
+	// important for CSRF
+	var refererHeader = make(http.Header)
+	refererHeader.Set("Referer", "https://localhost")
+	refererHeader.Set("X-Forwarded-For", "198.51.100.7")
+	ts.Client.SetHeaders(refererHeader)
+
+	solved := url.Values{
+		"challenge": []string{challenge},
+		"nonce":     []string{nonce},
+
+		csrfName: []string{csrfValue},
+	}
+
+	resp = ts.Client.PostForm(formURL, solved)
+	r.Equal(http.StatusOK, resp.Code)
+	r.Equal(1, ts.InvitesDB.CreateOpenModeCallCount())
+	_, address := ts.InvitesDB.CreateOpenModeArgsForCall(0)
+	a.Equal("198.51.100.0", address, "only the network of the address is kept")
+
+	doc, err = goquery.NewDocumentFromReader(resp.Body)
+	r.NoError(err)
 	facadeLink := doc.Find("#invite-facade-link")
-	r.NotNil(facadeLink)
-	r.Contains(facadeLink.AttrOr("href", ""), someToken)
-	r.Contains(facadeLink.Text(), someToken)
+	a.Contains(facadeLink.AttrOr("href", ""), someToken)
+	a.Contains(facadeLink.Text(), someToken)
+
+	// each challenge can only be used once
+	resp = ts.Client.PostForm(formURL, solved)
+	a.Equal(http.StatusSeeOther, resp.Code)
+	a.Equal(formURL.Path, resp.Header().Get("Location"))
+	webassert.HasFlashMessages(t, ts.Client, formURL, "ErrorBadRequest")
+	a.Equal(1, ts.InvitesDB.CreateOpenModeCallCount())
+
+	// the limit of the address
+	ts.InvitesDB.CreateOpenModeReturns("", roomdb.ErrOpenInviteLimit)
+	doc, _ = ts.Client.GetHTML(formURL)
+	challenge = doc.Find("#create-invite input[name=challenge]").AttrOr("value", "")
+	nonce, err = proofofwork.Solve(challenge)
+	r.NoError(err)
+
+	resp = ts.Client.PostForm(formURL, url.Values{
+		"challenge": []string{challenge},
+		"nonce":     []string{nonce},
+
+		csrfName: []string{csrfValue},
+	})
+	a.Equal(http.StatusSeeOther, resp.Code)
+	webassert.HasFlashMessages(t, ts.Client, formURL, "ErrorOpenInviteLimit")
+	a.Equal(2, ts.InvitesDB.CreateOpenModeCallCount())
 }
 
 func TestOpenModeCreateInviteJSON(t *testing.T) {
 	ts := setup(t)
-	r := require.New(t)
+	a, r := assert.New(t), require.New(t)
 
 	someToken := "fake-token"
-	ts.InvitesDB.CreateReturns(someToken, nil)
+	ts.InvitesDB.CreateOpenModeReturns(someToken, nil)
+	ts.ConfigDB.GetOpenInviteLimitsReturns(roomdb.OpenInviteLimits{ProofOfWorkBits: 4}, nil)
 
-	req, err := http.NewRequest("POST", ts.URLTo(router.OpenModeCreateInvite).String(), nil)
-	r.NoError(err)
+	createInvite := func(payload interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			r.NoError(json.NewEncoder(&body).Encode(payload))
+		}
 
-	req.Header.Set("Accept", "application/json")
+		req, err := http.NewRequest("POST", ts.URLTo(router.OpenModeCreateInvite).String(), &body)
+		r.NoError(err)
+		req.Header.Set("Accept", "application/json")
+
+		recorder := httptest.NewRecorder()
+		ts.Mux.ServeHTTP(recorder, req)
+		return recorder
+	}
 
-	recorder := httptest.NewRecorder()
-	ts.Mux.ServeHTTP(recorder, req)
+	// without a proof of work, the room answers with a challenge
+	recorder := createInvite(nil)
+	r.Equal(http.StatusPreconditionRequired, recorder.Code)
+
+	var challengeResp struct {
+		Status    string
+		Challenge string
+		Bits      uint
+	}
+	r.NoError(json.NewDecoder(recorder.Body).Decode(&challengeResp))
+	a.Equal("challenge", challengeResp.Status)
+	a.EqualValues(4, challengeResp.Bits)
+	a.Equal(0, ts.InvitesDB.CreateOpenModeCallCount())
+
+	nonce, err := proofofwork.Solve(challengeResp.Challenge)
+	r.NoError(err)
+
+	recorder = createInvite(openModeCreatePayload{Challenge: challengeResp.Challenge, Nonce: nonce})
 	r.Equal(http.StatusOK, recorder.Code)
 
 	response := map[string]string{}
-	err = json.Unmarshal(recorder.Body.Bytes(), &response)
+	r.NoError(json.Unmarshal(recorder.Body.Bytes(), &response))
+	a.Contains(response["url"], someToken)
+	a.Equal(1, ts.InvitesDB.CreateOpenModeCallCount())
+
+	// a nonce that doesn't solve a new challenge
+	recorder = createInvite(nil)
+	r.NoError(json.NewDecoder(recorder.Body).Decode(&challengeResp))
+	wrong := 0
+	for proofofwork.Solves(challengeResp.Challenge, strconv.Itoa(wrong), 4) {
+		wrong++
+	}
+	recorder = createInvite(openModeCreatePayload{Challenge: challengeResp.Challenge, Nonce: strconv.Itoa(wrong)})
+	a.Equal(http.StatusBadRequest, recorder.Code)
+	a.Equal(1, ts.InvitesDB.CreateOpenModeCallCount())
+
+	// the limit of the address
+	ts.InvitesDB.CreateOpenModeReturns("", roomdb.ErrOpenInviteLimit)
+	nonce, err = proofofwork.Solve(challengeResp.Challenge)
 	r.NoError(err)
+	recorder = createInvite(openModeCreatePayload{Challenge: challengeResp.Challenge, Nonce: nonce})
+	a.Equal(http.StatusTooManyRequests, recorder.Code)
+
+	// without the proof of work
+	ts.InvitesDB.CreateOpenModeReturns(someToken, nil)
+	ts.ConfigDB.GetOpenInviteLimitsReturns(roomdb.OpenInviteLimits{}, nil)
+	recorder = createInvite(nil)
+	r.Equal(http.StatusOK, recorder.Code)
+	a.Equal(3, ts.InvitesDB.CreateOpenModeCallCount())
+}
+
+func TestInviteConsumptionForOtherFeed(t *testing.T) {
+	ts := setup(t)
+	a, r := assert.New(t), require.New(t)
+
+	testToken := "existing-test-token-3"
 
-	require.Contains(t, response["url"], someToken)
+	ts.InvitesDB.GetByTokenReturns(roomdb.Invite{ID: 4321}, nil)
+	ts.InvitesDB.ConsumeReturns(roomdb.Invite{}, roomdb.ErrInviteForOtherFeed)
+
+	testNewMember, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte{2}, 32), refs.RefAlgoFeedSSB1)
+	r.NoError(err)
+
+	var consume inviteConsumePayload
+	consume.Invite = testToken
+	consume.ID = testNewMember
+
+	consumeInviteURL := ts.URLTo(router.CompleteInviteConsume)
+	resp := ts.Client.SendJSON(consumeInviteURL, consume)
+
+	var jsonConsumeResp struct {
+		Status string
+		Error  string
+	}
+	err = json.NewDecoder(resp.Body).Decode(&jsonConsumeResp)
+	r.NoError(err)
+
+	a.Equal("error", jsonConsumeResp.Status)
+	a.Contains(jsonConsumeResp.Error, "access denied")
+	a.Contains(jsonConsumeResp.Error, roomdb.ErrInviteForOtherFeed.Error())
+	r.EqualValues(1, ts.InvitesDB.ConsumeCallCount())
 }
@@ -0,0 +1,179 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/csrf"
+	"go.mindeco.de/http/render"
+	"go.mindeco.de/log/level"
+	"go.mindeco.de/logging"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web"
+	weberrs "github.com/ssbc/go-ssb-room/v2/web/errors"
+	"github.com/ssbc/go-ssb-room/v2/web/members"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+)
+
+// the lifetimes a new api token can be created with, in days
+var apiTokenLifetimes = []int{7, 30, 90, 365}
+
+// apiTokensHandler lets admins and moderators manage the personal tokens their scripts can use
+type apiTokensHandler struct {
+	r     *render.Renderer
+	urlTo web.URLMaker
+	fh    *weberrs.FlashHelper
+
+	tokens roomdb.APITokensService
+}
+
+// elevatedMember returns the member of the request, if they are an admin or a moderator
+func elevatedMember(req *http.Request) (*roomdb.Member, error) {
+	member := members.FromContext(req.Context())
+	if member == nil {
+		return nil, weberrs.ErrNotAuthorized
+	}
+
+	if member.Role != roomdb.RoleAdmin && member.Role != roomdb.RoleModerator {
+		return nil, weberrs.ErrForbidden{Details: fmt.Errorf("only admins and moderators can use api tokens")}
+	}
+
+	return member, nil
+}
+
+// list shows the tokens of the logged in member and the form to create a new one
+func (ath apiTokensHandler) list(w http.ResponseWriter, req *http.Request) (interface{}, error) {
+	member, err := elevatedMember(req)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := ath.tokens.List(req.Context(), member.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pageData = make(map[string]interface{})
+	pageData[csrf.TemplateTag] = csrf.TemplateField(req)
+	pageData["Tokens"] = tokens
+	pageData["Scopes"] = roomdb.APITokenScopes
+	pageData["Lifetimes"] = apiTokenLifetimes
+
+	pageData["Flashes"], err = ath.fh.GetAll(w, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return pageData, nil
+}
+
+// create makes a new token and shows it, once
+func (ath apiTokensHandler) create(w http.ResponseWriter, req *http.Request) (interface{}, error) {
+	member, err := elevatedMember(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := req.ParseForm(); err != nil {
+		return nil, weberrs.ErrBadRequest{Where: "Form data", Details: err}
+	}
+
+	redirectErr := weberrs.ErrRedirect{Path: ath.urlTo(router.MembersAPITokens).Path}
+
+	name := strings.TrimSpace(req.FormValue("name"))
+	if name == "" {
+		redirectErr.Reason = weberrs.ErrBadRequest{Where: "name", Details: fmt.Errorf("the token needs a name")}
+		return nil, redirectErr
+	}
+
+	var scopes []roomdb.APITokenScope
+	for _, s := range req.Form["scope"] {
+		scope := roomdb.APITokenScope(s)
+		if err := scope.IsValid(); err != nil {
+			redirectErr.Reason = weberrs.ErrBadRequest{Where: "scope", Details: err}
+			return nil, redirectErr
+		}
+		scopes = append(scopes, scope)
+	}
+	if len(scopes) == 0 {
+		redirectErr.Reason = weberrs.ErrGenericLocalized{Label: "MemberAPITokensNoScopes"}
+		return nil, redirectErr
+	}
+
+	days, err := strconv.Atoi(req.FormValue("lifetime"))
+	if err != nil {
+		redirectErr.Reason = weberrs.ErrBadRequest{Where: "lifetime", Details: err}
+		return nil, redirectErr
+	}
+	var validLifetime bool
+	for _, l := range apiTokenLifetimes {
+		if days == l {
+			validLifetime = true
+			break
+		}
+	}
+	if !validLifetime {
+		redirectErr.Reason = weberrs.ErrBadRequest{Where: "lifetime", Details: fmt.Errorf("unsupported lifetime: %d days", days)}
+		return nil, redirectErr
+	}
+	expiresAt := time.Now().Add(time.Duration(days) * 24 * time.Hour)
+
+	token, err := ath.tokens.Create(req.Context(), member.ID, name, scopes, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	level.Info(logging.FromContext(req.Context())).Log("event", "api token created", "member", member.ID, "name", name)
+
+	return map[string]interface{}{
+		"Token":     token,
+		"Name":      name,
+		"Scopes":    scopes,
+		"ExpiresAt": expiresAt,
+	}, nil
+}
+
+// revoke deletes one of the tokens of the logged in member
+func (ath apiTokensHandler) revoke(w http.ResponseWriter, req *http.Request) {
+	member, err := elevatedMember(req)
+	if err != nil {
+		ath.r.Error(w, req, http.StatusUnauthorized, err)
+		return
+	}
+
+	if req.Method != http.MethodPost {
+		ath.r.Error(w, req, http.StatusBadRequest, fmt.Errorf("expected POST method"))
+		return
+	}
+
+	err = req.ParseForm()
+	if err != nil {
+		ath.r.Error(w, req, http.StatusBadRequest, err)
+		return
+	}
+
+	redirectURL := ath.urlTo(router.MembersAPITokens).Path
+	defer http.Redirect(w, req, redirectURL, http.StatusSeeOther)
+
+	id, err := strconv.ParseInt(req.FormValue("id"), 10, 64)
+	if err != nil {
+		ath.fh.AddError(w, req, weberrs.ErrBadRequest{Where: "ID", Details: err})
+		return
+	}
+
+	err = ath.tokens.Revoke(req.Context(), member.ID, id)
+	if err != nil {
+		ath.fh.AddError(w, req, err)
+		return
+	}
+
+	ath.fh.AddMessage(w, req, "MemberAPITokensRevoked")
+}
@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	refs "github.com/ssbc/go-ssb-refs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+)
+
+func TestAPITokenAuthentication(t *testing.T) {
+	ts := setup(t)
+	a, r := assert.New(t), require.New(t)
+
+	adminRef, err := refs.NewFeedRefFromBytes(bytes.Repeat([]byte("admn"), 8), refs.RefAlgoFeedSSB1)
+	r.NoError(err)
+	admin := roomdb.Member{ID: 42, Role: roomdb.RoleAdmin, PubKey: adminRef}
+	ts.MembersDB.GetByIDReturns(admin, nil)
+	ts.ConfigDB.GetPrivacyModeReturns(roomdb.ModeRestricted, nil)
+
+	ts.APITokensDB.CheckTokenReturns(roomdb.APIToken{
+		ID:        1,
+		MemberID:  admin.ID,
+		Scopes:    []roomdb.APITokenScope{roomdb.APIScopeInvitesCreate},
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	ts.InvitesDB.CreateReturns("sometoken", nil)
+
+	withToken := make(http.Header)
+	withToken.Set("Authorization", "Bearer secret-token")
+	ts.Client.SetHeaders(withToken)
+
+	// a POST without a csrf token is fine with an api token
+	createInviteURL := ts.URLTo(router.AdminInvitesCreate)
+	resp := ts.Client.PostForm(createInviteURL, url.Values{})
+	a.Equal(http.StatusOK, resp.Code, "wrong HTTP status code for creating an invite")
+	a.Equal(1, ts.InvitesDB.CreateCallCount())
+	_, createdBy, _ := ts.InvitesDB.CreateArgsForCall(0)
+	a.Equal(admin.ID, createdBy)
+
+	_, tok := ts.APITokensDB.CheckTokenArgsForCall(0)
+	a.Equal("secret-token", tok)
+
+	// but it can't do things outside of its scopes
+	resp = ts.Client.GetBody(ts.URLTo(router.AdminMembersOverview))
+	a.Equal(http.StatusForbidden, resp.Code)
+	a.Contains(resp.Body.String(), "members:read")
+
+	// or use routes that aren't meant for tokens at all
+	_, resp = ts.Client.GetHTML(ts.URLTo(router.MembersAPITokens))
+	a.Equal(http.StatusForbidden, resp.Code)
+
+	// demoted members can't use their tokens anymore
+	ts.MembersDB.GetByIDReturns(roomdb.Member{ID: 42, Role: roomdb.RoleMember, PubKey: adminRef}, nil)
+	resp = ts.Client.PostForm(createInviteURL, url.Values{})
+	a.Equal(http.StatusForbidden, resp.Code)
+	a.Equal(1, ts.InvitesDB.CreateCallCount())
+	ts.MembersDB.GetByIDReturns(admin, nil)
+
+	// unknown or expired tokens
+	ts.APITokensDB.CheckTokenReturns(roomdb.APIToken{}, roomdb.ErrNotFound)
+	resp = ts.Client.PostForm(createInviteURL, url.Values{})
+	a.Equal(http.StatusUnauthorized, resp.Code)
+	a.True(strings.HasPrefix(resp.Header().Get("WWW-Authenticate"), "Bearer"))
+	a.Equal(1, ts.InvitesDB.CreateCallCount())
+
+	// without a token, the csrf check still applies
+	withoutToken := make(http.Header)
+	withoutToken.Set("Referer", "https://localhost")
+	ts.Client.ClearHeaders()
+	ts.Client.SetHeaders(withoutToken)
+	resp = ts.Client.PostForm(createInviteURL, url.Values{})
+	a.NotEqual(http.StatusOK, resp.Code)
+	a.Contains(resp.Body.String(), "CSRF token not found")
+	a.Equal(1, ts.InvitesDB.CreateCallCount())
+}
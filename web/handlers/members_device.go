@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/csrf"
+	"go.mindeco.de/http/render"
+	"go.mindeco.de/log/level"
+	"go.mindeco.de/logging"
+
+	"github.com/ssbc/go-ssb-room/v2/web"
+	weberrs "github.com/ssbc/go-ssb-room/v2/web/errors"
+	roomsAuth "github.com/ssbc/go-ssb-room/v2/web/handlers/auth"
+	"github.com/ssbc/go-ssb-room/v2/web/members"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+)
+
+// deviceHandler lets members confirm the codes that command line tools and other devices show them
+type deviceHandler struct {
+	r     *render.Renderer
+	urlTo web.URLMaker
+	fh    *weberrs.FlashHelper
+
+	device  *roomsAuth.WithDeviceHandler
+	withSSB *roomsAuth.WithSSBHandler
+}
+
+// verify shows the form to enter the code, or the device of the code so that the member can confirm it
+func (dh deviceHandler) verify(w http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if members.FromContext(req.Context()) == nil {
+		// come back here after signing in
+		if err := dh.withSSB.RedirectAfterSignIn(w, req, req.URL.RequestURI()); err != nil {
+			return nil, err
+		}
+		return nil, weberrs.ErrRedirect{Path: dh.urlTo(router.AuthWithSSBLogin).Path}
+	}
+
+	var pageData = make(map[string]interface{})
+	pageData[csrf.TemplateTag] = csrf.TemplateField(req)
+
+	if entered := req.URL.Query().Get("user_code"); entered != "" {
+		device, has := dh.device.PendingDevice(entered)
+		if has {
+			pageData["Device"] = device
+		} else {
+			pageData["UnknownCode"] = true
+		}
+	}
+
+	var err error
+	pageData["Flashes"], err = dh.fh.GetAll(w, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return pageData, nil
+}
+
+// confirm signs the device in as the member, or tells it that the member didn't want that
+func (dh deviceHandler) confirm(w http.ResponseWriter, req *http.Request) {
+	member := members.FromContext(req.Context())
+	if member == nil {
+		dh.r.Error(w, req, http.StatusUnauthorized, weberrs.ErrNotAuthorized)
+		return
+	}
+
+	if req.Method != "POST" {
+		err := weberrs.ErrBadRequest{Where: "HTTP Method", Details: fmt.Errorf("expected POST not %s", req.Method)}
+		dh.r.Error(w, req, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := req.ParseForm(); err != nil {
+		dh.r.Error(w, req, http.StatusBadRequest, weberrs.ErrBadRequest{Where: "Form data", Details: err})
+		return
+	}
+
+	userCode := req.FormValue("user_code")
+
+	if req.FormValue("allow") == "" {
+		if err := dh.device.Deny(userCode); err != nil {
+			dh.fh.AddError(w, req, weberrs.ErrBadRequest{Where: "Code", Details: err})
+			http.Redirect(w, req, dh.urlTo(router.AuthDeviceVerify).Path, http.StatusSeeOther)
+			return
+		}
+
+		dh.fh.AddMessage(w, req, "AuthDeviceDenied")
+		http.Redirect(w, req, dh.urlTo(router.AuthDeviceVerify).Path, http.StatusSeeOther)
+		return
+	}
+
+	if err := dh.device.Approve(req.Context(), userCode, member.ID); err != nil {
+		dh.fh.AddError(w, req, weberrs.ErrBadRequest{Where: "Code", Details: err})
+		http.Redirect(w, req, dh.urlTo(router.AuthDeviceVerify).Path, http.StatusSeeOther)
+		return
+	}
+
+	level.Info(logging.FromContext(req.Context())).Log("event", "device signed in", "member", member.PubKey.ShortSigil())
+
+	// the new session is listed with the others
+	dh.fh.AddMessage(w, req, "AuthDeviceApproved")
+	http.Redirect(w, req, dh.urlTo(router.MembersSessions).Path, http.StatusSeeOther)
+}
@@ -0,0 +1,174 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/csrf"
+	"go.mindeco.de/http/render"
+	"go.mindeco.de/log/level"
+	"go.mindeco.de/logging"
+
+	refs "github.com/ssbc/go-ssb-refs"
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web"
+	weberrs "github.com/ssbc/go-ssb-room/v2/web/errors"
+	"github.com/ssbc/go-ssb-room/v2/web/members"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+)
+
+// memberInvitesHandler lets members see and revoke the invites they created, and create new ones if the privacy mode allows it
+type memberInvitesHandler struct {
+	r     *render.Renderer
+	urlTo web.URLMaker
+	fh    *weberrs.FlashHelper
+
+	config  roomdb.RoomConfig
+	invites roomdb.InvitesService
+}
+
+// list shows the invites of the logged in member and how many more they can create
+func (mih memberInvitesHandler) list(w http.ResponseWriter, req *http.Request) (interface{}, error) {
+	member := members.FromContext(req.Context())
+	if member == nil {
+		return nil, weberrs.ErrNotAuthorized
+	}
+
+	invites, err := mih.invites.ListCreatedBy(req.Context(), member.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var unused uint
+	for _, inv := range invites {
+		if inv.Status == roomdb.InviteStatusActive {
+			unused++
+		}
+	}
+
+	// moderators and admins don't have a quota
+	var quota uint
+	if member.Role == roomdb.RoleMember {
+		quota, err = mih.config.GetMemberInviteQuota(req.Context())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = members.CheckAllowed(req.Context(), mih.config, members.ActionInviteMember)
+	canCreate := err == nil && (quota == 0 || unused < quota)
+
+	var pageData = make(map[string]interface{})
+	pageData[csrf.TemplateTag] = csrf.TemplateField(req)
+	pageData["Invites"] = invites
+	pageData["Unused"] = unused
+	pageData["Quota"] = quota
+	pageData["CanCreate"] = canCreate
+
+	pageData["Flashes"], err = mih.fh.GetAll(w, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return pageData, nil
+}
+
+// create makes a new plain invite, optionally for a specific feed, and shows its link
+func (mih memberInvitesHandler) create(w http.ResponseWriter, req *http.Request) (interface{}, error) {
+	member, err := members.CheckAllowed(req.Context(), mih.config, members.ActionInviteMember)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := req.ParseForm(); err != nil {
+		return nil, weberrs.ErrBadRequest{Where: "Form data", Details: err}
+	}
+
+	redirectErr := weberrs.ErrRedirect{Path: mih.urlTo(router.MembersInvites).Path}
+
+	var opts roomdb.InviteOptions
+	if f := strings.TrimSpace(req.FormValue("for_feed")); f != "" {
+		feed, err := refs.ParseFeedRef(f)
+		if err != nil {
+			redirectErr.Reason = weberrs.ErrBadRequest{Where: "Public Key", Details: err}
+			return nil, redirectErr
+		}
+		opts.ForFeed = &feed
+	}
+
+	token, err := mih.invites.Create(req.Context(), member.ID, opts)
+	if err != nil {
+		if errors.Is(err, roomdb.ErrInviteQuota) {
+			redirectErr.Reason = err
+			return nil, redirectErr
+		}
+		return nil, err
+	}
+
+	level.Info(logging.FromContext(req.Context())).Log("event", "member created invite", "member", member.ID)
+
+	facadeURL := mih.urlTo(router.CompleteInviteFacade, "token", token)
+
+	return map[string]interface{}{
+		"FacadeURL": facadeURL.String(),
+		"ForFeed":   opts.ForFeed,
+	}, nil
+}
+
+// revoke invalidates one of the unused invites of the logged in member
+func (mih memberInvitesHandler) revoke(w http.ResponseWriter, req *http.Request) {
+	member := members.FromContext(req.Context())
+	if member == nil {
+		mih.r.Error(w, req, http.StatusUnauthorized, weberrs.ErrNotAuthorized)
+		return
+	}
+
+	if req.Method != http.MethodPost {
+		mih.r.Error(w, req, http.StatusBadRequest, fmt.Errorf("expected POST method"))
+		return
+	}
+
+	err := req.ParseForm()
+	if err != nil {
+		mih.r.Error(w, req, http.StatusBadRequest, err)
+		return
+	}
+
+	redirectURL := mih.urlTo(router.MembersInvites).Path
+	defer http.Redirect(w, req, redirectURL, http.StatusSeeOther)
+
+	id, err := strconv.ParseInt(req.FormValue("id"), 10, 64)
+	if err != nil {
+		mih.fh.AddError(w, req, weberrs.ErrBadRequest{Where: "ID", Details: err})
+		return
+	}
+
+	invite, err := mih.invites.GetByID(req.Context(), id)
+	if err != nil {
+		if errors.Is(err, roomdb.ErrNotFound) {
+			err = weberrs.ErrNotFound{What: "invite"}
+		}
+		mih.fh.AddError(w, req, err)
+		return
+	}
+
+	if invite.CreatedBy.ID != member.ID {
+		mih.fh.AddError(w, req, weberrs.ErrForbidden{Details: fmt.Errorf("not your invite")})
+		return
+	}
+
+	err = mih.invites.Revoke(req.Context(), invite.ID)
+	if err != nil {
+		mih.fh.AddError(w, req, err)
+		return
+	}
+
+	mih.fh.AddMessage(w, req, "MemberInvitesRevoked")
+}
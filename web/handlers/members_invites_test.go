@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssbc/go-ssb-room/v2/internal/maybemod/keys"
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+	"github.com/ssbc/go-ssb-room/v2/web/webassert"
+)
+
+func TestMembersInvites(t *testing.T) {
+	ts := setup(t)
+	a, r := assert.New(t), require.New(t)
+
+	client, err := keys.NewKeyPair(nil)
+	r.NoError(err)
+	testMember := roomdb.Member{ID: 23, Role: roomdb.RoleMember, PubKey: client.Feed}
+	ts.MembersDB.GetByIDReturns(testMember, nil)
+
+	ts.AuthWithSSB.CheckTokenReturns(testMember.ID, nil)
+	resp := ts.Client.GetBody(ts.URLTo(router.AuthWithSSBFinalize, "token", "the-token"))
+	r.Equal(http.StatusTemporaryRedirect, resp.Code)
+
+	other, err := keys.NewKeyPair(nil)
+	r.NoError(err)
+
+	ts.ConfigDB.GetPrivacyModeReturns(roomdb.ModeCommunity, nil)
+	ts.ConfigDB.GetMemberInviteQuotaReturns(2, nil)
+	ts.InvitesDB.ListCreatedByReturns([]roomdb.CreatedInvite{
+		{
+			Invite: roomdb.Invite{ID: 2, CreatedBy: testMember, CreatedAt: time.Now()},
+			Status: roomdb.InviteStatusActive,
+		},
+		{
+			Invite: roomdb.Invite{ID: 1, CreatedBy: testMember, CreatedAt: time.Now().Add(-time.Hour)},
+			Status: roomdb.InviteStatusConsumed,
+			UsedBy: &roomdb.Member{ID: 24, PubKey: other.Feed},
+			UsedAt: time.Now(),
+		},
+	}, nil)
+
+	invitesURL := ts.URLTo(router.MembersInvites)
+	html, resp := ts.Client.GetHTML(invitesURL)
+	r.Equal(http.StatusOK, resp.Code)
+
+	_, memberID := ts.InvitesDB.ListCreatedByArgsForCall(0)
+	a.Equal(testMember.ID, memberID)
+
+	a.Equal(2, html.Find("#invite-list li").Length())
+	a.Equal("InviteStatusActive", strings.TrimSpace(html.Find(".invite-status").First().Text()))
+	a.Contains(html.Find(".invite-used-by").Text(), other.Feed.ShortSigil())
+	a.Contains(html.Find("#invite-quota").Text(), "1 / 2")
+	a.Equal(1, html.Find("#create-invite").Length(), "should be able to create more")
+
+	// only the active invite can be revoked
+	revokeForms := html.Find("#invite-list form")
+	r.Equal(1, revokeForms.Length())
+	revokeVals := webassert.CSRFTokenPresent(t, revokeForms)
+	revokeVals.Set("id", "2")
+
+	var refererHeader = make(http.Header)
+	refererHeader.Set("Referer", "https://localhost")
+	ts.Client.SetHeaders(refererHeader)
+
+	ts.InvitesDB.GetByIDReturns(roomdb.Invite{ID: 2, CreatedBy: testMember}, nil)
+	resp = ts.Client.PostForm(ts.URLTo(router.MembersInvitesRevoke), revokeVals)
+	r.Equal(http.StatusSeeOther, resp.Code, resp.Body.String())
+	a.Equal(invitesURL.Path, resp.Header().Get("Location"))
+	webassert.HasFlashMessages(t, ts.Client, invitesURL, "MemberInvitesRevoked")
+
+	r.Equal(1, ts.InvitesDB.RevokeCallCount())
+	_, revokedID := ts.InvitesDB.RevokeArgsForCall(0)
+	a.EqualValues(2, revokedID)
+
+	// the invites of other members can't be revoked
+	ts.InvitesDB.GetByIDReturns(roomdb.Invite{ID: 2, CreatedBy: roomdb.Member{ID: 99}}, nil)
+	resp = ts.Client.PostForm(ts.URLTo(router.MembersInvitesRevoke), revokeVals)
+	r.Equal(http.StatusSeeOther, resp.Code)
+	webassert.HasFlashMessages(t, ts.Client, invitesURL, "ErrorForbidden")
+	a.Equal(1, ts.InvitesDB.RevokeCallCount())
+
+	// at the quota, the form is gone
+	ts.ConfigDB.GetMemberInviteQuotaReturns(1, nil)
+	html, resp = ts.Client.GetHTML(invitesURL)
+	r.Equal(http.StatusOK, resp.Code)
+	a.Equal(0, html.Find("#create-invite").Length())
+}
@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/csrf"
+	"go.mindeco.de/http/render"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web"
+	weberrs "github.com/ssbc/go-ssb-room/v2/web/errors"
+	roomsAuth "github.com/ssbc/go-ssb-room/v2/web/handlers/auth"
+	"github.com/ssbc/go-ssb-room/v2/web/members"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+)
+
+// passkeysHandler lets members manage the passkeys they can sign in with
+type passkeysHandler struct {
+	r     *render.Renderer
+	urlTo web.URLMaker
+	fh    *weberrs.FlashHelper
+
+	webauthn *roomsAuth.WithWebAuthnHandler
+
+	credentials roomdb.WebAuthnService
+}
+
+// list shows the passkeys of the logged in member and the form to add a new one
+func (ph passkeysHandler) list(w http.ResponseWriter, req *http.Request) (interface{}, error) {
+	member := members.FromContext(req.Context())
+	if member == nil {
+		return nil, weberrs.ErrNotAuthorized
+	}
+
+	creds, err := ph.credentials.ListCredentials(req.Context(), member.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pageData = make(map[string]interface{})
+	pageData[csrf.TemplateTag] = csrf.TemplateField(req)
+	pageData["csrfToken"] = csrf.Token(req)
+	pageData["Passkeys"] = creds
+
+	pageData["Flashes"], err = ph.fh.GetAll(w, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return pageData, nil
+}
+
+// begin starts the registration of a new passkey (called by the javascript on the list page)
+func (ph passkeysHandler) begin(w http.ResponseWriter, req *http.Request) {
+	member := members.FromContext(req.Context())
+	if member == nil {
+		ph.r.Error(w, req, http.StatusUnauthorized, weberrs.ErrNotAuthorized)
+		return
+	}
+
+	ph.webauthn.BeginRegistration(w, req, *member)
+}
+
+// finish stores the new passkey, the name is passed as a query parameter since the body holds the authenticator response
+func (ph passkeysHandler) finish(w http.ResponseWriter, req *http.Request) {
+	member := members.FromContext(req.Context())
+	if member == nil {
+		ph.r.Error(w, req, http.StatusUnauthorized, weberrs.ErrNotAuthorized)
+		return
+	}
+
+	ph.webauthn.FinishRegistration(w, req, *member, req.URL.Query().Get("name"))
+}
+
+// remove deletes one of the passkeys of the logged in member
+func (ph passkeysHandler) remove(w http.ResponseWriter, req *http.Request) {
+	member := members.FromContext(req.Context())
+	if member == nil {
+		ph.r.Error(w, req, http.StatusUnauthorized, weberrs.ErrNotAuthorized)
+		return
+	}
+
+	if req.Method != http.MethodPost {
+		ph.r.Error(w, req, http.StatusBadRequest, fmt.Errorf("expected POST method"))
+		return
+	}
+
+	err := req.ParseForm()
+	if err != nil {
+		ph.r.Error(w, req, http.StatusBadRequest, err)
+		return
+	}
+
+	redirectURL := ph.urlTo(router.MembersPasskeys).Path
+	defer http.Redirect(w, req, redirectURL, http.StatusSeeOther)
+
+	id, err := strconv.ParseInt(req.FormValue("id"), 10, 64)
+	if err != nil {
+		ph.fh.AddError(w, req, weberrs.ErrBadRequest{Where: "ID", Details: err})
+		return
+	}
+
+	err = ph.credentials.RemoveCredential(req.Context(), member.ID, id)
+	if err != nil {
+		ph.fh.AddError(w, req, err)
+		return
+	}
+
+	ph.fh.AddMessage(w, req, "MemberPasskeysRemoved")
+}
@@ -26,17 +26,21 @@ type membersHandler struct {
 	fh    *weberrs.FlashHelper
 
 	authFallbackDB roomdb.AuthFallbackService
+	sessionsDB     roomdb.AuthWithSSBService
+	webSessionsDB  roomdb.WebSessionsService
 
 	leakedLookup func(string) (bool, error)
 }
 
-func newMembersHandler(devMode bool, r *render.Renderer, urlTo web.URLMaker, fh *weberrs.FlashHelper, db roomdb.AuthFallbackService) membersHandler {
+func newMembersHandler(devMode bool, r *render.Renderer, urlTo web.URLMaker, fh *weberrs.FlashHelper, db roomdb.AuthFallbackService, sessions roomdb.AuthWithSSBService, webSessions roomdb.WebSessionsService) membersHandler {
 	mh := membersHandler{
 		r:     r,
 		urlTo: urlTo,
 		fh:    fh,
 
 		authFallbackDB: db,
+		sessionsDB:     sessions,
+		webSessionsDB:  webSessions,
 	}
 
 	// we dont want to need network for our tests.
@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/csrf"
+	"go.mindeco.de/log/level"
+	"go.mindeco.de/logging"
+
+	weberrs "github.com/ssbc/go-ssb-room/v2/web/errors"
+	"github.com/ssbc/go-ssb-room/v2/web/members"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+)
+
+// sessions lists the sign-in with ssb sessions of the logged in member
+func (mh membersHandler) sessions(w http.ResponseWriter, req *http.Request) (interface{}, error) {
+	member := members.FromContext(req.Context())
+	if member == nil {
+		return nil, weberrs.ErrNotAuthorized
+	}
+
+	sessions, err := mh.sessionsDB.ListSessions(req.Context(), member.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pageData = make(map[string]interface{})
+	pageData[csrf.TemplateTag] = csrf.TemplateField(req)
+	pageData["Sessions"] = sessions
+
+	pageData["Flashes"], err = mh.fh.GetAll(w, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return pageData, nil
+}
+
+// revokeSession ends one of the sessions of the logged in member
+func (mh membersHandler) revokeSession(w http.ResponseWriter, req *http.Request) {
+	member := members.FromContext(req.Context())
+	if member == nil {
+		mh.r.Error(w, req, http.StatusUnauthorized, weberrs.ErrNotAuthorized)
+		return
+	}
+
+	if req.Method != http.MethodPost {
+		mh.r.Error(w, req, http.StatusBadRequest, fmt.Errorf("expected POST method"))
+		return
+	}
+
+	err := req.ParseForm()
+	if err != nil {
+		mh.r.Error(w, req, http.StatusBadRequest, err)
+		return
+	}
+
+	redirectURL := mh.urlTo(router.MembersSessions).Path
+	defer http.Redirect(w, req, redirectURL, http.StatusSeeOther)
+
+	sessionID, err := strconv.ParseInt(req.FormValue("id"), 10, 64)
+	if err != nil {
+		mh.fh.AddError(w, req, weberrs.ErrBadRequest{Where: "ID", Details: err})
+		return
+	}
+
+	err = mh.sessionsDB.RemoveSession(req.Context(), member.ID, sessionID)
+	if err != nil {
+		mh.fh.AddError(w, req, err)
+		return
+	}
+
+	mh.fh.AddMessage(w, req, "MemberSessionsRevoked")
+}
+
+// revokeAllSessions logs the member out everywhere, including this browser.
+// This ends the password and passkey sessions, too, and those of the devices that signed in with a code.
+func (mh membersHandler) revokeAllSessions(w http.ResponseWriter, req *http.Request) {
+	member := members.FromContext(req.Context())
+	if member == nil {
+		mh.r.Error(w, req, http.StatusUnauthorized, weberrs.ErrNotAuthorized)
+		return
+	}
+
+	if req.Method != http.MethodPost {
+		mh.r.Error(w, req, http.StatusBadRequest, fmt.Errorf("expected POST method"))
+		return
+	}
+
+	err := mh.webSessionsDB.RemoveForMember(req.Context(), member.ID)
+	if err != nil {
+		mh.r.Error(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	err = mh.sessionsDB.WipeTokensForMember(req.Context(), member.ID)
+	if err != nil {
+		mh.r.Error(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	level.Info(logging.FromContext(req.Context())).Log("event", "logged out everywhere", "member", member.PubKey.ShortSigil())
+
+	http.Redirect(w, req, "/", http.StatusSeeOther)
+}
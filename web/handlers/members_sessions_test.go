@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssbc/go-ssb-room/v2/internal/maybemod/keys"
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+	"github.com/ssbc/go-ssb-room/v2/web/webassert"
+)
+
+func TestMembersLogOutEverywhere(t *testing.T) {
+	ts := setup(t)
+	a, r := assert.New(t), require.New(t)
+
+	client, err := keys.NewKeyPair(nil)
+	r.NoError(err)
+	testMember := roomdb.Member{ID: 23, Role: roomdb.RoleMember, PubKey: client.Feed}
+	ts.MembersDB.GetByIDReturns(testMember, nil)
+
+	ts.AuthWithSSB.CheckTokenReturns(testMember.ID, nil)
+	resp := ts.Client.GetBody(ts.URLTo(router.AuthWithSSBFinalize, "token", "the-token"))
+	r.Equal(http.StatusTemporaryRedirect, resp.Code)
+
+	// the sign-in is stored on the server, for this member
+	r.Equal(1, ts.WebSessionsDB.CreateCallCount())
+	_, memberID, _, _, _ := ts.WebSessionsDB.CreateArgsForCall(0)
+	a.Equal(testMember.ID, memberID)
+
+	sessionsURL := ts.URLTo(router.MembersSessions)
+	html, resp := ts.Client.GetHTML(sessionsURL)
+	r.Equal(http.StatusOK, resp.Code)
+	revokeAll := webassert.CSRFTokenPresent(t, html.Find("#revoke-all"))
+
+	var refererHeader = make(http.Header)
+	refererHeader.Set("Referer", "https://localhost")
+	ts.Client.SetHeaders(refererHeader)
+
+	resp = ts.Client.PostForm(ts.URLTo(router.MembersSessionsRevokeAll), revokeAll)
+	r.Equal(http.StatusSeeOther, resp.Code, resp.Body.String())
+	a.Equal("/", resp.Header().Get("Location"))
+
+	r.Equal(1, ts.WebSessionsDB.RemoveForMemberCallCount())
+	_, memberID = ts.WebSessionsDB.RemoveForMemberArgsForCall(0)
+	a.Equal(testMember.ID, memberID)
+
+	r.Equal(1, ts.AuthWithSSB.WipeTokensForMemberCallCount())
+	_, memberID = ts.AuthWithSSB.WipeTokensForMemberArgsForCall(0)
+	a.Equal(testMember.ID, memberID)
+
+	// this browser is signed out, too
+	resp = ts.Client.GetBody(sessionsURL)
+	a.Equal(http.StatusForbidden, resp.Code)
+}
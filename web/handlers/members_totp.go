@@ -0,0 +1,170 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package handlers
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html/template"
+	"image/color"
+	"net/http"
+
+	"github.com/gorilla/csrf"
+	"github.com/pquerna/otp/totp"
+	"github.com/skip2/go-qrcode"
+	"go.mindeco.de/http/render"
+
+	"github.com/ssbc/go-ssb-room/v2/internal/network"
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web"
+	weberrs "github.com/ssbc/go-ssb-room/v2/web/errors"
+	"github.com/ssbc/go-ssb-room/v2/web/members"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+)
+
+// totpHandler lets members set up the second factor for their fallback password
+type totpHandler struct {
+	r     *render.Renderer
+	urlTo web.URLMaker
+	fh    *weberrs.FlashHelper
+
+	netInfo network.ServerEndpointDetails
+
+	totp roomdb.TOTPService
+}
+
+// overview shows if the second factor is enabled and offers to set it up or turn it off
+func (th totpHandler) overview(w http.ResponseWriter, req *http.Request) (interface{}, error) {
+	member := members.FromContext(req.Context())
+	if member == nil {
+		return nil, weberrs.ErrNotAuthorized
+	}
+
+	status, err := th.totp.Status(req.Context(), member.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pageData = make(map[string]interface{})
+	pageData[csrf.TemplateTag] = csrf.TemplateField(req)
+	pageData["Status"] = status
+
+	pageData["Flashes"], err = th.fh.GetAll(w, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return pageData, nil
+}
+
+// enroll creates a new secret and shows it as a QR code for the authenticator app
+func (th totpHandler) enroll(w http.ResponseWriter, req *http.Request) (interface{}, error) {
+	member := members.FromContext(req.Context())
+	if member == nil {
+		return nil, weberrs.ErrNotAuthorized
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      th.netInfo.Domain,
+		AccountName: member.PubKey.ShortSigil(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate two-factor secret: %w", err)
+	}
+
+	err = th.totp.Enroll(req.Context(), member.ID, key.Secret())
+	if err != nil {
+		return nil, err
+	}
+
+	qrCode, err := qrcode.New(key.URL(), qrcode.Medium)
+	if err != nil {
+		return nil, err
+	}
+
+	qrCode.BackgroundColor = color.Transparent // transparent to fit into the page
+	qrCode.ForegroundColor = color.Black
+
+	qrCodeData, err := qrCode.PNG(-5)
+	if err != nil {
+		return nil, err
+	}
+	qrURI := "data:image/png;base64," + base64.StdEncoding.EncodeToString(qrCodeData)
+
+	return map[string]interface{}{
+		csrf.TemplateTag: csrf.TemplateField(req),
+		"Secret":         key.Secret(),
+		// template.URL signals the template engine that this isn't fishy and from a trusted source
+		"QRCodeURI": template.URL(qrURI),
+	}, nil
+}
+
+// confirm checks the first code from the authenticator app and shows the recovery codes, once
+func (th totpHandler) confirm(w http.ResponseWriter, req *http.Request) (interface{}, error) {
+	member := members.FromContext(req.Context())
+	if member == nil {
+		return nil, weberrs.ErrNotAuthorized
+	}
+
+	if err := req.ParseForm(); err != nil {
+		return nil, weberrs.ErrBadRequest{Where: "Form data", Details: err}
+	}
+
+	recoveryCodes, err := th.totp.Confirm(req.Context(), member.ID, req.FormValue("code"))
+	if err != nil {
+		if errors.Is(err, roomdb.ErrInvalidTOTPCode) || errors.Is(err, roomdb.ErrNotFound) {
+			return nil, weberrs.ErrRedirect{
+				Path:   th.urlTo(router.MembersTOTP).Path,
+				Reason: weberrs.ErrGenericLocalized{Label: "MemberTOTPConfirmFailed"},
+			}
+		}
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"RecoveryCodes": recoveryCodes,
+	}, nil
+}
+
+// disable turns the second factor off, which needs a current code (or a recovery code)
+func (th totpHandler) disable(w http.ResponseWriter, req *http.Request) {
+	member := members.FromContext(req.Context())
+	if member == nil {
+		th.r.Error(w, req, http.StatusUnauthorized, weberrs.ErrNotAuthorized)
+		return
+	}
+
+	if req.Method != http.MethodPost {
+		th.r.Error(w, req, http.StatusBadRequest, fmt.Errorf("expected POST method"))
+		return
+	}
+
+	err := req.ParseForm()
+	if err != nil {
+		th.r.Error(w, req, http.StatusBadRequest, err)
+		return
+	}
+
+	redirectURL := th.urlTo(router.MembersTOTP).Path
+	defer http.Redirect(w, req, redirectURL, http.StatusSeeOther)
+
+	err = th.totp.Verify(req.Context(), member.ID, req.FormValue("code"))
+	if err != nil {
+		if errors.Is(err, roomdb.ErrInvalidTOTPCode) {
+			err = weberrs.ErrGenericLocalized{Label: "ErrorAuthTOTPBadCode"}
+		}
+		th.fh.AddError(w, req, err)
+		return
+	}
+
+	err = th.totp.Disable(req.Context(), member.ID)
+	if err != nil {
+		th.fh.AddError(w, req, err)
+		return
+	}
+
+	th.fh.AddMessage(w, req, "MemberTOTPDisabled")
+}
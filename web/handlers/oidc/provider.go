@@ -0,0 +1,655 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+// Package oidc lets the room act as an OpenID Connect provider, so that members can sign into other apps of the community with their room identity.
+//
+// Only the authorization code flow is supported, with PKCE (S256) and confidential clients, which are registered by the admins.
+// Members sign in with ssb as usual and then agree to share their identity with the app.
+// The id tokens are signed with RS256 and have the feed of the member as the subject, together with the role and the aliases.
+package oidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/csrf"
+	"github.com/gorilla/mux"
+	refs "github.com/ssbc/go-ssb-refs"
+	"go.mindeco.de/http/render"
+	"go.mindeco.de/log/level"
+	"go.mindeco.de/logging"
+
+	"github.com/ssbc/go-ssb-room/v2/internal/aliases"
+	"github.com/ssbc/go-ssb-room/v2/internal/network"
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web"
+	weberrors "github.com/ssbc/go-ssb-room/v2/web/errors"
+	"github.com/ssbc/go-ssb-room/v2/web/members"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+)
+
+var HTMLTemplates = []string{
+	"oidc/authorize.tmpl",
+}
+
+// Databases is an option struct that encapsulates the required database services
+type Databases struct {
+	Clients    roomdb.OIDCClientsService
+	Members    roomdb.MembersService
+	DeniedKeys roomdb.DeniedKeysService
+}
+
+// SignInRedirector remembers the page to come back to once the member signed in.
+// It's implemented by the sign-in with ssb handler.
+type SignInRedirector interface {
+	RedirectAfterSignIn(w http.ResponseWriter, req *http.Request, path string) error
+}
+
+const (
+	// how long the app has to exchange the code for the tokens
+	codeLifetime = 2 * time.Minute
+
+	tokenLifetime = time.Hour
+)
+
+type provider struct {
+	r      *render.Renderer
+	urlTo  web.URLMaker
+	signIn SignInRedirector
+
+	// issuer is the base url of the room, like https://room.example
+	issuer string
+	signer signer
+
+	dbs Databases
+}
+
+// NewProvider hooks up the routes of the provider on the passed router, which needs the ones from router.OIDC.
+func NewProvider(
+	m *mux.Router,
+	r *render.Renderer,
+	netInfo network.ServerEndpointDetails,
+	key *rsa.PrivateKey,
+	signIn SignInRedirector,
+	dbs Databases,
+) {
+	urlTo := web.NewURLTo(m, netInfo)
+
+	issuer := urlTo(router.CompleteIndex)
+	issuer.Path = ""
+
+	p := provider{
+		r:      r,
+		urlTo:  urlTo,
+		signIn: signIn,
+
+		issuer: issuer.String(),
+		signer: newSigner(key),
+
+		dbs: dbs,
+	}
+
+	m.Get(router.OIDCDiscovery).HandlerFunc(p.discovery)
+	m.Get(router.OIDCKeys).HandlerFunc(p.keys)
+	m.Get(router.OIDCAuthorize).HandlerFunc(p.authorize)
+	m.Get(router.OIDCAuthorizeConfirm).HandlerFunc(p.confirm)
+	m.Get(router.OIDCToken).HandlerFunc(p.token)
+	m.Get(router.OIDCUserInfo).HandlerFunc(p.userInfo)
+}
+
+type discoveryJSON struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserInfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+
+	ResponseTypes     []string `json:"response_types_supported"`
+	GrantTypes        []string `json:"grant_types_supported"`
+	SubjectTypes      []string `json:"subject_types_supported"`
+	SigningAlgorithms []string `json:"id_token_signing_alg_values_supported"`
+	Scopes            []string `json:"scopes_supported"`
+	Claims            []string `json:"claims_supported"`
+	AuthMethods       []string `json:"token_endpoint_auth_methods_supported"`
+	ChallengeMethods  []string `json:"code_challenge_methods_supported"`
+}
+
+func (p provider) discovery(w http.ResponseWriter, req *http.Request) {
+	sendJSON(w, req, http.StatusOK, discoveryJSON{
+		Issuer:                p.issuer,
+		AuthorizationEndpoint: p.urlTo(router.OIDCAuthorize).String(),
+		TokenEndpoint:         p.urlTo(router.OIDCToken).String(),
+		UserInfoEndpoint:      p.urlTo(router.OIDCUserInfo).String(),
+		JWKSURI:               p.urlTo(router.OIDCKeys).String(),
+
+		ResponseTypes:     []string{"code"},
+		GrantTypes:        []string{"authorization_code"},
+		SubjectTypes:      []string{"public"},
+		SigningAlgorithms: []string{"RS256"},
+		Scopes:            supportedScopes,
+		Claims:            []string{"sub", "iss", "aud", "exp", "iat", "nonce", "role", "aliases", "preferred_username"},
+		AuthMethods:       []string{"client_secret_basic", "client_secret_post"},
+		ChallengeMethods:  []string{"S256"},
+	})
+}
+
+func (p provider) keys(w http.ResponseWriter, req *http.Request) {
+	sendJSON(w, req, http.StatusOK, p.signer.keySet())
+}
+
+var supportedScopes = []string{"openid", "profile"}
+
+// authRequest holds the checked parameters of an authorization request
+type authRequest struct {
+	client roomdb.OIDCClient
+
+	redirectURI   string
+	state         string
+	nonce         string
+	scope         string
+	codeChallenge string
+}
+
+// authError is sent back to the app, as the error parameter of the redirect uri
+type authError struct {
+	code        string
+	description string
+}
+
+func (e authError) Error() string { return fmt.Sprintf("oidc: %s: %s", e.code, e.description) }
+
+// parseAuthRequest checks the parameters of the authorization request.
+// As long as the app and its redirect uri are not known, the errors are shown to the member.
+// After that they are authErrors, which are sent back to the app.
+func (p provider) parseAuthRequest(ctx context.Context, vals url.Values) (authRequest, error) {
+	var ar authRequest
+
+	clientID := vals.Get("client_id")
+	if clientID == "" {
+		return ar, weberrors.ErrBadRequest{Where: "client_id", Details: fmt.Errorf("the app didn't say who it is")}
+	}
+
+	client, err := p.dbs.Clients.GetByClientID(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, roomdb.ErrNotFound) {
+			return ar, weberrors.ErrBadRequest{Where: "client_id", Details: fmt.Errorf("the app is not registered in this room")}
+		}
+		return ar, err
+	}
+
+	redirectURI := vals.Get("redirect_uri")
+	if !client.HasRedirectURI(redirectURI) {
+		return ar, weberrors.ErrBadRequest{Where: "redirect_uri", Details: fmt.Errorf("the redirect uri is not registered for the app")}
+	}
+
+	ar = authRequest{
+		client:        client,
+		redirectURI:   redirectURI,
+		state:         vals.Get("state"),
+		nonce:         vals.Get("nonce"),
+		codeChallenge: vals.Get("code_challenge"),
+	}
+
+	if vals.Get("response_type") != "code" {
+		return ar, authError{"unsupported_response_type", "only the authorization code flow is supported"}
+	}
+
+	// only keep the scopes we know about
+	var (
+		scopes    []string
+		hasOpenID bool
+	)
+	for _, s := range strings.Fields(vals.Get("scope")) {
+		for _, supported := range supportedScopes {
+			if s == supported {
+				scopes = append(scopes, s)
+			}
+		}
+		hasOpenID = hasOpenID || s == "openid"
+	}
+	ar.scope = strings.Join(scopes, " ")
+
+	if !hasOpenID {
+		return ar, authError{"invalid_scope", "the openid scope is required"}
+	}
+
+	challenge, err := b64.DecodeString(ar.codeChallenge)
+	if vals.Get("code_challenge_method") != "S256" || err != nil || len(challenge) != sha256.Size {
+		return ar, authError{"invalid_request", "a PKCE code challenge with the S256 method is required"}
+	}
+
+	return ar, nil
+}
+
+// values returns the parameters to send the request again, from the consent page
+func (ar authRequest) values() url.Values {
+	vals := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {ar.client.ClientID},
+		"redirect_uri":          {ar.redirectURI},
+		"scope":                 {ar.scope},
+		"code_challenge":        {ar.codeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+	if ar.state != "" {
+		vals.Set("state", ar.state)
+	}
+	if ar.nonce != "" {
+		vals.Set("nonce", ar.nonce)
+	}
+	return vals
+}
+
+// callback returns the redirect uri with the passed parameters and the state of the app added to it
+func (ar authRequest) callback(params url.Values) string {
+	// the uri was checked when the app was registered
+	u, err := url.Parse(ar.redirectURI)
+	if err != nil {
+		panic(err)
+	}
+
+	q := u.Query()
+	for k, v := range params {
+		q[k] = v
+	}
+	if ar.state != "" {
+		q.Set("state", ar.state)
+	}
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
+// fail sends authErrors back to the app and shows the other errors to the member.
+// Unknown apps and redirect uris are the fault of the request, only the other errors are internal ones.
+func (p provider) fail(w http.ResponseWriter, req *http.Request, ar authRequest, err error) {
+	var ae authError
+	if errors.As(err, &ae) {
+		http.Redirect(w, req, ar.callback(url.Values{
+			"error":             {ae.code},
+			"error_description": {ae.description},
+		}), http.StatusSeeOther)
+		return
+	}
+
+	var br weberrors.ErrBadRequest
+	if errors.As(err, &br) {
+		p.r.Error(w, req, http.StatusBadRequest, err)
+		return
+	}
+
+	p.r.Error(w, req, http.StatusInternalServerError, err)
+}
+
+// authorize shows the consent page, after sending the member to sign in with ssb if needed
+func (p provider) authorize(w http.ResponseWriter, req *http.Request) {
+	ar, err := p.parseAuthRequest(req.Context(), req.URL.Query())
+	if err != nil {
+		p.fail(w, req, ar, err)
+		return
+	}
+
+	if members.FromContext(req.Context()) == nil {
+		err = p.signIn.RedirectAfterSignIn(w, req, req.URL.RequestURI())
+		if err != nil {
+			p.r.Error(w, req, http.StatusInternalServerError, err)
+			return
+		}
+
+		http.Redirect(w, req, p.urlTo(router.AuthWithSSBLogin).String(), http.StatusSeeOther)
+		return
+	}
+
+	p.r.Render(w, req, "oidc/authorize.tmpl", http.StatusOK, map[string]interface{}{
+		"Client":         ar.client,
+		"Params":         ar.values(),
+		csrf.TemplateTag: csrf.TemplateField(req),
+	})
+}
+
+// confirm is the answer of the member on the consent page
+func (p provider) confirm(w http.ResponseWriter, req *http.Request) {
+	if err := req.ParseForm(); err != nil {
+		p.r.Error(w, req, http.StatusBadRequest, weberrors.ErrBadRequest{Where: "Form data", Details: err})
+		return
+	}
+
+	ctx := req.Context()
+
+	member := members.FromContext(ctx)
+	if member == nil {
+		p.r.Error(w, req, http.StatusUnauthorized, weberrors.ErrNotAuthorized)
+		return
+	}
+
+	ar, err := p.parseAuthRequest(ctx, req.PostForm)
+	if err != nil {
+		p.fail(w, req, ar, err)
+		return
+	}
+
+	if req.PostForm.Get("allow") == "" {
+		p.fail(w, req, ar, authError{"access_denied", "the member didn't agree to sign in"})
+		return
+	}
+
+	code, err := p.dbs.Clients.CreateCode(ctx, roomdb.OIDCAuthCode{
+		ClientID:      ar.client.ID,
+		MemberID:      member.ID,
+		RedirectURI:   ar.redirectURI,
+		CodeChallenge: ar.codeChallenge,
+		Nonce:         ar.nonce,
+		Scope:         ar.scope,
+		ExpiresAt:     time.Now().Add(codeLifetime),
+	})
+	if err != nil {
+		p.r.Error(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	level.Info(logging.FromContext(ctx)).Log("event", "oidc sign-in", "client", ar.client.ClientID, "member", member.PubKey.ShortSigil())
+	http.Redirect(w, req, ar.callback(url.Values{"code": {code}}), http.StatusSeeOther)
+}
+
+// profileClaims describe the member, in the id token and the userinfo response
+type profileClaims struct {
+	Subject string `json:"sub"`
+
+	// only set if the app was granted the profile scope
+	Role              string   `json:"role,omitempty"`
+	Aliases           []string `json:"aliases,omitempty"`
+	PreferredUsername string   `json:"preferred_username,omitempty"`
+}
+
+var roleNames = map[roomdb.Role]string{
+	roomdb.RoleMember:    "member",
+	roomdb.RoleModerator: "moderator",
+	roomdb.RoleAdmin:     "admin",
+}
+
+// newProfileClaims only includes the role and the aliases if the profile scope is part of the granted scope
+func newProfileClaims(m roomdb.Member, scope string) profileClaims {
+	pc := profileClaims{
+		Subject: m.PubKey.String(),
+	}
+	if !hasScope(scope, "profile") {
+		return pc
+	}
+
+	pc.Role = roleNames[m.Role]
+	pc.Aliases = make([]string, len(m.Aliases))
+	// the apps show them to people, so they get the unicode form instead of the stored punycode
+	for i, a := range m.Aliases {
+		pc.Aliases[i] = aliases.ToUnicode(a.Name)
+	}
+	if len(pc.Aliases) > 0 {
+		pc.PreferredUsername = pc.Aliases[0]
+	}
+	return pc
+}
+
+func hasScope(scope, want string) bool {
+	for _, s := range strings.Fields(scope) {
+		if s == want {
+			return true
+		}
+	}
+	return false
+}
+
+type idTokenClaims struct {
+	Issuer    string `json:"iss"`
+	Audience  string `json:"aud"`
+	ExpiresAt int64  `json:"exp"`
+	IssuedAt  int64  `json:"iat"`
+	Nonce     string `json:"nonce,omitempty"`
+
+	profileClaims
+}
+
+// accessTokenClaims are only checked by the userinfo endpoint
+type accessTokenClaims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	Audience  string `json:"aud"`
+	ClientID  string `json:"client_id"`
+	Scope     string `json:"scope"`
+	ExpiresAt int64  `json:"exp"`
+	IssuedAt  int64  `json:"iat"`
+}
+
+type tokenResponseJSON struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	IDToken     string `json:"id_token"`
+	Scope       string `json:"scope"`
+}
+
+// token exchanges the code for the tokens. It's called by the app, not the browser of the member.
+func (p provider) token(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+
+	if err := req.ParseForm(); err != nil {
+		sendTokenError(w, req, http.StatusBadRequest, "invalid_request", "invalid form data")
+		return
+	}
+
+	client, err := p.authenticateClient(req)
+	if err != nil {
+		if errors.Is(err, roomdb.ErrNotFound) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="oidc"`)
+			sendTokenError(w, req, http.StatusUnauthorized, "invalid_client", "unknown app or wrong secret")
+			return
+		}
+		level.Error(logging.FromContext(ctx)).Log("event", "failed to check oidc client", "err", err)
+		sendTokenError(w, req, http.StatusInternalServerError, "server_error", "internal error")
+		return
+	}
+
+	if req.PostForm.Get("grant_type") != "authorization_code" {
+		sendTokenError(w, req, http.StatusBadRequest, "unsupported_grant_type", "only authorization codes can be exchanged")
+		return
+	}
+
+	auth, err := p.dbs.Clients.ConsumeCode(ctx, req.PostForm.Get("code"))
+	if err != nil {
+		if errors.Is(err, roomdb.ErrNotFound) {
+			sendTokenError(w, req, http.StatusBadRequest, "invalid_grant", "unknown or expired code")
+			return
+		}
+		level.Error(logging.FromContext(ctx)).Log("event", "failed to consume oidc code", "err", err)
+		sendTokenError(w, req, http.StatusInternalServerError, "server_error", "internal error")
+		return
+	}
+
+	if auth.ClientID != client.ID {
+		sendTokenError(w, req, http.StatusBadRequest, "invalid_grant", "the code was issued to another app")
+		return
+	}
+
+	if auth.RedirectURI != req.PostForm.Get("redirect_uri") {
+		sendTokenError(w, req, http.StatusBadRequest, "invalid_grant", "the redirect uri doesn't match the authorization request")
+		return
+	}
+
+	if !checkCodeVerifier(req.PostForm.Get("code_verifier"), auth.CodeChallenge) {
+		sendTokenError(w, req, http.StatusBadRequest, "invalid_grant", "wrong code verifier")
+		return
+	}
+
+	// get the current role and aliases
+	member, err := p.dbs.Members.GetByID(ctx, auth.MemberID)
+	if err != nil {
+		if errors.Is(err, roomdb.ErrNotFound) {
+			sendTokenError(w, req, http.StatusBadRequest, "invalid_grant", "the member is not in the room anymore")
+			return
+		}
+		level.Error(logging.FromContext(ctx)).Log("event", "failed to get oidc member", "err", err)
+		sendTokenError(w, req, http.StatusInternalServerError, "server_error", "internal error")
+		return
+	}
+
+	// for instance suspended through the invite tree after they agreed
+	if p.dbs.DeniedKeys.HasFeed(ctx, member.PubKey) {
+		sendTokenError(w, req, http.StatusBadRequest, "invalid_grant", "the member was denied access to the room")
+		return
+	}
+
+	now := time.Now()
+	expires := now.Add(tokenLifetime)
+
+	idToken, err := p.signer.sign(typeIDToken, idTokenClaims{
+		Issuer:    p.issuer,
+		Audience:  client.ClientID,
+		ExpiresAt: expires.Unix(),
+		IssuedAt:  now.Unix(),
+		Nonce:     auth.Nonce,
+
+		profileClaims: newProfileClaims(member, auth.Scope),
+	})
+	if err != nil {
+		level.Error(logging.FromContext(ctx)).Log("event", "failed to sign id token", "err", err)
+		sendTokenError(w, req, http.StatusInternalServerError, "server_error", "internal error")
+		return
+	}
+
+	accessToken, err := p.signer.sign(typeAccessToken, accessTokenClaims{
+		Issuer:    p.issuer,
+		Subject:   member.PubKey.String(),
+		Audience:  p.urlTo(router.OIDCUserInfo).String(),
+		ClientID:  client.ClientID,
+		Scope:     auth.Scope,
+		ExpiresAt: expires.Unix(),
+		IssuedAt:  now.Unix(),
+	})
+	if err != nil {
+		level.Error(logging.FromContext(ctx)).Log("event", "failed to sign access token", "err", err)
+		sendTokenError(w, req, http.StatusInternalServerError, "server_error", "internal error")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	sendJSON(w, req, http.StatusOK, tokenResponseJSON{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(tokenLifetime.Seconds()),
+		IDToken:     idToken,
+		Scope:       auth.Scope,
+	})
+}
+
+// authenticateClient checks the credentials of the app, from the basic auth header (client_secret_basic) or the form (client_secret_post)
+func (p provider) authenticateClient(req *http.Request) (roomdb.OIDCClient, error) {
+	clientID, secret, ok := req.BasicAuth()
+	if ok {
+		// both parts are form encoded before they are put into the header (RFC 6749, section 2.3.1)
+		var err error
+		if clientID, err = url.QueryUnescape(clientID); err != nil {
+			return roomdb.OIDCClient{}, roomdb.ErrNotFound
+		}
+		if secret, err = url.QueryUnescape(secret); err != nil {
+			return roomdb.OIDCClient{}, roomdb.ErrNotFound
+		}
+	} else {
+		clientID = req.PostForm.Get("client_id")
+		secret = req.PostForm.Get("client_secret")
+	}
+
+	if clientID == "" || secret == "" {
+		return roomdb.OIDCClient{}, roomdb.ErrNotFound
+	}
+
+	return p.dbs.Clients.CheckSecret(req.Context(), clientID, secret)
+}
+
+// checkCodeVerifier is the PKCE check (RFC 7636) of the S256 method
+func checkCodeVerifier(verifier, challenge string) bool {
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+	computed := b64.EncodeToString(sum[:])
+
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(challenge)) == 1
+}
+
+// userInfo returns the current claims of the member that the access token belongs to
+func (p provider) userInfo(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+
+	sendInvalidToken := func() {
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		sendTokenError(w, req, http.StatusUnauthorized, "invalid_token", "missing, invalid or expired access token")
+	}
+
+	token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if token == "" {
+		sendInvalidToken()
+		return
+	}
+
+	var claims accessTokenClaims
+	if err := p.signer.verify(token, typeAccessToken, &claims); err != nil {
+		sendInvalidToken()
+		return
+	}
+
+	if claims.Issuer != p.issuer || time.Now().Unix() >= claims.ExpiresAt {
+		sendInvalidToken()
+		return
+	}
+
+	feed, err := refs.ParseFeedRef(claims.Subject)
+	if err != nil {
+		sendInvalidToken()
+		return
+	}
+
+	member, err := p.dbs.Members.GetByFeed(ctx, feed)
+	if err != nil {
+		if errors.Is(err, roomdb.ErrNotFound) {
+			sendInvalidToken()
+			return
+		}
+		level.Error(logging.FromContext(ctx)).Log("event", "failed to get oidc member", "err", err)
+		sendTokenError(w, req, http.StatusInternalServerError, "server_error", "internal error")
+		return
+	}
+
+	if p.dbs.DeniedKeys.HasFeed(ctx, member.PubKey) {
+		sendInvalidToken()
+		return
+	}
+
+	sendJSON(w, req, http.StatusOK, newProfileClaims(member, claims.Scope))
+}
+
+type tokenErrorJSON struct {
+	Error       string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func sendTokenError(w http.ResponseWriter, req *http.Request, status int, code, description string) {
+	sendJSON(w, req, status, tokenErrorJSON{Error: code, Description: description})
+}
+
+func sendJSON(w http.ResponseWriter, req *http.Request, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		level.Warn(logging.FromContext(req.Context())).Log("event", "failed to send json", "err", err)
+	}
+}
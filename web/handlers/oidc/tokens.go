@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package oidc
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// signer creates and checks the RS256 signed JSON web tokens of the provider.
+// The id tokens are checked by the apps, with the public key from the jwks document.
+type signer struct {
+	key *rsa.PrivateKey
+
+	// kid is the JWK thumbprint (RFC 7638) of the public key
+	kid string
+}
+
+const (
+	typeIDToken     = "JWT"
+	typeAccessToken = "at+jwt"
+)
+
+type tokenHeader struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+	KeyID     string `json:"kid"`
+}
+
+type jsonWebKey struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+}
+
+var b64 = base64.RawURLEncoding
+
+func newSigner(key *rsa.PrivateKey) signer {
+	jwk := publicJWK(&key.PublicKey)
+
+	// the required members in lexicographic order, without whitespace
+	thumbprint := fmt.Sprintf(`{"e":%q,"kty":"RSA","n":%q}`, jwk.Exponent, jwk.Modulus)
+	sum := sha256.Sum256([]byte(thumbprint))
+
+	return signer{
+		key: key,
+		kid: b64.EncodeToString(sum[:]),
+	}
+}
+
+func publicJWK(pub *rsa.PublicKey) jsonWebKey {
+	return jsonWebKey{
+		KeyType:   "RSA",
+		Use:       "sig",
+		Algorithm: "RS256",
+		Modulus:   b64.EncodeToString(pub.N.Bytes()),
+		Exponent:  b64.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}
+}
+
+// keySet returns the content of the jwks document
+func (s signer) keySet() interface{} {
+	jwk := publicJWK(&s.key.PublicKey)
+	jwk.KeyID = s.kid
+	return map[string][]jsonWebKey{"keys": {jwk}}
+}
+
+// sign encodes the claims as the payload of a token of the passed type
+func (s signer) sign(typ string, claims interface{}) (string, error) {
+	header, err := json.Marshal(tokenHeader{Algorithm: "RS256", Type: typ, KeyID: s.kid})
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signed := b64.EncodeToString(header) + "." + b64.EncodeToString(payload)
+
+	digest := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(nil, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("oidc: failed to sign token: %w", err)
+	}
+
+	return signed + "." + b64.EncodeToString(sig), nil
+}
+
+var errInvalidToken = errors.New("oidc: invalid token")
+
+// verify checks that the token was signed by this provider and has the passed type, and decodes its payload into claims.
+// The claims themselves, like the expiry, need to be checked by the caller.
+func (s signer) verify(token, typ string, claims interface{}) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return errInvalidToken
+	}
+
+	headerBytes, err := b64.DecodeString(parts[0])
+	if err != nil {
+		return errInvalidToken
+	}
+
+	var header tokenHeader
+	if err := json.Unmarshal(headerBytes, &header); err != nil {
+		return errInvalidToken
+	}
+
+	if header.Algorithm != "RS256" || header.Type != typ || header.KeyID != s.kid {
+		return errInvalidToken
+	}
+
+	sig, err := b64.DecodeString(parts[2])
+	if err != nil {
+		return errInvalidToken
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(&s.key.PublicKey, crypto.SHA256, digest[:], sig); err != nil {
+		return errInvalidToken
+	}
+
+	payload, err := b64.DecodeString(parts[1])
+	if err != nil {
+		return errInvalidToken
+	}
+
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return errInvalidToken
+	}
+
+	return nil
+}
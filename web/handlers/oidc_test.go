@@ -0,0 +1,442 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package handlers
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssbc/go-ssb-room/v2/internal/maybemod/keys"
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+	"github.com/ssbc/go-ssb-room/v2/web/webassert"
+)
+
+func TestOIDCDiscovery(t *testing.T) {
+	ts := setup(t)
+	a, r := assert.New(t), require.New(t)
+
+	resp := ts.Client.GetBody(ts.URLTo(router.OIDCDiscovery))
+	r.Equal(http.StatusOK, resp.Code)
+	a.Equal("application/json", resp.Header().Get("Content-Type"))
+
+	var doc map[string]interface{}
+	r.NoError(json.NewDecoder(resp.Body).Decode(&doc))
+
+	a.Equal("https://localhost", doc["issuer"])
+	a.Equal(ts.URLTo(router.OIDCAuthorize).String(), doc["authorization_endpoint"])
+	a.Equal(ts.URLTo(router.OIDCToken).String(), doc["token_endpoint"])
+	a.Equal(ts.URLTo(router.OIDCKeys).String(), doc["jwks_uri"])
+	a.Equal([]interface{}{"S256"}, doc["code_challenge_methods_supported"])
+}
+
+func TestOIDCAuthorizeErrors(t *testing.T) {
+	ts := setup(t)
+	a := assert.New(t)
+
+	// unknown apps are the fault of the request
+	ts.OIDCClientsDB.GetByClientIDReturns(roomdb.OIDCClient{}, roomdb.ErrNotFound)
+	_, resp := ts.Client.GetHTML(ts.URLTo(router.OIDCAuthorize,
+		"client_id", "nope",
+		"redirect_uri", "https://wiki.example/cb",
+		"response_type", "code",
+	))
+	a.Equal(http.StatusBadRequest, resp.Code)
+
+	// and so are missing ones
+	_, resp = ts.Client.GetHTML(ts.URLTo(router.OIDCAuthorize,
+		"redirect_uri", "https://wiki.example/cb",
+		"response_type", "code",
+	))
+	a.Equal(http.StatusBadRequest, resp.Code)
+
+	ts.OIDCClientsDB.GetByClientIDReturns(roomdb.OIDCClient{ID: 1, ClientID: "wiki", RedirectURIs: []string{"https://wiki.example/cb"}}, nil)
+
+	// wrong redirect uris are not followed
+	_, resp = ts.Client.GetHTML(ts.URLTo(router.OIDCAuthorize,
+		"client_id", "wiki",
+		"redirect_uri", "https://evil.example/cb",
+		"response_type", "code",
+	))
+	a.Equal(http.StatusBadRequest, resp.Code)
+
+	// the others are sent back to the app
+	resp = ts.Client.GetBody(ts.URLTo(router.OIDCAuthorize,
+		"client_id", "wiki",
+		"redirect_uri", "https://wiki.example/cb",
+		"response_type", "code",
+		"scope", "openid",
+		"state", "xyz",
+	))
+	a.Equal(http.StatusSeeOther, resp.Code)
+
+	loc, err := url.Parse(resp.Header().Get("Location"))
+	a.NoError(err)
+	a.Equal("wiki.example", loc.Host)
+	a.Equal("invalid_request", loc.Query().Get("error"), "PKCE is required")
+	a.Equal("xyz", loc.Query().Get("state"))
+
+	a.Equal(0, ts.OIDCClientsDB.CreateCodeCallCount())
+}
+
+func TestOIDCHappyPath(t *testing.T) {
+	ts := setup(t)
+	a, r := assert.New(t), require.New(t)
+
+	client, err := keys.NewKeyPair(nil)
+	r.NoError(err)
+	testMember := roomdb.Member{
+		ID:      23,
+		Role:    roomdb.RoleModerator,
+		PubKey:  client.Feed,
+		Aliases: []roomdb.Alias{{Name: "alf"}},
+	}
+	ts.MembersDB.GetByIDReturns(testMember, nil)
+	ts.MembersDB.GetByFeedReturns(testMember, nil)
+
+	wiki := roomdb.OIDCClient{ID: 1, ClientID: "wiki-id", Name: "the wiki", RedirectURIs: []string{"https://wiki.example/cb"}}
+	ts.OIDCClientsDB.GetByClientIDReturns(wiki, nil)
+
+	verifier := strings.Repeat("v", 50)
+	sum := sha256.Sum256([]byte(verifier))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	authorizeURL := ts.URLTo(router.OIDCAuthorize,
+		"client_id", wiki.ClientID,
+		"redirect_uri", "https://wiki.example/cb",
+		"response_type", "code",
+		"scope", "openid profile",
+		"state", "xyz",
+		"nonce", "n-0S6",
+		"code_challenge", challenge,
+		"code_challenge_method", "S256",
+	)
+
+	// not signed in yet, go sign in with ssb and come back
+	resp := ts.Client.GetBody(authorizeURL)
+	r.Equal(http.StatusSeeOther, resp.Code)
+	a.Equal(ts.URLTo(router.AuthWithSSBLogin).String(), resp.Header().Get("Location"))
+
+	ts.AuthWithSSB.CheckTokenReturns(testMember.ID, nil)
+	resp = ts.Client.GetBody(ts.URLTo(router.AuthWithSSBFinalize, "token", "the-token"))
+	r.Equal(http.StatusTemporaryRedirect, resp.Code)
+	a.Equal(authorizeURL.RequestURI(), resp.Header().Get("Location"))
+
+	// now the consent page
+	html, resp := ts.Client.GetHTML(authorizeURL)
+	r.Equal(http.StatusOK, resp.Code, "wrong HTTP status code for the consent page")
+	webassert.Localized(t, html, []webassert.LocalizedElement{
+		{"title", "OIDCAuthorizeTitle"},
+		{"#welcome", "OIDCAuthorizeWelcome"},
+	})
+	a.Equal("the wiki", html.Find("#client").Text())
+
+	form := html.Find("#consent")
+	consent := webassert.CSRFTokenPresent(t, form)
+	form.Find("input[type=hidden]").Each(func(_ int, input *goquery.Selection) {
+		name, _ := input.Attr("name")
+		value, _ := input.Attr("value")
+		consent.Set(name, value)
+	})
+	a.Equal(challenge, consent.Get("code_challenge"))
+	consent.Set("allow", "yes")
+
+	ts.OIDCClientsDB.CreateCodeReturns("the-code", nil)
+
+	var refererHeader = make(http.Header)
+	refererHeader.Set("Referer", "https://localhost")
+	ts.Client.SetHeaders(refererHeader)
+
+	resp = ts.Client.PostForm(ts.URLTo(router.OIDCAuthorizeConfirm), consent)
+	r.Equal(http.StatusSeeOther, resp.Code, resp.Body.String())
+	a.Equal("https://wiki.example/cb?code=the-code&state=xyz", resp.Header().Get("Location"))
+
+	r.Equal(1, ts.OIDCClientsDB.CreateCodeCallCount())
+	_, auth := ts.OIDCClientsDB.CreateCodeArgsForCall(0)
+	a.Equal(wiki.ID, auth.ClientID)
+	a.Equal(testMember.ID, auth.MemberID)
+	a.Equal("n-0S6", auth.Nonce)
+	a.Equal("openid profile", auth.Scope)
+	a.True(auth.ExpiresAt.After(time.Now()))
+
+	// the app exchanges the code, without a csrf token
+	ts.OIDCClientsDB.CheckSecretReturns(wiki, nil)
+	ts.OIDCClientsDB.ConsumeCodeReturns(auth, nil)
+
+	tokenURL := ts.URLTo(router.OIDCToken)
+	tokenVals := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {"the-code"},
+		"redirect_uri":  {"https://wiki.example/cb"},
+		"code_verifier": {"wrong" + verifier},
+	}
+
+	appHeader := make(http.Header)
+	appHeader.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte("wiki-id:the-secret")))
+	ts.Client.SetHeaders(appHeader)
+
+	resp = ts.Client.PostForm(tokenURL, tokenVals)
+	a.Equal(http.StatusBadRequest, resp.Code, "wrong verifier")
+	a.Contains(resp.Body.String(), "invalid_grant")
+
+	tokenVals.Set("code_verifier", verifier)
+	resp = ts.Client.PostForm(tokenURL, tokenVals)
+	r.Equal(http.StatusOK, resp.Code, resp.Body.String())
+	a.Equal("no-store", resp.Header().Get("Cache-Control"))
+
+	_, clientID, secret := ts.OIDCClientsDB.CheckSecretArgsForCall(1)
+	a.Equal("wiki-id", clientID)
+	a.Equal("the-secret", secret)
+
+	var tokens struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		IDToken     string `json:"id_token"`
+	}
+	r.NoError(json.NewDecoder(resp.Body).Decode(&tokens))
+	a.Equal("Bearer", tokens.TokenType)
+
+	// check the id token with the published key
+	ts.Client.ClearHeaders()
+	resp = ts.Client.GetBody(ts.URLTo(router.OIDCKeys))
+	r.Equal(http.StatusOK, resp.Code)
+	var keySet struct {
+		Keys []struct {
+			N string `json:"n"`
+			E string `json:"e"`
+		} `json:"keys"`
+	}
+	r.NoError(json.NewDecoder(resp.Body).Decode(&keySet))
+	r.Len(keySet.Keys, 1)
+
+	nBytes, err := base64.RawURLEncoding.DecodeString(keySet.Keys[0].N)
+	r.NoError(err)
+	eBytes, err := base64.RawURLEncoding.DecodeString(keySet.Keys[0].E)
+	r.NoError(err)
+	pub := &rsa.PublicKey{N: new(big.Int).SetBytes(nBytes), E: int(new(big.Int).SetBytes(eBytes).Int64())}
+
+	parts := strings.Split(tokens.IDToken, ".")
+	r.Len(parts, 3)
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	r.NoError(err)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	r.NoError(rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig))
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	r.NoError(err)
+	var claims struct {
+		Issuer   string   `json:"iss"`
+		Subject  string   `json:"sub"`
+		Audience string   `json:"aud"`
+		Nonce    string   `json:"nonce"`
+		Role     string   `json:"role"`
+		Aliases  []string `json:"aliases"`
+	}
+	r.NoError(json.Unmarshal(payload, &claims))
+	a.Equal("https://localhost", claims.Issuer)
+	a.Equal(testMember.PubKey.String(), claims.Subject)
+	a.Equal("wiki-id", claims.Audience)
+	a.Equal("n-0S6", claims.Nonce)
+	a.Equal("moderator", claims.Role)
+	a.Equal([]string{"alf"}, claims.Aliases)
+
+	// the access token works for the userinfo endpoint
+	userHeader := make(http.Header)
+	userHeader.Set("Authorization", "Bearer "+tokens.AccessToken)
+	ts.Client.SetHeaders(userHeader)
+
+	resp = ts.Client.GetBody(ts.URLTo(router.OIDCUserInfo))
+	r.Equal(http.StatusOK, resp.Code, resp.Body.String())
+	var info struct {
+		Subject string `json:"sub"`
+		Role    string `json:"role"`
+	}
+	r.NoError(json.NewDecoder(resp.Body).Decode(&info))
+	a.Equal(testMember.PubKey.String(), info.Subject)
+	a.Equal("moderator", info.Role)
+
+	// but the id token doesn't
+	userHeader.Set("Authorization", "Bearer "+tokens.IDToken)
+	ts.Client.ClearHeaders()
+	ts.Client.SetHeaders(userHeader)
+	resp = ts.Client.GetBody(ts.URLTo(router.OIDCUserInfo))
+	a.Equal(http.StatusUnauthorized, resp.Code)
+}
+
+func TestOIDCScopesAndDeniedMembers(t *testing.T) {
+	ts := setup(t)
+	a, r := assert.New(t), require.New(t)
+
+	client, err := keys.NewKeyPair(nil)
+	r.NoError(err)
+	testMember := roomdb.Member{
+		ID:      23,
+		Role:    roomdb.RoleModerator,
+		PubKey:  client.Feed,
+		Aliases: []roomdb.Alias{{Name: "alf"}},
+	}
+	ts.MembersDB.GetByIDReturns(testMember, nil)
+	ts.MembersDB.GetByFeedReturns(testMember, nil)
+
+	wiki := roomdb.OIDCClient{ID: 1, ClientID: "wiki-id", Name: "the wiki", RedirectURIs: []string{"https://wiki.example/cb"}}
+	ts.OIDCClientsDB.CheckSecretReturns(wiki, nil)
+
+	verifier := strings.Repeat("v", 50)
+	sum := sha256.Sum256([]byte(verifier))
+
+	// the app only asked for the openid scope
+	ts.OIDCClientsDB.ConsumeCodeReturns(roomdb.OIDCAuthCode{
+		ClientID:      wiki.ID,
+		MemberID:      testMember.ID,
+		RedirectURI:   "https://wiki.example/cb",
+		CodeChallenge: base64.RawURLEncoding.EncodeToString(sum[:]),
+		Scope:         "openid",
+		ExpiresAt:     time.Now().Add(time.Minute),
+	}, nil)
+
+	tokenVals := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {"the-code"},
+		"redirect_uri":  {"https://wiki.example/cb"},
+		"code_verifier": {verifier},
+	}
+	appHeader := make(http.Header)
+	appHeader.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte("wiki-id:the-secret")))
+	ts.Client.SetHeaders(appHeader)
+
+	resp := ts.Client.PostForm(ts.URLTo(router.OIDCToken), tokenVals)
+	r.Equal(http.StatusOK, resp.Code, resp.Body.String())
+
+	var tokens struct {
+		AccessToken string `json:"access_token"`
+		IDToken     string `json:"id_token"`
+	}
+	r.NoError(json.NewDecoder(resp.Body).Decode(&tokens))
+
+	parts := strings.Split(tokens.IDToken, ".")
+	r.Len(parts, 3)
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	r.NoError(err)
+	var claims map[string]interface{}
+	r.NoError(json.Unmarshal(payload, &claims))
+	a.Equal(testMember.PubKey.String(), claims["sub"])
+	a.NotContains(claims, "role")
+	a.NotContains(claims, "aliases")
+	a.NotContains(claims, "preferred_username")
+
+	userHeader := make(http.Header)
+	userHeader.Set("Authorization", "Bearer "+tokens.AccessToken)
+	ts.Client.ClearHeaders()
+	ts.Client.SetHeaders(userHeader)
+
+	resp = ts.Client.GetBody(ts.URLTo(router.OIDCUserInfo))
+	r.Equal(http.StatusOK, resp.Code, resp.Body.String())
+	var info map[string]interface{}
+	r.NoError(json.NewDecoder(resp.Body).Decode(&info))
+	a.Equal(testMember.PubKey.String(), info["sub"])
+	a.NotContains(info, "role")
+	a.NotContains(info, "aliases")
+
+	// once the member is denied, the access token stops working
+	ts.DeniedKeysDB.HasFeedReturns(true)
+
+	resp = ts.Client.GetBody(ts.URLTo(router.OIDCUserInfo))
+	a.Equal(http.StatusUnauthorized, resp.Code)
+
+	// and no new tokens are handed out
+	ts.Client.ClearHeaders()
+	ts.Client.SetHeaders(appHeader)
+	resp = ts.Client.PostForm(ts.URLTo(router.OIDCToken), tokenVals)
+	a.Equal(http.StatusBadRequest, resp.Code)
+	a.Contains(resp.Body.String(), "invalid_grant")
+}
+
+func TestOIDCInternationalAliases(t *testing.T) {
+	ts := setup(t)
+	a, r := assert.New(t), require.New(t)
+
+	client, err := keys.NewKeyPair(nil)
+	r.NoError(err)
+	testMember := roomdb.Member{
+		ID:     23,
+		Role:   roomdb.RoleMember,
+		PubKey: client.Feed,
+		// stored as punycode
+		Aliases: []roomdb.Alias{{Name: "xn--bcher-kva"}, {Name: "alf"}},
+	}
+	ts.MembersDB.GetByIDReturns(testMember, nil)
+	ts.MembersDB.GetByFeedReturns(testMember, nil)
+
+	wiki := roomdb.OIDCClient{ID: 1, ClientID: "wiki-id", Name: "the wiki", RedirectURIs: []string{"https://wiki.example/cb"}}
+	ts.OIDCClientsDB.CheckSecretReturns(wiki, nil)
+
+	verifier := strings.Repeat("v", 50)
+	sum := sha256.Sum256([]byte(verifier))
+	ts.OIDCClientsDB.ConsumeCodeReturns(roomdb.OIDCAuthCode{
+		ClientID:      wiki.ID,
+		MemberID:      testMember.ID,
+		RedirectURI:   "https://wiki.example/cb",
+		CodeChallenge: base64.RawURLEncoding.EncodeToString(sum[:]),
+		Scope:         "openid profile",
+		ExpiresAt:     time.Now().Add(time.Minute),
+	}, nil)
+
+	appHeader := make(http.Header)
+	appHeader.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte("wiki-id:the-secret")))
+	ts.Client.SetHeaders(appHeader)
+
+	resp := ts.Client.PostForm(ts.URLTo(router.OIDCToken), url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {"the-code"},
+		"redirect_uri":  {"https://wiki.example/cb"},
+		"code_verifier": {verifier},
+	})
+	r.Equal(http.StatusOK, resp.Code, resp.Body.String())
+
+	var tokens struct {
+		AccessToken string `json:"access_token"`
+		IDToken     string `json:"id_token"`
+	}
+	r.NoError(json.NewDecoder(resp.Body).Decode(&tokens))
+
+	type aliasClaims struct {
+		Aliases           []string `json:"aliases"`
+		PreferredUsername string   `json:"preferred_username"`
+	}
+
+	parts := strings.Split(tokens.IDToken, ".")
+	r.Len(parts, 3)
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	r.NoError(err)
+	var claims aliasClaims
+	r.NoError(json.Unmarshal(payload, &claims))
+	a.Equal([]string{"bücher", "alf"}, claims.Aliases)
+	a.Equal("bücher", claims.PreferredUsername)
+
+	userHeader := make(http.Header)
+	userHeader.Set("Authorization", "Bearer "+tokens.AccessToken)
+	ts.Client.ClearHeaders()
+	ts.Client.SetHeaders(userHeader)
+
+	resp = ts.Client.GetBody(ts.URLTo(router.OIDCUserInfo))
+	r.Equal(http.StatusOK, resp.Code, resp.Body.String())
+	var info aliasClaims
+	r.NoError(json.NewDecoder(resp.Body).Decode(&info))
+	a.Equal([]string{"bücher", "alf"}, info.Aliases)
+	a.Equal("bücher", info.PreferredUsername)
+}
@@ -6,11 +6,15 @@ package handlers
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	"go.mindeco.de/http/tester"
 	"go.mindeco.de/logging/logtest"
@@ -28,6 +32,9 @@ import (
 	"github.com/ssbc/go-ssb-room/v2/web/router"
 )
 
+// testProxyAddr is the reverse proxy that the requests of the tests come through
+const testProxyAddr = "192.0.2.1"
+
 type testSession struct {
 	Mux    *http.ServeMux
 	Client *tester.Tester
@@ -43,9 +50,16 @@ type testSession struct {
 	ConfigDB       *mockdb.FakeRoomConfig
 	MembersDB      *mockdb.FakeMembersService
 	InvitesDB      *mockdb.FakeInvitesService
+	RequestsDB     *mockdb.FakeInviteRequestsService
 	DeniedKeysDB   *mockdb.FakeDeniedKeysService
 	PinnedDB       *mockdb.FakePinnedNoticesService
 	NoticeDB       *mockdb.FakeNoticesService
+	WebAuthnDB     *mockdb.FakeWebAuthnService
+	TOTPDB         *mockdb.FakeTOTPService
+	LockoutsDB     *mockdb.FakeLoginLockoutService
+	APITokensDB    *mockdb.FakeAPITokensService
+	OIDCClientsDB  *mockdb.FakeOIDCClientsService
+	WebSessionsDB  *mockdb.FakeWebSessionsService
 
 	RoomState *roomstate.Manager
 
@@ -75,6 +89,7 @@ func setup(t *testing.T) *testSession {
 	// default mode for all tests
 	ts.ConfigDB.GetPrivacyModeReturns(roomdb.ModeCommunity, nil)
 	ts.InvitesDB = new(mockdb.FakeInvitesService)
+	ts.RequestsDB = new(mockdb.FakeInviteRequestsService)
 	ts.DeniedKeysDB = new(mockdb.FakeDeniedKeysService)
 	ts.PinnedDB = new(mockdb.FakePinnedNoticesService)
 	defaultNotice := &roomdb.Notice{
@@ -83,6 +98,13 @@ func setup(t *testing.T) *testSession {
 	}
 	ts.PinnedDB.GetReturns(defaultNotice, nil)
 	ts.NoticeDB = new(mockdb.FakeNoticesService)
+	ts.WebAuthnDB = new(mockdb.FakeWebAuthnService)
+	ts.TOTPDB = new(mockdb.FakeTOTPService)
+	ts.LockoutsDB = new(mockdb.FakeLoginLockoutService)
+	ts.APITokensDB = new(mockdb.FakeAPITokensService)
+	ts.OIDCClientsDB = new(mockdb.FakeOIDCClientsService)
+	ts.WebSessionsDB = new(mockdb.FakeWebSessionsService)
+	keepWebSessions(ts.WebSessionsDB)
 
 	ts.MockedEndpoints = new(mocked.FakeEndpoints)
 
@@ -115,6 +137,11 @@ func setup(t *testing.T) *testSession {
 
 	ts.SignalBridge = signinwithssb.NewSignalBridge()
 
+	trustedProxies, err := web.ParseTrustedProxies([]string{testProxyAddr})
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	h, err := New(
 		log,
 		testRepo,
@@ -123,24 +150,117 @@ func setup(t *testing.T) *testSession {
 		ts.MockedEndpoints,
 		ts.SignalBridge,
 		Databases{
-			Aliases:       ts.AliasesDB,
-			AuthFallback:  ts.AuthFallbackDB,
-			AuthWithSSB:   ts.AuthWithSSB,
-			Config:        ts.ConfigDB,
-			Members:       ts.MembersDB,
-			Invites:       ts.InvitesDB,
-			DeniedKeys:    ts.DeniedKeysDB,
-			Notices:       ts.NoticeDB,
-			PinnedNotices: ts.PinnedDB,
+			Aliases:        ts.AliasesDB,
+			APITokens:      ts.APITokensDB,
+			AuthFallback:   ts.AuthFallbackDB,
+			AuthWithSSB:    ts.AuthWithSSB,
+			Config:         ts.ConfigDB,
+			Members:        ts.MembersDB,
+			Invites:        ts.InvitesDB,
+			InviteRequests: ts.RequestsDB,
+			LoginLockouts:  ts.LockoutsDB,
+			DeniedKeys:     ts.DeniedKeysDB,
+			Notices:        ts.NoticeDB,
+			OIDCClients:    ts.OIDCClientsDB,
+			PinnedNotices:  ts.PinnedDB,
+			WebAuthn:       ts.WebAuthnDB,
+			TOTP:           ts.TOTPDB,
+			WebSessions:    ts.WebSessionsDB,
 		},
+		WithTrustedProxies(trustedProxies),
 	)
 	if err != nil {
 		t.Fatal("setup: handler init failed:", err)
 	}
 
 	ts.Mux = http.NewServeMux()
-	ts.Mux.Handle("/", h)
+	ts.Mux.Handle("/", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// the requests of the tester come from a reverse proxy
+		req.RemoteAddr = testProxyAddr + ":4321"
+		h.ServeHTTP(w, req)
+	}))
 	ts.Client = tester.New(ts.Mux, t)
 
 	return &ts
 }
+
+// keepWebSessions backs the fake with a map, so that sign-ins last across requests like with the real database
+func keepWebSessions(fake *mockdb.FakeWebSessionsService) {
+	var (
+		mu       sync.Mutex
+		lastID   int64
+		sessions = make(map[string]roomdb.WebSession)
+	)
+
+	fake.CreateStub = func(_ context.Context, memberID int64, data []byte, expiresAt, idleExpiresAt time.Time) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		lastID++
+		sessions[fmt.Sprint("session-", lastID)] = roomdb.WebSession{
+			ID:            lastID,
+			MemberID:      memberID,
+			Data:          data,
+			CreatedAt:     time.Now(),
+			LastUsedAt:    time.Now(),
+			ExpiresAt:     expiresAt,
+			IdleExpiresAt: idleExpiresAt,
+		}
+		return fmt.Sprint("session-", lastID), nil
+	}
+
+	fake.GetStub = func(_ context.Context, id string) (roomdb.WebSession, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		s, has := sessions[id]
+		if !has || time.Now().After(s.ExpiresAt) || time.Now().After(s.IdleExpiresAt) {
+			return roomdb.WebSession{}, roomdb.ErrNotFound
+		}
+		return s, nil
+	}
+
+	fake.UpdateStub = func(_ context.Context, id string, memberID int64, data []byte, idleExpiresAt time.Time) error {
+		mu.Lock()
+		defer mu.Unlock()
+		s, has := sessions[id]
+		if !has {
+			return roomdb.ErrNotFound
+		}
+		s.MemberID = memberID
+		s.Data = data
+		s.LastUsedAt = time.Now()
+		s.IdleExpiresAt = idleExpiresAt
+		sessions[id] = s
+		return nil
+	}
+
+	fake.TouchStub = func(_ context.Context, id string, idleExpiresAt time.Time) error {
+		mu.Lock()
+		defer mu.Unlock()
+		s, has := sessions[id]
+		if !has {
+			return roomdb.ErrNotFound
+		}
+		s.LastUsedAt = time.Now()
+		s.IdleExpiresAt = idleExpiresAt
+		sessions[id] = s
+		return nil
+	}
+
+	fake.RemoveStub = func(_ context.Context, id string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		delete(sessions, id)
+		return nil
+	}
+
+	fake.RemoveForMemberStub = func(_ context.Context, memberID int64) error {
+		mu.Lock()
+		defer mu.Unlock()
+		for id, s := range sessions {
+			if s.MemberID == memberID {
+				delete(sessions, id)
+			}
+		}
+		return nil
+	}
+}
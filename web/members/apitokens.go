@@ -0,0 +1,236 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package members
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/csrf"
+	"github.com/gorilla/mux"
+	"go.mindeco.de/log/level"
+	"go.mindeco.de/logging"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+	"github.com/ssbc/go-ssb-room/v2/web/router"
+)
+
+// apiTokenScopes maps the routes that can be used with an api token to the scope the token needs for it.
+// Routes that are not listed here can only be used after signing in.
+var apiTokenScopes = map[string]roomdb.APITokenScope{
+	router.AdminInvitesOverview:      roomdb.APIScopeInvitesRead,
+	router.AdminInvitesCreate:        roomdb.APIScopeInvitesCreate,
+	router.AdminInvitesRevokeConfirm: roomdb.APIScopeInvitesRevoke,
+	router.AdminInvitesRevoke:        roomdb.APIScopeInvitesRevoke,
+	router.AdminInvitesTree:          roomdb.APIScopeInvitesRead,
+
+	router.AdminMembersOverview:            roomdb.APIScopeMembersRead,
+	router.AdminMemberDetails:              roomdb.APIScopeMembersRead,
+	router.AdminMembersAdd:                 roomdb.APIScopeMembersWrite,
+	router.AdminMembersChangeRole:          roomdb.APIScopeMembersWrite,
+	router.AdminMembersCreateFallbackReset: roomdb.APIScopeMembersWrite,
+	router.AdminMembersRemoveConfirm:       roomdb.APIScopeMembersWrite,
+	router.AdminMembersRemove:              roomdb.APIScopeMembersWrite,
+	router.AdminMembersRemoveSession:       roomdb.APIScopeMembersWrite,
+
+	router.AdminDeniedKeysOverview:      roomdb.APIScopeDeniedKeysRead,
+	router.AdminDeniedKeysAdd:           roomdb.APIScopeDeniedKeysWrite,
+	router.AdminDeniedKeysRemoveConfirm: roomdb.APIScopeDeniedKeysWrite,
+	router.AdminDeniedKeysRemove:        roomdb.APIScopeDeniedKeysWrite,
+
+	router.AdminAliasesRevokeConfirm: roomdb.APIScopeAliasesWrite,
+	router.AdminAliasesRevoke:        roomdb.APIScopeAliasesWrite,
+
+	router.AdminNoticeEdit:             roomdb.APIScopeNoticesWrite,
+	router.AdminNoticeSave:             roomdb.APIScopeNoticesWrite,
+	router.AdminNoticeDraftTranslation: roomdb.APIScopeNoticesWrite,
+	router.AdminNoticeAddTranslation:   roomdb.APIScopeNoticesWrite,
+
+	router.AdminSettings:                 roomdb.APIScopeSettingsRead,
+	router.AdminSettingsSetPrivacy:       roomdb.APIScopeSettingsWrite,
+	router.AdminSettingsSetLanguage:      roomdb.APIScopeSettingsWrite,
+	router.AdminSettingsSetTOTPMandatory: roomdb.APIScopeSettingsWrite,
+
+	// the json api, an empty scope means that any token can use it
+	router.APIOpenAPI: "",
+	router.APIStats:   roomdb.APIScopeStatsRead,
+
+	router.APIMembersList:          roomdb.APIScopeMembersRead,
+	router.APIMembersGet:           roomdb.APIScopeMembersRead,
+	router.APIMembersAdd:           roomdb.APIScopeMembersWrite,
+	router.APIMembersSetRole:       roomdb.APIScopeMembersWrite,
+	router.APIMembersRemove:        roomdb.APIScopeMembersWrite,
+	router.APIMembersPasswordReset: roomdb.APIScopeMembersWrite,
+
+	router.APIInvitesList:   roomdb.APIScopeInvitesRead,
+	router.APIInvitesGet:    roomdb.APIScopeInvitesRead,
+	router.APIInvitesCreate: roomdb.APIScopeInvitesCreate,
+	router.APIInvitesRevoke: roomdb.APIScopeInvitesRevoke,
+
+	router.APIDeniedKeysList:   roomdb.APIScopeDeniedKeysRead,
+	router.APIDeniedKeysGet:    roomdb.APIScopeDeniedKeysRead,
+	router.APIDeniedKeysAdd:    roomdb.APIScopeDeniedKeysWrite,
+	router.APIDeniedKeysRemove: roomdb.APIScopeDeniedKeysWrite,
+
+	router.APIAliasesList:   roomdb.APIScopeAliasesRead,
+	router.APIAliasesRevoke: roomdb.APIScopeAliasesWrite,
+
+	router.APINoticesList:           roomdb.APIScopeNoticesRead,
+	router.APINoticesGet:            roomdb.APIScopeNoticesRead,
+	router.APINoticesSave:           roomdb.APIScopeNoticesWrite,
+	router.APINoticesAddTranslation: roomdb.APIScopeNoticesWrite,
+
+	router.APISettingsGet:    roomdb.APIScopeSettingsRead,
+	router.APISettingsUpdate: roomdb.APIScopeSettingsWrite,
+}
+
+// deviceSessionScopes are what the sessions of the device sign-in can do with the json api.
+// They can only read, changing anything needs an api token with the right scopes.
+var deviceSessionScopes = []roomdb.APITokenScope{
+	roomdb.APIScopeInvitesRead,
+	roomdb.APIScopeMembersRead,
+	roomdb.APIScopeDeniedKeysRead,
+	roomdb.APIScopeAliasesRead,
+	roomdb.APIScopeNoticesRead,
+	roomdb.APIScopeSettingsRead,
+	roomdb.APIScopeStatsRead,
+}
+
+// RequiredAPITokenScope returns the scope a token needs to use the named route.
+// The second return value is false if the route can't be used with a token at all.
+func RequiredAPITokenScope(routeName string) (roomdb.APITokenScope, bool) {
+	scope, has := apiTokenScopes[routeName]
+	return scope, has
+}
+
+// APITokenAuthenticator returns middleware that lets requests with an "Authorization: Bearer <token>" header act as the member that created the token.
+// The token needs the scope of the requested route and the member still needs to be an admin or moderator whose key isn't denied.
+// The sessions devices get from the device sign-in are also accepted as bearer tokens, of all members, but only for the json api and with the read-only deviceSessionScopes.
+// Sessions of browser sign-ins are not, those only work with their cookie.
+// Only these requests skip the CSRF check, since they can't come from a browser session.
+// Requests without the header, and those to the OpenID Connect endpoints, are passed on unchanged.
+func APITokenAuthenticator(tokens roomdb.APITokensService, sessions roomdb.AuthWithSSBService, mdb roomdb.MembersService, deniedKeys roomdb.DeniedKeysService) Middleware {
+	routes := router.CompleteApp()
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			authz := req.Header.Get("Authorization")
+			if authz == "" || usesOwnAuthorization(routes, req) {
+				next.ServeHTTP(w, req)
+				return
+			}
+
+			logger := logging.FromContext(req.Context())
+
+			const bearerPrefix = "Bearer "
+			if !strings.HasPrefix(authz, bearerPrefix) {
+				sendAPITokenError(w, http.StatusUnauthorized, fmt.Errorf("only bearer tokens are supported"))
+				return
+			}
+			bearer := strings.TrimSpace(strings.TrimPrefix(authz, bearerPrefix))
+
+			var match mux.RouteMatch
+			matched := routes.Match(req, &match) && match.Route != nil
+			jsonAPI := matched && strings.HasPrefix(match.Route.GetName(), "api:")
+
+			var (
+				memberID int64
+				session  bool
+			)
+			token, err := tokens.CheckToken(req.Context(), bearer)
+			if err == nil {
+				memberID = token.MemberID
+			} else if errors.Is(err, roomdb.ErrNotFound) && jsonAPI {
+				// maybe it's the session of a device
+				memberID, err = sessions.CheckDeviceToken(req.Context(), bearer)
+				token = roomdb.APIToken{MemberID: memberID, Scopes: deviceSessionScopes}
+				session = true
+			}
+			if err != nil {
+				if !errors.Is(err, roomdb.ErrNotFound) {
+					level.Warn(logger).Log("event", "api token check failed", "err", err)
+					sendAPITokenError(w, http.StatusInternalServerError, fmt.Errorf("internal error"))
+					return
+				}
+				sendAPITokenError(w, http.StatusUnauthorized, fmt.Errorf("unknown or expired token"))
+				return
+			}
+
+			member, err := mdb.GetByID(req.Context(), memberID)
+			if err != nil {
+				sendAPITokenError(w, http.StatusUnauthorized, fmt.Errorf("unknown or expired token"))
+				return
+			}
+
+			// the member might have been demoted since the token was created.
+			// Device sessions stand in for the browser session of any member, the json api checks their role like it does for cookies.
+			if !session && member.Role != roomdb.RoleAdmin && member.Role != roomdb.RoleModerator {
+				sendAPITokenError(w, http.StatusForbidden, fmt.Errorf("tokens can only be used by admins and moderators"))
+				return
+			}
+
+			// or suspended
+			if deniedKeys.HasFeed(req.Context(), member.PubKey) {
+				sendAPITokenError(w, http.StatusForbidden, fmt.Errorf("the key of the member is denied"))
+				return
+			}
+
+			if !matched {
+				sendAPITokenError(w, http.StatusNotFound, fmt.Errorf("no such endpoint"))
+				return
+			}
+
+			scope, has := RequiredAPITokenScope(match.Route.GetName())
+			if !has {
+				sendAPITokenError(w, http.StatusForbidden, fmt.Errorf("this endpoint can't be used with a token"))
+				return
+			}
+
+			if scope != "" && !token.HasScope(scope) {
+				sendAPITokenError(w, http.StatusForbidden, fmt.Errorf("token is missing the %s scope", scope))
+				return
+			}
+
+			if session {
+				level.Debug(logger).Log("event", "device session used", "member", member.ID, "scope", scope)
+			} else {
+				level.Debug(logger).Log("event", "api token used", "token", token.ID, "member", member.ID, "scope", scope)
+			}
+
+			ctx := context.WithValue(req.Context(), roomMemberContextKey, &member)
+			next.ServeHTTP(w, csrf.UnsafeSkipCheck(req.WithContext(ctx)))
+		})
+	}
+}
+
+// usesOwnAuthorization is true for the OpenID Connect endpoints, which get the credentials of the apps in the Authorization header
+func usesOwnAuthorization(routes *mux.Router, req *http.Request) bool {
+	var match mux.RouteMatch
+	if !routes.Match(req, &match) || match.Route == nil {
+		return false
+	}
+
+	switch match.Route.GetName() {
+	case router.OIDCToken, router.OIDCUserInfo:
+		return true
+	}
+	return false
+}
+
+func sendAPITokenError(w http.ResponseWriter, code int, err error) {
+	if code == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="room"`)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+	}{"error", err.Error()})
+}
@@ -10,7 +10,6 @@ import (
 	"fmt"
 	"net/http"
 
-	"go.mindeco.de/http/auth"
 	"go.mindeco.de/http/render"
 
 	"github.com/ssbc/go-ssb-room/v2/roomdb"
@@ -52,38 +51,45 @@ func FromContext(ctx context.Context) *roomdb.Member {
 
 // ContextInjecter returns middleware for injecting a member into the context of the request.
 // Retreive it using FromContext(ctx)
-func ContextInjecter(mdb roomdb.MembersService, withPassword *auth.Handler, withSSB *authWithSSB.WithSSBHandler) Middleware {
+// Members whose key is on the denied list are treated as signed out, whichever way they signed in.
+func ContextInjecter(deniedKeys roomdb.DeniedKeysService, withSSB *authWithSSB.WithSSBHandler, withWebAuthn *authWithSSB.WithWebAuthnHandler, withTOTP *authWithSSB.WithTOTPHandler) Middleware {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			// already authenticated by an api token
+			if FromContext(req.Context()) != nil {
+				next.ServeHTTP(w, req)
+				return
+			}
+
 			var (
 				member *roomdb.Member
 
-				errWithPassword, errWithSSB error
+				errWithSSB, errWithWebAuthn, errWithTOTP error
 			)
 
-			v, errWithPassword := withPassword.AuthenticateRequest(req)
-			if errWithPassword == nil {
-				mid, ok := v.(int64)
-				if !ok {
-					next.ServeHTTP(w, req)
-					return
-				}
+			m, errWithSSB := withSSB.AuthenticateRequest(req)
+			if errWithSSB == nil {
+				member = m
+			}
 
-				m, err := mdb.GetByID(req.Context(), mid)
-				if err != nil {
-					next.ServeHTTP(w, req)
-					return
-				}
-				member = &m
+			m, errWithWebAuthn = withWebAuthn.AuthenticateRequest(req)
+			if errWithWebAuthn == nil {
+				member = m
 			}
 
-			m, errWithSSB := withSSB.AuthenticateRequest(req)
-			if errWithSSB == nil {
+			m, errWithTOTP = withTOTP.AuthenticateRequest(req)
+			if errWithTOTP == nil {
 				member = m
 			}
 
-			// if both methods failed, don't update the context
-			if errWithPassword != nil && errWithSSB != nil {
+			// if all methods failed, don't update the context
+			if errWithSSB != nil && errWithWebAuthn != nil && errWithTOTP != nil {
+				next.ServeHTTP(w, req)
+				return
+			}
+
+			// for instance suspended through the invite tree
+			if member != nil && deniedKeys.HasFeed(req.Context(), member.PubKey) {
 				next.ServeHTTP(w, req)
 				return
 			}
@@ -206,6 +212,10 @@ const (
 	ActionChangeDeniedKeys = "change-denied-keys"
 	ActionRemoveMember     = "remove-member"
 	ActionChangeNotice     = "change-notice"
+	ActionClearLockouts    = "clear-lockouts"
+	ActionManageOIDCApps   = "manage-oidc-apps"
+	ActionReviewInvites    = "review-invites"
+	ActionInviteBatches    = "invite-batches"
 )
 
 var allowedActionsMap = map[string]AllowedFunc{
@@ -251,6 +261,25 @@ var allowedActionsMap = map[string]AllowedFunc{
 			return false
 		}
 	},
+
+	// the lockouts show the IP addresses of those who tried to sign in, so only admins see them
+	ActionClearLockouts: func(_ roomdb.PrivacyMode, role roomdb.Role) bool {
+		return role == roomdb.RoleAdmin
+	},
+
+	ActionManageOIDCApps: func(_ roomdb.PrivacyMode, role roomdb.Role) bool {
+		return role == roomdb.RoleAdmin
+	},
+
+	// the invite tree can be used to suspend many members at once, and requests for invites let outsiders in
+	ActionReviewInvites: func(_ roomdb.PrivacyMode, role roomdb.Role) bool {
+		return role == roomdb.RoleAdmin || role == roomdb.RoleModerator
+	},
+
+	// a batch can bring a whole workshop into the room at once
+	ActionInviteBatches: func(_ roomdb.PrivacyMode, role roomdb.Role) bool {
+		return role == roomdb.RoleAdmin
+	},
 }
 
 // CheckAllowed retreives the member from the passed context and lookups the current privacy mode from the passed cfg to determain if the action is okay or not.
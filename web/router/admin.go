@@ -11,18 +11,28 @@ const (
 	AdminDashboard = "admin:dashboard"
 	AdminMenu      = "admin:menu"
 
-	AdminSettings            = "admin:settings:overview"
-	AdminSettingsSetPrivacy  = "admin:settings:set-privacy"
-	AdminSettingsSetLanguage = "admin:settings:set-language"
-
-	AdminAliasesRevokeConfirm = "admin:aliases:revoke:confirm"
-	AdminAliasesRevoke        = "admin:aliases:revoke"
+	AdminSettings                 = "admin:settings:overview"
+	AdminSettingsSetPrivacy       = "admin:settings:set-privacy"
+	AdminSettingsSetLanguage      = "admin:settings:set-language"
+	AdminSettingsSetTOTPMandatory = "admin:settings:set-totp-mandatory"
+	AdminSettingsRotateSecrets    = "admin:settings:rotate-secrets"
+	AdminSettingsSetOpenInvites   = "admin:settings:set-open-invites"
+	AdminSettingsSetMemberInvites = "admin:settings:set-member-invites"
+	AdminSettingsSetAliasPolicy   = "admin:settings:set-alias-policy"
+
+	AdminAliasesRevokeConfirm  = "admin:aliases:revoke:confirm"
+	AdminAliasesRevoke         = "admin:aliases:revoke"
+	AdminAliasesTransfer       = "admin:aliases:transfer"
+	AdminAliasesTransferCancel = "admin:aliases:transfer:cancel"
 
 	AdminDeniedKeysOverview      = "admin:denied-keys:overview"
 	AdminDeniedKeysAdd           = "admin:denied-keys:add"
 	AdminDeniedKeysRemoveConfirm = "admin:denied-keys:remove:confirm"
 	AdminDeniedKeysRemove        = "admin:denied-keys:remove"
 
+	AdminLockoutsOverview = "admin:lockouts:overview"
+	AdminLockoutsClear    = "admin:lockouts:clear"
+
 	AdminMemberDetails = "admin:member:details"
 
 	AdminMembersOverview            = "admin:members:overview"
@@ -31,16 +41,35 @@ const (
 	AdminMembersCreateFallbackReset = "admin:members:create-password-reset-link"
 	AdminMembersRemoveConfirm       = "admin:members:remove:confirm"
 	AdminMembersRemove              = "admin:members:remove"
+	AdminMembersRemoveSession       = "admin:members:remove-session"
 
 	AdminInvitesOverview      = "admin:invites:overview"
 	AdminInvitesRevokeConfirm = "admin:invites:revoke:confirm"
 	AdminInvitesRevoke        = "admin:invites:revoke"
 	AdminInvitesCreate        = "admin:invites:create"
 
+	AdminInvitesTree               = "admin:invites:tree"
+	AdminInvitesTreeSuspendConfirm = "admin:invites:tree:suspend:confirm"
+	AdminInvitesTreeSuspend        = "admin:invites:tree:suspend"
+
+	AdminInviteRequests        = "admin:invites:requests"
+	AdminInviteRequestsApprove = "admin:invites:requests:approve"
+	AdminInviteRequestsReject  = "admin:invites:requests:reject"
+
+	AdminInviteBatches              = "admin:invites:batches"
+	AdminInviteBatchesCreate        = "admin:invites:batches:create"
+	AdminInviteBatchesRevokeConfirm = "admin:invites:batches:revoke:confirm"
+	AdminInviteBatchesRevoke        = "admin:invites:batches:revoke"
+
 	AdminNoticeEdit             = "admin:notice:edit"
 	AdminNoticeSave             = "admin:notice:save"
 	AdminNoticeDraftTranslation = "admin:notice:translation:draft"
 	AdminNoticeAddTranslation   = "admin:notice:translation:add"
+
+	AdminOIDCClientsOverview      = "admin:oidc-clients:overview"
+	AdminOIDCClientsAdd           = "admin:oidc-clients:add"
+	AdminOIDCClientsRemoveConfirm = "admin:oidc-clients:remove:confirm"
+	AdminOIDCClientsRemove        = "admin:oidc-clients:remove"
 )
 
 // Admin constructs a mux.Router containing the routes for the admin dashboard and settings pages
@@ -54,17 +83,27 @@ func Admin(m *mux.Router) *mux.Router {
 	m.Path("/settings").Methods("GET").Name(AdminSettings)
 	m.Path("/settings/set-privacy").Methods("POST").Name(AdminSettingsSetPrivacy)
 	m.Path("/settings/set-language").Methods("POST").Name(AdminSettingsSetLanguage)
+	m.Path("/settings/set-totp-mandatory").Methods("POST").Name(AdminSettingsSetTOTPMandatory)
+	m.Path("/settings/rotate-secrets").Methods("POST").Name(AdminSettingsRotateSecrets)
+	m.Path("/settings/set-open-invites").Methods("POST").Name(AdminSettingsSetOpenInvites)
+	m.Path("/settings/set-member-invites").Methods("POST").Name(AdminSettingsSetMemberInvites)
+	m.Path("/settings/set-alias-policy").Methods("POST").Name(AdminSettingsSetAliasPolicy)
 
 	m.Path("/menu").Methods("GET").Name(AdminMenu)
 
 	m.Path("/aliases/revoke/confirm").Methods("GET").Name(AdminAliasesRevokeConfirm)
 	m.Path("/aliases/revoke").Methods("POST").Name(AdminAliasesRevoke)
+	m.Path("/aliases/transfer").Methods("POST").Name(AdminAliasesTransfer)
+	m.Path("/aliases/transfer/cancel").Methods("POST").Name(AdminAliasesTransferCancel)
 
 	m.Path("/denied").Methods("GET").Name(AdminDeniedKeysOverview)
 	m.Path("/denied/add").Methods("POST").Name(AdminDeniedKeysAdd)
 	m.Path("/denied/remove/confirm").Methods("GET").Name(AdminDeniedKeysRemoveConfirm)
 	m.Path("/denied/remove").Methods("POST").Name(AdminDeniedKeysRemove)
 
+	m.Path("/lockouts").Methods("GET").Name(AdminLockoutsOverview)
+	m.Path("/lockouts/clear").Methods("POST").Name(AdminLockoutsClear)
+
 	m.Path("/member").Methods("GET").Name(AdminMemberDetails)
 
 	m.Path("/members").Methods("GET").Name(AdminMembersOverview)
@@ -73,6 +112,7 @@ func Admin(m *mux.Router) *mux.Router {
 	m.Path("/members/create-fallback-reset-link").Methods("POST").Name(AdminMembersCreateFallbackReset)
 	m.Path("/members/remove/confirm").Methods("GET").Name(AdminMembersRemoveConfirm)
 	m.Path("/members/remove").Methods("POST").Name(AdminMembersRemove)
+	m.Path("/members/remove-session").Methods("POST").Name(AdminMembersRemoveSession)
 
 	m.Path("/notice/edit").Methods("GET").Name(AdminNoticeEdit)
 	m.Path("/notice/translation/draft").Methods("GET").Name(AdminNoticeDraftTranslation)
@@ -83,6 +123,21 @@ func Admin(m *mux.Router) *mux.Router {
 	m.Path("/invites/revoke/confirm").Methods("GET").Name(AdminInvitesRevokeConfirm)
 	m.Path("/invites/revoke").Methods("POST").Name(AdminInvitesRevoke)
 	m.Path("/invites/create").Methods("POST").Name(AdminInvitesCreate)
+	m.Path("/invites/tree").Methods("GET").Name(AdminInvitesTree)
+	m.Path("/invites/tree/suspend/confirm").Methods("GET").Name(AdminInvitesTreeSuspendConfirm)
+	m.Path("/invites/tree/suspend").Methods("POST").Name(AdminInvitesTreeSuspend)
+	m.Path("/invites/requests").Methods("GET").Name(AdminInviteRequests)
+	m.Path("/invites/requests/approve").Methods("POST").Name(AdminInviteRequestsApprove)
+	m.Path("/invites/requests/reject").Methods("POST").Name(AdminInviteRequestsReject)
+	m.Path("/invites/batches").Methods("GET").Name(AdminInviteBatches)
+	m.Path("/invites/batches/create").Methods("POST").Name(AdminInviteBatchesCreate)
+	m.Path("/invites/batches/revoke/confirm").Methods("GET").Name(AdminInviteBatchesRevokeConfirm)
+	m.Path("/invites/batches/revoke").Methods("POST").Name(AdminInviteBatchesRevoke)
+
+	m.Path("/oidc-clients").Methods("GET").Name(AdminOIDCClientsOverview)
+	m.Path("/oidc-clients/add").Methods("POST").Name(AdminOIDCClientsAdd)
+	m.Path("/oidc-clients/remove/confirm").Methods("GET").Name(AdminOIDCClientsRemoveConfirm)
+	m.Path("/oidc-clients/remove").Methods("POST").Name(AdminOIDCClientsRemove)
 
 	return m
 }
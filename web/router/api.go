@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package router
+
+import "github.com/gorilla/mux"
+
+// constant names for the named routes
+const (
+	APIOpenAPI = "api:v1:openapi"
+	APIStats   = "api:v1:stats"
+
+	APIMembersList          = "api:v1:members:list"
+	APIMembersAdd           = "api:v1:members:add"
+	APIMembersGet           = "api:v1:members:get"
+	APIMembersSetRole       = "api:v1:members:set-role"
+	APIMembersRemove        = "api:v1:members:remove"
+	APIMembersPasswordReset = "api:v1:members:password-reset"
+
+	APIInvitesList   = "api:v1:invites:list"
+	APIInvitesCreate = "api:v1:invites:create"
+	APIInvitesGet    = "api:v1:invites:get"
+	APIInvitesRevoke = "api:v1:invites:revoke"
+
+	APIDeniedKeysList   = "api:v1:denied-keys:list"
+	APIDeniedKeysAdd    = "api:v1:denied-keys:add"
+	APIDeniedKeysGet    = "api:v1:denied-keys:get"
+	APIDeniedKeysRemove = "api:v1:denied-keys:remove"
+
+	APIAliasesList   = "api:v1:aliases:list"
+	APIAliasesRevoke = "api:v1:aliases:revoke"
+
+	APINoticesList           = "api:v1:notices:list"
+	APINoticesGet            = "api:v1:notices:get"
+	APINoticesSave           = "api:v1:notices:save"
+	APINoticesAddTranslation = "api:v1:notices:add-translation"
+
+	APISettingsGet    = "api:v1:settings:get"
+	APISettingsUpdate = "api:v1:settings:update"
+)
+
+// API constructs a mux.Router containing the routes of the versioned JSON API
+func API(m *mux.Router) *mux.Router {
+	if m == nil {
+		m = mux.NewRouter()
+	}
+
+	m.Path("/openapi.json").Methods("GET").Name(APIOpenAPI)
+	m.Path("/stats").Methods("GET").Name(APIStats)
+
+	m.Path("/members").Methods("GET").Name(APIMembersList)
+	m.Path("/members").Methods("POST").Name(APIMembersAdd)
+	m.Path("/members/{id:[0-9]+}").Methods("GET").Name(APIMembersGet)
+	m.Path("/members/{id:[0-9]+}/role").Methods("PUT").Name(APIMembersSetRole)
+	m.Path("/members/{id:[0-9]+}").Methods("DELETE").Name(APIMembersRemove)
+	m.Path("/members/{id:[0-9]+}/password-reset").Methods("POST").Name(APIMembersPasswordReset)
+
+	m.Path("/invites").Methods("GET").Name(APIInvitesList)
+	m.Path("/invites").Methods("POST").Name(APIInvitesCreate)
+	m.Path("/invites/{id:[0-9]+}").Methods("GET").Name(APIInvitesGet)
+	m.Path("/invites/{id:[0-9]+}").Methods("DELETE").Name(APIInvitesRevoke)
+
+	m.Path("/denied-keys").Methods("GET").Name(APIDeniedKeysList)
+	m.Path("/denied-keys").Methods("POST").Name(APIDeniedKeysAdd)
+	m.Path("/denied-keys/{id:[0-9]+}").Methods("GET").Name(APIDeniedKeysGet)
+	m.Path("/denied-keys/{id:[0-9]+}").Methods("DELETE").Name(APIDeniedKeysRemove)
+
+	m.Path("/aliases").Methods("GET").Name(APIAliasesList)
+	m.Path("/aliases/{name}").Methods("DELETE").Name(APIAliasesRevoke)
+
+	m.Path("/notices").Methods("GET").Name(APINoticesList)
+	m.Path("/notices/{id:[0-9]+}").Methods("GET").Name(APINoticesGet)
+	m.Path("/notices/{id:[0-9]+}").Methods("PUT").Name(APINoticesSave)
+	m.Path("/notices/pinned/{name}").Methods("POST").Name(APINoticesAddTranslation)
+
+	m.Path("/settings").Methods("GET").Name(APISettingsGet)
+	m.Path("/settings").Methods("PATCH").Name(APISettingsUpdate)
+
+	return m
+}
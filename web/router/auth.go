@@ -14,9 +14,21 @@ const (
 	AuthFallbackLogin    = "auth:fallback:login"
 	AuthFallbackFinalize = "auth:fallback:finalize"
 
+	AuthFallbackTOTP         = "auth:fallback:totp"
+	AuthFallbackTOTPFinalize = "auth:fallback:totp:finalize"
+
 	AuthWithSSBLogin        = "auth:withssb:login"
 	AuthWithSSBServerEvents = "auth:withssb:sse"
 	AuthWithSSBFinalize     = "auth:withssb:finalize"
+
+	AuthWebAuthnLogin    = "auth:webauthn:login"
+	AuthWebAuthnBegin    = "auth:webauthn:begin"
+	AuthWebAuthnFinalize = "auth:webauthn:finalize"
+
+	AuthDeviceCode    = "auth:device:code"
+	AuthDeviceToken   = "auth:device:token"
+	AuthDeviceVerify  = "auth:device:verify"
+	AuthDeviceConfirm = "auth:device:confirm"
 )
 
 // Auth constructs a mux.Router containing the routes for sign-in and -out
@@ -30,10 +42,22 @@ func Auth(m *mux.Router) *mux.Router {
 
 	m.Path("/fallback/login").Methods("GET").Name(AuthFallbackLogin)
 	m.Path("/fallback/finalize").Methods("POST").Name(AuthFallbackFinalize)
+	m.Path("/fallback/totp").Methods("GET").Name(AuthFallbackTOTP)
+	m.Path("/fallback/totp/finalize").Methods("POST").Name(AuthFallbackTOTPFinalize)
 
 	m.Path("/withssb/login").Methods("GET").Name(AuthWithSSBLogin)
 	m.Path("/withssb/events").Methods("GET").Name(AuthWithSSBServerEvents)
 	m.Path("/withssb/finalize").Methods("GET").Name(AuthWithSSBFinalize)
 
+	m.Path("/webauthn/login").Methods("GET").Name(AuthWebAuthnLogin)
+	m.Path("/webauthn/begin").Methods("POST").Name(AuthWebAuthnBegin)
+	m.Path("/webauthn/finalize").Methods("POST").Name(AuthWebAuthnFinalize)
+
+	// the device asks for a code and polls for the token, the member confirms the code in the browser
+	m.Path("/device/code").Methods("POST").Name(AuthDeviceCode)
+	m.Path("/device/token").Methods("POST").Name(AuthDeviceToken)
+	m.Path("/device").Methods("GET").Name(AuthDeviceVerify)
+	m.Path("/device/confirm").Methods("POST").Name(AuthDeviceConfirm)
+
 	return m
 }
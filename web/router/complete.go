@@ -24,8 +24,29 @@ const (
 	CompleteInviteInsertID       = "complete:invite:insert-id"
 	CompleteInviteConsume        = "complete:invite:consume"
 
+	CompleteInviteRequestForm   = "complete:invite-request:form"
+	CompleteInviteRequestCreate = "complete:invite-request:create"
+	CompleteInviteRequestStatus = "complete:invite-request:status"
+
 	MembersChangePasswordForm = "members:change-password:form"
 	MembersChangePassword     = "members:change-password"
+	MembersSessions           = "members:sessions"
+	MembersSessionsRevoke     = "members:sessions:revoke"
+	MembersSessionsRevokeAll  = "members:sessions:revoke-all"
+	MembersPasskeys           = "members:passkeys"
+	MembersPasskeysBegin      = "members:passkeys:begin"
+	MembersPasskeysFinish     = "members:passkeys:finish"
+	MembersPasskeysRemove     = "members:passkeys:remove"
+	MembersTOTP               = "members:totp"
+	MembersTOTPEnroll         = "members:totp:enroll"
+	MembersTOTPConfirm        = "members:totp:confirm"
+	MembersTOTPDisable        = "members:totp:disable"
+	MembersAPITokens          = "members:api-tokens"
+	MembersAPITokensCreate    = "members:api-tokens:create"
+	MembersAPITokensRevoke    = "members:api-tokens:revoke"
+	MembersInvites            = "members:invites"
+	MembersInvitesCreate      = "members:invites:create"
+	MembersInvitesRevoke      = "members:invites:revoke"
 
 	OpenModeCreateInvite = "open:invites:create"
 )
@@ -35,7 +56,9 @@ func CompleteApp() *mux.Router {
 	m := mux.NewRouter()
 
 	Auth(m)
+	OIDC(m)
 	Admin(m.PathPrefix("/admin").Subrouter())
+	API(m.PathPrefix("/api/v1").Subrouter())
 
 	m.Path("/").Methods("GET").Name(CompleteIndex)
 
@@ -43,6 +66,23 @@ func CompleteApp() *mux.Router {
 
 	m.Path("/members/change-password").Methods("GET").Name(MembersChangePasswordForm)
 	m.Path("/members/change-password").Methods("POST").Name(MembersChangePassword)
+	m.Path("/members/sessions").Methods("GET").Name(MembersSessions)
+	m.Path("/members/sessions/revoke").Methods("POST").Name(MembersSessionsRevoke)
+	m.Path("/members/sessions/revoke-all").Methods("POST").Name(MembersSessionsRevokeAll)
+	m.Path("/members/passkeys").Methods("GET").Name(MembersPasskeys)
+	m.Path("/members/passkeys/begin").Methods("POST").Name(MembersPasskeysBegin)
+	m.Path("/members/passkeys/finish").Methods("POST").Name(MembersPasskeysFinish)
+	m.Path("/members/passkeys/remove").Methods("POST").Name(MembersPasskeysRemove)
+	m.Path("/members/two-factor").Methods("GET").Name(MembersTOTP)
+	m.Path("/members/two-factor/enroll").Methods("POST").Name(MembersTOTPEnroll)
+	m.Path("/members/two-factor/confirm").Methods("POST").Name(MembersTOTPConfirm)
+	m.Path("/members/two-factor/disable").Methods("POST").Name(MembersTOTPDisable)
+	m.Path("/members/api-tokens").Methods("GET").Name(MembersAPITokens)
+	m.Path("/members/api-tokens/create").Methods("POST").Name(MembersAPITokensCreate)
+	m.Path("/members/api-tokens/revoke").Methods("POST").Name(MembersAPITokensRevoke)
+	m.Path("/members/invites").Methods("GET").Name(MembersInvites)
+	m.Path("/members/invites/create").Methods("POST").Name(MembersInvitesCreate)
+	m.Path("/members/invites/revoke").Methods("POST").Name(MembersInvitesRevoke)
 
 	m.Path("/create-invite").Methods("GET", "POST").Name(OpenModeCreateInvite)
 	m.Path("/join").Methods("GET").Name(CompleteInviteFacade)
@@ -50,6 +90,10 @@ func CompleteApp() *mux.Router {
 	m.Path("/join-manually").Methods("GET").Name(CompleteInviteInsertID)
 	m.Path("/invite/consume").Methods("POST").Name(CompleteInviteConsume)
 
+	m.Path("/request-invite").Methods("GET").Name(CompleteInviteRequestForm)
+	m.Path("/request-invite").Methods("POST").Name(CompleteInviteRequestCreate)
+	m.Path("/request-invite/status").Methods("GET").Name(CompleteInviteRequestStatus)
+
 	m.Path("/notice/show").Methods("GET").Name(CompleteNoticeShow)
 	m.Path("/notice/list").Methods("GET").Name(CompleteNoticeList)
 
@@ -0,0 +1,35 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package router
+
+import "github.com/gorilla/mux"
+
+// constant names for the named routes
+const (
+	OIDCDiscovery        = "oidc:discovery"
+	OIDCKeys             = "oidc:keys"
+	OIDCAuthorize        = "oidc:authorize"
+	OIDCAuthorizeConfirm = "oidc:authorize:confirm"
+	OIDCToken            = "oidc:token"
+	OIDCUserInfo         = "oidc:userinfo"
+)
+
+// OIDC constructs a mux.Router containing the routes of the OpenID Connect provider.
+// The discovery document needs to be at the root of the domain, so it expects the main router.
+func OIDC(m *mux.Router) *mux.Router {
+	if m == nil {
+		m = mux.NewRouter()
+	}
+
+	m.Path("/.well-known/openid-configuration").Methods("GET").Name(OIDCDiscovery)
+
+	m.Path("/oidc/keys").Methods("GET").Name(OIDCKeys)
+	m.Path("/oidc/authorize").Methods("GET").Name(OIDCAuthorize)
+	m.Path("/oidc/authorize").Methods("POST").Name(OIDCAuthorizeConfirm)
+	m.Path("/oidc/token").Methods("POST").Name(OIDCToken)
+	m.Path("/oidc/userinfo").Methods("GET", "POST").Name(OIDCUserInfo)
+
+	return m
+}
@@ -0,0 +1,497 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package web
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gorilla/csrf"
+	"github.com/gorilla/securecookie"
+	"go.mindeco.de/log/level"
+	"go.mindeco.de/logging"
+
+	"github.com/ssbc/go-ssb-room/v2/internal/repo"
+)
+
+const (
+	// DefaultSecretsGracePeriod is how long the previous keys are still accepted after a rotation.
+	// It's longer than the default lifetime of sign-in sessions, so that nobody is signed out by a rotation.
+	DefaultSecretsGracePeriod = 7 * 24 * time.Hour
+
+	// how often the files are checked for rotations by other processes, like the rotate-secrets command
+	secretsCheckInterval = 10 * time.Second
+
+	// a hash and a block key for securecookie, 32 bytes each
+	cookieKeyPairLength = 64
+
+	csrfKeyLength = 32
+
+	// the name of the cookie of gorilla/csrf, we don't change it with csrf.CookieName
+	csrfCookieName = "_gorilla_csrf"
+)
+
+// the files in $repo/web, the keys are stored newest first
+const (
+	cookieSecretFile  = "cookie-secret"
+	csrfSecretFile    = "csrf-secret"
+	secretsExpiryFile = "secrets-expiry.json"
+)
+
+// Secrets holds the keys that sign and encrypt the cookies and the CSRF tokens of the room.
+// New cookies use the newest keys. Older keys are still accepted until the grace period of their rotation ends, then they are dropped.
+//
+// It implements securecookie.Codec, so that it can be used with the gorilla session stores.
+// Rotations by other processes are picked up without a restart.
+type Secrets struct {
+	dir string
+
+	mu         sync.Mutex
+	checkedAt  time.Time
+	filesState string
+	nextExpiry time.Time
+
+	codecs     []securecookie.Codec
+	csrfKeys   [][]byte
+	previous   int
+	previousTo time.Time
+}
+
+var _ securecookie.Codec = (*Secrets)(nil)
+
+var (
+	openSecretsMu sync.Mutex
+	openSecrets   = make(map[string]*Secrets)
+)
+
+// LoadOrCreateSecrets loads the keys from $repo/web or creates new ones if there are none yet.
+// All callers for the same repo share them, so that a rotation is seen by all the cookie stores at once.
+func LoadOrCreateSecrets(r repo.Interface) (*Secrets, error) {
+	dir := r.GetPath("web")
+
+	openSecretsMu.Lock()
+	defer openSecretsMu.Unlock()
+
+	s, has := openSecrets[dir]
+	if !has {
+		s = &Secrets{dir: dir}
+	}
+
+	s.mu.Lock()
+	err := s.check(time.Now(), true)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	openSecrets[dir] = s
+	return s, nil
+}
+
+// LoadOrCreateCookieSecrets returns the codecs for the cookies of the repo.
+// They follow the rotations of the keys, see LoadOrCreateSecrets.
+func LoadOrCreateCookieSecrets(r repo.Interface) ([]securecookie.Codec, error) {
+	s, err := LoadOrCreateSecrets(r)
+	if err != nil {
+		return nil, err
+	}
+	return []securecookie.Codec{s}, nil
+}
+
+// Encode encodes a cookie value with the newest keys
+func (s *Secrets) Encode(name string, value interface{}) (string, error) {
+	codecs, _ := s.current()
+	return codecs[0].Encode(name, value)
+}
+
+// Decode decodes a cookie value with any of the keys that are still accepted
+func (s *Secrets) Decode(name, value string, dst interface{}) error {
+	codecs, _ := s.current()
+	return securecookie.DecodeMulti(name, value, dst, codecs...)
+}
+
+// CSRF returns the csrf.Protect middleware for the newest key.
+// Requests with a CSRF cookie of an older key are checked with that key while it's still accepted,
+// so that forms which were opened before a rotation can still be sent.
+func (s *Secrets) CSRF(opts ...csrf.Option) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		var (
+			mu        sync.Mutex
+			protected = make(map[string]http.Handler)
+		)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			_, keys := s.current()
+
+			key := keys[0]
+			if c, err := req.Cookie(csrfCookieName); err == nil {
+				key = csrfKeyFor(keys, c.Value)
+			}
+
+			mu.Lock()
+			h, has := protected[string(key)]
+			if !has {
+				// forget the handlers of keys that were dropped
+				for k := range protected {
+					if !containsKey(keys, []byte(k)) {
+						delete(protected, k)
+					}
+				}
+
+				h = csrf.Protect(key, opts...)(next)
+				protected[string(key)] = h
+			}
+			mu.Unlock()
+
+			h.ServeHTTP(w, req)
+		})
+	}
+}
+
+// Previous returns how many older keys are still accepted and until when the last of them expires
+func (s *Secrets) Previous() (int, time.Time) {
+	s.current()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.previous, s.previousTo
+}
+
+// Rotate creates new keys and keeps the current ones valid for the gracePeriod, see RotateSecrets
+func (s *Secrets) Rotate(gracePeriod time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := rotateSecretFiles(s.dir, gracePeriod); err != nil {
+		return err
+	}
+	return s.check(time.Now(), true)
+}
+
+// current returns the codecs and the csrf keys, after reloading them if necessary
+func (s *Secrets) current() ([]securecookie.Codec, [][]byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.check(time.Now(), false); err != nil {
+		// keep using the keys we have, the files might be written right now
+		level.Warn(logging.Logger("web/secrets")).Log("event", "failed to reload secrets", "err", err)
+	}
+
+	return s.codecs, s.csrfKeys
+}
+
+// check reloads the keys if the files changed or one of the keys expired. s.mu needs to be held.
+func (s *Secrets) check(now time.Time, force bool) error {
+	expired := !s.nextExpiry.IsZero() && now.After(s.nextExpiry)
+	if !force && !expired && now.Sub(s.checkedAt) < secretsCheckInterval {
+		return nil
+	}
+	s.checkedAt = now
+
+	state := statSecretFiles(s.dir)
+	if !expired && state == s.filesState && s.codecs != nil {
+		return nil
+	}
+
+	sf, err := readSecretFiles(s.dir)
+	if err != nil {
+		return err
+	}
+
+	created := sf.ensure()
+	pruned := sf.prune(now)
+	if created || pruned {
+		if err := sf.write(s.dir); err != nil {
+			return err
+		}
+		state = statSecretFiles(s.dir)
+	}
+
+	var pairs [][]byte
+	for _, pair := range sf.cookieKeys {
+		pairs = append(pairs,
+			pair[0:32],  // hash key
+			pair[32:64], // block key
+		)
+	}
+
+	s.codecs = securecookie.CodecsFromPairs(pairs...)
+	s.csrfKeys = sf.csrfKeys
+	s.filesState = state
+
+	s.nextExpiry = time.Time{}
+	s.previous = len(sf.cookieKeys) - 1
+	s.previousTo = time.Time{}
+	for _, key := range sf.cookieKeys[1:] {
+		expires, has := sf.expiry[fingerprint(key)]
+		if !has {
+			// kept from before rotations were a thing, they don't expire
+			s.previousTo = time.Time{}
+			break
+		}
+		if expires.After(s.previousTo) {
+			s.previousTo = expires
+		}
+	}
+	for _, expires := range sf.expiry {
+		if s.nextExpiry.IsZero() || expires.Before(s.nextExpiry) {
+			s.nextExpiry = expires
+		}
+	}
+
+	return nil
+}
+
+// RotateSecrets creates new keys for the cookies and the CSRF tokens of the repo.
+// The current keys are still accepted for the gracePeriod, keys whose grace period ended are removed.
+// A running server picks up the new keys by itself.
+func RotateSecrets(r repo.Interface, gracePeriod time.Duration) error {
+	if gracePeriod < 0 {
+		return fmt.Errorf("secrets: grace period can't be negative")
+	}
+
+	openSecretsMu.Lock()
+	s, has := openSecrets[r.GetPath("web")]
+	openSecretsMu.Unlock()
+	if has {
+		return s.Rotate(gracePeriod)
+	}
+
+	return rotateSecretFiles(r.GetPath("web"), gracePeriod)
+}
+
+func rotateSecretFiles(dir string, gracePeriod time.Duration) error {
+	sf, err := readSecretFiles(dir)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	sf.prune(now)
+
+	// the keys that were used so far expire after the grace period, unless they already expire earlier
+	expires := now.Add(gracePeriod)
+	for _, keys := range [][][]byte{sf.cookieKeys, sf.csrfKeys} {
+		for _, key := range keys {
+			fp := fingerprint(key)
+			if current, has := sf.expiry[fp]; !has || current.After(expires) {
+				sf.expiry[fp] = expires
+			}
+		}
+	}
+
+	newPair := append(securecookie.GenerateRandomKey(32), securecookie.GenerateRandomKey(32)...)
+	sf.cookieKeys = append([][]byte{newPair}, sf.cookieKeys...)
+	sf.csrfKeys = append([][]byte{securecookie.GenerateRandomKey(csrfKeyLength)}, sf.csrfKeys...)
+
+	// drop the old keys right away if there is no grace period
+	sf.prune(now.Add(time.Nanosecond))
+
+	return sf.write(dir)
+}
+
+// secretFiles are the contents of the files in $repo/web
+type secretFiles struct {
+	cookieKeys [][]byte
+	csrfKeys   [][]byte
+
+	// when the keys stop being accepted, by their fingerprint. Keys without an entry don't expire.
+	expiry map[string]time.Time
+}
+
+func readSecretFiles(dir string) (*secretFiles, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil && !os.IsExist(err) {
+		return nil, fmt.Errorf("failed to create folder for secrets: %w", err)
+	}
+
+	var sf = secretFiles{
+		expiry: make(map[string]time.Time),
+	}
+
+	// secrets should contain multiple of 64byte (to enable key rotation as supported by gorilla)
+	cookieData, err := ioutil.ReadFile(filepath.Join(dir, cookieSecretFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to load cookie secrets: %w", err)
+	}
+	if n := len(cookieData); n%cookieKeyPairLength != 0 {
+		return nil, fmt.Errorf("expected multiple of %d bytes in cookie secret file but got: %d", cookieKeyPairLength, n)
+	}
+	sf.cookieKeys = splitKeys(cookieData, cookieKeyPairLength)
+
+	csrfData, err := ioutil.ReadFile(filepath.Join(dir, csrfSecretFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to load csrf secrets: %w", err)
+	}
+	if n := len(csrfData); n%csrfKeyLength != 0 {
+		return nil, fmt.Errorf("expected multiple of %d bytes in csrf secret file but got: %d", csrfKeyLength, n)
+	}
+	sf.csrfKeys = splitKeys(csrfData, csrfKeyLength)
+
+	expiryData, err := ioutil.ReadFile(filepath.Join(dir, secretsExpiryFile))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to load expiry of secrets: %w", err)
+		}
+	} else if err := json.Unmarshal(expiryData, &sf.expiry); err != nil {
+		return nil, fmt.Errorf("failed to parse expiry of secrets: %w", err)
+	}
+
+	return &sf, nil
+}
+
+// ensure creates the keys that are missing and returns true if it did
+func (sf *secretFiles) ensure() bool {
+	var created bool
+	if len(sf.cookieKeys) == 0 {
+		sf.cookieKeys = [][]byte{append(securecookie.GenerateRandomKey(32), securecookie.GenerateRandomKey(32)...)}
+		created = true
+	}
+	if len(sf.csrfKeys) == 0 {
+		sf.csrfKeys = [][]byte{securecookie.GenerateRandomKey(csrfKeyLength)}
+		created = true
+	}
+	return created
+}
+
+// prune drops the keys that expired before now and returns true if there were any.
+// The newest keys are always kept.
+func (sf *secretFiles) prune(now time.Time) bool {
+	var pruned bool
+
+	keep := func(keys [][]byte) [][]byte {
+		if len(keys) == 0 {
+			return keys
+		}
+		kept := [][]byte{keys[0]}
+		for _, key := range keys[1:] {
+			if expires, has := sf.expiry[fingerprint(key)]; has && expires.Before(now) {
+				pruned = true
+				continue
+			}
+			kept = append(kept, key)
+		}
+		return kept
+	}
+	sf.cookieKeys = keep(sf.cookieKeys)
+	sf.csrfKeys = keep(sf.csrfKeys)
+
+	// forget the expiry of keys that are gone
+	for fp := range sf.expiry {
+		if !sf.has(fp) {
+			delete(sf.expiry, fp)
+			pruned = true
+		}
+	}
+
+	return pruned
+}
+
+func (sf *secretFiles) has(fp string) bool {
+	for _, keys := range [][][]byte{sf.cookieKeys, sf.csrfKeys} {
+		for _, key := range keys {
+			if fingerprint(key) == fp {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// write replaces the files, each one at once so that a running server doesn't read half of one
+func (sf *secretFiles) write(dir string) error {
+	expiryData, err := json.Marshal(sf.expiry)
+	if err != nil {
+		return err
+	}
+
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{secretsExpiryFile, expiryData},
+		{cookieSecretFile, joinKeys(sf.cookieKeys)},
+		{csrfSecretFile, joinKeys(sf.csrfKeys)},
+	}
+	for _, f := range files {
+		path := filepath.Join(dir, f.name)
+		err := ioutil.WriteFile(path+".new", f.data, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.name, err)
+		}
+		err = os.Rename(path+".new", path)
+		if err != nil {
+			return fmt.Errorf("failed to replace %s: %w", f.name, err)
+		}
+	}
+	return nil
+}
+
+// statSecretFiles returns a summary of the files, which changes when they are written
+func statSecretFiles(dir string) string {
+	var state string
+	for _, name := range []string{cookieSecretFile, csrfSecretFile, secretsExpiryFile} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			state += name + ":missing;"
+			continue
+		}
+		state += fmt.Sprintf("%s:%d:%d;", name, info.Size(), info.ModTime().UnixNano())
+	}
+	return state
+}
+
+// csrfKeyFor returns the key that signed the csrf cookie, or the newest one if none of them did
+func csrfKeyFor(keys [][]byte, cookieValue string) []byte {
+	for _, key := range keys {
+		// the same settings gorilla/csrf uses, but without checking the age
+		sc := securecookie.New(key, nil)
+		sc.SetSerializer(securecookie.JSONEncoder{})
+		sc.MaxAge(0)
+
+		var token []byte
+		if err := sc.Decode(csrfCookieName, cookieValue, &token); err == nil {
+			return key
+		}
+	}
+	return keys[0]
+}
+
+func fingerprint(key []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(key))
+}
+
+func splitKeys(data []byte, size int) [][]byte {
+	var keys [][]byte
+	for len(data) >= size {
+		keys = append(keys, data[:size])
+		data = data[size:]
+	}
+	return keys
+}
+
+func joinKeys(keys [][]byte) []byte {
+	var data []byte
+	for _, key := range keys {
+		data = append(data, key...)
+	}
+	return data
+}
+
+func containsKey(keys [][]byte, key []byte) bool {
+	for _, k := range keys {
+		if string(k) == string(key) {
+			return true
+		}
+	}
+	return false
+}
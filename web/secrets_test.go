@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package web
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gorilla/securecookie"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ssbc/go-ssb-room/v2/internal/repo"
+)
+
+func TestSecretsRotation(t *testing.T) {
+	r := require.New(t)
+
+	testPath := filepath.Join("testrun", t.Name())
+	os.RemoveAll(testPath)
+	testRepo := repo.New(testPath)
+
+	s, err := LoadOrCreateSecrets(testRepo)
+	r.NoError(err)
+
+	previous, _ := s.Previous()
+	r.Equal(0, previous)
+
+	oldCookie, err := s.Encode("test", "hello")
+	r.NoError(err)
+	_, oldCSRFKeys := s.current()
+
+	// after a rotation, new cookies use the new keys but the old ones are still accepted
+	r.NoError(RotateSecrets(testRepo, time.Hour))
+
+	var value string
+	r.NoError(s.Decode("test", oldCookie, &value))
+	r.Equal("hello", value)
+
+	newCookie, err := s.Encode("test", "hello")
+	r.NoError(err)
+	// csrf cookies of the old key are checked with it
+	_, csrfKeys := s.current()
+	r.Len(csrfKeys, 2)
+	r.Equal(oldCSRFKeys[0], csrfKeys[1])
+
+	sc := securecookie.New(oldCSRFKeys[0], nil)
+	sc.SetSerializer(securecookie.JSONEncoder{})
+	oldCSRFCookie, err := sc.Encode(csrfCookieName, []byte("a token"))
+	r.NoError(err)
+	r.Equal(oldCSRFKeys[0], csrfKeyFor(csrfKeys, oldCSRFCookie))
+	r.Equal(csrfKeys[0], csrfKeyFor(csrfKeys, "garbage"))
+
+	previous, until := s.Previous()
+	r.Equal(1, previous)
+	r.WithinDuration(time.Now().Add(time.Hour), until, time.Minute)
+
+	// both pairs are in the file, for other processes
+	cookieData, err := ioutil.ReadFile(testRepo.GetPath("web", cookieSecretFile))
+	r.NoError(err)
+	r.Len(cookieData, 2*cookieKeyPairLength)
+
+	// without a grace period, the old keys are dropped right away
+	r.NoError(RotateSecrets(testRepo, 0))
+	r.Error(s.Decode("test", oldCookie, &value))
+	r.Error(s.Decode("test", newCookie, &value))
+
+	previous, _ = s.Previous()
+	r.Equal(0, previous)
+
+	// and so are the ones whose grace period ended
+	r.NoError(RotateSecrets(testRepo, time.Hour))
+	sf, err := readSecretFiles(testRepo.GetPath("web"))
+	r.NoError(err)
+	r.True(sf.prune(time.Now().Add(2 * time.Hour)))
+	r.Len(sf.cookieKeys, 1)
+	r.Len(sf.csrfKeys, 1)
+	r.Len(sf.expiry, 0)
+}
@@ -0,0 +1,175 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package web
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/securecookie"
+	"github.com/gorilla/sessions"
+
+	"github.com/ssbc/go-ssb-room/v2/roomdb"
+)
+
+const (
+	// DefaultSessionIdleTimeout ends sign-in sessions that weren't used for this long
+	DefaultSessionIdleTimeout = 2 * time.Hour
+
+	// DefaultSessionLifetime ends sign-in sessions this long after they started, even if they are used
+	DefaultSessionLifetime = 24 * time.Hour
+
+	// how precise the last use of a session is tracked, it's checked on every request
+	sessionTouchResolution = time.Minute
+)
+
+// SessionMemberKey holds the ID of the member that signed in with a session of the SessionStore.
+// The sign-in handlers need to set it, so that all the sessions of a member can be ended at once.
+const SessionMemberKey = "member-id"
+
+// SessionStore is a sessions.Store that keeps the values of the sessions in the database.
+// The cookies only hold the signed and encrypted ID of the session, so that sessions can be ended on the server.
+type SessionStore struct {
+	db     roomdb.WebSessionsService
+	codecs []securecookie.Codec
+
+	options *sessions.Options
+
+	idleTimeout time.Duration
+	lifetime    time.Duration
+}
+
+var _ sessions.Store = (*SessionStore)(nil)
+
+// NewSessionStore returns a store for the sign-in sessions.
+// They end after idleTimeout without requests, and lifetime after they started at the latest.
+func NewSessionStore(db roomdb.WebSessionsService, codecs []securecookie.Codec, idleTimeout, lifetime time.Duration) *SessionStore {
+	return &SessionStore{
+		db:     db,
+		codecs: codecs,
+
+		options: &sessions.Options{
+			Path:     "/",
+			MaxAge:   int(lifetime.Seconds()),
+			HttpOnly: true,
+		},
+
+		idleTimeout: idleTimeout,
+		lifetime:    lifetime,
+	}
+}
+
+// Get returns the session of the request, it's only loaded once per request.
+func (s *SessionStore) Get(r *http.Request, name string) (*sessions.Session, error) {
+	return sessions.GetRegistry(r).Get(s, name)
+}
+
+// New loads the session of the cookie from the database.
+// It returns a new, empty session if there is no cookie or the session ended.
+func (s *SessionStore) New(r *http.Request, name string) (*sessions.Session, error) {
+	session := sessions.NewSession(s, name)
+	opts := *s.options
+	session.Options = &opts
+	session.IsNew = true
+
+	c, err := r.Cookie(name)
+	if err != nil {
+		return session, nil
+	}
+
+	var id string
+	if err := securecookie.DecodeMulti(name, c.Value, &id, s.codecs...); err != nil {
+		// from an older version or the secrets changed, start over
+		return session, nil
+	}
+
+	stored, err := s.db.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, roomdb.ErrNotFound) {
+			return session, nil
+		}
+		return session, err
+	}
+
+	if err := gob.NewDecoder(bytes.NewReader(stored.Data)).Decode(&session.Values); err != nil {
+		return session, fmt.Errorf("web sessions: failed to decode session values: %w", err)
+	}
+	session.ID = id
+	session.IsNew = false
+
+	// only note the use once in a while, instead of on every request
+	if time.Since(stored.LastUsedAt) > sessionTouchResolution {
+		if err := s.db.Touch(r.Context(), id, time.Now().Add(s.idleTimeout)); err != nil {
+			return session, err
+		}
+	}
+
+	return session, nil
+}
+
+// Save stores the values of the session in the database and sets the cookie with its ID.
+// A negative MaxAge ends the session.
+func (s *SessionStore) Save(r *http.Request, w http.ResponseWriter, session *sessions.Session) error {
+	ctx := r.Context()
+
+	if session.Options.MaxAge < 0 {
+		if session.ID != "" {
+			if err := s.db.Remove(ctx, session.ID); err != nil {
+				return err
+			}
+		}
+		http.SetCookie(w, sessions.NewCookie(session.Name(), "", session.Options))
+		return nil
+	}
+
+	var data bytes.Buffer
+	if err := gob.NewEncoder(&data).Encode(session.Values); err != nil {
+		return fmt.Errorf("web sessions: failed to encode session values: %w", err)
+	}
+
+	memberID, _ := session.Values[SessionMemberKey].(int64)
+	idleExpires := time.Now().Add(s.idleTimeout)
+
+	if session.ID != "" {
+		stored, err := s.db.Get(ctx, session.ID)
+		if err != nil && !errors.Is(err, roomdb.ErrNotFound) {
+			return err
+		}
+
+		if err == nil && stored.MemberID == memberID {
+			return s.db.Update(ctx, session.ID, memberID, data.Bytes(), idleExpires)
+		}
+
+		// somebody signed in or the session ended in the meantime, use a new ID for it
+		if err == nil {
+			if err := s.db.Remove(ctx, session.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	// shorter sessions, like the ones for the steps of a sign-in, can set their own MaxAge
+	lifetime := s.lifetime
+	if maxAge := time.Duration(session.Options.MaxAge) * time.Second; maxAge > 0 && maxAge < lifetime {
+		lifetime = maxAge
+	}
+
+	id, err := s.db.Create(ctx, memberID, data.Bytes(), time.Now().Add(lifetime), idleExpires)
+	if err != nil {
+		return err
+	}
+	session.ID = id
+
+	encoded, err := securecookie.EncodeMulti(session.Name(), session.ID, s.codecs...)
+	if err != nil {
+		return err
+	}
+	http.SetCookie(w, sessions.NewCookie(session.Name(), encoded, session.Options))
+	return nil
+}
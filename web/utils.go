@@ -5,9 +5,18 @@
 package web
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
 	"fmt"
 	"html/template"
+	"image/color"
 	"io/ioutil"
+	"net"
+	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -17,12 +26,13 @@ import (
 
 	"github.com/dustin/go-humanize"
 	"github.com/gorilla/mux"
-	"github.com/gorilla/securecookie"
 	ua "github.com/mileusna/useragent"
+	"github.com/skip2/go-qrcode"
 	"go.mindeco.de/log/level"
 	"go.mindeco.de/logging"
 
 	refs "github.com/ssbc/go-ssb-refs"
+	"github.com/ssbc/go-ssb-room/v2/internal/aliases"
 	"github.com/ssbc/go-ssb-room/v2/internal/network"
 	"github.com/ssbc/go-ssb-room/v2/internal/repo"
 	"github.com/ssbc/go-ssb-room/v2/roomdb"
@@ -34,8 +44,10 @@ func TemplateFuncs(m *mux.Router, netInfo network.ServerEndpointDetails) templat
 		"human_time": func(when time.Time) string {
 			return humanize.Time(when)
 		},
-		"urlTo": NewURLTo(m, netInfo),
-		"inc":   func(i int) int { return i + 1 },
+		"urlTo":      NewURLTo(m, netInfo),
+		"inc":        func(i int) int { return i + 1 },
+		"user_agent": DescribeUserAgent,
+		"alias_name": aliases.ToUnicode,
 	}
 }
 
@@ -110,90 +122,54 @@ func NewURLTo(appRouter *mux.Router, netInfo network.ServerEndpointDetails) URLM
 	}
 }
 
-// LoadOrCreateCookieSecrets either parses the bytes from $repo/web/cookie-secret or creates a new file with suitable keys in it
-func LoadOrCreateCookieSecrets(repo repo.Interface) ([]securecookie.Codec, error) {
-	secretPath := repo.GetPath("web", "cookie-secret")
-	err := os.MkdirAll(filepath.Dir(secretPath), 0700)
+const oidcSigningKeyBits = 2048
+
+// LoadOrCreateOIDCSigningKey either loads the RSA key from $repo/web/oidc-signing-key.pem or creates a new one.
+// It signs the ID tokens of the OpenID Connect provider, so replacing it invalidates all the tokens that were handed out.
+func LoadOrCreateOIDCSigningKey(repo repo.Interface) (*rsa.PrivateKey, error) {
+	keyPath := repo.GetPath("web", "oidc-signing-key.pem")
+	err := os.MkdirAll(filepath.Dir(keyPath), 0700)
 	if err != nil && !os.IsExist(err) {
-		return nil, fmt.Errorf("failed to create folder for cookie secret: %w", err)
+		return nil, fmt.Errorf("failed to create folder for oidc signing key: %w", err)
 	}
 
-	// load the existing data
-	secrets, err := ioutil.ReadFile(secretPath)
+	// load the existing key
+	pemData, err := ioutil.ReadFile(keyPath)
 	if err != nil {
 		if !os.IsNotExist(err) {
-			return nil, fmt.Errorf("failed to load cookie secrets: %w", err)
+			return nil, fmt.Errorf("failed to load oidc signing key: %w", err)
 		}
 
-		// create new keys, save them and return the codec
-		hashKey := securecookie.GenerateRandomKey(32)
-		blockKey := securecookie.GenerateRandomKey(32)
-
-		data := append(hashKey, blockKey...)
-		err = ioutil.WriteFile(secretPath, data, 0600)
+		// create a new key, save and return it
+		key, err := rsa.GenerateKey(rand.Reader, oidcSigningKeyBits)
 		if err != nil {
-			return nil, err
-		}
-		sc := securecookie.CodecsFromPairs(hashKey, blockKey)
-		return sc, nil
-	}
-
-	// secrets should contain multiple of 64byte (to enable key rotation as supported by gorilla)
-	if n := len(secrets); n%64 != 0 {
-		return nil, fmt.Errorf("expected multiple of 64bytes in cookie secret file but got: %d", n)
-	}
-
-	// range over the secrets []byte in chunks of 64 bytes
-	// and slice it into 32byte pairs
-	var pairs [][]byte
-
-	// the increment/next part (which usually is i++)
-	// is the multiple comma assigment (a,b = b+1,a-1)
-	// so chunk is the next 64 bytes and then it slices of the first 64 bytes of secrets for the next iteration
-	for chunk := secrets[:64]; len(secrets) >= 64; chunk, secrets = secrets[:64], secrets[64:] {
-		pairs = append(pairs,
-			chunk[0:32],  // hash key
-			chunk[32:64], // block key
-		)
-	}
-
-	sc := securecookie.CodecsFromPairs(pairs...)
-	return sc, nil
-}
-
-const csrfKeyLength = 32
-
-// LoadOrCreateCSRFSecret either loads the bytes from $repo/web/csrf-secret or creates a new file with suitable keys in it
-func LoadOrCreateCSRFSecret(repo repo.Interface) ([]byte, error) {
-	secretPath := repo.GetPath("web", "csrf-secret")
-	err := os.MkdirAll(filepath.Dir(secretPath), 0700)
-	if err != nil && !os.IsExist(err) {
-		return nil, fmt.Errorf("failed to create folder for csrf secret: %w", err)
-	}
-
-	// load the existing data
-	secret, err := ioutil.ReadFile(secretPath)
-	if err != nil {
-		if !os.IsNotExist(err) {
-			return nil, fmt.Errorf("failed to load csrf secrets: %w", err)
+			return nil, fmt.Errorf("failed to generate oidc signing key: %w", err)
 		}
 
-		// create a new key, save and return it
-		freshKey := securecookie.GenerateRandomKey(csrfKeyLength)
+		pemData = pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(key),
+		})
 
-		err = ioutil.WriteFile(secretPath, freshKey, 0600)
+		err = ioutil.WriteFile(keyPath, pemData, 0600)
 		if err != nil {
 			return nil, err
 		}
 
-		return freshKey, nil
+		return key, nil
 	}
 
-	if n := len(secret); csrfKeyLength != n {
-		return nil, fmt.Errorf("expected %d bytes csrf secert but got %d", csrfKeyLength, n)
+	block, _ := pem.Decode(pemData)
+	if block == nil || block.Type != "RSA PRIVATE KEY" {
+		return nil, fmt.Errorf("expected an RSA PRIVATE KEY in %s", keyPath)
 	}
 
-	return secret, nil
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse oidc signing key: %w", err)
+	}
+
+	return key, nil
 }
 
 // Transforms the SSB URI into a string suitable for an <a href> and
@@ -215,3 +191,156 @@ func StringifySSBURI(uri *url.URL, userAgent string) string {
 		return uri.String()
 	}
 }
+
+// DescribeUserAgent turns a user agent header into a short description, like "Firefox (Linux)".
+// It returns the header as is, if it can't make sense of it.
+func DescribeUserAgent(userAgent string) string {
+	browser := ua.Parse(userAgent)
+	if browser.Name == "" {
+		return userAgent
+	}
+	if browser.OS == "" {
+		return browser.Name
+	}
+	return fmt.Sprintf("%s (%s)", browser.Name, browser.OS)
+}
+
+// QRCodeURI encodes content as a QR code and returns it as a data URI of a PNG, that can be used as the src of an img.
+// The background is transparent, to fit into the page.
+func QRCodeURI(content string) (template.URL, error) {
+	qrCode, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		return "", err
+	}
+
+	qrCode.BackgroundColor = color.Transparent
+	qrCode.ForegroundColor = color.Black
+
+	qrCodeData, err := qrCode.PNG(-5)
+	if err != nil {
+		return "", err
+	}
+
+	return template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(qrCodeData)), nil
+}
+
+// TrustedProxies are the reverse proxies in front of the room, as single addresses or networks.
+// Only they get to say where a request came from with X-Forwarded-For.
+type TrustedProxies []*net.IPNet
+
+// ParseTrustedProxies parses a list of IP addresses and CIDR networks, like 127.0.0.1 or 10.0.0.0/8.
+func ParseTrustedProxies(list []string) (TrustedProxies, error) {
+	var tp TrustedProxies
+	for _, entry := range list {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("trusted proxies: invalid address %q", entry)
+			}
+			bits := 8 * net.IPv6len
+			if v4 := ip.To4(); v4 != nil {
+				ip, bits = v4, 8*net.IPv4len
+			}
+			tp = append(tp, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxies: invalid network %q: %w", entry, err)
+		}
+		tp = append(tp, network)
+	}
+	return tp, nil
+}
+
+func (tp TrustedProxies) trusts(ip net.IP) bool {
+	for _, network := range tp {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// FromProxy returns true if the request came directly from one of the trusted proxies,
+// so that the X-Forwarded headers it set can be believed.
+func (tp TrustedProxies) FromProxy(req *http.Request) bool {
+	ip := parseHostIP(req.RemoteAddr)
+	return ip != nil && tp.trusts(ip)
+}
+
+// ClientIP returns the IP address of the client that made the request, or nil if it can't tell.
+// That is the peer of the connection, unless it is one of the trusted proxies. Then X-Forwarded-For is read from the right
+// and the first hop that isn't a trusted proxy is the client. The entries left of it can be made up by the client.
+func (tp TrustedProxies) ClientIP(req *http.Request) net.IP {
+	ip := parseHostIP(req.RemoteAddr)
+	if ip == nil || !tp.trusts(ip) {
+		return ip
+	}
+
+	var hops []string
+	for _, header := range req.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := parseHostIP(strings.TrimSpace(hops[i]))
+		if hop == nil {
+			return nil
+		}
+		if !tp.trusts(hop) {
+			return hop
+		}
+		ip = hop
+	}
+
+	// only proxies all the way down
+	return ip
+}
+
+type clientIPContextKey struct{}
+
+// Middleware puts the address of the client into the context of the request, for RemoteIP.
+func (tp TrustedProxies) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := context.WithValue(req.Context(), clientIPContextKey{}, tp.ClientIP(req))
+		next.ServeHTTP(w, req.WithContext(ctx))
+	})
+}
+
+func parseHostIP(addr string) net.IP {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	return net.ParseIP(addr)
+}
+
+// RemoteIP returns the IP address of the client that made the request.
+// Behind a reverse proxy it needs the Middleware of the TrustedProxies, otherwise it is the peer of the connection.
+// It returns an empty string if the address isn't known.
+// If truncate is true, only the network part of the address is returned (a /24 for IPv4 and a /48 for IPv6),
+// which is enough to tell sessions apart without storing who exactly made them.
+func RemoteIP(req *http.Request, truncate bool) string {
+	ip, ok := req.Context().Value(clientIPContextKey{}).(net.IP)
+	if !ok {
+		ip = TrustedProxies(nil).ClientIP(req)
+	}
+	if ip == nil {
+		return ""
+	}
+
+	if !truncate {
+		return ip.String()
+	}
+
+	if v4 := ip.To4(); v4 != nil {
+		return v4.Mask(net.CIDRMask(24, 32)).String()
+	}
+	return ip.Mask(net.CIDRMask(48, 128)).String()
+}
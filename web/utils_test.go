@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2021 The NGI Pointer Secure-Scuttlebutt Team of 2020/2021
+//
+// SPDX-License-Identifier: MIT
+
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteIP(t *testing.T) {
+	tp, err := ParseTrustedProxies([]string{"127.0.0.1", " 10.0.0.0/8", "", "::1"})
+	require.NoError(t, err)
+	require.Len(t, tp, 3)
+
+	_, err = ParseTrustedProxies([]string{"localhost"})
+	require.Error(t, err)
+	_, err = ParseTrustedProxies([]string{"10.0.0.0/33"})
+	require.Error(t, err)
+
+	type testCase struct {
+		remoteAddr string
+		forwarded  []string
+
+		want          string
+		wantTruncated string
+	}
+
+	cases := []testCase{
+		// without a proxy the header is ignored
+		{remoteAddr: "198.51.100.7:1234", want: "198.51.100.7", wantTruncated: "198.51.100.0"},
+		{remoteAddr: "198.51.100.7:1234", forwarded: []string{"203.0.113.5"}, want: "198.51.100.7", wantTruncated: "198.51.100.0"},
+		{remoteAddr: "198.51.100.7:1234", forwarded: []string{"x"}, want: "198.51.100.7", wantTruncated: "198.51.100.0"},
+
+		// the proxy appends the address it saw, whatever the client put in front of it doesn't count
+		{remoteAddr: "127.0.0.1:1234", forwarded: []string{"203.0.113.5"}, want: "203.0.113.5", wantTruncated: "203.0.113.0"},
+		{remoteAddr: "127.0.0.1:1234", forwarded: []string{"6.6.6.6, 203.0.113.5"}, want: "203.0.113.5", wantTruncated: "203.0.113.0"},
+		{remoteAddr: "127.0.0.1:1234", forwarded: []string{"6.6.6.6", "203.0.113.5"}, want: "203.0.113.5", wantTruncated: "203.0.113.0"},
+		{remoteAddr: "127.0.0.1:1234", forwarded: []string{"x, 203.0.113.5"}, want: "203.0.113.5", wantTruncated: "203.0.113.0"},
+
+		// a chain of trusted proxies
+		{remoteAddr: "127.0.0.1:1234", forwarded: []string{"6.6.6.6, 2001:db8::1, 10.1.2.3"}, want: "2001:db8::1", wantTruncated: "2001:db8::"},
+
+		// requests that came from the proxy itself
+		{remoteAddr: "[::1]:1234", want: "::1", wantTruncated: "::"},
+
+		// can't tell
+		{remoteAddr: "127.0.0.1:1234", forwarded: []string{"x"}},
+		{remoteAddr: "127.0.0.1:1234", forwarded: []string{"203.0.113.5, x"}},
+		{remoteAddr: ""},
+	}
+
+	for i, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = tc.remoteAddr
+		for _, f := range tc.forwarded {
+			req.Header.Add("X-Forwarded-For", f)
+		}
+
+		var got, gotTruncated string
+		tp.Middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			got = RemoteIP(req, false)
+			gotTruncated = RemoteIP(req, true)
+		})).ServeHTTP(httptest.NewRecorder(), req)
+
+		assert.Equal(t, tc.want, got, "case %d", i)
+		assert.Equal(t, tc.wantTruncated, gotTruncated, "case %d", i)
+	}
+
+	// without the middleware, only the peer counts
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "127.0.0.1:1234"
+	req.Header.Set("X-Forwarded-For", "203.0.113.5")
+	assert.Equal(t, "127.0.0.1", RemoteIP(req, false))
+}